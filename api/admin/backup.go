@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	errBackupRunning     = errors.New("a backup is already being created")
+	errBackupUnsupported = errors.New("the node's database doesn't support backups")
+)
+
+// Backup backs up the node's database in the background, as backing up a
+// large database can take longer than an API call should
+type Backup struct {
+	lock    sync.Mutex
+	log     logging.Logger
+	db      database.Backupper
+	running bool
+}
+
+// Start backing up the database to [dir]
+func (b *Backup) Start(dir string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch {
+	case b.db == nil:
+		return errBackupUnsupported
+	case b.running:
+		return errBackupRunning
+	}
+	// Checked up front so the mistake is reported by the call
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	b.running = true
+	go func() {
+		startTime := time.Now()
+		b.log.Info("backing up the database to %s", dir)
+		if err := b.db.Backup(dir); err != nil {
+			b.log.Error("failed to back up the database to %s due to %s", dir, err)
+		} else {
+			b.log.Info("backed up the database to %s in %s", dir, time.Since(startTime))
+		}
+
+		b.lock.Lock()
+		b.running = false
+		b.lock.Unlock()
+	}()
+	return nil
+}
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var errCompactionRunning = errors.New("the database is already being compacted")
+
+// Compaction compacts the node's database in the background, as compacting a
+// large database can take longer than an API call should
+type Compaction struct {
+	lock    sync.Mutex
+	log     logging.Logger
+	db      database.Database
+	running bool
+}
+
+// Start compacting the key range [start, limit). A nil start or limit leaves
+// that end of the range open.
+func (c *Compaction) Start(start, limit []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.running {
+		return errCompactionRunning
+	}
+	c.running = true
+	go func() {
+		startTime := time.Now()
+		c.log.Info("compacting the database")
+		if err := c.db.Compact(start, limit); err != nil {
+			c.log.Error("failed to compact the database due to %s", err)
+		} else {
+			c.log.Info("compacted the database in %s", time.Since(startTime))
+		}
+
+		c.lock.Lock()
+		c.running = false
+		c.lock.Unlock()
+	}()
+	return nil
+}
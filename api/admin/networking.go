@@ -4,16 +4,34 @@
 package admin
 
 import (
+	"errors"
 	"sort"
+	"time"
 
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/bans"
 	"github.com/ava-labs/gecko/utils"
 )
 
+var errBanTarget = errors.New("exactly one of nodeID and cidr must be given")
+
 // Peerable can return a group of peers
 type Peerable interface{ Peers() []utils.IPDesc }
 
+// Banner bans peers, and disconnects from the banned peers
+type Banner interface {
+	BanID(nodeID ids.ShortID, duration time.Duration) error
+	BanCIDR(cidr string, duration time.Duration) error
+	UnbanID(nodeID ids.ShortID) error
+	UnbanCIDR(cidr string) error
+	Bans() []bans.Ban
+}
+
 // Networking provides helper methods for tracking the current network state
-type Networking struct{ peers Peerable }
+type Networking struct {
+	peers  Peerable
+	banner Banner
+}
 
 // Peers returns the current peers
 func (n *Networking) Peers() ([]string, error) {
@@ -25,3 +43,46 @@ func (n *Networking) Peers() ([]string, error) {
 	sort.Strings(ips)
 	return ips, nil
 }
+
+// Ban the node [nodeID] or the range [cidr], exactly one of which must be
+// given, for [duration]. A ban with an empty duration doesn't expire.
+func (n *Networking) Ban(nodeID, cidr, duration string) error {
+	d := time.Duration(0)
+	if duration != "" {
+		parsed, err := time.ParseDuration(duration)
+		if err != nil {
+			return err
+		}
+		d = parsed
+	}
+
+	switch {
+	case nodeID != "" && cidr == "":
+		id, err := ids.ShortFromString(nodeID)
+		if err != nil {
+			return err
+		}
+		return n.banner.BanID(id, d)
+	case nodeID == "" && cidr != "":
+		return n.banner.BanCIDR(cidr, d)
+	default:
+		return errBanTarget
+	}
+}
+
+// Unban the node [nodeID] or the range [cidr], exactly one of which must be
+// given
+func (n *Networking) Unban(nodeID, cidr string) error {
+	switch {
+	case nodeID != "" && cidr == "":
+		id, err := ids.ShortFromString(nodeID)
+		if err != nil {
+			return err
+		}
+		return n.banner.UnbanID(id)
+	case nodeID == "" && cidr != "":
+		return n.banner.UnbanCIDR(cidr)
+	default:
+		return errBanTarget
+	}
+}
@@ -4,30 +4,48 @@
 package admin
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/api/auth"
+	"github.com/ava-labs/gecko/api/ipcs"
 	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
+var errNoLevel = errors.New("either logLevel or displayLevel must be given")
+
 // Admin is the API service for node admin management
 type Admin struct {
 	networkID    uint32
 	log          logging.Logger
+	logFactory   logging.Factory
 	networking   Networking
 	performance  Performance
 	chainManager chains.Manager
 	httpServer   *api.Server
+	auth         *auth.Auth
+	ipcs         *ipcs.IPCs
+	nodeConfig   interface{}
+	compaction   *Compaction
+	backup       *Backup
 }
 
 // NewService returns a new admin API service
-func NewService(networkID uint32, log logging.Logger, chainManager chains.Manager, peers Peerable, httpServer *api.Server) *common.HTTPHandler {
+// [banner] bans the peers banned by BanPeer. [ipcs] is nil if IPCs are
+// disabled. [nodeConfig] is the configuration the
+// node is running with, which is reported by GetNodeConfig. [db] is the node's
+// database, which is compacted by CompactDatabase. [backupper] backs up the
+// database, and is nil if it can't be backed up.
+func NewService(networkID uint32, log logging.Logger, logFactory logging.Factory, chainManager chains.Manager, peers Peerable, banner Banner, httpServer *api.Server, auth *auth.Auth, ipcs *ipcs.IPCs, nodeConfig interface{}, db database.Database, backupper database.Backupper) *common.HTTPHandler {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
@@ -35,11 +53,24 @@ func NewService(networkID uint32, log logging.Logger, chainManager chains.Manage
 	newServer.RegisterService(&Admin{
 		networkID:    networkID,
 		log:          log,
+		logFactory:   logFactory,
 		chainManager: chainManager,
 		networking: Networking{
-			peers: peers,
+			peers:  peers,
+			banner: banner,
 		},
 		httpServer: httpServer,
+		auth:       auth,
+		ipcs:       ipcs,
+		nodeConfig: nodeConfig,
+		compaction: &Compaction{
+			log: log,
+			db:  db,
+		},
+		backup: &Backup{
+			log: log,
+			db:  backupper,
+		},
 	}, "admin")
 	return &common.HTTPHandler{Handler: newServer}
 }
@@ -60,6 +91,82 @@ func (service *Admin) GetNetworkID(r *http.Request, args *GetNetworkIDArgs, repl
 	return nil
 }
 
+// GetNodeConfigArgs are the arguments for calling GetNodeConfig
+type GetNodeConfigArgs struct{}
+
+// GetNodeConfigReply are the results from calling GetNodeConfig
+type GetNodeConfigReply struct {
+	Config interface{} `json:"config"`
+}
+
+// GetNodeConfig returns the configuration this node is running with: its
+// flags, their defaults and the values derived from them. Secrets are
+// redacted.
+func (service *Admin) GetNodeConfig(r *http.Request, args *GetNodeConfigArgs, reply *GetNodeConfigReply) error {
+	service.log.Debug("Admin: GetNodeConfig called")
+
+	reply.Config = service.nodeConfig
+	return nil
+}
+
+// CompactDatabaseArgs are the arguments for calling CompactDatabase
+type CompactDatabaseArgs struct {
+	// Keys to compact from and to, formatted in CB58. If omitted, the range is
+	// open at that end, so by default the whole database is compacted.
+	Start formatting.CB58 `json:"start"`
+	Limit formatting.CB58 `json:"limit"`
+}
+
+// CompactDatabaseReply are the results from calling CompactDatabase
+type CompactDatabaseReply struct {
+	Success bool `json:"success"`
+}
+
+// CompactDatabase starts compacting the node's database, which discards
+// deleted and overwritten values. It returns without waiting for the
+// compaction, which is logged once it's done.
+func (service *Admin) CompactDatabase(_ *http.Request, args *CompactDatabaseArgs, reply *CompactDatabaseReply) error {
+	service.log.Debug("Admin: CompactDatabase called")
+
+	var start, limit []byte
+	if len(args.Start.Bytes) > 0 {
+		start = args.Start.Bytes
+	}
+	if len(args.Limit.Bytes) > 0 {
+		limit = args.Limit.Bytes
+	}
+	if err := service.compaction.Start(start, limit); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// CreateBackupArgs are the arguments for calling CreateBackup
+type CreateBackupArgs struct {
+	// Directory the backup is written to. It must not exist.
+	Dir string `json:"dir"`
+}
+
+// CreateBackupReply are the results from calling CreateBackup
+type CreateBackupReply struct {
+	Success bool `json:"success"`
+}
+
+// CreateBackup starts backing up the node's database, as it is when the call
+// is made, while the node keeps running. It returns without waiting for the
+// backup, which is logged once it's done. The backup is restored by starting
+// a node with db-restore-dir.
+func (service *Admin) CreateBackup(_ *http.Request, args *CreateBackupArgs, reply *CreateBackupReply) error {
+	service.log.Debug("Admin: CreateBackup called with %s", args.Dir)
+
+	if err := service.backup.Start(args.Dir); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
 // GetBlockchainIDArgs are the arguments for calling GetBlockchainID
 type GetBlockchainIDArgs struct {
 	Alias string `json:"alias"`
@@ -96,6 +203,94 @@ func (service *Admin) Peers(r *http.Request, args *PeersArgs, reply *PeersReply)
 	return err
 }
 
+// BanPeerArgs are the arguments for calling BanPeer. Exactly one of NodeID and
+// CIDR must be given.
+type BanPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// Range of IPs to ban, such as "10.0.0.0/8"
+	CIDR string `json:"cidr"`
+	// How long the ban lasts, such as "24h". If empty, the ban lasts until the
+	// peer is unbanned.
+	Duration string `json:"duration"`
+}
+
+// BanPeerReply are the results from calling BanPeer
+type BanPeerReply struct {
+	Success bool `json:"success"`
+}
+
+// BanPeer stops this node from connecting to a node, or to the IPs in a range,
+// and disconnects from them. Bans last across restarts.
+func (service *Admin) BanPeer(_ *http.Request, args *BanPeerArgs, reply *BanPeerReply) error {
+	service.log.Debug("Admin: BanPeer called with NodeID: %q, CIDR: %q, Duration: %q", args.NodeID, args.CIDR, args.Duration)
+
+	if err := service.networking.Ban(args.NodeID, args.CIDR, args.Duration); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// UnbanPeerArgs are the arguments for calling UnbanPeer. Exactly one of NodeID
+// and CIDR must be given.
+type UnbanPeerArgs struct {
+	NodeID string `json:"nodeID"`
+	// Range that was banned. A range in or around it isn't unbanned.
+	CIDR string `json:"cidr"`
+}
+
+// UnbanPeerReply are the results from calling UnbanPeer
+type UnbanPeerReply struct {
+	Success bool `json:"success"`
+}
+
+// UnbanPeer lifts the ban of a node, or of a range of IPs
+func (service *Admin) UnbanPeer(_ *http.Request, args *UnbanPeerArgs, reply *UnbanPeerReply) error {
+	service.log.Debug("Admin: UnbanPeer called with NodeID: %q, CIDR: %q", args.NodeID, args.CIDR)
+
+	if err := service.networking.Unban(args.NodeID, args.CIDR); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetBansArgs are the arguments for calling GetBans
+type GetBansArgs struct{}
+
+// PeerBan is a ban of a node or of a range of IPs. Exactly one of NodeID and
+// CIDR is set.
+type PeerBan struct {
+	NodeID string `json:"nodeID,omitempty"`
+	CIDR   string `json:"cidr,omitempty"`
+	// Unix time the ban expires at, or 0 if it doesn't expire
+	Expiry cjson.Uint64 `json:"expiry"`
+}
+
+// GetBansReply are the results from calling GetBans
+type GetBansReply struct {
+	Bans []PeerBan `json:"bans"`
+}
+
+// GetBans returns the bans in effect
+func (service *Admin) GetBans(_ *http.Request, _ *GetBansArgs, reply *GetBansReply) error {
+	service.log.Debug("Admin: GetBans called")
+
+	for _, ban := range service.networking.banner.Bans() {
+		peerBan := PeerBan{}
+		if ban.CIDR != nil {
+			peerBan.CIDR = ban.CIDR.String()
+		} else {
+			peerBan.NodeID = ban.NodeID.String()
+		}
+		if !ban.Expiry.IsZero() {
+			peerBan.Expiry = cjson.Uint64(ban.Expiry.Unix())
+		}
+		reply.Bans = append(reply.Bans, peerBan)
+	}
+	return nil
+}
+
 // StartCPUProfilerArgs are the arguments for calling StartCPUProfiler
 type StartCPUProfilerArgs struct {
 	Filename string `json:"filename"`
@@ -207,3 +402,155 @@ func (service *Admin) AliasChain(_ *http.Request, args *AliasChainArgs, reply *A
 	reply.Success = true
 	return service.httpServer.AddAliasesWithReadLock("bc/"+chainID.String(), "bc/"+args.Alias)
 }
+
+// SetLoggerLevelArgs are the arguments for calling SetLoggerLevel
+type SetLoggerLevelArgs struct {
+	// Name of the logger to set the levels of, such as "main", "http" or
+	// "[chainID]/http". A chain's ID or alias sets the levels of each of the
+	// chain's loggers. If empty, the levels of every logger are set.
+	LoggerName string `json:"loggerName"`
+	// Level of messages written to disk, such as "debug". Left unchanged if
+	// empty.
+	LogLevel string `json:"logLevel"`
+	// Level of messages displayed. Left unchanged if empty.
+	DisplayLevel string `json:"displayLevel"`
+}
+
+// SetLoggerLevelReply are the results from calling SetLoggerLevel
+type SetLoggerLevelReply struct {
+	Success bool `json:"success"`
+}
+
+// SetLoggerLevel sets the levels of a logger while the node is running
+func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs, reply *SetLoggerLevelReply) error {
+	service.log.Debug("Admin: SetLoggerLevel called with LoggerName: %q, LogLevel: %q, DisplayLevel: %q", args.LoggerName, args.LogLevel, args.DisplayLevel)
+
+	if args.LogLevel == "" && args.DisplayLevel == "" {
+		return errNoLevel
+	}
+
+	// Parse both levels before setting either so that an invalid level
+	// doesn't leave the logger half updated
+	logLevel, displayLevel := logging.Off, logging.Off
+	if args.LogLevel != "" {
+		level, err := logging.ToLevel(args.LogLevel)
+		if err != nil {
+			return err
+		}
+		logLevel = level
+	}
+	if args.DisplayLevel != "" {
+		level, err := logging.ToLevel(args.DisplayLevel)
+		if err != nil {
+			return err
+		}
+		displayLevel = level
+	}
+
+	// A chain's loggers are named by its ID
+	loggerName := args.LoggerName
+	if chainID, err := service.chainManager.Lookup(loggerName); err == nil {
+		loggerName = chainID.String()
+	}
+
+	if args.LogLevel != "" {
+		if err := service.logFactory.SetLogLevel(loggerName, logLevel); err != nil {
+			return err
+		}
+	}
+	if args.DisplayLevel != "" {
+		if err := service.logFactory.SetDisplayLevel(loggerName, displayLevel); err != nil {
+			return err
+		}
+	}
+	reply.Success = true
+	return nil
+}
+
+// NewTokenArgs are the arguments for calling NewToken
+type NewTokenArgs struct {
+	Password string `json:"password"`
+	// Endpoints the token is valid at, such as "/ext/keystore". "*" makes the
+	// token valid at every endpoint.
+	Endpoints []string `json:"endpoints"`
+}
+
+// NewTokenReply are the results from calling NewToken
+type NewTokenReply struct {
+	Token string `json:"token"`
+}
+
+// NewToken returns a new token that authorizes calls to restricted methods at
+// [args.Endpoints]. The token is passed in the Authorization header of calls,
+// as "Bearer <token>".
+func (service *Admin) NewToken(_ *http.Request, args *NewTokenArgs, reply *NewTokenReply) error {
+	service.log.Debug("Admin: NewToken called with endpoints %v", args.Endpoints)
+
+	token, err := service.auth.NewToken(args.Password, args.Endpoints)
+	reply.Token = token
+	return err
+}
+
+// RevokeTokenArgs are the arguments for calling RevokeToken
+type RevokeTokenArgs struct {
+	Password string `json:"password"`
+	Token    string `json:"token"`
+}
+
+// RevokeTokenReply are the results from calling RevokeToken
+type RevokeTokenReply struct {
+	Success bool `json:"success"`
+}
+
+// RevokeToken invalidates [args.Token]
+func (service *Admin) RevokeToken(_ *http.Request, args *RevokeTokenArgs, reply *RevokeTokenReply) error {
+	service.log.Debug("Admin: RevokeToken called")
+
+	if err := service.auth.RevokeToken(args.Password, args.Token); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// StartIPCArgs are the arguments for calling StartIPC
+type StartIPCArgs struct {
+	BlockchainID string `json:"blockchainID"`
+}
+
+// StartIPCReply are the results from calling StartIPC
+type StartIPCReply struct {
+	URL string `json:"url"`
+}
+
+// StartIPC publishes the containers accepted on [args.BlockchainID] to a Unix
+// domain socket at [reply.URL]. Each container is written to the socket as its
+// length, a 4 byte big-endian integer, followed by its bytes.
+func (service *Admin) StartIPC(_ *http.Request, args *StartIPCArgs, reply *StartIPCReply) error {
+	service.log.Debug("Admin: StartIPC called with %s", args.BlockchainID)
+
+	url, err := service.ipcs.Publish(args.BlockchainID)
+	reply.URL = url
+	return err
+}
+
+// StopIPCArgs are the arguments for calling StopIPC
+type StopIPCArgs struct {
+	BlockchainID string `json:"blockchainID"`
+}
+
+// StopIPCReply are the results from calling StopIPC
+type StopIPCReply struct {
+	Success bool `json:"success"`
+}
+
+// StopIPC stops publishing the containers accepted on [args.BlockchainID]
+func (service *Admin) StopIPC(_ *http.Request, args *StopIPCArgs, reply *StopIPCReply) error {
+	service.log.Debug("Admin: StopIPC called with %s", args.BlockchainID)
+
+	if err := service.ipcs.Unpublish(args.BlockchainID); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// WhitelistSubnetArgs are the arguments for calling WhitelistSubnet
+type WhitelistSubnetArgs struct {
+	SubnetID string `json:"subnetID"`
+}
+
+// WhitelistSubnetReply are the results from calling WhitelistSubnet
+type WhitelistSubnetReply struct {
+	// False if this node already validated the subnet
+	Success bool `json:"success"`
+}
+
+// WhitelistSubnet starts validating the subnet [args.SubnetID] without
+// restarting the node. The subnet's chains are created and bootstrapped. The
+// subnet isn't whitelisted when the node restarts unless it's given in
+// --whitelisted-subnets.
+func (service *Admin) WhitelistSubnet(_ *http.Request, args *WhitelistSubnetArgs, reply *WhitelistSubnetReply) error {
+	service.log.Debug("Admin: WhitelistSubnet called with %s", args.SubnetID)
+
+	subnetID, err := ids.FromString(args.SubnetID)
+	if err != nil {
+		return err
+	}
+	reply.Success = service.chainManager.WhitelistSubnet(subnetID)
+	return nil
+}
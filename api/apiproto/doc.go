@@ -0,0 +1,17 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package apiproto defines the gRPC services that serve the info, health, AVM
+// and platform APIs, and the REST mappings of their methods.
+//
+// The Go code is generated from the .proto files in this directory. To
+// regenerate it after changing them, run from the root of the repository:
+//
+//	protoc -I . -I $GOOGLEAPIS \
+//	  --go_out=paths=source_relative:. \
+//	  --go-grpc_out=paths=source_relative:. \
+//	  --grpc-gateway_out=paths=source_relative:. \
+//	  api/apiproto/*.proto
+//
+// where $GOOGLEAPIS holds the google/api annotation protos.
+package apiproto
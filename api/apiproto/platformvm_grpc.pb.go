@@ -0,0 +1,276 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: api/apiproto/platformvm.proto
+
+package apiproto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Platform_GetCurrentValidators_FullMethodName = "/gecko.api.Platform/GetCurrentValidators"
+	Platform_GetPendingValidators_FullMethodName = "/gecko.api.Platform/GetPendingValidators"
+	Platform_GetSubnets_FullMethodName           = "/gecko.api.Platform/GetSubnets"
+	Platform_GetBlockchainStatus_FullMethodName  = "/gecko.api.Platform/GetBlockchainStatus"
+	Platform_IssueTx_FullMethodName              = "/gecko.api.Platform/IssueTx"
+)
+
+// PlatformClient is the client API for Platform service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PlatformClient interface {
+	// GetCurrentValidators returns a page of the current stakers of a subnet,
+	// ordered by the time they stop staking
+	GetCurrentValidators(ctx context.Context, in *GetValidatorsRequest, opts ...grpc.CallOption) (*GetValidatorsResponse, error)
+	// GetPendingValidators returns a page of the pending stakers of a subnet,
+	// ordered by the time they start staking
+	GetPendingValidators(ctx context.Context, in *GetValidatorsRequest, opts ...grpc.CallOption) (*GetValidatorsResponse, error)
+	// GetSubnets returns the subnets with the given IDs, or every subnet other
+	// than the default subnet if no IDs are given
+	GetSubnets(ctx context.Context, in *GetSubnetsRequest, opts ...grpc.CallOption) (*GetSubnetsResponse, error)
+	// GetBlockchainStatus returns the status of a blockchain
+	GetBlockchainStatus(ctx context.Context, in *GetBlockchainStatusRequest, opts ...grpc.CallOption) (*GetBlockchainStatusResponse, error)
+	// IssueTx issues a signed transaction to the network
+	IssueTx(ctx context.Context, in *PlatformIssueTxRequest, opts ...grpc.CallOption) (*PlatformIssueTxResponse, error)
+}
+
+type platformClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPlatformClient(cc grpc.ClientConnInterface) PlatformClient {
+	return &platformClient{cc}
+}
+
+func (c *platformClient) GetCurrentValidators(ctx context.Context, in *GetValidatorsRequest, opts ...grpc.CallOption) (*GetValidatorsResponse, error) {
+	out := new(GetValidatorsResponse)
+	err := c.cc.Invoke(ctx, Platform_GetCurrentValidators_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) GetPendingValidators(ctx context.Context, in *GetValidatorsRequest, opts ...grpc.CallOption) (*GetValidatorsResponse, error) {
+	out := new(GetValidatorsResponse)
+	err := c.cc.Invoke(ctx, Platform_GetPendingValidators_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) GetSubnets(ctx context.Context, in *GetSubnetsRequest, opts ...grpc.CallOption) (*GetSubnetsResponse, error) {
+	out := new(GetSubnetsResponse)
+	err := c.cc.Invoke(ctx, Platform_GetSubnets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) GetBlockchainStatus(ctx context.Context, in *GetBlockchainStatusRequest, opts ...grpc.CallOption) (*GetBlockchainStatusResponse, error) {
+	out := new(GetBlockchainStatusResponse)
+	err := c.cc.Invoke(ctx, Platform_GetBlockchainStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *platformClient) IssueTx(ctx context.Context, in *PlatformIssueTxRequest, opts ...grpc.CallOption) (*PlatformIssueTxResponse, error) {
+	out := new(PlatformIssueTxResponse)
+	err := c.cc.Invoke(ctx, Platform_IssueTx_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PlatformServer is the server API for Platform service.
+// All implementations must embed UnimplementedPlatformServer
+// for forward compatibility
+type PlatformServer interface {
+	// GetCurrentValidators returns a page of the current stakers of a subnet,
+	// ordered by the time they stop staking
+	GetCurrentValidators(context.Context, *GetValidatorsRequest) (*GetValidatorsResponse, error)
+	// GetPendingValidators returns a page of the pending stakers of a subnet,
+	// ordered by the time they start staking
+	GetPendingValidators(context.Context, *GetValidatorsRequest) (*GetValidatorsResponse, error)
+	// GetSubnets returns the subnets with the given IDs, or every subnet other
+	// than the default subnet if no IDs are given
+	GetSubnets(context.Context, *GetSubnetsRequest) (*GetSubnetsResponse, error)
+	// GetBlockchainStatus returns the status of a blockchain
+	GetBlockchainStatus(context.Context, *GetBlockchainStatusRequest) (*GetBlockchainStatusResponse, error)
+	// IssueTx issues a signed transaction to the network
+	IssueTx(context.Context, *PlatformIssueTxRequest) (*PlatformIssueTxResponse, error)
+	mustEmbedUnimplementedPlatformServer()
+}
+
+// UnimplementedPlatformServer must be embedded to have forward compatible implementations.
+type UnimplementedPlatformServer struct {
+}
+
+func (UnimplementedPlatformServer) GetCurrentValidators(context.Context, *GetValidatorsRequest) (*GetValidatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentValidators not implemented")
+}
+func (UnimplementedPlatformServer) GetPendingValidators(context.Context, *GetValidatorsRequest) (*GetValidatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingValidators not implemented")
+}
+func (UnimplementedPlatformServer) GetSubnets(context.Context, *GetSubnetsRequest) (*GetSubnetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSubnets not implemented")
+}
+func (UnimplementedPlatformServer) GetBlockchainStatus(context.Context, *GetBlockchainStatusRequest) (*GetBlockchainStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockchainStatus not implemented")
+}
+func (UnimplementedPlatformServer) IssueTx(context.Context, *PlatformIssueTxRequest) (*PlatformIssueTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueTx not implemented")
+}
+func (UnimplementedPlatformServer) mustEmbedUnimplementedPlatformServer() {}
+
+// UnsafePlatformServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PlatformServer will
+// result in compilation errors.
+type UnsafePlatformServer interface {
+	mustEmbedUnimplementedPlatformServer()
+}
+
+func RegisterPlatformServer(s grpc.ServiceRegistrar, srv PlatformServer) {
+	s.RegisterService(&Platform_ServiceDesc, srv)
+}
+
+func _Platform_GetCurrentValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValidatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).GetCurrentValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Platform_GetCurrentValidators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).GetCurrentValidators(ctx, req.(*GetValidatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_GetPendingValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetValidatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).GetPendingValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Platform_GetPendingValidators_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).GetPendingValidators(ctx, req.(*GetValidatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_GetSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).GetSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Platform_GetSubnets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).GetSubnets(ctx, req.(*GetSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_GetBlockchainStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockchainStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).GetBlockchainStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Platform_GetBlockchainStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).GetBlockchainStatus(ctx, req.(*GetBlockchainStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Platform_IssueTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlatformIssueTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PlatformServer).IssueTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Platform_IssueTx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PlatformServer).IssueTx(ctx, req.(*PlatformIssueTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Platform_ServiceDesc is the grpc.ServiceDesc for Platform service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Platform_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gecko.api.Platform",
+	HandlerType: (*PlatformServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCurrentValidators",
+			Handler:    _Platform_GetCurrentValidators_Handler,
+		},
+		{
+			MethodName: "GetPendingValidators",
+			Handler:    _Platform_GetPendingValidators_Handler,
+		},
+		{
+			MethodName: "GetSubnets",
+			Handler:    _Platform_GetSubnets_Handler,
+		},
+		{
+			MethodName: "GetBlockchainStatus",
+			Handler:    _Platform_GetBlockchainStatus_Handler,
+		},
+		{
+			MethodName: "IssueTx",
+			Handler:    _Platform_IssueTx_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/apiproto/platformvm.proto",
+}
@@ -0,0 +1,251 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+const (
+	// AllEndpoints is the endpoint a token may be scoped to in order to be
+	// accepted at every endpoint
+	AllEndpoints = "*"
+
+	// Header that carries the token of a call, as "Bearer <token>"
+	headerKey    = "Authorization"
+	headerPrefix = "Bearer "
+
+	tokenLen = 32
+)
+
+var (
+	errAuthDisabled  = errors.New("authorization is disabled on this node")
+	errWrongPassword = errors.New("incorrect password")
+	errNoEndpoints   = errors.New("a token must be scoped to at least one endpoint")
+	errNoToken       = errors.New("this method requires an authorization token")
+	errUnknownToken  = errors.New("the authorization token is unknown or was revoked")
+	errWrongEndpoint = errors.New("the authorization token isn't valid at this endpoint")
+)
+
+// Auth issues bearer tokens and checks that calls to restricted API methods
+// carry a token that is valid at the endpoint being called.
+// The zero value is a disabled Auth, which lets every call through.
+type Auth struct {
+	lock sync.RWMutex
+
+	enabled  bool
+	password string
+
+	// Restricted namespaces and methods, lower-cased. A namespace is
+	// restricted in its entirety, for example "keystore", whereas a method is
+	// restricted on its own, for example "platform.createaccount".
+	restricted map[string]bool
+	// Methods of restricted namespaces that don't require a token, lower-cased
+	exempt map[string]bool
+
+	// Maps each token to the endpoints it's valid at
+	tokens map[string][]string
+}
+
+// Initialize the auth. If [enabled], calls to restricted methods require a
+// token, and tokens are issued to callers that know [password].
+func (a *Auth) Initialize(enabled bool, password string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.enabled = enabled
+	a.password = password
+	a.restricted = make(map[string]bool)
+	a.exempt = make(map[string]bool)
+	a.tokens = make(map[string][]string)
+}
+
+// Restrict requires a token to call each of [names], which are either
+// namespaces (e.g. "keystore") or methods (e.g. "platform.createAccount")
+func (a *Auth) Restrict(names ...string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, name := range names {
+		a.restricted[strings.ToLower(name)] = true
+	}
+}
+
+// Exempt lets the methods [methods] be called without a token even though
+// their namespace is restricted
+func (a *Auth) Exempt(methods ...string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, method := range methods {
+		a.exempt[strings.ToLower(method)] = true
+	}
+}
+
+// NewToken returns a new token that is valid at [endpoints], such as
+// "/ext/keystore" or "/ext/bc/P". A token valid at AllEndpoints is valid
+// everywhere.
+func (a *Auth) NewToken(password string, endpoints []string) (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.checkPassword(password); err != nil {
+		return "", err
+	}
+	if len(endpoints) == 0 {
+		return "", errNoEndpoints
+	}
+
+	tokenBytes := make([]byte, tokenLen)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := formatting.CB58{Bytes: tokenBytes}.String()
+
+	scope := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		scope[i] = strings.TrimSuffix(endpoint, "/")
+	}
+	a.tokens[token] = scope
+	return token, nil
+}
+
+// RevokeToken invalidates [token]
+func (a *Auth) RevokeToken(password, token string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.checkPassword(password); err != nil {
+		return err
+	}
+	if _, exists := a.tokens[token]; !exists {
+		return errUnknownToken
+	}
+	delete(a.tokens, token)
+	return nil
+}
+
+// WrapHandler returns a handler that rejects calls to restricted methods
+// unless they carry a token that is valid at the endpoint being called.
+// Calls are otherwise passed on to [h].
+func (a *Auth) WrapHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := a.authorize(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// authorize returns nil if [r] may be served
+func (a *Auth) authorize(r *http.Request) error {
+	a.lock.RLock()
+	enabled := a.enabled
+	a.lock.RUnlock()
+
+	if !enabled || r.Body == nil {
+		return nil
+	}
+
+	// The body is read to find the method being called, then put back so the
+	// handler can read it too
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	methods := calledMethods(body)
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	restricted := false
+	for _, method := range methods {
+		restricted = restricted || a.isRestricted(method)
+	}
+	if !restricted {
+		return nil
+	}
+
+	header := r.Header.Get(headerKey)
+	if !strings.HasPrefix(header, headerPrefix) {
+		return errNoToken
+	}
+	endpoints, exists := a.tokens[strings.TrimPrefix(header, headerPrefix)]
+	if !exists {
+		return errUnknownToken
+	}
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	for _, endpoint := range endpoints {
+		if endpoint == AllEndpoints || endpoint == path {
+			return nil
+		}
+	}
+	return errWrongEndpoint
+}
+
+// calledMethods returns the JSON-RPC methods called by [body], which is either
+// a call or a batch of calls. If [body] isn't JSON-RPC, no methods are
+// returned.
+func calledMethods(body []byte) []string {
+	calls := []json.RawMessage(nil)
+	if err := json.Unmarshal(body, &calls); err != nil {
+		calls = []json.RawMessage{body}
+	}
+
+	methods := []string(nil)
+	for _, callBytes := range calls {
+		call := struct {
+			Method string `json:"method"`
+		}{}
+		// A call that can't be parsed can't be served either, so it doesn't
+		// call a method
+		if err := json.Unmarshal(callBytes, &call); err == nil {
+			methods = append(methods, call.Method)
+		}
+	}
+	return methods
+}
+
+// isRestricted returns true if calling [method] requires a token
+// Assumes the lock is held
+func (a *Auth) isRestricted(method string) bool {
+	method = strings.ToLower(method)
+	if a.restricted[method] {
+		return true
+	}
+	if a.exempt[method] {
+		return false
+	}
+	namespace := method
+	if i := strings.Index(method, "."); i >= 0 {
+		namespace = method[:i]
+	}
+	return a.restricted[namespace]
+}
+
+// checkPassword returns nil if [password] is the auth's password
+// Assumes the lock is held
+func (a *Auth) checkPassword(password string) error {
+	if !a.enabled {
+		return errAuthDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(password), []byte(a.password)) != 1 {
+		return errWrongPassword
+	}
+	return nil
+}
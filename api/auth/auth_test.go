@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const password = "launch"
+
+// echo writes the body of each call it's passed
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Write(body)
+})
+
+func call(handler http.Handler, endpoint, method, token string) *httptest.ResponseRecorder {
+	body := `{"jsonrpc":"2.0","method":"` + method + `","params":[{}],"id":1}`
+	req := httptest.NewRequest("POST", endpoint, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set(headerKey, headerPrefix+token)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func newAuth() *Auth {
+	a := &Auth{}
+	a.Initialize(true, password)
+	a.Restrict("keystore", "admin", "platform.createAccount")
+	a.Exempt("admin.newToken")
+	return a
+}
+
+func TestAuthRestrictedMethods(t *testing.T) {
+	handler := newAuth().WrapHandler(echo)
+
+	tests := []struct {
+		endpoint, method string
+		allowed          bool
+	}{
+		{"/ext/keystore", "keystore.createUser", false},
+		{"/ext/admin", "admin.peers", false},
+		{"/ext/admin", "admin.newToken", true},
+		{"/ext/bc/P", "platform.createAccount", false},
+		{"/ext/bc/P", "platform.CreateAccount", false},
+		{"/ext/bc/P", "platform.getTimestamp", true},
+		{"/ext/metrics", "metrics.get", true},
+	}
+	for _, test := range tests {
+		w := call(handler, test.endpoint, test.method, "")
+		if allowed := w.Code == http.StatusOK; allowed != test.allowed {
+			t.Fatalf("%s at %s: allowed %t, expected %t", test.method, test.endpoint, allowed, test.allowed)
+		}
+		if test.allowed && !strings.Contains(w.Body.String(), test.method) {
+			t.Fatalf("the handler should have read the body of the call but wrote %s", w.Body.String())
+		}
+	}
+}
+
+func TestAuthTokens(t *testing.T) {
+	a := newAuth()
+	handler := a.WrapHandler(echo)
+
+	if _, err := a.NewToken("wrong", []string{"/ext/keystore"}); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+	if _, err := a.NewToken(password, nil); err == nil {
+		t.Fatalf("Should have errored because the token isn't valid anywhere")
+	}
+
+	token, err := a.NewToken(password, []string{"/ext/keystore/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := call(handler, "/ext/keystore", "keystore.createUser", token); w.Code != http.StatusOK {
+		t.Fatalf("The token should have been accepted but the response was %d: %s", w.Code, w.Body.String())
+	}
+	if w := call(handler, "/ext/admin", "admin.peers", token); w.Code != http.StatusUnauthorized {
+		t.Fatalf("The token shouldn't have been accepted at another endpoint")
+	}
+	if w := call(handler, "/ext/keystore", "keystore.createUser", token+"a"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("An unknown token shouldn't have been accepted")
+	}
+
+	allToken, err := a.NewToken(password, []string{AllEndpoints})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := call(handler, "/ext/admin", "admin.peers", allToken); w.Code != http.StatusOK {
+		t.Fatalf("The token should have been accepted at every endpoint")
+	}
+
+	if err := a.RevokeToken("wrong", token); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+	if err := a.RevokeToken(password, token); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.RevokeToken(password, token); err == nil {
+		t.Fatalf("Should have errored because the token was already revoked")
+	}
+	if w := call(handler, "/ext/keystore", "keystore.createUser", token); w.Code != http.StatusUnauthorized {
+		t.Fatalf("A revoked token shouldn't have been accepted")
+	}
+}
+
+func TestAuthDisabled(t *testing.T) {
+	a := &Auth{}
+	handler := a.WrapHandler(echo)
+	if w := call(handler, "/ext/keystore", "keystore.createUser", ""); w.Code != http.StatusOK {
+		t.Fatalf("A disabled auth should let every call through")
+	}
+
+	a.Initialize(false, password)
+	a.Restrict("keystore")
+	if w := call(handler, "/ext/keystore", "keystore.createUser", ""); w.Code != http.StatusOK {
+		t.Fatalf("A disabled auth should let every call through")
+	}
+	if _, err := a.NewToken(password, []string{AllEndpoints}); err == nil {
+		t.Fatalf("A disabled auth shouldn't issue tokens")
+	}
+}
+
+func TestAuthBatches(t *testing.T) {
+	handler := newAuth().WrapHandler(echo)
+
+	tests := []struct {
+		body    string
+		allowed bool
+	}{
+		{`[{"method":"platform.getTimestamp","id":1},{"method":"avm.getTx","id":2}]`, true},
+		{`[{"method":"platform.getTimestamp","id":1},{"method":"keystore.exportUser","id":2}]`, false},
+		{`["not a call",{"method":"admin.peers","id":1}]`, false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/ext/bc/X", strings.NewReader(test.body))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if allowed := w.Code == http.StatusOK; allowed != test.allowed {
+			t.Fatalf("%s: allowed %t, expected %t", test.body, allowed, test.allowed)
+		}
+	}
+}
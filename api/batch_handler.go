@@ -0,0 +1,179 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	errCodeParse          = -32700
+	errCodeInvalidRequest = -32600
+)
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCErrorResponse struct {
+	Version string          `json:"jsonrpc"`
+	Error   jsonRPCError    `json:"error"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// newErrorResponse returns the response to the request with ID [id], which
+// failed with [message]
+func newErrorResponse(id json.RawMessage, code int, message string) []byte {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	response, _ := json.Marshal(jsonRPCErrorResponse{
+		Version: "2.0",
+		Error:   jsonRPCError{Code: code, Message: message},
+		ID:      id,
+	})
+	return response
+}
+
+// batchHandler serves JSON-RPC 2.0 batches, which are arrays of requests, by
+// passing each request in the batch to [handler] and responding with the
+// array of their responses. A request in the batch that fails has an error as
+// its response without failing the rest of the batch.
+type batchHandler struct {
+	handler http.Handler
+	// Maximum size, in bytes, of a request, or of a request in a batch. Not
+	// limited if 0.
+	maxRequestSize int64
+	// Maximum number of requests in a batch. Not limited if 0.
+	maxBatchSize int
+}
+
+func (bh batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Only JSON-RPC calls are POSTed. Other requests, such as websocket
+	// upgrades, are passed through.
+	if r.Method != http.MethodPost || r.Body == nil {
+		bh.handler.ServeHTTP(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(bh.limitBody(w, r))
+	if err != nil {
+		bh.writeError(w, http.StatusRequestEntityTooLarge, errCodeInvalidRequest, fmt.Sprintf("couldn't read request: %s", err))
+		return
+	}
+	r.Body.Close()
+
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		if bh.maxRequestSize > 0 && int64(len(body)) > bh.maxRequestSize {
+			bh.writeError(w, http.StatusRequestEntityTooLarge, errCodeInvalidRequest, fmt.Sprintf("request is larger than %d bytes", bh.maxRequestSize))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		bh.handler.ServeHTTP(w, r)
+		return
+	}
+
+	requests := []json.RawMessage(nil)
+	if err := json.Unmarshal(trimmed, &requests); err != nil {
+		bh.writeError(w, http.StatusOK, errCodeParse, fmt.Sprintf("couldn't parse batch: %s", err))
+		return
+	}
+	switch {
+	case len(requests) == 0:
+		bh.writeError(w, http.StatusOK, errCodeInvalidRequest, "batch is empty")
+		return
+	case bh.maxBatchSize > 0 && len(requests) > bh.maxBatchSize:
+		bh.writeError(w, http.StatusOK, errCodeInvalidRequest, fmt.Sprintf("batch has %d requests but at most %d are allowed", len(requests), bh.maxBatchSize))
+		return
+	}
+
+	responses := make([]json.RawMessage, 0, len(requests))
+	for _, request := range requests {
+		if response := bh.serveRequest(r, request); len(response) > 0 {
+			responses = append(responses, response)
+		}
+	}
+
+	// A batch of only notifications has no response
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(responses)
+}
+
+// limitBody returns the body of [r], limited to the size of the largest
+// batch that may be served
+func (bh batchHandler) limitBody(w http.ResponseWriter, r *http.Request) io.Reader {
+	if bh.maxRequestSize <= 0 {
+		return r.Body
+	}
+	maxBatchSize := int64(bh.maxBatchSize)
+	if maxBatchSize <= 0 {
+		maxBatchSize = 1
+	}
+	return http.MaxBytesReader(w, r.Body, bh.maxRequestSize*maxBatchSize)
+}
+
+// serveRequest serves [request], which is part of the batch [batch], and
+// returns its response. Returns nil if [request] is a notification.
+func (bh batchHandler) serveRequest(batch *http.Request, request json.RawMessage) json.RawMessage {
+	header := struct {
+		ID json.RawMessage `json:"id"`
+	}{}
+	if err := json.Unmarshal(request, &header); err != nil {
+		return newErrorResponse(nil, errCodeInvalidRequest, fmt.Sprintf("couldn't parse request: %s", err))
+	}
+	if bh.maxRequestSize > 0 && int64(len(request)) > bh.maxRequestSize {
+		return newErrorResponse(header.ID, errCodeInvalidRequest, fmt.Sprintf("request is larger than %d bytes", bh.maxRequestSize))
+	}
+
+	r := batch.Clone(batch.Context())
+	r.Body = ioutil.NopCloser(bytes.NewReader(request))
+	r.ContentLength = int64(len(request))
+	w := &bufferedResponseWriter{header: make(http.Header)}
+	bh.handler.ServeHTTP(w, r)
+
+	response := bytes.TrimSpace(w.body.Bytes())
+	switch {
+	case len(response) == 0:
+		return nil
+	case !json.Valid(response):
+		// The handler didn't reply with JSON, such as when the request was
+		// rejected before it reached the JSON-RPC server
+		return newErrorResponse(header.ID, errCodeInvalidRequest, string(response))
+	default:
+		return response
+	}
+}
+
+// writeError responds with a single JSON-RPC error
+func (bh batchHandler) writeError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(newErrorResponse(nil, code, message))
+}
+
+// bufferedResponseWriter records the body of the response to a request in a
+// batch. The status of each response is dropped, as the batch is responded to
+// with a single status.
+type bufferedResponseWriter struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header { return w.header }
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *bufferedResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var errOdd = errors.New("odd")
+
+type EchoService struct{}
+
+type EchoArgs struct {
+	Value int `json:"value"`
+}
+
+type EchoReply struct {
+	Value int `json:"value"`
+}
+
+func (EchoService) Echo(_ *http.Request, args *EchoArgs, reply *EchoReply) error {
+	if args.Value%2 == 1 {
+		return errOdd
+	}
+	reply.Value = args.Value
+	return nil
+}
+
+func newBatchHandler(maxRequestSize int64, maxBatchSize int) http.Handler {
+	server := rpc.NewServer()
+	server.RegisterCodec(cjson.NewCodec(), "application/json")
+	server.RegisterService(EchoService{}, "echo")
+	return batchHandler{
+		handler:        server,
+		maxRequestSize: maxRequestSize,
+		maxBatchSize:   maxBatchSize,
+	}
+}
+
+func post(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/ext/echo", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+type response struct {
+	Result *EchoReply       `json:"result"`
+	Error  *jsonRPCError    `json:"error"`
+	ID     *json.RawMessage `json:"id"`
+}
+
+func TestBatchHandler(t *testing.T) {
+	handler := newBatchHandler(1000, 10)
+
+	w := post(handler, `[
+		{"jsonrpc":"2.0","method":"echo.echo","params":[{"value":2}],"id":1},
+		{"jsonrpc":"2.0","method":"echo.echo","params":[{"value":3}],"id":2},
+		{"jsonrpc":"2.0","method":"echo.echo","params":[{"value":4}]},
+		{"jsonrpc":"2.0","method":"echo.unknown","params":[{}],"id":3},
+		5
+	]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d but got %d", http.StatusOK, w.Code)
+	}
+	responses := []response(nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Couldn't parse %s: %s", w.Body.String(), err)
+	}
+	// The notification isn't responded to
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses but got %s", w.Body.String())
+	}
+	if responses[0].Result == nil || responses[0].Result.Value != 2 {
+		t.Fatalf("The first request should have succeeded but got %s", w.Body.String())
+	}
+	for i, response := range responses[1:] {
+		if response.Error == nil {
+			t.Fatalf("Request %d should have failed but got %s", i+1, w.Body.String())
+		}
+	}
+}
+
+func TestBatchHandlerLimits(t *testing.T) {
+	handler := newBatchHandler(200, 3)
+
+	call := `{"jsonrpc":"2.0","method":"echo.echo","params":[{"value":2}],"id":1}`
+	if w := post(handler, call); !strings.Contains(w.Body.String(), `"value":2`) {
+		t.Fatalf("Single calls should be served but got %s", w.Body.String())
+	}
+	if w := post(newBatchHandler(1000, 2), "["+call+","+call+","+call+"]"); !strings.Contains(w.Body.String(), "at most 2") {
+		t.Fatalf("The batch should have been too large but got %s", w.Body.String())
+	}
+
+	large := `{"jsonrpc":"2.0","method":"echo.echo","params":[{"value":2}],"id":"` + strings.Repeat("a", 250) + `"}`
+	if w := post(handler, large); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status %d but got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+	responses := []response(nil)
+	if err := json.Unmarshal(post(handler, "["+call+","+large+"]").Body.Bytes(), &responses); err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 2 || responses[0].Result == nil || responses[1].Error == nil {
+		t.Fatalf("Only the large request in the batch should have failed")
+	}
+}
+
+func TestBatchHandlerEmpty(t *testing.T) {
+	w := post(newBatchHandler(0, 0), "[]")
+	if !strings.Contains(w.Body.String(), "batch is empty") {
+		t.Fatalf("Empty batches should be invalid but got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,304 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// Size of the ws read buffer
+	readBufferSize = 1024
+
+	// Size of the ws write buffer
+	writeBufferSize = 1024
+
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from peer.
+	maxMessageSize = 4096 // bytes
+
+	// Maximum number of pending messages to send to a peer.
+	maxPendingMessages = 256 // messages
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  readBufferSize,
+	WriteBufferSize: writeBufferSize,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// Server pushes the transactions and blocks accepted on each chain to the
+// websocket connections subscribed to the chain. It's registered with the
+// decision dispatcher to be told of accepted containers.
+type Server struct {
+	log logging.Logger
+
+	lock sync.Mutex
+	// Maps each chain to the connections subscribed to it
+	chains map[[32]byte]map[*connection]*subscription
+	conns  map[*connection]struct{}
+}
+
+// NewServer returns a new events server
+func NewServer(log logging.Logger) *Server {
+	return &Server{
+		log:    log,
+		chains: make(map[[32]byte]map[*connection]*subscription),
+		conns:  make(map[*connection]struct{}),
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("Failed to upgrade %s", err)
+		return
+	}
+	conn := &connection{s: s, conn: wsConn, send: make(chan interface{}, maxPendingMessages)}
+
+	s.lock.Lock()
+	s.conns[conn] = struct{}{}
+	s.lock.Unlock()
+
+	go conn.writePump()
+	go conn.readPump()
+}
+
+// Accept is called when [container], with ID [containerID], is accepted on
+// the chain [chainID]. It's sent to the connections subscribed to the chain
+// whose filters it passes.
+func (s *Server) Accept(chainID, containerID ids.ID, container []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	conns := s.chains[chainID.Key()]
+	if len(conns) == 0 {
+		return nil
+	}
+
+	msg := &accepted{
+		ChainID:     chainID.String(),
+		ContainerID: containerID.String(),
+		Container:   formatting.CB58{Bytes: container},
+	}
+	for conn, sub := range conns {
+		if !sub.matches(container) {
+			continue
+		}
+		select {
+		case conn.send <- msg:
+		default:
+			s.log.Verbo("dropping message to subscribed connection due to too many pending messages")
+		}
+	}
+	return nil
+}
+
+// subscribe [conn] to the chain [chainID], replacing any previous subscription
+// of [conn] to the chain
+func (s *Server) subscribe(conn *connection, chainID ids.ID, sub *subscription) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.conns[conn]; !exists {
+		return
+	}
+
+	conns, exists := s.chains[chainID.Key()]
+	if !exists {
+		conns = make(map[*connection]*subscription)
+		s.chains[chainID.Key()] = conns
+	}
+	conns[conn] = sub
+}
+
+// unsubscribe [conn] from the chain [chainID]
+func (s *Server) unsubscribe(conn *connection, chainID ids.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.unsubscribeChain(conn, chainID.Key())
+}
+
+func (s *Server) removeConnection(conn *connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.conns, conn)
+	for chainKey := range s.chains {
+		s.unsubscribeChain(conn, chainKey)
+	}
+}
+
+// Assumes the lock is held
+func (s *Server) unsubscribeChain(conn *connection, chainKey [32]byte) {
+	conns, exists := s.chains[chainKey]
+	if !exists {
+		return
+	}
+	delete(conns, conn)
+	if len(conns) == 0 {
+		delete(s.chains, chainKey)
+	}
+}
+
+// subscription of a connection to a chain
+type subscription struct {
+	// If not empty, only containers that contain one of these addresses are
+	// sent
+	addresses []ids.ShortID
+}
+
+// matches returns true if [container] should be sent to the subscriber
+func (sub *subscription) matches(container []byte) bool {
+	if len(sub.addresses) == 0 {
+		return true
+	}
+	for _, addr := range sub.addresses {
+		if bytes.Contains(container, addr.Bytes()) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe is the message a client sends to subscribe to, or unsubscribe
+// from, a chain
+type subscribe struct {
+	ChainID string `json:"chainID"`
+	// If not empty, only containers that contain one of these addresses are
+	// sent. An address may be prefixed by a chain alias, as in "X-<address>".
+	Addresses   []string `json:"addresses"`
+	Unsubscribe bool     `json:"unsubscribe"`
+}
+
+// accepted is the message sent to clients when a container is accepted on a
+// chain they're subscribed to
+type accepted struct {
+	ChainID     string          `json:"chainID"`
+	ContainerID string          `json:"containerID"`
+	Container   formatting.CB58 `json:"container"`
+}
+
+// errorMsg is the message sent to clients whose subscription is invalid
+type errorMsg struct {
+	Error string `json:"error"`
+}
+
+// parseSubscription returns the chain and subscription that [msg] describes
+func parseSubscription(msg *subscribe) (ids.ID, *subscription, error) {
+	chainID, err := ids.FromString(msg.ChainID)
+	if err != nil {
+		return ids.ID{}, nil, err
+	}
+	sub := &subscription{addresses: make([]ids.ShortID, len(msg.Addresses))}
+	for i, addrStr := range msg.Addresses {
+		if j := strings.LastIndex(addrStr, "-"); j >= 0 {
+			addrStr = addrStr[j+1:]
+		}
+		addr, err := ids.ShortFromString(addrStr)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		sub.addresses[i] = addr
+	}
+	return chainID, sub, nil
+}
+
+// connection is a websocket connection to a client
+type connection struct {
+	s *Server
+
+	// The websocket connection.
+	conn *websocket.Conn
+
+	// Buffered channel of outbound messages.
+	send chan interface{}
+}
+
+// readPump reads subscriptions from the websocket connection.
+//
+// The application runs readPump in a per-connection goroutine. The application
+// ensures that there is at most one reader on a connection by executing all
+// reads from this goroutine.
+func (c *connection) readPump() {
+	defer func() {
+		c.s.removeConnection(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+
+	for {
+		msg := subscribe{}
+		err := c.conn.ReadJSON(&msg)
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.s.log.Debug("Unexpected close in websockets: %s", err)
+			}
+			break
+		}
+
+		chainID, sub, err := parseSubscription(&msg)
+		if err != nil {
+			select {
+			case c.send <- &errorMsg{Error: err.Error()}:
+			default:
+			}
+			continue
+		}
+		if msg.Unsubscribe {
+			c.s.unsubscribe(c, chainID)
+		} else {
+			c.s.subscribe(c, chainID, sub)
+		}
+	}
+}
+
+// writePump writes messages to the websocket connection.
+//
+// A goroutine running writePump is started for each connection. The
+// application ensures that there is at most one writer to a connection by
+// executing all writes from this goroutine.
+func (c *connection) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
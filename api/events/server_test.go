@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func dial(t *testing.T, server *httptest.Server) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// waitForSubscribers waits until [n] connections are subscribed to [chainID]
+func waitForSubscribers(t *testing.T, s *Server, chainID ids.ID, n int) {
+	for i := 0; i < 100; i++ {
+		s.lock.Lock()
+		subscribers := len(s.chains[chainID.Key()])
+		s.lock.Unlock()
+		if subscribers == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%d connections should have subscribed to %s", n, chainID)
+}
+
+func TestServerAccept(t *testing.T) {
+	s := NewServer(logging.NoLog{})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	chainID := ids.NewID([32]byte{1})
+	otherChainID := ids.NewID([32]byte{2})
+	addr := ids.NewShortID([20]byte{3})
+
+	all := dial(t, server)
+	defer all.Close()
+	filtered := dial(t, server)
+	defer filtered.Close()
+
+	if err := all.WriteJSON(&subscribe{ChainID: chainID.String()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := filtered.WriteJSON(&subscribe{
+		ChainID:   chainID.String(),
+		Addresses: []string{"X-" + addr.String()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	waitForSubscribers(t, s, chainID, 2)
+
+	unrelated := []byte{0, 1, 2}
+	related := append([]byte{0}, addr.Bytes()...)
+	if err := s.Accept(otherChainID, ids.NewID([32]byte{4}), related); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Accept(chainID, ids.NewID([32]byte{5}), unrelated); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Accept(chainID, ids.NewID([32]byte{6}), related); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := accepted{}
+	for _, expected := range []ids.ID{ids.NewID([32]byte{5}), ids.NewID([32]byte{6})} {
+		if err := all.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.ChainID != chainID.String() || msg.ContainerID != expected.String() {
+			t.Fatalf("Expected %s on %s but got %s on %s", expected, chainID, msg.ContainerID, msg.ChainID)
+		}
+	}
+
+	// The filtered connection only gets the container with its address
+	if err := filtered.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if expected := ids.NewID([32]byte{6}); msg.ContainerID != expected.String() {
+		t.Fatalf("Expected %s but got %s", expected, msg.ContainerID)
+	}
+
+	if err := all.WriteJSON(&subscribe{ChainID: chainID.String(), Unsubscribe: true}); err != nil {
+		t.Fatal(err)
+	}
+	waitForSubscribers(t, s, chainID, 1)
+
+	filtered.Close()
+	waitForSubscribers(t, s, chainID, 0)
+}
+
+func TestServerInvalidSubscription(t *testing.T) {
+	s := NewServer(logging.NoLog{})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	conn := dial(t, server)
+	defer conn.Close()
+
+	if err := conn.WriteJSON(&subscribe{ChainID: "not a chain"}); err != nil {
+		t.Fatal(err)
+	}
+	msg := errorMsg{}
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Error == "" {
+		t.Fatalf("Should have been told the subscription is invalid")
+	}
+}
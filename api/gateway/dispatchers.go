@@ -0,0 +1,180 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/ids"
+)
+
+var errPlatformUnavailable = status.Error(codes.Unavailable, "the platform chain hasn't been created yet")
+
+// avmDispatcher passes each AVM request to the server of the chain it names
+type avmDispatcher struct {
+	apiproto.UnimplementedAVMServer
+
+	chains Resolver
+
+	lock    sync.RWMutex
+	servers map[[32]byte]apiproto.AVMServer
+}
+
+func newAVMDispatcher(chains Resolver) *avmDispatcher {
+	return &avmDispatcher{
+		chains:  chains,
+		servers: make(map[[32]byte]apiproto.AVMServer),
+	}
+}
+
+func (d *avmDispatcher) add(chainID ids.ID, server apiproto.AVMServer) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.servers[chainID.Key()] = server
+}
+
+// server returns the server of [chain], which may be an alias or an ID
+func (d *avmDispatcher) server(chain string) (apiproto.AVMServer, error) {
+	chainID, err := d.chains.Lookup(chain)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "unknown chain %q", chain)
+	}
+
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	server, exists := d.servers[chainID.Key()]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "chain %q doesn't run the AVM", chain)
+	}
+	return server, nil
+}
+
+func (d *avmDispatcher) IssueTx(ctx context.Context, req *apiproto.AVMIssueTxRequest) (*apiproto.AVMIssueTxResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.IssueTx(ctx, req)
+}
+
+func (d *avmDispatcher) GetTxStatus(ctx context.Context, req *apiproto.GetTxStatusRequest) (*apiproto.GetTxStatusResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.GetTxStatus(ctx, req)
+}
+
+func (d *avmDispatcher) GetTx(ctx context.Context, req *apiproto.GetTxRequest) (*apiproto.GetTxResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.GetTx(ctx, req)
+}
+
+func (d *avmDispatcher) GetUTXOs(ctx context.Context, req *apiproto.GetUTXOsRequest) (*apiproto.GetUTXOsResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.GetUTXOs(ctx, req)
+}
+
+func (d *avmDispatcher) GetBalance(ctx context.Context, req *apiproto.GetBalanceRequest) (*apiproto.GetBalanceResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.GetBalance(ctx, req)
+}
+
+func (d *avmDispatcher) GetAssetDescription(ctx context.Context, req *apiproto.GetAssetDescriptionRequest) (*apiproto.GetAssetDescriptionResponse, error) {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return server.GetAssetDescription(ctx, req)
+}
+
+func (d *avmDispatcher) SubscribeAcceptedTxs(req *apiproto.SubscribeAcceptedTxsRequest, stream apiproto.AVM_SubscribeAcceptedTxsServer) error {
+	server, err := d.server(req.Chain)
+	if err != nil {
+		return err
+	}
+	return server.SubscribeAcceptedTxs(req, stream)
+}
+
+// platformDispatcher passes platform requests to the platform chain's server
+// once it's been created
+type platformDispatcher struct {
+	apiproto.UnimplementedPlatformServer
+
+	lock   sync.RWMutex
+	server apiproto.PlatformServer
+}
+
+func (d *platformDispatcher) set(server apiproto.PlatformServer) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.server = server
+}
+
+func (d *platformDispatcher) get() (apiproto.PlatformServer, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if d.server == nil {
+		return nil, errPlatformUnavailable
+	}
+	return d.server, nil
+}
+
+func (d *platformDispatcher) GetCurrentValidators(ctx context.Context, req *apiproto.GetValidatorsRequest) (*apiproto.GetValidatorsResponse, error) {
+	server, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetCurrentValidators(ctx, req)
+}
+
+func (d *platformDispatcher) GetPendingValidators(ctx context.Context, req *apiproto.GetValidatorsRequest) (*apiproto.GetValidatorsResponse, error) {
+	server, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetPendingValidators(ctx, req)
+}
+
+func (d *platformDispatcher) GetSubnets(ctx context.Context, req *apiproto.GetSubnetsRequest) (*apiproto.GetSubnetsResponse, error) {
+	server, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetSubnets(ctx, req)
+}
+
+func (d *platformDispatcher) GetBlockchainStatus(ctx context.Context, req *apiproto.GetBlockchainStatusRequest) (*apiproto.GetBlockchainStatusResponse, error) {
+	server, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return server.GetBlockchainStatus(ctx, req)
+}
+
+func (d *platformDispatcher) IssueTx(ctx context.Context, req *apiproto.PlatformIssueTxRequest) (*apiproto.PlatformIssueTxResponse, error) {
+	server, err := d.get()
+	if err != nil {
+		return nil, err
+	}
+	return server.IssueTx(ctx, req)
+}
@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/api/health"
+	"github.com/ava-labs/gecko/api/info"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+// RESTPrefix is the path the REST endpoints are served under by the API
+// server. It's stripped before requests are routed to the gateway.
+const RESTPrefix = "/ext/rest"
+
+// Resolver returns the ID of the chain an alias refers to
+type Resolver interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// Gateway serves the info, health, AVM and platform APIs over gRPC and, by
+// translating them to the same calls, as REST endpoints.
+// It's registered with the chain manager so that it can serve the AVM API of
+// every AVM chain, and the platform API once the platform chain is created.
+type Gateway struct {
+	log      logging.Logger
+	server   *grpc.Server
+	mux      *runtime.ServeMux
+	avm      *avmDispatcher
+	platform *platformDispatcher
+}
+
+// New returns a gateway that serves [infoService] and [healthService], if
+// they're non-nil, and the chains registered with it. AVM requests name their
+// chain by an alias or ID, which is resolved with [chains].
+func New(log logging.Logger, chains Resolver, infoService *info.Info, healthService *health.Health) (*Gateway, error) {
+	g := &Gateway{
+		log:      log,
+		server:   grpc.NewServer(),
+		mux:      runtime.NewServeMux(),
+		avm:      newAVMDispatcher(chains),
+		platform: &platformDispatcher{},
+	}
+
+	ctx := context.Background()
+	errs := wrappers.Errs{}
+	if infoService != nil {
+		server := info.NewGRPCServer(infoService)
+		apiproto.RegisterInfoServer(g.server, server)
+		errs.Add(apiproto.RegisterInfoHandlerServer(ctx, g.mux, server))
+	}
+	if healthService != nil {
+		server := health.NewGRPCServer(healthService)
+		apiproto.RegisterHealthServer(g.server, server)
+		errs.Add(apiproto.RegisterHealthHandlerServer(ctx, g.mux, server))
+	}
+	apiproto.RegisterAVMServer(g.server, g.avm)
+	apiproto.RegisterPlatformServer(g.server, g.platform)
+	errs.Add(
+		apiproto.RegisterAVMHandlerServer(ctx, g.mux, g.avm),
+		apiproto.RegisterPlatformHandlerServer(ctx, g.mux, g.platform),
+	)
+	return g, errs.Err
+}
+
+// RegisterChain implements the chains.Registrant interface
+// Chains that don't run the AVM or the platform VM are ignored.
+func (g *Gateway) RegisterChain(ctx *snow.Context, vm interface{}) {
+	switch vm := vm.(type) {
+	case *avm.VM:
+		g.log.Debug("serving the AVM API of chain %s over gRPC", ctx.ChainID)
+		g.avm.add(ctx.ChainID, avm.NewGRPCServer(vm))
+	case *platformvm.VM:
+		g.log.Debug("serving the platform API over gRPC")
+		g.platform.set(platformvm.NewGRPCServer(vm))
+	}
+}
+
+// Dispatch serves gRPC requests on [port] until the gateway is stopped
+func (g *Gateway) Dispatch(port uint16) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	g.log.Info("gRPC API server listening on \":%d\"", port)
+	return g.server.Serve(listener)
+}
+
+// Stop the gRPC server after the requests it's serving finish
+func (g *Gateway) Stop() { g.server.GracefulStop() }
+
+// RESTHandler returns the handler of the REST endpoints, which expects to be
+// served under RESTPrefix. Server streaming methods aren't available over
+// REST.
+func (g *Gateway) RESTHandler() *common.HTTPHandler {
+	return &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     http.StripPrefix(RESTPrefix, g.mux),
+	}
+}
@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/api/health"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testAVMServer struct {
+	apiproto.UnimplementedAVMServer
+	balance uint64
+}
+
+func (s *testAVMServer) GetBalance(context.Context, *apiproto.GetBalanceRequest) (*apiproto.GetBalanceResponse, error) {
+	return &apiproto.GetBalanceResponse{Balance: s.balance}, nil
+}
+
+func TestRESTHandler(t *testing.T) {
+	chainID := ids.NewID([32]byte{1})
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(chainID, "X"); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliaser.Alias(ids.NewID([32]byte{2}), "C"); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := New(logging.NoLog{}, aliaser, nil, health.NewHealth(logging.NoLog{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.avm.add(chainID, &testAVMServer{balance: 42})
+	handler := g.RESTHandler().Handler
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"/v1/health", http.StatusOK, `"healthy":true`},
+		{"/v1/avm/X/addresses/addr/balances/AVA", http.StatusOK, `"balance":"42"`},
+		// C isn't an AVM chain
+		{"/v1/avm/C/addresses/addr/balances/AVA", http.StatusNotFound, "doesn't run the AVM"},
+		{"/v1/avm/Y/addresses/addr/balances/AVA", http.StatusNotFound, "unknown chain"},
+		{"/v1/platform/subnets", http.StatusServiceUnavailable, "hasn't been created"},
+		// The info API isn't served
+		{"/v1/info/node_id", http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, RESTPrefix+test.path, nil))
+		if w.Code != test.expectedCode {
+			t.Fatalf("%s: expected status %d but got %d: %s", test.path, test.expectedCode, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), test.expectedBody) {
+			t.Fatalf("%s: expected %q in %s", test.path, test.expectedBody, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+var (
+	errDatabaseMismatch = errors.New("database returned a different value than was written")
+
+	dbCheckKey   = []byte("health")
+	dbCheckValue = []byte("ok")
+)
+
+// PeersCheck returns a check that passes if [numPeers] returns at least
+// [minPeers]
+func PeersCheck(numPeers func() int, minPeers int) Check {
+	return func() (interface{}, error) {
+		peers := numPeers()
+		details := map[string]int{"peers": peers, "minPeers": minPeers}
+		if peers < minPeers {
+			return details, fmt.Errorf("connected to %d peers, at least %d are required", peers, minPeers)
+		}
+		return details, nil
+	}
+}
+
+// DatabaseCheck returns a check that passes if a value can be written to,
+// read from and deleted from [db]
+func DatabaseCheck(db database.Database) Check {
+	return func() (interface{}, error) {
+		if err := db.Put(dbCheckKey, dbCheckValue); err != nil {
+			return nil, err
+		}
+		value, err := db.Get(dbCheckKey)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(value, dbCheckValue) {
+			return nil, errDatabaseMismatch
+		}
+		return nil, db.Delete(dbCheckKey)
+	}
+}
+
+// ChainMonitor tracks the chains running on this node and when each last
+// accepted a container. It's registered with the chain manager to be told of
+// new chains and with the decision dispatcher to be told of accepted
+// containers.
+type ChainMonitor struct {
+	clock timer.Clock
+
+	lock sync.Mutex
+	// Maps each chain to when it last accepted a container, or was created if
+	// it hasn't accepted one
+	lastAccepted map[[32]byte]time.Time
+}
+
+// NewChainMonitor returns a monitor that isn't tracking any chains
+func NewChainMonitor() *ChainMonitor {
+	return &ChainMonitor{lastAccepted: make(map[[32]byte]time.Time)}
+}
+
+// RegisterChain starts tracking the chain of [ctx]
+func (m *ChainMonitor) RegisterChain(ctx *snow.Context, _ interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.lastAccepted[ctx.ChainID.Key()] = m.clock.Time()
+}
+
+// Accept records that the chain [chainID] accepted a container
+func (m *ChainMonitor) Accept(chainID, _ ids.ID, _ []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, exists := m.lastAccepted[chainID.Key()]; exists {
+		m.lastAccepted[chainID.Key()] = m.clock.Time()
+	}
+	return nil
+}
+
+// LastAcceptedCheck returns a check that reports how long ago each chain last
+// accepted a container. If [maxAge] is positive, the check fails if a chain
+// hasn't accepted a container for longer than [maxAge].
+func (m *ChainMonitor) LastAcceptedCheck(maxAge time.Duration) Check {
+	return func() (interface{}, error) {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		now := m.clock.Time()
+		ages := make(map[string]string, len(m.lastAccepted))
+		stale := []string(nil)
+		for chainKey, lastAccepted := range m.lastAccepted {
+			chainID := ids.NewID(chainKey).String()
+			age := now.Sub(lastAccepted)
+			ages[chainID] = age.String()
+			if maxAge > 0 && age > maxAge {
+				stale = append(stale, chainID)
+			}
+		}
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			return ages, fmt.Errorf("chains %v haven't accepted a container in over %s", stale, maxAge)
+		}
+		return ages, nil
+	}
+}
+
+// BootstrappedCheck returns a check that passes if [isBootstrapped] returns
+// true for every chain
+func (m *ChainMonitor) BootstrappedCheck(isBootstrapped func(ids.ID) bool) Check {
+	return func() (interface{}, error) {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		bootstrapping := []string(nil)
+		for chainKey := range m.lastAccepted {
+			chainID := ids.NewID(chainKey)
+			if !isBootstrapped(chainID) {
+				bootstrapping = append(bootstrapping, chainID.String())
+			}
+		}
+		if len(bootstrapping) > 0 {
+			sort.Strings(bootstrapping)
+			return bootstrapping, fmt.Errorf("%d chains haven't finished bootstrapping", len(bootstrapping))
+		}
+		return nil, nil
+	}
+}
@@ -0,0 +1,47 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+)
+
+// GRPCServer serves the health API over gRPC
+type GRPCServer struct {
+	apiproto.UnimplementedHealthServer
+
+	health *Health
+}
+
+// NewGRPCServer returns a gRPC server that reports the results of the checks
+// registered with [health]
+func NewGRPCServer(health *Health) *GRPCServer { return &GRPCServer{health: health} }
+
+// GetLiveness implements the apiproto.HealthServer interface
+// The details of each check are marshalled to JSON, as they can be any value.
+func (s *GRPCServer) GetLiveness(context.Context, *apiproto.GetLivenessRequest) (*apiproto.GetLivenessResponse, error) {
+	s.health.log.Debug("Health: GetLiveness called over gRPC")
+
+	results, healthy := s.health.Results()
+	checks := make(map[string]*apiproto.CheckResult, len(results))
+	for name, result := range results {
+		check := &apiproto.CheckResult{
+			Error:   result.Error,
+			Latency: result.Latency,
+			Healthy: result.Healthy,
+		}
+		if result.Details != nil {
+			details, err := json.Marshal(result.Details)
+			if err != nil {
+				return nil, err
+			}
+			check.Details = string(details)
+		}
+		checks[name] = check
+	}
+	return &apiproto.GetLivenessResponse{Checks: checks, Healthy: healthy}, nil
+}
@@ -0,0 +1,146 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// Check returns details about the health of a component, or an error if the
+// component is unhealthy
+type Check func() (interface{}, error)
+
+// Result is the outcome of running a check
+type Result struct {
+	// Details returned by the check
+	Details interface{} `json:"details,omitempty"`
+	// Why the component is unhealthy, if it is
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+	Healthy bool   `json:"healthy"`
+}
+
+// Health runs the checks registered with it to report whether this node is
+// healthy
+type Health struct {
+	log logging.Logger
+
+	lock   sync.RWMutex
+	checks map[string]Check
+}
+
+// NewHealth returns a new Health with no checks
+func NewHealth(log logging.Logger) *Health {
+	return &Health{
+		log:    log,
+		checks: make(map[string]Check),
+	}
+}
+
+// RegisterCheck adds the check [check], reported under [name]
+func (h *Health) RegisterCheck(name string, check Check) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, exists := h.checks[name]; exists {
+		return fmt.Errorf("a check named %s is already registered", name)
+	}
+	h.checks[name] = check
+	return nil
+}
+
+// Results runs every check and returns their results, keyed by name, and
+// whether every check passed
+func (h *Health) Results() (map[string]Result, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]Result, len(names))
+	healthy := true
+	for _, name := range names {
+		start := time.Now()
+		details, err := h.checks[name]()
+		result := Result{
+			Details: details,
+			Latency: time.Since(start).String(),
+			Healthy: err == nil,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			healthy = false
+			h.log.Debug("health check %s failed: %s", name, err)
+		}
+		results[name] = result
+	}
+	return results, healthy
+}
+
+// Handler returns the handler of the health API. GET requests, such as load
+// balancer probes, are answered with the results of the checks and status 200
+// if the node is healthy, or 503 if it isn't. Other requests are served by the
+// JSON-RPC service.
+func (h *Health) Handler() *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Service{health: h}, "health")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			newServer.ServeHTTP(w, r)
+			return
+		}
+
+		reply := GetLivenessReply{}
+		reply.Checks, reply.Healthy = h.Results()
+		w.Header().Set("Content-Type", "application/json")
+		if !reply.Healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(&reply); err != nil {
+			h.log.Debug("failed to write health response: %s", err)
+		}
+	})
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}
+}
+
+// Service is the API service for the health of this node
+type Service struct{ health *Health }
+
+// GetLivenessArgs are the arguments for calling GetLiveness
+type GetLivenessArgs struct{}
+
+// GetLivenessReply are the results from calling GetLiveness
+type GetLivenessReply struct {
+	Checks  map[string]Result `json:"checks"`
+	Healthy bool              `json:"healthy"`
+}
+
+// GetLiveness returns the result of each health check and whether this node
+// is healthy
+func (service *Service) GetLiveness(_ *http.Request, _ *GetLivenessArgs, reply *GetLivenessReply) error {
+	service.health.log.Debug("Health: GetLiveness called")
+
+	reply.Checks, reply.Healthy = service.health.Results()
+	return nil
+}
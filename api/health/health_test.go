@@ -0,0 +1,186 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var errUnhealthy = errors.New("unhealthy")
+
+func TestHealthResults(t *testing.T) {
+	h := NewHealth(logging.NoLog{})
+	passing := true
+	if err := h.RegisterCheck("passing", func() (interface{}, error) { return "fine", nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.RegisterCheck("toggled", func() (interface{}, error) {
+		if passing {
+			return nil, nil
+		}
+		return nil, errUnhealthy
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.RegisterCheck("passing", func() (interface{}, error) { return nil, nil }); err == nil {
+		t.Fatalf("Should have errored because the check is already registered")
+	}
+
+	results, healthy := h.Results()
+	if !healthy {
+		t.Fatalf("Should be healthy when every check passes")
+	}
+	if result := results["passing"]; !result.Healthy || result.Details != "fine" || result.Latency == "" {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	passing = false
+	results, healthy = h.Results()
+	if healthy {
+		t.Fatalf("Should be unhealthy when a check fails")
+	}
+	if result := results["toggled"]; result.Healthy || result.Error != errUnhealthy.Error() {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+	if !results["passing"].Healthy {
+		t.Fatalf("The passing check should still pass")
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	h := NewHealth(logging.NoLog{})
+	passing := true
+	h.RegisterCheck("toggled", func() (interface{}, error) {
+		if passing {
+			return nil, nil
+		}
+		return nil, errUnhealthy
+	})
+	handler := h.Handler().Handler
+
+	for _, expectedCode := range []int{http.StatusOK, http.StatusServiceUnavailable} {
+		passing = expectedCode == http.StatusOK
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/ext/health", nil))
+		if w.Code != expectedCode {
+			t.Fatalf("Expected status %d but got %d", expectedCode, w.Code)
+		}
+		reply := GetLivenessReply{}
+		if err := json.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+			t.Fatal(err)
+		}
+		if reply.Healthy != passing || reply.Checks["toggled"].Healthy != passing {
+			t.Fatalf("Unexpected reply %+v", reply)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/ext/health", strings.NewReader(`{"jsonrpc":"2.0","method":"health.getLiveness","params":[{}],"id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `"healthy":false`) {
+		t.Fatalf("Unexpected JSON-RPC response %s", w.Body.String())
+	}
+}
+
+func TestPeersCheck(t *testing.T) {
+	peers := 0
+	check := PeersCheck(func() int { return peers }, 2)
+	if _, err := check(); err == nil {
+		t.Fatalf("Should have failed with too few peers")
+	}
+	peers = 2
+	if _, err := check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabaseCheck(t *testing.T) {
+	db := memdb.New()
+	check := DatabaseCheck(db)
+	if _, err := check(); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has(dbCheckKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("The check should have deleted what it wrote")
+	}
+
+	db.Close()
+	if _, err := check(); err == nil {
+		t.Fatalf("Should have failed because the database is closed")
+	}
+}
+
+func TestChainMonitor(t *testing.T) {
+	m := NewChainMonitor()
+	start := time.Unix(1000, 0)
+	m.clock.Set(start)
+
+	chainA := ids.NewID([32]byte{1})
+	chainB := ids.NewID([32]byte{2})
+	m.RegisterChain(&snow.Context{ChainID: chainA}, nil)
+	m.RegisterChain(&snow.Context{ChainID: chainB}, nil)
+
+	lastAccepted := m.LastAcceptedCheck(time.Minute)
+	m.clock.Set(start.Add(2 * time.Minute))
+	if err := m.Accept(chainA, ids.Empty, nil); err != nil {
+		t.Fatal(err)
+	}
+	details, err := lastAccepted()
+	if err == nil || !strings.Contains(err.Error(), chainB.String()) || strings.Contains(err.Error(), chainA.String()) {
+		t.Fatalf("Only %s should be stale but the error was %v", chainB, err)
+	}
+	if ages := details.(map[string]string); ages[chainA.String()] != "0s" || ages[chainB.String()] != "2m0s" {
+		t.Fatalf("Unexpected ages %v", ages)
+	}
+	if _, err := m.LastAcceptedCheck(0)(); err != nil {
+		t.Fatalf("Ages shouldn't be limited without a maximum")
+	}
+
+	bootstrapped := ids.Set{}
+	bootstrappedCheck := m.BootstrappedCheck(bootstrapped.Contains)
+	bootstrapped.Add(chainA)
+	if _, err := bootstrappedCheck(); err == nil {
+		t.Fatalf("Should have failed because %s is bootstrapping", chainB)
+	}
+	bootstrapped.Add(chainB)
+	if _, err := bootstrappedCheck(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGRPCServer(t *testing.T) {
+	h := NewHealth(logging.NoLog{})
+	h.RegisterCheck("details", func() (interface{}, error) { return map[string]int{"peers": 3}, nil })
+	h.RegisterCheck("failing", func() (interface{}, error) { return nil, errUnhealthy })
+
+	resp, err := NewGRPCServer(h).GetLiveness(context.Background(), &apiproto.GetLivenessRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Healthy {
+		t.Fatalf("Should be unhealthy when a check fails")
+	}
+	if check := resp.Checks["details"]; !check.Healthy || check.Details != `{"peers":3}` {
+		t.Fatalf("Unexpected result %+v", check)
+	}
+	if check := resp.Checks["failing"]; check.Healthy || check.Details != "" || check.Error != errUnhealthy.Error() {
+		t.Fatalf("Unexpected result %+v", check)
+	}
+}
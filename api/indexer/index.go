@@ -0,0 +1,266 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Keys of an index's database are prefixed by the table they belong to
+const (
+	// index -> container
+	containerPrefix byte = iota
+	// container ID -> index
+	idPrefix
+	// accept time, index -> nothing
+	timePrefix
+	// holds the index the next accepted container is given
+	nextIndexPrefix
+)
+
+var (
+	errNoContainers = errors.New("no containers have been accepted")
+	errNoneAfter    = errors.New("no containers were accepted at or after the given time")
+)
+
+// Container is a block, vertex or transaction that was accepted
+type Container struct {
+	ID    ids.ID
+	Bytes []byte
+	// Index the container was given
+	Index uint64
+	// Unix time, in nanoseconds, this node accepted the container at
+	Timestamp int64
+}
+
+// index of the containers of one kind accepted on one chain. Each container is
+// given the next index when it's accepted, so the indices are the order this
+// node accepted the containers in. For a linear chain, that's the order of the
+// blocks' heights.
+type index struct {
+	clock *timer.Clock
+
+	lock sync.RWMutex
+	db   database.Database
+	// The index the next accepted container is given
+	nextIndex uint64
+}
+
+// newIndex returns the index stored in [db]
+func newIndex(db database.Database, clock *timer.Clock) (*index, error) {
+	i := &index{
+		clock: clock,
+		db:    db,
+	}
+	nextIndexBytes, err := db.Get([]byte{nextIndexPrefix})
+	switch {
+	case err == database.ErrNotFound:
+		return i, nil
+	case err != nil:
+		return nil, err
+	case len(nextIndexBytes) != wrappers.LongLen:
+		return nil, fmt.Errorf("next index has %d bytes, expected %d", len(nextIndexBytes), wrappers.LongLen)
+	}
+	i.nextIndex = binary.BigEndian.Uint64(nextIndexBytes)
+	return i, nil
+}
+
+// Accept gives the container [containerID] the next index. A container that
+// was already indexed keeps its index.
+func (i *index) Accept(_, containerID ids.ID, container []byte) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	idKey := append([]byte{idPrefix}, containerID.Bytes()...)
+	if indexed, err := i.db.Has(idKey); err != nil || indexed {
+		return err
+	}
+
+	timestamp := i.clock.Time().UnixNano()
+	p := wrappers.Packer{MaxSize: hashing.HashLen + wrappers.LongLen + wrappers.IntLen + len(container)}
+	p.PackFixedBytes(containerID.Bytes())
+	p.PackLong(uint64(timestamp))
+	p.PackBytes(container)
+	if p.Errored() {
+		return p.Err
+	}
+
+	batch := i.db.NewBatch()
+	errs := wrappers.Errs{}
+	errs.Add(
+		batch.Put(indexKey(containerPrefix, i.nextIndex), p.Bytes),
+		batch.Put(idKey, uint64Bytes(i.nextIndex)),
+		batch.Put(timeKey(timestamp, i.nextIndex), nil),
+		batch.Put([]byte{nextIndexPrefix}, uint64Bytes(i.nextIndex+1)),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	i.nextIndex++
+	return nil
+}
+
+// GetContainerByIndex returns the container given [index]
+func (i *index) GetContainerByIndex(index uint64) (Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.getContainerByIndex(index)
+}
+
+func (i *index) getContainerByIndex(index uint64) (Container, error) {
+	if index >= i.nextIndex {
+		return Container{}, fmt.Errorf("no container has index %d, %d containers have been accepted", index, i.nextIndex)
+	}
+	containerBytes, err := i.db.Get(indexKey(containerPrefix, index))
+	if err != nil {
+		return Container{}, err
+	}
+
+	p := wrappers.Packer{Bytes: containerBytes}
+	containerID, _ := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+	timestamp := p.UnpackLong()
+	container := p.UnpackBytes()
+	if p.Errored() {
+		return Container{}, p.Err
+	}
+	return Container{
+		ID:        containerID,
+		Bytes:     container,
+		Index:     index,
+		Timestamp: int64(timestamp),
+	}, nil
+}
+
+// GetContainerRange returns at most [numToFetch] containers, starting with the
+// container given [startIndex]
+func (i *index) GetContainerRange(startIndex, numToFetch uint64) ([]Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if startIndex >= i.nextIndex {
+		return nil, fmt.Errorf("no container has index %d, %d containers have been accepted", startIndex, i.nextIndex)
+	}
+	if left := i.nextIndex - startIndex; numToFetch > left {
+		numToFetch = left
+	}
+
+	containers := make([]Container, numToFetch)
+	for j := range containers {
+		container, err := i.getContainerByIndex(startIndex + uint64(j))
+		if err != nil {
+			return nil, err
+		}
+		containers[j] = container
+	}
+	return containers, nil
+}
+
+// GetIndex returns the index the container [containerID] was given
+func (i *index) GetIndex(containerID ids.ID) (uint64, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.getIndex(containerID)
+}
+
+func (i *index) getIndex(containerID ids.ID) (uint64, error) {
+	indexBytes, err := i.db.Get(append([]byte{idPrefix}, containerID.Bytes()...))
+	if err == database.ErrNotFound {
+		return 0, fmt.Errorf("container %s isn't indexed", containerID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: indexBytes}
+	index := p.UnpackLong()
+	return index, p.Err
+}
+
+// GetContainerByID returns the container [containerID]
+func (i *index) GetContainerByID(containerID ids.ID) (Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	index, err := i.getIndex(containerID)
+	if err != nil {
+		return Container{}, err
+	}
+	return i.getContainerByIndex(index)
+}
+
+// GetLastAccepted returns the container accepted most recently
+func (i *index) GetLastAccepted() (Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.nextIndex == 0 {
+		return Container{}, errNoContainers
+	}
+	return i.getContainerByIndex(i.nextIndex - 1)
+}
+
+// GetContainerByTime returns the first container accepted at or after
+// [timestamp], in Unix nanoseconds
+func (i *index) GetContainerByTime(timestamp int64) (Container, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if timestamp < 0 {
+		timestamp = 0
+	}
+	iter := i.db.NewIteratorWithStartAndPrefix(timeKey(timestamp, 0), []byte{timePrefix})
+	defer iter.Release()
+
+	if !iter.Next() {
+		if err := iter.Error(); err != nil {
+			return Container{}, err
+		}
+		return Container{}, errNoneAfter
+	}
+	p := wrappers.Packer{Bytes: iter.Key()}
+	p.UnpackByte() // prefix
+	p.UnpackLong() // time
+	index := p.UnpackLong()
+	if p.Errored() {
+		return Container{}, p.Err
+	}
+	return i.getContainerByIndex(index)
+}
+
+// indexKey returns the key of [index] in the table [prefix]
+func indexKey(prefix byte, index uint64) []byte {
+	key := make([]byte, 1+wrappers.LongLen)
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:], index)
+	return key
+}
+
+// timeKey returns the key recording that the container given [index] was
+// accepted at [timestamp]. Keys are sorted by time, then by index.
+func timeKey(timestamp int64, index uint64) []byte {
+	key := make([]byte, 1+2*wrappers.LongLen)
+	key[0] = timePrefix
+	binary.BigEndian.PutUint64(key[1:], uint64(timestamp))
+	binary.BigEndian.PutUint64(key[1+wrappers.LongLen:], index)
+	return key
+}
+
+func uint64Bytes(val uint64) []byte {
+	bytes := make([]byte, wrappers.LongLen)
+	binary.BigEndian.PutUint64(bytes, val)
+	return bytes
+}
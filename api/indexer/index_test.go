@@ -0,0 +1,111 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+func TestIndex(t *testing.T) {
+	db := prefixdb.New([]byte("index"), memdb.New())
+	clock := timer.Clock{}
+	idx, err := newIndex(db, &clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.GetLastAccepted(); err != errNoContainers {
+		t.Fatalf("Should have errored with %v but got %v", errNoContainers, err)
+	}
+
+	chainID := ids.NewID([32]byte{1})
+	containerIDs := []ids.ID{
+		ids.NewID([32]byte{2}),
+		ids.NewID([32]byte{3}),
+		ids.NewID([32]byte{4}),
+	}
+	start := time.Unix(1000, 0)
+	for i, containerID := range containerIDs {
+		clock.Set(start.Add(time.Duration(i) * time.Second))
+		if err := idx.Accept(chainID, containerID, []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Accepting a container again doesn't change its index
+	if err := idx.Accept(chainID, containerIDs[0], []byte{0}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, containerID := range containerIDs {
+		container, err := idx.GetContainerByIndex(uint64(i))
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case !container.ID.Equals(containerID):
+			t.Fatalf("Container %d should be %s but is %s", i, containerID, container.ID)
+		case !bytes.Equal(container.Bytes, []byte{byte(i)}):
+			t.Fatalf("Container %d has the wrong bytes", i)
+		case container.Index != uint64(i):
+			t.Fatalf("Container %d has index %d", i, container.Index)
+		case container.Timestamp != start.Add(time.Duration(i)*time.Second).UnixNano():
+			t.Fatalf("Container %d has the wrong timestamp", i)
+		}
+
+		if container, err := idx.GetContainerByID(containerID); err != nil {
+			t.Fatal(err)
+		} else if container.Index != uint64(i) {
+			t.Fatalf("Container %s should have index %d but has %d", containerID, i, container.Index)
+		}
+	}
+	if _, err := idx.GetContainerByIndex(uint64(len(containerIDs))); err == nil {
+		t.Fatalf("Should have errored because no container has that index")
+	}
+	if _, err := idx.GetIndex(ids.NewID([32]byte{5})); err == nil {
+		t.Fatalf("Should have errored because the container isn't indexed")
+	}
+
+	if container, err := idx.GetLastAccepted(); err != nil {
+		t.Fatal(err)
+	} else if !container.ID.Equals(containerIDs[2]) {
+		t.Fatalf("Last accepted should be %s but is %s", containerIDs[2], container.ID)
+	}
+
+	if container, err := idx.GetContainerByTime(start.Add(500 * time.Millisecond).UnixNano()); err != nil {
+		t.Fatal(err)
+	} else if container.Index != 1 {
+		t.Fatalf("Should have returned the container accepted after the time, but returned %d", container.Index)
+	}
+	if container, err := idx.GetContainerByTime(0); err != nil {
+		t.Fatal(err)
+	} else if container.Index != 0 {
+		t.Fatalf("Should have returned the first container, but returned %d", container.Index)
+	}
+	if _, err := idx.GetContainerByTime(start.Add(time.Hour).UnixNano()); err != errNoneAfter {
+		t.Fatalf("Should have errored with %v but got %v", errNoneAfter, err)
+	}
+
+	if containers, err := idx.GetContainerRange(1, 10); err != nil {
+		t.Fatal(err)
+	} else if len(containers) != 2 || containers[0].Index != 1 || containers[1].Index != 2 {
+		t.Fatalf("Should have returned the last 2 containers")
+	}
+	if _, err := idx.GetContainerRange(3, 1); err == nil {
+		t.Fatalf("Should have errored because no container has the start index")
+	}
+
+	// The index is loaded from the database
+	idx, err = newIndex(db, &clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx.nextIndex != uint64(len(containerIDs)) {
+		t.Fatalf("Reloaded index should give the next container index %d but gives %d", len(containerIDs), idx.nextIndex)
+	}
+}
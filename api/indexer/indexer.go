@@ -0,0 +1,153 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Kinds of containers that are indexed. The index of a kind is served at
+// /ext/index/{chain}/{kind}.
+const (
+	blockKind  = "block"
+	vertexKind = "vtx"
+	txKind     = "tx"
+
+	// Identifier the indexes are registered with on the dispatchers
+	handlerName = "indexer"
+
+	// Names the database of a linear chain's height index
+	heightsName = "height"
+)
+
+// APIServer is the subset of the API server the indexer adds routes to
+type APIServer interface {
+	AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, log logging.Logger) error
+	AddAliases(endpoint string, aliases ...string) error
+}
+
+// Indexer indexes the containers accepted on each chain this node runs, and
+// serves the indexes over the API. It's registered with the chain manager to be
+// told of new chains. Linear chains have an index of their accepted blocks. DAG
+// chains have an index of their accepted vertices and one of their accepted
+// transactions.
+//
+// The indexes are kept in their own database namespace, so they persist across
+// restarts.
+type Indexer struct {
+	log          logging.Logger
+	db           database.Database
+	chainManager chains.Manager
+	server       APIServer
+	decisions    *triggers.EventDispatcher
+	consensus    *triggers.EventDispatcher
+	clock        timer.Clock
+
+	lock    sync.Mutex
+	indexes map[[32]byte]map[string]*index
+}
+
+// NewIndexer returns an indexer that keeps its indexes in [db], learns of
+// accepted transactions and blocks from [decisions], and of accepted vertices
+// from [consensus]
+func NewIndexer(
+	log logging.Logger,
+	db database.Database,
+	chainManager chains.Manager,
+	server APIServer,
+	decisions *triggers.EventDispatcher,
+	consensus *triggers.EventDispatcher,
+) *Indexer {
+	return &Indexer{
+		log:          log,
+		db:           db,
+		chainManager: chainManager,
+		server:       server,
+		decisions:    decisions,
+		consensus:    consensus,
+		indexes:      make(map[[32]byte]map[string]*index),
+	}
+}
+
+// RegisterChain starts indexing the chain of [ctx]
+func (i *Indexer) RegisterChain(ctx *snow.Context, vm interface{}) {
+	switch vm := vm.(type) {
+	case snowman.ChainVM:
+		// The chain's blocks are also looked up by height, in the VM's height
+		// index or, if it doesn't have one, in one kept by the indexer
+		heights := snowman.HeightIndexOf(vm, prefixdb.New(append(ctx.ChainID.Bytes(), heightsName...), i.db))
+		i.registerIndex(ctx.ChainID, blockKind, i.decisions, func(height uint64) (ids.ID, error) {
+			ctx.Lock.Lock()
+			defer ctx.Lock.Unlock()
+
+			return heights.GetBlockIDAtHeight(height)
+		})
+	case avalanche.DAGVM:
+		i.registerIndex(ctx.ChainID, vertexKind, i.consensus, nil)
+		i.registerIndex(ctx.ChainID, txKind, i.decisions, nil)
+	default:
+		i.log.Warn("not indexing chain %s as its VM is of unknown type %T", ctx.ChainID, vm)
+		return
+	}
+
+	endpoint := "index/" + ctx.ChainID.String()
+	aliases := []string(nil)
+	for _, alias := range i.chainManager.Aliases(ctx.ChainID) {
+		aliases = append(aliases, "index/"+alias)
+	}
+	if err := i.server.AddAliases(endpoint, aliases...); err != nil {
+		i.log.Warn("couldn't alias the index of chain %s: %s", ctx.ChainID, err)
+	}
+}
+
+// registerIndex starts indexing the containers of [kind] accepted on the chain
+// [chainID], as dispatched by [dispatcher]. [heights] looks up the IDs of the
+// containers by height, and is nil if they don't have heights.
+func (i *Indexer) registerIndex(chainID ids.ID, kind string, dispatcher *triggers.EventDispatcher, heights heightLookup) {
+	db := prefixdb.New(append(chainID.Bytes(), kind...), i.db)
+	idx, err := newIndex(db, &i.clock)
+	if err != nil {
+		i.log.Error("couldn't load the %s index of chain %s: %s", kind, chainID, err)
+		return
+	}
+	if err := dispatcher.RegisterChain(chainID, handlerName+"-"+kind, idx); err != nil {
+		i.log.Error("couldn't index the %ss of chain %s: %s", kind, chainID, err)
+		return
+	}
+
+	i.lock.Lock()
+	chainIndexes, exists := i.indexes[chainID.Key()]
+	if !exists {
+		chainIndexes = make(map[string]*index)
+		i.indexes[chainID.Key()] = chainIndexes
+	}
+	chainIndexes[kind] = idx
+	i.lock.Unlock()
+
+	handler := newService(idx, heights)
+	if err := i.server.AddRoute(handler, &sync.RWMutex{}, "index/"+chainID.String(), "/"+kind, i.log); err != nil {
+		i.log.Error("couldn't serve the %s index of chain %s: %s", kind, chainID, err)
+	}
+}
+
+// getIndex returns the index of [kind] of the chain [chainID], if it's indexed
+func (i *Indexer) getIndex(chainID ids.ID, kind string) (*index, bool) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	idx, exists := i.indexes[chainID.Key()][kind]
+	return idx, exists
+}
@@ -0,0 +1,100 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testManager struct {
+	chains.Manager
+	aliases []string
+}
+
+func (m *testManager) Aliases(ids.ID) []string { return m.aliases }
+
+type testServer struct {
+	routes  []string
+	aliases map[string][]string
+}
+
+func (s *testServer) AddRoute(_ *common.HTTPHandler, _ *sync.RWMutex, base, endpoint string, _ logging.Logger) error {
+	s.routes = append(s.routes, base+endpoint)
+	return nil
+}
+
+func (s *testServer) AddAliases(endpoint string, aliases ...string) error {
+	s.aliases[endpoint] = append(s.aliases[endpoint], aliases...)
+	return nil
+}
+
+func TestIndexerRegisterChain(t *testing.T) {
+	linearCtx := snow.DefaultContextTest()
+	linearCtx.ChainID = ids.NewID([32]byte{1})
+	dagCtx := snow.DefaultContextTest()
+	dagCtx.ChainID = ids.NewID([32]byte{2})
+	dagCtx.DecisionDispatcher = linearCtx.DecisionDispatcher
+	dagCtx.ConsensusDispatcher = linearCtx.ConsensusDispatcher
+
+	server := &testServer{aliases: make(map[string][]string)}
+	indexer := NewIndexer(
+		logging.NoLog{},
+		memdb.New(),
+		&testManager{aliases: []string{"X"}},
+		server,
+		linearCtx.DecisionDispatcher,
+		linearCtx.ConsensusDispatcher,
+	)
+	indexer.RegisterChain(linearCtx, &snowman.VMTest{})
+	indexer.RegisterChain(dagCtx, &avalanche.VMTest{})
+
+	if len(server.routes) != 3 {
+		t.Fatalf("Should have served 3 indexes but served %v", server.routes)
+	}
+	if aliases := server.aliases["index/"+dagCtx.ChainID.String()]; len(aliases) != 1 || aliases[0] != "index/X" {
+		t.Fatalf("Should have aliased the chain's indexes but aliased %v", aliases)
+	}
+
+	blkID := ids.NewID([32]byte{3})
+	linearCtx.DecisionDispatcher.Accept(linearCtx.ChainID, blkID, []byte{3})
+	linearCtx.ConsensusDispatcher.Accept(linearCtx.ChainID, blkID, []byte{3})
+	vtxID := ids.NewID([32]byte{4})
+	dagCtx.ConsensusDispatcher.Accept(dagCtx.ChainID, vtxID, []byte{4})
+	txID := ids.NewID([32]byte{5})
+	dagCtx.DecisionDispatcher.Accept(dagCtx.ChainID, txID, []byte{5})
+
+	tests := []struct {
+		chainID     ids.ID
+		kind        string
+		containerID ids.ID
+	}{
+		{chainID: linearCtx.ChainID, kind: blockKind, containerID: blkID},
+		{chainID: dagCtx.ChainID, kind: vertexKind, containerID: vtxID},
+		{chainID: dagCtx.ChainID, kind: txKind, containerID: txID},
+	}
+	for _, test := range tests {
+		idx, exists := indexer.getIndex(test.chainID, test.kind)
+		if !exists {
+			t.Fatalf("Chain %s should have a %s index", test.chainID, test.kind)
+		}
+		if idx.nextIndex != 1 {
+			t.Fatalf("The %s index of chain %s should hold 1 container but holds %d", test.kind, test.chainID, idx.nextIndex)
+		}
+		if container, err := idx.GetLastAccepted(); err != nil {
+			t.Fatal(err)
+		} else if !container.ID.Equals(test.containerID) {
+			t.Fatalf("The %s index of chain %s should hold %s but holds %s", test.kind, test.chainID, test.containerID, container.ID)
+		}
+	}
+}
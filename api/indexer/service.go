@@ -0,0 +1,194 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+// MaxFetchedByRange is the maximum number of containers returned by a call to
+// GetContainerRange
+const MaxFetchedByRange = 1024
+
+var (
+	errNumToFetchZero = errors.New("numToFetch must be positive")
+	errNoHeights      = errors.New("only the blocks of a linear chain have heights")
+)
+
+// heightLookup returns the ID of the accepted container with height [height]
+type heightLookup func(height uint64) (ids.ID, error)
+
+// Service is the API service for an index
+type Service struct {
+	index   *index
+	heights heightLookup // Nil if the indexed containers don't have heights
+}
+
+// newService returns the handler that serves [index]. [heights] looks up the
+// indexed containers by height, and is nil if they don't have heights.
+func newService(index *index, heights heightLookup) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Service{index: index, heights: heights}, "index")
+	// The index has its own lock, so calls needn't hold the chain's lock
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer}
+}
+
+// FormattedContainer is a container as returned by the API
+type FormattedContainer struct {
+	ID    ids.ID          `json:"id"`
+	Bytes formatting.CB58 `json:"bytes"`
+	Index json.Uint64     `json:"index"`
+	// Unix time, in seconds, this node accepted the container at
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+func newFormattedContainer(container Container) FormattedContainer {
+	return FormattedContainer{
+		ID:        container.ID,
+		Bytes:     formatting.CB58{Bytes: container.Bytes},
+		Index:     json.Uint64(container.Index),
+		Timestamp: json.Uint64(time.Unix(0, container.Timestamp).Unix()),
+	}
+}
+
+// GetContainerByIndexArgs are the arguments for calling GetContainerByIndex
+type GetContainerByIndexArgs struct {
+	Index json.Uint64 `json:"index"`
+}
+
+// GetContainerByIndex returns the container given [args.Index]
+func (service *Service) GetContainerByIndex(_ *http.Request, args *GetContainerByIndexArgs, reply *FormattedContainer) error {
+	container, err := service.index.GetContainerByIndex(uint64(args.Index))
+	if err != nil {
+		return err
+	}
+	*reply = newFormattedContainer(container)
+	return nil
+}
+
+// GetContainerByIDArgs are the arguments for calling GetContainerByID
+type GetContainerByIDArgs struct {
+	ContainerID ids.ID `json:"containerID"`
+}
+
+// GetContainerByID returns the container [args.ContainerID]
+func (service *Service) GetContainerByID(_ *http.Request, args *GetContainerByIDArgs, reply *FormattedContainer) error {
+	container, err := service.index.GetContainerByID(args.ContainerID)
+	if err != nil {
+		return err
+	}
+	*reply = newFormattedContainer(container)
+	return nil
+}
+
+// GetIndexReply is the result from calling GetIndex
+type GetIndexReply struct {
+	Index json.Uint64 `json:"index"`
+}
+
+// GetIndex returns the index the container [args.ContainerID] was given
+func (service *Service) GetIndex(_ *http.Request, args *GetContainerByIDArgs, reply *GetIndexReply) error {
+	index, err := service.index.GetIndex(args.ContainerID)
+	reply.Index = json.Uint64(index)
+	return err
+}
+
+// GetLastAccepted returns the container accepted most recently
+func (service *Service) GetLastAccepted(_ *http.Request, _ *struct{}, reply *FormattedContainer) error {
+	container, err := service.index.GetLastAccepted()
+	if err != nil {
+		return err
+	}
+	*reply = newFormattedContainer(container)
+	return nil
+}
+
+// GetContainerByTimeArgs are the arguments for calling GetContainerByTime
+type GetContainerByTimeArgs struct {
+	// Unix time, in seconds
+	Time json.Uint64 `json:"time"`
+}
+
+// GetContainerByTime returns the first container accepted at or after
+// [args.Time]
+func (service *Service) GetContainerByTime(_ *http.Request, args *GetContainerByTimeArgs, reply *FormattedContainer) error {
+	container, err := service.index.GetContainerByTime(time.Unix(int64(args.Time), 0).UnixNano())
+	if err != nil {
+		return err
+	}
+	*reply = newFormattedContainer(container)
+	return nil
+}
+
+// GetContainerByHeightArgs are the arguments for calling GetContainerByHeight
+type GetContainerByHeightArgs struct {
+	// Height of the block. The genesis block has height 0.
+	Height json.Uint64 `json:"height"`
+}
+
+// GetContainerByHeight returns the accepted block with height [args.Height].
+// Only the blocks of a linear chain have heights, and the block must have been
+// indexed.
+func (service *Service) GetContainerByHeight(_ *http.Request, args *GetContainerByHeightArgs, reply *FormattedContainer) error {
+	if service.heights == nil {
+		return errNoHeights
+	}
+	containerID, err := service.heights(uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("couldn't get the block at height %d: %w", args.Height, err)
+	}
+	container, err := service.index.GetContainerByID(containerID)
+	if err != nil {
+		return err
+	}
+	*reply = newFormattedContainer(container)
+	return nil
+}
+
+// GetContainerRangeArgs are the arguments for calling GetContainerRange
+type GetContainerRangeArgs struct {
+	StartIndex json.Uint64 `json:"startIndex"`
+	NumToFetch json.Uint64 `json:"numToFetch"`
+}
+
+// GetContainerRangeReply is the result from calling GetContainerRange
+type GetContainerRangeReply struct {
+	Containers []FormattedContainer `json:"containers"`
+}
+
+// GetContainerRange returns the containers given indices starting at
+// [args.StartIndex], in order. At most [args.NumToFetch] containers, and at most
+// MaxFetchedByRange, are returned. To page through an index, call again with
+// the index after the last returned container.
+func (service *Service) GetContainerRange(_ *http.Request, args *GetContainerRangeArgs, reply *GetContainerRangeReply) error {
+	switch {
+	case args.NumToFetch == 0:
+		return errNumToFetchZero
+	case args.NumToFetch > MaxFetchedByRange:
+		return fmt.Errorf("numToFetch must be at most %d", MaxFetchedByRange)
+	}
+
+	containers, err := service.index.GetContainerRange(uint64(args.StartIndex), uint64(args.NumToFetch))
+	if err != nil {
+		return err
+	}
+	reply.Containers = make([]FormattedContainer, len(containers))
+	for i, container := range containers {
+		reply.Containers[i] = newFormattedContainer(container)
+	}
+	return nil
+}
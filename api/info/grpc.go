@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"context"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+)
+
+// GRPCServer serves the info API over gRPC
+type GRPCServer struct {
+	apiproto.UnimplementedInfoServer
+
+	service *Info
+}
+
+// NewGRPCServer returns a gRPC server that answers requests using [service]
+func NewGRPCServer(service *Info) *GRPCServer { return &GRPCServer{service: service} }
+
+// GetNodeVersion implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetNodeVersion(context.Context, *apiproto.GetNodeVersionRequest) (*apiproto.GetNodeVersionResponse, error) {
+	reply := GetNodeVersionReply{}
+	if err := s.service.GetNodeVersion(nil, &GetNodeVersionArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetNodeVersionResponse{Version: reply.Version}, nil
+}
+
+// GetNodeID implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetNodeID(context.Context, *apiproto.GetNodeIDRequest) (*apiproto.GetNodeIDResponse, error) {
+	reply := GetNodeIDReply{}
+	if err := s.service.GetNodeID(nil, &GetNodeIDArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetNodeIDResponse{NodeId: reply.NodeID.String()}, nil
+}
+
+// GetNetworkID implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetNetworkID(context.Context, *apiproto.GetNetworkIDRequest) (*apiproto.GetNetworkIDResponse, error) {
+	reply := GetNetworkIDReply{}
+	if err := s.service.GetNetworkID(nil, &GetNetworkIDArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetNetworkIDResponse{NetworkId: uint32(reply.NetworkID)}, nil
+}
+
+// GetNetworkName implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetNetworkName(context.Context, *apiproto.GetNetworkNameRequest) (*apiproto.GetNetworkNameResponse, error) {
+	reply := GetNetworkNameReply{}
+	if err := s.service.GetNetworkName(nil, &GetNetworkNameArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetNetworkNameResponse{NetworkName: reply.NetworkName}, nil
+}
+
+// GetBlockchainID implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetBlockchainID(_ context.Context, req *apiproto.GetBlockchainIDRequest) (*apiproto.GetBlockchainIDResponse, error) {
+	reply := GetBlockchainIDReply{}
+	if err := s.service.GetBlockchainID(nil, &GetBlockchainIDArgs{Alias: req.Alias}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetBlockchainIDResponse{BlockchainId: reply.BlockchainID}, nil
+}
+
+// IsBootstrapped implements the apiproto.InfoServer interface
+func (s *GRPCServer) IsBootstrapped(_ context.Context, req *apiproto.IsBootstrappedRequest) (*apiproto.IsBootstrappedResponse, error) {
+	reply := IsBootstrappedReply{}
+	if err := s.service.IsBootstrapped(nil, &IsBootstrappedArgs{Chain: req.Chain}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.IsBootstrappedResponse{IsBootstrapped: reply.IsBootstrapped}, nil
+}
+
+// Peers implements the apiproto.InfoServer interface
+func (s *GRPCServer) Peers(context.Context, *apiproto.PeersRequest) (*apiproto.PeersResponse, error) {
+	reply := PeersReply{}
+	if err := s.service.Peers(nil, &PeersArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.PeersResponse{Peers: reply.Peers}, nil
+}
+
+// GetUptime implements the apiproto.InfoServer interface
+func (s *GRPCServer) GetUptime(context.Context, *apiproto.GetUptimeRequest) (*apiproto.GetUptimeResponse, error) {
+	reply := GetUptimeReply{}
+	if err := s.service.GetUptime(nil, &GetUptimeArgs{}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetUptimeResponse{Uptime: uint64(reply.Uptime)}, nil
+}
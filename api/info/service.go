@@ -0,0 +1,315 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/vms"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// Peerable can return a group of peers
+type Peerable interface{ Peers() []utils.IPDesc }
+
+// Versioned can return the versions peers run
+type Versioned interface {
+	// PeerVersions returns the version each connected peer runs, by the
+	// peer's IP
+	PeerVersions() map[string]string
+	// VersionDistribution returns the number of connected peers running each
+	// version
+	VersionDistribution() map[string]int
+}
+
+// Info is the API service for unprivileged info on a node
+type Info struct {
+	version      string
+	nodeID       ids.ShortID
+	networkID    uint32
+	log          logging.Logger
+	chainManager chains.Manager
+	vmManager    vms.Manager
+	peers        Peerable
+	versions     Versioned
+	uptimes      snow.Uptimes
+
+	clock timer.Clock
+	// Unix time this service was created at
+	startTime uint64
+}
+
+// New returns a new info API service
+func New(log logging.Logger, version string, nodeID ids.ShortID, networkID uint32, chainManager chains.Manager, vmManager vms.Manager, peers Peerable, versions Versioned, uptimes snow.Uptimes) *Info {
+	info := &Info{
+		version:      version,
+		nodeID:       nodeID,
+		networkID:    networkID,
+		log:          log,
+		chainManager: chainManager,
+		vmManager:    vmManager,
+		peers:        peers,
+		versions:     versions,
+		uptimes:      uptimes,
+	}
+	info.startTime = info.clock.Unix()
+	return info
+}
+
+// Handler returns the handler of the info API
+func (service *Info) Handler() *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := cjson.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(service, "info")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// GetNodeVersionArgs are the arguments for calling GetNodeVersion
+type GetNodeVersionArgs struct{}
+
+// GetNodeVersionReply are the results from calling GetNodeVersion
+type GetNodeVersionReply struct {
+	Version string `json:"version"`
+}
+
+// GetNodeVersion returns the version this node is running
+func (service *Info) GetNodeVersion(_ *http.Request, _ *GetNodeVersionArgs, reply *GetNodeVersionReply) error {
+	service.log.Debug("Info: GetNodeVersion called")
+
+	reply.Version = service.version
+	return nil
+}
+
+// GetNodeIDArgs are the arguments for calling GetNodeID
+type GetNodeIDArgs struct{}
+
+// GetNodeIDReply are the results from calling GetNodeID
+type GetNodeIDReply struct {
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// GetNodeID returns the node ID of this node
+func (service *Info) GetNodeID(_ *http.Request, _ *GetNodeIDArgs, reply *GetNodeIDReply) error {
+	service.log.Debug("Info: GetNodeID called")
+
+	reply.NodeID = service.nodeID
+	return nil
+}
+
+// GetNetworkIDArgs are the arguments for calling GetNetworkID
+type GetNetworkIDArgs struct{}
+
+// GetNetworkIDReply are the results from calling GetNetworkID
+type GetNetworkIDReply struct {
+	NetworkID cjson.Uint32 `json:"networkID"`
+}
+
+// GetNetworkID returns the network ID this node is running on
+func (service *Info) GetNetworkID(_ *http.Request, _ *GetNetworkIDArgs, reply *GetNetworkIDReply) error {
+	service.log.Debug("Info: GetNetworkID called")
+
+	reply.NetworkID = cjson.Uint32(service.networkID)
+	return nil
+}
+
+// GetNetworkNameArgs are the arguments for calling GetNetworkName
+type GetNetworkNameArgs struct{}
+
+// GetNetworkNameReply is the result from calling GetNetworkName
+type GetNetworkNameReply struct {
+	NetworkName string `json:"networkName"`
+}
+
+// GetNetworkName returns the name of the network this node is running on
+func (service *Info) GetNetworkName(_ *http.Request, _ *GetNetworkNameArgs, reply *GetNetworkNameReply) error {
+	service.log.Debug("Info: GetNetworkName called")
+
+	reply.NetworkName = genesis.NetworkName(service.networkID)
+	return nil
+}
+
+// GetBlockchainIDArgs are the arguments for calling GetBlockchainID
+type GetBlockchainIDArgs struct {
+	Alias string `json:"alias"`
+}
+
+// GetBlockchainIDReply are the results from calling GetBlockchainID
+type GetBlockchainIDReply struct {
+	BlockchainID string `json:"blockchainID"`
+}
+
+// GetBlockchainID returns the blockchain ID that resolves the alias that was supplied
+func (service *Info) GetBlockchainID(_ *http.Request, args *GetBlockchainIDArgs, reply *GetBlockchainIDReply) error {
+	service.log.Debug("Info: GetBlockchainID called")
+
+	bID, err := service.chainManager.Lookup(args.Alias)
+	reply.BlockchainID = bID.String()
+	return err
+}
+
+// IsBootstrappedArgs are the arguments for calling IsBootstrapped
+type IsBootstrappedArgs struct {
+	// Alias of the chain
+	// Can also be the string representation of the chain's ID
+	Chain string `json:"chain"`
+}
+
+// IsBootstrappedReply are the results from calling IsBootstrapped
+type IsBootstrappedReply struct {
+	IsBootstrapped bool `json:"isBootstrapped"`
+}
+
+// IsBootstrapped returns whether the chain [args.Chain] has finished
+// bootstrapping
+func (service *Info) IsBootstrapped(_ *http.Request, args *IsBootstrappedArgs, reply *IsBootstrappedReply) error {
+	service.log.Debug("Info: IsBootstrapped called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.IsBootstrapped = service.chainManager.IsBootstrapped(chainID)
+	return nil
+}
+
+// PeersArgs are the arguments for calling Peers
+type PeersArgs struct{}
+
+// PeersReply are the results from calling Peers
+type PeersReply struct {
+	NumPeers cjson.Uint32 `json:"numPeers"`
+	Peers    []string     `json:"peers"`
+	// IP of a peer -> version the peer runs
+	PeerVersions map[string]string `json:"peerVersions"`
+	// Version -> number of peers running it
+	Versions map[string]cjson.Uint32 `json:"versions"`
+}
+
+// Peers returns the number of peers this node is connected to, their IPs and
+// the versions they run
+func (service *Info) Peers(_ *http.Request, _ *PeersArgs, reply *PeersReply) error {
+	service.log.Debug("Info: Peers called")
+
+	ipDescs := service.peers.Peers()
+	reply.Peers = make([]string, len(ipDescs))
+	for i, ipDesc := range ipDescs {
+		reply.Peers[i] = ipDesc.String()
+	}
+	sort.Strings(reply.Peers)
+	reply.NumPeers = cjson.Uint32(len(reply.Peers))
+
+	reply.PeerVersions = service.versions.PeerVersions()
+	reply.Versions = make(map[string]cjson.Uint32)
+	for v, count := range service.versions.VersionDistribution() {
+		reply.Versions[v] = cjson.Uint32(count)
+	}
+	return nil
+}
+
+// GetUptimeArgs are the arguments for calling GetUptime
+type GetUptimeArgs struct{}
+
+// GetUptimeReply are the results from calling GetUptime
+type GetUptimeReply struct {
+	// Seconds since this node started
+	Uptime cjson.Uint64 `json:"uptime"`
+}
+
+// GetUptime returns how long this node has been running
+func (service *Info) GetUptime(_ *http.Request, _ *GetUptimeArgs, reply *GetUptimeReply) error {
+	service.log.Debug("Info: GetUptime called")
+
+	reply.Uptime = cjson.Uint64(service.clock.Unix() - service.startTime)
+	return nil
+}
+
+// UptimeArgs are the arguments for calling Uptime
+type UptimeArgs struct {
+	NodeID ids.ShortID `json:"nodeID"`
+	// Unix time to measure the uptime from. If 0, the uptime is measured over
+	// all the time this node has kept.
+	StartTime cjson.Uint64 `json:"startTime"`
+}
+
+// UptimeReply are the results from calling Uptime
+type UptimeReply struct {
+	// Fraction of the time this node was running that the node was connected
+	// to it, between 0 and 1
+	Uptime float64 `json:"uptime"`
+}
+
+// Uptime returns the uptime of a node, as observed by this node
+func (service *Info) Uptime(_ *http.Request, args *UptimeArgs, reply *UptimeReply) error {
+	service.log.Debug("Info: Uptime called with %s", args.NodeID)
+
+	uptime, err := service.uptimes.Uptime(args.NodeID, time.Unix(int64(args.StartTime), 0))
+	if err != nil {
+		return fmt.Errorf("couldn't get the uptime of %s: %w", args.NodeID, err)
+	}
+	reply.Uptime = uptime
+	return nil
+}
+
+// GetVMsArgs are the arguments for calling GetVMs
+type GetVMsArgs struct{}
+
+// APIVM is the representation of a VM used in API calls
+type APIVM struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases"`
+	// Version of the VM. Empty if the VM doesn't report one.
+	Version string `json:"version"`
+	// Version of the interface between the node and the VM that the VM
+	// implements
+	InterfaceVersion cjson.Uint32 `json:"interfaceVersion"`
+	// True if this node can run the VM. Otherwise, [Error] is why it can't.
+	Compatible bool   `json:"compatible"`
+	Error      string `json:"error,omitempty"`
+}
+
+// GetVMsReply are the results from calling GetVMs
+type GetVMsReply struct {
+	// Version of the interface between the node and VMs that this node
+	// supports
+	InterfaceVersion cjson.Uint32 `json:"interfaceVersion"`
+	VMs              []APIVM      `json:"vms"`
+}
+
+// GetVMs returns the VMs registered on this node and their versions
+func (service *Info) GetVMs(_ *http.Request, _ *GetVMsArgs, reply *GetVMsReply) error {
+	service.log.Debug("Info: GetVMs called")
+
+	reply.InterfaceVersion = cjson.Uint32(vms.InterfaceVersion)
+	vmInfos := service.vmManager.VMs()
+	reply.VMs = make([]APIVM, len(vmInfos))
+	for i, vmInfo := range vmInfos {
+		reply.VMs[i] = APIVM{
+			ID:               vmInfo.ID.String(),
+			Aliases:          vmInfo.Aliases,
+			Version:          vmInfo.VM,
+			InterfaceVersion: cjson.Uint32(vmInfo.Interface),
+			Compatible:       vmInfo.Err == nil,
+		}
+		if vmInfo.Err != nil {
+			reply.VMs[i].Error = vmInfo.Err.Error()
+		}
+	}
+	return nil
+}
@@ -4,8 +4,6 @@
 package ipcs
 
 import (
-	"nanomsg.org/go/mangos/v2"
-
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
@@ -14,7 +12,7 @@ import (
 // ChainIPC a struct which holds IPC socket information
 type ChainIPC struct {
 	log    logging.Logger
-	socket mangos.Socket
+	socket *Socket
 }
 
 // Accept delivers a message to the ChainIPC
@@ -4,16 +4,14 @@
 package ipcs
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-
-	"nanomsg.org/go/mangos/v2/protocol/pub"
-
-	_ "nanomsg.org/go/mangos/v2/transport/ipc" // registers the IPC transport
+	"path/filepath"
+	"sync"
 
 	"github.com/gorilla/rpc/v2"
 
-	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/triggers"
@@ -22,120 +20,159 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
-const baseURL = "ipc:///tmp/"
+var (
+	errIPCsDisabled = errors.New("IPCs are disabled on this node")
+)
 
-// IPCs maintains the IPCs
+// IPCs maintains the IPCs. Each IPC publishes the containers accepted on a
+// chain to a Unix domain socket named after the chain.
 type IPCs struct {
 	log          logging.Logger
 	chainManager chains.Manager
-	httpServer   *api.Server
 	events       *triggers.EventDispatcher
-	chains       map[[32]byte]*ChainIPC
+	// Directory the sockets are created in
+	dir string
+
+	lock   sync.Mutex
+	chains map[[32]byte]*ChainIPC
 }
 
-// NewService returns a new IPCs API service
-func NewService(log logging.Logger, chainManager chains.Manager, events *triggers.EventDispatcher, httpServer *api.Server) *common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := json.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&IPCs{
+// NewIPCs returns a new IPCs that create their sockets in [dir]
+func NewIPCs(log logging.Logger, chainManager chains.Manager, events *triggers.EventDispatcher, dir string) *IPCs {
+	return &IPCs{
 		log:          log,
 		chainManager: chainManager,
-		httpServer:   httpServer,
 		events:       events,
+		dir:          dir,
 		chains:       map[[32]byte]*ChainIPC{},
-	}, "ipcs")
-	return &common.HTTPHandler{Handler: newServer}
-}
-
-// PublishBlockchainArgs are the arguments for calling PublishBlockchain
-type PublishBlockchainArgs struct {
-	BlockchainID string `json:"blockchainID"`
+	}
 }
 
-// PublishBlockchainReply are the results from calling PublishBlockchain
-type PublishBlockchainReply struct {
-	URL string `json:"url"`
-}
+// Publish the containers accepted on the chain [blockchainID], which may be
+// an alias, and return the path of the socket they're published to
+func (ipcs *IPCs) Publish(blockchainID string) (string, error) {
+	if ipcs == nil {
+		return "", errIPCsDisabled
+	}
 
-// PublishBlockchain publishes the finalized accepted transactions from the blockchainID over the IPC
-func (ipc *IPCs) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs, reply *PublishBlockchainReply) error {
-	chainID, err := ipc.chainManager.Lookup(args.BlockchainID)
+	chainID, err := ipcs.chainManager.Lookup(blockchainID)
 	if err != nil {
-		ipc.log.Error("unknown blockchainID: %s", err)
-		return err
+		ipcs.log.Error("unknown blockchainID: %s", err)
+		return "", err
 	}
 
 	chainIDKey := chainID.Key()
 	chainIDStr := chainID.String()
-	url := baseURL + chainIDStr + ".ipc"
+	path := filepath.Join(ipcs.dir, chainIDStr+".ipc")
 
-	reply.URL = url
+	ipcs.lock.Lock()
+	defer ipcs.lock.Unlock()
 
-	if _, ok := ipc.chains[chainIDKey]; ok {
-		ipc.log.Info("returning existing blockchainID %s", chainIDStr)
-		return nil
+	if _, ok := ipcs.chains[chainIDKey]; ok {
+		ipcs.log.Info("returning existing blockchainID %s", chainIDStr)
+		return path, nil
 	}
 
-	sock, err := pub.NewSocket()
+	sock, err := NewSocket(ipcs.log, path)
 	if err != nil {
-		ipc.log.Error("can't get new pub socket: %s", err)
-		return err
-	}
-
-	if err = sock.Listen(url); err != nil {
-		ipc.log.Error("can't listen on pub socket: %s", err)
-		sock.Close()
-		return err
+		ipcs.log.Error("can't listen on socket: %s", err)
+		return "", err
 	}
 
 	chainIPC := &ChainIPC{
-		log:    ipc.log,
+		log:    ipcs.log,
 		socket: sock,
 	}
-	if err := ipc.events.RegisterChain(chainID, "ipc", chainIPC); err != nil {
-		ipc.log.Error("couldn't register event: %s", err)
+	if err := ipcs.events.RegisterChain(chainID, "ipc", chainIPC); err != nil {
+		ipcs.log.Error("couldn't register event: %s", err)
 		sock.Close()
-		return err
+		return "", err
 	}
 
-	ipc.chains[chainIDKey] = chainIPC
-	return nil
-}
-
-// UnpublishBlockchainArgs are the arguments for calling UnpublishBlockchain
-type UnpublishBlockchainArgs struct {
-	BlockchainID string `json:"blockchainID"`
+	ipcs.chains[chainIDKey] = chainIPC
+	return path, nil
 }
 
-// UnpublishBlockchainReply are the results from calling UnpublishBlockchain
-type UnpublishBlockchainReply struct {
-	Success bool `json:"success"`
-}
+// Unpublish stops publishing the containers accepted on the chain
+// [blockchainID], which may be an alias
+func (ipcs *IPCs) Unpublish(blockchainID string) error {
+	if ipcs == nil {
+		return errIPCsDisabled
+	}
 
-// UnpublishBlockchain closes publishing of a blockchainID
-func (ipc *IPCs) UnpublishBlockchain(r *http.Request, args *UnpublishBlockchainArgs, reply *UnpublishBlockchainReply) error {
-	chainID, err := ipc.chainManager.Lookup(args.BlockchainID)
+	chainID, err := ipcs.chainManager.Lookup(blockchainID)
 	if err != nil {
-		ipc.log.Error("unknown blockchainID %s: %s", args.BlockchainID, err)
+		ipcs.log.Error("unknown blockchainID %s: %s", blockchainID, err)
 		return err
 	}
 
 	chainIDKey := chainID.Key()
 
-	chain, ok := ipc.chains[chainIDKey]
+	ipcs.lock.Lock()
+	defer ipcs.lock.Unlock()
+
+	chain, ok := ipcs.chains[chainIDKey]
 	if !ok {
 		return fmt.Errorf("blockchainID not publishing: %s", chainID)
 	}
 
 	errs := wrappers.Errs{}
 	errs.Add(
+		ipcs.events.DeregisterChain(chainID, "ipc"),
 		chain.Stop(),
-		ipc.events.DeregisterChain(chainID, "ipc"),
 	)
-	delete(ipc.chains, chainIDKey)
+	delete(ipcs.chains, chainIDKey)
+	return errs.Err
+}
+
+// Service is the API service for IPCs
+type Service struct{ ipcs *IPCs }
+
+// NewService returns a new IPCs API service
+func NewService(ipcs *IPCs) *common.HTTPHandler {
+	newServer := rpc.NewServer()
+	codec := json.NewCodec()
+	newServer.RegisterCodec(codec, "application/json")
+	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	newServer.RegisterService(&Service{ipcs: ipcs}, "ipcs")
+	return &common.HTTPHandler{Handler: newServer}
+}
+
+// PublishBlockchainArgs are the arguments for calling PublishBlockchain
+type PublishBlockchainArgs struct {
+	BlockchainID string `json:"blockchainID"`
+}
+
+// PublishBlockchainReply are the results from calling PublishBlockchain
+type PublishBlockchainReply struct {
+	URL string `json:"url"`
+}
+
+// PublishBlockchain publishes the finalized accepted transactions from the
+// blockchainID over the IPC. [reply.URL] is the path of the Unix domain socket
+// they're published to. Each container is written to the socket as its
+// length, a 4 byte big-endian integer, followed by its bytes.
+func (service *Service) PublishBlockchain(r *http.Request, args *PublishBlockchainArgs, reply *PublishBlockchainReply) error {
+	url, err := service.ipcs.Publish(args.BlockchainID)
+	reply.URL = url
+	return err
+}
+
+// UnpublishBlockchainArgs are the arguments for calling UnpublishBlockchain
+type UnpublishBlockchainArgs struct {
+	BlockchainID string `json:"blockchainID"`
+}
 
+// UnpublishBlockchainReply are the results from calling UnpublishBlockchain
+type UnpublishBlockchainReply struct {
+	Success bool `json:"success"`
+}
+
+// UnpublishBlockchain closes publishing of a blockchainID
+func (service *Service) UnpublishBlockchain(r *http.Request, args *UnpublishBlockchainArgs, reply *UnpublishBlockchainReply) error {
+	if err := service.ipcs.Unpublish(args.BlockchainID); err != nil {
+		return err
+	}
 	reply.Success = true
-	return errs.Err
+	return nil
 }
@@ -0,0 +1,138 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const (
+	// Maximum number of messages queued for a reader. A reader that falls this
+	// far behind is disconnected, so it never silently misses a message.
+	maxPendingMessages = 1024
+)
+
+// Socket publishes messages to every reader connected to a Unix domain
+// socket. Each message is written as its length, a 4 byte big-endian integer,
+// followed by its bytes.
+type Socket struct {
+	log      logging.Logger
+	path     string
+	listener net.Listener
+
+	lock    sync.Mutex
+	readers map[*reader]struct{}
+	closed  bool
+}
+
+// NewSocket returns a socket listening at [path]. A stale socket file at
+// [path] is replaced.
+func NewSocket(log logging.Logger, path string) (*Socket, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Socket{
+		log:      log,
+		path:     path,
+		listener: listener,
+		readers:  make(map[*reader]struct{}),
+	}
+	go s.accept()
+	return s, nil
+}
+
+// Send [msg] to every connected reader. Send doesn't block on readers.
+func (s *Socket) Send(msg []byte) error {
+	framed := make([]byte, 4+len(msg))
+	binary.BigEndian.PutUint32(framed, uint32(len(msg)))
+	copy(framed[4:], msg)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for r := range s.readers {
+		select {
+		case r.send <- framed:
+		default:
+			s.log.Warn("disconnecting IPC reader that fell %d messages behind", maxPendingMessages)
+			s.removeReader(r)
+		}
+	}
+	return nil
+}
+
+// Close the socket and disconnect its readers
+func (s *Socket) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	for r := range s.readers {
+		s.removeReader(r)
+	}
+	return s.listener.Close()
+}
+
+// accept readers until the socket is closed
+func (s *Socket) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		r := &reader{conn: conn, send: make(chan []byte, maxPendingMessages)}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			conn.Close()
+			return
+		}
+		s.readers[r] = struct{}{}
+		s.lock.Unlock()
+
+		go s.write(r)
+	}
+}
+
+// write the messages sent to [r] until it's removed or a write fails
+func (s *Socket) write(r *reader) {
+	for msg := range r.send {
+		if _, err := r.conn.Write(msg); err != nil {
+			s.log.Debug("IPC reader disconnected: %s", err)
+			break
+		}
+	}
+	r.conn.Close()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.removeReader(r)
+}
+
+// removeReader stops sending messages to [r]
+// Assumes the lock is held
+func (s *Socket) removeReader(r *reader) {
+	if _, exists := s.readers[r]; !exists {
+		return
+	}
+	delete(s.readers, r)
+	close(r.send)
+}
+
+// reader is a connection to a socket
+type reader struct {
+	conn net.Conn
+	// Messages waiting to be written to the connection
+	send chan []byte
+}
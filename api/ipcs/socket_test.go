@@ -0,0 +1,162 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func readMsg(t *testing.T, conn net.Conn) []byte {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	lenBytes := make([]byte, 4)
+	if _, err := io.ReadFull(conn, lenBytes); err != nil {
+		t.Fatal(err)
+	}
+	msg := make([]byte, binary.BigEndian.Uint32(lenBytes))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+// waitForReaders waits until [n] readers are connected to [s]
+func waitForReaders(t *testing.T, s *Socket, n int) {
+	for i := 0; i < 100; i++ {
+		s.lock.Lock()
+		readers := len(s.readers)
+		s.lock.Unlock()
+		if readers == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%d readers should have connected", n)
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "gecko-ipcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSocketSend(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.ipc")
+	s, err := NewSocket(logging.NoLog{}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conns := make([]net.Conn, 2)
+	for i := range conns {
+		conns[i], err = net.Dial("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conns[i].Close()
+	}
+	waitForReaders(t, s, len(conns))
+
+	msgs := [][]byte{{1, 2, 3}, {}, bytes.Repeat([]byte{4}, 1000)}
+	for _, msg := range msgs {
+		if err := s.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, conn := range conns {
+		for _, expected := range msgs {
+			if msg := readMsg(t, conn); !bytes.Equal(msg, expected) {
+				t.Fatalf("Expected %v but read %v", expected, msg)
+			}
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := net.Dial("unix", path); err == nil {
+		t.Fatalf("Shouldn't be able to connect to a closed socket")
+	}
+}
+
+type testManager struct {
+	chains.Manager
+	chainID ids.ID
+}
+
+func (m *testManager) Lookup(alias string) (ids.ID, error) {
+	if alias == "X" || alias == m.chainID.String() {
+		return m.chainID, nil
+	}
+	return ids.ID{}, errIPCsDisabled
+}
+
+func TestIPCsPublish(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	chainID := ids.NewID([32]byte{1})
+	events := &triggers.EventDispatcher{}
+	events.Initialize(logging.NoLog{})
+	ipcs := NewIPCs(logging.NoLog{}, &testManager{chainID: chainID}, events, dir)
+
+	path, err := ipcs.Publish("X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(dir, chainID.String()+".ipc"); path != expected {
+		t.Fatalf("Expected the socket at %s but it's at %s", expected, path)
+	}
+	if samePath, err := ipcs.Publish(chainID.String()); err != nil {
+		t.Fatal(err)
+	} else if samePath != path {
+		t.Fatalf("Publishing a chain again should return its socket")
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	waitForReaders(t, ipcs.chains[chainID.Key()].socket, 1)
+
+	container := []byte{1, 2, 3}
+	events.Accept(ids.NewID([32]byte{2}), ids.Empty, []byte{4})
+	events.Accept(chainID, ids.Empty, container)
+	if msg := readMsg(t, conn); !bytes.Equal(msg, container) {
+		t.Fatalf("Expected %v but read %v", container, msg)
+	}
+
+	if err := ipcs.Unpublish("X"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ipcs.Unpublish("X"); err == nil {
+		t.Fatalf("Should have errored because the chain isn't being published")
+	}
+	if _, err := ipcs.Publish("Y"); err == nil {
+		t.Fatalf("Should have errored because the chain doesn't exist")
+	}
+
+	var disabled *IPCs
+	if _, err := disabled.Publish("X"); err != errIPCsDisabled {
+		t.Fatalf("Should have errored with %s but errored with %v", errIPCsDisabled, err)
+	}
+}
@@ -6,6 +6,7 @@ package keystore
 import (
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
 )
 
 // BlockchainKeystore ...
@@ -18,3 +19,13 @@ type BlockchainKeystore struct {
 func (bks *BlockchainKeystore) GetDatabase(username, password string) (database.Database, error) {
 	return bks.ks.GetDatabase(bks.blockchainID, username, password)
 }
+
+// GetSharedDatabase returns the user's database that every blockchain shares
+func (bks *BlockchainKeystore) GetSharedDatabase(username, password string) (database.Database, error) {
+	return bks.ks.GetSharedDatabase(username, password)
+}
+
+// NewKey returns a new key of the user on this blockchain
+func (bks *BlockchainKeystore) NewKey(username, password string) (*crypto.PrivateKeySECP256K1R, error) {
+	return bks.ks.NewKey(bks.blockchainID, username, password)
+}
@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/rand"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/encdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+)
+
+var (
+	// Key, in a user's database, of the salt the user's encryption key is
+	// derived with. It's distinct from the salt of the user's password hash,
+	// so the key can't be derived from what's stored in the user. Users whose
+	// data is still encrypted with the hash of their password don't have one.
+	encryptionSaltKey = []byte("encryptionSalt")
+)
+
+// deriveKey returns the key derived from [password] and [salt] with argon2id
+func deriveKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, keyLen)
+}
+
+// initEncryption generates the salt the encryption key of the user named
+// [username] is derived with
+// Assumes the lock is held
+func (ks *Keystore) initEncryption(username string) error {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	return userDB.Put(encryptionSaltKey, salt)
+}
+
+// encryptionKey returns the key the data of the user named [username] is
+// encrypted with. If the user's data is still encrypted with the hash of its
+// password, it's re-encrypted first.
+// Assumes the lock is held and that [password] is the user's password
+func (ks *Keystore) encryptionKey(username, password string) ([]byte, error) {
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	salt, err := userDB.Get(encryptionSaltKey)
+	switch err {
+	case nil:
+		return deriveKey(password, salt), nil
+	case database.ErrNotFound:
+		return ks.migrateUser(username, password)
+	default:
+		return nil, err
+	}
+}
+
+// migrateUser re-encrypts the data of the user named [username], which is
+// encrypted with the hash of its password, with a key derived from [password]
+// and a new salt, and returns the key. The data and the salt are written
+// atomically, so the user is never left partially migrated.
+// Assumes the lock is held and that [password] is the user's password
+func (ks *Keystore) migrateUser(username, password string) ([]byte, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := deriveKey(password, salt)
+
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	legacyDB, err := encdb.New([]byte(password), userDB)
+	if err != nil {
+		return nil, err
+	}
+	vdb := versiondb.New(userDB)
+	migratedDB, err := encdb.NewWithKey(key, vdb)
+	if err != nil {
+		return nil, err
+	}
+
+	it := legacyDB.NewIterator()
+	defer it.Release()
+	numValues := 0
+	for it.Next() {
+		if err := migratedDB.Put(it.Key(), it.Value()); err != nil {
+			return nil, err
+		}
+		numValues++
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	if err := vdb.Put(encryptionSaltKey, salt); err != nil {
+		return nil, err
+	}
+	if err := vdb.Commit(); err != nil {
+		return nil, err
+	}
+	ks.log.Info("re-encrypted the %d values of user %s with a derived key", numValues, username)
+	return key, nil
+}
@@ -0,0 +1,176 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/encdb"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// createLegacyUser creates a user named [username] the way users were created
+// before their data was encrypted with a derived key, and stores [value] under
+// [key] in its database for [bID]
+func createLegacyUser(t *testing.T, ks *Keystore, username, password string, bID ids.ID, key, value []byte) {
+	usr := &User{}
+	if err := usr.Initialize(password); err != nil {
+		t.Fatal(err)
+	}
+	usrBytes, err := ks.codec.Marshal(usr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.userDB.Put([]byte(username), usrBytes); err != nil {
+		t.Fatal(err)
+	}
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	legacyDB, err := encdb.New([]byte(password), prefixdb.NewNested(bID.Bytes(), userDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := legacyDB.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserDataEncryptedWithDerivedKey(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &CreateUserArgs{Username: "bob", Password: "launch"}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	bID := ids.NewID([32]byte{1})
+	db, err := ks.GetDatabase(bID, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The value shouldn't be decryptable with the hash of the password
+	bcDB := prefixdb.NewNested(bID.Bytes(), prefixdb.New([]byte("bob"), ks.bcDB))
+	legacyDB, err := encdb.New([]byte("launch"), bcDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacyDB.Get([]byte("key")); err == nil {
+		t.Fatalf("The value shouldn't be encrypted with the hash of the password")
+	}
+
+	db, err = ks.GetDatabase(bID, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := db.Get([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Returned %s, expected %s", value, "value")
+	}
+}
+
+func TestMigrateLegacyUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	bID := ids.NewID([32]byte{1})
+	createLegacyUser(t, &ks, "bob", "launch", bID, []byte("key"), []byte("value"))
+	userDB := prefixdb.New([]byte("bob"), ks.bcDB)
+	legacyDB, err := encdb.New([]byte("launch"), prefixdb.NewNested(bID.Bytes(), userDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.GetDatabase(bID, "bob", "wrong"); err == nil {
+		t.Fatalf("Should have errored because the password is wrong")
+	}
+	if has, err := userDB.Has(encryptionSaltKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("The user shouldn't be migrated without its password")
+	}
+
+	db, err := ks.GetDatabase(bID, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := db.Get([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Returned %s, expected %s", value, "value")
+	}
+	if _, err := legacyDB.Get([]byte("key")); err == nil {
+		t.Fatalf("The value should have been re-encrypted")
+	}
+
+	// Migrating is only done once
+	salt, err := userDB.Get(encryptionSaltKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.GetDatabase(bID, "bob", "launch"); err != nil {
+		t.Fatal(err)
+	}
+	if newSalt, err := userDB.Get(encryptionSaltKey); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(salt, newSalt) {
+		t.Fatalf("The user shouldn't have been migrated again")
+	}
+}
+
+func TestImportLegacyExport(t *testing.T) {
+	// Export a user whose data is encrypted with the hash of its password, the
+	// way users were exported before it was encrypted with a derived key
+	legacyKS := Keystore{}
+	legacyKS.Initialize(logging.NoLog{}, memdb.New())
+	bID := ids.NewID([32]byte{1})
+	createLegacyUser(t, &legacyKS, "bob", "launch", bID, []byte("key"), []byte("value"))
+	usr, err := legacyKS.getUser("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userData := UserDB{User: *usr}
+	it := prefixdb.New([]byte("bob"), legacyKS.bcDB).NewIterator()
+	for it.Next() {
+		userData.Data = append(userData.Data, KeyValuePair{Key: it.Key(), Value: it.Value()})
+	}
+	it.Release()
+	exported, err := encryptUser(legacyKS.codec, &userData, "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	// A salt left behind under the same name shouldn't be used
+	if err := ks.initEncryption("bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	cb58 := formatting.CB58{Bytes: exported}
+	if err := ks.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     cb58.String(),
+	}, &ImportUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := ks.GetDatabase(bID, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := db.Get([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Returned %s, expected %s", value, "value")
+	}
+}
@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	// Version of the format users are exported in
+	exportVersion uint16 = 1
+
+	saltLen  = 16
+	nonceLen = 12
+	keyLen   = 32
+)
+
+var (
+	errWrongExportPassword = errors.New("the exported user couldn't be decrypted with the provided password")
+)
+
+// exportedUser is the format users are exported in. The user and its data are
+// encrypted with AES-GCM under a key derived from the user's password with
+// argon2id.
+type exportedUser struct {
+	Version    uint16         `serialize:"true"`
+	Salt       [saltLen]byte  `serialize:"true"`
+	Nonce      [nonceLen]byte `serialize:"true"`
+	Ciphertext []byte         `serialize:"true"`
+}
+
+// newExportCipher returns the cipher an exported user is encrypted with, given
+// the user's password and the salt of the export
+func newExportCipher(password string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(password, salt))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptUser returns [userData] encrypted with [password]
+func encryptUser(c codec.Codec, userData *UserDB, password string) ([]byte, error) {
+	plaintext, err := c.Marshal(userData)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := exportedUser{Version: exportVersion}
+	if _, err := rand.Read(exported.Salt[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(exported.Nonce[:]); err != nil {
+		return nil, err
+	}
+	aead, err := newExportCipher(password, exported.Salt[:])
+	if err != nil {
+		return nil, err
+	}
+	exported.Ciphertext = aead.Seal(nil, exported.Nonce[:], plaintext, nil)
+	return c.Marshal(&exported)
+}
+
+// decryptUser returns the user that was encrypted with [password] by
+// encryptUser
+func decryptUser(c codec.Codec, b []byte, password string) (*UserDB, error) {
+	exported := exportedUser{}
+	if err := c.Unmarshal(b, &exported); err != nil {
+		return nil, err
+	}
+	if exported.Version != exportVersion {
+		return nil, fmt.Errorf("unsupported export version %d, expected %d", exported.Version, exportVersion)
+	}
+
+	aead, err := newExportCipher(password, exported.Salt[:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, exported.Nonce[:], exported.Ciphertext, nil)
+	if err != nil {
+		return nil, errWrongExportPassword
+	}
+
+	userData := &UserDB{}
+	if err := c.Unmarshal(plaintext, userData); err != nil {
+		return nil, err
+	}
+	return userData, nil
+}
@@ -0,0 +1,163 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"encoding/binary"
+	"net/http"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/encdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+const (
+	// Bits of entropy in generated mnemonics, which have 24 words
+	mnemonicEntropy = 256
+)
+
+var (
+	// Prefix of the database that holds a user's seed and the number of keys
+	// derived from it on each chain
+	hdPrefix = []byte("hd")
+
+	// Key of the seed in a user's HD database. The number of keys derived on
+	// each chain is stored under the chain's ID.
+	seedKey = []byte("seed")
+)
+
+// GenerateMnemonicArgs are the arguments to GenerateMnemonic
+type GenerateMnemonicArgs struct{}
+
+// GenerateMnemonicReply is the reply from GenerateMnemonic
+type GenerateMnemonicReply struct {
+	Mnemonic string `json:"mnemonic"`
+}
+
+// GenerateMnemonic returns a new, random BIP39 mnemonic. The mnemonic isn't
+// stored.
+func (ks *Keystore) GenerateMnemonic(_ *http.Request, args *GenerateMnemonicArgs, reply *GenerateMnemonicReply) error {
+	ks.log.Verbo("GenerateMnemonic called")
+
+	entropy, err := bip39.NewEntropy(mnemonicEntropy)
+	if err != nil {
+		return err
+	}
+	reply.Mnemonic, err = bip39.NewMnemonic(entropy)
+	return err
+}
+
+// CreateUserFromMnemonicArgs are arguments for passing into
+// CreateUserFromMnemonic requests
+type CreateUserFromMnemonicArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Mnemonic string `json:"mnemonic"`
+}
+
+// CreateUserFromMnemonicReply is the response from calling
+// CreateUserFromMnemonic
+type CreateUserFromMnemonicReply struct {
+	Success bool `json:"success"`
+}
+
+// CreateUserFromMnemonic creates a user whose keys are derived from the seed of
+// the BIP39 mnemonic [args.Mnemonic]. Creating a user from the same mnemonic on
+// another node recovers the same keys, in the same order, on every chain.
+func (ks *Keystore) CreateUserFromMnemonic(_ *http.Request, args *CreateUserFromMnemonicArgs, reply *CreateUserFromMnemonicReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("CreateUserFromMnemonic called with %s", args.Username)
+
+	seed, err := bip39.NewSeedWithErrorChecking(args.Mnemonic, "")
+	if err != nil {
+		return err
+	}
+
+	if err := ks.createUser(args.Username, args.Password); err != nil {
+		return err
+	}
+
+	hdDB, err := ks.hdDB(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if err := hdDB.Put(seedKey, seed); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// NewKey returns a new key of the user named [username] on the chain with ID
+// [bID]. If the user was created from a mnemonic, the key is the next one
+// derived from its seed for the chain. Otherwise, the key is random. The key
+// isn't stored.
+func (ks *Keystore) NewKey(bID ids.ID, username, password string) (*crypto.PrivateKeySECP256K1R, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if err := ks.checkPassword(username, password); err != nil {
+		return nil, err
+	}
+
+	hdDB, err := ks.hdDB(username, password)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hdDB.Get(seedKey)
+	if err == database.ErrNotFound {
+		factory := crypto.FactorySECP256K1R{}
+		sk, err := factory.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return sk.(*crypto.PrivateKeySECP256K1R), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := uint32(0)
+	indexBytes, err := hdDB.Get(bID.Bytes())
+	switch err {
+	case nil:
+		index = binary.BigEndian.Uint32(indexBytes)
+	case database.ErrNotFound:
+	default:
+		return nil, err
+	}
+
+	master, err := crypto.NewHDMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	key, err := master.Derive(crypto.ChainPath(bID, index))
+	if err != nil {
+		return nil, err
+	}
+
+	nextIndexBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(nextIndexBytes, index+1)
+	if err := hdDB.Put(bID.Bytes(), nextIndexBytes); err != nil {
+		return nil, err
+	}
+	return key.PrivateKey()
+}
+
+// hdDB returns the database that holds the seed of the user named [username]
+// Assumes the lock is held and that [password] is the user's password
+func (ks *Keystore) hdDB(username, password string) (database.Database, error) {
+	key, err := ks.encryptionKey(username, password)
+	if err != nil {
+		return nil, err
+	}
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	return encdb.NewWithKey(key, prefixdb.NewNested(hdPrefix, userDB))
+}
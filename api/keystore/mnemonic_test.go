@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keystore
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestServiceCreateUserFromMnemonic(t *testing.T) {
+	mnemonicReply := GenerateMnemonicReply{}
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.GenerateMnemonic(nil, &GenerateMnemonicArgs{}, &mnemonicReply); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered := Keystore{}
+	recovered.Initialize(logging.NoLog{}, memdb.New())
+
+	for _, keystore := range []*Keystore{&ks, &recovered} {
+		reply := CreateUserFromMnemonicReply{}
+		if err := keystore.CreateUserFromMnemonic(nil, &CreateUserFromMnemonicArgs{
+			Username: "bob",
+			Password: "launch",
+			Mnemonic: mnemonicReply.Mnemonic,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if !reply.Success {
+			t.Fatalf("User should have been created successfully")
+		}
+	}
+
+	chainA := ids.NewID([32]byte{1})
+	chainB := ids.NewID([32]byte{2})
+	keys := map[[20]byte]bool{}
+	for _, bID := range []ids.ID{chainA, chainA, chainB} {
+		sk, err := ks.NewKey(bID, "bob", "launch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		recoveredSK, err := recovered.NewKey(bID, "bob", "launch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sk.PublicKey().Address().Equals(recoveredSK.PublicKey().Address()) {
+			t.Fatalf("Keys derived from the same mnemonic should be the same")
+		}
+
+		addr := [20]byte{}
+		copy(addr[:], sk.PublicKey().Address().Bytes())
+		if keys[addr] {
+			t.Fatalf("Each derived key should be new")
+		}
+		keys[addr] = true
+	}
+
+	if _, err := ks.NewKey(chainA, "bob", "wrong"); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+}
+
+func TestServiceCreateUserFromMnemonicErrors(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	reply := CreateUserFromMnemonicReply{}
+	if err := ks.CreateUserFromMnemonic(nil, &CreateUserFromMnemonicArgs{
+		Username: "bob",
+		Password: "launch",
+		Mnemonic: "not a valid mnemonic",
+	}, &reply); err == nil {
+		t.Fatalf("Should have errored due to the invalid mnemonic")
+	}
+
+	listReply := ListUsersReply{}
+	if err := ks.ListUsers(nil, &ListUsersArgs{}, &listReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(listReply.Users) != 0 {
+		t.Fatalf("No user should have been created")
+	}
+}
+
+func TestServiceNewKeyWithoutMnemonic(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	reply := CreateUserReply{}
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	sk0, err := ks.NewKey(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk1, err := ks.NewKey(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk0.PublicKey().Address().Equals(sk1.PublicKey().Address()) {
+		t.Fatalf("Random keys should differ")
+	}
+}
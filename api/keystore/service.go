@@ -24,6 +24,9 @@ import (
 )
 
 var (
+	// Prefix of the database that every blockchain shares for a user
+	sharedPrefix = []byte("shared")
+
 	errEmptyUsername = errors.New("username can't be the empty string")
 )
 
@@ -116,15 +119,30 @@ func (ks *Keystore) CreateUser(_ *http.Request, args *CreateUserArgs, reply *Cre
 
 	ks.log.Verbo("CreateUser called with %s", args.Username)
 
-	if args.Username == "" {
+	if err := ks.createUser(args.Username, args.Password); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// createUser creates an empty user with the provided username and password
+// Assumes the lock is held
+func (ks *Keystore) createUser(username, password string) error {
+	if username == "" {
 		return errEmptyUsername
 	}
-	if usr, err := ks.getUser(args.Username); err == nil || usr != nil {
-		return fmt.Errorf("user already exists: %s", args.Username)
+	if usr, err := ks.getUser(username); err == nil || usr != nil {
+		return fmt.Errorf("user already exists: %s", username)
 	}
 
 	usr := &User{}
-	if err := usr.Initialize(args.Password); err != nil {
+	if err := usr.Initialize(password); err != nil {
+		return err
+	}
+	// The salt is written before the user, so that a user is never created
+	// without one
+	if err := ks.initEncryption(username); err != nil {
 		return err
 	}
 
@@ -133,11 +151,10 @@ func (ks *Keystore) CreateUser(_ *http.Request, args *CreateUserArgs, reply *Cre
 		return err
 	}
 
-	if err := ks.userDB.Put([]byte(args.Username), usrBytes); err != nil {
+	if err := ks.userDB.Put([]byte(username), usrBytes); err != nil {
 		return err
 	}
-	ks.users[args.Username] = usr
-	reply.Success = true
+	ks.users[username] = usr
 	return nil
 }
 
@@ -174,10 +191,13 @@ type ExportUserArgs struct {
 
 // ExportUserReply is the reply from ExportUser
 type ExportUserReply struct {
+	// The user, encrypted with its password
 	User string `json:"user"`
 }
 
-// ExportUser exports a serialized encoding of a user's information complete with encrypted database values
+// ExportUser exports a user and the data every blockchain stored for it,
+// encrypted with the user's password, so that it can be imported into another
+// node with ImportUser
 func (ks *Keystore) ExportUser(_ *http.Request, args *ExportUserArgs, reply *ExportUserReply) error {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
@@ -191,6 +211,11 @@ func (ks *Keystore) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Exp
 	if !usr.CheckPassword(args.Password) {
 		return fmt.Errorf("incorrect password for %s", args.Username)
 	}
+	// Users are exported with their encryption salt, so that their data can be
+	// decrypted once they're imported
+	if _, err := ks.encryptionKey(args.Username, args.Password); err != nil {
+		return err
+	}
 
 	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
 
@@ -210,7 +235,7 @@ func (ks *Keystore) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Exp
 		return err
 	}
 
-	b, err := ks.codec.Marshal(&userData)
+	b, err := encryptUser(ks.codec, &userData, args.Password)
 	if err != nil {
 		return err
 	}
@@ -231,7 +256,9 @@ type ImportUserReply struct {
 	Success bool `json:"success"`
 }
 
-// ImportUser imports a serialized encoding of a user's information complete with encrypted database values, integrity checks the password, and adds it to the database
+// ImportUser imports a user exported by ExportUser under the name
+// [args.Username]. [args.Password] must be the password the user was exported
+// with.
 func (ks *Keystore) ImportUser(r *http.Request, args *ImportUserArgs, reply *ImportUserReply) error {
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
@@ -247,32 +274,96 @@ func (ks *Keystore) ImportUser(r *http.Request, args *ImportUserArgs, reply *Imp
 		return err
 	}
 
-	userData := UserDB{}
-	if err := ks.codec.Unmarshal(cb58.Bytes, &userData); err != nil {
+	userData, err := decryptUser(ks.codec, cb58.Bytes, args.Password)
+	if err != nil {
 		return err
 	}
+	// The blockchains' data is encrypted with the user's password, so it must
+	// not change
+	if !userData.User.CheckPassword(args.Password) {
+		return fmt.Errorf("incorrect password for %s", args.Username)
+	}
 
 	usrBytes, err := ks.codec.Marshal(&userData.User)
 	if err != nil {
 		return err
 	}
 
-	// TODO: Should add batching to prevent creating a user without importing
-	// the account
+	// The data is written before the user, so that a user is never created
+	// without its data
+	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
+	batch := userDB.NewBatch()
+	// Users exported before their data was encrypted with a derived key don't
+	// have a salt, so one left behind under this name must not be used
+	if err := batch.Delete(encryptionSaltKey); err != nil {
+		return err
+	}
+	for _, kvp := range userData.Data {
+		if err := batch.Put(kvp.Key, kvp.Value); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
 	if err := ks.userDB.Put([]byte(args.Username), usrBytes); err != nil {
 		return err
 	}
 	ks.users[args.Username] = &userData.User
 
+	reply.Success = true
+	return nil
+}
+
+// DeleteUserArgs are arguments for DeleteUser
+type DeleteUserArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// DeleteUserReply is the response for DeleteUser
+type DeleteUserReply struct {
+	Success bool `json:"success"`
+}
+
+// DeleteUser deletes a user and the data every blockchain stored for it
+func (ks *Keystore) DeleteUser(_ *http.Request, args *DeleteUserArgs, reply *DeleteUserReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("DeleteUser called for %s", args.Username)
+
+	if err := ks.checkPassword(args.Username, args.Password); err != nil {
+		return err
+	}
+
+	// The data is deleted before the user, so that a user is never left
+	// without part of its data. The blockchains' databases are nested in the
+	// user's database, so iterating over it also returns their keys.
 	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
 	batch := userDB.NewBatch()
+	it := userDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
 
-	for _, kvp := range userData.Data {
-		batch.Put(kvp.Key, kvp.Value)
+	if err := ks.userDB.Delete([]byte(args.Username)); err != nil {
+		return err
 	}
+	delete(ks.users, args.Username)
 
 	reply.Success = true
-	return batch.Write()
+	return nil
 }
 
 // NewBlockchainKeyStore ...
@@ -288,21 +379,47 @@ func (ks *Keystore) GetDatabase(bID ids.ID, username, password string) (database
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	usr, err := ks.getUser(username)
-	if err != nil {
+	if err := ks.checkPassword(username, password); err != nil {
 		return nil, err
 	}
-	if !usr.CheckPassword(password) {
-		return nil, fmt.Errorf("incorrect password for user '%s'", username)
+	key, err := ks.encryptionKey(username, password)
+	if err != nil {
+		return nil, err
 	}
 
 	userDB := prefixdb.New([]byte(username), ks.bcDB)
 	bcDB := prefixdb.NewNested(bID.Bytes(), userDB)
-	encDB, err := encdb.New([]byte(password), bcDB)
+	return encdb.NewWithKey(key, bcDB)
+}
+
+// GetSharedDatabase returns the database of the user [username] that every
+// blockchain shares, such as for the user's address book
+func (ks *Keystore) GetSharedDatabase(username, password string) (database.Database, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
 
+	if err := ks.checkPassword(username, password); err != nil {
+		return nil, err
+	}
+	key, err := ks.encryptionKey(username, password)
 	if err != nil {
 		return nil, err
 	}
 
-	return encDB, nil
+	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	return encdb.NewWithKey(key, prefixdb.NewNested(sharedPrefix, userDB))
+}
+
+// checkPassword returns an error if [password] isn't the password of the user
+// named [username]
+// Assumes the lock is held
+func (ks *Keystore) checkPassword(username, password string) error {
+	usr, err := ks.getUser(username)
+	if err != nil {
+		return err
+	}
+	if !usr.CheckPassword(password) {
+		return fmt.Errorf("incorrect password for user '%s'", username)
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -200,3 +201,141 @@ func TestServiceExportImport(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceImportUserErrors(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	exportReply := ExportUserReply{}
+	if err := ks.ExportUser(nil, &ExportUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &exportReply); err != nil {
+		t.Fatal(err)
+	}
+
+	newKS := Keystore{}
+	newKS.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch!",
+		User:     exportReply.User,
+	}, &ImportUserReply{}); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+
+	cb58 := formatting.CB58{}
+	if err := cb58.FromString(exportReply.User); err != nil {
+		t.Fatal(err)
+	}
+	tampered := formatting.CB58{Bytes: append([]byte(nil), cb58.Bytes...)}
+	tampered.Bytes[len(tampered.Bytes)-1] ^= 1
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     tampered.String(),
+	}, &ImportUserReply{}); err == nil {
+		t.Fatalf("Should have errored due to the export being modified")
+	}
+
+	unsupported := formatting.CB58{Bytes: append([]byte(nil), cb58.Bytes...)}
+	unsupported.Bytes[1]++ // The version is the first two bytes
+	if err := newKS.ImportUser(nil, &ImportUserArgs{
+		Username: "bob",
+		Password: "launch",
+		User:     unsupported.String(),
+	}, &ImportUserReply{}); err == nil {
+		t.Fatalf("Should have errored due to the unsupported export version")
+	}
+
+	reply := ListUsersReply{}
+	if err := newKS.ListUsers(nil, &ListUsersArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Users) != 0 {
+		t.Fatalf("No users should have been imported")
+	}
+}
+
+func TestServiceDeleteUser(t *testing.T) {
+	baseDB := memdb.New()
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, baseDB)
+
+	numKeys := func() int {
+		it := baseDB.NewIterator()
+		defer it.Release()
+		n := 0
+		for it.Next() {
+			n++
+		}
+		if err := it.Error(); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "alice",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	expectedNumKeys := numKeys()
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, bID := range []ids.ID{ids.Empty, ids.NewID([32]byte{1})} {
+		db, err := ks.GetDatabase(bID, "bob", "launch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{
+		Username: "bob",
+		Password: "launch!",
+	}, &DeleteUserReply{}); err == nil {
+		t.Fatalf("Should have errored due to the wrong password")
+	}
+
+	reply := DeleteUserReply{}
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatalf("User should have been deleted successfully")
+	}
+
+	listReply := ListUsersReply{}
+	if err := ks.ListUsers(nil, &ListUsersArgs{}, &listReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(listReply.Users) != 1 || listReply.Users[0] != "alice" {
+		t.Fatalf("Only 'alice' should be left, but the users are %v", listReply.Users)
+	}
+	if n := numKeys(); n != expectedNumKeys {
+		t.Fatalf("The data of 'bob' should have been deleted, but %d keys were left rather than %d", n, expectedNumKeys)
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err == nil {
+		t.Fatalf("Should have errored due to the user not existing")
+	}
+}
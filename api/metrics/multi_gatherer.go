@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// MultiGatherer gathers the metrics of multiple gatherers, each under its own
+// namespace. For example, each chain registers its own registry, so that its
+// metrics can't collide with those of other chains.
+type MultiGatherer struct {
+	lock      sync.RWMutex
+	gatherers map[string]prometheus.Gatherer
+}
+
+// NewMultiGatherer returns a MultiGatherer with no gatherers
+func NewMultiGatherer() *MultiGatherer {
+	return &MultiGatherer{gatherers: make(map[string]prometheus.Gatherer)}
+}
+
+// Register [gatherer], whose metric names are prefixed with "[namespace]_". If
+// [namespace] is empty, the names aren't prefixed.
+func (g *MultiGatherer) Register(namespace string, gatherer prometheus.Gatherer) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if _, exists := g.gatherers[namespace]; exists {
+		return fmt.Errorf("a gatherer is already registered under the namespace %q", namespace)
+	}
+	g.gatherers[namespace] = gatherer
+	return nil
+}
+
+// Gather implements prometheus.Gatherer
+func (g *MultiGatherer) Gather() ([]*dto.MetricFamily, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	families := []*dto.MetricFamily(nil)
+	for namespace, gatherer := range g.gatherers {
+		gathered, err := gatherer.Gather()
+		if err != nil {
+			return nil, err
+		}
+		for _, family := range gathered {
+			if namespace != "" {
+				name := fmt.Sprintf("%s_%s", namespace, family.GetName())
+				family.Name = &name
+			}
+			families = append(families, family)
+		}
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].GetName() < families[j].GetName() })
+	return families, nil
+}
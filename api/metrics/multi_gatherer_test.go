@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestMultiGatherer(t *testing.T) {
+	g := NewMultiGatherer()
+
+	root := prometheus.NewRegistry()
+	root.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Name: "requests"}))
+	chain := prometheus.NewRegistry()
+	chain.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "requests"}))
+	chain.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: "accepted"}))
+
+	if err := g.Register("", root); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Register("chain", chain); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Register("chain", prometheus.NewRegistry()); err == nil {
+		t.Fatalf("Should have errored because the namespace is already registered")
+	}
+
+	families, err := g.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"chain_accepted", "chain_requests", "requests"}
+	if len(families) != len(expected) {
+		t.Fatalf("Expected %d metric families but gathered %d", len(expected), len(families))
+	}
+	for i, family := range families {
+		if name := family.GetName(); name != expected[i] {
+			t.Fatalf("Expected %s but gathered %s", expected[i], name)
+		}
+	}
+}
@@ -10,14 +10,19 @@ import (
 )
 
 // NewService returns a new prometheus service
-func NewService() (*prometheus.Registry, *common.HTTPHandler) {
+// The service serves the metrics of the returned registry, which aren't
+// namespaced, and of the gatherers registered with the returned MultiGatherer.
+func NewService() (*prometheus.Registry, *MultiGatherer, *common.HTTPHandler) {
 	registerer := prometheus.NewRegistry()
+	gatherer := NewMultiGatherer()
+	gatherer.Register("", registerer)
+
 	handler := promhttp.InstrumentMetricHandler(
 		registerer,
 		promhttp.HandlerFor(
-			registerer,
+			gatherer,
 			promhttp.HandlerOpts{},
 		),
 	)
-	return registerer, &common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}
+	return registerer, gatherer, &common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}
 }
@@ -0,0 +1,222 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// How often the buckets of IPs that haven't made requests recently are freed
+const sweepFrequency = time.Minute
+
+// RateLimits configures how many requests the API server serves. Limits of 0
+// aren't enforced.
+type RateLimits struct {
+	// Requests per second served from each IP, and how many requests an IP may
+	// make in a burst
+	PerIP      float64
+	PerIPBurst int
+	// Requests per second served from every IP together, and how many
+	// requests may be made in a burst
+	Global      float64
+	GlobalBurst int
+	// Number of requests served at once. Websocket connections aren't counted,
+	// as they're held open.
+	MaxConcurrent int
+}
+
+// tokenBucket allows [burst] requests at once, then [rate] requests per
+// second
+type tokenBucket struct {
+	rate, burst, tokens float64
+	last                time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// refill the bucket with the tokens earned since it was last refilled
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
+
+// allow returns true, and spends a token, if a request may be made at [now]
+func (b *tokenBucket) allow(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type rateLimiterMetrics struct {
+	numConcurrent                                           prometheus.Gauge
+	numRejectedIP, numRejectedGlobal, numRejectedConcurrent prometheus.Counter
+}
+
+func (m *rateLimiterMetrics) Initialize(namespace string, registerer prometheus.Registerer) error {
+	m.numConcurrent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "concurrent_requests",
+		Help:      "Number of requests being served",
+	})
+	m.numRejectedIP = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rejected_ip_rate",
+		Help:      "Number of requests rejected because their IP exceeded its rate limit",
+	})
+	m.numRejectedGlobal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rejected_global_rate",
+		Help:      "Number of requests rejected because the global rate limit was exceeded",
+	})
+	m.numRejectedConcurrent = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rejected_concurrent",
+		Help:      "Number of requests rejected because too many requests were being served",
+	})
+
+	for _, collector := range []prometheus.Collector{
+		m.numConcurrent,
+		m.numRejectedIP,
+		m.numRejectedGlobal,
+		m.numRejectedConcurrent,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rateLimiter rejects requests that exceed its limits with status 429
+type rateLimiter struct {
+	limits  RateLimits
+	metrics rateLimiterMetrics
+	clock   timer.Clock
+
+	lock      sync.Mutex
+	global    *tokenBucket
+	perIP     map[string]*tokenBucket
+	lastSweep time.Time
+
+	// Holds a value for each request being served. Nil if the number of
+	// concurrent requests isn't limited.
+	concurrent chan struct{}
+}
+
+func newRateLimiter(limits RateLimits, namespace string, registerer prometheus.Registerer) (*rateLimiter, error) {
+	rl := &rateLimiter{
+		limits: limits,
+		perIP:  make(map[string]*tokenBucket),
+	}
+	now := rl.clock.Time()
+	rl.lastSweep = now
+	if limits.Global > 0 {
+		rl.global = newTokenBucket(limits.Global, limits.GlobalBurst, now)
+	}
+	if limits.MaxConcurrent > 0 {
+		rl.concurrent = make(chan struct{}, limits.MaxConcurrent)
+	}
+	return rl, rl.metrics.Initialize(namespace, registerer)
+}
+
+// allow returns true if a request from [ip] may be served now, and the
+// counter of the limit it exceeded if not
+func (rl *rateLimiter) allow(ip string) (bool, prometheus.Counter) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	now := rl.clock.Time()
+	if rl.limits.PerIP > 0 {
+		rl.sweep(now)
+		bucket, exists := rl.perIP[ip]
+		if !exists {
+			bucket = newTokenBucket(rl.limits.PerIP, rl.limits.PerIPBurst, now)
+			rl.perIP[ip] = bucket
+		}
+		if !bucket.allow(now) {
+			return false, rl.metrics.numRejectedIP
+		}
+	}
+	if rl.global != nil && !rl.global.allow(now) {
+		return false, rl.metrics.numRejectedGlobal
+	}
+	return true, nil
+}
+
+// sweep frees the buckets of IPs that have been idle long enough for their
+// buckets to refill, as they'd be recreated the same
+// Assumes the lock is held
+func (rl *rateLimiter) sweep(now time.Time) {
+	if now.Sub(rl.lastSweep) < sweepFrequency {
+		return
+	}
+	rl.lastSweep = now
+	for ip, bucket := range rl.perIP {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.burst {
+			delete(rl.perIP, ip)
+		}
+	}
+}
+
+// wrap returns a handler that passes the requests [rl] allows to [h]
+func (rl *rateLimiter) wrap(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		if allowed, rejected := rl.allow(ip); !allowed {
+			rejected.Inc()
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		if rl.concurrent != nil && !isWebsocket(r) {
+			select {
+			case rl.concurrent <- struct{}{}:
+				defer func() { <-rl.concurrent }()
+			default:
+				rl.metrics.numRejectedConcurrent.Inc()
+				http.Error(w, "too many concurrent requests", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		rl.metrics.numConcurrent.Inc()
+		defer rl.metrics.numConcurrent.Dec()
+		h.ServeHTTP(w, r)
+	})
+}
+
+// isWebsocket returns true if [r] opens a websocket connection
+func isWebsocket(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
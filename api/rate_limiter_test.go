@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var ok = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+func get(handler http.Handler, remoteAddr string) int {
+	req := httptest.NewRequest("GET", "/ext/info", nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimiterPerIP(t *testing.T) {
+	rl, err := newRateLimiter(RateLimits{PerIP: 1, PerIPBurst: 2}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1000, 0)
+	rl.clock.Set(start)
+	rl.lastSweep = start
+	handler := rl.wrap(ok)
+
+	for i := 0; i < 2; i++ {
+		if code := get(handler, "1.2.3.4:5678"); code != http.StatusOK {
+			t.Fatalf("Request %d should have been in the burst but got status %d", i, code)
+		}
+	}
+	if code := get(handler, "1.2.3.4:9999"); code != http.StatusTooManyRequests {
+		t.Fatalf("The IP should have exceeded its limit but got status %d", code)
+	}
+	if code := get(handler, "5.6.7.8:5678"); code != http.StatusOK {
+		t.Fatalf("Other IPs shouldn't be limited but got status %d", code)
+	}
+
+	rl.clock.Set(start.Add(time.Second))
+	if code := get(handler, "1.2.3.4:5678"); code != http.StatusOK {
+		t.Fatalf("The IP should have earned a request but got status %d", code)
+	}
+
+	rl.clock.Set(start.Add(sweepFrequency + time.Second))
+	get(handler, "9.9.9.9:1")
+	if _, exists := rl.perIP["1.2.3.4"]; exists {
+		t.Fatalf("The bucket of an idle IP should have been freed")
+	}
+}
+
+func TestRateLimiterGlobal(t *testing.T) {
+	rl, err := newRateLimiter(RateLimits{Global: 1, GlobalBurst: 1}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rl.clock.Set(time.Unix(1000, 0))
+	handler := rl.wrap(ok)
+
+	if code := get(handler, "1.2.3.4:5678"); code != http.StatusOK {
+		t.Fatalf("The first request should have been served but got status %d", code)
+	}
+	if code := get(handler, "5.6.7.8:5678"); code != http.StatusTooManyRequests {
+		t.Fatalf("The global limit should have been exceeded but got status %d", code)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	rl, err := newRateLimiter(RateLimits{MaxConcurrent: 1}, "", prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	served := make(chan struct{})
+	handler := rl.wrap(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		served <- struct{}{}
+		<-release
+	}))
+
+	go get(handler, "1.2.3.4:5678")
+	<-served
+	if code := get(handler, "5.6.7.8:5678"); code != http.StatusTooManyRequests {
+		t.Fatalf("Too many requests should have been served but got status %d", code)
+	}
+	close(release)
+}
@@ -4,17 +4,24 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"sync"
 
 	"github.com/gorilla/handlers"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/rs/cors"
 
+	"github.com/ava-labs/gecko/api/auth"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
@@ -24,6 +31,7 @@ const baseURL = "/ext"
 
 var (
 	errUnknownLockOption = errors.New("invalid lock options")
+	errNoClientCAs       = errors.New("no client CA certificates could be parsed")
 )
 
 // Server maintains the HTTP router
@@ -32,26 +40,142 @@ type Server struct {
 	factory logging.Factory
 	router  *router
 	portURL string
+	// Rejects unauthorized calls to restricted methods
+	auth *auth.Auth
+	// Origins that browsers may call the server from
+	cors *cors.Cors
+	// Maximum size, in bytes, of a request. Not limited if 0.
+	maxRequestSize int64
+	// Maximum number of requests in a JSON-RPC batch. Not limited if 0.
+	maxBatchSize int
+	// Rejects requests that exceed the rate limits. Nil if requests aren't
+	// limited.
+	rateLimiter *rateLimiter
+
+	// The server being dispatched, and whether the server was shut down
+	lock     sync.Mutex
+	srv      *http.Server
+	shutdown bool
 }
 
-// Initialize creates the API server at the provided port
-func (s *Server) Initialize(log logging.Logger, factory logging.Factory, port uint16) {
+// Initialize creates the API server at the provided port. Calls to the server
+// must be authorized by [auth]. Browsers may call the server from
+// [allowedOrigins], where "*" allows every origin. Requests larger than
+// [maxRequestSize] bytes and JSON-RPC batches of more than [maxBatchSize]
+// requests are rejected. Limits of 0 aren't enforced.
+func (s *Server) Initialize(log logging.Logger, factory logging.Factory, port uint16, auth *auth.Auth, allowedOrigins []string, maxRequestSize int64, maxBatchSize int) {
 	s.log = log
 	s.factory = factory
 	s.portURL = fmt.Sprintf(":%d", port)
 	s.router = newRouter()
+	s.auth = auth
+	s.maxRequestSize = maxRequestSize
+	s.maxBatchSize = maxBatchSize
+	s.cors = cors.New(cors.Options{
+		AllowedOrigins: allowedOrigins,
+		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization"},
+	})
 }
 
-// Dispatch starts the API server
+// LimitRate rejects requests that exceed [limits] with status 429. The number
+// of rejected requests is recorded in metrics registered with [registerer].
+// Should be called before the server is dispatched.
+func (s *Server) LimitRate(limits RateLimits, namespace string, registerer prometheus.Registerer) error {
+	rateLimiter, err := newRateLimiter(limits, namespace, registerer)
+	if err != nil {
+		return err
+	}
+	s.rateLimiter = rateLimiter
+	return nil
+}
+
+// Dispatch starts the API server. Returns http.ErrServerClosed once the
+// server is shut down.
 func (s *Server) Dispatch() error {
-	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServe(s.portURL, handler)
+	server := &http.Server{
+		Addr:    s.portURL,
+		Handler: s.handler(),
+	}
+	if err := s.setServer(server); err != nil {
+		return err
+	}
+	return server.ListenAndServe()
 }
 
-// DispatchTLS starts the API server with the provided TLS certificate
-func (s *Server) DispatchTLS(certFile, keyFile string) error {
-	handler := cors.Default().Handler(s.router)
-	return http.ListenAndServeTLS(s.portURL, certFile, keyFile, handler)
+// DispatchTLS starts the API server with the provided TLS certificate. If
+// [clientCAFile] isn't empty, clients must present a certificate signed by one
+// of the CAs in it.
+func (s *Server) DispatchTLS(certFile, keyFile, clientCAFile string) error {
+	tlsConfig, err := newTLSConfig(clientCAFile)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:      s.portURL,
+		Handler:   s.handler(),
+		TLSConfig: tlsConfig,
+	}
+	if err := s.setServer(server); err != nil {
+		return err
+	}
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+// Shutdown stops the server from accepting calls, and waits for the calls
+// being served to finish until [ctx] is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.lock.Lock()
+	s.shutdown = true
+	server := s.srv
+	s.lock.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// setServer records [server] as the server being dispatched. Returns
+// http.ErrServerClosed if the server was already shut down.
+func (s *Server) setServer(server *http.Server) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.shutdown {
+		return http.ErrServerClosed
+	}
+	s.srv = server
+	return nil
+}
+
+// handler returns the handler of every call to the server
+func (s *Server) handler() http.Handler {
+	h := s.auth.WrapHandler(s.router)
+	if s.rateLimiter != nil {
+		h = s.rateLimiter.wrap(h)
+	}
+	return s.cors.Handler(h)
+}
+
+// newTLSConfig returns the TLS configuration of the server. If [clientCAFile]
+// isn't empty, it's a PEM file of the CAs that client certificates must be
+// signed by.
+func newTLSConfig(clientCAFile string) (*tls.Config, error) {
+	if clientCAFile == "" {
+		return &tls.Config{}, nil
+	}
+	pemBytes, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read client CA file: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pemBytes) {
+		return nil, errNoClientCAs
+	}
+	return &tls.Config{
+		ClientCAs:  clientCAs,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+	}, nil
 }
 
 // RegisterChain registers the API endpoints associated with this chain That
@@ -92,7 +216,11 @@ func (s *Server) RegisterChain(ctx *snow.Context, vmIntf interface{}) {
 func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, log logging.Logger) error {
 	url := fmt.Sprintf("%s/%s", baseURL, base)
 	s.log.Info("adding route %s%s", url, endpoint)
-	h := handlers.CombinedLoggingHandler(log, handler.Handler)
+	h := handlers.CombinedLoggingHandler(log, batchHandler{
+		handler:        handler.Handler,
+		maxRequestSize: s.maxRequestSize,
+		maxBatchSize:   s.maxBatchSize,
+	})
 	switch handler.LockOptions {
 	case common.WriteLock:
 		return s.router.AddRouter(url, endpoint, middlewareHandler{
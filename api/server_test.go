@@ -5,14 +5,27 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 	"github.com/gorilla/rpc/v2/json2"
 
+	"github.com/ava-labs/gecko/api/auth"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
 )
@@ -30,7 +43,7 @@ func (s *Service) Call(_ *http.Request, args *Args, reply *Reply) error {
 
 func TestCall(t *testing.T) {
 	s := Server{}
-	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080)
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080, &auth.Auth{}, []string{"*"}, 0, 0)
 
 	serv := &Service{}
 	newServer := rpc.NewServer()
@@ -58,3 +71,104 @@ func TestCall(t *testing.T) {
 		t.Fatalf("Should have been called")
 	}
 }
+
+func TestCORS(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 8080, &auth.Auth{}, []string{"https://wallet.example"}, 0, 0)
+
+	for origin, allowed := range map[string]bool{
+		"https://wallet.example": true,
+		"https://evil.example":   false,
+	} {
+		req := httptest.NewRequest("OPTIONS", "/ext/keystore", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		writer := httptest.NewRecorder()
+		s.handler().ServeHTTP(writer, req)
+
+		if got := writer.Header().Get("Access-Control-Allow-Origin") == origin; got != allowed {
+			t.Fatalf("Calls from %s should be allowed: %t", origin, allowed)
+		}
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	config, err := newTLSConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.NoClientCert {
+		t.Fatalf("Client certificates shouldn't be required without a client CA file")
+	}
+
+	dir, err := ioutil.TempDir("", "gecko-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := newTLSConfig(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Fatalf("Should have errored because the client CA file doesn't exist")
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	if err := ioutil.WriteFile(invalidFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTLSConfig(invalidFile); err != errNoClientCAs {
+		t.Fatalf("Should have errored with %s but errored with %v", errNoClientCAs, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err = newTLSConfig(caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("Client certificates should be required with a client CA file")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, 0, &auth.Auth{}, []string{"*"}, 0, 0)
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.Dispatch() }()
+	for {
+		s.lock.Lock()
+		dispatched := s.srv != nil
+		s.lock.Unlock()
+		if dispatched {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != http.ErrServerClosed {
+		t.Fatalf("Dispatch should have returned %s but returned %v", http.ErrServerClosed, err)
+	}
+	if err := s.Dispatch(); err != http.ErrServerClosed {
+		t.Fatalf("Dispatch after Shutdown should have returned %s but returned %v", http.ErrServerClosed, err)
+	}
+}
@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	configFileName   = "config.json"
+	upgradeFileName  = "upgrade.json"
+	settingsFileName = "settings.json"
+)
+
+// ChainConfig is the configuration an operator gave a chain on this node
+type ChainConfig struct {
+	// Replaces the configuration the chain was created with
+	Config []byte
+	// Passed to the chain's VM in its context's UpgradeBytes
+	Upgrade []byte
+	// Key/value settings and feature flags given to the chain in its
+	// context's Config. They override the settings given to every chain.
+	Settings map[string]string
+}
+
+// LoadChainConfigs reads the configurations of chains from [dir]. Each
+// subdirectory of [dir] is named by a chain's ID or alias, and may hold the
+// chain's config.json, upgrade.json and settings.json. If [dir] doesn't exist,
+// no chains are configured.
+func LoadChainConfigs(dir string) (map[string]ChainConfig, error) {
+	configs := make(map[string]ChainConfig)
+	if dir == "" {
+		return configs, nil
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return configs, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		chainDir := filepath.Join(dir, entry.Name())
+		config, err := readOptionalFile(filepath.Join(chainDir, configFileName))
+		if err != nil {
+			return nil, err
+		}
+		upgrade, err := readOptionalFile(filepath.Join(chainDir, upgradeFileName))
+		if err != nil {
+			return nil, err
+		}
+		settingsBytes, err := readOptionalFile(filepath.Join(chainDir, settingsFileName))
+		if err != nil {
+			return nil, err
+		}
+		settings := map[string]string(nil)
+		if settingsBytes != nil {
+			if settings, err = ParseSettings(settingsBytes); err != nil {
+				return nil, fmt.Errorf("couldn't parse the settings of chain %s: %w", entry.Name(), err)
+			}
+		}
+		if config != nil || upgrade != nil || settings != nil {
+			configs[entry.Name()] = ChainConfig{
+				Config:   config,
+				Upgrade:  upgrade,
+				Settings: settings,
+			}
+		}
+	}
+	return configs, nil
+}
+
+// readOptionalFile returns the contents of [file], or nil if it doesn't exist
+func readOptionalFile(file string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return contents, err
+}
+
+// ParseSettings parses the JSON object [b] into key/value settings. The keys
+// of nested objects are joined to the keys of the objects that hold them with
+// a ".", so {"features": {"batchedGossip": true}} sets the key
+// "features.batchedGossip" to "true".
+func ParseSettings(b []byte) (map[string]string, error) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	settings := make(map[string]string)
+	return settings, flattenSettings("", obj, settings)
+}
+
+// flattenSettings adds the values of [obj] to [settings], with their keys
+// prefixed by [prefix]
+func flattenSettings(prefix string, obj map[string]interface{}, settings map[string]string) error {
+	for key, val := range obj {
+		key = prefix + key
+		switch val := val.(type) {
+		case string:
+			settings[key] = val
+		case bool:
+			settings[key] = strconv.FormatBool(val)
+		case float64:
+			settings[key] = strconv.FormatFloat(val, 'f', -1, 64)
+		case map[string]interface{}:
+			if err := flattenSettings(key+".", val, settings); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("setting %s must be a string, bool, number or object", key)
+		}
+	}
+	return nil
+}
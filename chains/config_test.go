@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadChainConfigs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chain-configs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		filepath.Join("X", configFileName):   `{"indexTransactions":false}`,
+		filepath.Join("C", upgradeFileName):  `{}`,
+		filepath.Join("P", "unrelated.json"): `{}`,
+		filepath.Join("Q", settingsFileName): `{"gossipSize":10,"name":"q","features":{"batchedGossip":true}}`,
+	}
+	for file, contents := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configs, err := LoadChainConfigs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(configs) != 3:
+		t.Fatalf("Loaded %d configs, expected 3", len(configs))
+	case !bytes.Equal(configs["X"].Config, []byte(`{"indexTransactions":false}`)) || configs["X"].Upgrade != nil:
+		t.Fatalf("Loaded the wrong config of X")
+	case configs["C"].Config != nil || !bytes.Equal(configs["C"].Upgrade, []byte(`{}`)):
+		t.Fatalf("Loaded the wrong config of C")
+	case len(configs["Q"].Settings) != 3 || configs["Q"].Settings["gossipSize"] != "10" ||
+		configs["Q"].Settings["name"] != "q" || configs["Q"].Settings["features.batchedGossip"] != "true":
+		t.Fatalf("Loaded the wrong settings of Q: %v", configs["Q"].Settings)
+	}
+}
+
+func TestParseSettingsInvalid(t *testing.T) {
+	if _, err := ParseSettings([]byte(`{"list":[1,2]}`)); err == nil {
+		t.Fatalf("Should have errored due to a setting being a list")
+	}
+	if _, err := ParseSettings([]byte(`[]`)); err == nil {
+		t.Fatalf("Should have errored due to the settings not being an object")
+	}
+}
+
+func TestLoadChainConfigsMissingDir(t *testing.T) {
+	configs, err := LoadChainConfigs(filepath.Join(os.TempDir(), "chain-configs-that-dont-exist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 0 {
+		t.Fatalf("Loaded %d configs from a missing directory", len(configs))
+	}
+}
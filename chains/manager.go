@@ -4,12 +4,19 @@
 package chains
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/api/metrics"
+	"github.com/ava-labs/gecko/chains/replay"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/meterdb"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -23,10 +30,13 @@ import (
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/sender"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/networking/tracing"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/vms"
+	"github.com/ava-labs/gecko/vms/components/core"
 
 	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
 	avaeng "github.com/ava-labs/gecko/snow/engine/avalanche"
@@ -37,9 +47,18 @@ import (
 
 const (
 	defaultChannelSize = 1000
-	requestTimeout     = 2 * time.Second
+
+	// The number of read-only requests from the network each snowman chain
+	// serves at a time, alongside the messages that change its state
+	defaultReadWorkers = 4
+
+	// Avalanche chains pass read-only requests with the other messages, as
+	// fetching a vertex refreshes the serializer's cache of vertices
+	avalancheReadWorkers = 0
 )
 
+var errUnknownVMType = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
+
 // Manager manages the chains running on this node.
 // It can:
 //   * Create a chain
@@ -51,12 +70,19 @@ type Manager interface {
 	// Return the router this Manager is using to route consensus messages to chains
 	Router() router.Router
 
-	// Create a chain in the future
+	// Create a chain once the platform chain has bootstrapped. It's safe to
+	// call at runtime, such as when the platform chain accepts a tx that
+	// creates a chain. If this node doesn't validate the chain's subnet, the
+	// chain is only created once the subnet is whitelisted.
 	CreateChain(ChainParameters)
 
 	// Create a chain now
 	ForceCreateChain(ChainParameters)
 
+	// Replay the containers of a source through the chain's VM, without
+	// consensus. The chain must not have been created.
+	ReplayChain(ChainParameters, replay.Source) (replay.Result, error)
+
 	// Add a registrant [r]. Every time a chain is
 	// created, [r].RegisterChain([new chain]) is called
 	AddRegistrant(Registrant)
@@ -73,6 +99,19 @@ type Manager interface {
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Returns true if the chain has finished bootstrapping
+	IsBootstrapped(ids.ID) bool
+
+	// Returns true if this node validates the subnet
+	Validates(ids.ID) bool
+
+	// Start validating the subnet at runtime. Returns false if this node
+	// already validates it.
+	WhitelistSubnet(ids.ID) bool
+
+	// Add a tracker that's notified when a subnet is whitelisted
+	AddSubnetTracker(SubnetTracker)
+
 	Shutdown()
 }
 
@@ -81,6 +120,7 @@ type ChainParameters struct {
 	ID          ids.ID   // The ID of the chain being created
 	SubnetID    ids.ID   // ID of the subnet that validates this chain
 	GenesisData []byte   // The genesis data of this chain's ledger
+	ConfigData  []byte   // The configuration this chain's VM is initialized with
 	VMAlias     string   // The ID of the vm this chain is running
 	FxAliases   []string // The IDs of the feature extensions this chain is running
 
@@ -91,6 +131,9 @@ type manager struct {
 	// Note: The string representation of a chain's ID is also considered to be an alias of the chain
 	// That is, [chainID].String() is an alias for the chain, too
 	ids.Aliaser
+	// Chains are aliased as they're created, while the aliases are looked up
+	// by other chains and the APIs
+	aliasLock sync.RWMutex
 
 	log             logging.Logger
 	logFactory      logging.Factory
@@ -103,15 +146,40 @@ type manager struct {
 	timeoutManager  *timeout.Manager      // Manages request timeouts when sending messages to other validators
 	consensusParams avacon.Parameters     // The consensus parameters (alpha, beta, etc.) for new chains
 	validators      validators.Manager    // Validators validating on this chain
-	registrants     []Registrant          // Those notified when a chain is created
 	nodeID          ids.ShortID           // The ID of this node
 	networkID       uint32                // ID of the network this node is connected to
 	awaiter         Awaiter               // Waits for required connections before running bootstrapping
 	server          *api.Server           // Handles HTTP API calls
 	keystore        *keystore.Keystore
-
+	sharedMemory    *core.SharedMemory
+	metrics         *metrics.MultiGatherer // Gathers the metrics of each chain
+	stateRetention  time.Duration          // How long chains keep state before it's pruned
+	chainConfigs    map[string]ChainConfig // Operators' configurations of chains, by chain ID or alias
+	chainSettings   map[string]string      // Settings and feature flags given to every chain
+	stateSync       bool                   // Sync chains that support it to a recent state before bootstrapping
+	tracer          *tracing.Tracer        // Traces chains' messages. Nil if they aren't traced.
+	uptimes         snow.Uptimes           // Uptimes of other nodes, as observed by this node
+
+	// Chains requested before the platform chain bootstrapped are created
+	// once it has. The platform chain requests chains from its own goroutine.
+	unblockedLock sync.Mutex
 	unblocked     bool
 	blockedChains []ChainParameters
+
+	// Subnets this node validates, including the default subnet, and the
+	// chains of other subnets that weren't created
+	subnetsLock      sync.RWMutex
+	validatedSubnets ids.Set
+	skippedChains    map[[32]byte][]ChainParameters
+	subnetTrackers   []SubnetTracker
+
+	// Registrants are added by the node while chains are being created
+	registrantsLock sync.RWMutex
+	registrants     []Registrant // Those notified when a chain is created
+
+	// Chains that have finished bootstrapping
+	bootstrappedLock sync.RWMutex
+	bootstrapped     ids.Set
 }
 
 // New returns a new Manager where:
@@ -135,12 +203,29 @@ func New(
 	awaiter Awaiter,
 	server *api.Server,
 	keystore *keystore.Keystore,
+	sharedMemory *core.SharedMemory,
+	metrics *metrics.MultiGatherer,
+	stateRetention time.Duration,
+	chainConfigs map[string]ChainConfig,
+	chainSettings map[string]string,
+	stateSync bool,
+	tracer *tracing.Tracer,
+	uptimes snow.Uptimes,
+	validatedSubnets ids.Set,
+	timeoutConfig *timer.AdaptiveTimeoutConfig,
 ) Manager {
 	timeoutManager := timeout.Manager{}
-	timeoutManager.Initialize(requestTimeout)
+	if err := timeoutManager.InitializeAdaptive(timeoutConfig); err != nil {
+		log.Error("invalid network timeouts, so requests to other nodes don't adapt their timeouts: %s", err)
+		timeoutManager.Initialize(timeoutConfig.InitialTimeout)
+	}
 	go log.RecoverAndPanic(timeoutManager.Dispatch)
 
-	router.Initialize(log, &timeoutManager)
+	routerRegistry := prometheus.NewRegistry()
+	router.Initialize(log, &timeoutManager, validators, "", routerRegistry)
+	if err := metrics.Register("gecko_router", routerRegistry); err != nil {
+		log.Error("failed to register the router's metrics due to %s", err)
+	}
 
 	m := &manager{
 		log:             log,
@@ -159,7 +244,17 @@ func New(
 		awaiter:         awaiter,
 		server:          server,
 		keystore:        keystore,
+		sharedMemory:    sharedMemory,
+		metrics:         metrics,
+		stateRetention:  stateRetention,
+		chainConfigs:    chainConfigs,
+		chainSettings:   chainSettings,
+		stateSync:       stateSync,
+		tracer:          tracer,
+		uptimes:         uptimes,
 	}
+	m.validatedSubnets.Union(validatedSubnets)
+	m.skippedChains = make(map[[32]byte][]ChainParameters)
 	m.Initialize()
 	return m
 }
@@ -169,11 +264,19 @@ func (m *manager) Router() router.Router { return m.chainRouter }
 
 // Create a chain
 func (m *manager) CreateChain(chain ChainParameters) {
+	if m.skipUnvalidated(chain) {
+		return
+	}
+
+	m.unblockedLock.Lock()
 	if !m.unblocked {
 		m.blockedChains = append(m.blockedChains, chain)
-	} else {
-		m.ForceCreateChain(chain)
+		m.unblockedLock.Unlock()
+		return
 	}
+	m.unblockedLock.Unlock()
+
+	m.ForceCreateChain(chain)
 }
 
 // Create a chain
@@ -192,17 +295,87 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		return
 	}
 
-	vmID, err := m.vmManager.Lookup(chain.VMAlias)
+	vm, fxs, err := m.newVM(chain)
 	if err != nil {
-		m.log.Error("error while looking up VM: %s", err)
+		m.log.Error("%s", err)
+		return
+	}
+
+	ctx, configData, registry, err := m.newContext(chain)
+	if err != nil {
+		m.log.Error("%s", err)
+		return
+	}
+	consensusParams := m.consensusParams
+	consensusParams.Namespace = ""
+	consensusParams.Metrics = registry
+
+	// The validators of this blockchain
+	validators, ok := m.validators.GetValidatorSet(chain.SubnetID)
+	if !ok {
+		m.log.Error("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
+		return
+	}
+
+	beacons := validators
+	if chain.CustomBeacons != nil {
+		beacons = chain.CustomBeacons
+	}
+
+	switch vm := vm.(type) {
+	case avalanche.DAGVM:
+		err := m.createAvalancheChain(
+			ctx,
+			chain.GenesisData,
+			configData,
+			validators,
+			beacons,
+			vm,
+			fxs,
+			consensusParams,
+		)
+		if err != nil {
+			m.log.Error("error while creating new avalanche vm %s", err)
+			return
+		}
+	case smeng.ChainVM:
+		err := m.createSnowmanChain(
+			ctx,
+			chain.GenesisData,
+			configData,
+			validators,
+			beacons,
+			vm,
+			fxs,
+			consensusParams.Parameters,
+		)
+		if err != nil {
+			m.log.Error("error while creating new snowman vm %s", err)
+			return
+		}
+	default:
+		m.log.Error("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
 		return
 	}
 
+	// Associate the newly created chain with its default alias
+	m.log.AssertNoError(m.Alias(chain.ID, chain.ID.String()))
+
+	// Notify those that registered to be notified when a new chain is created
+	m.notifyRegistrants(ctx, vm)
+}
+
+// newVM creates the VM of [chain], and the feature extensions it runs
+func (m *manager) newVM(chain ChainParameters) (interface{}, []*common.Fx, error) {
+	vmID, err := m.vmManager.Lookup(chain.VMAlias)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while looking up VM: %w", err)
+	}
+
 	// Get a factory for the vm we want to use on our chain
 	vmFactory, err := m.vmManager.GetVMFactory(vmID)
 	if err != nil {
-		m.log.Error("error while getting vmFactory: %s", err)
-		return
+		return nil, nil, fmt.Errorf("error while getting vmFactory: %w", err)
 	}
 
 	// Create the chain
@@ -212,15 +385,13 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	for i, fxAlias := range chain.FxAliases {
 		fxID, err := m.vmManager.Lookup(fxAlias)
 		if err != nil {
-			m.log.Error("error while looking up Fx: %s", err)
-			return
+			return nil, nil, fmt.Errorf("error while looking up Fx: %w", err)
 		}
 
 		// Get a factory for the fx we want to use on our chain
 		fxFactory, err := m.vmManager.GetVMFactory(fxID)
 		if err != nil {
-			m.log.Error("error while getting fxFactory: %s", err)
-			return
+			return nil, nil, fmt.Errorf("error while getting fxFactory: %w", err)
 		}
 
 		// Create the fx
@@ -229,101 +400,147 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 			Fx: fxFactory.New(),
 		}
 	}
+	return vm, fxs, nil
+}
+
+// newContext returns the context [chain] runs with, the configuration its VM
+// is initialized with, and the registry of its metrics
+func (m *manager) newContext(chain ChainParameters) (*snow.Context, []byte, *prometheus.Registry, error) {
+	// An operator's configuration of the chain replaces the configuration it
+	// was created with
+	configData := chain.ConfigData
+	chainConfig, configured := m.chainConfig(chain.ID)
+	if configured && chainConfig.Config != nil {
+		m.log.Info("initializing chain %s with the configuration given to this node", chain.ID)
+		configData = chainConfig.Config
+	}
 
 	// Create the log and context of the chain
 	chainLog, err := m.logFactory.MakeChain(chain.ID, "")
 	if err != nil {
-		m.log.Error("error while creating chain's log %s", err)
-		return
+		return nil, nil, nil, fmt.Errorf("error while creating chain's log %w", err)
 	}
 
 	ctx := &snow.Context{
 		NetworkID:           m.networkID,
 		ChainID:             chain.ID,
+		SubnetID:            chain.SubnetID,
 		Log:                 chainLog,
 		DecisionDispatcher:  m.decisionEvents,
 		ConsensusDispatcher: m.consensusEvents,
 		NodeID:              m.nodeID,
 		HTTP:                m.server,
 		Keystore:            m.keystore.NewBlockchainKeyStore(chain.ID),
+		SharedMemory:        m.sharedMemory.NewBlockchainSharedMemory(chain.ID),
 		BCLookup:            m,
+		StateRetention:      m.stateRetention,
+		UpgradeBytes:        chainConfig.Upgrade,
+		Tracer:              m.tracer,
+		Uptimes:             m.uptimes,
+		Config:              snow.NewConfig(m.chainSettings, chainConfig.Settings),
 	}
-	consensusParams := m.consensusParams
+	// Each chain's metrics are registered with its own registry, gathered
+	// under the chain's namespace
+	namespace := fmt.Sprintf("gecko_%s", ctx.ChainID)
 	if alias, err := m.PrimaryAlias(ctx.ChainID); err == nil {
-		consensusParams.Namespace = fmt.Sprintf("gecko_%s", alias)
-	} else {
-		consensusParams.Namespace = fmt.Sprintf("gecko_%s", ctx.ChainID)
+		namespace = fmt.Sprintf("gecko_%s", alias)
+	}
+	registry := prometheus.NewRegistry()
+	if err := m.metrics.Register(namespace, registry); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to register the metrics of chain %s due to %w", ctx.ChainID, err)
 	}
+	return ctx, configData, registry, nil
+}
 
-	// The validators of this blockchain
-	validators, ok := m.validators.GetValidatorSet(ids.Empty) // TODO: Change argument to chain.SubnetID
-	if !ok {
-		m.log.Error("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
-		return
+// ReplayChain replays the containers of [source] through the VM of [chain],
+// without consensus. The VM runs on the chain's state in this node's
+// database, as it does when the chain is created.
+func (m *manager) ReplayChain(chain ChainParameters, source replay.Source) (replay.Result, error) {
+	vm, fxs, err := m.newVM(chain)
+	if err != nil {
+		return replay.Result{}, err
+	}
+	ctx, configData, registry, err := m.newContext(chain)
+	if err != nil {
+		return replay.Result{}, err
 	}
 
-	beacons := validators
-	if chain.CustomBeacons != nil {
-		beacons = chain.CustomBeacons
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	db, err := m.chainDB(ctx, registry)
+	if err != nil {
+		return replay.Result{}, err
 	}
+	vmDB := prefixdb.New([]byte("vm"), db)
+
+	// There's no engine for the VM to notify, so its messages are dropped
+	// once the channel is full
+	msgChan := make(chan common.Message, defaultChannelSize)
 
 	switch vm := vm.(type) {
 	case avalanche.DAGVM:
-		err := m.createAvalancheChain(
-			ctx,
-			chain.GenesisData,
-			validators,
-			beacons,
-			vm,
-			fxs,
-			consensusParams,
-		)
-		if err != nil {
-			m.log.Error("error while creating new avalanche vm %s", err)
-			return
+		if err := vm.Initialize(ctx, vmDB, chain.GenesisData, configData, msgChan, fxs); err != nil {
+			return replay.Result{}, err
 		}
+		defer vm.Shutdown()
+		return replay.Txs(ctx, vm, source)
 	case smeng.ChainVM:
-		err := m.createSnowmanChain(
-			ctx,
-			chain.GenesisData,
-			validators,
-			beacons,
-			vm,
-			fxs,
-			consensusParams.Parameters,
-		)
-		if err != nil {
-			m.log.Error("error while creating new snowman vm %s", err)
-			return
+		if err := vm.Initialize(ctx, vmDB, chain.GenesisData, configData, msgChan, fxs); err != nil {
+			return replay.Result{}, err
 		}
+		defer vm.Shutdown()
+		return replay.Blocks(ctx, vm, source)
 	default:
-		m.log.Error("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
-		return
+		return replay.Result{}, errUnknownVMType
 	}
+}
 
-	// Associate the newly created chain with its default alias
-	m.log.AssertNoError(m.Alias(chain.ID, chain.ID.String()))
-
-	// Notify those that registered to be notified when a new chain is created
-	m.notifyRegistrants(ctx, vm)
+// chainConfig returns the configuration an operator gave the chain [chainID],
+// under its ID or one of its aliases
+func (m *manager) chainConfig(chainID ids.ID) (ChainConfig, bool) {
+	for _, name := range append([]string{chainID.String()}, m.Aliases(chainID)...) {
+		if config, ok := m.chainConfigs[name]; ok {
+			return config, true
+		}
+	}
+	return ChainConfig{}, false
 }
 
 // Implements Manager.AddRegistrant
-func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
+func (m *manager) AddRegistrant(r Registrant) {
+	m.registrantsLock.Lock()
+	defer m.registrantsLock.Unlock()
 
+	m.registrants = append(m.registrants, r)
+}
+
+// unblockChains creates the chains requested before the platform chain
+// bootstrapped. Chains requested after this are created immediately.
 func (m *manager) unblockChains() {
+	m.unblockedLock.Lock()
 	m.unblocked = true
 	blocked := m.blockedChains
 	m.blockedChains = nil
+	m.unblockedLock.Unlock()
+
 	for _, chain := range blocked {
 		m.ForceCreateChain(chain)
 	}
 }
 
+// chainDB returns the database of the chain of [ctx]. Its usage is recorded in
+// metrics registered with [registerer], so each chain's share of the load on
+// the disk can be told apart.
+func (m *manager) chainDB(ctx *snow.Context, registerer prometheus.Registerer) (database.Database, error) {
+	return meterdb.New("db", registerer, prefixdb.New(ctx.ChainID.Bytes(), m.db))
+}
+
 // Create a DAG-based blockchain that uses Avalanche
 func (m *manager) createAvalancheChain(
 	ctx *snow.Context,
 	genesisData []byte,
+	configData []byte,
 	validators,
 	beacons validators.Set,
 	vm avalanche.DAGVM,
@@ -333,7 +550,10 @@ func (m *manager) createAvalancheChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	db := prefixdb.New(ctx.ChainID.Bytes(), m.db)
+	db, err := m.chainDB(ctx, consensusParams.Metrics)
+	if err != nil {
+		return err
+	}
 	vmDB := prefixdb.New([]byte("vm"), db)
 	vertexDB := prefixdb.New([]byte("vertex"), db)
 	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bootstrapping"), db)
@@ -348,11 +568,12 @@ func (m *manager) createAvalancheChain(
 		return err
 	}
 
-	// The channel through which a VM may send messages to the consensus engine
-	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	// Passes the messages the VM sends to the consensus engine, such as that a
+	// vertex is ready to be made
+	notifier := common.NewNotifier()
 
-	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
+	if err := vm.Initialize(ctx, vmDB, genesisData, configData, notifier.ToEngine(), fxs); err != nil {
+		notifier.Close()
 		return err
 	}
 
@@ -365,6 +586,15 @@ func (m *manager) createAvalancheChain(
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
 
+	// VMs that gossip their pending txs do so through the sender
+	if appVM, ok := vm.(common.AppVM); ok {
+		appVM.SetAppSender(&sender)
+	}
+
+	// VMs that serve their state to bootstrapping validators do so through
+	// the engine
+	stateSyncVM, _ := vm.(common.StateSyncableVM)
+
 	// The engine handles consensus
 	engine := avaeng.Transitive{
 		Config: avaeng.Config{
@@ -379,16 +609,19 @@ func (m *manager) createAvalancheChain(
 	engine.Initialize(avaeng.Config{
 		BootstrapConfig: avaeng.BootstrapConfig{
 			Config: common.Config{
-				Context:    ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
-				Sender:     &sender,
+				Context:     ctx,
+				Validators:  validators,
+				Beacons:     beacons,
+				Alpha:       (beacons.Len() + 1) / 2,
+				Sender:      &sender,
+				StateSyncVM: stateSyncVM,
+				StateSync:   m.stateSync,
 			},
-			VtxBlocked: vtxBlocker,
-			TxBlocked:  txBlocker,
-			State:      vtxState,
-			VM:         vm,
+			VtxBlocked:   vtxBlocker,
+			TxBlocked:    txBlocker,
+			State:        vtxState,
+			VM:           vm,
+			Bootstrapped: func() { m.markBootstrapped(ctx.ChainID) },
 		},
 		Params:    consensusParams,
 		Consensus: &avacon.Topological{},
@@ -396,11 +629,13 @@ func (m *manager) createAvalancheChain(
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	handler.Initialize(&engine, notifier.Messages(), defaultChannelSize, avalancheReadWorkers)
 
-	// Allows messages to be routed to the new chain
+	// Allows messages to be routed to the new chain. Queries are held
+	// until the chain finishes bootstrapping.
+	m.chainRouter.Bootstrapping(ctx.ChainID)
 	m.chainRouter.AddChain(handler)
-	go ctx.Log.RecoverAndPanic(handler.Dispatch)
+	go ctx.Log.RecoverAndPanic(func() { dispatch(handler, notifier) })
 
 	awaiting := &networking.AwaitingConnections{
 		Finish: func() {
@@ -423,6 +658,7 @@ func (m *manager) createAvalancheChain(
 func (m *manager) createSnowmanChain(
 	ctx *snow.Context,
 	genesisData []byte,
+	configData []byte,
 	validators,
 	beacons validators.Set,
 	vm smeng.ChainVM,
@@ -432,7 +668,10 @@ func (m *manager) createSnowmanChain(
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	db := prefixdb.New(ctx.ChainID.Bytes(), m.db)
+	db, err := m.chainDB(ctx, consensusParams.Metrics)
+	if err != nil {
+		return err
+	}
 	vmDB := prefixdb.New([]byte("vm"), db)
 	bootstrappingDB := prefixdb.New([]byte("bootstrapping"), db)
 
@@ -441,12 +680,13 @@ func (m *manager) createSnowmanChain(
 		return err
 	}
 
-	// The channel through which a VM may send messages to the consensus engine
-	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	// Passes the messages the VM sends to the consensus engine, such as that a
+	// block is ready to be made
+	notifier := common.NewNotifier()
 
 	// Initialize the VM
-	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
+	if err := vm.Initialize(ctx, vmDB, genesisData, configData, notifier.ToEngine(), fxs); err != nil {
+		notifier.Close()
 		return err
 	}
 
@@ -454,20 +694,34 @@ func (m *manager) createSnowmanChain(
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
 
+	// VMs that gossip their pending txs do so through the sender
+	if appVM, ok := vm.(common.AppVM); ok {
+		appVM.SetAppSender(&sender)
+	}
+
+	// VMs that serve their state to bootstrapping validators do so through
+	// the engine
+	stateSyncVM, _ := vm.(common.StateSyncableVM)
+
 	// The engine handles consensus
 	engine := smeng.Transitive{}
 	engine.Initialize(smeng.Config{
 		BootstrapConfig: smeng.BootstrapConfig{
 			Config: common.Config{
-				Context:    ctx,
-				Validators: validators,
-				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
-				Sender:     &sender,
+				Context:     ctx,
+				Validators:  validators,
+				Beacons:     beacons,
+				Alpha:       (beacons.Len() + 1) / 2,
+				Sender:      &sender,
+				StateSyncVM: stateSyncVM,
+				StateSync:   m.stateSync,
+			},
+			Blocked: blocked,
+			VM:      vm,
+			Bootstrapped: func() {
+				m.markBootstrapped(ctx.ChainID)
+				m.unblockChains()
 			},
-			Blocked:      blocked,
-			VM:           vm,
-			Bootstrapped: m.unblockChains,
 		},
 		Params:    consensusParams,
 		Consensus: &smcon.Topological{},
@@ -475,11 +729,13 @@ func (m *manager) createSnowmanChain(
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	handler.Initialize(&engine, notifier.Messages(), defaultChannelSize, defaultReadWorkers)
 
-	// Allow incoming messages to be routed to the new chain
+	// Allow incoming messages to be routed to the new chain. Queries are held
+	// until the chain finishes bootstrapping.
+	m.chainRouter.Bootstrapping(ctx.ChainID)
 	m.chainRouter.AddChain(handler)
-	go ctx.Log.RecoverAndPanic(handler.Dispatch)
+	go ctx.Log.RecoverAndPanic(func() { dispatch(handler, notifier) })
 
 	awaiting := &networking.AwaitingConnections{
 		Finish: func() {
@@ -497,16 +753,77 @@ func (m *manager) createSnowmanChain(
 	return nil
 }
 
+// dispatch passes messages to the engine of [h] until it's shut down, and
+// then closes [notifier], the VM's messages to that engine
+func dispatch(h *handler.Handler, notifier *common.Notifier) {
+	defer notifier.Close()
+	h.Dispatch()
+}
+
+// markBootstrapped records that the chain [chainID] finished bootstrapping
+func (m *manager) markBootstrapped(chainID ids.ID) {
+	m.bootstrappedLock.Lock()
+	defer m.bootstrappedLock.Unlock()
+
+	m.bootstrapped.Add(chainID)
+	m.chainRouter.Bootstrapped(chainID)
+}
+
+// IsBootstrapped returns true if the chain [chainID] finished bootstrapping
+func (m *manager) IsBootstrapped(chainID ids.ID) bool {
+	m.bootstrappedLock.RLock()
+	defer m.bootstrappedLock.RUnlock()
+
+	return m.bootstrapped.Contains(chainID)
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() { m.chainRouter.Shutdown() }
 
+// Lookup returns the ID of the chain associated with an alias
+func (m *manager) Lookup(alias string) (ids.ID, error) {
+	m.aliasLock.RLock()
+	defer m.aliasLock.RUnlock()
+
+	return m.Aliaser.Lookup(alias)
+}
+
+// PrimaryAlias returns the first alias of the chain [chainID]
+func (m *manager) PrimaryAlias(chainID ids.ID) (string, error) {
+	m.aliasLock.RLock()
+	defer m.aliasLock.RUnlock()
+
+	return m.Aliaser.PrimaryAlias(chainID)
+}
+
+// Aliases returns the aliases of the chain [chainID]
+func (m *manager) Aliases(chainID ids.ID) []string {
+	m.aliasLock.RLock()
+	defer m.aliasLock.RUnlock()
+
+	aliases := m.Aliaser.Aliases(chainID)
+	return append([]string(nil), aliases...)
+}
+
+// Alias gives the chain [chainID] the alias [alias]
+func (m *manager) Alias(chainID ids.ID, alias string) error {
+	m.aliasLock.Lock()
+	defer m.aliasLock.Unlock()
+
+	return m.Aliaser.Alias(chainID, alias)
+}
+
 // LookupVM returns the ID of the VM associated with an alias
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.vmManager.Lookup(alias) }
 
 // Notify registrants [those who want to know about the creation of chains]
 // that the specified chain has been created
 func (m *manager) notifyRegistrants(ctx *snow.Context, vm interface{}) {
-	for _, registrant := range m.registrants {
+	m.registrantsLock.RLock()
+	registrants := m.registrants
+	m.registrantsLock.RUnlock()
+
+	for _, registrant := range registrants {
 		registrant.RegisterChain(ctx, vm)
 	}
 }
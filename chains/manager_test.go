@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/avalanche/state"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+
+	avaeng "github.com/ava-labs/gecko/snow/engine/avalanche"
+)
+
+// Gets for more vertices than the serializer keeps unique are served by an
+// avalanche chain's handler from many goroutines at once. Run with -race, this
+// fails if the engine fetches vertices concurrently.
+func TestAvalancheConcurrentGets(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+
+	vm := &avaeng.VMTest{}
+	vm.T = t
+	vm.Default(true)
+	vm.ShutdownF = func() {}
+
+	vtxState := &state.Serializer{}
+	vtxState.Initialize(ctx, vm, memdb.New())
+
+	// Each vertex is the child of the last, so they're all different
+	numVtxs := 1500
+	vtxIDs := make([]ids.ID, numVtxs)
+	parents := ids.Set{}
+	for i := range vtxIDs {
+		vtx, err := vtxState.BuildVertex(parents, []snowstorm.Tx{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		vtxIDs[i] = vtx.ID()
+		parents = ids.Set{}
+		parents.Add(vtx.ID())
+	}
+
+	putLock := sync.Mutex{}
+	numPuts := 0
+	sender := &common.SenderTest{}
+	sender.T = t
+	sender.Default(true)
+	sender.PutF = func(_ ids.ShortID, _ uint32, _ ids.ID, _ []byte) {
+		putLock.Lock()
+		defer putLock.Unlock()
+
+		numPuts++
+	}
+
+	engine := avaeng.Transitive{}
+	engine.Config = avaeng.Config{
+		BootstrapConfig: avaeng.BootstrapConfig{
+			Config: common.Config{
+				Context: ctx,
+				Sender:  sender,
+			},
+			State: vtxState,
+			VM:    vm,
+		},
+	}
+
+	numRequesters := 4
+	handler := handler.Handler{}
+	handler.Initialize(&engine, nil, numRequesters*numVtxs, avalancheReadWorkers)
+	go handler.Dispatch()
+
+	// Each vertex is requested by every requester, at about the same time
+	requesters := sync.WaitGroup{}
+	requesters.Add(numRequesters)
+	for i := 0; i < numRequesters; i++ {
+		go func() {
+			defer requesters.Done()
+
+			for j := 0; j < numVtxs; j++ {
+				handler.Get(ids.ShortEmpty, uint32(j), vtxIDs[j])
+			}
+		}()
+	}
+	requesters.Wait()
+	handler.Shutdown()
+
+	if numPuts != numRequesters*numVtxs {
+		t.Fatalf("Should have sent %d vertices, sent %d", numRequesters*numVtxs, numPuts)
+	}
+}
+
+// Once a chain's handler shuts its engine down, the notifier that passed the
+// VM's messages to that engine is closed, so it doesn't leak its goroutine
+func TestDispatchClosesNotifier(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+
+	engine := &common.EngineTest{}
+	engine.T = t
+	engine.Default(true)
+	engine.ContextF = func() *snow.Context { return ctx }
+	engine.ShutdownF = func() {}
+
+	notifier := common.NewNotifier()
+	h := &handler.Handler{}
+	h.Initialize(engine, notifier.Messages(), 1, 0)
+
+	done := make(chan struct{})
+	go func() {
+		dispatch(h, notifier)
+		close(done)
+	}()
+	h.Shutdown()
+	<-done
+
+	select {
+	case _, ok := <-notifier.Messages():
+		if ok {
+			t.Fatalf("The notifier shouldn't have sent a message")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("The notifier should have been closed when the engine was shut down")
+	}
+}
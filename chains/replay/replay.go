@@ -0,0 +1,163 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package replay replays the containers a chain accepted through the chain's
+// VM, without consensus. Each container is verified and accepted as it was
+// when the chain accepted it, and the VM's state is checked against the
+// container after it's accepted. Replaying a chain's history checks that a
+// database migration or a new version of a VM reaches the state the chain
+// reached, without running a node on a network.
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// How many containers are replayed between reports of the replay's progress
+const logInterval = 10000
+
+var errNoContainers = errors.New("the source has no containers")
+
+// Result of a replay
+type Result struct {
+	// Number of containers the replay accepted
+	Accepted int
+	// Number of containers that were already accepted, such as by an earlier
+	// replay
+	Skipped int
+	// ID of the last container of the source
+	LastAccepted ids.ID
+}
+
+// Blocks replays the blocks of [source] through [vm], which must be
+// initialized. Each block must be the child of the one before it. Returns an
+// error once a block isn't accepted as it was by the chain, or the VM's last
+// accepted block isn't the block that was just replayed.
+func Blocks(ctx *snow.Context, vm snowman.ChainVM, source Source) (Result, error) {
+	result := Result{}
+	for {
+		container, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		blk, err := vm.ParseBlock(container.Bytes.Bytes)
+		if err != nil {
+			return result, fmt.Errorf("couldn't parse block %s: %w", container.ID, err)
+		}
+		blkID := blk.ID()
+		if !blkID.Equals(container.ID) {
+			return result, fmt.Errorf("block %s was parsed as block %s", container.ID, blkID)
+		}
+
+		switch status := blk.Status(); status {
+		case choices.Accepted:
+			result.Skipped++
+		case choices.Processing:
+			if lastAccepted := vm.LastAccepted(); !blk.Parent().ID().Equals(lastAccepted) {
+				return result, fmt.Errorf("block %s isn't the child of the last accepted block %s", blkID, lastAccepted)
+			}
+			if err := blk.Verify(); err != nil {
+				return result, fmt.Errorf("block %s failed verification: %w", blkID, err)
+			}
+			// Replayed blocks are dispatched as if they were accepted by
+			// consensus
+			bytes := blk.Bytes()
+			ctx.DecisionDispatcher.Accept(ctx.ChainID, blkID, bytes)
+			ctx.ConsensusDispatcher.Accept(ctx.ChainID, blkID, bytes)
+			blk.Accept()
+			if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blkID) {
+				return result, fmt.Errorf("the last accepted block is %s after accepting block %s", lastAccepted, blkID)
+			}
+			result.Accepted++
+		default:
+			return result, fmt.Errorf("block %s has status %s", blkID, status)
+		}
+
+		result.LastAccepted = blkID
+		logProgress(ctx, &result)
+	}
+
+	if result.LastAccepted.IsZero() {
+		return result, errNoContainers
+	}
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(result.LastAccepted) {
+		return result, fmt.Errorf("the last accepted block is %s, but the last replayed block is %s", lastAccepted, result.LastAccepted)
+	}
+	return result, nil
+}
+
+// Txs replays the transactions of [source] through [vm], which must be
+// initialized. Each transaction's dependencies must have been accepted before
+// it. Returns an error once a transaction isn't accepted as it was by the
+// chain, or the VM doesn't report it as accepted once it's replayed.
+func Txs(ctx *snow.Context, vm avalanche.DAGVM, source Source) (Result, error) {
+	result := Result{}
+	for {
+		container, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		tx, err := vm.ParseTx(container.Bytes.Bytes)
+		if err != nil {
+			return result, fmt.Errorf("couldn't parse transaction %s: %w", container.ID, err)
+		}
+		txID := tx.ID()
+		if !txID.Equals(container.ID) {
+			return result, fmt.Errorf("transaction %s was parsed as transaction %s", container.ID, txID)
+		}
+
+		switch status := tx.Status(); status {
+		case choices.Accepted:
+			result.Skipped++
+		case choices.Processing:
+			for _, dep := range tx.Dependencies() {
+				if dep.Status() != choices.Accepted {
+					return result, fmt.Errorf("transaction %s depends on transaction %s, which wasn't accepted", txID, dep.ID())
+				}
+			}
+			if err := tx.Verify(); err != nil {
+				return result, fmt.Errorf("transaction %s failed verification: %w", txID, err)
+			}
+			// Replayed transactions are dispatched as if they were accepted
+			// by consensus
+			ctx.DecisionDispatcher.Accept(ctx.ChainID, txID, tx.Bytes())
+			tx.Accept()
+			if stored, err := vm.GetTx(txID); err != nil || stored.Status() != choices.Accepted {
+				return result, fmt.Errorf("transaction %s wasn't stored as accepted", txID)
+			}
+			result.Accepted++
+		default:
+			return result, fmt.Errorf("transaction %s has status %s", txID, status)
+		}
+
+		result.LastAccepted = txID
+		logProgress(ctx, &result)
+	}
+
+	if result.LastAccepted.IsZero() {
+		return result, errNoContainers
+	}
+	return result, nil
+}
+
+func logProgress(ctx *snow.Context, result *Result) {
+	if replayed := result.Accepted + result.Skipped; replayed%logInterval == 0 {
+		ctx.Log.Info("replayed %d containers, up to %s", replayed, result.LastAccepted)
+	}
+}
@@ -0,0 +1,246 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+	"github.com/ava-labs/gecko/utils/formatting"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// testSource returns the containers it was given
+type testSource struct{ containers []Container }
+
+func (s *testSource) Next() (Container, error) {
+	if len(s.containers) == 0 {
+		return Container{}, io.EOF
+	}
+	container := s.containers[0]
+	s.containers = s.containers[1:]
+	return container, nil
+}
+
+func (s *testSource) Close() error { return nil }
+
+type testBlock struct {
+	parent    snowman.Block
+	id        ids.ID
+	status    choices.Status
+	verifyErr error
+	bytes     []byte
+}
+
+func (b *testBlock) ID() ids.ID             { return b.id }
+func (b *testBlock) Parent() snowman.Block  { return b.parent }
+func (b *testBlock) Accept()                { b.status = choices.Accepted }
+func (b *testBlock) Reject()                { b.status = choices.Rejected }
+func (b *testBlock) Status() choices.Status { return b.status }
+func (b *testBlock) Verify() error          { return b.verifyErr }
+func (b *testBlock) Bytes() []byte          { return b.bytes }
+
+// newTestChain returns a VM with [numBlocks] blocks after its genesis block,
+// and the source of those blocks
+func newTestChain(t *testing.T, numBlocks int) (*smeng.VMTest, []*testBlock, *testSource) {
+	genesis := &testBlock{
+		id:     ids.Empty.Prefix(0),
+		status: choices.Accepted,
+	}
+	blks := []*testBlock{genesis}
+	source := &testSource{}
+	for i := 1; i <= numBlocks; i++ {
+		blk := &testBlock{
+			parent: blks[i-1],
+			id:     ids.Empty.Prefix(uint64(i)),
+			status: choices.Processing,
+			bytes:  []byte{byte(i)},
+		}
+		blks = append(blks, blk)
+		source.containers = append(source.containers, Container{
+			ID:    blk.id,
+			Bytes: formatting.CB58{Bytes: blk.bytes},
+		})
+	}
+
+	vm := &smeng.VMTest{}
+	vm.T = t
+	vm.Default(true)
+	vm.ParseBlockF = func(b []byte) (snowman.Block, error) {
+		if len(b) != 1 || int(b[0]) >= len(blks) {
+			return nil, errors.New("unknown block")
+		}
+		return blks[b[0]], nil
+	}
+	vm.LastAcceptedF = func() ids.ID {
+		lastAccepted := genesis.id
+		for _, blk := range blks {
+			if blk.status == choices.Accepted {
+				lastAccepted = blk.id
+			}
+		}
+		return lastAccepted
+	}
+	return vm, blks, source
+}
+
+func TestBlocks(t *testing.T) {
+	vm, blks, source := newTestChain(t, 3)
+	// The first block was accepted by an earlier replay
+	blks[1].status = choices.Accepted
+
+	result, err := Blocks(snow.DefaultContextTest(), vm, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case result.Accepted != 2:
+		t.Fatalf("Should have accepted 2 blocks but accepted %d", result.Accepted)
+	case result.Skipped != 1:
+		t.Fatalf("Should have skipped 1 block but skipped %d", result.Skipped)
+	case !result.LastAccepted.Equals(blks[3].id):
+		t.Fatalf("Last accepted block should be %s but is %s", blks[3].id, result.LastAccepted)
+	}
+	for _, blk := range blks {
+		if blk.status != choices.Accepted {
+			t.Fatalf("Block %s should have been accepted", blk.id)
+		}
+	}
+}
+
+func TestBlocksVerifyFails(t *testing.T) {
+	vm, blks, source := newTestChain(t, 3)
+	blks[2].verifyErr = errors.New("invalid block")
+
+	result, err := Blocks(snow.DefaultContextTest(), vm, source)
+	if err == nil {
+		t.Fatalf("Should have errored on the invalid block")
+	}
+	if result.Accepted != 1 || blks[3].status != choices.Processing {
+		t.Fatalf("Should have stopped replaying at the invalid block")
+	}
+}
+
+func TestBlocksIDMismatch(t *testing.T) {
+	vm, _, source := newTestChain(t, 2)
+	source.containers[1].ID = ids.Empty.Prefix(100)
+
+	if _, err := Blocks(snow.DefaultContextTest(), vm, source); err == nil {
+		t.Fatalf("Should have errored because the block's ID doesn't match the source's")
+	}
+}
+
+func TestBlocksNotChild(t *testing.T) {
+	vm, _, source := newTestChain(t, 3)
+	// The second block is missing from the source
+	source.containers = append(source.containers[:1], source.containers[2:]...)
+
+	if _, err := Blocks(snow.DefaultContextTest(), vm, source); err == nil {
+		t.Fatalf("Should have errored because a block isn't the child of the last accepted block")
+	}
+}
+
+func TestBlocksLastAcceptedMismatch(t *testing.T) {
+	vm, blks, source := newTestChain(t, 2)
+	// The VM doesn't record that it accepted blocks
+	vm.LastAcceptedF = func() ids.ID { return blks[0].id }
+
+	if _, err := Blocks(snow.DefaultContextTest(), vm, source); err == nil {
+		t.Fatalf("Should have errored because the VM's last accepted block didn't change")
+	}
+}
+
+func TestBlocksNoContainers(t *testing.T) {
+	vm, _, _ := newTestChain(t, 0)
+
+	if _, err := Blocks(snow.DefaultContextTest(), vm, &testSource{}); err != errNoContainers {
+		t.Fatalf("Should have errored with %v but got %v", errNoContainers, err)
+	}
+}
+
+// newTestDAG returns a VM with [numTxs] transactions, each spending the
+// transaction before it, and the source of those transactions
+func newTestDAG(t *testing.T, numTxs int) (*avalanche.VMTest, []*snowstorm.TestTx, *testSource) {
+	txs := []*snowstorm.TestTx{}
+	source := &testSource{}
+	for i := 0; i < numTxs; i++ {
+		tx := &snowstorm.TestTx{
+			Identifier: ids.Empty.Prefix(uint64(i)),
+			Stat:       choices.Processing,
+			Bits:       []byte{byte(i)},
+		}
+		if i > 0 {
+			tx.Deps = []snowstorm.Tx{txs[i-1]}
+		}
+		txs = append(txs, tx)
+		source.containers = append(source.containers, Container{
+			ID:    tx.Identifier,
+			Bytes: formatting.CB58{Bytes: tx.Bits},
+		})
+	}
+
+	vm := &avalanche.VMTest{}
+	vm.T = t
+	vm.Default(true)
+	vm.ParseTxF = func(b []byte) (snowstorm.Tx, error) {
+		if len(b) != 1 || int(b[0]) >= len(txs) {
+			return nil, errors.New("unknown transaction")
+		}
+		return txs[b[0]], nil
+	}
+	vm.GetTxF = func(txID ids.ID) (snowstorm.Tx, error) {
+		for _, tx := range txs {
+			if tx.Identifier.Equals(txID) {
+				return tx, nil
+			}
+		}
+		return nil, errors.New("unknown transaction")
+	}
+	return vm, txs, source
+}
+
+func TestTxs(t *testing.T) {
+	vm, txs, source := newTestDAG(t, 3)
+	txs[0].Stat = choices.Accepted
+
+	result, err := Txs(snow.DefaultContextTest(), vm, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case result.Accepted != 2:
+		t.Fatalf("Should have accepted 2 transactions but accepted %d", result.Accepted)
+	case result.Skipped != 1:
+		t.Fatalf("Should have skipped 1 transaction but skipped %d", result.Skipped)
+	case !result.LastAccepted.Equals(txs[2].Identifier):
+		t.Fatalf("Last accepted transaction should be %s but is %s", txs[2].Identifier, result.LastAccepted)
+	}
+}
+
+func TestTxsMissingDependency(t *testing.T) {
+	vm, _, source := newTestDAG(t, 3)
+	// The first transaction is missing from the source
+	source.containers = source.containers[1:]
+
+	if _, err := Txs(snow.DefaultContextTest(), vm, source); err == nil {
+		t.Fatalf("Should have errored because a dependency wasn't accepted")
+	}
+}
+
+func TestTxsRejected(t *testing.T) {
+	vm, txs, source := newTestDAG(t, 2)
+	txs[1].Stat = choices.Rejected
+
+	if _, err := Txs(snow.DefaultContextTest(), vm, source); err == nil {
+		t.Fatalf("Should have errored because a transaction was rejected")
+	}
+}
@@ -0,0 +1,165 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// Number of containers fetched by each call to an index API. It's the
+	// most the API returns.
+	pageSize = 1024
+
+	// How long a call to an index API may take
+	callTimeout = 30 * time.Second
+)
+
+// Container is a block or transaction accepted on the chain being replayed
+type Container struct {
+	ID    ids.ID          `json:"id"`
+	Bytes formatting.CB58 `json:"bytes"`
+}
+
+// Source of the containers a chain accepted, in the order it accepted them
+type Source interface {
+	// Next returns the next container. Returns io.EOF once there are no more.
+	Next() (Container, error)
+
+	// Close the source
+	Close() error
+}
+
+// NewSource returns the source at [location]. If it's a URL, it's the index
+// API of another node, such as http://127.0.0.1:9650/ext/index/X/tx.
+// Otherwise, it's the path of an archive.
+func NewSource(location string) (Source, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return NewIndexSource(location), nil
+	}
+	file, err := os.Open(location)
+	if err != nil {
+		return nil, err
+	}
+	return NewArchiveSource(file), nil
+}
+
+// archive is a source that reads its containers from a stream. The containers
+// are JSON objects with the container's ID and CB58 encoded bytes, as returned
+// by the index API, one after the other.
+type archive struct {
+	r       io.ReadCloser
+	decoder *json.Decoder
+}
+
+// NewArchiveSource returns a source that reads the archive [r]
+func NewArchiveSource(r io.ReadCloser) Source {
+	return &archive{
+		r:       r,
+		decoder: json.NewDecoder(r),
+	}
+}
+
+func (a *archive) Next() (Container, error) {
+	container := Container{}
+	err := a.decoder.Decode(&container)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("couldn't read the archive: %w", err)
+	}
+	return container, err
+}
+
+func (a *archive) Close() error { return a.r.Close() }
+
+// index is a source that fetches its containers from another node's index
+// API, a page at a time
+type index struct {
+	uri  string
+	http http.Client
+
+	next    uint64
+	page    []Container
+	drained bool
+}
+
+// NewIndexSource returns a source that fetches the containers of the index
+// served at [uri], starting with the first container it indexed
+func NewIndexSource(uri string) Source {
+	return &index{
+		uri:  uri,
+		http: http.Client{Timeout: callTimeout},
+	}
+}
+
+func (i *index) Next() (Container, error) {
+	if len(i.page) == 0 && !i.drained {
+		if err := i.fetch(); err != nil {
+			return Container{}, err
+		}
+	}
+	if len(i.page) == 0 {
+		return Container{}, io.EOF
+	}
+	container := i.page[0]
+	i.page = i.page[1:]
+	return container, nil
+}
+
+// fetch the next page of containers
+func (i *index) fetch() error {
+	args := struct {
+		StartIndex cjson.Uint64 `json:"startIndex"`
+		NumToFetch cjson.Uint64 `json:"numToFetch"`
+	}{
+		StartIndex: cjson.Uint64(i.next),
+		NumToFetch: pageSize,
+	}
+	reply := struct {
+		Containers []Container `json:"containers"`
+	}{}
+
+	body, err := json2.EncodeClientRequest("index.getContainerRange", &args)
+	if err != nil {
+		return err
+	}
+	resp, err := i.http.Post(i.uri, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("couldn't fetch containers from %s: %w", i.uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", i.uri, resp.StatusCode)
+	}
+	if err := json2.DecodeClientResponse(resp.Body, &reply); err != nil {
+		// The index errors when no containers were indexed at or after the
+		// start index
+		if _, ok := err.(*json2.Error); ok && i.next != 0 {
+			i.drained = true
+			return nil
+		}
+		return fmt.Errorf("couldn't fetch containers from %s: %w", i.uri, err)
+	}
+
+	i.page = reply.Containers
+	i.next += uint64(len(reply.Containers))
+	i.drained = len(reply.Containers) < pageSize
+	return nil
+}
+
+func (i *index) Close() error { return nil }
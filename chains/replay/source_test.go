@@ -0,0 +1,156 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+// TestIndex serves containers as the index API does
+type TestIndex struct{ containers []Container }
+
+// TestRangeArgs are the arguments of GetContainerRange
+type TestRangeArgs struct {
+	StartIndex json.Uint64 `json:"startIndex"`
+	NumToFetch json.Uint64 `json:"numToFetch"`
+}
+
+// TestRangeReply is the result of GetContainerRange
+type TestRangeReply struct {
+	Containers []Container `json:"containers"`
+}
+
+func (i *TestIndex) GetContainerRange(_ *http.Request, args *TestRangeArgs, reply *TestRangeReply) error {
+	start, end := int(args.StartIndex), int(args.StartIndex+args.NumToFetch)
+	if start >= len(i.containers) {
+		return fmt.Errorf("no container has index %d", start)
+	}
+	if end > len(i.containers) {
+		end = len(i.containers)
+	}
+	reply.Containers = i.containers[start:end]
+	return nil
+}
+
+func testContainers(n int) []Container {
+	containers := []Container{}
+	for i := 0; i < n; i++ {
+		containers = append(containers, Container{
+			ID:    ids.Empty.Prefix(uint64(i)),
+			Bytes: formatting.CB58{Bytes: []byte{byte(i), byte(i >> 8)}},
+		})
+	}
+	return containers
+}
+
+// readAll returns the containers of [source]
+func readAll(source Source) ([]Container, error) {
+	containers := []Container{}
+	for {
+		container, err := source.Next()
+		if err == io.EOF {
+			return containers, nil
+		}
+		if err != nil {
+			return containers, err
+		}
+		containers = append(containers, container)
+	}
+}
+
+func checkContainers(t *testing.T, expected, result []Container) {
+	if len(result) != len(expected) {
+		t.Fatalf("Should have read %d containers but read %d", len(expected), len(result))
+	}
+	for i, container := range result {
+		if !container.ID.Equals(expected[i].ID) || string(container.Bytes.Bytes) != string(expected[i].Bytes.Bytes) {
+			t.Fatalf("Container %d should be %s but is %s", i, expected[i].ID, container.ID)
+		}
+	}
+}
+
+func TestArchiveSource(t *testing.T) {
+	expected := testContainers(3)
+	archive := ""
+	for _, container := range expected {
+		archive += fmt.Sprintf("{\"id\":\"%s\",\"bytes\":\"%s\"}\n", container.ID, container.Bytes)
+	}
+
+	source := NewArchiveSource(ioutil.NopCloser(strings.NewReader(archive)))
+	result, err := readAll(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkContainers(t, expected, result)
+
+	source = NewArchiveSource(ioutil.NopCloser(strings.NewReader(archive + "{\"id\":")))
+	if _, err := readAll(source); err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("Should have errored on the truncated container, but got %v", err)
+	}
+}
+
+func TestIndexSource(t *testing.T) {
+	// The containers span more than one page
+	expected := testContainers(pageSize + 10)
+
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	if err := server.RegisterService(&TestIndex{containers: expected}, "index"); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	source, err := NewSource(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := readAll(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkContainers(t, expected, result)
+
+	// A whole number of pages is read until the index errors on the next page
+	server = rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	if err := server.RegisterService(&TestIndex{containers: expected[:pageSize]}, "index"); err != nil {
+		t.Fatal(err)
+	}
+	httpServer = httptest.NewServer(server)
+	defer httpServer.Close()
+
+	result, err = readAll(NewIndexSource(httpServer.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkContainers(t, expected[:pageSize], result)
+}
+
+func TestIndexSourceEmpty(t *testing.T) {
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	if err := server.RegisterService(&TestIndex{}, "index"); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	if _, err := readAll(NewIndexSource(httpServer.URL)); err == nil {
+		t.Fatalf("Should have errored because the index is empty")
+	}
+}
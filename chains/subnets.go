@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"github.com/ava-labs/gecko/ids"
+)
+
+// SubnetTracker tracks the validators of the subnets this node validates
+type SubnetTracker interface {
+	// TrackSubnet starts tracking the validators of the subnet [subnetID],
+	// which this node just started validating
+	TrackSubnet(subnetID ids.ID) error
+}
+
+// Validates returns true if this node validates the subnet [subnetID]
+func (m *manager) Validates(subnetID ids.ID) bool {
+	m.subnetsLock.RLock()
+	defer m.subnetsLock.RUnlock()
+
+	return m.validatedSubnets.Contains(subnetID)
+}
+
+// WhitelistSubnet starts validating the subnet [subnetID]. Its validators are
+// tracked, and the chains of the subnet that were skipped are created.
+// Returns false if this node already validates the subnet.
+func (m *manager) WhitelistSubnet(subnetID ids.ID) bool {
+	m.subnetsLock.Lock()
+	if m.validatedSubnets.Contains(subnetID) {
+		m.subnetsLock.Unlock()
+		return false
+	}
+	m.validatedSubnets.Add(subnetID)
+	skipped := m.skippedChains[subnetID.Key()]
+	delete(m.skippedChains, subnetID.Key())
+	trackers := m.subnetTrackers
+	m.subnetsLock.Unlock()
+
+	m.log.Info("whitelisted subnet %s", subnetID)
+	for _, tracker := range trackers {
+		if err := tracker.TrackSubnet(subnetID); err != nil {
+			m.log.Warn("failed to track the validators of subnet %s: %s", subnetID, err)
+		}
+	}
+	for _, chain := range skipped {
+		m.CreateChain(chain)
+	}
+	return true
+}
+
+// AddSubnetTracker adds [tracker] to the trackers notified when a subnet is
+// whitelisted
+func (m *manager) AddSubnetTracker(tracker SubnetTracker) {
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	m.subnetTrackers = append(m.subnetTrackers, tracker)
+}
+
+// skipUnvalidated returns true if [chain] isn't created because this node
+// doesn't validate its subnet. The chain is created if the subnet is
+// whitelisted later.
+func (m *manager) skipUnvalidated(chain ChainParameters) bool {
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	if m.validatedSubnets.Contains(chain.SubnetID) {
+		return false
+	}
+	key := chain.SubnetID.Key()
+	m.skippedChains[key] = append(m.skippedChains[key], chain)
+	m.log.Info("skipping creation of chain %s as this node doesn't validate subnet %s", chain.ID, chain.SubnetID)
+	return true
+}
@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testSubnetTracker struct{ tracked []ids.ID }
+
+func (t *testSubnetTracker) TrackSubnet(subnetID ids.ID) error {
+	t.tracked = append(t.tracked, subnetID)
+	return nil
+}
+
+func TestWhitelistSubnet(t *testing.T) {
+	defaultSubnetID := ids.Empty
+	subnetID := ids.NewID([32]byte{1})
+
+	m := &manager{
+		log:           logging.NoLog{},
+		skippedChains: make(map[[32]byte][]ChainParameters),
+	}
+	m.validatedSubnets.Add(defaultSubnetID)
+	tracker := &testSubnetTracker{}
+	m.AddSubnetTracker(tracker)
+
+	// Chains are queued until the platform chain bootstraps, unless they're
+	// skipped
+	m.CreateChain(ChainParameters{ID: ids.NewID([32]byte{2}), SubnetID: defaultSubnetID})
+	m.CreateChain(ChainParameters{ID: ids.NewID([32]byte{3}), SubnetID: subnetID})
+	if len(m.blockedChains) != 1 {
+		t.Fatalf("Only the chain of the default subnet should have been created")
+	}
+	if m.Validates(subnetID) {
+		t.Fatalf("The subnet shouldn't be validated before it's whitelisted")
+	}
+
+	if !m.WhitelistSubnet(subnetID) {
+		t.Fatalf("Should have whitelisted the subnet")
+	}
+	switch {
+	case !m.Validates(subnetID):
+		t.Fatalf("The subnet should be validated once it's whitelisted")
+	case len(tracker.tracked) != 1 || !tracker.tracked[0].Equals(subnetID):
+		t.Fatalf("The subnet's validators should have been tracked")
+	case len(m.blockedChains) != 2 || !m.blockedChains[1].ID.Equals(ids.NewID([32]byte{3})):
+		t.Fatalf("The skipped chain should have been created")
+	}
+
+	if m.WhitelistSubnet(subnetID) {
+		t.Fatalf("The subnet was already whitelisted")
+	}
+	if len(tracker.tracked) != 1 {
+		t.Fatalf("The subnet's validators shouldn't be tracked again")
+	}
+}
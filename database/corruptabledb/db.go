@@ -0,0 +1,248 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package corruptabledb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/nodb"
+)
+
+// ErrInjected is returned by the calls that are made to fail
+var ErrInjected = errors.New("injected failure")
+
+// Database wraps a database and makes the calls to it fail once told to. It's
+// meant to be used in tests, to simulate a failing disk or a node that's killed
+// part way through writing its state.
+type Database struct {
+	lock sync.Mutex
+	db   database.Database
+
+	// Number of writes that succeed before writes start failing. Negative if
+	// writes don't fail.
+	writesLeft int
+	// If true, the batch whose write fails is partially written
+	tearBatches bool
+	// If true, reads fail
+	failReads bool
+	// True once a write has failed
+	failed bool
+}
+
+// New returns a new database that passes every call through to [db] until
+// it's told to fail them
+func New(db database.Database) *Database {
+	return &Database{
+		db:         db,
+		writesLeft: -1,
+	}
+}
+
+// FailWritesAfter makes writes fail once [n] more have succeeded, as if the
+// node were killed at that point. Each Put, Delete and batch write is a write.
+func (db *Database) FailWritesAfter(n int) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writesLeft = n
+}
+
+// TearBatches sets whether the batch whose write fails has the first half of
+// its operations written before it fails. It simulates a database that doesn't
+// write batches atomically.
+func (db *Database) TearBatches(tear bool) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.tearBatches = tear
+}
+
+// FailReads sets whether reads, including iteration, fail
+func (db *Database) FailReads(fail bool) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.failReads = fail
+}
+
+// Failed returns true if a write has failed
+func (db *Database) Failed() bool {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.failed
+}
+
+// write returns whether the next write should fail
+// Assumes the lock is held
+func (db *Database) write() bool {
+	switch {
+	case db.writesLeft < 0:
+		return false
+	case db.writesLeft == 0:
+		db.failed = true
+		return true
+	default:
+		db.writesLeft--
+		return false
+	}
+}
+
+// readFailed returns true if reads should fail
+func (db *Database) readFailed() bool {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.failReads
+}
+
+// Has implements the Database interface
+func (db *Database) Has(key []byte) (bool, error) {
+	if db.readFailed() {
+		return false, ErrInjected
+	}
+	return db.db.Has(key)
+}
+
+// Get implements the Database interface
+func (db *Database) Get(key []byte) ([]byte, error) {
+	if db.readFailed() {
+		return nil, ErrInjected
+	}
+	return db.db.Get(key)
+}
+
+// Put implements the Database interface
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.write() {
+		return ErrInjected
+	}
+	return db.db.Put(key, value)
+}
+
+// Delete implements the Database interface
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.write() {
+		return ErrInjected
+	}
+	return db.db.Delete(key)
+}
+
+// NewBatch implements the Database interface
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+// NewIterator implements the Database interface
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if db.readFailed() {
+		return &nodb.Iterator{Err: ErrInjected}
+	}
+	return db.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the Database interface
+func (db *Database) Stat(stat string) (string, error) { return db.db.Stat(stat) }
+
+// Compact implements the Database interface
+func (db *Database) Compact(start, limit []byte) error { return db.db.Compact(start, limit) }
+
+// Close implements the Database interface
+func (db *Database) Close() error { return db.db.Close() }
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch records its operations, so that it can be partially written
+type batch struct {
+	database.Batch
+
+	db     *Database
+	writes []keyValue
+}
+
+// Put implements the Batch interface
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), copyBytes(value), false})
+	return b.Batch.Put(key, value)
+}
+
+// Delete implements the Batch interface
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), nil, true})
+	return b.Batch.Delete(key)
+}
+
+// Write implements the Batch interface
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if !b.db.write() {
+		return b.Batch.Write()
+	}
+	if !b.db.tearBatches {
+		return ErrInjected
+	}
+
+	torn := b.db.db.NewBatch()
+	for _, kv := range b.writes[:len(b.writes)/2] {
+		if kv.delete {
+			if err := torn.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := torn.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	if err := torn.Write(); err != nil {
+		return err
+	}
+	return ErrInjected
+}
+
+// Reset implements the Batch interface
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.Batch.Reset()
+}
+
+// Inner returns itself, so that writes made through wrapping batches are
+// counted
+func (b *batch) Inner() database.Batch { return b }
+
+func copyBytes(bytes []byte) []byte {
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package corruptabledb
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		test(t, New(memdb.New()))
+	}
+}
+
+func TestFailWritesAfter(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+	db.FailWritesAfter(2)
+
+	if err := db.Put([]byte("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if db.Failed() {
+		t.Fatalf("No write should have failed yet")
+	}
+	if err := db.Put([]byte("c"), []byte("c")); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+	batch := db.NewBatch()
+	if err := batch.Put([]byte("d"), []byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+	if !db.Failed() {
+		t.Fatalf("A write should have failed")
+	}
+
+	for key, expected := range map[string]bool{"a": true, "c": false, "d": false} {
+		if has, err := baseDB.Has([]byte(key)); err != nil {
+			t.Fatal(err)
+		} else if has != expected {
+			t.Fatalf("%s should be written: %v", key, expected)
+		}
+	}
+}
+
+func TestTearBatches(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+	db.FailWritesAfter(0)
+	db.TearBatches(true)
+
+	batch := db.NewBatch()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := batch.Put([]byte(key), []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.Write(); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+
+	for key, expected := range map[string]bool{"a": true, "b": true, "c": false, "d": false} {
+		if has, err := baseDB.Has([]byte(key)); err != nil {
+			t.Fatal(err)
+		} else if has != expected {
+			t.Fatalf("%s should be written: %v", key, expected)
+		}
+	}
+}
+
+func TestFailReads(t *testing.T) {
+	db := New(memdb.New())
+	if err := db.Put([]byte("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	db.FailReads(true)
+
+	if _, err := db.Has([]byte("a")); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+	if _, err := db.Get([]byte("a")); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+	it := db.NewIterator()
+	defer it.Release()
+	if it.Next() {
+		t.Fatalf("The iterator shouldn't return anything")
+	} else if err := it.Error(); err != ErrInjected {
+		t.Fatalf("Expected %s but got %v", ErrInjected, err)
+	}
+
+	db.FailReads(false)
+	if _, err := db.Get([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -60,3 +60,12 @@ type Database interface {
 	Compacter
 	io.Closer
 }
+
+// Backupper wraps the Backup method of a backing data store. Unlike the other
+// methods, not every data store implements it.
+type Backupper interface {
+	// Backup writes a copy of the data store, as it is when Backup is called,
+	// to [dir], which must not exist. The data store can be written to while
+	// it's backed up.
+	Backup(dir string) error
+}
@@ -24,10 +24,16 @@ type Database struct {
 	db     database.Database
 }
 
-// New returns a new encrypted database
+// New returns a new encrypted database whose values are encrypted with a key
+// that's the hash of [password]
 func New(password []byte, db database.Database) (*Database, error) {
-	h := hashing.ComputeHash256(password)
-	aead, err := chacha20poly1305.NewX(h)
+	return NewWithKey(hashing.ComputeHash256(password), db)
+}
+
+// NewWithKey returns a new encrypted database whose values are encrypted with
+// [key], which must be 32 bytes
+func NewWithKey(key []byte, db database.Database) (*Database, error) {
+	aead, err := chacha20poly1305.NewX(key)
 	if err != nil {
 		return nil, err
 	}
@@ -218,6 +224,9 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	return nil
 }
 
+// Inner returns the batch of the underlying database
+func (b *batch) Inner() database.Batch { return b.Batch.Inner() }
+
 type iterator struct {
 	database.Iterator
 	db *Database
@@ -4,6 +4,7 @@
 package encdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
@@ -22,3 +23,79 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+func TestInterfaceWithKey(t *testing.T) {
+	key := make([]byte, 32)
+	for _, test := range database.Tests {
+		db, err := NewWithKey(key, memdb.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test(t, db)
+	}
+}
+
+func TestNewWithKeyInvalidLength(t *testing.T) {
+	if _, err := NewWithKey(make([]byte, 16), memdb.New()); err == nil {
+		t.Fatalf("Should have errored because the key is too short")
+	}
+}
+
+func TestInterfaceOpen(t *testing.T) {
+	for _, test := range database.Tests {
+		db, err := Open(PassphraseKey([]byte("passphrase")), memdb.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test(t, db)
+	}
+}
+
+func TestOpenReopen(t *testing.T) {
+	baseDB := memdb.New()
+	db, err := Open(PassphraseKey([]byte("passphrase")), baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(PassphraseKey([]byte("wrong")), baseDB); err != errWrongKey {
+		t.Fatalf("Should have errored with %s but got %v", errWrongKey, err)
+	}
+	if _, err := Open(StaticKey(make([]byte, 32)), baseDB); err != errWrongKey {
+		t.Fatalf("Should have errored with %s but got %v", errWrongKey, err)
+	}
+
+	db, err = Open(PassphraseKey([]byte("passphrase")), baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := db.Get([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Returned %s, expected %s", value, "value")
+	}
+
+	// The value shouldn't be stored in the clear
+	it := baseDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if bytes.Contains(it.Value(), []byte("value")) {
+			t.Fatalf("%s was stored in the clear", it.Key())
+		}
+	}
+}
+
+func TestOpenUnencryptedData(t *testing.T) {
+	baseDB := memdb.New()
+	if err := baseDB.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(StaticKey(make([]byte, 32)), baseDB); err != errNotEncrypted {
+		t.Fatalf("Should have errored with %s but got %v", errNotEncrypted, err)
+	}
+}
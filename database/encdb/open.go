@@ -0,0 +1,112 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package encdb
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	errNotEncrypted = errors.New("the database already holds data that isn't encrypted")
+	errWrongKey     = errors.New("the database is encrypted with a different key")
+
+	// Values are stored under [dataPrefix]. What's needed to check the key is
+	// stored, in the clear, under [metaPrefix].
+	dataPrefix = []byte("data")
+	metaPrefix = []byte("meta")
+
+	saltKey    = []byte("salt")
+	checkKey   = []byte("check")
+	checkValue = []byte("encdb")
+)
+
+// KeySource returns the key a database is encrypted with, given the random
+// salt generated when the database was first opened. It's the hook for keys
+// held by a key management service rather than derived from a passphrase.
+type KeySource func(salt []byte) ([]byte, error)
+
+// PassphraseKey returns a KeySource that derives the key from [passphrase]
+// and the database's salt with argon2id
+func PassphraseKey(passphrase []byte) KeySource {
+	return func(salt []byte) ([]byte, error) {
+		return argon2.IDKey(passphrase, salt, 1, 64*1024, 4, keyLen), nil
+	}
+}
+
+// StaticKey returns a KeySource that returns [key], which must be 32 bytes,
+// for every database
+func StaticKey(key []byte) KeySource {
+	return func([]byte) ([]byte, error) { return key, nil }
+}
+
+// Open returns a database that encrypts its values with the key returned by
+// [source] before writing them to [db]. Unlike with New, [db] stores a value
+// encrypted with the key, so opening it with a different key fails rather
+// than returning values that can't be decrypted. The first time [db] is
+// opened, it must be empty.
+func Open(source KeySource, db database.Database) (*Database, error) {
+	meta := prefixdb.NewNested(metaPrefix, db)
+	salt, err := meta.Get(saltKey)
+	created := err == database.ErrNotFound
+	switch {
+	case created:
+		it := db.NewIterator()
+		hasData := it.Next()
+		it.Release()
+		if hasData {
+			return nil, errNotEncrypted
+		}
+		salt = make([]byte, saltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	key, err := source(salt)
+	if err != nil {
+		return nil, err
+	}
+	encDB, err := NewWithKey(key, prefixdb.NewNested(dataPrefix, db))
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		check, err := encDB.encrypt(checkValue)
+		if err != nil {
+			return nil, err
+		}
+		batch := meta.NewBatch()
+		if err := batch.Put(checkKey, check); err != nil {
+			return nil, err
+		}
+		if err := batch.Put(saltKey, salt); err != nil {
+			return nil, err
+		}
+		return encDB, batch.Write()
+	}
+
+	check, err := meta.Get(checkKey)
+	if err != nil {
+		return nil, err
+	}
+	if value, err := encDB.decrypt(check); err != nil || !bytes.Equal(value, checkValue) {
+		return nil, errWrongKey
+	}
+	return encDB, nil
+}
@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightdb
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+)
+
+const (
+	lastHeightPrefix byte = iota
+	heightPrefix
+	valuePrefix
+)
+
+// lastHeightKey is the key the greatest height is stored under
+var lastHeightKey = []byte{lastHeightPrefix}
+
+// HeightDB indexes values, such as accepted blocks, by their ID and by their
+// height. Each change is written atomically.
+type HeightDB struct {
+	lock sync.RWMutex
+	db   database.Database
+}
+
+// New returns a new index that's stored in [db]. [db] shouldn't hold anything
+// else, so it's typically a prefixdb.
+func New(db database.Database) *HeightDB { return &HeightDB{db: db} }
+
+// Put indexes [value] by [id] and [height]. If a value is already indexed at
+// [height] by a different ID, it's replaced.
+func (hdb *HeightDB) Put(height uint64, id ids.ID, value []byte) error {
+	hdb.lock.Lock()
+	defer hdb.lock.Unlock()
+
+	batch := hdb.db.NewBatch()
+	oldIDBytes, err := hdb.db.Get(heightKey(height))
+	switch err {
+	case nil:
+		if oldID, err := ids.ToID(oldIDBytes); err != nil {
+			return err
+		} else if !oldID.Equals(id) {
+			if err := batch.Delete(valueKey(oldID)); err != nil {
+				return err
+			}
+		}
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	lastHeight, err := hdb.lastHeight()
+	switch {
+	case err == database.ErrNotFound, err == nil && height > lastHeight:
+		if err := batch.Put(lastHeightKey, heightBytes(height)); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	}
+
+	if err := batch.Put(heightKey(height), id.Bytes()); err != nil {
+		return err
+	}
+	if err := batch.Put(valueKey(id), value); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// GetID returns the ID of the value indexed at [height]
+func (hdb *HeightDB) GetID(height uint64) (ids.ID, error) {
+	hdb.lock.RLock()
+	defer hdb.lock.RUnlock()
+
+	idBytes, err := hdb.db.Get(heightKey(height))
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(idBytes)
+}
+
+// Get returns the value indexed by [id]
+func (hdb *HeightDB) Get(id ids.ID) ([]byte, error) {
+	hdb.lock.RLock()
+	defer hdb.lock.RUnlock()
+
+	return hdb.db.Get(valueKey(id))
+}
+
+// GetByHeight returns the value indexed at [height]
+func (hdb *HeightDB) GetByHeight(height uint64) ([]byte, error) {
+	id, err := hdb.GetID(height)
+	if err != nil {
+		return nil, err
+	}
+	return hdb.Get(id)
+}
+
+// LastHeight returns the greatest height a value is indexed at. It returns
+// database.ErrNotFound if nothing is indexed.
+func (hdb *HeightDB) LastHeight() (uint64, error) {
+	hdb.lock.RLock()
+	defer hdb.lock.RUnlock()
+
+	return hdb.lastHeight()
+}
+
+// Assumes the lock is held
+func (hdb *HeightDB) lastHeight() (uint64, error) {
+	lastHeightBytes, err := hdb.db.Get(lastHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(lastHeightBytes), nil
+}
+
+// heightBytes returns [height] big endian encoded, so heights are ordered like
+// their keys
+func heightBytes(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
+
+func heightKey(height uint64) []byte {
+	return append([]byte{heightPrefix}, heightBytes(height)...)
+}
+
+func valueKey(id ids.ID) []byte {
+	return append([]byte{valuePrefix}, id.Bytes()...)
+}
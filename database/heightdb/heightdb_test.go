@@ -0,0 +1,67 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package heightdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestHeightDB(t *testing.T) {
+	hdb := New(memdb.New())
+	if _, err := hdb.LastHeight(); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+
+	id0 := ids.NewID([32]byte{0})
+	id1 := ids.NewID([32]byte{1})
+	if err := hdb.Put(1, id1, []byte("one")); err != nil {
+		t.Fatal(err)
+	}
+	if err := hdb.Put(0, id0, []byte("zero")); err != nil {
+		t.Fatal(err)
+	}
+
+	if height, err := hdb.LastHeight(); err != nil {
+		t.Fatal(err)
+	} else if height != 1 {
+		t.Fatalf("Last height is %d, expected %d", height, 1)
+	}
+	if id, err := hdb.GetID(0); err != nil {
+		t.Fatal(err)
+	} else if !id.Equals(id0) {
+		t.Fatalf("Returned %s, expected %s", id, id0)
+	}
+	if value, err := hdb.Get(id1); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("one")) {
+		t.Fatalf("Returned %s, expected %s", value, "one")
+	}
+	if value, err := hdb.GetByHeight(0); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("zero")) {
+		t.Fatalf("Returned %s, expected %s", value, "zero")
+	}
+	if _, err := hdb.GetByHeight(2); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+
+	// Replacing the value at a height removes the old value
+	id2 := ids.NewID([32]byte{2})
+	if err := hdb.Put(1, id2, []byte("two")); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := hdb.GetByHeight(1); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("two")) {
+		t.Fatalf("Returned %s, expected %s", value, "two")
+	}
+	if _, err := hdb.Get(id1); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+}
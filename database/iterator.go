@@ -16,6 +16,10 @@ package database
 // An iterator must be released after use, but it is not necessary to read an
 // iterator until exhaustion. An iterator is not safe for concurrent use, but it
 // is safe to use multiple iterators concurrently.
+//
+// An iterator returns the key/value pairs as they were when it was created.
+// Writes made to the database afterward, including through batches, aren't
+// returned by it.
 type Iterator interface {
 	// Next moves the iterator to the next key/value pair. It returns whether the
 	// iterator is exhausted.
@@ -60,3 +64,30 @@ type Iteratee interface {
 	// specified key.
 	NewIteratorWithStartAndPrefix(start, prefix []byte) Iterator
 }
+
+// KeyValue is a key/value pair returned by an iterator
+type KeyValue struct {
+	Key   []byte
+	Value []byte
+}
+
+// Collect returns the next key/value pairs of [it], up to [limit] of them. The
+// pairs are copied, so they stay valid once [it] is released. To return the
+// following pairs from a new iterator, start it at the last key returned with
+// a 0 byte appended.
+func Collect(it Iterator, limit int) ([]KeyValue, error) {
+	pairs := []KeyValue(nil)
+	for len(pairs) < limit && it.Next() {
+		pairs = append(pairs, KeyValue{
+			Key:   copyBytes(it.Key()),
+			Value: copyBytes(it.Value()),
+		})
+	}
+	return pairs, it.Error()
+}
+
+func copyBytes(bytes []byte) []byte {
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
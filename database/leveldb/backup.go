@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package leveldb
+
+import (
+	"os"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// copyBatchSize is the number of bytes copied into a backup, or restored from
+// one, per write
+const copyBatchSize = 4 * opt.MiB
+
+// Backup writes a copy of the database, as it is when Backup is called, to a
+// new database in [dir]. The database can be written to while it's backed up,
+// and those writes aren't part of the backup. [dir] must not exist.
+func (db *Database) Backup(dir string) error {
+	snapshot, err := db.DB.GetSnapshot()
+	if err != nil {
+		return updateError(err)
+	}
+	defer snapshot.Release()
+
+	it := snapshot.NewIterator(nil, nil)
+	defer it.Release()
+	return copyTo(dir, it)
+}
+
+// Restore writes a copy of the backup in [backupDir] to a new database in
+// [dir], which must not exist
+func Restore(backupDir, dir string) error {
+	backup, err := leveldb.OpenFile(backupDir, &opt.Options{
+		ErrorIfMissing: true,
+		ReadOnly:       true,
+	})
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	it := backup.NewIterator(nil, nil)
+	defer it.Release()
+	return copyTo(dir, it)
+}
+
+// copyTo writes the key-value pairs of [it] to a new database in [dir]. If it
+// fails, the new database is removed, so [dir] is never left with part of the
+// copy.
+func copyTo(dir string, it iterator.Iterator) error {
+	if _, err := os.Stat(dir); err == nil {
+		return os.ErrExist
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	db, err := leveldb.OpenFile(dir, &opt.Options{ErrorIfExist: true})
+	if err != nil {
+		return err
+	}
+	if err := copyIterator(db, it); err != nil {
+		db.Close()
+		os.RemoveAll(dir)
+		return err
+	}
+	if err := db.Close(); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+func copyIterator(db *leveldb.DB, it iterator.Iterator) error {
+	batch := new(leveldb.Batch)
+	size := 0
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		size += len(it.Key()) + len(it.Value())
+		if size < copyBatchSize {
+			continue
+		}
+		if err := db.Write(batch, nil); err != nil {
+			return err
+		}
+		batch.Reset()
+		size = 0
+	}
+	if err := it.Error(); err != nil {
+		return updateError(err)
+	}
+	return db.Write(batch, nil)
+}
@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package leveldb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+func TestBackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "leveldb-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := New(path.Join(dir, "db"), 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	backupDir := path.Join(dir, "backup")
+	if err := db.Backup(backupDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(backupDir); err == nil {
+		t.Fatalf("Should have errored because the backup already exists")
+	}
+	// Writes after the backup aren't part of it
+	if err := db.Put([]byte("later"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredDir := path.Join(dir, "restored")
+	if err := Restore(backupDir, restoredDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(path.Join(dir, "missing"), path.Join(dir, "other")); err == nil {
+		t.Fatalf("Should have errored because the backup doesn't exist")
+	}
+
+	restored, err := New(restoredDir, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	if value, err := restored.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("world")) {
+		t.Fatalf("Returned %s, expected %s", value, "world")
+	}
+	if _, err := restored.Get([]byte("later")); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+}
@@ -27,6 +27,10 @@ const (
 	// minHandleCap is the minimum number of files descriptors to cap levelDB to
 	// use
 	minHandleCap = 16
+
+	// defaultBloomFilterBits is the number of bits per key of bloom filters
+	// unless configured otherwise
+	defaultBloomFilterBits = 10
 )
 
 // Database is a persistent key-value store. Apart from basic data storage
@@ -34,27 +38,71 @@ const (
 // in binary-alphabetical order.
 type Database struct{ *leveldb.DB }
 
+// Config is the configuration of a LevelDB database. Sizes below the minimums
+// are raised to them.
+type Config struct {
+	// Number of bytes to use for block caching
+	BlockCacheSize int
+	// Number of bytes to buffer writes in before they're written to disk
+	WriteBufferSize int
+	// Maximum number of files to keep open
+	HandleCap int
+	// Number of bits per key of the bloom filters that let reads skip tables
+	// that don't hold the key. If 0, tables don't have bloom filters.
+	BloomFilterBits int
+	// If true, tables are compressed with snappy
+	Compression bool
+}
+
+// DefaultConfig returns the configuration New opens databases with
+func DefaultConfig() Config {
+	return Config{
+		BlockCacheSize:  minBlockCacheSize,
+		WriteBufferSize: minWriteBufferSize,
+		HandleCap:       minHandleCap,
+		BloomFilterBits: defaultBloomFilterBits,
+		Compression:     true,
+	}
+}
+
 // New returns a wrapped LevelDB object.
 func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database, error) {
+	config := DefaultConfig()
+	config.BlockCacheSize = blockCacheSize
+	config.WriteBufferSize = writeBufferSize
+	config.HandleCap = handleCap
+	return NewWithConfig(file, config)
+}
+
+// NewWithConfig returns a wrapped LevelDB object configured by [config]
+func NewWithConfig(file string, config Config) (*Database, error) {
 	// Enforce minimums
-	if blockCacheSize < minBlockCacheSize {
-		blockCacheSize = minBlockCacheSize
+	if config.BlockCacheSize < minBlockCacheSize {
+		config.BlockCacheSize = minBlockCacheSize
 	}
-	if writeBufferSize < minWriteBufferSize {
-		writeBufferSize = minWriteBufferSize
+	if config.WriteBufferSize < minWriteBufferSize {
+		config.WriteBufferSize = minWriteBufferSize
 	}
-	if handleCap < minHandleCap {
-		handleCap = minHandleCap
+	if config.HandleCap < minHandleCap {
+		config.HandleCap = minHandleCap
 	}
 
-	// Open the db and recover any potential corruptions
-	db, err := leveldb.OpenFile(file, &opt.Options{
-		OpenFilesCacheCapacity: handleCap,
-		BlockCacheCapacity:     blockCacheSize,
+	options := &opt.Options{
+		OpenFilesCacheCapacity: config.HandleCap,
+		BlockCacheCapacity:     config.BlockCacheSize,
 		// There are two buffers of size WriteBuffer used.
-		WriteBuffer: writeBufferSize / 2,
-		Filter:      filter.NewBloomFilter(10),
-	})
+		WriteBuffer: config.WriteBufferSize / 2,
+		Compression: opt.NoCompression,
+	}
+	if config.BloomFilterBits > 0 {
+		options.Filter = filter.NewBloomFilter(config.BloomFilterBits)
+	}
+	if config.Compression {
+		options.Compression = opt.SnappyCompression
+	}
+
+	// Open the db and recover any potential corruptions
+	db, err := leveldb.OpenFile(file, options)
 	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
 		db, err = leveldb.RecoverFile(file, nil)
 	}
@@ -184,6 +232,9 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	return updateError(replay.err)
 }
 
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
 type replayer struct {
 	writer database.KeyValueWriter
 	err    error
@@ -25,3 +25,21 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+func TestInterfaceWithConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.BloomFilterBits = 0
+	config.Compression = false
+	for i, test := range database.Tests {
+		folder := fmt.Sprintf("db-config%d", i)
+
+		db, err := NewWithConfig(folder, config)
+		if err != nil {
+			t.Fatalf("leveldb.NewWithConfig(%s) errored with %s", folder, err)
+		}
+		defer os.RemoveAll(folder)
+		defer db.Close()
+
+		test(t, db)
+	}
+}
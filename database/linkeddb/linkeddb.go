@@ -0,0 +1,281 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package linkeddb
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	headPrefix byte = iota
+	nodePrefix
+)
+
+// headKey is the key the key of the list's head is stored under
+var headKey = []byte{headPrefix}
+
+// node is an element of the list, stored under its key
+type node struct {
+	Value       []byte `serialize:"true"`
+	HasNext     bool   `serialize:"true"`
+	Next        []byte `serialize:"true"`
+	HasPrevious bool   `serialize:"true"`
+	Previous    []byte `serialize:"true"`
+}
+
+// LinkedDB is a doubly linked list of key-value pairs stored in a database. A
+// key that's added is put at the head of the list, so iterating over the list
+// returns the most recently added keys first. Unlike iterating over the
+// database itself, the order doesn't depend on the keys. Each change is
+// written atomically.
+type LinkedDB struct {
+	lock  sync.RWMutex
+	codec codec.Codec
+	db    database.Database
+}
+
+// New returns a new list that's stored in [db]. [db] shouldn't hold anything
+// else, so it's typically a prefixdb.
+func New(db database.Database) *LinkedDB {
+	return &LinkedDB{
+		codec: codec.NewDefault(),
+		db:    db,
+	}
+}
+
+// Has returns whether [key] is in the list
+func (ldb *LinkedDB) Has(key []byte) (bool, error) {
+	ldb.lock.RLock()
+	defer ldb.lock.RUnlock()
+
+	return ldb.db.Has(nodeKey(key))
+}
+
+// Get returns the value of [key]
+func (ldb *LinkedDB) Get(key []byte) ([]byte, error) {
+	ldb.lock.RLock()
+	defer ldb.lock.RUnlock()
+
+	n, err := ldb.getNode(key)
+	return n.Value, err
+}
+
+// Put sets the value of [key] to [value]. If [key] isn't in the list, it's
+// added at the head. Otherwise, it keeps its place.
+func (ldb *LinkedDB) Put(key, value []byte) error {
+	ldb.lock.Lock()
+	defer ldb.lock.Unlock()
+
+	batch := ldb.db.NewBatch()
+	n, err := ldb.getNode(key)
+	switch err {
+	case nil:
+		n.Value = value
+		if err := ldb.putNode(batch, key, n); err != nil {
+			return err
+		}
+		return batch.Write()
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+
+	newNode := node{Value: value}
+	oldHeadKey, err := ldb.db.Get(headKey)
+	switch err {
+	case nil:
+		oldHead, err := ldb.getNode(oldHeadKey)
+		if err != nil {
+			return err
+		}
+		oldHead.HasPrevious = true
+		oldHead.Previous = key
+		if err := ldb.putNode(batch, oldHeadKey, oldHead); err != nil {
+			return err
+		}
+		newNode.HasNext = true
+		newNode.Next = oldHeadKey
+	case database.ErrNotFound:
+	default:
+		return err
+	}
+	if err := ldb.putNode(batch, key, newNode); err != nil {
+		return err
+	}
+	if err := batch.Put(headKey, key); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Delete removes [key] from the list. It's not an error if [key] isn't in the
+// list.
+func (ldb *LinkedDB) Delete(key []byte) error {
+	ldb.lock.Lock()
+	defer ldb.lock.Unlock()
+
+	n, err := ldb.getNode(key)
+	switch err {
+	case nil:
+	case database.ErrNotFound:
+		return nil
+	default:
+		return err
+	}
+
+	batch := ldb.db.NewBatch()
+	if n.HasPrevious {
+		previous, err := ldb.getNode(n.Previous)
+		if err != nil {
+			return err
+		}
+		previous.HasNext = n.HasNext
+		previous.Next = n.Next
+		if err := ldb.putNode(batch, n.Previous, previous); err != nil {
+			return err
+		}
+	} else if n.HasNext {
+		// [key] is the head, so the next node becomes the head
+		if err := batch.Put(headKey, n.Next); err != nil {
+			return err
+		}
+	} else if err := batch.Delete(headKey); err != nil {
+		return err
+	}
+	if n.HasNext {
+		next, err := ldb.getNode(n.Next)
+		if err != nil {
+			return err
+		}
+		next.HasPrevious = n.HasPrevious
+		next.Previous = n.Previous
+		if err := ldb.putNode(batch, n.Next, next); err != nil {
+			return err
+		}
+	}
+	if err := batch.Delete(nodeKey(key)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// IsEmpty returns whether the list is empty
+func (ldb *LinkedDB) IsEmpty() (bool, error) {
+	ldb.lock.RLock()
+	defer ldb.lock.RUnlock()
+
+	has, err := ldb.db.Has(headKey)
+	return !has, err
+}
+
+// Head returns the most recently added key and its value. It returns
+// database.ErrNotFound if the list is empty.
+func (ldb *LinkedDB) Head() ([]byte, []byte, error) {
+	ldb.lock.RLock()
+	defer ldb.lock.RUnlock()
+
+	key, err := ldb.db.Get(headKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	n, err := ldb.getNode(key)
+	return key, n.Value, err
+}
+
+// NewIterator returns an iterator over the list, from the head to the tail.
+// The list shouldn't be changed while it's iterated over.
+func (ldb *LinkedDB) NewIterator() database.Iterator { return &iterator{ldb: ldb} }
+
+func (ldb *LinkedDB) getNode(key []byte) (node, error) {
+	n := node{}
+	nodeBytes, err := ldb.db.Get(nodeKey(key))
+	if err != nil {
+		return n, err
+	}
+	return n, ldb.codec.Unmarshal(nodeBytes, &n)
+}
+
+func (ldb *LinkedDB) putNode(batch database.Batch, key []byte, n node) error {
+	nodeBytes, err := ldb.codec.Marshal(&n)
+	if err != nil {
+		return err
+	}
+	return batch.Put(nodeKey(key), nodeBytes)
+}
+
+// nodeKey returns the key the node of [key] is stored under
+func nodeKey(key []byte) []byte {
+	return append([]byte{nodePrefix}, key...)
+}
+
+type iterator struct {
+	ldb     *LinkedDB
+	started bool
+	done    bool
+	key     []byte
+	node    node
+	err     error
+}
+
+// Next implements the Iterator interface
+func (it *iterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	it.ldb.lock.RLock()
+	defer it.ldb.lock.RUnlock()
+
+	var (
+		key []byte
+		err error
+	)
+	switch {
+	case !it.started:
+		it.started = true
+		key, err = it.ldb.db.Get(headKey)
+		if err == database.ErrNotFound {
+			return it.finish(nil)
+		}
+	case !it.node.HasNext:
+		return it.finish(nil)
+	default:
+		key = it.node.Next
+	}
+	if err != nil {
+		return it.finish(err)
+	}
+
+	n, err := it.ldb.getNode(key)
+	if err != nil {
+		return it.finish(err)
+	}
+	it.key = key
+	it.node = n
+	return true
+}
+
+// finish ends the iteration, with [err] if it failed, and returns false
+func (it *iterator) finish(err error) bool {
+	it.done = true
+	it.key = nil
+	it.node = node{}
+	it.err = err
+	return false
+}
+
+// Error implements the Iterator interface
+func (it *iterator) Error() error { return it.err }
+
+// Key implements the Iterator interface
+func (it *iterator) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iterator) Value() []byte { return it.node.Value }
+
+// Release implements the Iterator interface
+func (it *iterator) Release() {}
@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package linkeddb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+// assertKeys asserts that iterating over [ldb] returns [expected], in order,
+// each with its own key as its value
+func assertKeys(t *testing.T, ldb *LinkedDB, expected ...string) {
+	it := ldb.NewIterator()
+	defer it.Release()
+
+	keys := []string{}
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+		if !bytes.Equal(it.Key(), it.Value()) {
+			t.Fatalf("Key %s has value %s", it.Key(), it.Value())
+		}
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("Iterated over %v, expected %v", keys, expected)
+	}
+	for i, key := range keys {
+		if key != expected[i] {
+			t.Fatalf("Iterated over %v, expected %v", keys, expected)
+		}
+	}
+}
+
+func TestLinkedDB(t *testing.T) {
+	ldb := New(memdb.New())
+	if empty, err := ldb.IsEmpty(); err != nil {
+		t.Fatal(err)
+	} else if !empty {
+		t.Fatalf("A new list should be empty")
+	}
+	if _, _, err := ldb.Head(); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+	assertKeys(t, ldb)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if err := ldb.Put([]byte(key), []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertKeys(t, ldb, "d", "c", "b", "a")
+
+	// Updating a key doesn't move it
+	if err := ldb.Put([]byte("b"), []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, ldb, "d", "c", "b", "a")
+
+	// Remove from the middle, the head and the tail
+	for _, key := range []string{"c", "d", "a"} {
+		if err := ldb.Delete([]byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertKeys(t, ldb, "b")
+	if has, err := ldb.Has([]byte("c")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("c should have been deleted")
+	}
+	if err := ldb.Delete([]byte("c")); err != nil {
+		t.Fatalf("Deleting a missing key shouldn't error but got %s", err)
+	}
+
+	if err := ldb.Put([]byte("e"), []byte("e")); err != nil {
+		t.Fatal(err)
+	}
+	assertKeys(t, ldb, "e", "b")
+	if key, value, err := ldb.Head(); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(key, []byte("e")) || !bytes.Equal(value, []byte("e")) {
+		t.Fatalf("Head returned %s: %s, expected e: e", key, value)
+	}
+
+	for _, key := range []string{"b", "e"} {
+		if err := ldb.Delete([]byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if empty, err := ldb.IsEmpty(); err != nil {
+		t.Fatal(err)
+	} else if !empty {
+		t.Fatalf("The list should be empty")
+	}
+	assertKeys(t, ldb)
+}
+
+func TestLinkedDBPersisted(t *testing.T) {
+	db := memdb.New()
+	ldb := New(db)
+	for _, key := range []string{"a", "b"} {
+		if err := ldb.Put([]byte(key), []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assertKeys(t, New(db), "b", "a")
+}
@@ -0,0 +1,178 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package meterdb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Database tracks the amount of time each operation takes and how many bytes
+// are read and written to the underlying database instance.
+type Database struct {
+	metrics
+	db    database.Database
+	clock timer.Clock
+}
+
+// New returns a new database that records the calls to [db] in metrics
+// registered with [registerer], under [namespace]
+func New(namespace string, registerer prometheus.Registerer, db database.Database) (*Database, error) {
+	meterDB := &Database{db: db}
+	return meterDB, meterDB.metrics.Initialize(namespace, registerer)
+}
+
+// Has implements the Database interface
+func (db *Database) Has(key []byte) (bool, error) {
+	start := db.clock.Time()
+	has, err := db.db.Has(key)
+	db.has.Observe(float64(db.clock.Time().Sub(start)))
+	db.countError(err)
+	return has, err
+}
+
+// Get implements the Database interface
+func (db *Database) Get(key []byte) ([]byte, error) {
+	start := db.clock.Time()
+	value, err := db.db.Get(key)
+	db.get.Observe(float64(db.clock.Time().Sub(start)))
+	db.readSize.Add(float64(len(value)))
+	db.countError(err)
+	return value, err
+}
+
+// Put implements the Database interface
+func (db *Database) Put(key, value []byte) error {
+	start := db.clock.Time()
+	err := db.db.Put(key, value)
+	db.put.Observe(float64(db.clock.Time().Sub(start)))
+	db.writeSize.Add(float64(len(key) + len(value)))
+	db.countError(err)
+	return err
+}
+
+// Delete implements the Database interface
+func (db *Database) Delete(key []byte) error {
+	start := db.clock.Time()
+	err := db.db.Delete(key)
+	db.delete.Observe(float64(db.clock.Time().Sub(start)))
+	db.countError(err)
+	return err
+}
+
+// NewBatch implements the Database interface
+func (db *Database) NewBatch() database.Batch {
+	start := db.clock.Time()
+	b := &batch{
+		batch: db.db.NewBatch(),
+		db:    db,
+	}
+	db.newBatch.Observe(float64(db.clock.Time().Sub(start)))
+	return b
+}
+
+// NewIterator implements the Database interface
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	startTime := db.clock.Time()
+	it := db.db.NewIteratorWithStartAndPrefix(start, prefix)
+	db.newIterator.Observe(float64(db.clock.Time().Sub(startTime)))
+	return it
+}
+
+// Stat implements the Database interface
+func (db *Database) Stat(stat string) (string, error) {
+	start := db.clock.Time()
+	result, err := db.db.Stat(stat)
+	db.stat.Observe(float64(db.clock.Time().Sub(start)))
+	db.countError(err)
+	return result, err
+}
+
+// Compact implements the Database interface
+func (db *Database) Compact(start, limit []byte) error {
+	startTime := db.clock.Time()
+	err := db.db.Compact(start, limit)
+	db.compact.Observe(float64(db.clock.Time().Sub(startTime)))
+	db.countError(err)
+	return err
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	start := db.clock.Time()
+	err := db.db.Close()
+	db.close.Observe(float64(db.clock.Time().Sub(start)))
+	db.countError(err)
+	return err
+}
+
+// countError records [err] if it's a failure
+func (db *Database) countError(err error) {
+	if err != nil && err != database.ErrNotFound {
+		db.errors.Inc()
+	}
+}
+
+type batch struct {
+	batch database.Batch
+	db    *Database
+}
+
+// Put implements the Batch interface
+func (b *batch) Put(key, value []byte) error {
+	start := b.db.clock.Time()
+	err := b.batch.Put(key, value)
+	b.db.batchPut.Observe(float64(b.db.clock.Time().Sub(start)))
+	b.db.countError(err)
+	return err
+}
+
+// Delete implements the Batch interface
+func (b *batch) Delete(key []byte) error {
+	start := b.db.clock.Time()
+	err := b.batch.Delete(key)
+	b.db.batchDelete.Observe(float64(b.db.clock.Time().Sub(start)))
+	b.db.countError(err)
+	return err
+}
+
+// ValueSize implements the Batch interface
+func (b *batch) ValueSize() int { return b.batch.ValueSize() }
+
+// Write implements the Batch interface
+func (b *batch) Write() error {
+	start := b.db.clock.Time()
+	err := b.batch.Write()
+	b.db.batchWrite.Observe(float64(b.db.clock.Time().Sub(start)))
+	b.db.writeSize.Add(float64(b.batch.ValueSize()))
+	b.db.batchSize.Observe(float64(b.batch.ValueSize()))
+	b.db.countError(err)
+	return err
+}
+
+// Reset implements the Batch interface
+func (b *batch) Reset() { b.batch.Reset() }
+
+// Replay implements the Batch interface
+func (b *batch) Replay(w database.KeyValueWriter) error { return b.batch.Replay(w) }
+
+// Inner returns the batch of the underlying database
+func (b *batch) Inner() database.Batch { return b.batch.Inner() }
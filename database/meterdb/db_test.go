@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package meterdb
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, err := New("", prometheus.NewRegistry(), memdb.New())
+		if err != nil {
+			t.Fatal(err)
+		}
+		test(t, db)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db, err := New("db", registry, memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New("db", registry, memdb.New()); err == nil {
+		t.Fatalf("Should have errored because the metrics are already registered")
+	}
+
+	if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if histogram := metric.GetHistogram(); histogram != nil {
+				counts[family.GetName()] = histogram.GetSampleCount()
+			}
+			if counter := metric.GetCounter(); counter != nil {
+				values[family.GetName()] = counter.GetValue()
+			}
+		}
+	}
+	if counts["db_put"] != 1 || counts["db_get"] != 1 || counts["db_delete"] != 0 {
+		t.Fatalf("Unexpected call counts %v", counts)
+	}
+	if values["db_write_size"] != 10 || values["db_read_size"] != 5 {
+		t.Fatalf("Unexpected sizes %v", values)
+	}
+}
+
+func TestErrorsAndBatchSize(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	baseDB := memdb.New()
+	db, err := New("db", registry, baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batch := db.NewBatch()
+	if err := batch.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	// Missing keys aren't failures
+	if _, err := db.Get([]byte("missing")); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+	if err := baseDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get([]byte("hello")); err != database.ErrClosed {
+		t.Fatalf("Expected %s but got %v", database.ErrClosed, err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch family.GetName() {
+			case "db_errors":
+				if errors := metric.GetCounter().GetValue(); errors != 1 {
+					t.Fatalf("Expected 1 error but got %v", errors)
+				}
+			case "db_batch_size":
+				if sum := metric.GetHistogram().GetSampleSum(); sum != 5 {
+					t.Fatalf("Expected a batch of 5 bytes but got %v", sum)
+				}
+			}
+		}
+	}
+}
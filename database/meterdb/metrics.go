@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package meterdb
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// Buckets of the durations of database calls, in nanoseconds, from 1µs to
+	// about a quarter of a second
+	durationBuckets = prometheus.ExponentialBuckets(1000, 4, 10)
+
+	// Buckets of the sizes of written batches, in bytes, from 64B to 16MiB
+	sizeBuckets = prometheus.ExponentialBuckets(64, 4, 10)
+)
+
+func newDuration(namespace, name string) prometheus.Histogram {
+	return prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      fmt.Sprintf("Duration of %s calls, in nanoseconds", name),
+		Buckets:   durationBuckets,
+	})
+}
+
+type metrics struct {
+	readSize, writeSize, errors prometheus.Counter
+	batchSize                   prometheus.Histogram
+
+	has, get, put, delete,
+	newBatch, newIterator,
+	stat, compact, close,
+	batchPut, batchDelete, batchWrite prometheus.Histogram
+}
+
+func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer) error {
+	m.readSize = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "read_size",
+		Help:      "Number of bytes read from the database",
+	})
+	m.writeSize = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "write_size",
+		Help:      "Number of bytes written to the database",
+	})
+	m.errors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors",
+		Help:      "Number of database calls that failed. Getting a missing key isn't a failure",
+	})
+	m.batchSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "batch_size",
+		Help:      "Number of bytes in each batch written to the database",
+		Buckets:   sizeBuckets,
+	})
+	m.has = newDuration(namespace, "has")
+	m.get = newDuration(namespace, "get")
+	m.put = newDuration(namespace, "put")
+	m.delete = newDuration(namespace, "delete")
+	m.newBatch = newDuration(namespace, "new_batch")
+	m.newIterator = newDuration(namespace, "new_iterator")
+	m.stat = newDuration(namespace, "stat")
+	m.compact = newDuration(namespace, "compact")
+	m.close = newDuration(namespace, "close")
+	m.batchPut = newDuration(namespace, "batch_put")
+	m.batchDelete = newDuration(namespace, "batch_delete")
+	m.batchWrite = newDuration(namespace, "batch_write")
+
+	for _, collector := range []prometheus.Collector{
+		m.readSize, m.writeSize, m.errors, m.batchSize,
+		m.has, m.get, m.put, m.delete,
+		m.newBatch, m.newIterator,
+		m.stat, m.compact, m.close,
+		m.batchPut, m.batchDelete, m.batchWrite,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
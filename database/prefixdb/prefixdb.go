@@ -13,6 +13,10 @@ import (
 
 // Database partitions a database into a sub-database by prefixing all keys with
 // a unique value.
+// The prefix of a database is the hash of the prefix it's created with, so
+// every prefix has the same length and the keys of sibling databases can't
+// collide, however their prefixes and keys are chosen. Iterators only return
+// the keys of their own database, with the prefix stripped.
 type Database struct {
 	lock     sync.RWMutex
 	dbPrefix []byte
@@ -20,6 +24,10 @@ type Database struct {
 }
 
 // New returns a new prefixed database
+// If [db] is itself a prefixed database, the prefixes are combined so that
+// every operation is done with a single prefix on the underlying database. The
+// keys of the returned database then aren't keys of [db], so they aren't
+// returned by [db]'s iterators.
 func New(prefix []byte, db database.Database) *Database {
 	if prefixDB, ok := db.(*Database); ok {
 		simplePrefix := make([]byte, len(prefixDB.dbPrefix)+len(prefix))
@@ -32,7 +40,8 @@ func New(prefix []byte, db database.Database) *Database {
 }
 
 // NewNested returns a new prefixed database without attempting to compress
-// prefixes.
+// prefixes. The keys of the returned database are also keys of [db], so
+// iterating over [db] includes them.
 func NewNested(prefix []byte, db database.Database) *Database {
 	return &Database{
 		dbPrefix: hashing.ComputeHash256(prefix),
@@ -216,6 +225,9 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	return nil
 }
 
+// Inner returns the batch of the underlying database
+func (b *batch) Inner() database.Batch { return b.Batch.Inner() }
+
 type iterator struct {
 	database.Iterator
 	db *Database
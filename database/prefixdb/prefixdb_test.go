@@ -21,3 +21,71 @@ func TestInterface(t *testing.T) {
 		test(t, NewNested([]byte("ld"), New([]byte("wor"), db)))
 	}
 }
+
+func TestSiblingsDontCollide(t *testing.T) {
+	baseDB := memdb.New()
+	// The keys of the first database would start with the prefix of the
+	// second if the prefixes weren't hashed
+	dbA := New([]byte("a"), baseDB)
+	dbB := New([]byte("ab"), baseDB)
+
+	if err := dbA.Put([]byte("bc"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbB.Put([]byte("c"), []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := dbA.Get([]byte("bc")); err != nil {
+		t.Fatal(err)
+	} else if string(value) != "a" {
+		t.Fatalf("dbA.Get Returned: %s ; Expected: %s", value, "a")
+	}
+	if value, err := dbB.Get([]byte("c")); err != nil {
+		t.Fatal(err)
+	} else if string(value) != "b" {
+		t.Fatalf("dbB.Get Returned: %s ; Expected: %s", value, "b")
+	}
+
+	iterator := dbA.NewIterator()
+	defer iterator.Release()
+	if !iterator.Next() {
+		t.Fatalf("iterator.Next Returned: false ; Expected: true")
+	} else if key := string(iterator.Key()); key != "bc" {
+		t.Fatalf("iterator.Key Returned: %s ; Expected: %s", key, "bc")
+	} else if iterator.Next() {
+		t.Fatalf("Iterating over dbA shouldn't return the keys of dbB")
+	}
+}
+
+func TestNestedIteration(t *testing.T) {
+	baseDB := memdb.New()
+	parent := New([]byte("parent"), baseDB)
+	combined := New([]byte("child"), parent)
+	nested := NewNested([]byte("child"), parent)
+
+	if err := parent.Put([]byte("key"), []byte("parent")); err != nil {
+		t.Fatal(err)
+	}
+	if err := combined.Put([]byte("key"), []byte("combined")); err != nil {
+		t.Fatal(err)
+	}
+	if err := nested.Put([]byte("key"), []byte("nested")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the keys of the parent and of the nested database are in the
+	// parent's key space
+	values := map[string]bool{}
+	iterator := parent.NewIterator()
+	defer iterator.Release()
+	for iterator.Next() {
+		values[string(iterator.Value())] = true
+	}
+	if err := iterator.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || !values["parent"] || !values["nested"] {
+		t.Fatalf("Iterating over the parent returned %v", values)
+	}
+}
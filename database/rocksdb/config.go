@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rocksdb
+
+import (
+	"runtime"
+)
+
+const (
+	// minBlockCacheSize is the minimum number of bytes to use for block caching
+	// in rocksdb.
+	minBlockCacheSize = 8 * 1024 * 1024
+
+	// minWriteBufferSize is the minimum number of bytes to use for buffers in
+	// rocksdb.
+	minWriteBufferSize = 8 * 1024 * 1024
+
+	// minHandleCap is the minimum number of files descriptors to cap rocksdb to
+	// use
+	minHandleCap = 16
+
+	// The defaults are sized for a node whose chains hold gigabytes of state
+	defaultBlockCacheSize  = 256 * 1024 * 1024
+	defaultWriteBufferSize = 128 * 1024 * 1024
+	defaultHandleCap       = 1024
+
+	// defaultBloomFilterBits is the number of bits per key of bloom filters
+	// unless configured otherwise
+	defaultBloomFilterBits = 10
+)
+
+// Config is the configuration of a RocksDB database. It sets the options of
+// the default column family, which holds all of the database's keys. Sizes
+// below the minimums are raised to them.
+type Config struct {
+	// Number of bytes to use for block caching
+	BlockCacheSize int
+	// Number of bytes to buffer writes in before they're written to disk
+	WriteBufferSize int
+	// Maximum number of files to keep open
+	HandleCap int
+	// Number of bits per key of the bloom filters that let reads skip tables
+	// that don't hold the key. If 0, tables don't have bloom filters.
+	BloomFilterBits int
+	// If true, tables are compressed with LZ4
+	Compression bool
+	// Number of background threads that flush and compact tables. If less
+	// than 1, one is used.
+	Parallelism int
+}
+
+// DefaultConfig returns the configuration New opens databases with
+func DefaultConfig() Config {
+	return Config{
+		BlockCacheSize:  defaultBlockCacheSize,
+		WriteBufferSize: defaultWriteBufferSize,
+		HandleCap:       defaultHandleCap,
+		BloomFilterBits: defaultBloomFilterBits,
+		Compression:     true,
+		Parallelism:     runtime.NumCPU(),
+	}
+}
@@ -0,0 +1,411 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build rocksdb
+// +build rocksdb
+
+package rocksdb
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/tecbot/gorocksdb"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+// Database is a persistent key-value store backed by RocksDB. Apart from basic
+// data storage functionality it also supports batch writes and iterating over
+// the keyspace in binary-alphabetical order.
+type Database struct {
+	lock sync.RWMutex
+	db   *gorocksdb.DB
+	opts *gorocksdb.Options
+	ro   *gorocksdb.ReadOptions
+	wo   *gorocksdb.WriteOptions
+
+	// Iterators that haven't been released. They're closed when the database
+	// is, as RocksDB requires.
+	iterators map[*iter]struct{}
+}
+
+// New returns a wrapped RocksDB object, opened with the default configuration
+// apart from the provided sizes
+func New(file string, blockCacheSize, writeBufferSize, handleCap int) (database.Database, error) {
+	config := DefaultConfig()
+	config.BlockCacheSize = blockCacheSize
+	config.WriteBufferSize = writeBufferSize
+	config.HandleCap = handleCap
+	return NewWithConfig(file, config)
+}
+
+// NewWithConfig returns a wrapped RocksDB object configured by [config]. Its
+// options are tuned for chain data, which is written once and read by key:
+// bloom filters keep reads of missing keys off disk, and levels are sized
+// dynamically with compactions spread across [config.Parallelism] threads, so
+// large databases don't stall writes while compacting.
+func NewWithConfig(file string, config Config) (database.Database, error) {
+	// Enforce minimums
+	if config.BlockCacheSize < minBlockCacheSize {
+		config.BlockCacheSize = minBlockCacheSize
+	}
+	if config.WriteBufferSize < minWriteBufferSize {
+		config.WriteBufferSize = minWriteBufferSize
+	}
+	if config.HandleCap < minHandleCap {
+		config.HandleCap = minHandleCap
+	}
+	if config.Parallelism < 1 {
+		config.Parallelism = 1
+	}
+
+	tableOpts := gorocksdb.NewDefaultBlockBasedTableOptions()
+	tableOpts.SetBlockCache(gorocksdb.NewLRUCache(uint64(config.BlockCacheSize)))
+	if config.BloomFilterBits > 0 {
+		tableOpts.SetFilterPolicy(gorocksdb.NewBloomFilter(config.BloomFilterBits))
+	}
+
+	opts := gorocksdb.NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	opts.SetBlockBasedTableFactory(tableOpts)
+	opts.SetMaxOpenFiles(config.HandleCap)
+	// There are two buffers of size WriteBuffer used.
+	opts.SetWriteBufferSize(config.WriteBufferSize / 2)
+	opts.SetMaxWriteBufferNumber(2)
+	opts.SetCompression(gorocksdb.NoCompression)
+	if config.Compression {
+		opts.SetCompression(gorocksdb.LZ4Compression)
+	}
+	opts.SetLevelCompactionDynamicLevelBytes(true)
+	opts.IncreaseParallelism(config.Parallelism)
+
+	db, err := gorocksdb.OpenDb(opts, file)
+	if err != nil {
+		opts.Destroy()
+		return nil, err
+	}
+	return &Database{
+		db:        db,
+		opts:      opts,
+		ro:        gorocksdb.NewDefaultReadOptions(),
+		wo:        gorocksdb.NewDefaultWriteOptions(),
+		iterators: make(map[*iter]struct{}),
+	}, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, database.ErrClosed
+	}
+	value, err := db.db.Get(db.ro, key)
+	if err != nil {
+		return false, err
+	}
+	defer value.Free()
+	return value.Exists(), nil
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	value, err := db.db.Get(db.ro, key)
+	if err != nil {
+		return nil, err
+	}
+	defer value.Free()
+	if !value.Exists() {
+		return nil, database.ErrNotFound
+	}
+	return copyBytes(value.Data()), nil
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	return db.db.Put(db.wo, key, value)
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	return db.db.Delete(db.wo, key)
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return &iter{err: database.ErrClosed}
+	}
+	seek := prefix
+	if bytes.Compare(start, prefix) == 1 {
+		seek = start
+	}
+	it := &iter{
+		db:     db,
+		it:     db.db.NewIterator(db.ro),
+		seek:   copyBytes(seek),
+		prefix: copyBytes(prefix),
+	}
+	db.iterators[it] = struct{}{}
+	return it
+}
+
+// Stat returns a particular internal stat of the database.
+func (db *Database) Stat(property string) (string, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return "", database.ErrClosed
+	}
+	return db.db.GetProperty(property), nil
+}
+
+// Compact the underlying DB for the given key range.
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	db.db.CompactRange(gorocksdb.Range{Start: start, Limit: limit})
+	return nil
+}
+
+// Close implements the Database interface
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return database.ErrClosed
+	}
+	for it := range db.iterators {
+		it.close(database.ErrClosed)
+	}
+	db.iterators = nil
+	db.ro.Destroy()
+	db.wo.Destroy()
+	db.db.Close()
+	db.opts.Destroy()
+	db.db = nil
+	return nil
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// batch buffers writes until they're written, so no RocksDB memory is held by
+// batches that are never written
+type batch struct {
+	db     *Database
+	writes []keyValue
+	size   int
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), copyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{copyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int { return b.size }
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return database.ErrClosed
+	}
+
+	wb := gorocksdb.NewWriteBatch()
+	defer wb.Destroy()
+	for _, kv := range b.writes {
+		if kv.delete {
+			wb.Delete(kv.key)
+		} else {
+			wb.Put(kv.key, kv.value)
+		}
+	}
+	return b.db.db.Write(b.db.wo, wb)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// iter is a wrapper around a RocksDB iterator that stops at the end of its
+// prefix
+type iter struct {
+	db     *Database
+	it     *gorocksdb.Iterator
+	seek   []byte
+	prefix []byte
+
+	started  bool
+	key, val []byte
+	err      error
+}
+
+// Next implements the Iterator interface
+func (it *iter) Next() bool {
+	it.key, it.val = nil, nil
+	if it.db == nil {
+		return false
+	}
+
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	// The iterator is closed once it's released or the database is closed
+	if it.it == nil {
+		return false
+	}
+	if it.started {
+		it.it.Next()
+	} else {
+		it.it.Seek(it.seek)
+		it.started = true
+	}
+	if !it.it.Valid() {
+		it.err = it.it.Err()
+		return false
+	}
+
+	key := it.it.Key()
+	defer key.Free()
+	if !bytes.HasPrefix(key.Data(), it.prefix) {
+		return false
+	}
+	value := it.it.Value()
+	defer value.Free()
+	it.key = copyBytes(key.Data())
+	it.val = copyBytes(value.Data())
+	return true
+}
+
+// Error implements the Iterator interface
+func (it *iter) Error() error { return it.err }
+
+// Key implements the Iterator interface
+func (it *iter) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iter) Value() []byte { return it.val }
+
+// Release implements the Iterator interface
+func (it *iter) Release() {
+	if it.db == nil {
+		return
+	}
+
+	it.db.lock.Lock()
+	defer it.db.lock.Unlock()
+
+	if it.it != nil {
+		delete(it.db.iterators, it)
+		it.close(nil)
+	}
+}
+
+// close releases the underlying iterator, leaving [err] to be reported
+// Assumes the database's lock is held
+func (it *iter) close(err error) {
+	it.it.Close()
+	it.it = nil
+	if it.err == nil {
+		it.err = err
+	}
+}
+
+func copyBytes(bytes []byte) []byte {
+	if bytes == nil {
+		return nil
+	}
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build rocksdb
+// +build rocksdb
+
+package rocksdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+func TestInterface(t *testing.T) {
+	for i, test := range database.Tests {
+		folder := fmt.Sprintf("db%d", i)
+
+		db, err := New(folder, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("rocksdb.New(%s, 0, 0, 0) errored with %s", folder, err)
+		}
+		defer os.RemoveAll(folder)
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestInterfaceWithConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.BloomFilterBits = 0
+	config.Compression = false
+	config.Parallelism = 0
+	for i, test := range database.Tests {
+		folder := fmt.Sprintf("db-config%d", i)
+
+		db, err := NewWithConfig(folder, config)
+		if err != nil {
+			t.Fatalf("rocksdb.NewWithConfig(%s) errored with %s", folder, err)
+		}
+		defer os.RemoveAll(folder)
+		defer db.Close()
+
+		test(t, db)
+	}
+}
+
+func TestIteratorReleasedByClose(t *testing.T) {
+	folder := "db-iterator"
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	it := db.NewIterator()
+	defer it.Release()
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if it.Next() {
+		t.Fatalf("The iterator should have been released when the database was closed")
+	} else if err := it.Error(); err != database.ErrClosed {
+		t.Fatalf("Expected %s on iterator.Error but got %v", database.ErrClosed, err)
+	}
+}
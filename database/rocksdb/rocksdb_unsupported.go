@@ -0,0 +1,27 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !rocksdb
+// +build !rocksdb
+
+package rocksdb
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+var errUnsupported = errors.New("this binary was built without RocksDB support. Build it with -tags rocksdb to use RocksDB")
+
+// New returns an error, as RocksDB requires cgo and is only built with the
+// rocksdb build tag
+func New(file string, blockCacheSize, writeBufferSize, handleCap int) (database.Database, error) {
+	return nil, errUnsupported
+}
+
+// NewWithConfig returns an error, as RocksDB requires cgo and is only built
+// with the rocksdb build tag
+func NewWithConfig(file string, config Config) (database.Database, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,218 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"errors"
+	"net/rpc"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/nodb"
+)
+
+// DatabaseClient is a database that is served by a DatabaseServer
+type DatabaseClient struct{ client *rpc.Client }
+
+// NewClient returns a database that makes calls to the DatabaseServer
+// registered on [client]
+func NewClient(client *rpc.Client) *DatabaseClient { return &DatabaseClient{client: client} }
+
+// Has implements the Database interface
+func (db *DatabaseClient) Has(key []byte) (bool, error) {
+	reply := HasReply{}
+	err := db.call("Has", &KeyArgs{Key: key}, &reply)
+	return reply.Has, err
+}
+
+// Get implements the Database interface
+func (db *DatabaseClient) Get(key []byte) ([]byte, error) {
+	reply := GetReply{}
+	if err := db.call("Get", &KeyArgs{Key: key}, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Value == nil {
+		// The empty value is sent as nil, but nil means not found
+		reply.Value = []byte{}
+	}
+	return reply.Value, nil
+}
+
+// Put implements the Database interface
+func (db *DatabaseClient) Put(key, value []byte) error {
+	return db.call("Put", &PutArgs{Key: key, Value: value}, &Empty{})
+}
+
+// Delete implements the Database interface
+func (db *DatabaseClient) Delete(key []byte) error {
+	return db.call("Delete", &KeyArgs{Key: key}, &Empty{})
+}
+
+// NewBatch implements the Database interface
+func (db *DatabaseClient) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator implements the Database interface
+func (db *DatabaseClient) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Database interface
+func (db *DatabaseClient) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *DatabaseClient) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *DatabaseClient) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	reply := IteratorArgs{}
+	if err := db.call("NewIterator", &NewIteratorArgs{Start: start, Prefix: prefix}, &reply); err != nil {
+		return &nodb.Iterator{Err: err}
+	}
+	return &iterator{db: db, id: reply.ID}
+}
+
+// Stat implements the Database interface
+func (db *DatabaseClient) Stat(property string) (string, error) {
+	reply := StatReply{}
+	err := db.call("Stat", &StatArgs{Property: property}, &reply)
+	return reply.Stat, err
+}
+
+// Compact implements the Database interface
+func (db *DatabaseClient) Compact(start, limit []byte) error {
+	return db.call("Compact", &CompactArgs{Start: start, Limit: limit}, &Empty{})
+}
+
+// Close implements the Database interface
+func (db *DatabaseClient) Close() error { return db.call("Close", &Empty{}, &Empty{}) }
+
+// call [method] of the server and convert the error it returned back into the
+// database's errors
+func (db *DatabaseClient) call(method string, args, reply interface{}) error {
+	err := db.client.Call("Database."+method, args, reply)
+	switch err := err.(type) {
+	case nil:
+		return nil
+	case rpc.ServerError:
+		switch string(err) {
+		case database.ErrClosed.Error():
+			return database.ErrClosed
+		case database.ErrNotFound.Error():
+			return database.ErrNotFound
+		default:
+			return errors.New(string(err))
+		}
+	default:
+		return err
+	}
+}
+
+type batch struct {
+	db   *DatabaseClient
+	ops  []BatchOp
+	size int
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: copyBytes(key), Value: copyBytes(value)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: copyBytes(key), Delete: true})
+	b.size++
+	return nil
+}
+
+// ValueSize implements the Batch interface
+func (b *batch) ValueSize() int { return b.size }
+
+// Write implements the Batch interface
+func (b *batch) Write() error { return b.db.call("WriteBatch", &WriteBatchArgs{Ops: b.ops}, &Empty{}) }
+
+// Reset implements the Batch interface
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+// Replay implements the Batch interface
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+type iterator struct {
+	db *DatabaseClient
+	id uint64
+
+	// Key/value pairs fetched from the server that haven't been iterated over
+	keys, values [][]byte
+	key, value   []byte
+
+	exhausted, released bool
+	err                 error
+}
+
+// Next implements the Iterator interface
+func (it *iterator) Next() bool {
+	if len(it.keys) == 0 && !it.exhausted {
+		reply := IteratorNextReply{}
+		if it.err = it.db.call("IteratorNext", &IteratorArgs{ID: it.id}, &reply); it.err != nil {
+			reply = IteratorNextReply{}
+		}
+		it.keys, it.values = reply.Keys, reply.Values
+		it.exhausted = len(it.keys) == 0
+	}
+	if len(it.keys) == 0 {
+		it.key, it.value = nil, nil
+		return false
+	}
+	it.key, it.value = it.keys[0], it.values[0]
+	if it.value == nil {
+		it.value = []byte{}
+	}
+	it.keys, it.values = it.keys[1:], it.values[1:]
+	return true
+}
+
+// Error implements the Iterator interface
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.db.call("IteratorError", &IteratorArgs{ID: it.id}, &Empty{})
+}
+
+// Key implements the Iterator interface
+func (it *iterator) Key() []byte { return it.key }
+
+// Value implements the Iterator interface
+func (it *iterator) Value() []byte { return it.value }
+
+// Release implements the Iterator interface
+func (it *iterator) Release() {
+	if it.released {
+		return
+	}
+	it.released = true
+	it.keys, it.values = nil, nil
+	it.key, it.value = nil, nil
+	it.db.call("IteratorRelease", &IteratorArgs{ID: it.id}, &Empty{})
+}
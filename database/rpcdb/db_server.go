@@ -0,0 +1,195 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+const (
+	// Max number of key/value pairs sent in response to an iterator's Next
+	iteratorBatchSize = 128
+)
+
+var (
+	errUnknownIterator = errors.New("unknown iterator")
+)
+
+// DatabaseServer exposes a database over net/rpc
+// It is registered under the name "Database"
+type DatabaseServer struct {
+	db database.Database
+
+	lock           sync.Mutex
+	nextIteratorID uint64
+	iterators      map[uint64]database.Iterator
+}
+
+// NewServer returns a server that exposes [db]
+func NewServer(db database.Database) *DatabaseServer {
+	return &DatabaseServer{
+		db:        db,
+		iterators: make(map[uint64]database.Iterator),
+	}
+}
+
+// Empty is the argument and reply of calls that have none
+type Empty struct{}
+
+// KeyArgs are the arguments of calls that take a key
+type KeyArgs struct{ Key []byte }
+
+// HasReply is the reply of Has
+type HasReply struct{ Has bool }
+
+// Has implements the Database interface
+func (db *DatabaseServer) Has(args *KeyArgs, reply *HasReply) error {
+	has, err := db.db.Has(args.Key)
+	reply.Has = has
+	return err
+}
+
+// GetReply is the reply of Get
+type GetReply struct{ Value []byte }
+
+// Get implements the Database interface
+func (db *DatabaseServer) Get(args *KeyArgs, reply *GetReply) error {
+	value, err := db.db.Get(args.Key)
+	reply.Value = value
+	return err
+}
+
+// PutArgs are the arguments of Put
+type PutArgs struct{ Key, Value []byte }
+
+// Put implements the Database interface
+func (db *DatabaseServer) Put(args *PutArgs, _ *Empty) error { return db.db.Put(args.Key, args.Value) }
+
+// Delete implements the Database interface
+func (db *DatabaseServer) Delete(args *KeyArgs, _ *Empty) error { return db.db.Delete(args.Key) }
+
+// StatArgs are the arguments of Stat
+type StatArgs struct{ Property string }
+
+// StatReply is the reply of Stat
+type StatReply struct{ Stat string }
+
+// Stat implements the Database interface
+func (db *DatabaseServer) Stat(args *StatArgs, reply *StatReply) error {
+	stat, err := db.db.Stat(args.Property)
+	reply.Stat = stat
+	return err
+}
+
+// CompactArgs are the arguments of Compact
+type CompactArgs struct{ Start, Limit []byte }
+
+// Compact implements the Database interface
+func (db *DatabaseServer) Compact(args *CompactArgs, _ *Empty) error {
+	return db.db.Compact(args.Start, args.Limit)
+}
+
+// Close implements the Database interface
+func (db *DatabaseServer) Close(_ *Empty, _ *Empty) error { return db.db.Close() }
+
+// BatchOp is a write in a batch
+type BatchOp struct {
+	Key, Value []byte
+	Delete     bool
+}
+
+// WriteBatchArgs are the arguments of WriteBatch
+type WriteBatchArgs struct{ Ops []BatchOp }
+
+// WriteBatch atomically writes [args.Ops] to the database
+func (db *DatabaseServer) WriteBatch(args *WriteBatchArgs, _ *Empty) error {
+	batch := db.db.NewBatch()
+	for _, op := range args.Ops {
+		if op.Delete {
+			if err := batch.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := batch.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// NewIteratorArgs are the arguments of NewIterator
+type NewIteratorArgs struct{ Start, Prefix []byte }
+
+// IteratorArgs are the arguments of calls on an iterator
+type IteratorArgs struct{ ID uint64 }
+
+// NewIterator creates an iterator over the database
+func (db *DatabaseServer) NewIterator(args *NewIteratorArgs, reply *IteratorArgs) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	id := db.nextIteratorID
+	db.nextIteratorID++
+	db.iterators[id] = db.db.NewIteratorWithStartAndPrefix(args.Start, args.Prefix)
+	reply.ID = id
+	return nil
+}
+
+// IteratorNextReply is the reply of IteratorNext
+type IteratorNextReply struct{ Keys, Values [][]byte }
+
+// IteratorNext returns the next key/value pairs of an iterator
+// If the iterator is exhausted, no pairs are returned
+func (db *DatabaseServer) IteratorNext(args *IteratorArgs, reply *IteratorNextReply) error {
+	it, err := db.iterator(args.ID)
+	if err != nil {
+		return err
+	}
+	for len(reply.Keys) < iteratorBatchSize && it.Next() {
+		// The iterator may reuse the returned slices on the next call to Next
+		reply.Keys = append(reply.Keys, copyBytes(it.Key()))
+		reply.Values = append(reply.Values, copyBytes(it.Value()))
+	}
+	return nil
+}
+
+// IteratorError returns the error of an iterator
+func (db *DatabaseServer) IteratorError(args *IteratorArgs, _ *Empty) error {
+	it, err := db.iterator(args.ID)
+	if err != nil {
+		return err
+	}
+	return it.Error()
+}
+
+// IteratorRelease releases an iterator
+func (db *DatabaseServer) IteratorRelease(args *IteratorArgs, _ *Empty) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if it, exists := db.iterators[args.ID]; exists {
+		delete(db.iterators, args.ID)
+		it.Release()
+	}
+	return nil
+}
+
+func (db *DatabaseServer) iterator(id uint64) (database.Iterator, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	it, exists := db.iterators[id]
+	if !exists {
+		return nil, errUnknownIterator
+	}
+	return it, nil
+}
+
+func copyBytes(bytes []byte) []byte {
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"net"
+	"net/rpc"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func setupDB(t *testing.T) (*DatabaseClient, func()) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Database", NewServer(memdb.New())); err != nil {
+		t.Fatal(err)
+	}
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	client := rpc.NewClient(clientConn)
+	return NewClient(client), func() { client.Close() }
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db, closeFn := setupDB(t)
+		test(t, db)
+		closeFn()
+	}
+}
+
+func TestIteratorBatches(t *testing.T) {
+	db, closeFn := setupDB(t)
+	defer closeFn()
+
+	numKeys := 2*iteratorBatchSize + 1
+	for i := 0; i < numKeys; i++ {
+		if err := db.Put([]byte{byte(i >> 8), byte(i)}, []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for i := 0; i < numKeys; i++ {
+		if !it.Next() {
+			t.Fatalf("iterator stopped after %d keys but should have returned %d", i, numKeys)
+		}
+		if key := it.Key(); len(key) != 2 || int(key[0])<<8|int(key[1]) != i {
+			t.Fatalf("iterator returned key 0x%x at position %d", key, i)
+		}
+		if value := it.Value(); len(value) != 1 || value[0] != byte(i) {
+			t.Fatalf("iterator returned value 0x%x at position %d", value, i)
+		}
+	}
+	if it.Next() {
+		t.Fatalf("iterator should have been exhausted")
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
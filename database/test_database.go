@@ -22,6 +22,8 @@ var (
 		TestIteratorPrefix,
 		TestIteratorStartPrefix,
 		TestIteratorClosed,
+		TestIteratorSnapshot,
+		TestCollect,
 		TestStatNoPanic,
 		TestCompactNoPanic,
 	}
@@ -494,6 +496,83 @@ func TestIteratorClosed(t *testing.T, db Database) {
 	}
 }
 
+// TestIteratorSnapshot ...
+func TestIteratorSnapshot(t *testing.T, db Database) {
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	iterator := db.NewIterator()
+	if iterator == nil {
+		t.Fatalf("db.NewIterator returned nil")
+	}
+	defer iterator.Release()
+
+	// Changes made after the iterator is created aren't seen by it
+	if err := db.Put(key2, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db.Put(key1, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	} else if err := db.Delete(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+
+	if !iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", false, true)
+	} else if key := iterator.Key(); !bytes.Equal(key, key1) {
+		t.Fatalf("iterator.Key Returned: 0x%x ; Expected: 0x%x", key, key1)
+	} else if value := iterator.Value(); !bytes.Equal(value, value1) {
+		t.Fatalf("iterator.Value Returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", true, false)
+	} else if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+}
+
+// TestCollect ...
+func TestCollect(t *testing.T, db Database) {
+	keys := [][]byte{[]byte("hello1"), []byte("hello2"), []byte("hello3")}
+	for _, key := range keys {
+		if err := db.Put(key, key); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+
+	iterator := db.NewIterator()
+	defer iterator.Release()
+
+	pairs, err := Collect(iterator, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error on Collect: %s", err)
+	} else if len(pairs) != 2 {
+		t.Fatalf("Collect Returned %d pairs ; Expected: %d", len(pairs), 2)
+	}
+	// The next page starts after the last key returned
+	nextIterator := db.NewIteratorWithStart(append(pairs[1].Key, 0))
+	defer nextIterator.Release()
+	nextPairs, err := Collect(nextIterator, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error on Collect: %s", err)
+	} else if len(nextPairs) != 1 {
+		t.Fatalf("Collect Returned %d pairs ; Expected: %d", len(nextPairs), 1)
+	}
+
+	for i, pair := range append(pairs, nextPairs...) {
+		if !bytes.Equal(pair.Key, keys[i]) {
+			t.Fatalf("Collect Returned key 0x%x ; Expected: 0x%x", pair.Key, keys[i])
+		} else if !bytes.Equal(pair.Value, keys[i]) {
+			t.Fatalf("Collect Returned value 0x%x ; Expected: 0x%x", pair.Value, keys[i])
+		}
+	}
+}
+
 // TestStatNoPanic ...
 func TestStatNoPanic(t *testing.T, db Database) {
 	key1 := []byte("hello1")
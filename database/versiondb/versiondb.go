@@ -16,6 +16,10 @@ import (
 // Database implements the Database interface by living on top of another
 // database, writing changes to the underlying database only when commit is
 // called.
+// Databases can be layered on top of each other, such as one per block on top
+// of its parent block's, so that the changes of a block are staged until it's
+// accepted and then committed atomically, or dropped with Abort if it's
+// rejected.
 type Database struct {
 	lock sync.RWMutex
 	mem  map[string]valueDelete
@@ -27,7 +31,7 @@ type valueDelete struct {
 	delete bool
 }
 
-// New returns a new prefixed database
+// New returns a new database that stages changes on top of [db]
 func New(db database.Database) *Database {
 	return &Database{
 		mem: make(map[string]valueDelete, memdb.DefaultSize),
@@ -191,22 +195,59 @@ func (db *Database) Commit() error {
 		return nil
 	}
 
+	batch, err := db.commitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	db.abort()
+	return nil
+}
+
+// Abort all changes to the underlying database
+func (db *Database) Abort() {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.mem != nil {
+		db.abort()
+	}
+}
+
+func (db *Database) abort() { db.mem = make(map[string]valueDelete, memdb.DefaultSize) }
+
+// CommitBatch returns a batch that contains all uncommitted puts/deletes.
+// Calling Write() on the returned batch causes the puts/deletes to be written
+// to the underlying database. The returned batch should be written before
+// future calls to this DB unless the batch will never be written. Once the
+// batch has been written, Abort should be called to clear the pending
+// operations from this database.
+func (db *Database) CommitBatch() (database.Batch, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.commitBatch()
+}
+
+// Put all of the puts/deletes in memory into db.batch and return the batch
+func (db *Database) commitBatch() (database.Batch, error) {
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+
 	batch := db.db.NewBatch()
 	for key, value := range db.mem {
 		if value.delete {
 			if err := batch.Delete([]byte(key)); err != nil {
-				return err
+				return nil, err
 			}
 		} else if err := batch.Put([]byte(key), value.value); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	if err := batch.Write(); err != nil {
-		return err
-	}
-
-	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
-	return nil
+	return batch, nil
 }
 
 // Close implements the database.Database interface
@@ -289,6 +330,9 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	return nil
 }
 
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
 // iterator walks over both the in memory database and the underlying database
 // at the same time.
 type iterator struct {
@@ -209,6 +209,70 @@ func TestCommit(t *testing.T) {
 	}
 }
 
+func TestCommitBatch(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	batch, err := db.CommitBatch()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.CommitBatch: %s", err)
+	}
+
+	if has, err := baseDB.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Has: %s", err)
+	} else if has {
+		t.Fatalf("Unexpected result of baseDB.Has: %v", has)
+	}
+
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Unexpected error on batch.Write: %s", err)
+	}
+	db.Abort()
+
+	if value, err := baseDB.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("baseDB.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if value, err := db.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("db.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	baseDB := memdb.New()
+	db := New(baseDB)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	db.Abort()
+
+	if has, err := db.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("Unexpected result of db.Has: %v", has)
+	} else if err := db.Commit(); err != nil {
+		t.Fatalf("Unexpected error on db.Commit: %s", err)
+	} else if has, err := baseDB.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Has: %s", err)
+	} else if has {
+		t.Fatalf("Unexpected result of baseDB.Has: %v", has)
+	}
+}
+
 func TestCommitClosed(t *testing.T) {
 	baseDB := memdb.New()
 	db := New(baseDB)
@@ -297,3 +361,114 @@ func TestSetDatabaseClosed(t *testing.T) {
 		t.Fatalf("Unexpected database from db.GetDatabase")
 	}
 }
+
+func TestNestedLayers(t *testing.T) {
+	baseDB := memdb.New()
+	parent := New(baseDB)
+	child := New(parent)
+
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+
+	if err := baseDB.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Put: %s", err)
+	}
+	if err := child.Delete(key1); err != nil {
+		t.Fatalf("Unexpected error on child.Delete: %s", err)
+	}
+	if err := child.Put(key2, value2); err != nil {
+		t.Fatalf("Unexpected error on child.Put: %s", err)
+	}
+
+	if has, err := child.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on child.Has: %s", err)
+	} else if has {
+		t.Fatalf("The child's delete should shadow the base's value")
+	}
+	if has, err := parent.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on parent.Has: %s", err)
+	} else if has {
+		t.Fatalf("The child's changes shouldn't be visible before it's committed")
+	}
+
+	if err := child.Commit(); err != nil {
+		t.Fatalf("Unexpected error on child.Commit: %s", err)
+	}
+	if has, err := parent.Has(key1); err != nil {
+		t.Fatalf("Unexpected error on parent.Has: %s", err)
+	} else if has {
+		t.Fatalf("The child's delete should have been committed to the parent")
+	}
+	if value, err := parent.Get(key2); err != nil {
+		t.Fatalf("Unexpected error on parent.Get: %s", err)
+	} else if !bytes.Equal(value, value2) {
+		t.Fatalf("parent.Get Returned: 0x%x ; Expected: 0x%x", value, value2)
+	}
+	if has, err := baseDB.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Has: %s", err)
+	} else if has {
+		t.Fatalf("The child's changes shouldn't reach the base before the parent is committed")
+	}
+
+	// Aborting the parent drops the changes committed to it by the child
+	parent.Abort()
+	if value, err := child.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on child.Get: %s", err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("child.Get Returned: 0x%x ; Expected: 0x%x", value, value1)
+	}
+	if has, err := child.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on child.Has: %s", err)
+	} else if has {
+		t.Fatalf("The aborted changes shouldn't be visible")
+	}
+}
+
+func TestNestedLayersIterate(t *testing.T) {
+	baseDB := memdb.New()
+	parent := New(baseDB)
+	child := New(parent)
+
+	if err := baseDB.Put([]byte("a"), []byte("base")); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Put: %s", err)
+	}
+	if err := baseDB.Put([]byte("b"), []byte("base")); err != nil {
+		t.Fatalf("Unexpected error on baseDB.Put: %s", err)
+	}
+	if err := parent.Put([]byte("b"), []byte("parent")); err != nil {
+		t.Fatalf("Unexpected error on parent.Put: %s", err)
+	}
+	if err := parent.Put([]byte("c"), []byte("parent")); err != nil {
+		t.Fatalf("Unexpected error on parent.Put: %s", err)
+	}
+	if err := child.Delete([]byte("a")); err != nil {
+		t.Fatalf("Unexpected error on child.Delete: %s", err)
+	}
+	if err := child.Put([]byte("d"), []byte("child")); err != nil {
+		t.Fatalf("Unexpected error on child.Put: %s", err)
+	}
+
+	expected := []struct{ key, value string }{
+		{"b", "parent"},
+		{"c", "parent"},
+		{"d", "child"},
+	}
+	iterator := child.NewIterator()
+	defer iterator.Release()
+	for _, kv := range expected {
+		if !iterator.Next() {
+			t.Fatalf("iterator.Next Returned: false ; Expected: true")
+		} else if key := string(iterator.Key()); key != kv.key {
+			t.Fatalf("iterator.Key Returned: %s ; Expected: %s", key, kv.key)
+		} else if value := string(iterator.Value()); value != kv.value {
+			t.Fatalf("iterator.Value Returned: %s ; Expected: %s", value, kv.value)
+		}
+	}
+	if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: true ; Expected: false")
+	} else if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+}
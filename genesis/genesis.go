@@ -6,16 +6,23 @@ package genesis
 // TODO: Move this to a separate repo and leave only a byte array
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 	"github.com/ava-labs/gecko/vms/timestampvm"
@@ -126,6 +133,60 @@ func NetworkID(networkName string) (uint32, error) {
 	return 0, fmt.Errorf("Failed to parse %s as a network name", networkName)
 }
 
+// MaxClockDrift returns how far ahead of a node's clock a proposed Platform
+// Chain timestamp may be on the network with ID [networkID]
+func MaxClockDrift(networkID uint32) time.Duration {
+	switch networkID {
+	case MainnetID, TestnetID:
+		return platformvm.Delta
+	default:
+		// Local and custom networks are often run on machines whose clocks
+		// aren't kept in sync
+		return time.Minute
+	}
+}
+
+// Fees are the transaction fees, in nAVA, charged on a network. The fees are
+// burned.
+type Fees struct {
+	// Charged by each transaction that doesn't create an asset, subnet or
+	// blockchain
+	TxFee uint64
+	// Charged by each transaction that creates an asset, subnet or blockchain
+	CreationTxFee uint64
+}
+
+// GetFees returns the transaction fees charged on the network with ID
+// [networkID]
+func GetFees(networkID uint32) Fees {
+	switch networkID {
+	case MainnetID, TestnetID:
+		return Fees{
+			TxFee:         units.MilliAva,
+			CreationTxFee: 10 * units.MilliAva,
+		}
+	default:
+		// Local and custom networks are used for testing, so transactions
+		// are free
+		return Fees{}
+	}
+}
+
+// GetStakingParameters returns how much, and for how long, stakers on the
+// default subnet of the network with ID [networkID] may stake
+func GetStakingParameters(networkID uint32) platformvm.StakingParameters {
+	switch networkID {
+	case MainnetID, TestnetID:
+		return platformvm.DefaultStakingParameters
+	default:
+		// Local and custom networks are used for testing, so stakers may
+		// stake for much less time
+		params := platformvm.DefaultStakingParameters
+		params.MinStakeDuration = 5 * time.Minute
+		return params
+	}
+}
+
 // Aliases returns the default aliases based on the network ID
 func Aliases(networkID uint32) (generalAliases map[string][]string, chainAliases map[[32]byte][]string, vmAliases map[[32]byte][]string) {
 	generalAliases = map[string][]string{
@@ -286,12 +347,20 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x05, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03,
 		0x41, 0x56, 0x4d, 0x61, 0x76, 0x6d, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x73,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x6e,
+		0x66, 0x74, 0x66, 0x78, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x73,
 		0x65, 0x63, 0x70, 0x32, 0x35, 0x36, 0x6b, 0x31,
 		0x66, 0x78, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -305,8 +374,8 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x03, 0x41, 0x56, 0x41, 0x00, 0x03, 0x41,
 		0x56, 0x41, 0x09, 0x00, 0x00, 0x00, 0x01, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
-		0x00, 0x00, 0x04, 0x00, 0x9f, 0xdf, 0x42, 0xf6,
+		0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x00, 0x00, 0x09, 0x00, 0x9f, 0xdf, 0x42, 0xf6,
 		0xe4, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
 		0x00, 0x00, 0x01, 0x3c, 0xb7, 0xd3, 0x84, 0x2e,
@@ -320,62 +389,67 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x30, 0x39, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x41, 0x74,
-		0x68, 0x65, 0x72, 0x65, 0x75, 0x6d, 0x65, 0x76,
-		0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x02, 0xc9, 0x7b, 0x22,
-		0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x3a,
-		0x7b, 0x22, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x49,
-		0x64, 0x22, 0x3a, 0x34, 0x33, 0x31, 0x31, 0x30,
-		0x2c, 0x22, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x74,
-		0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-		0x22, 0x3a, 0x30, 0x2c, 0x22, 0x64, 0x61, 0x6f,
-		0x46, 0x6f, 0x72, 0x6b, 0x42, 0x6c, 0x6f, 0x63,
-		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x64, 0x61,
-		0x6f, 0x46, 0x6f, 0x72, 0x6b, 0x53, 0x75, 0x70,
-		0x70, 0x6f, 0x72, 0x74, 0x22, 0x3a, 0x74, 0x72,
-		0x75, 0x65, 0x2c, 0x22, 0x65, 0x69, 0x70, 0x31,
-		0x35, 0x30, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22,
-		0x3a, 0x30, 0x2c, 0x22, 0x65, 0x69, 0x70, 0x31,
-		0x35, 0x30, 0x48, 0x61, 0x73, 0x68, 0x22, 0x3a,
-		0x22, 0x30, 0x78, 0x32, 0x30, 0x38, 0x36, 0x37,
-		0x39, 0x39, 0x61, 0x65, 0x65, 0x62, 0x65, 0x61,
-		0x65, 0x31, 0x33, 0x35, 0x63, 0x32, 0x34, 0x36,
-		0x63, 0x36, 0x35, 0x30, 0x32, 0x31, 0x63, 0x38,
-		0x32, 0x62, 0x34, 0x65, 0x31, 0x35, 0x61, 0x32,
-		0x63, 0x34, 0x35, 0x31, 0x33, 0x34, 0x30, 0x39,
-		0x39, 0x33, 0x61, 0x61, 0x63, 0x66, 0x64, 0x32,
-		0x37, 0x35, 0x31, 0x38, 0x38, 0x36, 0x35, 0x31,
-		0x34, 0x66, 0x30, 0x22, 0x2c, 0x22, 0x65, 0x69,
-		0x70, 0x31, 0x35, 0x35, 0x42, 0x6c, 0x6f, 0x63,
-		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x65, 0x69,
-		0x70, 0x31, 0x35, 0x38, 0x42, 0x6c, 0x6f, 0x63,
-		0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22, 0x62, 0x79,
-		0x7a, 0x61, 0x6e, 0x74, 0x69, 0x75, 0x6d, 0x42,
-		0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c,
-		0x22, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x61, 0x6e,
-		0x74, 0x69, 0x6e, 0x6f, 0x70, 0x6c, 0x65, 0x42,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x39,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x08, 0x41, 0x74, 0x68, 0x65, 0x72, 0x65,
+		0x75, 0x6d, 0x65, 0x76, 0x6d, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x02, 0xc9, 0x7b, 0x22, 0x63, 0x6f, 0x6e, 0x66,
+		0x69, 0x67, 0x22, 0x3a, 0x7b, 0x22, 0x63, 0x68,
+		0x61, 0x69, 0x6e, 0x49, 0x64, 0x22, 0x3a, 0x34,
+		0x33, 0x31, 0x31, 0x30, 0x2c, 0x22, 0x68, 0x6f,
+		0x6d, 0x65, 0x73, 0x74, 0x65, 0x61, 0x64, 0x42,
 		0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c,
-		0x22, 0x70, 0x65, 0x74, 0x65, 0x72, 0x73, 0x62,
-		0x75, 0x72, 0x67, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-		0x22, 0x3a, 0x30, 0x7d, 0x2c, 0x22, 0x6e, 0x6f,
-		0x6e, 0x63, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78,
-		0x30, 0x22, 0x2c, 0x22, 0x74, 0x69, 0x6d, 0x65,
-		0x73, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x65, 0x78,
-		0x74, 0x72, 0x61, 0x44, 0x61, 0x74, 0x61, 0x22,
-		0x3a, 0x22, 0x30, 0x78, 0x30, 0x30, 0x22, 0x2c,
-		0x22, 0x67, 0x61, 0x73, 0x4c, 0x69, 0x6d, 0x69,
-		0x74, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x35, 0x66,
-		0x35, 0x65, 0x31, 0x30, 0x30, 0x22, 0x2c, 0x22,
-		0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c,
-		0x74, 0x79, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
-		0x22, 0x2c, 0x22, 0x6d, 0x69, 0x78, 0x48, 0x61,
-		0x73, 0x68, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
+		0x22, 0x64, 0x61, 0x6f, 0x46, 0x6f, 0x72, 0x6b,
+		0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30,
+		0x2c, 0x22, 0x64, 0x61, 0x6f, 0x46, 0x6f, 0x72,
+		0x6b, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74,
+		0x22, 0x3a, 0x74, 0x72, 0x75, 0x65, 0x2c, 0x22,
+		0x65, 0x69, 0x70, 0x31, 0x35, 0x30, 0x42, 0x6c,
+		0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x2c, 0x22,
+		0x65, 0x69, 0x70, 0x31, 0x35, 0x30, 0x48, 0x61,
+		0x73, 0x68, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x32,
+		0x30, 0x38, 0x36, 0x37, 0x39, 0x39, 0x61, 0x65,
+		0x65, 0x62, 0x65, 0x61, 0x65, 0x31, 0x33, 0x35,
+		0x63, 0x32, 0x34, 0x36, 0x63, 0x36, 0x35, 0x30,
+		0x32, 0x31, 0x63, 0x38, 0x32, 0x62, 0x34, 0x65,
+		0x31, 0x35, 0x61, 0x32, 0x63, 0x34, 0x35, 0x31,
+		0x33, 0x34, 0x30, 0x39, 0x39, 0x33, 0x61, 0x61,
+		0x63, 0x66, 0x64, 0x32, 0x37, 0x35, 0x31, 0x38,
+		0x38, 0x36, 0x35, 0x31, 0x34, 0x66, 0x30, 0x22,
+		0x2c, 0x22, 0x65, 0x69, 0x70, 0x31, 0x35, 0x35,
+		0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30,
+		0x2c, 0x22, 0x65, 0x69, 0x70, 0x31, 0x35, 0x38,
+		0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30,
+		0x2c, 0x22, 0x62, 0x79, 0x7a, 0x61, 0x6e, 0x74,
+		0x69, 0x75, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+		0x22, 0x3a, 0x30, 0x2c, 0x22, 0x63, 0x6f, 0x6e,
+		0x73, 0x74, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x6f,
+		0x70, 0x6c, 0x65, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+		0x22, 0x3a, 0x30, 0x2c, 0x22, 0x70, 0x65, 0x74,
+		0x65, 0x72, 0x73, 0x62, 0x75, 0x72, 0x67, 0x42,
+		0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x3a, 0x30, 0x7d,
+		0x2c, 0x22, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22,
+		0x3a, 0x22, 0x30, 0x78, 0x30, 0x22, 0x2c, 0x22,
+		0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+		0x70, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x22,
+		0x2c, 0x22, 0x65, 0x78, 0x74, 0x72, 0x61, 0x44,
+		0x61, 0x74, 0x61, 0x22, 0x3a, 0x22, 0x30, 0x78,
+		0x30, 0x30, 0x22, 0x2c, 0x22, 0x67, 0x61, 0x73,
+		0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3a, 0x22,
+		0x30, 0x78, 0x35, 0x66, 0x35, 0x65, 0x31, 0x30,
+		0x30, 0x22, 0x2c, 0x22, 0x64, 0x69, 0x66, 0x66,
+		0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x6d,
+		0x69, 0x78, 0x48, 0x61, 0x73, 0x68, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
@@ -383,32 +457,32 @@ func Genesis(networkID uint32) []byte {
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22,
-		0x2c, 0x22, 0x63, 0x6f, 0x69, 0x6e, 0x62, 0x61,
-		0x73, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30,
+		0x30, 0x30, 0x30, 0x22, 0x2c, 0x22, 0x63, 0x6f,
+		0x69, 0x6e, 0x62, 0x61, 0x73, 0x65, 0x22, 0x3a,
+		0x22, 0x30, 0x78, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22,
-		0x2c, 0x22, 0x61, 0x6c, 0x6c, 0x6f, 0x63, 0x22,
-		0x3a, 0x7b, 0x22, 0x37, 0x35, 0x31, 0x61, 0x30,
-		0x62, 0x39, 0x36, 0x65, 0x31, 0x30, 0x34, 0x32,
-		0x62, 0x65, 0x65, 0x37, 0x38, 0x39, 0x34, 0x35,
-		0x32, 0x65, 0x63, 0x62, 0x32, 0x30, 0x32, 0x35,
-		0x33, 0x66, 0x62, 0x61, 0x34, 0x30, 0x64, 0x62,
-		0x65, 0x38, 0x35, 0x22, 0x3a, 0x7b, 0x22, 0x62,
-		0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x3a,
-		0x22, 0x30, 0x78, 0x33, 0x33, 0x62, 0x32, 0x65,
-		0x33, 0x63, 0x39, 0x66, 0x64, 0x30, 0x38, 0x30,
-		0x34, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x22, 0x7d, 0x7d, 0x2c, 0x22, 0x6e,
-		0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x67, 0x61,
-		0x73, 0x55, 0x73, 0x65, 0x64, 0x22, 0x3a, 0x22,
-		0x30, 0x78, 0x30, 0x22, 0x2c, 0x22, 0x70, 0x61,
-		0x72, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68,
-		0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x30, 0x30,
+		0x30, 0x30, 0x30, 0x22, 0x2c, 0x22, 0x61, 0x6c,
+		0x6c, 0x6f, 0x63, 0x22, 0x3a, 0x7b, 0x22, 0x37,
+		0x35, 0x31, 0x61, 0x30, 0x62, 0x39, 0x36, 0x65,
+		0x31, 0x30, 0x34, 0x32, 0x62, 0x65, 0x65, 0x37,
+		0x38, 0x39, 0x34, 0x35, 0x32, 0x65, 0x63, 0x62,
+		0x32, 0x30, 0x32, 0x35, 0x33, 0x66, 0x62, 0x61,
+		0x34, 0x30, 0x64, 0x62, 0x65, 0x38, 0x35, 0x22,
+		0x3a, 0x7b, 0x22, 0x62, 0x61, 0x6c, 0x61, 0x6e,
+		0x63, 0x65, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x33,
+		0x33, 0x62, 0x32, 0x65, 0x33, 0x63, 0x39, 0x66,
+		0x64, 0x30, 0x38, 0x30, 0x34, 0x30, 0x30, 0x30,
+		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x22, 0x7d,
+		0x7d, 0x2c, 0x22, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+		0x72, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x22,
+		0x2c, 0x22, 0x67, 0x61, 0x73, 0x55, 0x73, 0x65,
+		0x64, 0x22, 0x3a, 0x22, 0x30, 0x78, 0x30, 0x22,
+		0x2c, 0x22, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+		0x48, 0x61, 0x73, 0x68, 0x22, 0x3a, 0x22, 0x30,
+		0x78, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
@@ -416,7 +490,7 @@ func Genesis(networkID uint32) []byte {
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
 		0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30,
-		0x30, 0x30, 0x30, 0x30, 0x30, 0x22, 0x7d, 0x00,
+		0x30, 0x22, 0x7d, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -426,6 +500,10 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x30, 0x39, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x13, 0x53, 0x69,
 		0x6d, 0x70, 0x6c, 0x65, 0x20, 0x44, 0x41, 0x47,
 		0x20, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
@@ -454,21 +532,26 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x30, 0x39, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15,
-		0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x20, 0x43,
-		0x68, 0x61, 0x69, 0x6e, 0x20, 0x50, 0x61, 0x79,
-		0x6d, 0x65, 0x6e, 0x74, 0x73, 0x73, 0x70, 0x63,
-		0x68, 0x61, 0x69, 0x6e, 0x76, 0x6d, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x30, 0x39, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x28, 0x00, 0x00, 0x00,
-		0x01, 0x3c, 0xb7, 0xd3, 0x84, 0x2e, 0x8c, 0xee,
-		0x6a, 0x0e, 0xbd, 0x09, 0xf1, 0xfe, 0x88, 0x4f,
-		0x68, 0x61, 0xe1, 0xb2, 0x9c, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x12,
-		0x30, 0x9c, 0xe5, 0x40, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x15, 0x53, 0x69, 0x6d, 0x70,
+		0x6c, 0x65, 0x20, 0x43, 0x68, 0x61, 0x69, 0x6e,
+		0x20, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+		0x73, 0x73, 0x70, 0x63, 0x68, 0x61, 0x69, 0x6e,
+		0x76, 0x6d, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x28, 0x00, 0x00, 0x00, 0x01, 0x3c, 0xb7, 0xd3,
+		0x84, 0x2e, 0x8c, 0xee, 0x6a, 0x0e, 0xbd, 0x09,
+		0xf1, 0xfe, 0x88, 0x4f, 0x68, 0x61, 0xe1, 0xb2,
+		0x9c, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x12, 0x30, 0x9c, 0xe5, 0x40,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
@@ -478,6 +561,10 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x30, 0x39, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x17, 0x53, 0x69, 0x6d, 0x70,
 		0x6c, 0x65, 0x20, 0x54, 0x69, 0x6d, 0x65, 0x73,
 		0x74, 0x61, 0x6d, 0x70, 0x20, 0x53, 0x65, 0x72,
@@ -495,19 +582,75 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x5d, 0xbb, 0x75, 0x80,
+		0x00, 0x00, 0x00, 0x00, 0x5d, 0xbb, 0x75, 0x80,
 	}
 }
 
-// VMGenesis ...
-func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
+// Chains returns the transactions that create the chains in the genesis of the
+// Platform Chain
+func Chains(networkID uint32) []*platformvm.CreateChainTx {
 	genesisBytes := Genesis(networkID)
 	genesis := platformvm.Genesis{}
 	platformvm.Codec.Unmarshal(genesisBytes, &genesis)
-	for _, chain := range genesis.Chains {
+	genesis.Initialize()
+	return genesis.Chains
+}
+
+// VMGenesis ...
+func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
+	for _, chain := range Chains(networkID) {
 		if chain.VMID.Equals(vmID) {
 			return chain
 		}
 	}
 	return nil
 }
+
+// AVAAssetID returns the ID of the AVA asset, which is the first asset created
+// in the genesis of the X-Chain
+func AVAAssetID(networkID uint32) (ids.ID, error) {
+	createAVM := VMGenesis(networkID, avm.ID)
+	if createAVM == nil {
+		return ids.ID{}, errors.New("couldn't find the X-Chain in the genesis")
+	}
+
+	c := codec.NewDefault()
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&avm.BaseTx{}),
+		c.RegisterType(&avm.CreateAssetTx{}),
+		c.RegisterType(&avm.OperationTx{}),
+
+		// The X-Chain runs the nftfx and the secp256k1fx. The fxs register
+		// their types in the order of the chain's sorted fx IDs.
+		c.RegisterType(&nftfx.MintOutput{}),
+		c.RegisterType(&nftfx.TransferOutput{}),
+		c.RegisterType(&nftfx.MintInput{}),
+		c.RegisterType(&nftfx.TransferInput{}),
+		c.RegisterType(&nftfx.Credential{}),
+		c.RegisterType(&secp256k1fx.MintOutput{}),
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		c.RegisterType(&secp256k1fx.MintInput{}),
+		c.RegisterType(&secp256k1fx.TransferInput{}),
+		c.RegisterType(&secp256k1fx.Credential{}),
+	)
+	if errs.Errored() {
+		return ids.ID{}, errs.Err
+	}
+
+	genesis := avm.Genesis{}
+	if err := c.Unmarshal(createAVM.GenesisData, &genesis); err != nil {
+		return ids.ID{}, err
+	}
+	if len(genesis.Txs) == 0 {
+		return ids.ID{}, errors.New("the X-Chain genesis doesn't create any assets")
+	}
+
+	tx := avm.Tx{UnsignedTx: &genesis.Txs[0].CreateAssetTx}
+	txBytes, err := c.Marshal(&tx)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	tx.Initialize(txBytes)
+	return tx.ID(), nil
+}
@@ -105,6 +105,27 @@ func TestAliases(t *testing.T) {
 	}
 }
 
+func TestGetFees(t *testing.T) {
+	if fees := GetFees(MainnetID); fees.TxFee == 0 || fees.CreationTxFee < fees.TxFee {
+		t.Fatalf("Mainnet should charge a tx fee, and at least as much to create, but charges %+v", fees)
+	}
+	if fees := GetFees(LocalID); fees.TxFee != 0 || fees.CreationTxFee != 0 {
+		t.Fatalf("Local networks shouldn't charge fees but charge %+v", fees)
+	}
+}
+
+func TestGetStakingParameters(t *testing.T) {
+	if err := GetStakingParameters(MainnetID).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := GetStakingParameters(LocalID).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if mainnet, local := GetStakingParameters(MainnetID), GetStakingParameters(LocalID); local.MinStakeDuration >= mainnet.MinStakeDuration {
+		t.Fatalf("Local networks should allow shorter stakes than mainnet but allow %s", local.MinStakeDuration)
+	}
+}
+
 func TestGenesis(t *testing.T) {
 	genesisBytes := Genesis(LocalID)
 	genesis := platformvm.Genesis{}
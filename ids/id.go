@@ -6,6 +6,7 @@ package ids
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
@@ -14,6 +15,8 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
+var errMissingQuotes = errors.New("missing quotes")
+
 // Empty is a useful all zero value
 var Empty = ID{ID: &[32]byte{}}
 
@@ -33,14 +36,14 @@ func ToID(bytes []byte) (ID, error) {
 	return NewID(addrHash), err
 }
 
-// FromString is the inverse of ID.String()
+// FromString is the inverse of ID.String(). It also accepts the ID in hex,
+// prefixed with 0x.
 func FromString(idStr string) (ID, error) {
-	cb58 := formatting.CB58{}
-	err := cb58.FromString(idStr)
+	b, err := formatting.Decode(idStr)
 	if err != nil {
 		return ID{}, err
 	}
-	return ToID(cb58.Bytes)
+	return ToID(b)
 }
 
 // MarshalJSON ...
@@ -57,11 +60,11 @@ func (id *ID) UnmarshalJSON(b []byte) error {
 	if string(b) == "null" {
 		return nil
 	}
-	cb58 := formatting.CB58{}
-	if err := cb58.UnmarshalJSON(b); err != nil {
-		return err
+	str := string(b)
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return errMissingQuotes
 	}
-	newID, err := ToID(cb58.Bytes)
+	newID, err := FromString(str[1 : len(str)-1])
 	if err != nil {
 		return err
 	}
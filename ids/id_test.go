@@ -79,3 +79,26 @@ func TestFromString(t *testing.T) {
 		t.Fatal("Expected FromString to be inverse of String but it wasn't")
 	}
 }
+
+func TestFromHexString(t *testing.T) {
+	key := [32]byte{'a', 'v', 'a', ' ', 'l', 'a', 'b', 's'}
+	id := NewID(key)
+	id2, err := FromString("0x" + id.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Key() != id2.Key() {
+		t.Fatal("Expected FromString to parse the ID in hex")
+	}
+
+	id3 := ID{}
+	if err := id3.UnmarshalJSON([]byte("\"0x" + id.Hex() + "\"")); err != nil {
+		t.Fatal(err)
+	}
+	if id.Key() != id3.Key() {
+		t.Fatal("Expected UnmarshalJSON to parse the ID in hex")
+	}
+	if err := id3.UnmarshalJSON([]byte(id.String())); err == nil {
+		t.Fatal("Should have errored due to missing quotes")
+	}
+}
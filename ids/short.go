@@ -30,14 +30,14 @@ func ToShortID(bytes []byte) (ShortID, error) {
 	return NewShortID(addrHash), err
 }
 
-// ShortFromString is the inverse of ShortID.String()
+// ShortFromString is the inverse of ShortID.String(). It also accepts the ID in hex,
+// prefixed with 0x.
 func ShortFromString(idStr string) (ShortID, error) {
-	cb58 := formatting.CB58{}
-	err := cb58.FromString(idStr)
+	b, err := formatting.Decode(idStr)
 	if err != nil {
 		return ShortID{}, err
 	}
-	return ToShortID(cb58.Bytes)
+	return ToShortID(b)
 }
 
 // MarshalJSON ...
@@ -54,11 +54,11 @@ func (id *ShortID) UnmarshalJSON(b []byte) error {
 	if string(b) == "null" {
 		return nil
 	}
-	cb58 := formatting.CB58{}
-	if err := cb58.UnmarshalJSON(b); err != nil {
-		return err
+	str := string(b)
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return errMissingQuotes
 	}
-	newID, err := ToShortID(cb58.Bytes)
+	newID, err := ShortFromString(str[1 : len(str)-1])
 	if err != nil {
 		return err
 	}
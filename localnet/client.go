@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package localnet
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Client calls the APIs of a node
+type Client struct {
+	uri  string
+	http http.Client
+}
+
+// NewClient returns a client of the node whose HTTP server is at [uri], such
+// as http://127.0.0.1:9650
+func NewClient(uri string, timeout time.Duration) *Client {
+	return &Client{
+		uri:  uri,
+		http: http.Client{Timeout: timeout},
+	}
+}
+
+// URI of the node's HTTP server
+func (c *Client) URI() string { return c.uri }
+
+// Call the JSON-RPC [method] served at /ext/[endpoint] with [args], and parse
+// its result into [reply]
+func (c *Client) Call(endpoint, method string, args, reply interface{}) error {
+	body, err := json2.EncodeClientRequest(method, args)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Post(fmt.Sprintf("%s/ext/%s", c.uri, endpoint), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", method, resp.StatusCode)
+	}
+	return json2.DecodeClientResponse(resp.Body, reply)
+}
+
+// NodeID returns the ID of the node
+func (c *Client) NodeID() (ids.ShortID, error) {
+	reply := struct {
+		NodeID ids.ShortID `json:"nodeID"`
+	}{}
+	err := c.Call("info", "info.getNodeID", struct{}{}, &reply)
+	return reply.NodeID, err
+}
+
+// IsBootstrapped returns true if the node finished bootstrapping [chain],
+// given by its alias or ID
+func (c *Client) IsBootstrapped(chain string) (bool, error) {
+	args := struct {
+		Chain string `json:"chain"`
+	}{Chain: chain}
+	reply := struct {
+		IsBootstrapped bool `json:"isBootstrapped"`
+	}{}
+	err := c.Call("info", "info.isBootstrapped", &args, &reply)
+	return reply.IsBootstrapped, err
+}
+
+// Peers returns the IPs of the node's peers
+func (c *Client) Peers() ([]string, error) {
+	reply := struct {
+		Peers []string `json:"peers"`
+	}{}
+	err := c.Call("info", "info.peers", struct{}{}, &reply)
+	return reply.Peers, err
+}
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package localnet
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+type testInfo struct{ nodeID ids.ShortID }
+
+type TestChainArgs struct {
+	Chain string `json:"chain"`
+}
+
+type TestNodeIDReply struct {
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+type TestBootstrappedReply struct {
+	IsBootstrapped bool `json:"isBootstrapped"`
+}
+
+func (i *testInfo) GetNodeID(_ *http.Request, _ *struct{}, reply *TestNodeIDReply) error {
+	reply.NodeID = i.nodeID
+	return nil
+}
+
+func (i *testInfo) IsBootstrapped(_ *http.Request, args *TestChainArgs, reply *TestBootstrappedReply) error {
+	reply.IsBootstrapped = args.Chain == "X"
+	return nil
+}
+
+func TestClient(t *testing.T) {
+	nodeID := ids.NewShortID([20]byte{1})
+	server := rpc.NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	if err := server.RegisterService(&testInfo{nodeID: nodeID}, "info"); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/ext/info", server)
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	client := NewClient(httpServer.URL, time.Second)
+	if result, err := client.NodeID(); err != nil {
+		t.Fatal(err)
+	} else if !result.Equals(nodeID) {
+		t.Fatalf("Node ID should be %s but is %s", nodeID, result)
+	}
+
+	if bootstrapped, err := client.IsBootstrapped("X"); err != nil {
+		t.Fatal(err)
+	} else if !bootstrapped {
+		t.Fatalf("X should be bootstrapped")
+	}
+	if bootstrapped, err := client.IsBootstrapped("P"); err != nil {
+		t.Fatal(err)
+	} else if bootstrapped {
+		t.Fatalf("P shouldn't be bootstrapped")
+	}
+
+	if err := client.Call("info", "info.unknown", struct{}{}, &struct{}{}); err == nil {
+		t.Fatalf("Should have errored calling an unknown method")
+	}
+	if err := client.Call("unknown", "info.getNodeID", struct{}{}, &struct{}{}); err == nil {
+		t.Fatalf("Should have errored calling an unknown endpoint")
+	}
+}
@@ -0,0 +1,264 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package localnet runs a local network of nodes, so tests can call the APIs
+// of a network that's bootstrapped from the local genesis.
+//
+// The networking layer keeps its state in package variables shared with its C
+// callbacks, so only one node may run in a process. Each node of a local
+// network is run as a child process of the test instead.
+package localnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/staking"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// MaxNodes is the number of stakers in the local genesis. A node of the
+	// network stakes with the key of one of them.
+	MaxNodes = 5
+
+	// How long a node may take to shut down before it's killed
+	shutdownTimeout = 30 * time.Second
+
+	// How often nodes are polled while waiting on them
+	pollInterval = 250 * time.Millisecond
+
+	// How long an API call may take
+	callTimeout = 5 * time.Second
+)
+
+var (
+	errNoBinary     = errors.New("the node binary must be given")
+	errNumNodes     = fmt.Errorf("a local network must have between 1 and %d nodes", MaxNodes)
+	errBootstrapped = errors.New("timed out waiting for the nodes to bootstrap")
+)
+
+// Config of a local network
+type Config struct {
+	// Path of the node binary, such as build/ava
+	Binary string
+
+	// Number of nodes. Consensus on the local genesis's chains only makes
+	// progress once enough of its MaxNodes stakers are running.
+	NumNodes int
+
+	// Directory each node runs in, and writes its database, logs and output
+	// in, under a subdirectory named by the node's index
+	Dir string
+
+	// Directory of the local genesis stakers' keys. Node i stakes with the key
+	// in the keys[i+1] subdirectory.
+	KeysDir string
+
+	// Node i serves HTTP on BasePort+2i, and stakes on BasePort+2i+1
+	BasePort uint16
+
+	// Flags given to every node, by name. They take precedence over the flags
+	// the network sets.
+	Flags map[string]string
+}
+
+// Node of a local network
+type Node struct {
+	Index       int
+	ID          ids.ShortID
+	HTTPPort    uint16
+	StakingPort uint16
+	Client      *Client
+
+	dir, keyFile, certFile string
+
+	cmd    *exec.Cmd
+	output *os.File
+	exited chan error
+}
+
+// Network of nodes run as child processes
+type Network struct {
+	config Config
+	Nodes  []*Node
+}
+
+// New starts a local network. The first node is the bootstrap peer of the
+// others.
+func New(config Config) (*Network, error) {
+	switch {
+	case config.Binary == "":
+		return nil, errNoBinary
+	case config.NumNodes < 1 || config.NumNodes > MaxNodes:
+		return nil, errNumNodes
+	}
+
+	// Nodes run in their own directories, so paths mustn't be relative to
+	// this process's
+	errs := wrappers.Errs{}
+	for _, path := range []*string{&config.Binary, &config.Dir, &config.KeysDir} {
+		abs, err := filepath.Abs(*path)
+		errs.Add(err)
+		*path = abs
+	}
+	if errs.Errored() {
+		return nil, errs.Err
+	}
+
+	n := &Network{config: config}
+	for i := 0; i < config.NumNodes; i++ {
+		node, err := n.newNode(i)
+		if err != nil {
+			return nil, err
+		}
+		n.Nodes = append(n.Nodes, node)
+	}
+
+	for _, node := range n.Nodes {
+		if err := n.start(node); err != nil {
+			n.Stop()
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+func (n *Network) newNode(i int) (*Node, error) {
+	keysDir := filepath.Join(n.config.KeysDir, fmt.Sprintf("keys%d", i+1))
+	node := &Node{
+		Index:       i,
+		HTTPPort:    n.config.BasePort + uint16(2*i),
+		StakingPort: n.config.BasePort + uint16(2*i+1),
+		dir:         filepath.Join(n.config.Dir, fmt.Sprintf("node%d", i)),
+		keyFile:     filepath.Join(keysDir, "staker.key"),
+		certFile:    filepath.Join(keysDir, "staker.crt"),
+	}
+	nodeID, err := staking.NodeID(node.certFile)
+	if err != nil {
+		return nil, err
+	}
+	node.ID = nodeID
+	node.Client = NewClient(fmt.Sprintf("http://127.0.0.1:%d", node.HTTPPort), callTimeout)
+	return node, nil
+}
+
+// args returns the flags [node] is run with
+func (n *Network) args(node *Node) []string {
+	flags := map[string]string{
+		"network-id":            "local",
+		"public-ip":             "127.0.0.1",
+		"http-port":             fmt.Sprint(node.HTTPPort),
+		"staking-port":          fmt.Sprint(node.StakingPort),
+		"staking-tls-key-file":  node.keyFile,
+		"staking-tls-cert-file": node.certFile,
+		"db-dir":                filepath.Join(node.dir, "db"),
+		"log-dir":               filepath.Join(node.dir, "logs"),
+		"snow-sample-size":      fmt.Sprint(n.config.NumNodes),
+		"snow-quorum-size":      fmt.Sprint(n.config.NumNodes/2 + 1),
+	}
+	if node.Index != 0 {
+		bootstrapper := n.Nodes[0]
+		flags["bootstrap-ips"] = fmt.Sprintf("127.0.0.1:%d", bootstrapper.StakingPort)
+		flags["bootstrap-ids"] = bootstrapper.ID.String()
+	}
+	for name, value := range n.config.Flags {
+		flags[name] = value
+	}
+
+	args := make([]string, 0, len(flags))
+	for name, value := range flags {
+		args = append(args, fmt.Sprintf("--%s=%s", name, value))
+	}
+	return args
+}
+
+// start [node], writing its output to a file in its directory
+func (n *Network) start(node *Node) error {
+	if err := os.MkdirAll(node.dir, 0700); err != nil {
+		return err
+	}
+	output, err := os.Create(filepath.Join(node.dir, "output.log"))
+	if err != nil {
+		return err
+	}
+
+	node.cmd = exec.Command(n.config.Binary, n.args(node)...)
+	node.cmd.Dir = node.dir
+	node.cmd.Stdout = output
+	node.cmd.Stderr = output
+	if err := node.cmd.Start(); err != nil {
+		output.Close()
+		return fmt.Errorf("couldn't start node %d: %w", node.Index, err)
+	}
+	node.output = output
+	node.exited = make(chan error, 1)
+	go func() { node.exited <- node.cmd.Wait() }()
+	return nil
+}
+
+// WaitForBootstrap returns once every node finished bootstrapping [chains],
+// given by their aliases or IDs, or errors if they don't within [timeout] or a
+// node exits
+func (n *Network) WaitForBootstrap(timeout time.Duration, chains ...string) error {
+	deadline := time.Now().Add(timeout)
+	for _, node := range n.Nodes {
+		for _, chain := range chains {
+			for {
+				select {
+				case err := <-node.exited:
+					node.exited <- err
+					return fmt.Errorf("node %d exited with %v. Its output is in %s", node.Index, err, node.output.Name())
+				default:
+				}
+
+				// The node errors until its API server is up
+				if bootstrapped, err := node.Client.IsBootstrapped(chain); err == nil && bootstrapped {
+					break
+				}
+				if time.Now().After(deadline) {
+					return fmt.Errorf("%w: node %d hasn't bootstrapped %s", errBootstrapped, node.Index, chain)
+				}
+				time.Sleep(pollInterval)
+			}
+		}
+	}
+	return nil
+}
+
+// Stop every node. Nodes are interrupted, and killed if they don't shut down
+// in time.
+func (n *Network) Stop() error {
+	errs := wrappers.Errs{}
+	for _, node := range n.Nodes {
+		if node.cmd == nil || node.cmd.Process == nil {
+			continue
+		}
+		errs.Add(node.stop())
+	}
+	return errs.Err
+}
+
+func (node *Node) stop() error {
+	defer node.output.Close()
+
+	if err := node.cmd.Process.Signal(os.Interrupt); err != nil {
+		// The node already exited
+		return nil
+	}
+	select {
+	case err := <-node.exited:
+		node.exited <- err
+		if err != nil {
+			return fmt.Errorf("node %d didn't shut down cleanly: %w", node.Index, err)
+		}
+		return nil
+	case <-time.After(shutdownTimeout):
+		return node.cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package localnet
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestNetworkArgs(t *testing.T) {
+	n := &Network{config: Config{
+		NumNodes: 3,
+		Dir:      "/tmp/localnet",
+		KeysDir:  "../keys",
+		BasePort: 9650,
+		Flags:    map[string]string{"log-level": "debug", "snow-quorum-size": "3"},
+	}}
+	for i := 0; i < n.config.NumNodes; i++ {
+		node, err := n.newNode(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.Nodes = append(n.Nodes, node)
+	}
+
+	bootstrapper := n.Nodes[0]
+	if bootstrapper.HTTPPort != 9650 || bootstrapper.StakingPort != 9651 {
+		t.Fatalf("First node should use the base ports")
+	}
+	if expected := "7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"; bootstrapper.ID.String() != expected {
+		t.Fatalf("First node should stake as %s but stakes as %s", expected, bootstrapper.ID)
+	}
+
+	args := n.args(n.Nodes[2])
+	sort.Strings(args)
+	expected := map[string]bool{
+		"--http-port=9654":                                  true,
+		"--staking-port=9655":                               true,
+		"--bootstrap-ips=127.0.0.1:9651":                    true,
+		"--bootstrap-ids=7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg": true,
+		"--db-dir=/tmp/localnet/node2/db":                   true,
+		"--snow-sample-size=3":                              true,
+		// Given flags take precedence
+		"--snow-quorum-size=3": true,
+		"--log-level=debug":    true,
+	}
+	for _, arg := range args {
+		delete(expected, arg)
+	}
+	if len(expected) != 0 {
+		t.Fatalf("Node should have been given %v, but was given %v", expected, args)
+	}
+
+	for _, arg := range n.args(bootstrapper) {
+		if arg == "--bootstrap-ips=127.0.0.1:9651" {
+			t.Fatalf("First node shouldn't bootstrap from itself")
+		}
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	if _, err := New(Config{NumNodes: 1}); err != errNoBinary {
+		t.Fatalf("Should have errored with %v but got %v", errNoBinary, err)
+	}
+	if _, err := New(Config{Binary: "ava", NumNodes: MaxNodes + 1}); err != errNumNodes {
+		t.Fatalf("Should have errored with %v but got %v", errNumNodes, err)
+	}
+}
+
+// TestLocalNetwork runs a network of every local staker. It's skipped unless
+// GECKO_BINARY is the path of a built node.
+func TestLocalNetwork(t *testing.T) {
+	binary := os.Getenv("GECKO_BINARY")
+	if binary == "" {
+		t.Skip("GECKO_BINARY isn't set")
+	}
+
+	dir, err := ioutil.TempDir("", "localnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n, err := New(Config{
+		Binary:   binary,
+		NumNodes: MaxNodes,
+		Dir:      dir,
+		KeysDir:  "../keys",
+		BasePort: 19650,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := n.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if err := n.WaitForBootstrap(2*time.Minute, "P", "X"); err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range n.Nodes {
+		if nodeID, err := node.Client.NodeID(); err != nil {
+			t.Fatal(err)
+		} else if !nodeID.Equals(node.ID) {
+			t.Fatalf("Node %d should have ID %s but has %s", node.Index, node.ID, nodeID)
+		}
+	}
+}
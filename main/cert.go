@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/gecko/staking"
+)
+
+// certCommand is the subcommand that prints the node ID of a staking
+// certificate. It's run as "gecko cert [certificate file]".
+const certCommand = "cert"
+
+const (
+	// Where the staking key and certificate are generated, if the node isn't
+	// given them
+	defaultStakingKeyPath  = "staking/staker.key"
+	defaultStakingCertPath = "staking/staker.crt"
+)
+
+// isCertCommand returns true if the process was started to run the cert
+// subcommand rather than a node
+func isCertCommand() bool { return len(os.Args) > 1 && os.Args[1] == certCommand }
+
+// printNodeID prints the node ID of the certificate in the file given by
+// [args], or in the default staking certificate file if none is given. Returns
+// the exit code of the process.
+func printNodeID(args []string) int {
+	certPath := defaultStakingCertPath
+	switch len(args) {
+	case 0:
+	case 1:
+		certPath = args[0]
+	default:
+		fmt.Printf("usage: %s %s [certificate file]\n", os.Args[0], certCommand)
+		return exitFatalConfig
+	}
+
+	nodeID, err := staking.NodeID(certPath)
+	if err != nil {
+		fmt.Printf("couldn't get the node ID of %s: %s\n", certPath, err)
+		return exitFailed
+	}
+	fmt.Println(nodeID)
+	return exitOK
+}
+
+// fileExists returns true if there's a file at [path]
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
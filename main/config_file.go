@@ -0,0 +1,268 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A flag's value is taken from the first of these sources that sets it:
+//  1. The command line
+//  2. An environment variable named by the flag's name in upper case, with
+//     "-" replaced by "_" and prefixed by envPrefix. For example,
+//     GECKO_HTTP_PORT sets --http-port.
+//  3. The config file given by --config-file
+//  4. The flag's default
+const (
+	envPrefix = "GECKO_"
+
+	// Sources of flag values
+	sourceDefault = "default"
+	sourceFlag    = "command line"
+	sourceEnv     = "environment"
+	sourceFile    = "config file"
+)
+
+var (
+	errUnterminatedString = errors.New("unterminated string")
+	errUnterminatedArray  = errors.New("unterminated array")
+	errNestedArray        = errors.New("nested arrays aren't supported")
+	errNoValue            = errors.New("missing value")
+)
+
+// envName returns the environment variable that sets the flag [name]
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// applyEnv sets the flags of [fs] that aren't in [sources] from the
+// environment, and records their source
+func applyEnv(fs *flag.FlagSet, sources map[string]string) error {
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, set := sources[f.Name]; set || err != nil {
+			return
+		}
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok {
+			return
+		}
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q of %s: %w", value, envName(f.Name), setErr)
+			return
+		}
+		sources[f.Name] = sourceEnv
+	})
+	return err
+}
+
+// applyConfigFile sets the flags of [fs] that aren't in [sources] from the
+// config file at [path], and records their source. The file is JSON or TOML,
+// depending on its extension. Every key must name a flag.
+func applyConfigFile(fs *flag.FlagSet, path string, sources map[string]string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]string
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		values, err = parseJSON(data)
+	case ".toml":
+		values, err = parseTOML(data)
+	default:
+		return fmt.Errorf("config file %s must have a .json or .toml extension", path)
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't parse config file %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(values))
+	unknown := []string(nil)
+	for name := range values {
+		if fs.Lookup(name) == nil || name == "config-file" {
+			unknown = append(unknown, name)
+		}
+		names = append(names, name)
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("config file %s has unknown keys: %s", path, strings.Join(unknown, ", "))
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		if _, set := sources[name]; set {
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid value %q of %s in config file %s: %w", values[name], name, path, err)
+		}
+		sources[name] = sourceFile
+	}
+	return nil
+}
+
+// parseJSON parses a JSON object whose values are strings, booleans, numbers
+// or arrays of them into the values of the flags it sets. Arrays are joined
+// with commas.
+func parseJSON(data []byte) (map[string]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	object := map[string]interface{}{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(object))
+	for key, value := range object {
+		if array, ok := value.([]interface{}); ok {
+			elements := make([]string, len(array))
+			for i, element := range array {
+				str, err := jsonScalar(element)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", key, err)
+				}
+				elements[i] = str
+			}
+			values[key] = strings.Join(elements, ",")
+			continue
+		}
+		str, err := jsonScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = str
+	}
+	return values, nil
+}
+
+// jsonScalar returns the flag value of a decoded JSON string, boolean or number
+func jsonScalar(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case json.Number:
+		return value.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
+
+// parseTOML parses the flat subset of TOML that config files are written in
+// into the values of the flags it sets. Each line is blank, a comment, or a
+// key set to a string, boolean, number or single line array of them. Arrays
+// are joined with commas. Tables aren't supported.
+func parseTOML(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			return nil, fmt.Errorf("line %d: tables aren't supported", i+1)
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if strings.HasPrefix(key, "\"") {
+			unquoted, err := strconv.Unquote(key)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid key %s", i+1, key)
+			}
+			key = unquoted
+		}
+		value, rest, err := parseTOMLValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+			return nil, fmt.Errorf("line %d: unexpected %q after the value", i+1, rest)
+		}
+		if _, exists := values[key]; exists {
+			return nil, fmt.Errorf("line %d: %s is set more than once", i+1, key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// parseTOMLValue parses the TOML value at the start of [s], and returns the
+// rest of [s]
+func parseTOMLValue(s string) (string, string, error) {
+	switch {
+	case s == "":
+		return "", "", errNoValue
+	case s[0] == '"':
+		end := 1
+		for ; end < len(s) && s[end] != '"'; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) {
+			return "", "", errUnterminatedString
+		}
+		value, err := strconv.Unquote(s[:end+1])
+		return value, s[end+1:], err
+	case s[0] == '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", errUnterminatedString
+		}
+		return s[1 : end+1], s[end+2:], nil
+	case s[0] == '[':
+		elements := []string(nil)
+		s = strings.TrimSpace(s[1:])
+		for {
+			switch {
+			case s == "":
+				return "", "", errUnterminatedArray
+			case s[0] == ']':
+				return strings.Join(elements, ","), s[1:], nil
+			case s[0] == '[':
+				return "", "", errNestedArray
+			}
+			element, rest, err := parseTOMLValue(s)
+			if err != nil {
+				return "", "", err
+			}
+			elements = append(elements, element)
+			s = strings.TrimSpace(rest)
+			if strings.HasPrefix(s, ",") {
+				s = strings.TrimSpace(s[1:])
+			} else if !strings.HasPrefix(s, "]") {
+				return "", "", errUnterminatedArray
+			}
+		}
+	default:
+		end := strings.IndexAny(s, " \t,]#")
+		if end < 0 {
+			end = len(s)
+		}
+		value := s[:end]
+		if value != "true" && value != "false" {
+			value = strings.Replace(value, "_", "", -1)
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "", "", fmt.Errorf("invalid value %q", s[:end])
+			}
+		}
+		return value, s[end:], nil
+	}
+}
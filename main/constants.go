@@ -11,3 +11,19 @@ const (
 		"           \\______  /\\___  >\\___  >__|_ \\____/\n" +
 		"                  \\/     \\/     \\/     \\/"
 )
+
+// Exit codes of the node
+const (
+	exitOK = 0
+	// The node failed to start, or crashed
+	exitFailed = 1
+	// The node didn't shut down cleanly, such as when its chains didn't shut
+	// down within the shutdown timeout
+	exitShutdownFailed = 2
+	// The node was signaled to stop again while it was shutting down
+	exitForced = 3
+	// The node's configuration is invalid
+	exitFatalConfig = 4
+	// The node's database must be migrated before the node can use it
+	exitNeedsMigration = 5
+)
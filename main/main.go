@@ -4,10 +4,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"path"
+	"syscall"
 
 	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/staking"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/go-ethereum/p2p/nat"
@@ -16,12 +21,32 @@ import (
 // main is the primary entry point to Ava. This can either create a CLI to an
 //     existing node or create a new node.
 func main() {
+	if isCertCommand() {
+		os.Exit(printNodeID(os.Args[2:]))
+	}
+	if Err == nil && Supervise {
+		os.Exit(supervise())
+	}
+	os.Exit(run())
+}
+
+// run the node until it's signaled to stop, and return the exit code of the
+// process
+func run() (code int) {
 	// Err is set based on the CLI arguments
 	if Err != nil {
 		fmt.Printf("parsing parameters returned with error %s\n", Err)
-		return
+		return exitFatalConfig
 	}
 
+	// The node closes the database when it shuts down, once it's initialized
+	closeDB := true
+	defer func() {
+		if closeDB {
+			Config.DB.Close()
+		}
+	}()
+
 	config := Config.LoggingConfig
 	config.Directory = path.Join(config.Directory, "node")
 	factory := logging.NewFactory(config)
@@ -30,15 +55,18 @@ func main() {
 	log, err := factory.Make()
 	if err != nil {
 		fmt.Printf("starting logger failed with: %s\n", err)
-		return
+		return exitFailed
 	}
 	fmt.Println(gecko)
 
-	defer func() { recover() }()
+	defer func() {
+		if recover() != nil {
+			code = exitFailed
+		}
+	}()
 
 	defer log.Stop()
 	defer log.StopOnPanic()
-	defer Config.DB.Close()
 
 	// Track if sybil control is enforced
 	if !Config.EnableStaking {
@@ -53,7 +81,22 @@ func main() {
 
 	if err := Config.ConsensusParams.Valid(); err != nil {
 		log.Fatal("consensus parameters are invalid: %s", err)
-		return
+		return exitFatalConfig
+	}
+
+	// A node that wasn't given a staking key and certificate generates them
+	// the first time it runs
+	if GenerateStakingCert {
+		if err := staking.NewCertAndKey(Config.StakingKeyFile, Config.StakingCertFile); err != nil {
+			log.Fatal("couldn't generate the staking key and certificate: %s", err)
+			return exitFailed
+		}
+		nodeID, err := staking.NodeID(Config.StakingCertFile)
+		if err != nil {
+			log.Fatal("couldn't read the generated staking certificate: %s", err)
+			return exitFailed
+		}
+		log.Info("generated the staking key %s and certificate %s. This node's ID is %s", Config.StakingKeyFile, Config.StakingCertFile, nodeID)
 	}
 
 	// Track if assertions should be executed
@@ -61,6 +104,11 @@ func main() {
 		log.Warn("assertions are enabled. This may slow down execution")
 	}
 
+	// A node replaying a chain doesn't join the network
+	if Config.ReplayChain != "" {
+		return replayChain(log, factory)
+	}
+
 	natChan := make(chan struct{})
 	defer close(natChan)
 
@@ -86,17 +134,35 @@ func main() {
 	// MainNode is a global variable in the node.go file
 	if err := node.MainNode.Initialize(&Config, log, factory); err != nil {
 		log.Fatal("error initializing node state: %s", err)
-		return
+		if errors.Is(err, node.ErrDatabaseMigrationNeeded) {
+			return exitNeedsMigration
+		}
+		return exitFailed
 	}
+	closeDB = false
 
 	log.Debug("Starting servers")
 	if err := node.MainNode.StartConsensusServer(); err != nil {
 		log.Fatal("problem starting servers: %s", err)
-		return
+		code = exitFailed
+	} else {
+		log.Debug("Dispatching node handlers")
+		// Returns once the node is signaled to stop
+		node.MainNode.Dispatch()
 	}
 
-	defer node.MainNode.Shutdown()
-
-	log.Debug("Dispatching node handlers")
-	node.MainNode.Dispatch()
+	// Signaling the node again while it shuts down exits right away
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Warn("received %s while shutting down. Exiting without waiting for the node to shut down", sig)
+		os.Exit(exitForced)
+	}()
+
+	if err := node.MainNode.Shutdown(); err != nil {
+		log.Error("node didn't shut down cleanly: %s", err)
+		return exitShutdownFailed
+	}
+	return code
 }
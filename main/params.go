@@ -4,20 +4,28 @@
 package main
 
 import (
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/encdb"
 	"github.com/ava-labs/gecko/database/leveldb"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/rocksdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -30,26 +38,101 @@ import (
 var (
 	Config = node.Config{}
 	Err    error
+	// True if this process supervises the node, rather than running it. Only
+	// Err is set then.
+	Supervise bool
+	// True if the node wasn't given a staking key and certificate, and they
+	// don't exist at the default paths, so they're generated before the node
+	// starts
+	GenerateStakingCert bool
+)
+
+const (
+	// Value reported in place of a secret flag's value
+	redacted = "<redacted>"
+
+	// Values of the db-type flag
+	leveldbType = "leveldb"
+	rocksdbType = "rocksdb"
+
+	// Values of the log-format flag
+	textLogFormat = "text"
+	jsonLogFormat = "json"
 )
 
 var (
-	errBootstrapMismatch = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	// Flags whose values aren't reported by the node
+	secretFlags = map[string]bool{
+		"api-auth-password": true,
+	}
+
+	errBootstrapMismatch  = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errNoAuthPassword     = errors.New("api-auth-password must be set when api-auth-required is true")
+	errRestoreUnsupported = errors.New("db-restore-dir is only supported with the leveldb db-type")
+	errDBKeyMismatch      = errors.New("only one of db-encryption-key-file and db-encryption-passphrase-file may be set")
+	errNoStateRetention   = errors.New("state-pruning-retention must be at least one second when state-pruning is true")
+	errReplaySource       = errors.New("replay-chain and replay-source must be set together")
+	errUpgradeFraction    = errors.New("upgrade-warning-stake-fraction must be in (0, 1]")
+	errPartialStakingPair = fmt.Errorf("only one of %s and %s exists. Either both or neither must exist", defaultStakingKeyPath, defaultStakingCertPath)
 )
 
+// dbKeySource returns the source of the key the database is encrypted with,
+// read from [keyFile] or derived from the passphrase in [passphraseFile]
+func dbKeySource(keyFile, passphraseFile string) (encdb.KeySource, error) {
+	if keyFile != "" && passphraseFile != "" {
+		return nil, errDBKeyMismatch
+	}
+	if passphraseFile != "" {
+		passphrase, err := ioutil.ReadFile(passphraseFile)
+		if err != nil {
+			return nil, err
+		}
+		return encdb.PassphraseKey([]byte(strings.TrimSpace(string(passphrase)))), nil
+	}
+	keyHex, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(keyHex)))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the database encryption key: %w", err)
+	}
+	return encdb.StaticKey(key), nil
+}
+
 // Parse the CLI arguments
 func init() {
 	errs := &wrappers.Errs{}
 	defer func() { Err = errs.Err }()
 
+	// The cert subcommand doesn't run a node, so there's nothing to configure
+	if isCertCommand() {
+		return
+	}
+
 	loggingConfig, err := logging.DefaultConfig()
 	errs.Add(err)
 
+	// Supervision:
+	flag.BoolVar(&Supervise, "supervise", false, "If true, the node is run as a child process, and restarted with backoff if it exits abnormally")
+
+	// Config file:
+	configFile := flag.String("config-file", "", "JSON or TOML file, by its extension, of flag values. Flags given on the command line take precedence over environment variables, such as GECKO_HTTP_PORT for http-port, which take precedence over the config file")
+
 	// NetworkID:
 	networkName := flag.String("network-id", genesis.LocalName, "Network ID this node will connect to")
 
 	// Ava fees:
 	flag.Uint64Var(&Config.AvaTxFee, "ava-tx-fee", 0, "Ava transaction fee, in $nAva")
 
+	// Mempool:
+	flag.IntVar(&Config.AVMMempoolSize, "avm-mempool-size", 4096, "Maximum number of transactions the AVM holds while they wait to be issued")
+
+	// EVM:
+	flag.Uint64Var(&Config.EVMChainID, "evm-chain-id", 0, "Chain ID of the EVM chain. If 0, the chain ID in the chain's genesis is used")
+	flag.Uint64Var(&Config.EVMGasLimit, "evm-gas-limit", 0, "Gas limit of the EVM chain's blocks. If 0, the gas limit in the chain's genesis is used")
+	flag.Uint64Var(&Config.EVMMinGasPrice, "evm-min-gas-price", 0, "Minimum gas price, in wei, of EVM transactions this node accepts. If 0, the default is used")
+
 	// Assertions:
 	flag.BoolVar(&loggingConfig.Assertions, "assertions-enabled", true, "Turn on assertion execution")
 
@@ -59,6 +142,34 @@ func init() {
 	// Database:
 	db := flag.Bool("db-enabled", true, "Turn on persistent storage")
 	dbDir := flag.String("db-dir", "db", "Database directory for Ava state")
+	dbType := flag.String("db-type", leveldbType, fmt.Sprintf("Database backend to use. Either %s or %s, which requires building with -tags rocksdb", leveldbType, rocksdbType))
+	levelDBConfig := leveldb.DefaultConfig()
+	flag.IntVar(&levelDBConfig.BlockCacheSize, "leveldb-block-cache-size", levelDBConfig.BlockCacheSize, "Number of bytes LevelDB caches blocks in")
+	flag.IntVar(&levelDBConfig.WriteBufferSize, "leveldb-write-buffer-size", levelDBConfig.WriteBufferSize, "Number of bytes LevelDB buffers writes in before writing them to disk")
+	flag.IntVar(&levelDBConfig.HandleCap, "leveldb-max-open-files", levelDBConfig.HandleCap, "Maximum number of files LevelDB keeps open")
+	flag.IntVar(&levelDBConfig.BloomFilterBits, "leveldb-bloom-filter-bits", levelDBConfig.BloomFilterBits, "Bits per key of LevelDB's bloom filters. If 0, bloom filters aren't used")
+	flag.BoolVar(&levelDBConfig.Compression, "leveldb-compression-enabled", levelDBConfig.Compression, "If true, LevelDB compresses its tables with snappy")
+	rocksDBConfig := rocksdb.DefaultConfig()
+	flag.IntVar(&rocksDBConfig.BlockCacheSize, "rocksdb-block-cache-size", rocksDBConfig.BlockCacheSize, "Number of bytes RocksDB caches blocks in")
+	flag.IntVar(&rocksDBConfig.WriteBufferSize, "rocksdb-write-buffer-size", rocksDBConfig.WriteBufferSize, "Number of bytes RocksDB buffers writes in before writing them to disk")
+	flag.IntVar(&rocksDBConfig.HandleCap, "rocksdb-max-open-files", rocksDBConfig.HandleCap, "Maximum number of files RocksDB keeps open")
+	flag.IntVar(&rocksDBConfig.BloomFilterBits, "rocksdb-bloom-filter-bits", rocksDBConfig.BloomFilterBits, "Bits per key of RocksDB's bloom filters. If 0, bloom filters aren't used")
+	flag.BoolVar(&rocksDBConfig.Compression, "rocksdb-compression-enabled", rocksDBConfig.Compression, "If true, RocksDB compresses its tables with LZ4")
+	flag.IntVar(&rocksDBConfig.Parallelism, "rocksdb-parallelism", rocksDBConfig.Parallelism, "Number of background threads RocksDB flushes and compacts tables with. Defaults to the number of CPUs")
+	dbRestoreDir := flag.String("db-restore-dir", "", "If set, the backup in this directory is restored before the node starts. The database must not exist")
+	dbKeyFile := flag.String("db-encryption-key-file", "", "If set, the database is encrypted with the hex encoded 32 byte key in this file, such as one written by a key management service")
+	dbPassphraseFile := flag.String("db-encryption-passphrase-file", "", "If set, the database is encrypted with a key derived from the passphrase in this file")
+	statePruning := flag.Bool("state-pruning", false, "If true, rejected transactions and blocks are deleted once the retention window has passed. Other state, such as old state roots and the indexes of spent UTXOs, is always kept. By default, nothing is deleted")
+	stateRetention := flag.Duration("state-pruning-retention", 24*time.Hour, "How long rejected transactions and blocks are kept when state-pruning is true")
+	flag.BoolVar(&Config.StateSyncEnabled, "state-sync-enabled", false, "If true, chains whose VMs support it sync to a recent state attested to by more than half of the beacons' stake, rather than executing their history, before bootstrapping")
+
+	// Plugins:
+	flag.StringVar(&Config.PluginDir, "plugin-dir", "plugins", "Directory of VM plugins. Each plugin is named by the ID of the VM it runs")
+
+	// Chain configurations:
+	chainConfigDir := flag.String("chain-config-dir", "chain-configs", "Directory of chain configurations. Each subdirectory is named by a chain's ID or alias, and may hold the chain's config.json, upgrade.json and settings.json")
+	chainSettings := flag.String("chain-settings", "", "Comma separated key=value settings given to every chain. A chain's settings.json overrides them")
+	chainFeatures := flag.String("chain-features", "", "Comma separated feature flags enabled on every chain. A chain's settings.json may disable them")
 
 	// IP:
 	consensusIP := flag.String("public-ip", "", "Public IP of this node")
@@ -68,26 +179,58 @@ func init() {
 	flag.BoolVar(&Config.EnableHTTPS, "http-tls-enabled", false, "Upgrade the HTTP server to HTTPs")
 	flag.StringVar(&Config.HTTPSKeyFile, "http-tls-key-file", "", "TLS private key file for the HTTPs server")
 	flag.StringVar(&Config.HTTPSCertFile, "http-tls-cert-file", "", "TLS certificate file for the HTTPs server")
+	flag.StringVar(&Config.HTTPSClientCAFile, "http-tls-client-ca-file", "", "If set, HTTPs clients must present a certificate signed by a CA in this PEM file")
+	flag.Int64Var(&Config.APIMaxRequestSize, "api-max-request-size", 1<<20, "Maximum size, in bytes, of an API request, or of a request in a JSON-RPC batch. If 0, the size isn't limited")
+	flag.IntVar(&Config.APIMaxBatchSize, "api-max-batch-size", 100, "Maximum number of requests in a JSON-RPC batch. If 0, the number isn't limited")
+	flag.Float64Var(&Config.APIRateLimits.PerIP, "api-rate-limit-per-ip", 0, "Requests per second served from each IP. If 0, the rate isn't limited")
+	flag.IntVar(&Config.APIRateLimits.PerIPBurst, "api-rate-limit-per-ip-burst", 1, "Number of requests an IP may make at once when its rate is limited")
+	flag.Float64Var(&Config.APIRateLimits.Global, "api-rate-limit", 0, "Requests per second served from every IP together. If 0, the rate isn't limited")
+	flag.IntVar(&Config.APIRateLimits.GlobalBurst, "api-rate-limit-burst", 1, "Number of requests that may be made at once when the rate is limited")
+	flag.IntVar(&Config.APIRateLimits.MaxConcurrent, "api-max-concurrent-requests", 0, "Maximum number of API requests served at once. If 0, the number isn't limited")
+	httpAllowedOrigins := flag.String("http-allowed-origins", "*", "Comma separated list of origins browsers may call the HTTP server from. \"*\" allows every origin")
 
 	// Bootstrapping:
 	bootstrapIPs := flag.String("bootstrap-ips", "", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
 	bootstrapIDs := flag.String("bootstrap-ids", "", "Comma separated list of bootstrap peer ids to connect to. Example: JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	bootstrapDNSSeeds := flag.String("bootstrap-dns-seeds", "", "Comma separated list of DNS names whose TXT records, formatted as ID@IP:port, or _gecko._tcp SRV records list bootstrap peers. Example: seeds.example.com")
+	flag.DurationVar(&Config.BootstrapDNSRefresh, "bootstrap-dns-refresh", 30*time.Minute, "How often the DNS seeds are resolved again to find new bootstrap peers. If 0, they're only resolved when the node starts")
+
+	// Subnets:
+	whitelistedSubnets := flag.String("whitelisted-subnets", "", "Comma separated list of non-default subnets this node validates. Example: 2Gz4Tu4nDhKTYtSGAX2KLa4bzuB6hJVqjmkcCo7J9ZbDAYs4F")
 
 	// Staking:
 	consensusPort := flag.Uint("staking-port", 9651, "Port of the consensus server")
 	flag.BoolVar(&Config.EnableStaking, "staking-tls-enabled", true, "Require TLS to authenticate staking connections")
-	flag.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", "", "TLS private key file for staking connections")
-	flag.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", "", "TLS certificate file for staking connections")
+	flag.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", "", fmt.Sprintf("TLS private key file for staking connections. If neither it nor the certificate file is set, %s is used, and generated if it doesn't exist", defaultStakingKeyPath))
+	flag.Float64Var(&Config.UpgradeWarningFraction, "upgrade-warning-stake-fraction", 0.5, "A warning is logged when at least this fraction of the default subnet's stake runs a newer major version than this node")
+	flag.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", "", fmt.Sprintf("TLS certificate file for staking connections. If neither it nor the key file is set, %s is used, and generated if it doesn't exist", defaultStakingCertPath))
 
 	// Logging:
 	logsDir := flag.String("log-dir", "", "Logging directory for Ava")
 	logLevel := flag.String("log-level", "info", "The log level. Should be one of {verbo, debug, info, warn, error, fatal, off}")
 	logDisplayLevel := flag.String("log-display-level", "", "The log display level. If left blank, will inherit the value of log-level. Otherwise, should be one of {verbo, debug, info, warn, error, fatal, off}")
+	logFormat := flag.String("log-format", textLogFormat, fmt.Sprintf("Format of logged messages. Either %s or %s, which writes one JSON object per line", textLogFormat, jsonLogFormat))
+	flag.DurationVar(&loggingConfig.RotationInterval, "log-rotation-interval", loggingConfig.RotationInterval, "How often each logger's log file is rotated")
+	flag.IntVar(&loggingConfig.FileSize, "log-max-file-size", loggingConfig.FileSize, "Number of bytes each logger's log file is rotated at")
+	flag.IntVar(&loggingConfig.RotationSize, "log-max-files", loggingConfig.RotationSize, "Number of rotated log files kept for each logger. If 0, the number isn't limited")
+	flag.DurationVar(&loggingConfig.MaxAge, "log-max-age", loggingConfig.MaxAge, "Rotated log files older than this are deleted. If 0, rotated log files aren't deleted by age")
+	flag.Int64Var(&loggingConfig.MaxTotalSize, "log-max-total-size", loggingConfig.MaxTotalSize, "Maximum number of bytes of each logger's log files. The oldest rotated files are deleted to stay under it. If 0, the size isn't limited")
+	flag.BoolVar(&loggingConfig.Compress, "log-compression-enabled", loggingConfig.Compress, "If true, rotated log files are compressed with gzip")
+	flag.BoolVar(&Config.TraceMessages, "trace-messages", false, "If true, the spans of the consensus messages each chain sends and handles are logged to the tracing log, under trace IDs that are the same on each node a request went through")
+	logLevels := flag.String("log-levels", "", "Comma separated list of log levels of individual loggers, overriding log-level. A chain's ID sets the level of each of the chain's loggers. Example: main=info,http=debug,2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM=verbo")
+
+	// Network simulation, for testing only:
+	flag.DurationVar(&Config.NetworkSimulation.Latency, "network-sim-latency", 0, "Testing only. Each consensus message is delayed by this long before it's sent")
+	flag.DurationVar(&Config.NetworkSimulation.Jitter, "network-sim-jitter", 0, "Testing only. Each consensus message is delayed by a random duration of up to this long, on top of network-sim-latency")
+	flag.Float64Var(&Config.NetworkSimulation.DropRate, "network-sim-drop-rate", 0, "Testing only. Fraction of the consensus messages that are dropped rather than sent")
+	flag.Float64Var(&Config.NetworkSimulation.ReorderRate, "network-sim-reorder-rate", 0, "Testing only. Fraction of the consensus messages that are held back, so that messages sent after them are sent first")
+	flag.Int64Var(&Config.NetworkSimulationSeed, "network-sim-seed", 0, "Seed of the random decisions of the network simulation, so a run can be repeated")
 
 	flag.IntVar(&Config.ConsensusParams.K, "snow-sample-size", 20, "Number of nodes to query for each network poll")
 	flag.IntVar(&Config.ConsensusParams.Alpha, "snow-quorum-size", 18, "Alpha value to use for required number positive results")
 	flag.IntVar(&Config.ConsensusParams.BetaVirtuous, "snow-virtuous-commit-threshold", 20, "Beta value to use for virtuous transactions")
 	flag.IntVar(&Config.ConsensusParams.BetaRogue, "snow-rogue-commit-threshold", 30, "Beta value to use for rogue transactions")
+	flag.IntVar(&Config.ConsensusParams.ConcurrentPolls, "snow-concurrent-polls", 4, "Maximum number of network polls a linear chain keeps pending when it repolls. The number is adjusted between 1 and this bound to how quickly polls are answered")
 	flag.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	flag.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
 
@@ -95,7 +238,36 @@ func init() {
 	flag.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", true, "If true, this node exposes the Admin API")
 	flag.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
 	flag.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
+	flag.BoolVar(&Config.EventsAPIEnabled, "api-events-enabled", true, "If true, this node streams accepted transactions and blocks over websockets")
+	flag.BoolVar(&Config.HealthAPIEnabled, "api-health-enabled", true, "If true, this node exposes the Health API")
+	flag.BoolVar(&Config.InfoAPIEnabled, "api-info-enabled", true, "If true, this node exposes the Info API")
+	flag.BoolVar(&Config.GRPCAPIEnabled, "api-grpc-enabled", false, "If true, this node serves the Info, Health, AVM and Platform APIs over gRPC, and as REST endpoints under /ext/rest")
+	grpcPort := flag.Uint("api-grpc-port", 9653, "Port of the gRPC server")
+	flag.BoolVar(&Config.IndexAPIEnabled, "api-index-enabled", false, "If true, this node indexes the containers accepted on each chain, and serves the indexes under /ext/index")
+
+	// Health:
+	flag.IntVar(&Config.HealthMinPeers, "health-min-peers", 1, "Minimum number of peers this node must be connected to in order to be healthy")
+	flag.DurationVar(&Config.HealthMaxLastAccepted, "health-max-last-accepted", 0, "If positive, this node is unhealthy if a chain hasn't accepted a container in this long")
 	flag.BoolVar(&Config.IPCEnabled, "api-ipcs-enabled", false, "If true, IPCs can be opened")
+	flag.StringVar(&Config.IPCPath, "api-ipcs-path", "/tmp", "Directory the IPC sockets are created in")
+
+	// API authorization:
+	flag.BoolVar(&Config.APIRequireAuth, "api-auth-required", false, "If true, calls to sensitive API methods require an authorization token")
+	flag.StringVar(&Config.APIAuthPassword, "api-auth-password", "", "Password required to create and revoke API authorization tokens")
+
+	// Network timeouts:
+	flag.DurationVar(&Config.NetworkTimeout.InitialTimeout, "network-initial-timeout", 2*time.Second, "Timeout of requests to other nodes when the node starts. The timeout then adapts to how quickly requests are answered")
+	flag.DurationVar(&Config.NetworkTimeout.MinimumTimeout, "network-minimum-timeout", 500*time.Millisecond, "Minimum timeout of requests to other nodes")
+	flag.DurationVar(&Config.NetworkTimeout.MaximumTimeout, "network-maximum-timeout", 10*time.Second, "Maximum timeout of requests to other nodes")
+	flag.Float64Var(&Config.NetworkTimeout.TimeoutMultiplier, "network-timeout-multiplier", 1.1, "The timeout of requests to other nodes is multiplied by this each time a request times out")
+	flag.DurationVar(&Config.NetworkTimeout.TimeoutReduction, "network-timeout-reduction", time.Millisecond, "The timeout of requests to other nodes is reduced by this each time a request is answered in time")
+
+	// Shutdown:
+	flag.DurationVar(&Config.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "How long the node waits for its APIs to finish serving calls and its chains to shut down before it exits with an error")
+
+	// Replay:
+	flag.StringVar(&Config.ReplayChain, "replay-chain", "", "If set, the node doesn't join the network. It replays the containers of replay-source through the VM of this chain, given by its ID or alias, without consensus, checks that it reaches the same last accepted container, and exits. The chain must be created in the genesis")
+	flag.StringVar(&Config.ReplaySource, "replay-source", "", "Containers replay-chain replays, in the order they were accepted. Either the URL of another node's index of the chain, such as http://127.0.0.1:9650/ext/index/X/tx, or an archive file of the containers that index returns, one JSON object per line")
 
 	// Throughput Server
 	throughputPort := flag.Uint("xput-server-port", 9652, "Port of the deprecated throughput test server")
@@ -103,6 +275,36 @@ func init() {
 
 	flag.Parse()
 
+	sources := map[string]string{}
+	flag.Visit(func(f *flag.Flag) { sources[f.Name] = sourceFlag })
+	errs.Add(applyEnv(flag.CommandLine, sources))
+	if *configFile != "" {
+		errs.Add(applyConfigFile(flag.CommandLine, *configFile, sources))
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if secretFlags[f.Name] && value != "" {
+			value = redacted
+		}
+		source, set := sources[f.Name]
+		if !set {
+			source = sourceDefault
+		}
+		Config.Flags = append(Config.Flags, node.Flag{
+			Name:    f.Name,
+			Value:   value,
+			Default: f.DefValue,
+			Set:     set,
+			Source:  source,
+		})
+	})
+
+	if Supervise {
+		// The supervised node parses the flags itself, so the supervisor
+		// mustn't open the database or otherwise set up the node
+		return
+	}
+
 	networkID, err := genesis.NetworkID(*networkName)
 	errs.Add(err)
 
@@ -114,15 +316,86 @@ func init() {
 
 	// DB:
 	if *db && err == nil {
-		// TODO: Add better params here
-		dbPath := path.Join(*dbDir, genesis.NetworkName(Config.NetworkID))
-		db, err := leveldb.New(dbPath, 0, 0, 0)
+		var (
+			dbPath string
+			db     database.Database
+		)
+		switch *dbType {
+		case leveldbType:
+			dbPath = path.Join(*dbDir, genesis.NetworkName(Config.NetworkID))
+			if *dbRestoreDir != "" {
+				err = leveldb.Restore(*dbRestoreDir, dbPath)
+			}
+			if err == nil {
+				var levelDB *leveldb.Database
+				levelDB, err = leveldb.NewWithConfig(dbPath, levelDBConfig)
+				db = levelDB
+				// The backup is taken beneath any encryption, so it stays
+				// encrypted
+				Config.DBBackupper = levelDB
+			}
+		case rocksdbType:
+			// RocksDB's files are kept apart from LevelDB's, so switching
+			// backends starts from an empty database rather than failing to
+			// read the other's files
+			dbPath = path.Join(*dbDir, rocksdbType, genesis.NetworkName(Config.NetworkID))
+			if *dbRestoreDir != "" {
+				err = errRestoreUnsupported
+				break
+			}
+			db, err = rocksdb.NewWithConfig(dbPath, rocksDBConfig)
+		default:
+			err = fmt.Errorf("unknown db-type %q. Must be %s or %s", *dbType, leveldbType, rocksdbType)
+		}
 		Config.DB = db
+		Config.DBDir = dbPath
 		errs.Add(err)
+
+		if err == nil && (*dbKeyFile != "" || *dbPassphraseFile != "") {
+			source, err := dbKeySource(*dbKeyFile, *dbPassphraseFile)
+			errs.Add(err)
+			if err == nil {
+				Config.DB, err = encdb.Open(source, db)
+				Config.DBEncrypted = true
+				errs.Add(err)
+			}
+		}
 	} else {
 		Config.DB = memdb.New()
 	}
 
+	Config.ChainConfigs, err = chains.LoadChainConfigs(*chainConfigDir)
+	errs.Add(err)
+
+	Config.ChainSettings = map[string]string{}
+	for _, setting := range strings.Split(*chainSettings, ",") {
+		if setting = strings.TrimSpace(setting); setting == "" {
+			continue
+		}
+		parts := strings.SplitN(setting, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			errs.Add(fmt.Errorf("chain-settings entry %q must be of the form key=value", setting))
+			continue
+		}
+		Config.ChainSettings[parts[0]] = parts[1]
+	}
+	for _, feature := range strings.Split(*chainFeatures, ",") {
+		if feature = strings.TrimSpace(feature); feature != "" {
+			Config.ChainSettings[snow.FeaturePrefix+feature] = "true"
+		}
+	}
+
+	if *statePruning {
+		if *stateRetention < time.Second {
+			errs.Add(errNoStateRetention)
+		}
+		Config.StateRetention = *stateRetention
+	}
+
+	if (Config.ReplayChain == "") != (Config.ReplaySource == "") {
+		errs.Add(errReplaySource)
+	}
+
 	Config.Nat = nat.Any()
 
 	var ip net.IP
@@ -142,6 +415,27 @@ func init() {
 		Port: uint16(*consensusPort),
 	}
 
+	if Config.UpgradeWarningFraction <= 0 || Config.UpgradeWarningFraction > 1 {
+		errs.Add(errUpgradeFraction)
+	}
+
+	if err := Config.NetworkSimulation.Verify(); err != nil {
+		errs.Add(fmt.Errorf("invalid network simulation: %w", err))
+	}
+
+	// Staking key and certificate:
+	if Config.EnableStaking && Config.StakingKeyFile == "" && Config.StakingCertFile == "" {
+		Config.StakingKeyFile = defaultStakingKeyPath
+		Config.StakingCertFile = defaultStakingCertPath
+		keyExists, certExists := fileExists(defaultStakingKeyPath), fileExists(defaultStakingCertPath)
+		switch {
+		case keyExists != certExists:
+			errs.Add(errPartialStakingPair)
+		case !keyExists:
+			GenerateStakingCert = true
+		}
+	}
+
 	// Bootstrapping:
 	for _, ip := range strings.Split(*bootstrapIPs, ",") {
 		if ip != "" {
@@ -178,8 +472,35 @@ func init() {
 		}
 	}
 
+	for _, seed := range strings.Split(*bootstrapDNSSeeds, ",") {
+		if seed != "" {
+			Config.BootstrapDNSSeeds = append(Config.BootstrapDNSSeeds, seed)
+		}
+	}
+
+	// Subnets:
+	for _, subnet := range strings.Split(*whitelistedSubnets, ",") {
+		if subnet != "" {
+			subnetID, err := ids.FromString(subnet)
+			errs.Add(err)
+			Config.WhitelistedSubnets.Add(subnetID)
+		}
+	}
+
 	// HTTP:
 	Config.HTTPPort = uint16(*httpPort)
+	Config.GRPCPort = uint16(*grpcPort)
+	for _, origin := range strings.Split(*httpAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			Config.HTTPAllowedOrigins = append(Config.HTTPAllowedOrigins, origin)
+		}
+	}
+	if Config.APIRequireAuth && Config.APIAuthPassword == "" {
+		errs.Add(errNoAuthPassword)
+	}
+	if err := Config.NetworkTimeout.Verify(); err != nil {
+		errs.Add(fmt.Errorf("invalid network timeouts: %w", err))
+	}
 
 	// Logging:
 	if *logsDir != "" {
@@ -196,6 +517,29 @@ func init() {
 	errs.Add(err)
 	loggingConfig.DisplayLevel = displayLevel
 
+	switch *logFormat {
+	case textLogFormat:
+	case jsonLogFormat:
+		loggingConfig.JSONFormat = true
+	default:
+		errs.Add(fmt.Errorf("unknown log-format %q. Must be %s or %s", *logFormat, textLogFormat, jsonLogFormat))
+	}
+
+	loggingConfig.LoggerLevels = map[string]logging.Level{}
+	for _, rule := range strings.Split(*logLevels, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		parts := strings.SplitN(rule, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			errs.Add(fmt.Errorf("log-levels entry %q must be of the form name=level", rule))
+			continue
+		}
+		level, err := logging.ToLevel(parts[1])
+		errs.Add(err)
+		loggingConfig.LoggerLevels[parts[0]] = level
+	}
+
 	Config.LoggingConfig = loggingConfig
 
 	// Throughput:
@@ -0,0 +1,32 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// replayChain replays the chain given by the replay-chain flag, and returns the
+// exit code of the process. The process exits with exitOK only if every
+// container was accepted as it was by the chain.
+func replayChain(log logging.Logger, factory logging.Factory) int {
+	result, err := node.MainNode.Replay(&Config, log, factory)
+	if err != nil {
+		log.Fatal("replaying chain %s failed after accepting %d containers: %s", Config.ReplayChain, result.Accepted, err)
+		if errors.Is(err, node.ErrDatabaseMigrationNeeded) {
+			return exitNeedsMigration
+		}
+		return exitFailed
+	}
+	log.Info("replayed chain %s. Accepted %d containers and skipped %d that were already accepted. The last accepted container is %s",
+		Config.ReplayChain,
+		result.Accepted,
+		result.Skipped,
+		result.LastAccepted,
+	)
+	return exitOK
+}
@@ -0,0 +1,112 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	// How long the supervisor waits before restarting the node the first time
+	// it exits abnormally. The wait doubles each time the node exits
+	// abnormally again, up to maxRestartDelay.
+	minRestartDelay = time.Second
+	maxRestartDelay = 5 * time.Minute
+
+	// If the node ran for this long before exiting, the wait before restarting
+	// it is reset to minRestartDelay
+	stableRunDuration = 10 * time.Minute
+)
+
+// supervise runs the node as a child process with the arguments this process
+// was started with, and restarts the node when it exits abnormally. Signals
+// this process receives are forwarded to the node. Returns the exit code of
+// the node once it isn't restarted.
+//
+// The node isn't restarted if it exits cleanly, if its configuration is
+// invalid, or if its database must be migrated, as restarting wouldn't help.
+func supervise() int {
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("couldn't find the node's executable: %s\n", err)
+		return exitFailed
+	}
+	// The flag given last takes precedence, so the node doesn't supervise
+	// itself, even if supervise is set in the environment or config file
+	args := append(os.Args[1:], "--supervise=false")
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	delay := minRestartDelay
+	for {
+		cmd := exec.Command(executable, args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		// The node is put in its own process group, so a signal sent to this
+		// process's group, such as by Ctrl+C, is only received once by the
+		// node, when it's forwarded
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		started := time.Now()
+		if err := cmd.Start(); err != nil {
+			fmt.Printf("couldn't start the node: %s\n", err)
+			return exitFailed
+		}
+		exited := make(chan struct{})
+		go func() {
+			_ = cmd.Wait()
+			close(exited)
+		}()
+
+		stopping := false
+	wait:
+		for {
+			select {
+			case sig := <-signals:
+				// A second signal makes the node exit without waiting for it
+				// to shut down
+				stopping = true
+				_ = cmd.Process.Signal(sig)
+			case <-exited:
+				break wait
+			}
+		}
+
+		code := cmd.ProcessState.ExitCode()
+		switch {
+		case stopping:
+			return code
+		case code == exitOK:
+			fmt.Printf("node exited\n")
+			return code
+		case code == exitFatalConfig:
+			fmt.Printf("node exited because its configuration is invalid. Not restarting it\n")
+			return code
+		case code == exitNeedsMigration:
+			fmt.Printf("node exited because its database must be migrated. Not restarting it\n")
+			return code
+		}
+
+		if time.Since(started) >= stableRunDuration {
+			delay = minRestartDelay
+		}
+		fmt.Printf("node exited with %s. Restarting it in %s\n", cmd.ProcessState, delay)
+		select {
+		case <-signals:
+			return code
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxRestartDelay {
+			delay = maxRestartDelay
+		}
+	}
+}
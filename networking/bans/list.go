@@ -0,0 +1,303 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bans records the peers this node refuses to connect to. A peer is
+// banned either by its node ID or by a CIDR range its IP is in. Bans are kept
+// in a database, so they last across restarts, and may expire.
+package bans
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Keys of the database are prefixed by what is banned
+const (
+	// A node: prefix, node ID -> expiry
+	idPrefix byte = iota
+	// An IP range: prefix, CIDR -> expiry
+	cidrPrefix
+)
+
+var (
+	errNotBanned        = errors.New("not banned")
+	errBadExpiry        = errors.New("expected the expiry of a ban to be 8 bytes")
+	errNegativeDuration = errors.New("a ban can't last a negative duration")
+)
+
+// Ban of a node ID or of a CIDR range. Exactly one of NodeID and CIDR is set.
+type Ban struct {
+	NodeID ids.ShortID
+	CIDR   *net.IPNet
+	// When the ban expires. The zero time if the ban doesn't expire.
+	Expiry time.Time
+}
+
+type cidrBan struct {
+	cidr   *net.IPNet
+	expiry time.Time
+}
+
+// List of the bans in effect, recorded in its own database
+type List struct {
+	db    database.Database
+	clock timer.Clock
+
+	lock sync.RWMutex
+	// node ID -> expiry
+	ids map[[20]byte]time.Time
+	// CIDR string -> ban
+	cidrs map[string]cidrBan
+}
+
+// New returns the list of bans recorded in [db]. Bans that expired while the
+// node wasn't running are deleted.
+func New(db database.Database) (*List, error) {
+	l := &List{
+		db:    db,
+		ids:   make(map[[20]byte]time.Time),
+		cidrs: make(map[string]cidrBan),
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// BanID bans the node [nodeID] for [duration], or until it's unbanned if
+// [duration] is 0. Banning a node again replaces its ban.
+func (l *List) BanID(nodeID ids.ShortID, duration time.Duration) error {
+	expiry, err := l.expiry(duration)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.db.Put(idKey(nodeID), expiryBytes(expiry)); err != nil {
+		return err
+	}
+	l.ids[nodeID.Key()] = expiry
+	return nil
+}
+
+// BanCIDR bans the IPs in the range [cidr], such as 10.0.0.0/8, for
+// [duration], or until it's unbanned if [duration] is 0. Banning a range again
+// replaces its ban.
+func (l *List) BanCIDR(cidr string, duration time.Duration) error {
+	expiry, err := l.expiry(duration)
+	if err != nil {
+		return err
+	}
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := l.db.Put(cidrKey(ipNet), expiryBytes(expiry)); err != nil {
+		return err
+	}
+	l.cidrs[ipNet.String()] = cidrBan{
+		cidr:   ipNet,
+		expiry: expiry,
+	}
+	return nil
+}
+
+// UnbanID lifts the ban of the node [nodeID]
+func (l *List) UnbanID(nodeID ids.ShortID) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.ids[nodeID.Key()]; !ok {
+		return errNotBanned
+	}
+	if err := l.db.Delete(idKey(nodeID)); err != nil {
+		return err
+	}
+	delete(l.ids, nodeID.Key())
+	return nil
+}
+
+// UnbanCIDR lifts the ban of the range [cidr]. The range must be the one that
+// was banned, not a range in or around it.
+func (l *List) UnbanCIDR(cidr string) error {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if _, ok := l.cidrs[ipNet.String()]; !ok {
+		return errNotBanned
+	}
+	if err := l.db.Delete(cidrKey(ipNet)); err != nil {
+		return err
+	}
+	delete(l.cidrs, ipNet.String())
+	return nil
+}
+
+// BannedID returns true if the node [nodeID] is banned
+func (l *List) BannedID(nodeID ids.ShortID) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	expiry, ok := l.ids[nodeID.Key()]
+	return ok && l.active(expiry)
+}
+
+// BannedIP returns true if [ip] is in a banned range
+func (l *List) BannedIP(ip net.IP) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	for _, ban := range l.cidrs {
+		if ban.cidr.Contains(ip) && l.active(ban.expiry) {
+			return true
+		}
+	}
+	return false
+}
+
+// Banned returns true if the node [nodeID], or its IP [ip], is banned
+func (l *List) Banned(nodeID ids.ShortID, ip net.IP) bool {
+	return l.BannedID(nodeID) || l.BannedIP(ip)
+}
+
+// Bans returns the bans in effect, node IDs first and then ranges, each sorted
+func (l *List) Bans() []Ban {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	idBans := []Ban(nil)
+	for key, expiry := range l.ids {
+		if l.active(expiry) {
+			idBans = append(idBans, Ban{
+				NodeID: ids.NewShortID(key),
+				Expiry: expiry,
+			})
+		}
+	}
+	sort.Slice(idBans, func(i, j int) bool { return idBans[i].NodeID.String() < idBans[j].NodeID.String() })
+
+	cidrBans := []Ban(nil)
+	for _, ban := range l.cidrs {
+		if l.active(ban.expiry) {
+			cidrBans = append(cidrBans, Ban{
+				CIDR:   ban.cidr,
+				Expiry: ban.expiry,
+			})
+		}
+	}
+	sort.Slice(cidrBans, func(i, j int) bool { return cidrBans[i].CIDR.String() < cidrBans[j].CIDR.String() })
+
+	return append(idBans, cidrBans...)
+}
+
+// load the bans recorded in the database, deleting the expired ones
+func (l *List) load() error {
+	it := l.db.NewIterator()
+	defer it.Release()
+
+	expired := [][]byte(nil)
+	for it.Next() {
+		key := it.Key()
+		if len(key) == 0 {
+			continue
+		}
+		if len(it.Value()) != wrappers.LongLen {
+			return errBadExpiry
+		}
+		expiry := parseExpiry(it.Value())
+		if !l.active(expiry) {
+			expired = append(expired, append([]byte(nil), key...))
+			continue
+		}
+
+		switch key[0] {
+		case idPrefix:
+			nodeID, err := ids.ToShortID(key[1:])
+			if err != nil {
+				return err
+			}
+			l.ids[nodeID.Key()] = expiry
+		case cidrPrefix:
+			_, ipNet, err := net.ParseCIDR(string(key[1:]))
+			if err != nil {
+				return err
+			}
+			l.cidrs[ipNet.String()] = cidrBan{
+				cidr:   ipNet,
+				expiry: expiry,
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	for _, key := range expired {
+		if err := l.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiry returns when a ban made now for [duration] expires
+func (l *List) expiry(duration time.Duration) (time.Time, error) {
+	switch {
+	case duration < 0:
+		return time.Time{}, errNegativeDuration
+	case duration == 0:
+		return time.Time{}, nil
+	default:
+		return l.clock.Time().Add(duration), nil
+	}
+}
+
+// active returns true if a ban that expires at [expiry] is in effect
+func (l *List) active(expiry time.Time) bool {
+	return expiry.IsZero() || l.clock.Time().Before(expiry)
+}
+
+func idKey(nodeID ids.ShortID) []byte {
+	return append([]byte{idPrefix}, nodeID.Bytes()...)
+}
+
+func cidrKey(cidr *net.IPNet) []byte {
+	return append([]byte{cidrPrefix}, cidr.String()...)
+}
+
+// expiryBytes encodes [expiry] as unix nanoseconds, where 0 never expires
+func expiryBytes(expiry time.Time) []byte {
+	b := make([]byte, wrappers.LongLen)
+	if !expiry.IsZero() {
+		binary.BigEndian.PutUint64(b, uint64(expiry.UnixNano()))
+	}
+	return b
+}
+
+func parseExpiry(b []byte) time.Time {
+	nanos := binary.BigEndian.Uint64(b)
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(nanos))
+}
@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bans
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestBans(t *testing.T) {
+	l, err := New(memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1000000, 0)
+	l.clock.Set(start)
+
+	nodeID := ids.NewShortID([20]byte{1})
+	if err := l.BanID(nodeID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.BanCIDR("10.1.2.3/16", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if !l.BannedID(nodeID) {
+		t.Fatalf("Node should have been banned")
+	}
+	if l.BannedID(ids.NewShortID([20]byte{2})) {
+		t.Fatalf("Node shouldn't have been banned")
+	}
+	if !l.Banned(ids.NewShortID([20]byte{2}), net.ParseIP("10.1.200.1")) {
+		t.Fatalf("IP in the banned range should have been banned")
+	}
+	if l.BannedIP(net.ParseIP("10.2.0.1")) {
+		t.Fatalf("IP outside the banned range shouldn't have been banned")
+	}
+	if bans := l.Bans(); len(bans) != 2 || !bans[0].NodeID.Equals(nodeID) || bans[1].CIDR.String() != "10.1.0.0/16" {
+		t.Fatalf("Wrong bans: %v", bans)
+	}
+
+	// The range expires, while the node stays banned
+	l.clock.Set(start.Add(time.Hour))
+	if l.BannedIP(net.ParseIP("10.1.200.1")) {
+		t.Fatalf("Ban of the range should have expired")
+	}
+	if !l.BannedID(nodeID) {
+		t.Fatalf("Ban of the node shouldn't expire")
+	}
+
+	if err := l.UnbanID(nodeID); err != nil {
+		t.Fatal(err)
+	}
+	if l.BannedID(nodeID) {
+		t.Fatalf("Node should have been unbanned")
+	}
+	if err := l.UnbanID(nodeID); err == nil {
+		t.Fatalf("Should have errored as the node isn't banned")
+	}
+	if err := l.BanID(nodeID, -time.Second); err == nil {
+		t.Fatalf("Should have errored due to the negative duration")
+	}
+	if err := l.BanCIDR("10.1.2.3", 0); err == nil {
+		t.Fatalf("Should have errored due to the malformed range")
+	}
+}
+
+// Test that bans are kept across restarts, except those that expired
+func TestBansPersist(t *testing.T) {
+	db := memdb.New()
+	l, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Banned long ago, so this ban has expired by the restart
+	l.clock.Set(time.Unix(1000, 0))
+	if err := l.BanCIDR("192.168.0.0/24", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	l.clock.Sync()
+
+	nodeID := ids.NewShortID([20]byte{1})
+	if err := l.BanID(nodeID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.BanCIDR("2001:db8::/32", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.BannedID(nodeID) {
+		t.Fatalf("Node should still have been banned after the restart")
+	}
+	if !l.BannedIP(net.ParseIP("2001:db8::1")) {
+		t.Fatalf("Range should still have been banned after the restart")
+	}
+	if l.BannedIP(net.ParseIP("192.168.0.1")) {
+		t.Fatalf("Expired ban shouldn't have been loaded")
+	}
+	if has, err := db.Has(cidrKey(&net.IPNet{IP: net.IP{192, 168, 0, 0}, Mask: net.CIDRMask(24, 32)})); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Expired ban should have been deleted")
+	}
+
+	if err := l.UnbanCIDR("2001:db8::/32"); err != nil {
+		t.Fatal(err)
+	}
+	l, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.BannedIP(net.ParseIP("2001:db8::1")) {
+		t.Fatalf("Unbanned range shouldn't have been loaded")
+	}
+}
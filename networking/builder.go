@@ -130,6 +130,52 @@ func (m Builder) Chits(chainID ids.ID, requestID uint32, containerIDs ids.Set) (
 	})
 }
 
+// AppGossip message. Gossip isn't a response to a request, so its request ID
+// is always 0.
+func (m Builder) AppGossip(chainID ids.ID, msg []byte) (Msg, error) {
+	return m.Pack(AppGossip, map[Field]interface{}{
+		ChainID:   chainID.Bytes(),
+		RequestID: uint32(0),
+		AppBytes:  msg,
+	})
+}
+
+// GetStateSummary message
+func (m Builder) GetStateSummary(chainID ids.ID, requestID uint32) (Msg, error) {
+	return m.Pack(GetStateSummary, map[Field]interface{}{
+		ChainID:   chainID.Bytes(),
+		RequestID: requestID,
+	})
+}
+
+// StateSummary message
+func (m Builder) StateSummary(chainID ids.ID, requestID uint32, summary []byte) (Msg, error) {
+	return m.Pack(StateSummary, map[Field]interface{}{
+		ChainID:    chainID.Bytes(),
+		RequestID:  requestID,
+		StateBytes: summary,
+	})
+}
+
+// GetStateChunk message
+func (m Builder) GetStateChunk(chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32) (Msg, error) {
+	return m.Pack(GetStateChunk, map[Field]interface{}{
+		ChainID:     chainID.Bytes(),
+		RequestID:   requestID,
+		ContainerID: summaryID.Bytes(),
+		ChunkIndex:  index,
+	})
+}
+
+// StateChunk message
+func (m Builder) StateChunk(chainID ids.ID, requestID uint32, chunk []byte) (Msg, error) {
+	return m.Pack(StateChunk, map[Field]interface{}{
+		ChainID:    chainID.Bytes(),
+		RequestID:  requestID,
+		StateBytes: chunk,
+	})
+}
+
 // Ping message
 func (m Builder) Ping() (Msg, error) { return m.Pack(Ping, nil) }
 
@@ -27,6 +27,9 @@ const (
 	TxID                        // Used for throughput tests
 	Tx                          // Used for throughput tests
 	Status                      // Used for throughput tests
+	AppBytes                    // Used for app-level gossip
+	StateBytes                  // Used for state sync
+	ChunkIndex                  // Used for state sync
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -58,6 +61,12 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackBytes
 	case Status:
 		return wrappers.TryPackInt
+	case AppBytes:
+		return wrappers.TryPackBytes
+	case StateBytes:
+		return wrappers.TryPackBytes
+	case ChunkIndex:
+		return wrappers.TryPackInt
 	default:
 		return nil
 	}
@@ -92,6 +101,12 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackBytes
 	case Status:
 		return wrappers.TryUnpackInt
+	case AppBytes:
+		return wrappers.TryUnpackBytes
+	case StateBytes:
+		return wrappers.TryUnpackBytes
+	case ChunkIndex:
+		return wrappers.TryUnpackInt
 	default:
 		return nil
 	}
@@ -123,6 +138,12 @@ func (f Field) String() string {
 		return "Tx"
 	case Status:
 		return "Status"
+	case AppBytes:
+		return "App Bytes"
+	case StateBytes:
+		return "State Bytes"
+	case ChunkIndex:
+		return "Chunk Index"
 	default:
 		return "Unknown Field"
 	}
@@ -154,6 +175,13 @@ const (
 	// Throughput test:
 	IssueTx
 	DecidedTx
+	// App-level gossip:
+	AppGossip
+	// State sync:
+	GetStateSummary
+	StateSummary
+	GetStateChunk
+	StateChunk
 )
 
 // Defines the messages that can be sent/received with this network
@@ -183,5 +211,12 @@ var (
 		// Throughput test:
 		IssueTx:   []Field{ChainID, Tx},
 		DecidedTx: []Field{TxID, Status},
+		// App-level gossip:
+		AppGossip: []Field{ChainID, RequestID, AppBytes},
+		// State sync:
+		GetStateSummary: []Field{ChainID, RequestID},
+		StateSummary:    []Field{ChainID, RequestID, StateBytes},
+		GetStateChunk:   []Field{ChainID, RequestID, ContainerID, ChunkIndex},
+		StateChunk:      []Field{ChainID, RequestID, StateBytes},
 	}
 )
@@ -28,13 +28,16 @@ import (
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking/bans"
 	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/uptime"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/random"
 	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/version"
 )
 
 /*
@@ -72,6 +75,9 @@ const (
 	GetVersionTimeout = 2 * time.Second
 )
 
+// currentVersion is CurrentVersion, parsed
+var currentVersion = mustParseVersion(CurrentVersion)
+
 // Manager is the struct that will be accessed on event calls
 var (
 	HandshakeNet = Handshake{}
@@ -94,6 +100,15 @@ type Handshake struct {
 	myID          ids.ShortID
 	net           salticidae.PeerNetwork
 	enableStaking bool // Should only be false for local tests
+	uptimes       *uptime.Manager
+	// Peers that aren't connected to
+	bans *bans.List
+
+	// Versions of the connected peers
+	versions peerVersions
+	// A warning is logged when at least this fraction of the stake runs a
+	// newer major version than this node
+	upgradeWarningFraction float64
 
 	clock       timer.Clock
 	pending     AddrCert // Connections that I haven't gotten version messages from
@@ -117,6 +132,9 @@ func (nm *Handshake) Initialize(
 	registerer prometheus.Registerer,
 	enableStaking bool,
 	networkID uint32,
+	uptimes *uptime.Manager,
+	upgradeWarningFraction float64,
+	bans *bans.List,
 ) {
 	log.AssertTrue(nm.net == nil, "Should only register network handlers once")
 	nm.log = log
@@ -126,6 +144,9 @@ func (nm *Handshake) Initialize(
 	nm.net = peerNet
 	nm.enableStaking = enableStaking
 	nm.networkID = networkID
+	nm.uptimes = uptimes
+	nm.upgradeWarningFraction = upgradeWarningFraction
+	nm.bans = bans
 
 	net := peerNet.AsMsgNetwork()
 
@@ -208,6 +229,90 @@ func (nm *Handshake) gossipPeerList() {
 // connected to this node.
 func (nm *Handshake) Connections() Connections { return &nm.connections }
 
+// PeerVersions returns the version each connected peer runs, by the peer's IP
+func (nm *Handshake) PeerVersions() map[string]string {
+	peerIPs, peerIDs := nm.connections.Conns()
+	versions := make(map[string]string, len(peerIDs))
+	for i, peerID := range peerIDs {
+		if v, exists := nm.versions.get(peerID); exists {
+			versions[peerIPs[i].String()] = v.String()
+		}
+	}
+	return versions
+}
+
+// VersionDistribution returns the number of connected peers running each
+// version
+func (nm *Handshake) VersionDistribution() map[string]int { return nm.versions.distribution() }
+
+// connectedVersion records that peer [cert] runs [v]
+func (nm *Handshake) connectedVersion(cert ids.ShortID, v version.Version) {
+	if old, exists := nm.versions.add(cert, v); exists {
+		nm.peerVersions.WithLabelValues(old.String()).Dec()
+	}
+	nm.peerVersions.WithLabelValues(v.String()).Inc()
+
+	major, fraction, newer := nm.versions.newerMajor(currentVersion, nm.vdrs, nm.upgradeWarningFraction)
+	if newer {
+		nm.log.Warn("%.1f%% of the stake runs major version %d, which is newer than this node's version %s. This node should be upgraded",
+			100*fraction, major, currentVersion)
+	}
+}
+
+// disconnectedVersion stops recording the version peer [cert] runs
+func (nm *Handshake) disconnectedVersion(cert ids.ShortID) {
+	if old, exists := nm.versions.remove(cert); exists {
+		nm.peerVersions.WithLabelValues(old.String()).Dec()
+	}
+}
+
+// BanID bans the node [nodeID] for [duration], or until it's unbanned if
+// [duration] is 0, and disconnects from it
+func (nm *Handshake) BanID(nodeID ids.ShortID, duration time.Duration) error {
+	if err := nm.bans.BanID(nodeID, duration); err != nil {
+		return err
+	}
+	nm.disconnectBanned()
+	return nil
+}
+
+// BanCIDR bans the IPs in the range [cidr] for [duration], or until it's
+// unbanned if [duration] is 0, and disconnects from the peers in it
+func (nm *Handshake) BanCIDR(cidr string, duration time.Duration) error {
+	if err := nm.bans.BanCIDR(cidr, duration); err != nil {
+		return err
+	}
+	nm.disconnectBanned()
+	return nil
+}
+
+// UnbanID lifts the ban of the node [nodeID]
+func (nm *Handshake) UnbanID(nodeID ids.ShortID) error { return nm.bans.UnbanID(nodeID) }
+
+// UnbanCIDR lifts the ban of the range [cidr]
+func (nm *Handshake) UnbanCIDR(cidr string) error { return nm.bans.UnbanCIDR(cidr) }
+
+// Bans returns the bans in effect
+func (nm *Handshake) Bans() []bans.Ban { return nm.bans.Bans() }
+
+// Banned returns true if [ip] is in a banned range, so it shouldn't be dialed
+func (nm *Handshake) Banned(ip utils.IPDesc) bool { return nm.bans.BannedIP(ip.IP) }
+
+// disconnectBanned disconnects from the connected and connecting peers that are
+// banned
+func (nm *Handshake) disconnectBanned() {
+	for _, conns := range []*AddrCert{&nm.pending, &nm.connections} {
+		addrs, certs := conns.RawConns()
+		for i, addr := range addrs {
+			ip := toIPDesc(addr)
+			if nm.bans.Banned(certs[i], ip.IP) {
+				nm.log.Info("Disconnecting from banned peer %s", ip)
+				nm.net.DelPeer(addr)
+			}
+		}
+	}
+}
+
 // Shutdown the network
 func (nm *Handshake) Shutdown() {
 	nm.versionTimeout.Stop()
@@ -314,6 +419,12 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 			return
 		}
 
+		if HandshakeNet.connections.ContainsIP(addr) {
+			if err := HandshakeNet.uptimes.Disconnected(cert); err != nil {
+				HandshakeNet.log.Error("Failed to record the uptime of %s due to %s", cert, err)
+			}
+			HandshakeNet.disconnectedVersion(cert)
+		}
 		HandshakeNet.pending.RemoveIP(addr)
 		HandshakeNet.connections.RemoveIP(addr)
 
@@ -341,6 +452,12 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 	} else {
 		cert = toShortID(ip)
 	}
+	if HandshakeNet.bans.Banned(cert, ip.IP) {
+		HandshakeNet.log.Debug("Dropping connection to banned peer %s", ip)
+
+		HandshakeNet.net.DelPeer(addr)
+		return
+	}
 	HandshakeNet.pending.Add(addr, cert)
 
 	certID := cert.LongID()
@@ -359,6 +476,10 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 func unknownPeerHandler(_addr *C.netaddr_t, _cert *C.x509_t, _ unsafe.Pointer) {
 	addr := salticidae.NetAddrFromC(salticidae.CNetAddr(_addr))
 	ip := toIPDesc(addr)
+	if HandshakeNet.Banned(ip) {
+		HandshakeNet.log.Debug("Not adding banned peer %s", ip)
+		return
+	}
 	HandshakeNet.log.Info("Adding peer %s", ip)
 	HandshakeNet.net.AddPeer(addr)
 }
@@ -449,7 +570,15 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 		return
 	}
 
-	if peerVersion := pMsg.Get(VersionStr).(string); !checkCompatibility(CurrentVersion, peerVersion) {
+	peerVersionStr := pMsg.Get(VersionStr).(string)
+	peerVersion, err := version.Parse(peerVersionStr)
+	if err != nil {
+		HandshakeNet.log.Warn("Peer's version %q is malformed due to %s", peerVersionStr, err)
+
+		HandshakeNet.net.DelPeer(addr)
+		return
+	}
+	if !checkCompatibility(currentVersion, peerVersion) {
 		HandshakeNet.log.Warn("Bad version")
 
 		HandshakeNet.net.DelPeer(addr)
@@ -460,6 +589,8 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 
 	HandshakeNet.SendPeerList(addr)
 	HandshakeNet.connections.Add(addr, cert)
+	HandshakeNet.uptimes.Connected(cert)
+	HandshakeNet.connectedVersion(cert, peerVersion)
 
 	HandshakeNet.versionTimeout.Remove(cert.LongID())
 
@@ -526,7 +657,7 @@ func peerList(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 		if cErr.GetCode() == 0 && !HandshakeNet.myAddr.IsEq(addr) { // Make sure not to connect to myself
 			ip := toIPDesc(addr)
 
-			if !HandshakeNet.pending.ContainsIP(addr) && !HandshakeNet.connections.ContainsIP(addr) {
+			if !HandshakeNet.pending.ContainsIP(addr) && !HandshakeNet.connections.ContainsIP(addr) && !HandshakeNet.Banned(ip) {
 				HandshakeNet.log.Debug("Adding peer %s", ip)
 				HandshakeNet.net.AddPeer(addr)
 			}
@@ -559,9 +690,16 @@ func getCert(cert salticidae.X509) ids.ShortID {
 }
 
 // checkCompatibility Check to make sure that the peer and I speak the same language.
-func checkCompatibility(myVersion string, peerVersion string) bool {
-	// At the moment, we are all compatible.
-	return true
+func checkCompatibility(myVersion version.Version, peerVersion version.Version) bool {
+	return myVersion.Compatible(peerVersion)
+}
+
+func mustParseVersion(s string) version.Version {
+	v, err := version.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
 }
 
 func toAddr(ip utils.IPDesc, autoFree bool) salticidae.NetAddr {
@@ -11,6 +11,8 @@ import (
 
 type handshakeMetrics struct {
 	numPeers prometheus.Gauge
+	// version -> number of connected peers running it
+	peerVersions *prometheus.GaugeVec
 
 	numGetVersionSent, numGetVersionReceived,
 	numVersionSent, numVersionReceived,
@@ -25,6 +27,13 @@ func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus
 			Name:      "peers",
 			Help:      "Number of network peers",
 		})
+	hm.peerVersions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "peer_versions",
+			Help:      "Number of network peers running each version",
+		},
+		[]string{"version"})
 	hm.numGetVersionSent = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "gecko",
@@ -77,6 +86,9 @@ func (hm *handshakeMetrics) Initialize(log logging.Logger, registerer prometheus
 	if err := registerer.Register(hm.numPeers); err != nil {
 		log.Error("Failed to register peers statistics due to %s", err)
 	}
+	if err := registerer.Register(hm.peerVersions); err != nil {
+		log.Error("Failed to register peer_versions statistics due to %s", err)
+	}
 	if err := registerer.Register(hm.numGetVersionSent); err != nil {
 		log.Error("Failed to register get_version_sent statistics due to %s", err)
 	}
@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/version"
+)
+
+// peerVersions tracks the versions connected peers reported in their
+// handshakes
+type peerVersions struct {
+	lock sync.Mutex
+	// peer ID -> version the peer runs
+	versions map[[20]byte]version.Version
+	// version -> number of connected peers running it
+	counts map[version.Version]int
+	// Newest major version a warning was logged about
+	warnedMajor int
+}
+
+// add that peer [id] runs [v]. Returns the version [id] was previously
+// recorded as running, if any.
+func (pv *peerVersions) add(id ids.ShortID, v version.Version) (version.Version, bool) {
+	pv.lock.Lock()
+	defer pv.lock.Unlock()
+
+	if pv.versions == nil {
+		pv.versions = make(map[[20]byte]version.Version)
+		pv.counts = make(map[version.Version]int)
+	}
+
+	key := id.Key()
+	old, exists := pv.versions[key]
+	if exists {
+		pv.decrement(old)
+	}
+	pv.versions[key] = v
+	pv.counts[v]++
+	return old, exists
+}
+
+// remove peer [id]. Returns the version [id] was recorded as running, if any.
+func (pv *peerVersions) remove(id ids.ShortID) (version.Version, bool) {
+	pv.lock.Lock()
+	defer pv.lock.Unlock()
+
+	key := id.Key()
+	old, exists := pv.versions[key]
+	if exists {
+		delete(pv.versions, key)
+		pv.decrement(old)
+	}
+	return old, exists
+}
+
+func (pv *peerVersions) decrement(v version.Version) {
+	if pv.counts[v]--; pv.counts[v] <= 0 {
+		delete(pv.counts, v)
+	}
+}
+
+// get the version peer [id] runs
+func (pv *peerVersions) get(id ids.ShortID) (version.Version, bool) {
+	pv.lock.Lock()
+	defer pv.lock.Unlock()
+
+	v, exists := pv.versions[id.Key()]
+	return v, exists
+}
+
+// distribution returns the number of connected peers running each version
+func (pv *peerVersions) distribution() map[string]int {
+	pv.lock.Lock()
+	defer pv.lock.Unlock()
+
+	dist := make(map[string]int, len(pv.counts))
+	for v, count := range pv.counts {
+		dist[v.String()] = count
+	}
+	return dist
+}
+
+// newerMajor returns the newest major version newer than [current]'s that
+// at least [fraction] of the stake of [vdrs] runs, and hasn't been returned
+// before. Returns false if there isn't one.
+func (pv *peerVersions) newerMajor(current version.Version, vdrs validators.Set, fraction float64) (int, float64, bool) {
+	pv.lock.Lock()
+	defer pv.lock.Unlock()
+
+	totalWeight := uint64(0)
+	// major version -> weight of the validators running it
+	majorWeights := make(map[int]uint64)
+	for _, vdr := range vdrs.List() {
+		weight := vdr.Weight()
+		totalWeight += weight
+
+		v, exists := pv.versions[vdr.ID().Key()]
+		if exists && v.Major > current.Major && v.Major > pv.warnedMajor {
+			majorWeights[v.Major] += weight
+		}
+	}
+	if totalWeight == 0 {
+		return 0, 0, false
+	}
+
+	// Stake running a major version also signals support for the major
+	// versions before it, so the weights are accumulated from the newest down.
+	newest, newestFraction := 0, float64(0)
+	for major := range majorWeights {
+		weight := uint64(0)
+		for otherMajor, otherWeight := range majorWeights {
+			if otherMajor >= major {
+				weight += otherWeight
+			}
+		}
+		if f := float64(weight) / float64(totalWeight); f >= fraction && major > newest {
+			newest, newestFraction = major, f
+		}
+	}
+	if newest == 0 {
+		return 0, 0, false
+	}
+	pv.warnedMajor = newest
+	return newest, newestFraction, true
+}
@@ -13,11 +13,17 @@ package networking
 // void pushQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void pullQuery(msg_t *, msgnetwork_conn_t *, void *);
 // void chits(msg_t *, msgnetwork_conn_t *, void *);
+// void appGossip(msg_t *, msgnetwork_conn_t *, void *);
+// void getStateSummary(msg_t *, msgnetwork_conn_t *, void *);
+// void stateSummary(msg_t *, msgnetwork_conn_t *, void *);
+// void getStateChunk(msg_t *, msgnetwork_conn_t *, void *);
+// void stateChunk(msg_t *, msgnetwork_conn_t *, void *);
 import "C"
 
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"unsafe"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -25,13 +31,22 @@ import (
 	"github.com/ava-labs/salticidae-go"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
 	"github.com/ava-labs/gecko/utils/timer"
 )
 
+const (
+	// AppGossipSize is the number of validators each app gossip message is
+	// sent to. Receivers gossip the message on, so it reaches the other
+	// validators over a few hops.
+	AppGossipSize = 10
+)
+
 var (
 	// VotingNet implements the SenderExternal interface.
 	VotingNet = Voting{}
@@ -46,22 +61,27 @@ type Voting struct {
 	votingMetrics
 
 	log   logging.Logger
-	vdrs  validators.Set
+	vdrs  validators.Manager // The validator set of each subnet
 	net   salticidae.PeerNetwork
 	conns Connections
 
+	// The ID of the subnet that validates each chain, keyed by the chain's ID
+	subnetsLock sync.RWMutex
+	subnets     map[[32]byte]ids.ID
+
 	router   router.Router
 	executor timer.Executor
 }
 
 // Initialize to the c networking library. Should only be called once ever.
-func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet salticidae.PeerNetwork, conns Connections, router router.Router, registerer prometheus.Registerer) {
+func (s *Voting) Initialize(log logging.Logger, vdrs validators.Manager, peerNet salticidae.PeerNetwork, conns Connections, router router.Router, registerer prometheus.Registerer) {
 	log.AssertTrue(s.net == nil, "Should only register network handlers once")
 	log.AssertTrue(s.conns == nil, "Should only set connections once")
 	log.AssertTrue(s.router == nil, "Should only set the router once")
 
 	s.log = log
 	s.vdrs = vdrs
+	s.subnets = make(map[[32]byte]ids.ID)
 	s.net = peerNet
 	s.conns = conns
 	s.router = router
@@ -79,6 +99,11 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 	net.RegHandler(PushQuery, salticidae.MsgNetworkMsgCallback(C.pushQuery), nil)
 	net.RegHandler(PullQuery, salticidae.MsgNetworkMsgCallback(C.pullQuery), nil)
 	net.RegHandler(Chits, salticidae.MsgNetworkMsgCallback(C.chits), nil)
+	net.RegHandler(AppGossip, salticidae.MsgNetworkMsgCallback(C.appGossip), nil)
+	net.RegHandler(GetStateSummary, salticidae.MsgNetworkMsgCallback(C.getStateSummary), nil)
+	net.RegHandler(StateSummary, salticidae.MsgNetworkMsgCallback(C.stateSummary), nil)
+	net.RegHandler(GetStateChunk, salticidae.MsgNetworkMsgCallback(C.getStateChunk), nil)
+	net.RegHandler(StateChunk, salticidae.MsgNetworkMsgCallback(C.stateChunk), nil)
 
 	s.executor.Initialize()
 	go log.RecoverAndPanic(s.executor.Dispatch)
@@ -87,13 +112,47 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 // Shutdown threads
 func (s *Voting) Shutdown() { s.executor.Stop() }
 
-// Accept is called after every consensus decision
+// RegisterChain implements the chains.Registrant interface. It records the
+// subnet that validates the chain of [ctx].
+func (s *Voting) RegisterChain(ctx *snow.Context, _ interface{}) {
+	s.subnetsLock.Lock()
+	defer s.subnetsLock.Unlock()
+
+	s.subnets[ctx.ChainID.Key()] = ctx.SubnetID
+}
+
+// validators returns the validator set of the subnet [subnetID]
+func (s *Voting) validators(subnetID ids.ID) (validators.Set, bool) {
+	return s.vdrs.GetValidatorSet(subnetID)
+}
+
+// chainValidators returns the validator set of the subnet that validates the
+// chain [chainID]
+func (s *Voting) chainValidators(chainID ids.ID) (validators.Set, bool) {
+	s.subnetsLock.RLock()
+	subnetID, exists := s.subnets[chainID.Key()]
+	s.subnetsLock.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	return s.validators(subnetID)
+}
+
+// Accept is called after every consensus decision. The container is sent to
+// the connected nodes that don't validate the chain's subnet.
 func (s *Voting) Accept(chainID, containerID ids.ID, container []byte) error {
+	vdrs, exists := s.chainValidators(chainID)
+	if !exists {
+		s.log.Debug("Not sending the accepted container %s to non-validators as chain %s wasn't registered", containerID, chainID)
+		return nil
+	}
+
 	addrs := []salticidae.NetAddr(nil)
 
 	allAddrs, allIDs := s.conns.RawConns()
 	for i, id := range allIDs {
-		if !s.vdrs.Contains(id) {
+		if !vdrs.Contains(id) {
 			addrs = append(addrs, allAddrs[i])
 		}
 	}
@@ -422,6 +481,176 @@ func (s *Voting) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32
 	s.numChitsSent.Inc()
 }
 
+// AppGossip implements the Sender interface. [appMsg] is sent to a sample of
+// AppGossipSize of the connected validators of the subnet [subnetID].
+func (s *Voting) AppGossip(subnetID, chainID ids.ID, appMsg []byte) {
+	vdrs, exists := s.validators(subnetID)
+	if !exists {
+		s.log.Debug("Not sending an AppGossip message for chain %s as subnet %s has no validator set", chainID, subnetID)
+		return
+	}
+
+	vdrAddrs := []salticidae.NetAddr(nil)
+	allAddrs, allIDs := s.conns.RawConns()
+	for i, id := range allIDs {
+		if vdrs.Contains(id) {
+			vdrAddrs = append(vdrAddrs, allAddrs[i])
+		}
+	}
+
+	numToSend := AppGossipSize
+	if len(vdrAddrs) < numToSend {
+		numToSend = len(vdrAddrs)
+	}
+	addrs := make([]salticidae.NetAddr, numToSend)
+	sampler := random.Uniform{N: len(vdrAddrs)}
+	for i := range addrs {
+		addrs[i] = vdrAddrs[sampler.Sample()]
+	}
+
+	build := Builder{}
+	msg, err := build.AppGossip(chainID, appMsg)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of an AppGossip message.\nMessage length: %d", len(appMsg))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending an AppGossip message."+
+		"\nNumber of Validators: %d"+
+		"\nChain: %s"+
+		"\nMessage:\n%s",
+		len(addrs),
+		chainID,
+		formatting.DumpBytes{Bytes: appMsg},
+	)
+	s.send(msg, addrs...)
+	s.numAppGossipSent.Add(float64(len(addrs)))
+}
+
+// GetStateSummary implements the Sender interface.
+func (s *Voting) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	addrs := []salticidae.NetAddr(nil)
+	validatorIDList := validatorIDs.List()
+	for _, validatorID := range validatorIDList {
+		vID := validatorID
+		if addr, exists := s.conns.GetIP(vID); exists {
+			addrs = append(addrs, addr)
+			s.log.Verbo("Sending a GetStateSummary to %s", toIPDesc(addr))
+		} else {
+			s.log.Debug("Attempted to send a GetStateSummary message to a disconnected validator: %s", vID)
+			s.executor.Add(func() { s.router.GetStateSummaryFailed(vID, chainID, requestID) })
+		}
+	}
+
+	build := Builder{}
+	msg, err := build.GetStateSummary(chainID, requestID)
+	s.log.AssertNoError(err)
+
+	s.log.Verbo("Sending a GetStateSummary message."+
+		"\nNumber of Validators: %d"+
+		"\nChain: %s"+
+		"\nRequest ID: %d",
+		len(addrs),
+		chainID,
+		requestID,
+	)
+	s.send(msg, addrs...)
+	s.numGetStateSummarySent.Add(float64(len(addrs)))
+}
+
+// StateSummary implements the Sender interface.
+func (s *Voting) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a StateSummary message to a disconnected validator: %s", validatorID)
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.StateSummary(chainID, requestID, summary)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a StateSummary message.\nSummary length: %d", len(summary))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a StateSummary message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nSummary:\n%s",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		formatting.DumpBytes{Bytes: summary},
+	)
+	s.send(msg, addr)
+	s.numStateSummarySent.Inc()
+}
+
+// GetStateChunk implements the Sender interface.
+func (s *Voting) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a GetStateChunk message to a disconnected validator: %s", validatorID)
+		s.executor.Add(func() { s.router.GetStateChunkFailed(validatorID, chainID, requestID) })
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.GetStateChunk(chainID, requestID, summaryID, index)
+	s.log.AssertNoError(err)
+
+	s.log.Verbo("Sending a GetStateChunk message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nSummary ID: %s"+
+		"\nChunk Index: %d",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		summaryID,
+		index,
+	)
+	s.send(msg, addr)
+	s.numGetStateChunkSent.Inc()
+}
+
+// StateChunk implements the Sender interface.
+func (s *Voting) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a StateChunk message to a disconnected validator: %s", validatorID)
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.StateChunk(chainID, requestID, chunk)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a StateChunk message.\nChunk length: %d", len(chunk))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a StateChunk message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nChunk length: %d",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		len(chunk),
+	)
+	s.send(msg, addr)
+	s.numStateChunkSent.Inc()
+}
+
 func (s *Voting) send(msg Msg, addrs ...salticidae.NetAddr) {
 	ds := msg.DataStream()
 	defer ds.Free()
@@ -618,6 +847,79 @@ func chits(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Poi
 	VotingNet.router.Chits(validatorID, chainID, requestID, votes)
 }
 
+// appGossip handles the recept of an app gossip message
+//export appGossip
+func appGossip(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numAppGossipReceived.Inc()
+
+	validatorID, chainID, _, msg, err := VotingNet.sanitize(_msg, _conn, AppGossip)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	VotingNet.router.AppGossip(validatorID, chainID, msg.Get(AppBytes).([]byte))
+}
+
+// getStateSummary handles the recept of a request for a chain's state summary
+//export getStateSummary
+func getStateSummary(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGetStateSummaryReceived.Inc()
+
+	validatorID, chainID, requestID, _, err := VotingNet.sanitize(_msg, _conn, GetStateSummary)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	VotingNet.router.GetStateSummary(validatorID, chainID, requestID)
+}
+
+// stateSummary handles the receipt of a chain's state summary
+//export stateSummary
+func stateSummary(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numStateSummaryReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, StateSummary)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	VotingNet.router.StateSummary(validatorID, chainID, requestID, msg.Get(StateBytes).([]byte))
+}
+
+// getStateChunk handles the recept of a request for a chunk of a chain's state
+//export getStateChunk
+func getStateChunk(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGetStateChunkReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, GetStateChunk)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	summaryID, _ := ids.ToID(msg.Get(ContainerID).([]byte))
+	index := msg.Get(ChunkIndex).(uint32)
+
+	VotingNet.router.GetStateChunk(validatorID, chainID, requestID, summaryID, index)
+}
+
+// stateChunk handles the receipt of a chunk of a chain's state
+//export stateChunk
+func stateChunk(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numStateChunkReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, StateChunk)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	VotingNet.router.StateChunk(validatorID, chainID, requestID, msg.Get(StateBytes).([]byte))
+}
+
 func (s *Voting) sanitize(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, op salticidae.Opcode) (ids.ShortID, ids.ID, uint32, Msg, error) {
 	conn := salticidae.PeerNetworkConnFromC(salticidae.CPeerNetworkConn((*C.peernetwork_conn_t)(_conn)))
 	addr := conn.GetPeerAddr(false)
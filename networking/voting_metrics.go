@@ -18,7 +18,12 @@ type votingMetrics struct {
 	numPutSent, numPutReceived,
 	numPushQuerySent, numPushQueryReceived,
 	numPullQuerySent, numPullQueryReceived,
-	numChitsSent, numChitsReceived prometheus.Counter
+	numChitsSent, numChitsReceived,
+	numAppGossipSent, numAppGossipReceived,
+	numGetStateSummarySent, numGetStateSummaryReceived,
+	numStateSummarySent, numStateSummaryReceived,
+	numGetStateChunkSent, numGetStateChunkReceived,
+	numStateChunkSent, numStateChunkReceived prometheus.Counter
 }
 
 func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Registerer) {
@@ -130,6 +135,66 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 			Name:      "chits_received",
 			Help:      "Number of chits messages received",
 		})
+	vm.numAppGossipSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "app_gossip_sent",
+			Help:      "Number of app gossip messages sent",
+		})
+	vm.numAppGossipReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "app_gossip_received",
+			Help:      "Number of app gossip messages received",
+		})
+	vm.numGetStateSummarySent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_state_summary_sent",
+			Help:      "Number of get state summary messages sent",
+		})
+	vm.numGetStateSummaryReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_state_summary_received",
+			Help:      "Number of get state summary messages received",
+		})
+	vm.numStateSummarySent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "state_summary_sent",
+			Help:      "Number of state summary messages sent",
+		})
+	vm.numStateSummaryReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "state_summary_received",
+			Help:      "Number of state summary messages received",
+		})
+	vm.numGetStateChunkSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_state_chunk_sent",
+			Help:      "Number of get state chunk messages sent",
+		})
+	vm.numGetStateChunkReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_state_chunk_received",
+			Help:      "Number of get state chunk messages received",
+		})
+	vm.numStateChunkSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "state_chunk_sent",
+			Help:      "Number of state chunk messages sent",
+		})
+	vm.numStateChunkReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "state_chunk_received",
+			Help:      "Number of state chunk messages received",
+		})
 
 	if err := registerer.Register(vm.numGetAcceptedFrontierSent); err != nil {
 		log.Error("Failed to register get_accepted_frontier_sent statistics due to %s", err)
@@ -185,4 +250,34 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 	if err := registerer.Register(vm.numChitsReceived); err != nil {
 		log.Error("Failed to register chits_received statistics due to %s", err)
 	}
+	if err := registerer.Register(vm.numAppGossipSent); err != nil {
+		log.Error("Failed to register app_gossip_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numAppGossipReceived); err != nil {
+		log.Error("Failed to register app_gossip_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetStateSummarySent); err != nil {
+		log.Error("Failed to register get_state_summary_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetStateSummaryReceived); err != nil {
+		log.Error("Failed to register get_state_summary_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numStateSummarySent); err != nil {
+		log.Error("Failed to register state_summary_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numStateSummaryReceived); err != nil {
+		log.Error("Failed to register state_summary_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetStateChunkSent); err != nil {
+		log.Error("Failed to register get_state_chunk_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetStateChunkReceived); err != nil {
+		log.Error("Failed to register get_state_chunk_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numStateChunkSent); err != nil {
+		log.Error("Failed to register state_chunk_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numStateChunkReceived); err != nil {
+		log.Error("Failed to register state_chunk_received statistics due to %s", err)
+	}
 }
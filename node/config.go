@@ -4,13 +4,20 @@
 package node
 
 import (
+	"time"
+
 	"github.com/ava-labs/go-ethereum/p2p/nat"
 
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/networking/netsim"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Config contains all of the configurations of an Ava node.
@@ -24,6 +31,18 @@ type Config struct {
 	// Transaction fee configuration
 	AvaTxFee uint64
 
+	// Mempool configuration
+	AVMMempoolSize int
+
+	// EVM configuration. Zero values keep the EVM chain's genesis settings.
+	EVMChainID     uint64
+	EVMGasLimit    uint64
+	EVMMinGasPrice uint64
+
+	// Directory of VM plugins. Each file in it is the executable of a plugin
+	// named by the ID of the VM it runs.
+	PluginDir string
+
 	// Assertions configuration
 	EnableAssertions bool
 
@@ -32,6 +51,35 @@ type Config struct {
 
 	// Database to use for the node
 	DB database.Database
+	// Directory of the database. Empty if the database isn't persisted.
+	DBDir string
+	// True if the database's values are encrypted at rest
+	DBEncrypted bool
+	// Backs up the database. Nil if the database can't be backed up.
+	DBBackupper database.Backupper
+	// How long rejected transactions and blocks are kept before they're
+	// pruned. If 0, they're never pruned. No other state is pruned.
+	StateRetention time.Duration
+
+	// Operators' configurations of chains, by chain ID or alias
+	ChainConfigs map[string]chains.ChainConfig
+
+	// Key/value settings and feature flags given to every chain
+	ChainSettings map[string]string
+
+	// If true, chains whose VMs support state sync sync to a recent state
+	// attested to by the beacons before bootstrapping
+	StateSyncEnabled bool
+
+	// If true, the consensus messages chains send and handle are traced
+	TraceMessages bool
+
+	// Network conditions consensus messages are sent under, to test consensus
+	// against a slow or unreliable network. Messages are sent normally unless
+	// it's set.
+	NetworkSimulation netsim.Conditions
+	// Seed of the random decisions of the network simulation
+	NetworkSimulationSeed int64
 
 	// Staking configuration
 	StakingIP       utils.IPDesc
@@ -39,19 +87,58 @@ type Config struct {
 	StakingKeyFile  string
 	StakingCertFile string
 
+	// A warning is logged when at least this fraction of the default subnet's
+	// stake runs a newer major version than this node
+	UpgradeWarningFraction float64
+
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
+	// DNS names whose TXT and SRV records list bootstrap peers
+	BootstrapDNSSeeds []string
+	// How often the DNS seeds are resolved again. If 0, they're only resolved
+	// when the node starts.
+	BootstrapDNSRefresh time.Duration
+
+	// Non-default subnets this node validates
+	WhitelistedSubnets ids.Set
 
 	// HTTP configuration
 	HTTPPort      uint16
 	EnableHTTPS   bool
 	HTTPSKeyFile  string
 	HTTPSCertFile string
+	// If not empty, HTTPS clients must present a certificate signed by a CA in
+	// this file
+	HTTPSClientCAFile string
+	// Origins browsers may call the HTTP APIs from
+	HTTPAllowedOrigins []string
+	// Maximum size, in bytes, of an API request. Not limited if 0.
+	APIMaxRequestSize int64
+	// Maximum number of requests in a JSON-RPC batch. Not limited if 0.
+	APIMaxBatchSize int
+	// Limits on the rate and number of concurrent API requests
+	APIRateLimits api.RateLimits
 
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
+	EventsAPIEnabled   bool
+	HealthAPIEnabled   bool
+	InfoAPIEnabled     bool
+	GRPCAPIEnabled     bool
+	IndexAPIEnabled    bool
+
+	// Port of the gRPC server
+	GRPCPort uint16
+
+	// Health configuration
+	HealthMinPeers        int
+	HealthMaxLastAccepted time.Duration
+
+	// API authorization configuration
+	APIRequireAuth  bool
+	APIAuthPassword string
 
 	// Logging configuration
 	LoggingConfig logging.Config
@@ -65,7 +152,37 @@ type Config struct {
 
 	// IPCEnabled configuration
 	IPCEnabled bool
+	// Directory IPC sockets are created in
+	IPCPath string
 
 	// Router that is used to handle incoming consensus messages
 	ConsensusRouter router.Router
+
+	// How long the node waits for its APIs and chains to shut down
+	ShutdownTimeout time.Duration
+
+	// If ReplayChain is set, the node replays the containers of ReplaySource
+	// through the VM of that chain instead of running. ReplaySource is the
+	// path of an archive, or the URL of another node's index of the chain.
+	ReplayChain  string
+	ReplaySource string
+
+	// Timeouts of requests sent to other nodes
+	NetworkTimeout timer.AdaptiveTimeoutConfig
+
+	// Flags the node was started with, in lexicographical order. Secrets are
+	// redacted.
+	Flags []Flag
+}
+
+// Flag is a command line flag of the node
+type Flag struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	// True if the flag was given rather than defaulted
+	Set bool `json:"set"`
+	// Where the flag's value came from. One of "command line",
+	// "environment", "config file" or "default".
+	Source string `json:"source"`
 }
@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"sort"
+
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+// EffectiveConfig is the configuration a node is running with, including the
+// values derived from its flags. Secrets are redacted.
+type EffectiveConfig struct {
+	Version     string      `json:"version"`
+	NodeID      ids.ShortID `json:"nodeID"`
+	NetworkID   json.Uint32 `json:"networkID"`
+	NetworkName string      `json:"networkName"`
+
+	StakingIP      string      `json:"stakingIP"`
+	StakingEnabled bool        `json:"stakingEnabled"`
+	HTTPPort       json.Uint16 `json:"httpPort"`
+	HTTPSEnabled   bool        `json:"httpsEnabled"`
+	GRPCPort       json.Uint16 `json:"grpcPort"`
+	ThroughputPort json.Uint16 `json:"throughputPort"`
+
+	// Empty if the database isn't persisted
+	DBDir       string `json:"dbDir"`
+	DBEncrypted bool   `json:"dbEncrypted"`
+	LogDir      string `json:"logDir"`
+	PluginDir   string `json:"pluginDir"`
+	IPCPath     string `json:"ipcPath"`
+
+	// Empty if state isn't pruned
+	StateRetention string `json:"stateRetention"`
+
+	StateSyncEnabled bool `json:"stateSyncEnabled"`
+	TraceMessages    bool `json:"traceMessages"`
+
+	// Names of the APIs this node serves
+	EnabledAPIs     []string `json:"enabledAPIs"`
+	APIAuthRequired bool     `json:"apiAuthRequired"`
+
+	// Each peer is formatted as ID@IP
+	BootstrapPeers     []string `json:"bootstrapPeers"`
+	BootstrapDNSSeeds  []string `json:"bootstrapDNSSeeds"`
+	WhitelistedSubnets []string `json:"whitelistedSubnets"`
+
+	Flags []Flag `json:"flags"`
+}
+
+// effectiveConfig returns the configuration this node is running with
+// Assumes n.ID is already set
+func (n *Node) effectiveConfig() *EffectiveConfig {
+	config := &EffectiveConfig{
+		Version:            networking.CurrentVersion,
+		NodeID:             n.ID,
+		NetworkID:          json.Uint32(n.Config.NetworkID),
+		NetworkName:        genesis.NetworkName(n.Config.NetworkID),
+		StakingIP:          n.Config.StakingIP.String(),
+		StakingEnabled:     n.Config.EnableStaking,
+		HTTPPort:           json.Uint16(n.Config.HTTPPort),
+		HTTPSEnabled:       n.Config.EnableHTTPS,
+		GRPCPort:           json.Uint16(n.Config.GRPCPort),
+		ThroughputPort:     json.Uint16(n.Config.ThroughputPort),
+		DBDir:              n.Config.DBDir,
+		DBEncrypted:        n.Config.DBEncrypted,
+		LogDir:             n.Config.LoggingConfig.Directory,
+		PluginDir:          n.Config.PluginDir,
+		IPCPath:            n.Config.IPCPath,
+		EnabledAPIs:        []string{},
+		APIAuthRequired:    n.Config.APIRequireAuth,
+		BootstrapPeers:     []string{},
+		BootstrapDNSSeeds:  append([]string{}, n.Config.BootstrapDNSSeeds...),
+		WhitelistedSubnets: []string{},
+		StateSyncEnabled:   n.Config.StateSyncEnabled,
+		TraceMessages:      n.Config.TraceMessages,
+		Flags:              n.Config.Flags,
+	}
+	if n.Config.StateRetention > 0 {
+		config.StateRetention = n.Config.StateRetention.String()
+	}
+
+	apis := []struct {
+		name    string
+		enabled bool
+	}{
+		{"admin", n.Config.AdminAPIEnabled},
+		{"keystore", n.Config.KeystoreAPIEnabled},
+		{"metrics", n.Config.MetricsAPIEnabled},
+		{"events", n.Config.EventsAPIEnabled},
+		{"health", n.Config.HealthAPIEnabled},
+		{"info", n.Config.InfoAPIEnabled},
+		{"grpc", n.Config.GRPCAPIEnabled},
+		{"ipcs", n.Config.IPCEnabled},
+	}
+	for _, api := range apis {
+		if api.enabled {
+			config.EnabledAPIs = append(config.EnabledAPIs, api.name)
+		}
+	}
+
+	for _, peer := range n.Config.BootstrapPeers {
+		config.BootstrapPeers = append(config.BootstrapPeers, peer.ID.String()+"@"+peer.IP.String())
+	}
+	for _, subnetID := range n.Config.WhitelistedSubnets.List() {
+		config.WhitelistedSubnets = append(config.WhitelistedSubnets, subnetID.String())
+	}
+	sort.Strings(config.WhitelistedSubnets)
+	return config
+}
@@ -9,37 +9,61 @@ package node
 import "C"
 
 import (
-	"crypto/x509"
-	"encoding/pem"
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 	"unsafe"
 
 	"github.com/ava-labs/salticidae-go"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/auth"
+	"github.com/ava-labs/gecko/api/events"
+	"github.com/ava-labs/gecko/api/gateway"
+	"github.com/ava-labs/gecko/api/health"
+	"github.com/ava-labs/gecko/api/indexer"
+	"github.com/ava-labs/gecko/api/info"
 	"github.com/ava-labs/gecko/api/ipcs"
 	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/api/metrics"
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/meterdb"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/networking/bans"
 	"github.com/ava-labs/gecko/networking/xputtest"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/netsim"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+	"github.com/ava-labs/gecko/snow/networking/tracing"
 	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/snow/uptime"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/staking"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/vms"
 	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/core"
 	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/rpcchainvm"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
@@ -50,6 +74,24 @@ const (
 	defaultChannelSize     = 1
 	externalRequestTimeout = 2 * time.Second
 	internalRequestTimeout = 250 * time.Millisecond
+	uptimeFlushFrequency   = time.Minute
+	seedResolveTimeout     = 30 * time.Second
+
+	// Version of the layout of the node's database. Should be incremented
+	// whenever a change to the layout requires existing databases to be
+	// migrated.
+	databaseVersion = 1
+)
+
+var (
+	// ErrDatabaseMigrationNeeded is returned by Initialize when the node's
+	// database was written with a different layout, and must be migrated
+	// before this node can use it
+	ErrDatabaseMigrationNeeded = errors.New("the database must be migrated")
+
+	errShutdownTimeout = errors.New("chains didn't shut down in time")
+
+	databaseVersionKey = []byte("database version")
 )
 
 // MainNode is the reference for node callbacks
@@ -68,12 +110,42 @@ type Node struct {
 	// Storage for this node
 	DB database.Database
 
+	// Gathers the metrics of the node, its database and each of its chains
+	metricsGatherer *metrics.MultiGatherer
+	// Serves the gathered metrics
+	metricsHandler *common.HTTPHandler
+
 	// Handles calls to Keystore API
 	keystoreServer keystore.Keystore
 
+	// Authorizes calls to the HTTP APIs
+	auth auth.Auth
+
+	// Publishes accepted containers over Unix domain sockets. Nil if IPCs are
+	// disabled.
+	ipcs *ipcs.IPCs
+
+	// Serves the info and health APIs. Nil if the API is disabled.
+	infoService   *info.Info
+	healthService *health.Health
+
+	// Serves the APIs over gRPC and REST. Nil if gRPC is disabled.
+	gateway *gateway.Gateway
+
+	// Manages shared memory
+	sharedMemory core.SharedMemory
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
+	// Traces the consensus messages of chains. Nil if they aren't traced.
+	tracer *tracing.Tracer
+
+	// Tracks how long other nodes are connected to this node, and flushes
+	// the tracked time to the database periodically
+	uptimes       *uptime.Manager
+	uptimeFlusher *timer.Repeater
+
 	// Manages Virtual Machines
 	vmManager vms.Manager
 
@@ -96,6 +168,15 @@ type Node struct {
 	// current validators of the network
 	vdrs validators.Manager
 
+	// Peers this node doesn't connect to
+	bans *bans.List
+
+	// Periodically adds the bootstrap peers listed by the DNS seeds as peers.
+	// Nil if there are no DNS seeds.
+	seedRefresher *timer.Repeater
+	// IPs of the bootstrap peers that were added as peers
+	bootstrapIPs map[string]bool
+
 	// APIs that handle client messages
 	// TODO: Remove
 	Issuer     *xputtest.Issuer
@@ -199,19 +280,22 @@ func (n *Node) initValidatorNet() error {
 		/*metrics=*/ n.Config.ConsensusParams.Metrics,
 		/*enableStaking=*/ n.Config.EnableStaking,
 		/*networkID=*/ n.Config.NetworkID,
+		/*uptimes=*/ n.uptimes,
+		/*upgradeWarningFraction=*/ n.Config.UpgradeWarningFraction,
+		/*bans=*/ n.bans,
 	)
 
 	return nil
 }
 
 func (n *Node) initConsensusNet() {
-	vdrs, ok := n.vdrs.GetValidatorSet(platformvm.DefaultSubnetID)
-	n.Log.AssertTrue(ok, "should have initialize the validator set already")
-
 	n.ConsensusAPI = &networking.VotingNet
-	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
+	n.ConsensusAPI.Initialize(n.Log, n.vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
 
 	n.Log.AssertNoError(n.ConsensusDispatcher.Register("gossip", n.ConsensusAPI))
+
+	// Tells the consensus network which subnet validates each chain
+	n.chainManager.AddRegistrant(n.ConsensusAPI)
 }
 
 func (n *Node) initClients() {
@@ -261,21 +345,103 @@ func (n *Node) StartConsensusServer() error {
 	}
 
 	// Add bootstrap nodes to the peer network
+	n.bootstrapIPs = make(map[string]bool)
 	for _, peer := range n.Config.BootstrapPeers {
-		if !peer.IP.Equal(n.Config.StakingIP) {
+		if n.ValidatorAPI.Banned(peer.IP) {
+			n.Log.Warn("not adding banned bootstrap peer %s", peer.IP)
+		} else if !peer.IP.Equal(n.Config.StakingIP) {
 			bootstrapIP := salticidae.NewNetAddrFromIPPortString(peer.IP.String(), true, &err)
 			if code := err.GetCode(); code != 0 {
 				return fmt.Errorf("failed to create bootstrap ip addr: %s", salticidae.StrError(code))
 			}
 			n.PeerNet.AddPeer(bootstrapIP)
+			n.bootstrapIPs[peer.IP.String()] = true
 		} else {
 			n.Log.Error("can't add self as a bootstrapper")
 		}
 	}
 
+	// The peers the DNS seeds list may change, so they're resolved again
+	// periodically. Peers listed later are connected to, but they aren't
+	// beacons of the chains that were already created.
+	if len(n.Config.BootstrapDNSSeeds) > 0 && n.Config.BootstrapDNSRefresh > 0 {
+		n.seedRefresher = timer.NewRepeater(n.refreshBootstrapSeeds, n.Config.BootstrapDNSRefresh)
+		go n.Log.RecoverAndPanic(n.seedRefresher.Dispatch)
+	}
+
 	return nil
 }
 
+// resolveBootstrapSeeds returns the bootstrap peers listed by the DNS seeds
+func (n *Node) resolveBootstrapSeeds() ([]*Peer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), seedResolveTimeout)
+	defer cancel()
+
+	peers, err := ResolveSeeds(ctx, net.DefaultResolver, n.Config.BootstrapDNSSeeds)
+	if err != nil {
+		return nil, err
+	}
+	if !n.Config.EnableStaking {
+		// Without staking, the ID of a peer is derived from its IP
+		for _, peer := range peers {
+			peer.ID = ids.NewShortID(hashing.ComputeHash160Array([]byte(peer.IP.String())))
+		}
+	}
+	return peers, nil
+}
+
+// initBootstrapSeeds adds the bootstrap peers listed by the DNS seeds to the
+// configured bootstrap peers
+func (n *Node) initBootstrapSeeds() {
+	if len(n.Config.BootstrapDNSSeeds) == 0 {
+		return
+	}
+
+	peers, err := n.resolveBootstrapSeeds()
+	if err != nil {
+		n.Log.Warn("couldn't resolve bootstrap peers from the DNS seeds: %s", err)
+		return
+	}
+
+	configured := map[string]bool{}
+	for _, peer := range n.Config.BootstrapPeers {
+		configured[peer.IP.String()] = true
+	}
+	for _, peer := range peers {
+		if !configured[peer.IP.String()] {
+			configured[peer.IP.String()] = true
+			n.Config.BootstrapPeers = append(n.Config.BootstrapPeers, peer)
+		}
+	}
+	n.Log.Info("resolved %d bootstrap peers from the DNS seeds", len(peers))
+}
+
+// refreshBootstrapSeeds adds the bootstrap peers listed by the DNS seeds that
+// weren't added yet as peers
+func (n *Node) refreshBootstrapSeeds() {
+	peers, err := n.resolveBootstrapSeeds()
+	if err != nil {
+		n.Log.Debug("couldn't refresh bootstrap peers from the DNS seeds: %s", err)
+		return
+	}
+
+	cErr := salticidae.NewError()
+	for _, peer := range peers {
+		ip := peer.IP.String()
+		if n.bootstrapIPs[ip] || peer.IP.Equal(n.Config.StakingIP) || n.ValidatorAPI.Banned(peer.IP) {
+			continue
+		}
+		addr := salticidae.NewNetAddrFromIPPortString(ip, true, &cErr)
+		if code := cErr.GetCode(); code != 0 {
+			n.Log.Debug("couldn't add bootstrap peer %s: %s", ip, salticidae.StrError(code))
+			continue
+		}
+		n.Log.Info("adding bootstrap peer %s from the DNS seeds", ip)
+		n.PeerNet.AddPeer(addr)
+		n.bootstrapIPs[ip] = true
+	}
+}
+
 // Dispatch starts the node's servers.
 // Returns when the node exits.
 func (n *Node) Dispatch() { n.EC.Dispatch() }
@@ -286,7 +452,47 @@ func (n *Node) Dispatch() { n.EC.Dispatch() }
  ******************************************************************************
  */
 
-func (n *Node) initDatabase() { n.DB = n.Config.DB }
+// initDatabase wraps the configured database so that its usage is recorded in
+// metrics
+// Assumes n.metricsGatherer is already set
+func (n *Node) initDatabase() error {
+	registry := prometheus.NewRegistry()
+	db, err := meterdb.New("", registry, n.Config.DB)
+	if err != nil {
+		return err
+	}
+	n.DB = db
+	if err := n.checkDatabaseVersion(); err != nil {
+		return err
+	}
+	return n.metricsGatherer.Register("gecko_db", registry)
+}
+
+// checkDatabaseVersion returns ErrDatabaseMigrationNeeded if the database was
+// written with a different layout than this node's. Databases written before
+// the version was recorded have this node's layout.
+func (n *Node) checkDatabaseVersion() error {
+	db := prefixdb.New([]byte("version"), n.DB)
+	versionBytes, err := db.Get(databaseVersionKey)
+	if err == database.ErrNotFound {
+		versionBytes = make([]byte, 4)
+		binary.BigEndian.PutUint32(versionBytes, databaseVersion)
+		return db.Put(databaseVersionKey, versionBytes)
+	}
+	if err != nil {
+		return err
+	}
+	if len(versionBytes) != 4 {
+		return fmt.Errorf("malformed database version %x", versionBytes)
+	}
+	switch version := binary.BigEndian.Uint32(versionBytes); {
+	case version < databaseVersion:
+		return fmt.Errorf("%w: database version %d must be migrated to version %d", ErrDatabaseMigrationNeeded, version, databaseVersion)
+	case version > databaseVersion:
+		return fmt.Errorf("%w: database version %d was written by a newer node than this node, which uses version %d", ErrDatabaseMigrationNeeded, version, databaseVersion)
+	}
+	return nil
+}
 
 // Initialize this node's ID
 // If staking is disabled, a node's ID is a hash of its IP
@@ -299,20 +505,11 @@ func (n *Node) initNodeID() error {
 		return nil
 	}
 
-	stakeCert, err := ioutil.ReadFile(n.Config.StakingCertFile)
+	nodeID, err := staking.NodeID(n.Config.StakingCertFile)
 	if err != nil {
-		return fmt.Errorf("problem reading staking certificate: %w", err)
-	}
-
-	block, _ := pem.Decode(stakeCert)
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return fmt.Errorf("problem parsing staking certificate: %w", err)
-	}
-	n.ID, err = ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
-	if err != nil {
-		return fmt.Errorf("problem deriving staker ID from certificate: %w", err)
+		return err
 	}
+	n.ID = nodeID
 	n.Log.Info("Set node's ID to %s", n.ID)
 	return nil
 }
@@ -321,14 +518,92 @@ func (n *Node) initNodeID() error {
 // AVM, EVM, Simple Payments DAG, Simple Payments Chain
 // The Platform VM is registered in initStaking because
 // its factory needs to reference n.chainManager, which is nil right now
-func (n *Node) initVMManager() {
+func (n *Node) initVMManager() error {
+	avaAssetID, err := genesis.AVAAssetID(n.Config.NetworkID)
+	if err != nil {
+		return err
+	}
+
+	fees := genesis.GetFees(n.Config.NetworkID)
+
+	// AVA is moved between the X-Chain and the C-Chain, if the genesis has
+	// them, through shared memory
+	xChainID, cChainID := ids.ID{}, ids.ID{}
+	if createAVMTx := genesis.VMGenesis(n.Config.NetworkID, avm.ID); createAVMTx != nil {
+		xChainID = createAVMTx.ID()
+	}
+	if createEVMTx := genesis.VMGenesis(n.Config.NetworkID, evm.ID); createEVMTx != nil {
+		cChainID = createEVMTx.ID()
+	}
+
 	n.vmManager = vms.NewManager(&n.APIServer, n.HTTPLog)
-	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{})
-	n.vmManager.RegisterVMFactory(evm.ID, &evm.Factory{})
+	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
+		AVA:           avaAssetID,
+		Platform:      platformvm.ChainID,
+		EVM:           cChainID,
+		MempoolSize:   n.Config.AVMMempoolSize,
+		TxFee:         fees.TxFee,
+		CreationTxFee: fees.CreationTxFee,
+	})
+	n.vmManager.RegisterVMFactory(evm.ID, &evm.Factory{
+		Config: evm.Config{
+			ChainID:     n.Config.EVMChainID,
+			GasLimit:    n.Config.EVMGasLimit,
+			MinGasPrice: n.Config.EVMMinGasPrice,
+		},
+		AVA:   avaAssetID,
+		AVM:   xChainID,
+		TxFee: fees.TxFee,
+	})
 	n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{TxFee: n.Config.AvaTxFee})
 	n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{})
 	n.vmManager.RegisterVMFactory(secp256k1fx.ID, &secp256k1fx.Factory{})
+	n.vmManager.RegisterVMFactory(nftfx.ID, &nftfx.Factory{})
 	n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{})
+	return n.registerPlugins()
+}
+
+// Register the VMs in the plugin directory. Each plugin runs a Snowman VM in
+// its own process and is named by the ID of its VM.
+func (n *Node) registerPlugins() error {
+	files, err := ioutil.ReadDir(n.Config.PluginDir)
+	if os.IsNotExist(err) {
+		n.Log.Debug("plugin directory %s doesn't exist", n.Config.PluginDir)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		vmID, err := ids.FromString(file.Name())
+		if err != nil {
+			n.Log.Warn("skipping plugin %s because its name isn't a VM ID", file.Name())
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join(n.Config.PluginDir, file.Name()))
+		if err != nil {
+			return err
+		}
+		if err := n.vmManager.RegisterVMFactory(vmID, &rpcchainvm.Factory{
+			Path:         path,
+			LogDirectory: filepath.Join(n.Config.LoggingConfig.Directory, "plugins"),
+		}); err != nil {
+			n.Log.Warn("skipping plugin %s: %s", file.Name(), err)
+			continue
+		}
+		// Chains of a plugin this node can't run fail to be created, so the
+		// plugin stays registered to report why
+		if _, err := n.vmManager.GetVMFactory(vmID); err != nil {
+			n.Log.Warn("registered plugin VM %s, but it can't be run: %s", vmID, err)
+			continue
+		}
+		n.Log.Info("registered plugin VM %s", vmID)
+	}
+	return nil
 }
 
 // Create the EventDispatcher used for hooking events
@@ -341,11 +616,18 @@ func (n *Node) initEventDispatcher() {
 	n.ConsensusDispatcher.Initialize(n.Log)
 }
 
-// Initializes the Platform chain.
-// Its genesis data specifies the other chains that should
-// be created.
-func (n *Node) initChains() {
-	n.Log.Info("initializing chains")
+// initPlatformVM registers the Platform VM, whose factory references
+// n.chainManager and n.vdrs
+func (n *Node) initPlatformVM() error {
+	avaAssetID, err := genesis.AVAAssetID(n.Config.NetworkID)
+	if err != nil {
+		return err
+	}
+	createAVMTx := genesis.VMGenesis(n.Config.NetworkID, avm.ID)
+	if createAVMTx == nil {
+		return errors.New("couldn't find the X-Chain in the genesis")
+	}
+	fees := genesis.GetFees(n.Config.NetworkID)
 
 	vdrs := n.vdrs
 	if !n.Config.EnableStaking {
@@ -359,8 +641,27 @@ func (n *Node) initChains() {
 		/*vmFactory=*/ &platformvm.Factory{
 			ChainManager: n.chainManager,
 			Validators:   vdrs,
+			AVA:          avaAssetID,
+			AVM:          createAVMTx.ID(),
+
+			MaxClockDrift: genesis.MaxClockDrift(n.Config.NetworkID),
+			TxFee:         fees.TxFee,
+			CreationTxFee: fees.CreationTxFee,
+			Staking:       genesis.GetStakingParameters(n.Config.NetworkID),
 		},
 	)
+	return nil
+}
+
+// Initializes the Platform chain.
+// Its genesis data specifies the other chains that should
+// be created.
+func (n *Node) initChains() error {
+	n.Log.Info("initializing chains")
+
+	if err := n.initPlatformVM(); err != nil {
+		return err
+	}
 
 	beacons := validators.NewSet()
 	for _, peer := range n.Config.BootstrapPeers {
@@ -371,35 +672,79 @@ func (n *Node) initChains() {
 
 	// Create the Platform Chain
 	n.chainManager.ForceCreateChain(chains.ChainParameters{
-		ID:            ids.Empty,
+		ID:            platformvm.ChainID,
 		GenesisData:   genesisBytes, // Specifies other chains to create
 		VMAlias:       platformvm.ID.String(),
 		CustomBeacons: beacons,
 	})
+	return nil
 }
 
 // initAPIServer initializes the server that handles HTTP calls
-func (n *Node) initAPIServer() {
+// Assumes n.metricsGatherer is already set
+func (n *Node) initAPIServer() error {
 	n.Log.Info("Initializing API server")
 
-	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort)
+	n.auth.Initialize(n.Config.APIRequireAuth, n.Config.APIAuthPassword)
+	// Calls that manage users, keys and the node itself require a token
+	n.auth.Restrict(
+		"keystore",
+		"admin",
+		"ipcs",
+		"platform.createAccount",
+		"platform.listAccounts",
+		"platform.sign",
+		"platform.exportAVA",
+		"platform.importAVA",
+	)
+	// Tokens are created and revoked with the auth password
+	n.auth.Exempt("admin.newToken", "admin.revokeToken")
+
+	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort, &n.auth, n.Config.HTTPAllowedOrigins, n.Config.APIMaxRequestSize, n.Config.APIMaxBatchSize)
+
+	registry := prometheus.NewRegistry()
+	if err := n.APIServer.LimitRate(n.Config.APIRateLimits, "", registry); err != nil {
+		return err
+	}
+	if err := n.metricsGatherer.Register("gecko_api", registry); err != nil {
+		return err
+	}
 
 	if n.Config.EnableHTTPS {
 		n.Log.Debug("Initializing API server with TLS Enabled")
 		go n.Log.RecoverAndPanic(func() {
-			if err := n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile); err != nil {
-				n.Log.Warn("API server initialization failed with %s, attempting to create insecure API server", err)
-				n.APIServer.Dispatch()
+			err := n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile, n.Config.HTTPSClientCAFile)
+			if err == nil || err == http.ErrServerClosed {
+				return
+			}
+			// Serving without TLS would bypass client certificate checks
+			if n.Config.HTTPSClientCAFile != "" {
+				n.Log.Error("API server initialization failed with %s", err)
+				return
 			}
+			n.Log.Warn("API server initialization failed with %s, attempting to create insecure API server", err)
+			n.APIServer.Dispatch()
 		})
 	} else {
 		n.Log.Debug("Initializing API server with TLS Disabled")
 		go n.Log.RecoverAndPanic(func() { n.APIServer.Dispatch() })
 	}
+	return nil
 }
 
 // Assumes n.DB, n.vdrs all initialized (non-nil)
 func (n *Node) initChainManager() {
+	// Every node validates the default subnet
+	validatedSubnets := ids.Set{}
+	validatedSubnets.Add(platformvm.DefaultSubnetID)
+	validatedSubnets.Union(n.Config.WhitelistedSubnets)
+
+	externalSender := sender.ExternalSender(&networking.VotingNet)
+	if n.Config.NetworkSimulation.Enabled() {
+		n.Log.Warn("simulating network conditions %+v for consensus messages. This should only be done in tests", n.Config.NetworkSimulation)
+		externalSender = netsim.New(externalSender, n.Config.NetworkSimulation, n.Config.NetworkSimulationSeed)
+	}
+
 	n.chainManager = chains.New(
 		n.Log,
 		n.LogFactory,
@@ -408,7 +753,7 @@ func (n *Node) initChainManager() {
 		n.ConsensusDispatcher,
 		n.DB,
 		n.Config.ConsensusRouter,
-		&networking.VotingNet,
+		externalSender,
 		n.Config.ConsensusParams,
 		n.vdrs,
 		n.ID,
@@ -416,11 +761,66 @@ func (n *Node) initChainManager() {
 		n.ValidatorAPI,
 		&n.APIServer,
 		&n.keystoreServer,
+		&n.sharedMemory,
+		n.metricsGatherer,
+		n.Config.StateRetention,
+		n.Config.ChainConfigs,
+		n.Config.ChainSettings,
+		n.Config.StateSyncEnabled,
+		n.tracer,
+		n.uptimes,
+		validatedSubnets,
+		&n.Config.NetworkTimeout,
 	)
 
 	n.chainManager.AddRegistrant(&n.APIServer)
 }
 
+// initTracer sets up the tracing of chains' consensus messages, if it's
+// enabled. Spans are logged to their own log.
+func (n *Node) initTracer() error {
+	if !n.Config.TraceMessages {
+		return nil
+	}
+	tracingLog, err := n.LogFactory.MakeSubdir("tracing")
+	if err != nil {
+		return err
+	}
+	n.tracer = tracing.NewTracer(n.ID, tracing.LogExporter{Log: tracingLog})
+	return nil
+}
+
+// initUptimes sets up the tracking of other nodes' uptimes. Uptimes are kept
+// for as long as a validator may stake.
+func (n *Node) initUptimes() {
+	maxStakeDuration := genesis.GetStakingParameters(n.Config.NetworkID).MaxStakeDuration
+	n.uptimes = uptime.NewManager(n.ID, prefixdb.New([]byte("uptime"), n.DB), maxStakeDuration)
+	n.uptimeFlusher = timer.NewRepeater(func() {
+		if err := n.uptimes.Flush(); err != nil {
+			n.Log.Error("failed to flush uptimes due to %s", err)
+		}
+	}, uptimeFlushFrequency)
+	go n.Log.RecoverAndPanic(n.uptimeFlusher.Dispatch)
+}
+
+// initBans loads the peers that were banned, which are kept across restarts
+func (n *Node) initBans() error {
+	list, err := bans.New(prefixdb.New([]byte("bans"), n.DB))
+	if err != nil {
+		return err
+	}
+	n.bans = list
+	return nil
+}
+
+// initSharedMemory initializes the memory that chains use to atomically move
+// state between each other
+func (n *Node) initSharedMemory() {
+	n.Log.Info("initializing SharedMemory")
+	sharedMemoryDB := prefixdb.New([]byte("shared memory"), n.DB)
+	n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
+}
+
 // initWallet initializes the Wallet service
 // Assumes n.APIServer is already set
 func (n *Node) initKeystoreAPI() {
@@ -433,33 +833,134 @@ func (n *Node) initKeystoreAPI() {
 	}
 }
 
+// initMetrics initializes the registry of the node's metrics and the gatherer
+// that the database, router and chains register their metrics with
+func (n *Node) initMetrics() {
+	registry, gatherer, handler := metrics.NewService()
+	n.Config.ConsensusParams.Metrics = registry
+	n.metricsGatherer = gatherer
+	n.metricsHandler = handler
+}
+
 // initMetricsAPI initializes the Metrics API
-// Assumes n.APIServer is already set
+// Assumes n.APIServer and n.metricsHandler are already set
 func (n *Node) initMetricsAPI() {
-	n.Log.Info("initializing Metrics API")
-	registry, handler := metrics.NewService()
 	if n.Config.MetricsAPIEnabled {
-		n.APIServer.AddRoute(handler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
+		n.Log.Info("initializing Metrics API")
+		n.APIServer.AddRoute(n.metricsHandler, &sync.RWMutex{}, "metrics", "", n.HTTPLog)
 	}
-	n.Config.ConsensusParams.Metrics = registry
 }
 
 // initAdminAPI initializes the Admin API service
-// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+// Assumes n.log, n.chainManager, n.ValidatorAPI and n.ipcs already initialized
 func (n *Node) initAdminAPI() {
 	if n.Config.AdminAPIEnabled {
 		n.Log.Info("initializing Admin API")
-		service := admin.NewService(n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), &n.APIServer)
+		service := admin.NewService(n.Config.NetworkID, n.Log, n.LogFactory, n.chainManager, n.ValidatorAPI.Connections(), n.ValidatorAPI, &n.APIServer, &n.auth, n.ipcs, n.effectiveConfig(), n.DB, n.Config.DBBackupper)
 		n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
 	}
 }
 
+// initInfoAPI initializes the Info API service
+// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+func (n *Node) initInfoAPI() {
+	if n.Config.InfoAPIEnabled {
+		n.Log.Info("initializing Info API")
+		n.infoService = info.New(n.Log, networking.CurrentVersion, n.ID, n.Config.NetworkID, n.chainManager, n.vmManager, n.ValidatorAPI.Connections(), n.ValidatorAPI, n.uptimes)
+		n.APIServer.AddRoute(n.infoService.Handler(), &sync.RWMutex{}, "info", "", n.HTTPLog)
+	}
+}
+
+// initEventsAPI initializes the websocket API that streams accepted decisions
+// Assumes n.DecisionDispatcher is already initialized
+func (n *Node) initEventsAPI() {
+	if n.Config.EventsAPIEnabled {
+		n.Log.Info("initializing Events API")
+		server := events.NewServer(n.Log)
+		n.Log.AssertNoError(n.DecisionDispatcher.Register("events", server))
+		handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: server}
+		n.APIServer.AddRoute(handler, &sync.RWMutex{}, "events", "", n.HTTPLog)
+	}
+}
+
+// initHealthAPI initializes the Health API service
+// Assumes n.DB, n.ValidatorAPI, n.DecisionDispatcher and n.chainManager already
+// initialized, and that no chains have been created yet
+func (n *Node) initHealthAPI() {
+	if !n.Config.HealthAPIEnabled {
+		return
+	}
+	n.Log.Info("initializing Health API")
+
+	monitor := health.NewChainMonitor()
+	n.chainManager.AddRegistrant(monitor)
+	n.Log.AssertNoError(n.DecisionDispatcher.Register("health", monitor))
+
+	service := health.NewHealth(n.Log)
+	peers := n.ValidatorAPI.Connections()
+	numPeers := func() int { return len(peers.Peers()) }
+	n.Log.AssertNoError(service.RegisterCheck("network", health.PeersCheck(numPeers, n.Config.HealthMinPeers)))
+	n.Log.AssertNoError(service.RegisterCheck("database", health.DatabaseCheck(prefixdb.New([]byte("health"), n.DB))))
+	n.Log.AssertNoError(service.RegisterCheck("lastAccepted", monitor.LastAcceptedCheck(n.Config.HealthMaxLastAccepted)))
+	n.Log.AssertNoError(service.RegisterCheck("bootstrapped", monitor.BootstrappedCheck(n.chainManager.IsBootstrapped)))
+	n.APIServer.AddRoute(service.Handler(), &sync.RWMutex{}, "health", "", n.HTTPLog)
+	n.healthService = service
+}
+
+// initIndexAPI initializes the indexer, which indexes the containers accepted
+// on each chain and serves the indexes
+// Assumes n.DB, n.APIServer, n.DecisionDispatcher, n.ConsensusDispatcher and
+// n.chainManager already initialized, and that no chains have been created yet
+func (n *Node) initIndexAPI() {
+	if !n.Config.IndexAPIEnabled {
+		return
+	}
+	n.Log.Info("initializing Index API")
+
+	indexer := indexer.NewIndexer(
+		n.Log,
+		prefixdb.New([]byte("index"), n.DB),
+		n.chainManager,
+		&n.APIServer,
+		n.DecisionDispatcher,
+		n.ConsensusDispatcher,
+	)
+	n.chainManager.AddRegistrant(indexer)
+}
+
+// initGRPCAPI serves the info, health, AVM and platform APIs over gRPC, and as
+// REST endpoints on the API server
+// Assumes n.APIServer, n.chainManager, n.infoService and n.healthService are
+// already set, and that no chains have been created yet
+func (n *Node) initGRPCAPI() error {
+	if !n.Config.GRPCAPIEnabled {
+		return nil
+	}
+	n.Log.Info("initializing gRPC API")
+
+	server, err := gateway.New(n.Log, n.chainManager, n.infoService, n.healthService)
+	if err != nil {
+		return err
+	}
+	n.gateway = server
+	n.chainManager.AddRegistrant(server)
+	n.APIServer.AddRoute(server.RESTHandler(), &sync.RWMutex{}, "rest", "/{path:.*}", n.HTTPLog)
+
+	go n.Log.RecoverAndPanic(func() {
+		if err := server.Dispatch(n.Config.GRPCPort); err != nil {
+			n.Log.Error("gRPC API server failed with %s", err)
+		}
+	})
+	return nil
+}
+
 // initIPCAPI initializes the IPC API service
 // Assumes n.log and n.chainManager already initialized
 func (n *Node) initIPCAPI() {
 	if n.Config.IPCEnabled {
 		n.Log.Info("initializing IPC API")
-		service := ipcs.NewService(n.Log, n.chainManager, n.DecisionDispatcher, &n.APIServer)
+		n.ipcs = ipcs.NewIPCs(n.Log, n.chainManager, n.DecisionDispatcher, n.Config.IPCPath)
+		service := ipcs.NewService(n.ipcs)
 		n.APIServer.AddRoute(service, &sync.RWMutex{}, "ipcs", "", n.HTTPLog)
 	}
 }
@@ -497,23 +998,43 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	}
 	n.HTTPLog = httpLog
 
-	n.initDatabase() // Set up the node's database
+	n.initMetrics() // Set up the node's metrics
+
+	if err = n.initDatabase(); err != nil { // Set up the node's database
+		return fmt.Errorf("problem initializing database: %w", err)
+	}
+	n.initSharedMemory() // Set up the shared memory
 
 	if err = n.initNodeID(); err != nil { // Derive this node's ID
 		return fmt.Errorf("problem initializing staker ID: %w", err)
 	}
+	if err = n.initTracer(); err != nil { // Set up message tracing
+		return fmt.Errorf("problem initializing message tracing: %w", err)
+	}
+	n.initUptimes()                     // Set up the tracking of uptimes
+	if err = n.initBans(); err != nil { // Load the banned peers
+		return fmt.Errorf("problem loading banned peers: %w", err)
+	}
 
 	// Start HTTP APIs
-	n.initAPIServer()   // Start the API Server
+	if err = n.initAPIServer(); err != nil { // Start the API Server
+		return fmt.Errorf("problem initializing API server: %w", err)
+	}
 	n.initKeystoreAPI() // Start the Keystore API
 	n.initMetricsAPI()  // Start the Metrics API
 
+	n.initBootstrapSeeds() // Resolve the bootstrap peers the DNS seeds list
+
 	// Start node-to-node consensus server
 	if err = n.initNetlib(); err != nil { // Set up all networking
 		return fmt.Errorf("problem initializing networking: %w", err)
 	}
-	n.initValidatorNet()    // Set up the validator handshake + authentication
-	n.initVMManager()       // Set up the vm manager
+	n.initValidatorNet() // Set up the validator handshake + authentication
+
+	if err = n.initVMManager(); err != nil { // Set up the vm manager
+		return fmt.Errorf("problem initializing the VM manager: %w", err)
+	}
+
 	n.initEventDispatcher() // Set up the event dipatcher
 	n.initChainManager()    // Set up the chain manager
 	n.initConsensusNet()    // Set up the main consensus network
@@ -523,18 +1044,69 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 		n.initClients() // Set up the client servers
 	}
 
-	n.initAdminAPI() // Start the Admin API
-	n.initIPCAPI()   // Start the IPC API
-	n.initAliases()  // Set up aliases
-	n.initChains()   // Start the Platform chain
+	n.initIPCAPI()    // Start the IPC API
+	n.initAdminAPI()  // Start the Admin API
+	n.initInfoAPI()   // Start the Info API
+	n.initEventsAPI() // Start the Events API
+	n.initHealthAPI() // Start the Health API
+	n.initIndexAPI()  // Start the Index API
+	n.initAliases()   // Set up aliases
+
+	if err = n.initGRPCAPI(); err != nil { // Start the gRPC API
+		return fmt.Errorf("problem initializing the gRPC API: %w", err)
+	}
+
+	if err = n.initChains(); err != nil { // Start the Platform chain
+		return fmt.Errorf("problem initializing chains: %w", err)
+	}
 
 	return nil
 }
 
-// Shutdown this node
-func (n *Node) Shutdown() {
+// Shutdown this node. The APIs stop accepting calls and finish the calls being
+// served, the node stops dialing and gossiping to peers, each chain handles
+// the messages queued for it before its VM is shut down, and then the
+// database is closed.
+// Returns an error if the chains don't shut down within the shutdown timeout.
+// The database is then left open, as chains may still be writing to it.
+func (n *Node) Shutdown() error {
 	n.Log.Info("shutting down the node")
+	ctx, cancel := context.WithTimeout(context.Background(), n.Config.ShutdownTimeout)
+	defer cancel()
+
+	if err := n.APIServer.Shutdown(ctx); err != nil {
+		n.Log.Warn("API server didn't finish serving calls: %s", err)
+	}
+	if n.gateway != nil {
+		n.gateway.Stop()
+	}
+
+	if n.seedRefresher != nil {
+		n.seedRefresher.Stop()
+	}
 	n.ValidatorAPI.Shutdown()
 	n.ConsensusAPI.Shutdown()
-	n.chainManager.Shutdown()
+
+	chainsDone := make(chan struct{})
+	go n.Log.RecoverAndPanic(func() {
+		n.chainManager.Shutdown()
+		close(chainsDone)
+	})
+	select {
+	case <-chainsDone:
+	case <-ctx.Done():
+		n.Log.Error("chains didn't shut down within %s", n.Config.ShutdownTimeout)
+		return errShutdownTimeout
+	}
+
+	n.uptimeFlusher.Stop()
+	if err := n.uptimes.Shutdown(); err != nil {
+		n.Log.Error("failed to flush uptimes due to %s", err)
+	}
+
+	if err := n.DB.Close(); err != nil {
+		return fmt.Errorf("couldn't close the database: %w", err)
+	}
+	n.Log.Info("node shut down")
+	return nil
 }
@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/chains/replay"
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+// Replay the containers of Config.ReplaySource through the VM of the chain
+// Config.ReplayChain, on the chain's state in this node's database. The node
+// doesn't connect to the network, serve its APIs or run consensus. The chain
+// must be created in the genesis.
+func (n *Node) Replay(Config *Config, logger logging.Logger, logFactory logging.Factory) (replay.Result, error) {
+	n.Log = logger
+	n.LogFactory = logFactory
+	n.Config = Config
+
+	httpLog, err := logFactory.MakeSubdir("http")
+	if err != nil {
+		return replay.Result{}, fmt.Errorf("problem initializing HTTP logger: %w", err)
+	}
+	n.HTTPLog = httpLog
+
+	n.initMetrics()
+	if err := n.initDatabase(); err != nil {
+		return replay.Result{}, fmt.Errorf("problem initializing database: %w", err)
+	}
+	n.initSharedMemory()
+	if err := n.initNodeID(); err != nil {
+		return replay.Result{}, fmt.Errorf("problem initializing staker ID: %w", err)
+	}
+
+	// VMs add their handlers to the API server, which isn't dispatched
+	n.auth.Initialize(false, "")
+	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPPort, &n.auth, n.Config.HTTPAllowedOrigins, n.Config.APIMaxRequestSize, n.Config.APIMaxBatchSize)
+	n.initKeystoreAPI()
+
+	// There's no network to learn the validators from
+	n.vdrs = validators.NewManager()
+	n.vdrs.PutValidatorSet(platformvm.DefaultSubnetID, validators.NewSet())
+
+	if err := n.initVMManager(); err != nil {
+		return replay.Result{}, fmt.Errorf("problem initializing the VM manager: %w", err)
+	}
+	n.initEventDispatcher()
+	n.initChainManager()
+	n.initAliases()
+	if err := n.initPlatformVM(); err != nil {
+		return replay.Result{}, fmt.Errorf("problem initializing the Platform VM: %w", err)
+	}
+
+	chainID, err := n.chainManager.Lookup(n.Config.ReplayChain)
+	if err != nil {
+		return replay.Result{}, fmt.Errorf("couldn't find chain %s: %w", n.Config.ReplayChain, err)
+	}
+	chain, err := n.genesisChain(chainID)
+	if err != nil {
+		return replay.Result{}, err
+	}
+
+	source, err := replay.NewSource(n.Config.ReplaySource)
+	if err != nil {
+		return replay.Result{}, fmt.Errorf("couldn't open %s: %w", n.Config.ReplaySource, err)
+	}
+	defer source.Close()
+
+	n.Log.Info("replaying chain %s from %s", chainID, n.Config.ReplaySource)
+	return n.chainManager.ReplayChain(chain, source)
+}
+
+// genesisChain returns the parameters of the chain [chainID], which is the
+// Platform Chain or a chain created in its genesis
+func (n *Node) genesisChain(chainID ids.ID) (chains.ChainParameters, error) {
+	if chainID.Equals(platformvm.ChainID) {
+		return chains.ChainParameters{
+			ID:          platformvm.ChainID,
+			GenesisData: genesis.Genesis(n.Config.NetworkID),
+			VMAlias:     platformvm.ID.String(),
+		}, nil
+	}
+
+	for _, tx := range genesis.Chains(n.Config.NetworkID) {
+		if !tx.ID().Equals(chainID) {
+			continue
+		}
+		chain := chains.ChainParameters{
+			ID:          tx.ID(),
+			SubnetID:    tx.SubnetID,
+			GenesisData: tx.GenesisData,
+			ConfigData:  tx.ConfigData,
+			VMAlias:     tx.VMID.String(),
+		}
+		for _, fxID := range tx.FxIDs {
+			chain.FxAliases = append(chain.FxAliases, fxID.String())
+		}
+		return chain, nil
+	}
+	return chains.ChainParameters{}, fmt.Errorf("chain %s isn't created in the genesis", chainID)
+}
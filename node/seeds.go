@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+// Seeds are DNS names that list bootstrap peers. A peer is listed either:
+// 1) In a TXT record of the seed, as ID@IP:port. A record may list several
+//    peers, separated by whitespace or commas.
+// 2) In an SRV record of the service "gecko" over "tcp" at the seed, such as
+//    _gecko._tcp.[seed]. The first label of the record's target is the peer's
+//    ID, and the target resolves to the peer's IPs.
+
+const (
+	seedService  = "gecko"
+	seedProtocol = "tcp"
+)
+
+var (
+	errBadSeedPeer = errors.New("expected a peer formatted as ID@IP:port")
+	errNoSeedPeers = errors.New("no bootstrap peers were resolved from the DNS seeds")
+)
+
+// Resolver looks up the DNS records of seeds. *net.Resolver implements it.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// ParseSeedPeer parses the peer [str], formatted as ID@IP:port
+func ParseSeedPeer(str string) (*Peer, error) {
+	parts := strings.Split(str, "@")
+	if len(parts) != 2 {
+		return nil, errBadSeedPeer
+	}
+	id, err := ids.ShortFromString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the ID of peer %q: %w", str, err)
+	}
+	ip, err := utils.ToIPDesc(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse the IP of peer %q: %w", str, err)
+	}
+	return &Peer{IP: ip, ID: id}, nil
+}
+
+// ResolveSeeds returns the peers listed by [seeds]. Records that don't list a
+// valid peer are skipped. Each peer is returned once, even if it's listed by
+// several seeds. Returns an error if no peer was resolved.
+func ResolveSeeds(ctx context.Context, resolver Resolver, seeds []string) ([]*Peer, error) {
+	peers := []*Peer(nil)
+	seen := map[string]bool{}
+	add := func(peer *Peer) {
+		key := peer.ID.String() + "@" + peer.IP.String()
+		if !seen[key] {
+			seen[key] = true
+			peers = append(peers, peer)
+		}
+	}
+
+	errs := []string(nil)
+	for _, seed := range seeds {
+		txtPeers, txtErr := resolveTXT(ctx, resolver, seed)
+		for _, peer := range txtPeers {
+			add(peer)
+		}
+		srvPeers, srvErr := resolveSRV(ctx, resolver, seed)
+		for _, peer := range srvPeers {
+			add(peer)
+		}
+		if len(txtPeers) == 0 && len(srvPeers) == 0 {
+			errs = append(errs, fmt.Sprintf("%s: TXT: %v, SRV: %v", seed, txtErr, srvErr))
+		}
+	}
+
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoSeedPeers, strings.Join(errs, "; "))
+	}
+	return peers, nil
+}
+
+// resolveTXT returns the peers listed in the TXT records of [seed]
+func resolveTXT(ctx context.Context, resolver Resolver, seed string) ([]*Peer, error) {
+	records, err := resolver.LookupTXT(ctx, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := []*Peer(nil)
+	for _, record := range records {
+		fields := strings.FieldsFunc(record, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		for _, field := range fields {
+			peer, err := ParseSeedPeer(field)
+			if err != nil {
+				continue
+			}
+			peers = append(peers, peer)
+		}
+	}
+	return peers, nil
+}
+
+// resolveSRV returns the peers listed in the SRV records of [seed]
+func resolveSRV(ctx context.Context, resolver Resolver, seed string) ([]*Peer, error) {
+	_, records, err := resolver.LookupSRV(ctx, seedService, seedProtocol, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := []*Peer(nil)
+	for _, record := range records {
+		label := strings.SplitN(record.Target, ".", 2)[0]
+		id, err := ids.ShortFromString(label)
+		if err != nil {
+			continue
+		}
+		addrs, err := resolver.LookupIPAddr(ctx, record.Target)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			peers = append(peers, &Peer{
+				IP: utils.IPDesc{
+					IP:   addr.IP,
+					Port: record.Port,
+				},
+				ID: id,
+			})
+		}
+	}
+	return peers, nil
+}
@@ -6,15 +6,18 @@ PREFIX="${PREFIX:-$(pwd)/build}"
 
 SRC_DIR="$(dirname "${BASH_SOURCE[0]}")"
 source "$SRC_DIR/env.sh"
+source "$SRC_DIR/versions.sh"
 
 GECKO_PKG=github.com/ava-labs/gecko
 GECKO_PATH="$GOPATH/src/$GECKO_PKG"
 if [[ -d "$GECKO_PATH/.git" ]]; then
     cd "$GECKO_PATH"
-    go get -t -v "./..."
+    go get -d -t -v "./..."
     cd -
 else
-    go get -t -v "$GECKO_PKG/..."
+    go get -d -t -v "$GECKO_PKG/..."
 fi
+pin_evm_deps "$GOPATH"
 go build -o "$PREFIX/ava" "$GECKO_PATH/main/"*.go
 go build -o "$PREFIX/xputtest" "$GECKO_PATH/xputtest/"*.go
+go build -o "$PREFIX/spexport" "$GECKO_PATH/spexport/"*.go
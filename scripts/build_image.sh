@@ -3,6 +3,7 @@ SRC_DIR="$(dirname "${BASH_SOURCE[0]}")"
 export GOPATH="$SRC_DIR/.build_image_gopath"
 WORKPREFIX="$GOPATH/src/github.com/ava-labs/"
 DOCKER="${DOCKER:-docker}"
+source "$SRC_DIR/versions.sh"
 keep_existing=0
 while getopts 'k' opt
 do
@@ -18,8 +19,9 @@ if [[ ! -d "$WORKPREFIX" ]]; then
     mkdir -p "$WORKPREFIX"
     git config --global credential.helper cache
     git clone https://github.com/ava-labs/coreth.git "$WORKPREFIX/coreth"
-    git clone --depth 1 https://github.com/ava-labs/go-ethereum.git "$WORKPREFIX/go-ethereum"
+    git clone https://github.com/ava-labs/go-ethereum.git "$WORKPREFIX/go-ethereum"
     git clone https://github.com/ava-labs/gecko.git "$WORKPREFIX/gecko"
 fi
+pin_evm_deps "$GOPATH"
 GECKO_COMMIT="$(git --git-dir="$WORKPREFIX/gecko/.git" rev-parse --short HEAD)"
 "${DOCKER}" build -t "gecko-$GECKO_COMMIT" "$SRC_DIR" -f "$SRC_DIR/Dockerfile.deploy"
@@ -0,0 +1,17 @@
+#!/bin/bash
+
+# The C-Chain needs a coreth that provides the OnFinalizeAndAssemble and
+# OnExtraStateChange hooks, and a go-ethereum whose blocks carry extra data in
+# their body. Bump these together.
+CORETH_VERSION="${CORETH_VERSION:-v0.2.0}"
+GO_ETHEREUM_VERSION="${GO_ETHEREUM_VERSION:-v1.9.3}"
+
+# pin_evm_deps checks out the pinned coreth and go-ethereum under the GOPATH
+# [1]
+pin_evm_deps() {
+    local prefix="$1/src/github.com/ava-labs"
+    git -C "$prefix/coreth" fetch -q --tags
+    git -C "$prefix/coreth" checkout -q "$CORETH_VERSION"
+    git -C "$prefix/go-ethereum" fetch -q --tags
+    git -C "$prefix/go-ethereum" checkout -q "$GO_ETHEREUM_VERSION"
+}
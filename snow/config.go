@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"strconv"
+	"time"
+)
+
+// FeaturePrefix prefixes the keys of feature flags. The feature [name] is
+// enabled if the key FeaturePrefix+[name] is set to true.
+const FeaturePrefix = "features."
+
+// Config is the key/value configuration a chain runs with. The getters return
+// the provided default if the key isn't set or its value doesn't parse as the
+// requested type. A nil Config has no keys set.
+type Config struct {
+	values map[string]string
+}
+
+// NewConfig returns a config holding [values]. Keys of later maps override
+// those of earlier ones, so node wide values can be given first and values
+// specific to the chain after them.
+func NewConfig(values ...map[string]string) *Config {
+	c := &Config{values: make(map[string]string)}
+	for _, vals := range values {
+		for key, val := range vals {
+			c.values[key] = val
+		}
+	}
+	return c
+}
+
+// Get returns the value of [key] and true, or false if [key] isn't set
+func (c *Config) Get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	val, ok := c.values[key]
+	return val, ok
+}
+
+// String returns the value of [key], or [def] if it isn't set
+func (c *Config) String(key string, def string) string {
+	if val, ok := c.Get(key); ok {
+		return val
+	}
+	return def
+}
+
+// Bool returns the value of [key] as a bool, or [def]
+func (c *Config) Bool(key string, def bool) bool {
+	if val, ok := c.Get(key); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// Int returns the value of [key] as an int, or [def]
+func (c *Config) Int(key string, def int) int {
+	if val, ok := c.Get(key); ok {
+		if i, err := strconv.Atoi(val); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// Uint64 returns the value of [key] as a uint64, or [def]
+func (c *Config) Uint64(key string, def uint64) uint64 {
+	if val, ok := c.Get(key); ok {
+		if u, err := strconv.ParseUint(val, 10, 64); err == nil {
+			return u
+		}
+	}
+	return def
+}
+
+// Float64 returns the value of [key] as a float64, or [def]
+func (c *Config) Float64(key string, def float64) float64 {
+	if val, ok := c.Get(key); ok {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// Duration returns the value of [key] as a duration, such as "1m30s", or [def]
+func (c *Config) Duration(key string, def time.Duration) time.Duration {
+	if val, ok := c.Get(key); ok {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// Enabled returns true if the feature flag [feature] is enabled. Features are
+// disabled unless enabled explicitly.
+func (c *Config) Enabled(feature string) bool { return c.Bool(FeaturePrefix+feature, false) }
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	config := NewConfig(
+		map[string]string{
+			"gossipSize":               "10",
+			"timeout":                  "1m",
+			FeaturePrefix + "batching": "true",
+			FeaturePrefix + "indexing": "true",
+		},
+		map[string]string{
+			"gossipSize":               "20",
+			FeaturePrefix + "indexing": "false",
+			"name":                     "x",
+		},
+	)
+
+	switch {
+	case config.Int("gossipSize", 0) != 20:
+		t.Fatalf("The chain's setting should override the node's")
+	case config.Duration("timeout", 0) != time.Minute:
+		t.Fatalf("Wrong duration")
+	case config.String("name", "") != "x":
+		t.Fatalf("Wrong string")
+	case config.Bool("name", true) != true:
+		t.Fatalf("A value that isn't a bool should give the default")
+	case config.Uint64("missing", 5) != 5:
+		t.Fatalf("A missing key should give the default")
+	case !config.Enabled("batching"):
+		t.Fatalf("Feature should have been enabled")
+	case config.Enabled("indexing"):
+		t.Fatalf("The chain should have disabled the feature")
+	case config.Enabled("unknown"):
+		t.Fatalf("Features should be disabled by default")
+	}
+}
+
+func TestNilConfig(t *testing.T) {
+	config := (*Config)(nil)
+	if _, ok := config.Get("key"); ok {
+		t.Fatalf("A nil config shouldn't have any keys set")
+	}
+	if config.Float64("key", 1.5) != 1.5 {
+		t.Fatalf("A nil config should give the default")
+	}
+	if config.Enabled("feature") {
+		t.Fatalf("A nil config shouldn't enable any features")
+	}
+}
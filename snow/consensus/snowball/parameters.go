@@ -14,6 +14,11 @@ type Parameters struct {
 	Namespace                         string
 	Metrics                           prometheus.Registerer
 	K, Alpha, BetaVirtuous, BetaRogue int
+
+	// The most polls a snowman engine keeps outstanding when it repolls. The
+	// engine adjusts how many it keeps, between 1 and ConcurrentPolls, to how
+	// quickly polls are answered. If 0, it keeps one.
+	ConcurrentPolls int
 }
 
 // Valid returns nil if the parameters describe a valid initialization.
@@ -27,6 +32,8 @@ func (p Parameters) Valid() error {
 		return fmt.Errorf("BetaVirtuous = %d: Fails the condition that: 0 < BetaVirtuous", p.BetaVirtuous)
 	case p.BetaRogue < p.BetaVirtuous:
 		return fmt.Errorf("BetaVirtuous = %d, BetaRogue = %d: Fails the condition that: BetaVirtuous <= BetaRogue", p.BetaVirtuous, p.BetaRogue)
+	case p.ConcurrentPolls < 0:
+		return fmt.Errorf("ConcurrentPolls = %d: Fails the condition that: 0 <= ConcurrentPolls", p.ConcurrentPolls)
 	default:
 		return nil
 	}
@@ -17,6 +17,9 @@ type TreeFactory struct{}
 func (TreeFactory) New() Consensus { return &Tree{} }
 
 // Tree implements the snowball interface by using a modified patricia tree.
+// A path of bits that no choice conflicts on is voted on by a single unary
+// node, rather than a node per bit, so choices that share long prefixes cost
+// as little memory and work per poll as choices that don't.
 type Tree struct {
 	// params contains all the configurations of a snowball instance
 	params Parameters
@@ -74,7 +77,7 @@ func (t *Tree) RecordPoll(votes ids.Bag) {
 
 	// If any of the bits differ from the preference in this prefix, the vote is
 	// for a rejected operation. So, we filter out these invalid votes.
-	filteredVotes := votes.Filter(0, decidedPrefix, t.Preference())
+	filteredVotes := filter(votes, 0, decidedPrefix, t.Preference())
 
 	// Now that the votes have been restricted to valid votes, pass them into
 	// the first snowball instance
@@ -122,6 +125,17 @@ func (t *Tree) String() string {
 	return strings.TrimSuffix(builder.String(), "\n")
 }
 
+// filter returns the votes in [votes] that match [id] in the bits
+// [start, end). The bits between nodes are often an empty range, such as
+// between a unary node and the binary node that splits its last bit, in which
+// case [votes] is returned rather than copied.
+func filter(votes ids.Bag, start, end int, id ids.ID) ids.Bag {
+	if start >= end {
+		return votes
+	}
+	return votes.Filter(start, end, id)
+}
+
 type node interface {
 	// Preference returns the preferred choice of this sub-tree
 	Preference() ids.ID
@@ -396,7 +410,7 @@ func (u *unaryNode) Add(newChoice ids.ID) node {
 
 func (u *unaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 	// This ensures that votes for rejected colors are dropped
-	votes = votes.Filter(u.decidedPrefix, u.commonPrefix, u.preference)
+	votes = filter(votes, u.decidedPrefix, u.commonPrefix, u.preference)
 
 	// If my parent didn't get enough votes previously, then neither did I
 	if reset {
@@ -410,7 +424,7 @@ func (u *unaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 
 		if u.child != nil {
 			decidedPrefix := u.child.DecidedPrefix()
-			filteredVotes := votes.Filter(u.commonPrefix, decidedPrefix, u.preference)
+			filteredVotes := filter(votes, u.commonPrefix, decidedPrefix, u.preference)
 			// If I'm now decided, return my child
 			if u.Finalized() {
 				return u.child.RecordPoll(filteredVotes, u.shouldReset)
@@ -510,8 +524,8 @@ func (b *binaryNode) RecordPoll(votes ids.Bag, reset bool) node {
 		if child := b.children[bit]; child != nil {
 			// The votes are filtered to ensure that they are votes that should
 			// count for the child
-			filteredVotes := prunedVotes.Filter(
-				b.bit+1, child.DecidedPrefix(), b.preferences[bit])
+			filteredVotes := filter(
+				prunedVotes, b.bit+1, child.DecidedPrefix(), b.preferences[bit])
 
 			if b.snowball.Finalized() {
 				// If we are decided here, that means we must have decided due
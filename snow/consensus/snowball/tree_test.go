@@ -199,6 +199,60 @@ func TestSnowballLastBinary(t *testing.T) {
 	}
 }
 
+// Test that choices that only differ in their last bits are voted on by a node
+// per conflicting bit, and a single node for the bits they share
+func TestSnowballLongSharedPrefix(t *testing.T) {
+	zero := ids.Empty
+	two := ids.NewID([32]byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04,
+	})
+	one := ids.NewID([32]byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80,
+	})
+
+	params := Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 2, BetaRogue: 2,
+	}
+	tree := Tree{}
+	tree.Initialize(params, zero)
+	tree.Add(two)
+	tree.Add(one)
+
+	expected := "SB(NumSuccessfulPolls = 0, Confidence = 0, Finalized = false) Bits = [0, 250)\n" +
+		"    SB(Preference = 0, NumSuccessfulPolls[0] = 0, NumSuccessfulPolls[1] = 0, SF = SF(Preference = 0, Confidence = 0, Finalized = false)) Bit = 250\n" +
+		"        SB(NumSuccessfulPolls = 0, Confidence = 0, Finalized = false) Bits = [251, 255)\n" +
+		"            SB(Preference = 0, NumSuccessfulPolls[0] = 0, NumSuccessfulPolls[1] = 0, SF = SF(Preference = 0, Confidence = 0, Finalized = false)) Bit = 255\n" +
+		"        SB(NumSuccessfulPolls = 0, Confidence = 0, Finalized = false) Bits = [251, 256)"
+	if str := tree.String(); expected != str {
+		t.Fatalf("Wrong string. Expected %s got %s", expected, str)
+	}
+
+	oneBag := ids.Bag{}
+	oneBag.Add(one)
+	tree.RecordPoll(oneBag)
+
+	if pref := tree.Preference(); !one.Equals(pref) {
+		t.Fatalf("Wrong preference. Expected %s got %s", one, pref)
+	} else if tree.Finalized() {
+		t.Fatalf("Finalized too early")
+	}
+
+	tree.RecordPoll(oneBag)
+
+	if pref := tree.Preference(); !one.Equals(pref) {
+		t.Fatalf("Wrong preference. Expected %s got %s", one, pref)
+	} else if !tree.Finalized() {
+		t.Fatalf("Finalized too late")
+	}
+}
+
 func TestSnowballTrinary(t *testing.T) {
 	params := Parameters{
 		Metrics: prometheus.NewRegistry(),
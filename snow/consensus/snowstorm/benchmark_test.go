@@ -9,6 +9,9 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 )
 
@@ -223,3 +226,102 @@ func BenchmarkMultiRogueInput(b *testing.B) {
 		)
 	}
 }
+
+/*
+ ******************************************************************************
+ ********************************* Rejections *********************************
+ ******************************************************************************
+ */
+
+// RejectionGraph returns a conflict graph where a poll of the returned votes
+// accepts a transaction and rejects its conflict. [numTxs] transactions depend
+// on the rejected transaction, in a chain if [chain] is true or each directly
+// otherwise.
+func RejectionGraph(numTxs int, chain bool, fact Factory) (Consensus, ids.Bag) {
+	graph := fact.New()
+	graph.Initialize(snow.DefaultContextTest(), snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 1,
+	})
+
+	accepted := &TestTx{
+		Identifier: ids.Empty.Prefix(0),
+		Stat:       choices.Processing,
+	}
+	accepted.Ins.Add(ids.Empty.Prefix(1))
+	rejected := &TestTx{
+		Identifier: ids.Empty.Prefix(2),
+		Stat:       choices.Processing,
+	}
+	rejected.Ins.Add(ids.Empty.Prefix(1))
+	graph.Add(accepted)
+	graph.Add(rejected)
+
+	var dependency Tx = rejected
+	for i := 0; i < numTxs; i++ {
+		tx := &TestTx{
+			Identifier: ids.Empty.Prefix(3, uint64(i)),
+			Deps:       []Tx{dependency},
+			Stat:       choices.Processing,
+		}
+		tx.Ins.Add(ids.Empty.Prefix(4, uint64(i)))
+		graph.Add(tx)
+		if chain {
+			dependency = tx
+		}
+	}
+
+	votes := ids.Bag{}
+	votes.Add(accepted.ID())
+	return graph, votes
+}
+
+// The poll that rejects a transaction only marks its dependents, however many
+// there are
+
+func BenchmarkRejectChainDirected(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		graph, votes := RejectionGraph(
+			/*numTxs=*/ 100000,
+			/*chain=*/ true,
+			/*fact=*/ DirectedFactory{},
+		)
+		b.StartTimer()
+
+		graph.RecordPoll(votes)
+	}
+}
+
+func BenchmarkRejectFanOutDirected(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		graph, votes := RejectionGraph(
+			/*numTxs=*/ 100000,
+			/*chain=*/ false,
+			/*fact=*/ DirectedFactory{},
+		)
+		b.StartTimer()
+
+		graph.RecordPoll(votes)
+	}
+}
+
+// Rejecting all the dependents, over as many polls as it takes
+
+func BenchmarkRejectAllChainDirected(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		graph, votes := RejectionGraph(
+			/*numTxs=*/ 100000,
+			/*chain=*/ true,
+			/*fact=*/ DirectedFactory{},
+		)
+		b.StartTimer()
+
+		graph.RecordPoll(votes)
+		for !graph.Quiesce() {
+			graph.RecordPoll(ids.Bag{})
+		}
+	}
+}
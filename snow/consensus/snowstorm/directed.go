@@ -13,11 +13,20 @@ import (
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
 )
 
+// MaxDependentRejections is the most transactions rejected per poll because a
+// transaction they depend on was rejected. Rejecting a transaction only marks
+// its dependents, which are rejected over the following polls, so rejecting a
+// transaction with many descendants doesn't hold up the poll that rejected it.
+// Until a marked transaction is rejected, it stays in the graph but can't be
+// accepted.
+const MaxDependentRejections = 1024
+
 // DirectedFactory implements Factory by returning a directed struct
 type DirectedFactory struct{}
 
@@ -55,6 +64,10 @@ type Directed struct {
 	// Keep track of whether dependencies have been accepted or rejected
 	pendingAccept, pendingReject events.Blocker
 
+	// IDs of the transactions marked for rejection, oldest first, because a
+	// transaction they depend on was rejected
+	rejecting []ids.ID
+
 	// Number of times RecordPoll has been called
 	currentVote int
 }
@@ -234,7 +247,12 @@ func (dg *Directed) Add(tx Tx) {
 		fn: fn,
 	}
 	for _, dependency := range tx.Dependencies() {
-		if !dependency.Status().Decided() {
+		switch status := dependency.Status(); {
+		case status == choices.Rejected:
+			// The dependency's dependents may still be marked for rejection
+			dg.reject(id)
+			return
+		case !status.Decided():
 			toReject.deps.Add(dependency.ID())
 		}
 	}
@@ -289,13 +307,16 @@ func (dg *Directed) RecordPoll(votes ids.Bag) {
 			dg.redirectEdges(fn)
 		}
 	}
+
+	dg.rejectDependents()
 }
 
 // Quiesce implements the Consensus interface
 func (dg *Directed) Quiesce() bool {
 	numVirtuous := dg.virtuousVoting.Len()
-	dg.ctx.Log.Verbo("Conflict graph has %d voting virtuous transactions and %d transactions", numVirtuous, len(dg.nodes))
-	return numVirtuous == 0
+	dg.ctx.Log.Verbo("Conflict graph has %d voting virtuous transactions, %d transactions and %d transactions marked for rejection", numVirtuous, len(dg.nodes), len(dg.rejecting))
+	// Polls continue until the transactions marked for rejection are rejected
+	return numVirtuous == 0 && len(dg.rejecting) == 0
 }
 
 // Finalized implements the Consensus interface
@@ -339,6 +360,12 @@ func (dg *Directed) String() string {
 }
 
 func (dg *Directed) deferAcceptance(fn *flatNode) {
+	for _, dependency := range fn.tx.Dependencies() {
+		if dependency.Status() == choices.Rejected {
+			// This transaction is marked for rejection
+			return
+		}
+	}
 	fn.pendingAccept = true
 
 	toAccept := &directedAccepter{
@@ -358,7 +385,12 @@ func (dg *Directed) deferAcceptance(fn *flatNode) {
 func (dg *Directed) reject(ids ...ids.ID) {
 	for _, conflict := range ids {
 		conflictKey := conflict.Key()
-		conf := dg.nodes[conflictKey]
+		conf, exists := dg.nodes[conflictKey]
+		if !exists {
+			// A transaction marked for rejection may have already been
+			// rejected as a conflict of an accepted transaction
+			continue
+		}
 		delete(dg.nodes, conflictKey)
 
 		if conf.rogue {
@@ -367,6 +399,8 @@ func (dg *Directed) reject(ids ...ids.ID) {
 			dg.numProcessingVirtuous.Dec()
 		}
 
+		dg.virtuous.Remove(conflict)
+		dg.virtuousVoting.Remove(conflict)
 		dg.preferences.Remove(conflict)
 
 		// remove the edge between this node and all its neighbors
@@ -382,6 +416,20 @@ func (dg *Directed) reject(ids ...ids.ID) {
 	}
 }
 
+// rejectDependents rejects up to MaxDependentRejections of the transactions
+// marked for rejection, oldest first. Their dependents are marked in turn.
+func (dg *Directed) rejectDependents() {
+	for i := 0; i < MaxDependentRejections && len(dg.rejecting) > 0; i++ {
+		txID := dg.rejecting[0]
+		dg.rejecting[0] = ids.Empty
+		dg.rejecting = dg.rejecting[1:]
+		dg.reject(txID)
+	}
+	if len(dg.rejecting) == 0 {
+		dg.rejecting = nil
+	}
+}
+
 func (dg *Directed) redirectEdges(fn *flatNode) {
 	for _, conflictID := range fn.outs.List() {
 		dg.redirectEdge(fn, conflictID)
@@ -493,7 +541,8 @@ func (r *directedRejector) Fulfill(id ids.ID) {
 		return
 	}
 	r.rejected = true
-	r.dg.reject(r.fn.tx.ID())
+	// Rejected lazily, by rejectDependents
+	r.dg.rejecting = append(r.dg.rejecting, r.fn.tx.ID())
 }
 
 func (*directedRejector) Abandon(id ids.ID) {}
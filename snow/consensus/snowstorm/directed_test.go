@@ -5,6 +5,9 @@ package snowstorm
 
 import (
 	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 )
 
 func TestDirectedParams(t *testing.T) { ParamsTest(t, DirectedFactory{}) }
@@ -38,3 +41,34 @@ func TestDirectedVirtuousDependsOnRogue(t *testing.T) {
 }
 
 func TestDirectedString(t *testing.T) { StringTest(t, DirectedFactory{}, "DG") }
+
+// Test that the dependents of a rejected transaction are rejected over several
+// polls, and that polls continue until they all are
+func TestDirectedLazyRejection(t *testing.T) {
+	graph, votes := RejectionGraph(2*MaxDependentRejections, true, DirectedFactory{})
+	dg := graph.(*Directed)
+
+	graph.RecordPoll(votes)
+
+	if graph.Quiesce() {
+		t.Fatalf("Shouldn't quiesce while transactions are marked for rejection")
+	} else if numTxs := len(dg.nodes); numTxs != MaxDependentRejections {
+		t.Fatalf("Should have left %d transactions, but left %d", MaxDependentRejections, numTxs)
+	}
+
+	// The first transaction left depends on a rejected transaction, so it can't
+	// be accepted
+	markedID := ids.Empty.Prefix(3, uint64(MaxDependentRejections))
+	marked := dg.nodes[markedID.Key()].tx
+	markedVotes := ids.Bag{}
+	markedVotes.Add(markedID)
+	graph.RecordPoll(markedVotes)
+
+	if marked.Status() != choices.Rejected {
+		t.Fatalf("Wrong status. %s should be %s", markedID, choices.Rejected)
+	} else if !graph.Quiesce() {
+		t.Fatalf("Should have quiesced once the dependents were rejected")
+	} else if !graph.Finalized() {
+		t.Fatalf("Should have finalized once the dependents were rejected")
+	}
+}
@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/tracing"
 	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -22,6 +25,19 @@ type Callable interface {
 // Keystore ...
 type Keystore interface {
 	GetDatabase(username, password string) (database.Database, error)
+
+	// GetSharedDatabase returns the user's database that every chain shares
+	GetSharedDatabase(username, password string) (database.Database, error)
+
+	// NewKey returns a new key of the user. If the user was created from a
+	// mnemonic, the key is the next one derived from its seed for this chain.
+	NewKey(username, password string) (*crypto.PrivateKeySECP256K1R, error)
+}
+
+// SharedMemory ...
+type SharedMemory interface {
+	GetDatabase(id ids.ID) database.Database
+	ReleaseDatabase(id ids.ID)
 }
 
 // AliasLookup ...
@@ -30,13 +46,31 @@ type AliasLookup interface {
 	PrimaryAlias(id ids.ID) (string, error)
 }
 
+// Uptimes ...
+type Uptimes interface {
+	// Uptime returns the fraction of the time since [start] that this node
+	// was running that [nodeID] was connected to it
+	Uptime(nodeID ids.ShortID, start time.Time) (float64, error)
+}
+
 // Context is information about the current execution.
 // [NetworkID] is the ID of the network this context exists within.
 // [ChainID] is the ID of the chain this context exists within.
+// [SubnetID] is the ID of the subnet that validates this chain.
 // [NodeID] is the ID of this node
+// [StateRetention] is how long state the VM marks as mortal is kept before
+// it's pruned. If 0, state is never pruned.
+// [UpgradeBytes] is the upgrade file an operator gave this chain. It's nil if
+// none was given.
+// [Tracer] traces the messages of this chain. It's nil if they aren't traced.
+// [Uptimes] is the uptime of other nodes, as observed by this node. It's nil if
+// uptimes aren't tracked.
+// [Config] is the key/value configuration and feature flags an operator gave
+// this chain. It may be nil, in which case no keys are set.
 type Context struct {
 	NetworkID           uint32
 	ChainID             ids.ID
+	SubnetID            ids.ID
 	NodeID              ids.ShortID
 	Log                 logging.Logger
 	DecisionDispatcher  *triggers.EventDispatcher
@@ -44,7 +78,13 @@ type Context struct {
 	Lock                sync.RWMutex
 	HTTP                Callable
 	Keystore            Keystore
+	SharedMemory        SharedMemory
 	BCLookup            AliasLookup
+	StateRetention      time.Duration
+	UpgradeBytes        []byte
+	Tracer              *tracing.Tracer
+	Uptimes             Uptimes
+	Config              *Config
 }
 
 // DefaultContextTest ...
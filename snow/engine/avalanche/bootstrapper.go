@@ -23,6 +23,8 @@ type BootstrapConfig struct {
 
 	State State
 	VM    DAGVM
+
+	Bootstrapped func()
 }
 
 type bootstrapper struct {
@@ -40,12 +42,14 @@ func (b *bootstrapper) Initialize(config BootstrapConfig) {
 	b.BootstrapConfig = config
 
 	b.VtxBlocked.SetParser(&vtxParser{
+		ctx:         config.Context,
 		numAccepted: b.numBootstrappedVtx,
 		numDropped:  b.numDroppedVtx,
 		state:       b.State,
 	})
 
 	b.TxBlocked.SetParser(&txParser{
+		ctx:         config.Context,
 		numAccepted: b.numBootstrappedTx,
 		numDropped:  b.numDroppedTx,
 		vm:          b.VM,
@@ -197,6 +201,10 @@ func (b *bootstrapper) finish() {
 	// Start consensus
 	b.onFinished()
 	b.finished = true
+
+	if b.Bootstrapped != nil {
+		b.Bootstrapped()
+	}
 }
 
 func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge) {
@@ -4,7 +4,10 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 )
 
@@ -13,6 +16,7 @@ type issuer struct {
 	vtx               avalanche.Vertex
 	issued, abandoned bool
 	vtxDeps, txDeps   ids.Set
+	start             time.Time // When the engine learned of the vertex
 }
 
 func (i *issuer) FulfillVtx(id ids.ID) {
@@ -28,7 +32,7 @@ func (i *issuer) FulfillTx(id ids.ID) {
 func (i *issuer) Abandon() {
 	if !i.abandoned {
 		vtxID := i.vtx.ID()
-		i.t.pending.Remove(vtxID)
+		delete(i.t.pending, vtxID.Key())
 		i.abandoned = true
 
 		i.t.vtxBlocked.Abandon(vtxID)
@@ -42,19 +46,18 @@ func (i *issuer) Update() {
 	i.issued = true
 
 	vtxID := i.vtx.ID()
-	i.t.pending.Remove(vtxID)
+	delete(i.t.pending, vtxID.Key())
 
-	for _, tx := range i.vtx.Txs() {
-		if err := tx.Verify(); err != nil {
-			i.t.Config.Context.Log.Debug("Transaction failed verification due to %s, dropping vertex", err)
-			i.t.vtxBlocked.Abandon(vtxID)
-			return
-		}
+	if err := i.t.verify(i.vtx); err != nil {
+		i.t.Config.Context.Log.Debug("Transaction failed verification due to %s, dropping vertex", err)
+		i.t.vtxBlocked.Abandon(vtxID)
+		return
 	}
 
 	i.t.Config.Context.Log.Verbo("Adding vertex to consensus:\n%s", i.vtx)
 
 	i.t.Consensus.Add(i.vtx)
+	i.t.issued(i.vtx, i.start)
 
 	p := i.t.Consensus.Parameters()
 	vdrs := i.t.Config.Validators.Sample(p.K) // Validators to sample
@@ -77,6 +80,17 @@ func (i *issuer) Update() {
 	}
 }
 
+// orphaned returns true if the vertex can't be issued, because a parent was
+// rejected, or if it has been blocked for longer than PendingTTL at [now]
+func (i *issuer) orphaned(now time.Time) bool {
+	for _, parent := range i.vtx.Parents() {
+		if parent.Status() == choices.Rejected {
+			return true
+		}
+	}
+	return now.Sub(i.start) >= PendingTTL
+}
+
 type vtxIssuer struct{ i *issuer }
 
 func (vi *vtxIssuer) Dependencies() ids.Set { return vi.i.vtxDeps }
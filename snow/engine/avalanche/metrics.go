@@ -9,12 +9,31 @@ import (
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
+// Buckets of the durations of the stages of handling a vertex, in nanoseconds,
+// from 10µs to about 11 minutes
+var durationBuckets = prometheus.ExponentialBuckets(10000, 4, 14)
+
+func newDuration(namespace, name, help string) prometheus.Histogram {
+	return prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   durationBuckets,
+	})
+}
+
 type metrics struct {
 	numPendingRequests, numBlockedVtx, numBlockedTx prometheus.Gauge
 	numBootstrappedVtx, numDroppedVtx,
 	numBootstrappedTx, numDroppedTx prometheus.Counter
 
 	numPolls, numVtxRequests, numTxRequests, numPendingVtx prometheus.Gauge
+	numCollectedVtx                                        prometheus.Counter
+
+	// Durations of the stages of handling a vertex. Each chain's metrics are
+	// registered under the chain's namespace, so they're per chain.
+	parseDuration, verifyDuration, issueDuration,
+	pollDuration, acceptDuration prometheus.Histogram
 }
 
 // Initialize implements the Engine interface
@@ -85,6 +104,22 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "av_blocked_vts",
 			Help:      "Number of blocked vertices",
 		})
+	m.numCollectedVtx = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "av_collected_vts",
+			Help:      "Number of blocked vertices dropped because they couldn't be issued",
+		})
+	m.parseDuration = newDuration(namespace, "av_parse_duration",
+		"Duration of parsing a vertex, in nanoseconds")
+	m.verifyDuration = newDuration(namespace, "av_verify_duration",
+		"Duration of verifying the transactions of a vertex, in nanoseconds")
+	m.issueDuration = newDuration(namespace, "av_issue_duration",
+		"Time from learning of a vertex until it was issued into consensus, in nanoseconds. This includes fetching its ancestors")
+	m.pollDuration = newDuration(namespace, "av_poll_duration",
+		"Time from sending a poll until it finished, in nanoseconds")
+	m.acceptDuration = newDuration(namespace, "av_accept_duration",
+		"Time from issuing a vertex into consensus until it was accepted, in nanoseconds")
 
 	if err := registerer.Register(m.numPendingRequests); err != nil {
 		log.Error("Failed to register av_bs_vtx_requests statistics due to %s", err)
@@ -119,4 +154,22 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 	if err := registerer.Register(m.numPendingVtx); err != nil {
 		log.Error("Failed to register av_blocked_vts statistics due to %s", err)
 	}
+	if err := registerer.Register(m.numCollectedVtx); err != nil {
+		log.Error("Failed to register av_collected_vts statistics due to %s", err)
+	}
+	if err := registerer.Register(m.parseDuration); err != nil {
+		log.Error("Failed to register av_parse_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.verifyDuration); err != nil {
+		log.Error("Failed to register av_verify_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.issueDuration); err != nil {
+		log.Error("Failed to register av_issue_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.pollDuration); err != nil {
+		log.Error("Failed to register av_poll_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.acceptDuration); err != nil {
+		log.Error("Failed to register av_accept_duration statistics due to %s", err)
+	}
 }
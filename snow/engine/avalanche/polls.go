@@ -6,11 +6,13 @@ package avalanche
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // TODO: There is a conservative early termination case that doesn't require dag
@@ -32,7 +34,9 @@ import (
 type polls struct {
 	log      logging.Logger
 	numPolls prometheus.Gauge
+	duration prometheus.Histogram // Observes how long finished polls took
 	m        map[uint32]poll
+	clock    timer.Clock
 }
 
 // Add to the current set of polls
@@ -42,6 +46,7 @@ func (p *polls) Add(requestID uint32, numPolled int) bool {
 	poll, exists := p.m[requestID]
 	if !exists {
 		poll.numPending = numPolled
+		poll.start = p.clock.Time()
 		p.m[requestID] = poll
 
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
@@ -64,6 +69,9 @@ func (p *polls) Vote(requestID uint32, vdr ids.ShortID, votes []ids.ID) (ids.Uni
 		p.log.Verbo("Poll is finished")
 		delete(p.m, requestID)
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		if p.duration != nil {
+			p.duration.Observe(float64(p.clock.Time().Sub(poll.start)))
+		}
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
@@ -85,6 +93,7 @@ func (p *polls) String() string {
 type poll struct {
 	votes      ids.UniqueBag
 	numPending int
+	start      time.Time // When the poll was sent
 }
 
 // Vote registers a vote for this poll
@@ -4,14 +4,28 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// PendingTTL is how long a vertex may be blocked from being issued into
+	// consensus, waiting on its ancestry or transactions, before it's dropped
+	PendingTTL = 5 * time.Minute
+
+	// CollectFrequency is how often the blocked vertices are checked for ones
+	// to drop
+	CollectFrequency = 30 * time.Second
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -24,15 +38,31 @@ type Transitive struct {
 
 	// vtxReqs prevents asking validators for the same vertex
 	// missingTxs tracks transaction that are missing
-	vtxReqs, missingTxs, pending ids.Set
+	vtxReqs, missingTxs ids.Set
+
+	// Vertices that are blocked from being issued into consensus, by ID
+	pending map[[32]byte]*issuer
+	// When the blocked vertices were last checked for ones to drop
+	lastCollected time.Time
 
 	// vtxBlocked tracks operations that are blocked on vertices
 	// txBlocked tracks operations that are blocked on transactions
 	vtxBlocked, txBlocked events.Blocker
 
+	// When each vertex that's processing in consensus was issued into it, to
+	// observe how long vertices take to be accepted
+	processing map[[32]byte]processingVertex
+	clock      timer.Clock
+
 	bootstrapped bool
 }
 
+// processingVertex is a vertex that's processing in consensus
+type processingVertex struct {
+	vtx    avalanche.Vertex
+	issued time.Time
+}
+
 // Initialize implements the Engine interface
 func (t *Transitive) Initialize(config Config) {
 	config.Context.Log.Info("Initializing Avalanche consensus")
@@ -45,7 +75,11 @@ func (t *Transitive) Initialize(config Config) {
 
 	t.polls.log = config.Context.Log
 	t.polls.numPolls = t.numPolls
+	t.polls.duration = t.pollDuration
 	t.polls.m = make(map[uint32]poll)
+
+	t.processing = make(map[[32]byte]processingVertex)
+	t.pending = make(map[[32]byte]*issuer)
 }
 
 func (t *Transitive) finishBootstrapping() {
@@ -88,7 +122,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		return
 	}
 
-	vtx, err := t.Config.State.ParseVertex(vtxBytes)
+	vtx, err := t.parseVertex(vtxBytes)
 	if err != nil {
 		t.Config.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
 			err,
@@ -97,6 +131,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 		return
 	}
 	t.insertFrom(vdr, vtx)
+	t.collect()
 }
 
 // GetFailed implements the Engine interface
@@ -106,7 +141,7 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 		return
 	}
 
-	t.pending.Remove(vtxID)
+	delete(t.pending, vtxID.Key())
 	t.vtxBlocked.Abandon(vtxID)
 	t.vtxReqs.Remove(vtxID)
 
@@ -120,7 +155,7 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID)
 	// Track performance statistics
 	t.numVtxRequests.Set(float64(t.vtxReqs.Len()))
 	t.numTxRequests.Set(float64(t.missingTxs.Len()))
-	t.numBlockedVtx.Set(float64(t.pending.Len()))
+	t.numBlockedVtx.Set(float64(len(t.pending)))
 }
 
 // PullQuery implements the Engine interface
@@ -176,6 +211,7 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 	}
 
 	t.vtxBlocked.Register(v)
+	t.collect()
 }
 
 // QueryFailed implements the Engine interface
@@ -183,6 +219,22 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) {
 	t.Chits(vdr, requestID, ids.Set{})
 }
 
+// AppGossip implements the Engine interface
+func (t *Transitive) AppGossip(vdr ids.ShortID, msg []byte) {
+	if !t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping AppGossip due to bootstrapping")
+		return
+	}
+
+	appVM, ok := t.Config.VM.(common.AppVM)
+	if !ok {
+		return
+	}
+	if err := appVM.AppGossip(vdr, msg); err != nil {
+		t.Config.Context.Log.Debug("AppGossip from %s failed due to %s", vdr, err)
+	}
+}
+
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) {
 	if !t.bootstrapped {
@@ -195,6 +247,7 @@ func (t *Transitive) Notify(msg common.Message) {
 		txs := t.Config.VM.PendingTxs()
 		t.batch(txs, false /*=force*/, false /*=empty*/)
 	}
+	t.collect()
 }
 
 func (t *Transitive) repoll() {
@@ -221,7 +274,7 @@ func (t *Transitive) insertFrom(vdr ids.ShortID, vtx avalanche.Vertex) bool {
 		if t.Consensus.VertexIssued(vtx) {
 			continue
 		}
-		if t.pending.Contains(vtx.ID()) {
+		if _, blocked := t.pending[vtx.ID().Key()]; blocked {
 			issued = false
 			continue
 		}
@@ -243,14 +296,15 @@ func (t *Transitive) insertFrom(vdr ids.ShortID, vtx avalanche.Vertex) bool {
 func (t *Transitive) insert(vtx avalanche.Vertex) {
 	vtxID := vtx.ID()
 
-	t.pending.Add(vtxID)
-	t.vtxReqs.Remove(vtxID)
-
 	i := &issuer{
-		t:   t,
-		vtx: vtx,
+		t:     t,
+		vtx:   vtx,
+		start: t.clock.Time(),
 	}
 
+	t.pending[vtxID.Key()] = i
+	t.vtxReqs.Remove(vtxID)
+
 	for _, parent := range vtx.Parents() {
 		if !t.Consensus.VertexIssued(parent) {
 			i.vtxDeps.Add(parent.ID())
@@ -289,7 +343,52 @@ func (t *Transitive) insert(vtx avalanche.Vertex) {
 	// Track performance statistics
 	t.numVtxRequests.Set(float64(t.vtxReqs.Len()))
 	t.numTxRequests.Set(float64(t.missingTxs.Len()))
-	t.numBlockedVtx.Set(float64(t.pending.Len()))
+	t.numBlockedVtx.Set(float64(len(t.pending)))
+}
+
+// collect drops the blocked vertices that can't be issued, because a parent was
+// rejected, or that have been blocked for longer than PendingTTL, along with
+// the vertices blocked on them. Their transactions that are still valid are
+// batched into new vertices. Does nothing if the blocked vertices were checked
+// less than CollectFrequency ago.
+func (t *Transitive) collect() {
+	now := t.clock.Time()
+	if now.Sub(t.lastCollected) < CollectFrequency {
+		return
+	}
+	t.lastCollected = now
+
+	blocked := make([]*issuer, 0, len(t.pending))
+	for _, i := range t.pending {
+		blocked = append(blocked, i)
+	}
+	for _, i := range blocked {
+		if !i.abandoned && i.orphaned(now) {
+			i.Abandon()
+		}
+	}
+
+	numDropped := 0
+	txs := []snowstorm.Tx(nil)
+	for _, i := range blocked {
+		if !i.abandoned {
+			continue
+		}
+		numDropped++
+		for _, tx := range i.vtx.Txs() {
+			if err := tx.Verify(); err == nil {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	if numDropped == 0 {
+		return
+	}
+
+	t.Config.Context.Log.Debug("Dropped %d blocked vertices, re-issuing up to %d of their transactions", numDropped, len(txs))
+	t.numCollectedVtx.Add(float64(numDropped))
+	t.numBlockedVtx.Set(float64(len(t.pending)))
+	t.batch(txs, false /*=force*/, false /*=empty*/)
 }
 
 func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
@@ -308,8 +407,9 @@ func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) {
 			overlaps = false
 		}
 
-		// Force allows for a conflict to be issued
-		if txID := tx.ID(); !overlaps && !issuedTxs.Contains(txID) && (force || (t.Consensus.IsVirtuous(tx))) && !tx.Status().Decided() {
+		// Force allows for a conflict to be issued. Without force, a tx that is
+		// already being processed isn't issued again.
+		if txID := tx.ID(); !overlaps && !issuedTxs.Contains(txID) && (force || (t.Consensus.IsVirtuous(tx) && !t.Consensus.TxIssued(tx))) && !tx.Status().Decided() {
 			batch = append(batch, tx)
 			issuedTxs.Add(txID)
 			consumed.Union(inputs)
@@ -351,3 +451,51 @@ func (t *Transitive) sendRequest(vdr ids.ShortID, vtxID ids.ID) {
 	t.RequestID++
 	t.Config.Sender.Get(vdr, t.RequestID, vtxID)
 }
+
+// parseVertex parses [vtxBytes], observing how long it took
+func (t *Transitive) parseVertex(vtxBytes []byte) (avalanche.Vertex, error) {
+	start := t.clock.Time()
+	vtx, err := t.Config.State.ParseVertex(vtxBytes)
+	t.parseDuration.Observe(float64(t.clock.Time().Sub(start)))
+	return vtx, err
+}
+
+// verify verifies the transactions of [vtx], observing how long it took
+func (t *Transitive) verify(vtx avalanche.Vertex) error {
+	start := t.clock.Time()
+	defer func() { t.verifyDuration.Observe(float64(t.clock.Time().Sub(start))) }()
+
+	for _, tx := range vtx.Txs() {
+		if err := tx.Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issued records that [vtx], which the engine learned of at [start], was just
+// issued into consensus
+func (t *Transitive) issued(vtx avalanche.Vertex, start time.Time) {
+	now := t.clock.Time()
+	t.issueDuration.Observe(float64(now.Sub(start)))
+	t.processing[vtx.ID().Key()] = processingVertex{
+		vtx:    vtx,
+		issued: now,
+	}
+}
+
+// observeDecided stops tracking the vertices that were decided, observing how
+// long the accepted ones took to be accepted
+func (t *Transitive) observeDecided() {
+	now := t.clock.Time()
+	for key, processing := range t.processing {
+		switch processing.vtx.Status() {
+		case choices.Accepted:
+			t.acceptDuration.Observe(float64(now.Sub(processing.issued)))
+		case choices.Rejected:
+		default:
+			continue
+		}
+		delete(t.processing, key)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -2363,3 +2364,144 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	sender.PushQueryF = nil
 	st.getVertex = nil
 }
+
+func TestEngineCollectOrphans(t *testing.T) {
+	config := DefaultConfig()
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	gVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Accepted,
+	}
+	rejectedVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Rejected,
+	}
+	missingVtx := &Vtx{
+		id:     GenerateID(),
+		status: choices.Unknown,
+	}
+
+	tx0 := &TestTx{
+		TestTx: snowstorm.TestTx{
+			Identifier: GenerateID(),
+			Stat:       choices.Processing,
+		},
+	}
+	tx0.Ins.Add(GenerateID())
+
+	tx1 := &TestTx{
+		TestTx: snowstorm.TestTx{
+			Identifier: GenerateID(),
+			Stat:       choices.Processing,
+		},
+	}
+	tx1.Ins.Add(GenerateID())
+
+	// Blocked on the missing vertex, and can't be issued as a parent was
+	// rejected
+	vtx0 := &Vtx{
+		parents: []avalanche.Vertex{rejectedVtx, missingVtx},
+		id:      GenerateID(),
+		txs:     []snowstorm.Tx{tx0},
+		height:  1,
+		status:  choices.Processing,
+		bytes:   []byte{0},
+	}
+	// Blocked on the missing vertex
+	vtx1 := &Vtx{
+		parents: []avalanche.Vertex{missingVtx},
+		id:      GenerateID(),
+		txs:     []snowstorm.Tx{tx1},
+		height:  1,
+		status:  choices.Processing,
+		bytes:   []byte{1},
+	}
+
+	st.edge = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	st.getVertex = func(id ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case id.Equals(gVtx.ID()):
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+
+	start := time.Unix(1000000, 0)
+	te.clock.Set(start)
+	te.collect()
+
+	te.insert(vtx0)
+	te.insert(vtx1)
+
+	if len(te.pending) != 2 {
+		t.Fatalf("Both vertices should be blocked")
+	}
+
+	reissued := []snowstorm.Tx(nil)
+	st.buildVertex = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		reissued = append(reissued, txs...)
+		return &Vtx{
+			parents: []avalanche.Vertex{gVtx},
+			id:      GenerateID(),
+			txs:     txs,
+			height:  1,
+			status:  choices.Processing,
+			bytes:   []byte{2},
+		}, nil
+	}
+	sender.CantPushQuery = false
+
+	// Checked too soon since the last time
+	te.clock.Set(start.Add(CollectFrequency - time.Second))
+	te.collect()
+
+	if len(te.pending) != 2 {
+		t.Fatalf("Blocked vertices shouldn't have been checked yet")
+	}
+
+	te.clock.Set(start.Add(CollectFrequency))
+	te.collect()
+
+	if _, blocked := te.pending[vtx0.ID().Key()]; blocked {
+		t.Fatalf("Vertex with a rejected parent should have been dropped")
+	} else if _, blocked := te.pending[vtx1.ID().Key()]; !blocked {
+		t.Fatalf("Vertex shouldn't have been dropped before its TTL")
+	} else if len(reissued) != 1 || !reissued[0].ID().Equals(tx0.ID()) {
+		t.Fatalf("Transaction of the dropped vertex should have been re-issued")
+	} else if !te.Consensus.TxIssued(tx0) {
+		t.Fatalf("Re-issued transaction should have been issued into consensus")
+	}
+
+	te.clock.Set(start.Add(PendingTTL))
+	te.collect()
+
+	if len(te.pending) != 0 {
+		t.Fatalf("Vertex should have been dropped after its TTL")
+	} else if len(reissued) != 2 || !reissued[1].ID().Equals(tx1.ID()) {
+		t.Fatalf("Transaction of the expired vertex should have been re-issued")
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 )
 
 type txParser struct {
+	ctx                     *snow.Context
 	numAccepted, numDropped prometheus.Counter
 	vm                      DAGVM
 }
@@ -23,6 +25,7 @@ func (p *txParser) Parse(txBytes []byte) (queue.Job, error) {
 		return nil, err
 	}
 	return &txJob{
+		ctx:         p.ctx,
 		numAccepted: p.numAccepted,
 		numDropped:  p.numDropped,
 		tx:          tx,
@@ -30,6 +33,7 @@ func (p *txParser) Parse(txBytes []byte) (queue.Job, error) {
 }
 
 type txJob struct {
+	ctx                     *snow.Context
 	numAccepted, numDropped prometheus.Counter
 	tx                      snowstorm.Tx
 }
@@ -55,6 +59,9 @@ func (t *txJob) Execute() {
 		t.numDropped.Inc()
 	case choices.Processing:
 		if err := t.tx.Verify(); err == nil {
+			// Transactions accepted while bootstrapping are dispatched as if
+			// they were accepted by consensus
+			t.ctx.DecisionDispatcher.Accept(t.ctx.ChainID, t.tx.ID(), t.tx.Bytes())
 			t.tx.Accept()
 			t.numAccepted.Inc()
 		} else {
@@ -7,12 +7,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 )
 
 type vtxParser struct {
+	ctx                     *snow.Context
 	numAccepted, numDropped prometheus.Counter
 	state                   State
 }
@@ -23,6 +25,7 @@ func (p *vtxParser) Parse(vtxBytes []byte) (queue.Job, error) {
 		return nil, err
 	}
 	return &vertexJob{
+		ctx:         p.ctx,
 		numAccepted: p.numAccepted,
 		numDropped:  p.numDropped,
 		vtx:         vtx,
@@ -30,6 +33,7 @@ func (p *vtxParser) Parse(vtxBytes []byte) (queue.Job, error) {
 }
 
 type vertexJob struct {
+	ctx                     *snow.Context
 	numAccepted, numDropped prometheus.Counter
 	vtx                     avalanche.Vertex
 }
@@ -59,6 +63,9 @@ func (v *vertexJob) Execute() {
 	case choices.Unknown, choices.Rejected:
 		v.numDropped.Inc()
 	case choices.Processing:
+		// Vertices accepted while bootstrapping are dispatched as if they were
+		// accepted by consensus
+		v.ctx.ConsensusDispatcher.Accept(v.ctx.ChainID, v.vtx.ID(), v.vtx.Bytes())
 		v.vtx.Accept()
 		v.numAccepted.Inc()
 	}
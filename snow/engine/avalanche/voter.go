@@ -37,6 +37,7 @@ func (v *voter) Update() {
 
 	v.t.Config.Context.Log.Debug("Finishing poll with:\n%s", &results)
 	v.t.Consensus.RecordPoll(results)
+	v.t.observeDecided()
 
 	txs := []snowstorm.Tx(nil)
 	for _, orphanID := range v.t.Consensus.Orphans().List() {
@@ -17,6 +17,8 @@ type Bootstrapper struct {
 	pendingAccepted ids.ShortSet
 	accepted        ids.Bag
 
+	syncer stateSyncer
+
 	RequestID uint32
 }
 
@@ -33,8 +35,18 @@ func (b *Bootstrapper) Initialize(config Config) {
 	b.accepted.SetThreshold(config.Alpha)
 }
 
-// Startup implements the Engine interface.
+// Startup implements the Engine interface. If state sync is enabled, the
+// chain's state is synced to a recent summary before it's bootstrapped.
 func (b *Bootstrapper) Startup() {
+	if b.StateSync && b.StateSyncVM != nil && b.Beacons.Len() > 0 {
+		b.startStateSync()
+		return
+	}
+	b.startBootstrapping()
+}
+
+// startBootstrapping requests the beacons' accepted frontiers
+func (b *Bootstrapper) startBootstrapping() {
 	if b.pendingAcceptedFrontier.Len() == 0 {
 		b.Context.Log.Info("Bootstrapping skipped due to no provided bootstraps")
 		b.Bootstrapable.ForceAccepted(ids.Set{})
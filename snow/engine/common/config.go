@@ -18,4 +18,11 @@ type Config struct {
 	Alpha         int
 	Sender        Sender
 	Bootstrapable Bootstrapable
+
+	// Serves this chain's state to other validators. Nil if the chain's VM
+	// doesn't support state sync.
+	StateSyncVM StateSyncableVM
+	// If true, and StateSyncVM isn't nil, the chain's state is synced to a
+	// recent summary attested to by the beacons before bootstrapping
+	StateSync bool
 }
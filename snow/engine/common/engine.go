@@ -9,6 +9,16 @@ import (
 )
 
 // Engine describes the standard interface of a consensus engine
+//
+// The engine is called with its chain's lock, ctx.Lock, held. Messages that may
+// change the chain's state are passed one at a time, with the write lock held.
+// If the chain's handler has read workers, requests that only read the
+// chain's state are passed with the read lock held, and may be passed
+// concurrently with each other and with the chain's read-only API calls. These
+// are GetAcceptedFrontier, GetAccepted, Get, GetStateSummary and
+// GetStateChunk, which must then only read the engine's and the VM's state,
+// and send their responses. Avalanche chains have no read workers, as their
+// engine's requests refresh the cached vertices and transactions they fetch.
 type Engine interface {
 	Handler
 
@@ -29,6 +39,8 @@ type ExternalHandler interface {
 	AcceptedHandler
 	FetchHandler
 	QueryHandler
+	AppHandler
+	StateSyncHandler
 }
 
 // FrontierHandler defines how a consensus engine reacts to frontier messages
@@ -106,6 +118,44 @@ type QueryHandler interface {
 	QueryFailed(validatorID ids.ShortID, requestID uint32)
 }
 
+// AppHandler defines how a consensus engine reacts to app-level messages from
+// other validators
+type AppHandler interface {
+	// Notify this engine that the specified validator gossiped an app-level
+	// message. The message is passed on to the VM, if it handles them.
+	AppGossip(validatorID ids.ShortID, msg []byte)
+}
+
+// StateSyncHandler defines how a consensus engine reacts to state sync
+// messages from other validators
+type StateSyncHandler interface {
+	// GetStateSummary notifies this consensus engine that the specified
+	// validator requested a summary of this engine's recent state
+	GetStateSummary(validatorID ids.ShortID, requestID uint32)
+
+	// StateSummary notifies this consensus engine of the summary of the
+	// specified validator's recent state. [summary] is empty if the validator
+	// doesn't serve its state.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+
+	// GetStateSummaryFailed notifies this consensus engine that the summary
+	// requested from the specified validator should be considered lost
+	GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32)
+
+	// GetStateChunk notifies this consensus engine that the specified
+	// validator requested the chunk with index [index] of the state summarized
+	// by the summary with ID [summaryID]
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32)
+
+	// StateChunk notifies this consensus engine of a chunk of state requested
+	// from the specified validator
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte)
+
+	// GetStateChunkFailed notifies this consensus engine that the chunk
+	// requested from the specified validator should be considered lost
+	GetStateChunkFailed(validatorID ids.ShortID, requestID uint32)
+}
+
 // InternalHandler defines how this consensus engine reacts to messages from
 // other components of this validator
 type InternalHandler interface {
@@ -12,8 +12,14 @@ type LockOption int
 
 // List of all allowed options
 const (
+	// The handler is called with the chain's write lock held, so it's the
+	// only code running on the chain
 	WriteLock = iota
+	// The handler is called with the chain's read lock held, concurrently
+	// with other read-only handlers and the engine's read-only requests, so
+	// it must only read the chain's state
 	ReadLock
+	// The handler is called without the chain's lock
 	NoLock
 )
 
@@ -17,12 +17,19 @@ const (
 	// its VM has pending transactions
 	// (i.e. it would like to add a new block/vertex to consensus)
 	PendingTxs Message = iota
+
+	// StateSyncDone notifies a consensus engine that
+	// its VM has finished syncing its state.
+	// No engine acts on it yet, as VMs sync their state synchronously.
+	StateSyncDone
 )
 
 func (msg Message) String() string {
 	switch msg {
 	case PendingTxs:
 		return "Pending Transactions"
+	case StateSyncDone:
+		return "State Sync Done"
 	default:
 		return fmt.Sprintf("Unknown Message: %d", msg)
 	}
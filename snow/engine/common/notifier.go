@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+)
+
+// The number of messages a VM may send before the notifier takes them in
+const notifierBufferSize = 64
+
+// Notifier passes the messages a VM sends to its consensus engine. The VM is
+// initialized with ToEngine, and the engine's handler receives from Messages.
+//
+// Messages are coalesced: a message that's waiting for the engine to receive
+// it is received once, however many times it's sent. So a VM sending a message
+// doesn't wait on the engine, and messages aren't dropped while the engine is
+// busy. A message the engine has received may be sent again, such as when the
+// VM has more pending transactions after the engine asked for them.
+type Notifier struct {
+	toEngine  chan Message
+	messages  chan Message
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewNotifier returns a notifier that passes messages until it's closed
+func NewNotifier() *Notifier {
+	n := &Notifier{
+		toEngine: make(chan Message, notifierBufferSize),
+		messages: make(chan Message),
+		closed:   make(chan struct{}),
+	}
+	go n.forward()
+	return n
+}
+
+// ToEngine returns the channel the VM sends its messages on
+func (n *Notifier) ToEngine() chan<- Message { return n.toEngine }
+
+// Messages returns the channel the engine receives the VM's messages on. It's
+// closed once the notifier is.
+func (n *Notifier) Messages() <-chan Message { return n.messages }
+
+// Close the notifier. Messages the engine hasn't received are dropped.
+func (n *Notifier) Close() { n.closeOnce.Do(func() { close(n.closed) }) }
+
+// forward the messages sent to the engine, oldest first, until the notifier is
+// closed
+func (n *Notifier) forward() {
+	defer close(n.messages)
+
+	// The messages waiting for the engine to receive them, each at most once
+	waiting := []Message(nil)
+	for {
+		// While no message is waiting, [messages] is nil so nothing is sent
+		messages := chan Message(nil)
+		next := Message(0)
+		if len(waiting) > 0 {
+			messages = n.messages
+			next = waiting[0]
+		}
+
+		select {
+		case msg := <-n.toEngine:
+			if !containsMessage(waiting, msg) {
+				waiting = append(waiting, msg)
+			}
+		case messages <- next:
+			waiting = waiting[1:]
+		case <-n.closed:
+			return
+		}
+	}
+}
+
+func containsMessage(msgs []Message, msg Message) bool {
+	for _, m := range msgs {
+		if m == msg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotifierCoalesces(t *testing.T) {
+	n := NewNotifier()
+
+	for i := 0; i < 10; i++ {
+		n.ToEngine() <- PendingTxs
+	}
+	n.ToEngine() <- StateSyncDone
+	n.ToEngine() <- PendingTxs
+
+	// Wait for the notifier to take in the messages
+	for len(n.toEngine) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if msg := <-n.Messages(); msg != PendingTxs {
+		t.Fatalf("Received %s, expected %s", msg, PendingTxs)
+	}
+	if msg := <-n.Messages(); msg != StateSyncDone {
+		t.Fatalf("Received %s, expected %s", msg, StateSyncDone)
+	}
+
+	// Once received, a message is passed again
+	n.ToEngine() <- PendingTxs
+	if msg := <-n.Messages(); msg != PendingTxs {
+		t.Fatalf("Received %s, expected %s", msg, PendingTxs)
+	}
+
+	n.Close()
+	if msg, ok := <-n.Messages(); ok {
+		t.Fatalf("Received %s after the notifier was closed", msg)
+	}
+}
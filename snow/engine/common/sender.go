@@ -14,6 +14,8 @@ type Sender interface {
 	AcceptedSender
 	FetchSender
 	QuerySender
+	AppSender
+	StateSyncSender
 }
 
 // FrontierSender defines how a consensus engine sends frontier messages to
@@ -70,3 +72,32 @@ type QuerySender interface {
 	// Chits sends chits to the specified validator
 	Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set)
 }
+
+// AppSender defines how a VM sends app-level messages to other validators
+type AppSender interface {
+	// AppGossip sends [msg] to a sample of the validators. Messages may be
+	// dropped if the chain is gossiping too quickly, or if [msg] was gossiped
+	// recently.
+	AppGossip(msg []byte)
+}
+
+// StateSyncSender defines how a consensus engine sends state sync messages to
+// other validators
+type StateSyncSender interface {
+	// GetStateSummary requests that every validator in [validatorIDs] sends a
+	// StateSummary message.
+	GetStateSummary(validatorIDs ids.ShortSet, requestID uint32)
+
+	// StateSummary responds to a GetStateSummary message with a summary of
+	// this engine's recent state. [summary] is empty if this engine doesn't
+	// serve its state.
+	StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte)
+
+	// GetStateChunk requests that the specified validator sends the chunk with
+	// index [index] of the state summarized by the summary with ID
+	// [summaryID].
+	GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32)
+
+	// StateChunk responds to a GetStateChunk message with the requested chunk
+	StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte)
+}
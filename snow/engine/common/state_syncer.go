@@ -0,0 +1,276 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// The number of state chunks requested at a time while syncing
+	maxOutstandingStateChunks = 16
+
+	// The number of times chunks may fail to be fetched before state sync is
+	// abandoned
+	maxStateChunkFailures = 64
+)
+
+// stateSyncer tracks the progress of syncing to a state summary. The chain's
+// state is synced to the summary with the most stake attesting to it, if more
+// than half of the beacons' stake does. The summary's chunks are then fetched
+// from the beacons that attested to it.
+type stateSyncer struct {
+	pendingSummaries ids.ShortSet
+	summaries        map[[32]byte]StateSummary
+	attestations     map[[32]byte][]ids.ShortID
+	weights          map[[32]byte]uint64
+
+	// The summary being synced to, and the beacons that attested to it
+	summary      StateSummary
+	attesters    []ids.ShortID
+	nextAttester int
+
+	chunks        [][]byte
+	nextChunk     int
+	numFetched    int
+	numFailed     int
+	chunkRequests map[uint32]chunkRequest
+}
+
+type chunkRequest struct {
+	validatorID ids.ShortID
+	index       int
+}
+
+// startStateSync requests the beacons' state summaries
+func (b *Bootstrapper) startStateSync() {
+	b.syncer = stateSyncer{
+		summaries:     make(map[[32]byte]StateSummary),
+		attestations:  make(map[[32]byte][]ids.ShortID),
+		weights:       make(map[[32]byte]uint64),
+		chunkRequests: make(map[uint32]chunkRequest),
+	}
+	for _, vdr := range b.Beacons.List() {
+		b.syncer.pendingSummaries.Add(vdr.ID())
+	}
+
+	vdrs := ids.ShortSet{}
+	vdrs.Union(b.syncer.pendingSummaries)
+
+	b.Context.Log.Info("Requesting state summaries from %d beacons", vdrs.Len())
+	b.RequestID++
+	b.Sender.GetStateSummary(vdrs, b.RequestID)
+}
+
+// GetStateSummary implements the Engine interface.
+func (b *Bootstrapper) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	summaryBytes := []byte(nil)
+	if b.StateSyncVM != nil {
+		if summary, err := b.StateSyncVM.StateSummary(); err == nil {
+			summaryBytes = summary.Bytes()
+		} else {
+			b.Context.Log.Debug("Couldn't get a state summary for %s due to %s", validatorID, err)
+		}
+	}
+	b.Sender.StateSummary(validatorID, requestID, summaryBytes)
+}
+
+// GetStateSummaryFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	b.StateSummary(validatorID, requestID, nil)
+}
+
+// StateSummary implements the Engine interface.
+func (b *Bootstrapper) StateSummary(validatorID ids.ShortID, requestID uint32, summaryBytes []byte) {
+	if !b.syncer.pendingSummaries.Contains(validatorID) {
+		b.Context.Log.Debug("Received a StateSummary message from %s unexpectedly", validatorID)
+		return
+	}
+	b.syncer.pendingSummaries.Remove(validatorID)
+
+	if len(summaryBytes) > 0 {
+		b.attest(validatorID, summaryBytes)
+	}
+
+	if b.syncer.pendingSummaries.Len() == 0 {
+		b.selectSummary()
+	}
+}
+
+// attest records that [validatorID] attested to the summary [summaryBytes]
+func (b *Bootstrapper) attest(validatorID ids.ShortID, summaryBytes []byte) {
+	summary, err := b.StateSyncVM.ParseStateSummary(summaryBytes)
+	if err != nil {
+		b.Context.Log.Debug("Dropping the state summary of %s due to %s", validatorID, err)
+		return
+	}
+
+	weight := uint64(0)
+	for _, vdr := range b.Beacons.List() {
+		if vdr.ID().Equals(validatorID) {
+			weight = vdr.Weight()
+			break
+		}
+	}
+
+	key := summary.ID().Key()
+	if _, exists := b.syncer.summaries[key]; !exists {
+		b.syncer.summaries[key] = summary
+	}
+	b.syncer.attestations[key] = append(b.syncer.attestations[key], validatorID)
+	b.syncer.weights[key] += weight
+}
+
+// selectSummary syncs to the summary with the most stake attesting to it, if
+// more than half of the beacons' stake does. Otherwise, the chain bootstraps
+// from its current state.
+func (b *Bootstrapper) selectSummary() {
+	totalWeight := uint64(0)
+	for _, vdr := range b.Beacons.List() {
+		totalWeight += vdr.Weight()
+	}
+
+	bestKey, bestWeight := [32]byte{}, uint64(0)
+	for key, weight := range b.syncer.weights {
+		if weight > bestWeight {
+			bestKey, bestWeight = key, weight
+		}
+	}
+	if bestWeight == 0 || bestWeight <= totalWeight/2 {
+		b.Context.Log.Info("No state summary was attested to by more than half of the beacons' stake. Bootstrapping from this node's state")
+		b.startBootstrapping()
+		return
+	}
+
+	summary := b.syncer.summaries[bestKey]
+	if local, err := b.StateSyncVM.StateSummary(); err == nil && local.Height() >= summary.Height() {
+		b.Context.Log.Info("State is already at height %d, which isn't behind the attested summary. Bootstrapping from this node's state", local.Height())
+		b.startBootstrapping()
+		return
+	}
+
+	b.syncer.summary = summary
+	b.syncer.attesters = b.syncer.attestations[bestKey]
+	b.syncer.chunks = make([][]byte, len(summary.ChunkIDs()))
+
+	b.Context.Log.Info("Syncing to state summary %s at height %d, attested to by %d beacons, in %d chunks",
+		summary.ID(), summary.Height(), len(b.syncer.attesters), len(b.syncer.chunks))
+
+	if len(b.syncer.chunks) == 0 {
+		b.finishStateSync()
+		return
+	}
+	for i := 0; i < maxOutstandingStateChunks && b.syncer.nextChunk < len(b.syncer.chunks); i++ {
+		b.requestChunk(b.syncer.nextChunk)
+		b.syncer.nextChunk++
+	}
+}
+
+// requestChunk requests the chunk with index [index] from the next of the
+// summary's attesters
+func (b *Bootstrapper) requestChunk(index int) {
+	vdr := b.syncer.attesters[b.syncer.nextAttester]
+	b.syncer.nextAttester = (b.syncer.nextAttester + 1) % len(b.syncer.attesters)
+
+	b.RequestID++
+	b.syncer.chunkRequests[b.RequestID] = chunkRequest{
+		validatorID: vdr,
+		index:       index,
+	}
+	b.Sender.GetStateChunk(vdr, b.RequestID, b.syncer.summary.ID(), uint32(index))
+}
+
+// GetStateChunk implements the Engine interface.
+func (b *Bootstrapper) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	if b.StateSyncVM == nil {
+		b.Context.Log.Debug("Dropping GetStateChunk from %s as this chain doesn't serve its state", validatorID)
+		return
+	}
+	chunk, err := b.StateSyncVM.StateChunk(summaryID, index)
+	if err != nil {
+		b.Context.Log.Debug("Couldn't get chunk %d of state summary %s for %s due to %s", index, summaryID, validatorID, err)
+		return
+	}
+	b.Sender.StateChunk(validatorID, requestID, chunk)
+}
+
+// GetStateChunkFailed implements the Engine interface.
+func (b *Bootstrapper) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) {
+	request, ok := b.popChunkRequest(validatorID, requestID)
+	if !ok {
+		b.Context.Log.Debug("Received a GetStateChunkFailed message from %s unexpectedly", validatorID)
+		return
+	}
+
+	b.Context.Log.Debug("Failed to get chunk %d from %s", request.index, validatorID)
+	b.retryChunk(request.index)
+}
+
+// StateChunk implements the Engine interface.
+func (b *Bootstrapper) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	request, ok := b.popChunkRequest(validatorID, requestID)
+	if !ok {
+		b.Context.Log.Debug("Received a StateChunk message from %s unexpectedly", validatorID)
+		return
+	}
+
+	expectedID := b.syncer.summary.ChunkIDs()[request.index]
+	if chunkID := ids.NewID(hashing.ComputeHash256Array(chunk)); !chunkID.Equals(expectedID) {
+		b.Context.Log.Warn("Chunk %d from %s has ID %s rather than %s", request.index, validatorID, chunkID, expectedID)
+		b.retryChunk(request.index)
+		return
+	}
+
+	b.syncer.chunks[request.index] = chunk
+	b.syncer.numFetched++
+
+	switch {
+	case b.syncer.numFetched == len(b.syncer.chunks):
+		b.finishStateSync()
+	case b.syncer.nextChunk < len(b.syncer.chunks):
+		b.requestChunk(b.syncer.nextChunk)
+		b.syncer.nextChunk++
+	}
+}
+
+// retryChunk requests the chunk with index [index] again, unless chunks failed
+// to be fetched too many times, in which case the chain bootstraps from its
+// current state
+func (b *Bootstrapper) retryChunk(index int) {
+	b.syncer.numFailed++
+	if b.syncer.numFailed <= maxStateChunkFailures {
+		b.requestChunk(index)
+		return
+	}
+
+	b.Context.Log.Warn("Abandoning state sync after failing to fetch chunks %d times. Bootstrapping from this node's state", b.syncer.numFailed)
+	b.syncer = stateSyncer{}
+	b.startBootstrapping()
+}
+
+// popChunkRequest removes and returns the outstanding chunk request with ID
+// [requestID], if it was sent to [validatorID]
+func (b *Bootstrapper) popChunkRequest(validatorID ids.ShortID, requestID uint32) (chunkRequest, bool) {
+	request, ok := b.syncer.chunkRequests[requestID]
+	if !ok || !request.validatorID.Equals(validatorID) {
+		return chunkRequest{}, false
+	}
+	delete(b.syncer.chunkRequests, requestID)
+	return request, true
+}
+
+// finishStateSync passes the fetched chunks to the VM, and then bootstraps the
+// chain from the synced state
+func (b *Bootstrapper) finishStateSync() {
+	summary := b.syncer.summary
+	if err := b.StateSyncVM.SyncState(summary, b.syncer.chunks); err != nil {
+		b.Context.Log.Error("Failed to sync to state summary %s due to %s. Bootstrapping from this node's state", summary.ID(), err)
+	} else {
+		b.Context.Log.Info("Synced to state summary %s at height %d", summary.ID(), summary.Height())
+	}
+
+	b.syncer = stateSyncer{}
+	b.startBootstrapping()
+}
@@ -36,7 +36,17 @@ type EngineTest struct {
 	CantPushQuery,
 	CantPullQuery,
 	CantQueryFailed,
-	CantChits bool
+	CantChits,
+
+	CantAppGossip,
+
+	CantGetStateSummary,
+	CantGetStateSummaryFailed,
+	CantStateSummary,
+
+	CantGetStateChunk,
+	CantGetStateChunkFailed,
+	CantStateChunk bool
 
 	StartupF, ShutdownF                                                                func()
 	ContextF                                                                           func() *snow.Context
@@ -45,6 +55,11 @@ type EngineTest struct {
 	PutF, PushQueryF                                                                   func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
 	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
 	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                 func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
+	AppGossipF                                                                         func(validatorID ids.ShortID, msg []byte)
+	GetStateSummaryF, GetStateSummaryFailedF, GetStateChunkFailedF                     func(validatorID ids.ShortID, requestID uint32)
+	StateSummaryF                                                                      func(validatorID ids.ShortID, requestID uint32, summary []byte)
+	GetStateChunkF                                                                     func(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32)
+	StateChunkF                                                                        func(validatorID ids.ShortID, requestID uint32, chunk []byte)
 }
 
 // Default ...
@@ -72,6 +87,16 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantPullQuery = cant
 	e.CantQueryFailed = cant
 	e.CantChits = cant
+
+	e.CantAppGossip = cant
+
+	e.CantGetStateSummary = cant
+	e.CantGetStateSummaryFailed = cant
+	e.CantStateSummary = cant
+
+	e.CantGetStateChunk = cant
+	e.CantGetStateChunkFailed = cant
+	e.CantStateChunk = cant
 }
 
 // Startup ...
@@ -228,3 +253,66 @@ func (e *EngineTest) Chits(validatorID ids.ShortID, requestID uint32, containerI
 		e.T.Fatalf("Unexpectedly called Chits")
 	}
 }
+
+// AppGossip ...
+func (e *EngineTest) AppGossip(validatorID ids.ShortID, msg []byte) {
+	if e.AppGossipF != nil {
+		e.AppGossipF(validatorID, msg)
+	} else if e.CantAppGossip && e.T != nil {
+		e.T.Fatalf("Unexpectedly called AppGossip")
+	}
+}
+
+// GetStateSummary ...
+func (e *EngineTest) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	if e.GetStateSummaryF != nil {
+		e.GetStateSummaryF(validatorID, requestID)
+	} else if e.CantGetStateSummary && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// GetStateSummaryFailed ...
+func (e *EngineTest) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	if e.GetStateSummaryFailedF != nil {
+		e.GetStateSummaryFailedF(validatorID, requestID)
+	} else if e.CantGetStateSummaryFailed && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateSummaryFailed")
+	}
+}
+
+// StateSummary ...
+func (e *EngineTest) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	if e.StateSummaryF != nil {
+		e.StateSummaryF(validatorID, requestID, summary)
+	} else if e.CantStateSummary && e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GetStateChunk ...
+func (e *EngineTest) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	if e.GetStateChunkF != nil {
+		e.GetStateChunkF(validatorID, requestID, summaryID, index)
+	} else if e.CantGetStateChunk && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// GetStateChunkFailed ...
+func (e *EngineTest) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) {
+	if e.GetStateChunkFailedF != nil {
+		e.GetStateChunkFailedF(validatorID, requestID)
+	} else if e.CantGetStateChunkFailed && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetStateChunkFailed")
+	}
+}
+
+// StateChunk ...
+func (e *EngineTest) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	if e.StateChunkF != nil {
+		e.StateChunkF(validatorID, requestID, chunk)
+	} else if e.CantStateChunk && e.T != nil {
+		e.T.Fatalf("Unexpectedly called StateChunk")
+	}
+}
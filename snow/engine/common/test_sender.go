@@ -16,7 +16,10 @@ type SenderTest struct {
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
 	CantGet, CantPut,
-	CantPullQuery, CantPushQuery, CantChits bool
+	CantPullQuery, CantPushQuery, CantChits,
+	CantAppGossip,
+	CantGetStateSummary, CantStateSummary,
+	CantGetStateChunk, CantStateChunk bool
 
 	GetAcceptedFrontierF func(ids.ShortSet, uint32)
 	AcceptedFrontierF    func(ids.ShortID, uint32, ids.Set)
@@ -27,6 +30,11 @@ type SenderTest struct {
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
 	PullQueryF           func(ids.ShortSet, uint32, ids.ID)
 	ChitsF               func(ids.ShortID, uint32, ids.Set)
+	AppGossipF           func([]byte)
+	GetStateSummaryF     func(ids.ShortSet, uint32)
+	StateSummaryF        func(ids.ShortID, uint32, []byte)
+	GetStateChunkF       func(ids.ShortID, uint32, ids.ID, uint32)
+	StateChunkF          func(ids.ShortID, uint32, []byte)
 }
 
 // Default set the default callable value to [cant]
@@ -40,6 +48,11 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
+	s.CantAppGossip = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
 }
 
 // GetAcceptedFrontier calls GetAcceptedFrontierF if it was initialized. If it
@@ -140,3 +153,58 @@ func (s *SenderTest) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) {
 		s.T.Fatalf("Unexpectedly called Chits")
 	}
 }
+
+// AppGossip calls AppGossipF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) AppGossip(msg []byte) {
+	if s.AppGossipF != nil {
+		s.AppGossipF(msg)
+	} else if s.CantAppGossip && s.T != nil {
+		s.T.Fatalf("Unexpectedly called AppGossip")
+	}
+}
+
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(validatorIDs, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) StateSummary(vdr ids.ShortID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) GetStateChunk(vdr ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	if s.GetStateChunkF != nil {
+		s.GetStateChunkF(vdr, requestID, summaryID, index)
+	} else if s.CantGetStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *SenderTest) StateChunk(vdr ids.ShortID, requestID uint32, chunk []byte) {
+	if s.StateChunkF != nil {
+		s.StateChunkF(vdr, requestID, chunk)
+	} else if s.CantStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	}
+}
@@ -21,7 +21,7 @@ type VMTest struct {
 
 	CantInitialize, CantShutdown, CantCreateHandlers, CantCreateStaticHandlers bool
 
-	InitializeF           func(*snow.Context, database.Database, []byte, chan<- Message, []*Fx) error
+	InitializeF           func(*snow.Context, database.Database, []byte, []byte, chan<- Message, []*Fx) error
 	ShutdownF             func()
 	CreateHandlersF       func() map[string]*HTTPHandler
 	CreateStaticHandlersF func() map[string]*HTTPHandler
@@ -35,9 +35,9 @@ func (vm *VMTest) Default(cant bool) {
 }
 
 // Initialize ...
-func (vm *VMTest) Initialize(ctx *snow.Context, db database.Database, initState []byte, msgChan chan<- Message, fxs []*Fx) error {
+func (vm *VMTest) Initialize(ctx *snow.Context, db database.Database, initState, configBytes []byte, msgChan chan<- Message, fxs []*Fx) error {
 	if vm.InitializeF != nil {
-		return vm.InitializeF(ctx, db, initState, msgChan, fxs)
+		return vm.InitializeF(ctx, db, initState, configBytes, msgChan, fxs)
 	}
 	if vm.CantInitialize && vm.T != nil {
 		vm.T.Fatal(errInitialize)
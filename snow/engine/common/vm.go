@@ -5,6 +5,7 @@ package common
 
 import (
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 )
 
@@ -19,7 +20,12 @@ type VM interface {
 	//     [ctx.NodeID]: The unique staker ID of this node.
 	//     [ctx.Lock]: A Read/Write lock shared by this VM and the consensus
 	//                 engine that manages this VM. The write lock is held
-	//                 whenever code in the consensus engine calls the VM.
+	//                 whenever code in the consensus engine calls the VM,
+	//                 except when it serves read-only requests. Those hold
+	//                 the read lock, and may call the VM's read-only methods,
+	//                 such as GetBlock and LastAccepted, concurrently. These
+	//                 must be safe to call concurrently with each other, as
+	//                 the VM's read-only API calls must be.
 	// [db]: The database this VM will persist data to.
 	// [genesisBytes]: The byte-encoding of the genesis information of this
 	//                 VM. The VM uses it to initialize its state. For
@@ -27,12 +33,16 @@ type VM interface {
 	//                 system, `genesisBytes` would probably contain a genesis
 	//                 transaction that gives coins to some accounts, and this
 	//                 transaction would be in the genesis block.
+	// [configBytes]: The byte-encoding of the configuration of this chain.
+	//                Chains running the same VM may be parameterized
+	//                differently by their configuration. May be empty.
 	// [toEngine]: The channel used to send messages to the consensus engine.
 	// [fxs]: Feature extensions that attach to this VM.
 	Initialize(
 		ctx *snow.Context,
 		db database.Database,
 		genesisBytes []byte,
+		configBytes []byte,
 		toEngine chan<- Message,
 		fxs []*Fx,
 	) error
@@ -56,6 +66,62 @@ type VM interface {
 	CreateHandlers() map[string]*HTTPHandler
 }
 
+// AppVM describes the functionality that allows a VM to gossip app-level
+// messages, such as pending transactions, to other validators. Implementing it
+// is optional.
+type AppVM interface {
+	// SetAppSender is called once, after Initialize, with the sender the VM
+	// gossips with.
+	SetAppSender(AppSender)
+
+	// AppGossip notifies this VM that [nodeID] gossiped [msg].
+	AppGossip(nodeID ids.ShortID, msg []byte) error
+}
+
+// StateSummary is a summary of a chain's state at an accepted block, which a
+// bootstrapping node may sync to rather than executing the chain's history.
+// The state is served in chunks, each identified by the SHA256 hash of its
+// bytes, so chunks fetched from any peer can be verified against the summary.
+type StateSummary interface {
+	// ID of this summary. Peers attest to the same state iff they report a
+	// summary with the same ID.
+	ID() ids.ID
+
+	// Height of the accepted block this summary is of
+	Height() uint64
+
+	// ChunkIDs returns the IDs of the chunks of this summary's state, in the
+	// order they're synced in
+	ChunkIDs() []ids.ID
+
+	// Bytes returns the byte representation of this summary
+	Bytes() []byte
+}
+
+// StateSyncableVM describes the functionality that allows a VM to serve its
+// state to, and sync its state from, other validators. Implementing it is
+// optional.
+type StateSyncableVM interface {
+	// StateSummary returns a summary of this VM's state at a recently accepted
+	// block, which this VM can serve the chunks of
+	StateSummary() (StateSummary, error)
+
+	// ParseStateSummary parses a summary returned by the StateSummary of
+	// another validator's VM
+	ParseStateSummary([]byte) (StateSummary, error)
+
+	// StateChunk returns the chunk with index [index] of the state the summary
+	// with ID [summaryID] is of. Errors if this VM no longer serves the
+	// summary.
+	StateChunk(summaryID ids.ID, index uint32) ([]byte, error)
+
+	// SyncState replaces this VM's state with the state [summary] is of.
+	// [chunks] are the summary's chunks, in order, and were verified against
+	// the summary's chunk IDs. Once synced, the summary's block is this VM's
+	// last accepted block.
+	SyncState(summary StateSummary, chunks [][]byte) error
+}
+
 // StaticVM describes the functionality that allows a user to interact with a VM
 // statically.
 type StaticVM interface {
@@ -40,6 +40,7 @@ func (b *bootstrapper) Initialize(config BootstrapConfig) {
 	b.BootstrapConfig = config
 
 	b.Blocked.SetParser(&parser{
+		ctx:         config.Context,
 		numAccepted: b.numBootstrapped,
 		numDropped:  b.numDropped,
 		vm:          b.VM,
@@ -22,6 +22,7 @@ import (
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 var (
@@ -52,9 +53,9 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	peerID := peer.ID()
 	peers.Add(peer)
 
-	handler.Initialize(engine, make(chan common.Message), 1)
+	handler.Initialize(engine, make(chan common.Message), 1, 0)
 	timeouts.Initialize(0)
-	router.Initialize(ctx.Log, timeouts)
+	router.Initialize(ctx.Log, timeouts, nil, "", prometheus.NewRegistry())
 
 	blocker, _ := queue.New(db)
 
@@ -425,3 +426,170 @@ func TestBootstrapperFilterAccepted(t *testing.T) {
 		t.Fatalf("Blk shouldn't be accepted")
 	}
 }
+
+type testSummary struct {
+	id       ids.ID
+	height   uint64
+	chunkIDs []ids.ID
+	bytes    []byte
+}
+
+func (s *testSummary) ID() ids.ID         { return s.id }
+func (s *testSummary) Height() uint64     { return s.height }
+func (s *testSummary) ChunkIDs() []ids.ID { return s.chunkIDs }
+func (s *testSummary) Bytes() []byte      { return s.bytes }
+
+// testStateSyncVM serves the summary [summary] of its state, whose chunks are
+// [chunks]
+type testStateSyncVM struct {
+	summary *testSummary
+	chunks  [][]byte
+
+	synced [][]byte
+}
+
+func (vm *testStateSyncVM) StateSummary() (common.StateSummary, error) {
+	return &testSummary{height: 0}, nil
+}
+
+func (vm *testStateSyncVM) ParseStateSummary(b []byte) (common.StateSummary, error) {
+	if !bytes.Equal(b, vm.summary.bytes) {
+		return nil, errUnknownBlock
+	}
+	return vm.summary, nil
+}
+
+func (vm *testStateSyncVM) StateChunk(summaryID ids.ID, index uint32) ([]byte, error) {
+	return vm.chunks[index], nil
+}
+
+func (vm *testStateSyncVM) SyncState(summary common.StateSummary, chunks [][]byte) error {
+	vm.synced = chunks
+	return nil
+}
+
+func newStateSyncConfig(t *testing.T) (BootstrapConfig, []ids.ShortID, *common.SenderTest, *testStateSyncVM) {
+	config, peerID, sender, _ := newConfig(t)
+
+	peer := validators.GenerateRandomValidator(1)
+	config.Beacons.Add(peer)
+
+	chunks := [][]byte{{1}, {2}, {3}}
+	summary := &testSummary{
+		id:     GenerateID(),
+		height: 100,
+		bytes:  []byte{0},
+	}
+	for _, chunk := range chunks {
+		summary.chunkIDs = append(summary.chunkIDs, ids.NewID(hashing.ComputeHash256Array(chunk)))
+	}
+	stateSyncVM := &testStateSyncVM{
+		summary: summary,
+		chunks:  chunks,
+	}
+
+	config.StateSync = true
+	config.StateSyncVM = stateSyncVM
+	sender.CantGetAcceptedFrontier = true
+	return config, []ids.ShortID{peerID, peer.ID()}, sender, stateSyncVM
+}
+
+func TestBootstrapperStateSync(t *testing.T) {
+	config, peerIDs, sender, stateSyncVM := newStateSyncConfig(t)
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	summaryReqID := new(uint32)
+	sender.GetStateSummaryF = func(vdrs ids.ShortSet, reqID uint32) {
+		if vdrs.Len() != 2 {
+			t.Fatalf("Should have requested the summaries of both beacons")
+		}
+		*summaryReqID = reqID
+	}
+
+	bs.Startup()
+
+	chunkReqs := map[uint32]ids.ShortID{}
+	chunkIndices := map[uint32]uint32{}
+	sender.GetStateChunkF = func(vdr ids.ShortID, reqID uint32, summaryID ids.ID, index uint32) {
+		if !summaryID.Equals(stateSyncVM.summary.id) {
+			t.Fatalf("Requested a chunk of the wrong summary")
+		}
+		chunkReqs[reqID] = vdr
+		chunkIndices[reqID] = index
+	}
+
+	for _, peerID := range peerIDs {
+		bs.StateSummary(peerID, *summaryReqID, stateSyncVM.summary.bytes)
+	}
+
+	if len(chunkReqs) != len(stateSyncVM.chunks) {
+		t.Fatalf("Should have requested %d chunks but requested %d", len(stateSyncVM.chunks), len(chunkReqs))
+	}
+
+	bootstrapping := false
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		if len(stateSyncVM.synced) == 0 {
+			t.Fatalf("Should have synced before bootstrapping")
+		}
+		bootstrapping = true
+	}
+
+	// The first response is corrupted, so its chunk is requested again
+	corrupted := false
+	for len(chunkReqs) > 0 {
+		for reqID, vdr := range chunkReqs {
+			delete(chunkReqs, reqID)
+			chunk := stateSyncVM.chunks[chunkIndices[reqID]]
+			if !corrupted {
+				corrupted = true
+				chunk = []byte{0xff}
+			}
+			bs.StateChunk(vdr, reqID, chunk)
+			break
+		}
+	}
+
+	if len(stateSyncVM.synced) != len(stateSyncVM.chunks) {
+		t.Fatalf("Should have synced to the summary's chunks")
+	}
+	for i, chunk := range stateSyncVM.synced {
+		if !bytes.Equal(chunk, stateSyncVM.chunks[i]) {
+			t.Fatalf("Synced chunk %d is wrong", i)
+		}
+	}
+	if !bootstrapping {
+		t.Fatalf("Should have started bootstrapping from the synced state")
+	}
+}
+
+func TestBootstrapperStateSyncNoQuorum(t *testing.T) {
+	config, peerIDs, sender, stateSyncVM := newStateSyncConfig(t)
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	summaryReqID := new(uint32)
+	sender.GetStateSummaryF = func(_ ids.ShortSet, reqID uint32) { *summaryReqID = reqID }
+
+	bs.Startup()
+
+	// Half of the beacons' stake doesn't attest to a summary, so the chain
+	// bootstraps without syncing
+	bs.StateSummary(peerIDs[0], *summaryReqID, stateSyncVM.summary.bytes)
+
+	bootstrapping := false
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { bootstrapping = true }
+
+	bs.GetStateSummaryFailed(peerIDs[1], *summaryReqID)
+
+	if !bootstrapping {
+		t.Fatalf("Should have started bootstrapping")
+	}
+	if stateSyncVM.synced != nil {
+		t.Fatalf("Shouldn't have synced without a quorum of stake attesting to the summary")
+	}
+}
@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"time"
+)
+
+const (
+	// The fraction of a poll's validators that may fail to respond before the
+	// network is considered congested
+	maxPollFailureRate = 0.2
+
+	// A poll that takes this many times longer than the quickest polls
+	// indicates the network is congested
+	pollLatencyTolerance = 2
+
+	// How much the limit shrinks when polls time out, and when they're slow
+	timeoutDecrease = 0.5
+	latencyDecrease = 0.875
+
+	// How quickly the baseline latency rises towards the latency of polls
+	// slower than it, so it adapts if the network slows down for good
+	baselineDrift = 0.01
+)
+
+// concurrency limits the number of polls the engine keeps outstanding when it
+// repolls. The limit is adjusted as polls finish: it grows additively, by about
+// one per limit's worth of polls, while polls are answered quickly, and shrinks
+// multiplicatively when they time out or take much longer than the quickest
+// polls did. The limit is kept between 1 and [max].
+type concurrency struct {
+	max   int
+	limit float64
+
+	// The latency polls take when the network isn't congested
+	baseline time.Duration
+}
+
+func newConcurrency(max int) *concurrency {
+	if max < 1 {
+		max = 1
+	}
+	return &concurrency{
+		max:   max,
+		limit: 1,
+	}
+}
+
+// Limit returns the number of polls the engine may keep outstanding
+func (c *concurrency) Limit() int { return int(c.limit) }
+
+// Observe adjusts the limit given a poll of [numPolled] validators that took
+// [latency] to finish, and that [numFailed] of the validators failed to
+// respond to
+func (c *concurrency) Observe(latency time.Duration, numPolled, numFailed int) {
+	if numPolled > 0 && float64(numFailed)/float64(numPolled) > maxPollFailureRate {
+		c.decrease(timeoutDecrease)
+		return
+	}
+
+	switch {
+	case c.baseline == 0 || latency < c.baseline:
+		c.baseline = latency
+	default:
+		c.baseline += time.Duration(float64(latency-c.baseline) * baselineDrift)
+	}
+
+	if latency > pollLatencyTolerance*c.baseline {
+		c.decrease(latencyDecrease)
+		return
+	}
+	c.limit += 1 / c.limit
+	if max := float64(c.max); c.limit > max {
+		c.limit = max
+	}
+}
+
+func (c *concurrency) decrease(factor float64) {
+	c.limit *= factor
+	if c.limit < 1 {
+		c.limit = 1
+	}
+}
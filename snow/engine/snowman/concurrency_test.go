@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConcurrencyIncrease(t *testing.T) {
+	c := newConcurrency(4)
+	if limit := c.Limit(); limit != 1 {
+		t.Fatalf("Should have started with a limit of 1 but started with %d", limit)
+	}
+
+	// The limit grows by about one per limit's worth of quick polls
+	c.Observe(time.Second, 5, 0)
+	if limit := c.Limit(); limit != 2 {
+		t.Fatalf("Limit should have been 2 but was %d", limit)
+	}
+	c.Observe(time.Second, 5, 0)
+	c.Observe(time.Second, 5, 0)
+	if limit := c.Limit(); limit != 2 {
+		t.Fatalf("Limit should have been 2 but was %d", limit)
+	}
+	c.Observe(time.Second, 5, 0)
+	if limit := c.Limit(); limit != 3 {
+		t.Fatalf("Limit should have been 3 but was %d", limit)
+	}
+
+	for i := 0; i < 100; i++ {
+		c.Observe(time.Second, 5, 0)
+	}
+	if limit := c.Limit(); limit != 4 {
+		t.Fatalf("Limit should have been capped at 4 but was %d", limit)
+	}
+}
+
+func TestConcurrencyTimeouts(t *testing.T) {
+	c := newConcurrency(8)
+	for i := 0; i < 100; i++ {
+		c.Observe(time.Second, 5, 0)
+	}
+	if limit := c.Limit(); limit != 8 {
+		t.Fatalf("Limit should have been 8 but was %d", limit)
+	}
+
+	// A validator failing to respond to a poll isn't congestion
+	c.Observe(time.Second, 5, 1)
+	if limit := c.Limit(); limit != 8 {
+		t.Fatalf("Limit should have been 8 but was %d", limit)
+	}
+
+	// More failing to respond is
+	c.Observe(time.Second, 5, 2)
+	if limit := c.Limit(); limit != 4 {
+		t.Fatalf("Limit should have halved to 4 but was %d", limit)
+	}
+	for i := 0; i < 10; i++ {
+		c.Observe(time.Second, 5, 5)
+	}
+	if limit := c.Limit(); limit != 1 {
+		t.Fatalf("Limit should have been kept at 1 but was %d", limit)
+	}
+}
+
+func TestConcurrencyLatency(t *testing.T) {
+	c := newConcurrency(8)
+	for i := 0; i < 100; i++ {
+		c.Observe(time.Second, 5, 0)
+	}
+
+	// Polls slower than the quickest ones, but within the tolerance, are fine
+	c.Observe(time.Second*3/2, 5, 0)
+	if limit := c.Limit(); limit != 8 {
+		t.Fatalf("Limit should have been 8 but was %d", limit)
+	}
+
+	// Much slower polls shrink the limit
+	c.Observe(3*time.Second, 5, 0)
+	if limit := c.Limit(); limit != 7 {
+		t.Fatalf("Limit should have shrunk to 7 but was %d", limit)
+	}
+}
+
+func TestConcurrencyZero(t *testing.T) {
+	c := newConcurrency(0)
+	for i := 0; i < 100; i++ {
+		c.Observe(time.Second, 5, 0)
+	}
+	if limit := c.Limit(); limit != 1 {
+		t.Fatalf("Limit should have been 1 but was %d", limit)
+	}
+}
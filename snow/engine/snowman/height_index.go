@@ -0,0 +1,167 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Keys of a height index's database are prefixed by the table they belong to
+const (
+	// height -> block ID
+	heightPrefix byte = iota
+	// holds the height and ID of the last indexed block
+	tipPrefix
+)
+
+var (
+	// ErrNoBlockAtHeight is returned when no accepted block has the requested
+	// height
+	ErrNoBlockAtHeight = errors.New("no accepted block has this height")
+
+	errIndexGap = errors.New("the accepted blocks don't lead back to the last indexed block")
+)
+
+// BlockGetter is the subset of a ChainVM a height index reads blocks from
+type BlockGetter interface {
+	GetBlock(ids.ID) (snowman.Block, error)
+	LastAccepted() ids.ID
+}
+
+// HeightIndex indexes the accepted blocks of a chain by height. It's kept in
+// its own database, for VMs that don't index their blocks by height.
+//
+// The genesis block, which has no accepted parent, has height 0. The index is
+// brought up to date with the last accepted block when a height past its last
+// indexed block is requested, by walking back through the accepted blocks'
+// parents.
+//
+// The index reads blocks from the VM, so the chain's lock must be held while
+// it's used.
+type HeightIndex struct {
+	vm BlockGetter
+	db database.Database
+}
+
+// NewHeightIndex returns the height index of [vm]'s blocks stored in [db]
+func NewHeightIndex(vm BlockGetter, db database.Database) *HeightIndex {
+	return &HeightIndex{
+		vm: vm,
+		db: db,
+	}
+}
+
+// HeightIndexOf returns [vm]'s own height index if it has one. Otherwise, it
+// returns a height index of [vm]'s blocks stored in [db].
+func HeightIndexOf(vm ChainVM, db database.Database) HeightIndexer {
+	if indexer, ok := vm.(HeightIndexer); ok {
+		return indexer
+	}
+	return NewHeightIndex(vm, db)
+}
+
+// GetBlockIDAtHeight implements the HeightIndexer interface
+func (i *HeightIndex) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	tipHeight, _, indexed, err := i.tip()
+	if err != nil {
+		return ids.ID{}, err
+	}
+	if !indexed || height > tipHeight {
+		if err := i.update(); err != nil {
+			return ids.ID{}, err
+		}
+	}
+
+	blkIDBytes, err := i.db.Get(heightKey(height))
+	if err == database.ErrNotFound {
+		return ids.ID{}, ErrNoBlockAtHeight
+	} else if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(blkIDBytes)
+}
+
+// update indexes the blocks accepted since the last indexed block
+func (i *HeightIndex) update() error {
+	tipHeight, tipID, indexed, err := i.tip()
+	if err != nil {
+		return err
+	}
+	lastAccepted := i.vm.LastAccepted()
+	if indexed && tipID.Equals(lastAccepted) {
+		return nil
+	}
+
+	// IDs of the unindexed blocks, from the last accepted block back
+	unindexed := []ids.ID(nil)
+	blk, err := i.vm.GetBlock(lastAccepted)
+	if err != nil {
+		return fmt.Errorf("couldn't get the last accepted block %s: %w", lastAccepted, err)
+	}
+	for !indexed || !blk.ID().Equals(tipID) {
+		unindexed = append(unindexed, blk.ID())
+
+		parent := blk.Parent()
+		if parent == nil || parent.Status() != choices.Accepted {
+			// [blk] is the genesis block
+			if indexed {
+				return errIndexGap
+			}
+			break
+		}
+		blk = parent
+	}
+
+	height := uint64(0)
+	if indexed {
+		height = tipHeight + 1
+	}
+	batch := i.db.NewBatch()
+	for j := len(unindexed) - 1; j >= 0; j-- {
+		if err := batch.Put(heightKey(height), unindexed[j].Bytes()); err != nil {
+			return err
+		}
+		height++
+	}
+
+	tipBytes := make([]byte, wrappers.LongLen+hashing.HashLen)
+	binary.BigEndian.PutUint64(tipBytes, height-1)
+	copy(tipBytes[wrappers.LongLen:], lastAccepted.Bytes())
+	if err := batch.Put([]byte{tipPrefix}, tipBytes); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// tip returns the height and ID of the last indexed block, and false if no
+// block is indexed yet
+func (i *HeightIndex) tip() (uint64, ids.ID, bool, error) {
+	tipBytes, err := i.db.Get([]byte{tipPrefix})
+	switch {
+	case err == database.ErrNotFound:
+		return 0, ids.ID{}, false, nil
+	case err != nil:
+		return 0, ids.ID{}, false, err
+	case len(tipBytes) != wrappers.LongLen+hashing.HashLen:
+		return 0, ids.ID{}, false, fmt.Errorf("tip has %d bytes, expected %d", len(tipBytes), wrappers.LongLen+hashing.HashLen)
+	}
+	tipID, err := ids.ToID(tipBytes[wrappers.LongLen:])
+	return binary.BigEndian.Uint64(tipBytes), tipID, true, err
+}
+
+func heightKey(height uint64) []byte {
+	key := make([]byte, 1+wrappers.LongLen)
+	key[0] = heightPrefix
+	binary.BigEndian.PutUint64(key[1:], height)
+	return key
+}
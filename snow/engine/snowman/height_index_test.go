@@ -0,0 +1,109 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+// heightIndexVM returns a VM whose chain is [blks], and a pointer to the index
+// of its last accepted block in [blks]
+func heightIndexVM(t *testing.T, blks []*Blk) (*VMTest, *int) {
+	vm := &VMTest{}
+	vm.T = t
+	vm.Default(true)
+
+	lastAccepted := len(blks) - 1
+	vm.LastAcceptedF = func() ids.ID { return blks[lastAccepted].ID() }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID().Equals(blkID) {
+				return blk, nil
+			}
+		}
+		t.Fatalf("Unknown block")
+		return nil, errUnknownBlock
+	}
+	return vm, &lastAccepted
+}
+
+func TestHeightIndex(t *testing.T) {
+	blks := []*Blk{{
+		id:     ids.Empty.Prefix(0),
+		status: choices.Accepted,
+	}}
+	for i := 1; i < 5; i++ {
+		blks = append(blks, &Blk{
+			parent: blks[i-1],
+			id:     ids.Empty.Prefix(uint64(i)),
+			height: i,
+			status: choices.Accepted,
+		})
+	}
+
+	vm, lastAccepted := heightIndexVM(t, blks)
+	*lastAccepted = 2
+	index := NewHeightIndex(vm, memdb.New())
+
+	for height := 0; height <= 2; height++ {
+		if blkID, err := index.GetBlockIDAtHeight(uint64(height)); err != nil {
+			t.Fatal(err)
+		} else if !blkID.Equals(blks[height].ID()) {
+			t.Fatalf("Height %d should have been block %s but was %s", height, blks[height].ID(), blkID)
+		}
+	}
+	if _, err := index.GetBlockIDAtHeight(3); err != ErrNoBlockAtHeight {
+		t.Fatalf("Height 3 shouldn't have had an accepted block")
+	}
+
+	// The blocks accepted since are indexed when they're requested
+	*lastAccepted = 4
+	for height := 0; height <= 4; height++ {
+		if blkID, err := index.GetBlockIDAtHeight(uint64(height)); err != nil {
+			t.Fatal(err)
+		} else if !blkID.Equals(blks[height].ID()) {
+			t.Fatalf("Height %d should have been block %s but was %s", height, blks[height].ID(), blkID)
+		}
+	}
+}
+
+func TestHeightIndexGap(t *testing.T) {
+	genesis := &Blk{
+		id:     ids.Empty.Prefix(0),
+		status: choices.Accepted,
+	}
+	blks := []*Blk{genesis, {
+		parent: genesis,
+		id:     ids.Empty.Prefix(1),
+		height: 1,
+		status: choices.Accepted,
+	}}
+
+	vm, _ := heightIndexVM(t, blks)
+	index := NewHeightIndex(vm, memdb.New())
+	if _, err := index.GetBlockIDAtHeight(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A block that doesn't lead back to the indexed blocks can't be indexed
+	blks = append(blks, &Blk{
+		parent: &Blk{
+			id:     ids.Empty.Prefix(2),
+			status: choices.Processing,
+		},
+		id:     ids.Empty.Prefix(3),
+		height: 2,
+		status: choices.Accepted,
+	})
+	index.vm, _ = heightIndexVM(t, blks)
+	if _, err := index.GetBlockIDAtHeight(2); !errors.Is(err, errIndexGap) {
+		t.Fatalf("Should have errored due to the gap in the accepted blocks")
+	}
+}
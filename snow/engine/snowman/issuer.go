@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 )
@@ -13,6 +15,7 @@ type issuer struct {
 	blk       snowman.Block
 	abandoned bool
 	deps      ids.Set
+	start     time.Time // When the engine learned of the block
 }
 
 func (i *issuer) Dependencies() ids.Set { return i.deps }
@@ -40,5 +43,5 @@ func (i *issuer) Update() {
 		return
 	}
 
-	i.t.deliver(i.blk)
+	i.t.deliver(i.blk, i.start)
 }
@@ -9,11 +9,30 @@ import (
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
+// Buckets of the durations of the stages of handling a block, in nanoseconds,
+// from 10µs to about 11 minutes
+var durationBuckets = prometheus.ExponentialBuckets(10000, 4, 14)
+
+func newDuration(namespace, name, help string) prometheus.Histogram {
+	return prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   durationBuckets,
+	})
+}
+
 type metrics struct {
 	numPendingRequests, numBlocked prometheus.Gauge
 	numBootstrapped, numDropped    prometheus.Counter
 
 	numPolls, numBlkRequests, numBlockedBlk prometheus.Gauge
+	pollLimit                               prometheus.Gauge
+
+	// Durations of the stages of handling a block. Each chain's metrics are
+	// registered under the chain's namespace, so they're per chain.
+	parseDuration, verifyDuration, issueDuration,
+	pollDuration, acceptDuration prometheus.Histogram
 }
 
 // Initialize implements the Engine interface
@@ -48,6 +67,12 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "sm_polls",
 			Help:      "Number of pending network polls",
 		})
+	m.pollLimit = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sm_poll_limit",
+			Help:      "Number of network polls that may be pending when repolling",
+		})
 	m.numBlkRequests = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -60,6 +85,16 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "sm_blocked_blks",
 			Help:      "Number of blocked vertices",
 		})
+	m.parseDuration = newDuration(namespace, "sm_parse_duration",
+		"Duration of parsing a block, in nanoseconds")
+	m.verifyDuration = newDuration(namespace, "sm_verify_duration",
+		"Duration of verifying a block, in nanoseconds")
+	m.issueDuration = newDuration(namespace, "sm_issue_duration",
+		"Time from learning of a block until it was issued into consensus, in nanoseconds. This includes fetching its ancestors")
+	m.pollDuration = newDuration(namespace, "sm_poll_duration",
+		"Time from sending a poll until it finished, in nanoseconds")
+	m.acceptDuration = newDuration(namespace, "sm_accept_duration",
+		"Time from issuing a block into consensus until it was accepted, in nanoseconds")
 
 	if err := registerer.Register(m.numPendingRequests); err != nil {
 		log.Error("Failed to register sm_bs_requests statistics due to %s", err)
@@ -76,10 +111,28 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 	if err := registerer.Register(m.numPolls); err != nil {
 		log.Error("Failed to register sm_polls statistics due to %s", err)
 	}
+	if err := registerer.Register(m.pollLimit); err != nil {
+		log.Error("Failed to register sm_poll_limit statistics due to %s", err)
+	}
 	if err := registerer.Register(m.numBlkRequests); err != nil {
 		log.Error("Failed to register sm_blk_requests statistics due to %s", err)
 	}
 	if err := registerer.Register(m.numBlockedBlk); err != nil {
 		log.Error("Failed to register sm_blocked_blks statistics due to %s", err)
 	}
+	if err := registerer.Register(m.parseDuration); err != nil {
+		log.Error("Failed to register sm_parse_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.verifyDuration); err != nil {
+		log.Error("Failed to register sm_verify_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.issueDuration); err != nil {
+		log.Error("Failed to register sm_issue_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.pollDuration); err != nil {
+		log.Error("Failed to register sm_poll_duration statistics due to %s", err)
+	}
+	if err := registerer.Register(m.acceptDuration); err != nil {
+		log.Error("Failed to register sm_accept_duration statistics due to %s", err)
+	}
 }
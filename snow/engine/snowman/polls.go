@@ -6,17 +6,25 @@ package snowman
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type polls struct {
 	log      logging.Logger
 	numPolls prometheus.Gauge
+	duration prometheus.Histogram // Observes how long finished polls took
 	alpha    int
 	m        map[uint32]poll
+
+	// Observes how long finished polls took, and how many of their validators
+	// failed to respond. Nil if polls aren't observed.
+	concurrency *concurrency
+	clock       timer.Clock
 }
 
 // Add to the current set of polls
@@ -27,6 +35,8 @@ func (p *polls) Add(requestID uint32, numPolled int) bool {
 	if !exists {
 		poll.alpha = p.alpha
 		poll.numPolled = numPolled
+		poll.numSampled = numPolled
+		poll.start = p.clock.Time()
 		p.m[requestID] = poll
 
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
@@ -46,6 +56,7 @@ func (p *polls) Vote(requestID uint32, vdr ids.ShortID, vote ids.ID) (ids.Bag, b
 	if poll.Finished() {
 		delete(p.m, requestID)
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		p.observe(poll)
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
@@ -64,12 +75,25 @@ func (p *polls) CancelVote(requestID uint32, vdr ids.ShortID) (ids.Bag, bool) {
 	if poll.Finished() {
 		delete(p.m, requestID)
 		p.numPolls.Set(float64(len(p.m))) // Tracks performance statistics
+		p.observe(poll)
 		return poll.votes, true
 	}
 	p.m[requestID] = poll
 	return ids.Bag{}, false
 }
 
+// observe records how long the finished [poll] took, and passes how it went
+// to the concurrency controller
+func (p *polls) observe(poll poll) {
+	duration := p.clock.Time().Sub(poll.start)
+	if p.duration != nil {
+		p.duration.Observe(float64(duration))
+	}
+	if p.concurrency != nil {
+		p.concurrency.Observe(duration, poll.numSampled, poll.numFailed)
+	}
+}
+
 func (p *polls) String() string {
 	sb := strings.Builder{}
 
@@ -86,12 +110,17 @@ type poll struct {
 	alpha     int
 	votes     ids.Bag
 	numPolled int
+
+	start      time.Time // When the poll was sent
+	numSampled int       // Number of validators polled
+	numFailed  int       // Number of validators that failed to respond
 }
 
 // Vote registers a vote for this poll
 func (p *poll) CancelVote() {
 	if p.numPolled > 0 {
 		p.numPolled--
+		p.numFailed++
 	}
 }
 
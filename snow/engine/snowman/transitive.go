@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -11,6 +13,7 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -25,9 +28,20 @@ type Transitive struct {
 
 	blocked events.Blocker // track operations that are blocked on blocks
 
+	// When each block that's processing in consensus was issued into it, to
+	// observe how long blocks take to be accepted
+	processing map[[32]byte]processingBlock
+	clock      timer.Clock
+
 	bootstrapped bool
 }
 
+// processingBlock is a block that's processing in consensus
+type processingBlock struct {
+	blk    snowman.Block
+	issued time.Time
+}
+
 // Initialize implements the Engine interface
 func (t *Transitive) Initialize(config Config) {
 	config.Context.Log.Info("Initializing Snowman consensus")
@@ -42,6 +56,10 @@ func (t *Transitive) Initialize(config Config) {
 	t.polls.numPolls = t.numPolls
 	t.polls.alpha = t.Params.Alpha
 	t.polls.m = make(map[uint32]poll)
+	t.polls.concurrency = newConcurrency(t.Params.ConcurrentPolls)
+	t.polls.duration = t.pollDuration
+
+	t.processing = make(map[[32]byte]processingBlock)
 }
 
 func (t *Transitive) finishBootstrapping() {
@@ -76,7 +94,7 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		return
 	}
 
-	blk, err := t.Config.VM.ParseBlock(blkBytes)
+	blk, err := t.parseBlock(blkBytes)
 	if err != nil {
 		t.Config.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
 			err,
@@ -180,6 +198,22 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) {
 	})
 }
 
+// AppGossip implements the Engine interface
+func (t *Transitive) AppGossip(vdr ids.ShortID, msg []byte) {
+	if !t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping AppGossip due to bootstrapping")
+		return
+	}
+
+	appVM, ok := t.Config.VM.(common.AppVM)
+	if !ok {
+		return
+	}
+	if err := appVM.AppGossip(vdr, msg); err != nil {
+		t.Config.Context.Log.Debug("AppGossip from %s failed due to %s", vdr, err)
+	}
+}
+
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) {
 	if !t.bootstrapped {
@@ -259,8 +293,9 @@ func (t *Transitive) insert(blk snowman.Block) {
 	t.blkReqs.Remove(blkID)
 
 	i := &issuer{
-		t:   t,
-		blk: blk,
+		t:     t,
+		blk:   blk,
+		start: t.clock.Time(),
 	}
 
 	if parent := blk.Parent(); !t.Consensus.Issued(parent) {
@@ -322,7 +357,8 @@ func (t *Transitive) pushSample(blk snowman.Block) {
 	}
 }
 
-func (t *Transitive) deliver(blk snowman.Block) {
+// deliver issues [blk], which the engine learned of at [start], into consensus
+func (t *Transitive) deliver(blk snowman.Block, start time.Time) {
 	if t.Consensus.Issued(blk) {
 		return
 	}
@@ -330,7 +366,7 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	blkID := blk.ID()
 	t.pending.Remove(blkID)
 
-	if err := blk.Verify(); err != nil {
+	if err := t.verify(blk); err != nil {
 		t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
 		t.blocked.Abandon(blkID)
 		t.numBlockedBlk.Set(float64(t.pending.Len())) // Tracks performance statistics
@@ -340,6 +376,7 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	t.Config.Context.Log.Verbo("Adding block to consensus: %s", blkID)
 
 	t.Consensus.Add(blk)
+	t.issued(blk, start)
 	t.pushSample(blk)
 
 	added := []snowman.Block{}
@@ -347,12 +384,13 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	switch blk := blk.(type) {
 	case OracleBlock:
 		for _, blk := range blk.Options() {
-			if err := blk.Verify(); err != nil {
+			if err := t.verify(blk); err != nil {
 				t.Config.Context.Log.Debug("Block failed verification due to %s, dropping block", err)
 				t.blocked.Abandon(blk.ID())
 				dropped = append(dropped, blk)
 			} else {
 				t.Consensus.Add(blk)
+				t.issued(blk, start)
 				t.pushSample(blk)
 				added = append(added, blk)
 			}
@@ -377,3 +415,46 @@ func (t *Transitive) deliver(blk snowman.Block) {
 	t.numBlkRequests.Set(float64(t.blkReqs.Len()))
 	t.numBlockedBlk.Set(float64(t.pending.Len()))
 }
+
+// parseBlock parses [blkBytes] with the VM, observing how long it took
+func (t *Transitive) parseBlock(blkBytes []byte) (snowman.Block, error) {
+	start := t.clock.Time()
+	blk, err := t.Config.VM.ParseBlock(blkBytes)
+	t.parseDuration.Observe(float64(t.clock.Time().Sub(start)))
+	return blk, err
+}
+
+// verify verifies [blk], observing how long it took
+func (t *Transitive) verify(blk snowman.Block) error {
+	start := t.clock.Time()
+	err := blk.Verify()
+	t.verifyDuration.Observe(float64(t.clock.Time().Sub(start)))
+	return err
+}
+
+// issued records that [blk], which the engine learned of at [start], was just
+// issued into consensus
+func (t *Transitive) issued(blk snowman.Block, start time.Time) {
+	now := t.clock.Time()
+	t.issueDuration.Observe(float64(now.Sub(start)))
+	t.processing[blk.ID().Key()] = processingBlock{
+		blk:    blk,
+		issued: now,
+	}
+}
+
+// observeDecided stops tracking the blocks that were decided, observing how
+// long the accepted ones took to be accepted
+func (t *Transitive) observeDecided() {
+	now := t.clock.Time()
+	for key, processing := range t.processing {
+		switch processing.blk.Status() {
+		case choices.Accepted:
+			t.acceptDuration.Observe(float64(now.Sub(processing.issued)))
+		case choices.Rejected:
+		default:
+			continue
+		}
+		delete(t.processing, key)
+	}
+}
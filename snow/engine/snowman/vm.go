@@ -55,3 +55,22 @@ type ChainVM interface {
 	// returned.
 	LastAccepted() ids.ID
 }
+
+// HeightIndexer describes the functionality that allows the accepted blocks of
+// a chain to be looked up by height
+type HeightIndexer interface {
+	// GetBlockIDAtHeight returns the ID of the accepted block with height
+	// [height]. The genesis block has height 0.
+	//
+	// If no accepted block has the height, ErrNoBlockAtHeight should be
+	// returned.
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+}
+
+// HeightIndexedChainVM describes a Snowman VM that indexes its accepted blocks
+// by height. Implementing it is optional. The height index of a VM that
+// doesn't is maintained by a HeightIndex.
+type HeightIndexedChainVM interface {
+	ChainVM
+	HeightIndexer
+}
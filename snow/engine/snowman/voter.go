@@ -43,6 +43,7 @@ func (v *voter) Update() {
 
 	v.t.Config.Context.Log.Verbo("Finishing poll [%d] with:\n%s", v.requestID, &results)
 	v.t.Consensus.RecordPoll(results)
+	v.t.observeDecided()
 
 	v.t.Config.VM.SetPreference(v.t.Consensus.Preference())
 
@@ -53,7 +54,8 @@ func (v *voter) Update() {
 
 	v.t.Config.Context.Log.Verbo("Snowman engine can't quiesce")
 
-	if len(v.t.polls.m) == 0 {
+	for i := len(v.t.polls.m); i < v.t.polls.concurrency.Limit(); i++ {
 		v.t.repoll()
 	}
+	v.t.pollLimit.Set(float64(v.t.polls.concurrency.Limit())) // Tracks performance statistics
 }
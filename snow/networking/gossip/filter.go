@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Config limits the app-level gossip of a chain
+type Config struct {
+	// Messages per second that pass the filter, and how many may pass in a
+	// burst. If Rate is 0, messages aren't rate limited.
+	Rate  float64
+	Burst int
+
+	// Number of messages that passed the filter that are remembered. A
+	// message that's remembered doesn't pass again.
+	CacheSize int
+}
+
+// DefaultConfig is the filter each chain's gossip passes through, in each
+// direction
+var DefaultConfig = Config{
+	Rate:      100,
+	Burst:     500,
+	CacheSize: 4096,
+}
+
+// Filter drops the gossip of a chain that exceeds its rate limit, or that
+// recently passed the filter. A tx is gossiped by each validator that learns
+// of it, so each validator sees it several times; only the first is passed on.
+type Filter struct {
+	config Config
+	clock  timer.Clock
+
+	lock   sync.Mutex
+	tokens float64
+	last   time.Time
+	seen   cache.LRU
+}
+
+// NewFilter returns a filter that passes the messages [config] allows
+func NewFilter(config Config) *Filter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+	f := &Filter{
+		config: config,
+		tokens: float64(config.Burst),
+		seen:   cache.LRU{Size: config.CacheSize},
+	}
+	f.last = f.clock.Time()
+	return f
+}
+
+// Allow returns true if [msg] passes the filter. Messages that pass are
+// remembered, and count towards the rate limit.
+func (f *Filter) Allow(msg []byte) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	msgID := ids.NewID(hashing.ComputeHash256Array(msg))
+	if _, seen := f.seen.Get(msgID); seen {
+		return false
+	}
+
+	if f.config.Rate > 0 {
+		now := f.clock.Time()
+		if elapsed := now.Sub(f.last).Seconds(); elapsed > 0 {
+			f.tokens += elapsed * f.config.Rate
+			if burst := float64(f.config.Burst); f.tokens > burst {
+				f.tokens = burst
+			}
+		}
+		f.last = now
+
+		if f.tokens < 1 {
+			return false
+		}
+		f.tokens--
+	}
+
+	f.seen.Put(msgID, nil)
+	return true
+}
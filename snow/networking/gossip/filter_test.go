@@ -0,0 +1,49 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package gossip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterDedup(t *testing.T) {
+	f := NewFilter(Config{CacheSize: 2})
+
+	if !f.Allow([]byte{1}) {
+		t.Fatalf("Should have allowed the first message")
+	}
+	if f.Allow([]byte{1}) {
+		t.Fatalf("Should have dropped the repeated message")
+	}
+	if !f.Allow([]byte{2}) || !f.Allow([]byte{3}) {
+		t.Fatalf("Should have allowed new messages")
+	}
+	// The first message was evicted from the cache, so it passes again
+	if !f.Allow([]byte{1}) {
+		t.Fatalf("Should have allowed the forgotten message")
+	}
+}
+
+func TestFilterRateLimit(t *testing.T) {
+	start := time.Unix(1000, 0)
+	f := NewFilter(Config{Rate: 1, Burst: 2, CacheSize: 10})
+	f.clock.Set(start)
+	f.last = start
+
+	if !f.Allow([]byte{1}) || !f.Allow([]byte{2}) {
+		t.Fatalf("Should have allowed a burst of 2 messages")
+	}
+	if f.Allow([]byte{3}) {
+		t.Fatalf("Should have dropped the message exceeding the burst")
+	}
+	// A dropped message isn't remembered, so it passes once the rate allows
+	f.clock.Set(start.Add(time.Second))
+	if !f.Allow([]byte{3}) {
+		t.Fatalf("Should have allowed a message after a token was earned")
+	}
+	if f.Allow([]byte{4}) {
+		t.Fatalf("Should have dropped the message exceeding the rate")
+	}
+}
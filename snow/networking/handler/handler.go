@@ -9,24 +9,45 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/gossip"
+	"github.com/ava-labs/gecko/snow/networking/tracing"
 )
 
 // Handler passes incoming messages from the network to the consensus engine
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference)
+//
+// Messages are passed to the engine one at a time, with the chain's write lock
+// held. Requests that only read the chain's state are instead passed by a pool
+// of read workers, with the chain's read lock held, so they're served
+// alongside each other and the chain's read-only API calls rather than waiting
+// behind the messages queued before them.
 type Handler struct {
 	msgs    chan message
 	wg      sync.WaitGroup
 	engine  common.Engine
 	msgChan <-chan common.Message
+	gossip  *gossip.Filter // Limits the app-level gossip this chain receives
+
+	readWorkers int
+	reads       chan message  // Read-only requests, passed by the read workers
+	closing     chan struct{} // Closed once the engine is shut down
+	shutdown    bool          // Set, with the write lock held, once the engine is shut down
 }
 
-// Initialize this consensus handler
-func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message, bufferSize int) {
+// Initialize this consensus handler. [readWorkers] is the number of read-only
+// requests that may be passed to the engine at a time. If it's 0, they're
+// passed along with the other messages.
+func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message, bufferSize, readWorkers int) {
 	h.msgs = make(chan message, bufferSize)
 	h.engine = engine
 	h.msgChan = msgChan
+	h.gossip = gossip.NewFilter(gossip.DefaultConfig)
+
+	h.readWorkers = readWorkers
+	h.reads = make(chan message, bufferSize)
+	h.closing = make(chan struct{})
 
-	h.wg.Add(1)
+	h.wg.Add(1 + readWorkers)
 }
 
 // Context of this Handler
@@ -37,13 +58,22 @@ func (h *Handler) Context() *snow.Context { return h.engine.Context() }
 func (h *Handler) Dispatch() {
 	defer h.wg.Done()
 
+	for i := 0; i < h.readWorkers; i++ {
+		go h.dispatchReads()
+	}
+
 	for {
 		select {
 		case msg := <-h.msgs:
 			if !h.dispatchMsg(msg) {
 				return
 			}
-		case msg := <-h.msgChan:
+		case msg, ok := <-h.msgChan:
+			if !ok {
+				// The VM can't send any more messages
+				h.msgChan = nil
+				continue
+			}
 			if !h.dispatchMsg(message{messageType: notifyMsg, notification: msg}) {
 				return
 			}
@@ -51,6 +81,40 @@ func (h *Handler) Dispatch() {
 	}
 }
 
+// dispatchReads passes read-only requests to the consensus engine until the
+// engine is shut down
+func (h *Handler) dispatchReads() {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case msg := <-h.reads:
+			h.dispatchRead(msg)
+		case <-h.closing:
+			return
+		}
+	}
+}
+
+// Dispatch a read-only request to the consensus engine, unless it was shut
+// down
+func (h *Handler) dispatchRead(msg message) {
+	ctx := h.engine.Context()
+
+	ctx.Lock.RLock()
+	defer ctx.Lock.RUnlock()
+
+	if h.shutdown {
+		return
+	}
+
+	ctx.Log.With(msg.fields()...).Verbo("Forwarding read-only request to consensus: %s", msg)
+	if msg.traced {
+		defer ctx.Tracer.Handled(msg.traceID, ctx.ChainID, msg.validatorID, msg.messageType.String(), msg.received, ctx.Tracer.Now())
+	}
+	h.handle(msg)
+}
+
 // Dispatch a message to the consensus engine.
 // Returns false iff this consensus handler (and its associated engine) should shutdown
 // (due to receipt of a shutdown message)
@@ -60,8 +124,24 @@ func (h *Handler) dispatchMsg(msg message) bool {
 	ctx.Lock.Lock()
 	defer ctx.Lock.Unlock()
 
-	ctx.Log.Verbo("Forwarding message to consensus: %s", msg)
+	ctx.Log.With(msg.fields()...).Verbo("Forwarding message to consensus: %s", msg)
+
+	if msg.messageType == shutdownMsg {
+		h.engine.Shutdown()
+		h.shutdown = true
+		close(h.closing)
+		return false
+	}
+	if msg.traced {
+		defer ctx.Tracer.Handled(msg.traceID, ctx.ChainID, msg.validatorID, msg.messageType.String(), msg.received, ctx.Tracer.Now())
+	}
+	h.handle(msg)
+	return true
+}
 
+// handle passes [msg] to the consensus engine. The chain's lock must be held:
+// its read lock if [msg] is read-only, and its write lock otherwise.
+func (h *Handler) handle(msg message) {
 	switch msg.messageType {
 	case getAcceptedFrontierMsg:
 		h.engine.GetAcceptedFrontier(msg.validatorID, msg.requestID)
@@ -89,149 +169,268 @@ func (h *Handler) dispatchMsg(msg message) bool {
 		h.engine.QueryFailed(msg.validatorID, msg.requestID)
 	case chitsMsg:
 		h.engine.Chits(msg.validatorID, msg.requestID, msg.containerIDs)
+	case appGossipMsg:
+		h.engine.AppGossip(msg.validatorID, msg.appMsg)
+	case getStateSummaryMsg:
+		h.engine.GetStateSummary(msg.validatorID, msg.requestID)
+	case stateSummaryMsg:
+		h.engine.StateSummary(msg.validatorID, msg.requestID, msg.container)
+	case getStateSummaryFailedMsg:
+		h.engine.GetStateSummaryFailed(msg.validatorID, msg.requestID)
+	case getStateChunkMsg:
+		h.engine.GetStateChunk(msg.validatorID, msg.requestID, msg.containerID, msg.index)
+	case stateChunkMsg:
+		h.engine.StateChunk(msg.validatorID, msg.requestID, msg.container)
+	case getStateChunkFailedMsg:
+		h.engine.GetStateChunkFailed(msg.validatorID, msg.requestID)
 	case notifyMsg:
 		h.engine.Notify(msg.notification)
-	case shutdownMsg:
-		h.engine.Shutdown()
-		return false
 	}
-	return true
+}
+
+// traced stamps [msg] with the trace it belongs to, if this chain's messages
+// are traced. A response, or the failure of a request, also ends the span of
+// the request this node sent.
+func (h *Handler) traced(msg message) message {
+	ctx := h.engine.Context()
+	if ctx.Tracer == nil {
+		return msg
+	}
+
+	requester := ctx.NodeID
+	if msg.isRequest() {
+		requester = msg.validatorID
+	} else {
+		ctx.Tracer.ResponseReceived(ctx.ChainID, msg.validatorID, msg.requestID, msg.messageType.String())
+	}
+	msg.traced = true
+	msg.traceID = tracing.NewID(requester, ctx.ChainID, msg.requestID)
+	msg.received = ctx.Tracer.Now()
+	return msg
+}
+
+// read queues the read-only request [msg] for the read workers, or with the
+// other messages if there are none
+func (h *Handler) read(msg message) {
+	if h.readWorkers == 0 {
+		h.msgs <- msg
+		return
+	}
+	h.reads <- msg
 }
 
 // GetAcceptedFrontier passes a GetAcceptedFrontier message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.read(h.traced(message{
 		messageType: getAcceptedFrontierMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	}))
 }
 
 // AcceptedFrontier passes a AcceptedFrontier message received from the network
 // to the consensus engine.
 func (h *Handler) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType:  acceptedFrontierMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFrontierFailed passes a GetAcceptedFrontierFailed message received
 // from the network to the consensus engine.
 func (h *Handler) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: getAcceptedFrontierFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // GetAccepted passes a GetAccepted message received from the
 // network to the consensus engine.
 func (h *Handler) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.read(h.traced(message{
 		messageType:  getAcceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	}))
 }
 
 // Accepted passes a Accepted message received from the network to the consensus
 // engine.
 func (h *Handler) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType:  acceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFailed passes a GetAcceptedFailed message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: getAcceptedFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // Get passes a Get message received from the network to the consensus engine.
 func (h *Handler) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.read(h.traced(message{
 		messageType: getMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
-	}
+	}))
 }
 
 // Put passes a Put message received from the network to the consensus engine.
 func (h *Handler) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: putMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
 		container:   container,
-	}
+	})
 }
 
 // GetFailed passes a GetFailed message to the consensus engine.
 func (h *Handler) GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: getFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
-	}
+	})
 }
 
 // PushQuery passes a PushQuery message received from the network to the consensus engine.
 func (h *Handler) PushQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID, block []byte) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: pushQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
 		container:   block,
-	}
+	})
 }
 
 // PullQuery passes a PullQuery message received from the network to the consensus engine.
 func (h *Handler) PullQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: pullQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
-	}
+	})
 }
 
 // Chits passes a Chits message received from the network to the consensus engine.
 func (h *Handler) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType:  chitsMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: votes,
-	}
+	})
 }
 
 // QueryFailed passes a QueryFailed message received from the network to the consensus engine.
 func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.msgs <- h.traced(message{
 		messageType: queryFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
+	})
+}
+
+// AppGossip passes an AppGossip message received from the network to the
+// consensus engine, unless this chain is receiving gossip too quickly or [msg]
+// was received recently.
+func (h *Handler) AppGossip(validatorID ids.ShortID, msg []byte) {
+	if !h.gossip.Allow(msg) {
+		return
+	}
+	h.msgs <- message{
+		messageType: appGossipMsg,
+		validatorID: validatorID,
+		appMsg:      msg,
 	}
 }
 
+// GetStateSummary passes a GetStateSummary message received from the network
+// to the consensus engine.
+func (h *Handler) GetStateSummary(validatorID ids.ShortID, requestID uint32) {
+	h.read(h.traced(message{
+		messageType: getStateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	}))
+}
+
+// StateSummary passes a StateSummary message received from the network to the
+// consensus engine.
+func (h *Handler) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	h.msgs <- h.traced(message{
+		messageType: stateSummaryMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   summary,
+	})
+}
+
+// GetStateSummaryFailed passes a GetStateSummaryFailed message to the
+// consensus engine.
+func (h *Handler) GetStateSummaryFailed(validatorID ids.ShortID, requestID uint32) {
+	h.msgs <- h.traced(message{
+		messageType: getStateSummaryFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
+// GetStateChunk passes a GetStateChunk message received from the network to
+// the consensus engine.
+func (h *Handler) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	h.read(h.traced(message{
+		messageType: getStateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		containerID: summaryID,
+		index:       index,
+	}))
+}
+
+// StateChunk passes a StateChunk message received from the network to the
+// consensus engine.
+func (h *Handler) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	h.msgs <- h.traced(message{
+		messageType: stateChunkMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		container:   chunk,
+	})
+}
+
+// GetStateChunkFailed passes a GetStateChunkFailed message to the consensus
+// engine.
+func (h *Handler) GetStateChunkFailed(validatorID ids.ShortID, requestID uint32) {
+	h.msgs <- h.traced(message{
+		messageType: getStateChunkFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
+}
+
 // Shutdown shuts down the dispatcher
 func (h *Handler) Shutdown() { h.msgs <- message{messageType: shutdownMsg}; h.wg.Wait() }
 
@@ -6,9 +6,12 @@ package handler
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/tracing"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
 type msgType int
@@ -28,6 +31,13 @@ const (
 	pullQueryMsg
 	chitsMsg
 	queryFailedMsg
+	appGossipMsg
+	getStateSummaryMsg
+	stateSummaryMsg
+	getStateSummaryFailedMsg
+	getStateChunkMsg
+	stateChunkMsg
+	getStateChunkFailedMsg
 	notifyMsg
 	shutdownMsg
 )
@@ -40,6 +50,14 @@ type message struct {
 	container    []byte
 	containerIDs ids.Set
 	notification common.Message
+	appMsg       []byte
+	index        uint32 // Index of a requested state chunk
+
+	// The trace the message belongs to, and when it was received, if the
+	// chain's messages are traced
+	traced   bool
+	traceID  tracing.ID
+	received time.Time
 }
 
 func (m message) String() string {
@@ -55,6 +73,30 @@ func (m message) String() string {
 	return sb.String()
 }
 
+// fields returns the validator and request the message is about, to attach to
+// the messages logged about it
+func (m message) fields() []logging.Field {
+	switch m.messageType {
+	case notifyMsg, shutdownMsg:
+		return nil
+	default:
+		return []logging.Field{
+			{Key: "nodeID", Value: m.validatorID.String()},
+			{Key: "requestID", Value: fmt.Sprint(m.requestID)},
+		}
+	}
+}
+
+// isRequest returns true if the message requests a response from this node
+func (m message) isRequest() bool {
+	switch m.messageType {
+	case getAcceptedFrontierMsg, getAcceptedMsg, getMsg, pushQueryMsg, pullQueryMsg, getStateSummaryMsg, getStateChunkMsg:
+		return true
+	default:
+		return false
+	}
+}
+
 func (t msgType) String() string {
 	switch t {
 	case nullMsg:
@@ -85,6 +127,20 @@ func (t msgType) String() string {
 		return "Chits Message"
 	case queryFailedMsg:
 		return "Query Failed Message"
+	case appGossipMsg:
+		return "App Gossip Message"
+	case getStateSummaryMsg:
+		return "Get State Summary Message"
+	case stateSummaryMsg:
+		return "State Summary Message"
+	case getStateSummaryFailedMsg:
+		return "Get State Summary Failed Message"
+	case getStateChunkMsg:
+		return "Get State Chunk Message"
+	case stateChunkMsg:
+		return "State Chunk Message"
+	case getStateChunkFailedMsg:
+		return "Get State Chunk Failed Message"
 	case notifyMsg:
 		return "Notify Message"
 	case shutdownMsg:
@@ -0,0 +1,265 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package netsim simulates adverse network conditions, so consensus can be
+// tested against them. Messages are delayed, dropped and reordered before
+// they're sent, as if the network between this node and each peer were slow
+// or unreliable.
+package netsim
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// ReorderDelay is how much longer than the slowest message a reordered
+// message is held back, so that messages sent after it are delivered first
+const ReorderDelay = 50 * time.Millisecond
+
+var (
+	errNegativeDelay = errors.New("latency and jitter can't be negative")
+	errBadRate       = errors.New("drop and reorder rates must be in [0, 1]")
+)
+
+// Conditions of the network between this node and a peer
+type Conditions struct {
+	// Each message is delayed by Latency, plus a uniformly random duration of
+	// up to Jitter. Messages that aren't reordered are still delivered in the
+	// order they're sent.
+	Latency, Jitter time.Duration
+	// Fraction of the messages that are dropped
+	DropRate float64
+	// Fraction of the messages that are held back, so that messages sent
+	// after them are delivered first
+	ReorderRate float64
+}
+
+// Verify returns an error if the conditions aren't possible
+func (c Conditions) Verify() error {
+	switch {
+	case c.Latency < 0 || c.Jitter < 0:
+		return errNegativeDelay
+	case c.DropRate < 0 || c.DropRate > 1 || c.ReorderRate < 0 || c.ReorderRate > 1:
+		return errBadRate
+	default:
+		return nil
+	}
+}
+
+// Enabled returns true if the conditions affect messages
+func (c Conditions) Enabled() bool { return c != Conditions{} }
+
+// Sender wraps a sender, sending the messages to each peer under the network
+// conditions between this node and the peer. A message to several peers is
+// simulated separately for each of them.
+type Sender struct {
+	sender   sender.ExternalSender
+	defaults Conditions
+	clock    timer.Clock
+
+	lock sync.Mutex
+	rand *rand.Rand
+	// peer ID -> conditions, for the peers that don't have the defaults
+	peers map[[20]byte]Conditions
+	// peer ID -> when the last message to the peer, that wasn't reordered, is
+	// delivered, and a channel that's closed once it has been
+	last map[[20]byte]delivery
+}
+
+type delivery struct {
+	at   time.Time
+	done chan struct{}
+}
+
+// New returns a sender that sends messages with [sender] under the network
+// conditions [defaults]. Random decisions are seeded by [seed], so a run can be
+// repeated.
+func New(sender sender.ExternalSender, defaults Conditions, seed int64) *Sender {
+	return &Sender{
+		sender:   sender,
+		defaults: defaults,
+		rand:     rand.New(rand.NewSource(seed)),
+		peers:    make(map[[20]byte]Conditions),
+		last:     make(map[[20]byte]delivery),
+	}
+}
+
+// SetConditions of the network between this node and [peer]
+func (s *Sender) SetConditions(peer ids.ShortID, c Conditions) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.peers[peer.Key()] = c
+}
+
+// ResetConditions of the network between this node and [peer] to the defaults
+func (s *Sender) ResetConditions(peer ids.ShortID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.peers, peer.Key())
+}
+
+// GetAcceptedFrontier implements the ExternalSender interface
+func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	s.each(validatorIDs, func(vdrs ids.ShortSet) { s.sender.GetAcceptedFrontier(vdrs, chainID, requestID) })
+}
+
+// AcceptedFrontier implements the ExternalSender interface
+func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.schedule(validatorID, func() { s.sender.AcceptedFrontier(validatorID, chainID, requestID, containerIDs) })
+}
+
+// GetAccepted implements the ExternalSender interface
+func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.each(validatorIDs, func(vdrs ids.ShortSet) { s.sender.GetAccepted(vdrs, chainID, requestID, containerIDs) })
+}
+
+// Accepted implements the ExternalSender interface
+func (s *Sender) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.schedule(validatorID, func() { s.sender.Accepted(validatorID, chainID, requestID, containerIDs) })
+}
+
+// Get implements the ExternalSender interface
+func (s *Sender) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.schedule(validatorID, func() { s.sender.Get(validatorID, chainID, requestID, containerID) })
+}
+
+// Put implements the ExternalSender interface
+func (s *Sender) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.schedule(validatorID, func() { s.sender.Put(validatorID, chainID, requestID, containerID, container) })
+}
+
+// PushQuery implements the ExternalSender interface
+func (s *Sender) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.each(validatorIDs, func(vdrs ids.ShortSet) { s.sender.PushQuery(vdrs, chainID, requestID, containerID, container) })
+}
+
+// PullQuery implements the ExternalSender interface
+func (s *Sender) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.each(validatorIDs, func(vdrs ids.ShortSet) { s.sender.PullQuery(vdrs, chainID, requestID, containerID) })
+}
+
+// Chits implements the ExternalSender interface
+func (s *Sender) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set) {
+	s.schedule(validatorID, func() { s.sender.Chits(validatorID, chainID, requestID, votes) })
+}
+
+// AppGossip implements the ExternalSender interface. The peers gossip is sent
+// to aren't known, so it's sent under the default conditions.
+func (s *Sender) AppGossip(subnetID, chainID ids.ID, msg []byte) {
+	s.schedule(ids.ShortEmpty, func() { s.sender.AppGossip(subnetID, chainID, msg) })
+}
+
+// GetStateSummary implements the ExternalSender interface
+func (s *Sender) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	s.each(validatorIDs, func(vdrs ids.ShortSet) { s.sender.GetStateSummary(vdrs, chainID, requestID) })
+}
+
+// StateSummary implements the ExternalSender interface
+func (s *Sender) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	s.schedule(validatorID, func() { s.sender.StateSummary(validatorID, chainID, requestID, summary) })
+}
+
+// GetStateChunk implements the ExternalSender interface
+func (s *Sender) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32) {
+	s.schedule(validatorID, func() { s.sender.GetStateChunk(validatorID, chainID, requestID, summaryID, index) })
+}
+
+// StateChunk implements the ExternalSender interface
+func (s *Sender) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	s.schedule(validatorID, func() { s.sender.StateChunk(validatorID, chainID, requestID, chunk) })
+}
+
+// each schedules sending a message to each of [validatorIDs], by calling
+// [send] with a set of just that validator
+func (s *Sender) each(validatorIDs ids.ShortSet, send func(ids.ShortSet)) {
+	for _, validatorID := range validatorIDs.List() {
+		vdrs := ids.ShortSet{}
+		vdrs.Add(validatorID)
+		s.schedule(validatorID, func() { send(vdrs) })
+	}
+}
+
+// schedule sending a message to [peer], by calling [send], under the network
+// conditions between this node and [peer]
+func (s *Sender) schedule(peer ids.ShortID, send func()) {
+	delay, prev, done, sent := s.plan(peer)
+	if !sent {
+		return
+	}
+
+	deliver := func() {
+		if prev != nil {
+			<-prev
+		}
+		send()
+		if done != nil {
+			close(done)
+		}
+	}
+	if delay <= 0 && (prev == nil || isClosed(prev)) {
+		deliver()
+	} else {
+		time.AfterFunc(delay, deliver)
+	}
+}
+
+// plan how a message to [peer] is sent. Returns false if it's dropped.
+// Otherwise, it's delivered after [delay], once [prev] is closed if it isn't
+// nil, and then [done] is closed if it isn't nil.
+func (s *Sender) plan(peer ids.ShortID) (delay time.Duration, prev, done chan struct{}, sent bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := peer.Key()
+	c, ok := s.peers[key]
+	if !ok {
+		c = s.defaults
+	}
+
+	if c.DropRate > 0 && s.rand.Float64() < c.DropRate {
+		return 0, nil, nil, false
+	}
+	delay = c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(s.rand.Int63n(int64(c.Jitter) + 1))
+	}
+
+	if c.ReorderRate > 0 && s.rand.Float64() < c.ReorderRate {
+		// Messages sent after this one are delivered within Latency+Jitter
+		return delay + c.Latency + c.Jitter + ReorderDelay, nil, nil, true
+	}
+
+	// The message is delivered after the previous message to [peer], so the
+	// messages that aren't reordered are delivered in the order they're sent
+	now := s.clock.Time()
+	last, hasLast := s.last[key]
+	if hasLast {
+		prev = last.done
+		if last.at.After(now.Add(delay)) {
+			delay = last.at.Sub(now)
+		}
+	}
+	done = make(chan struct{})
+	s.last[key] = delivery{
+		at:   now.Add(delay),
+		done: done,
+	}
+	return delay, prev, done, true
+}
+
+func isClosed(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package netsim
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+)
+
+// Test that messages are dropped under the conditions of the peer they're sent
+// to, and sent to each peer separately
+func TestPeerConditions(t *testing.T) {
+	external := sender.ExternalSenderTest{T: t}
+	external.Default(true)
+
+	s := New(&external, Conditions{DropRate: 1}, 0)
+
+	vdr := ids.NewShortID([20]byte{1})
+	s.SetConditions(vdr, Conditions{})
+
+	queried := ids.ShortSet{}
+	external.PullQueryF = func(validatorIDs ids.ShortSet, _ ids.ID, _ uint32, _ ids.ID) {
+		if validatorIDs.Len() != 1 {
+			t.Fatalf("Query should have been sent to each peer separately")
+		}
+		queried.Union(validatorIDs)
+	}
+
+	vdrs := ids.ShortSet{}
+	vdrs.Add(vdr, ids.NewShortID([20]byte{2}))
+	s.PullQuery(vdrs, ids.Empty, 0, ids.Empty)
+
+	// Without latency, the query is sent immediately
+	if queried.Len() != 1 || !queried.Contains(vdr) {
+		t.Fatalf("Query should have been sent to only %s but was sent to %s", vdr, queried)
+	}
+
+	// Once reset to the defaults, messages to the peer are dropped as well
+	s.ResetConditions(vdr)
+	s.PullQuery(vdrs, ids.Empty, 1, ids.Empty)
+}
+
+// Test that delayed messages that aren't reordered are delivered in the order
+// they're sent
+func TestDelayKeepsOrder(t *testing.T) {
+	external := sender.ExternalSenderTest{T: t}
+	external.Default(true)
+
+	s := New(&external, Conditions{
+		Latency: time.Millisecond,
+		Jitter:  5 * time.Millisecond,
+	}, 0)
+
+	const numMsgs = 20
+	wg := sync.WaitGroup{}
+	wg.Add(numMsgs)
+	lock := sync.Mutex{}
+	received := []uint32(nil)
+	external.ChitsF = func(_ ids.ShortID, _ ids.ID, requestID uint32, _ ids.Set) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		received = append(received, requestID)
+		wg.Done()
+	}
+
+	vdr := ids.NewShortID([20]byte{1})
+	for requestID := uint32(0); requestID < numMsgs; requestID++ {
+		s.Chits(vdr, ids.Empty, requestID, ids.Set{})
+	}
+	wg.Wait()
+
+	for i, requestID := range received {
+		if requestID != uint32(i) {
+			t.Fatalf("Messages were delivered out of order: %v", received)
+		}
+	}
+}
+
+// Test that a reordered message is delivered after messages sent after it
+func TestReorder(t *testing.T) {
+	external := sender.ExternalSenderTest{T: t}
+	external.Default(true)
+
+	s := New(&external, Conditions{ReorderRate: 1}, 0)
+
+	vdr := ids.NewShortID([20]byte{1})
+	done := make(chan struct{})
+	lock := sync.Mutex{}
+	received := []uint32(nil)
+	external.GetF = func(_ ids.ShortID, _ ids.ID, requestID uint32, _ ids.ID) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		received = append(received, requestID)
+		if len(received) == 2 {
+			close(done)
+		}
+	}
+
+	s.Get(vdr, ids.Empty, 0, ids.Empty)
+	s.SetConditions(vdr, Conditions{})
+	s.Get(vdr, ids.Empty, 1, ids.Empty)
+	<-done
+
+	if received[0] != 1 || received[1] != 0 {
+		t.Fatalf("Reordered message should have been delivered last: %v", received)
+	}
+}
+
+func TestConditionsVerify(t *testing.T) {
+	if err := (Conditions{Latency: time.Second, DropRate: 0.5}).Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Conditions{Jitter: -time.Second}).Verify(); err == nil {
+		t.Fatalf("Should have errored due to the negative jitter")
+	}
+	if err := (Conditions{ReorderRate: 1.5}).Verify(); err == nil {
+		t.Fatalf("Should have errored due to the reorder rate above 1")
+	}
+}
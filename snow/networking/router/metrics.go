@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type metrics struct {
+	numChains   prometheus.Gauge
+	numDropped  prometheus.Counter
+	numHeld     prometheus.Gauge
+	numEvicted  prometheus.Counter
+	numRejected prometheus.Counter
+}
+
+// Initialize the metrics, registering them with [registerer]
+func (m *metrics) Initialize(log logging.Logger, namespace string, registerer prometheus.Registerer) {
+	m.numChains = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "chains",
+			Help:      "Number of chains messages are routed to",
+		})
+	m.numDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped",
+			Help:      "Number of messages dropped because they referenced a chain this node isn't validating",
+		})
+	m.numHeld = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "held",
+			Help:      "Number of queries held until the chains they referenced finish bootstrapping",
+		})
+	m.numEvicted = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "evicted",
+			Help:      "Number of held queries evicted to hold newer queries",
+		})
+	m.numRejected = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rejected",
+			Help:      "Number of messages dropped because they came from a node that doesn't validate the subnet of the chain they referenced",
+		})
+
+	if err := registerer.Register(m.numChains); err != nil {
+		log.Error("Failed to register chains statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numDropped); err != nil {
+		log.Error("Failed to register dropped statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numHeld); err != nil {
+		log.Error("Failed to register held statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numEvicted); err != nil {
+		log.Error("Failed to register evicted statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numRejected); err != nil {
+		log.Error("Failed to register rejected statistics due to %s", err)
+	}
+}
@@ -4,9 +4,12 @@
 package router
 
 import (
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -18,8 +21,10 @@ type Router interface {
 
 	AddChain(chain *handler.Handler)
 	RemoveChain(chainID ids.ID)
+	Bootstrapping(chainID ids.ID)
+	Bootstrapped(chainID ids.ID)
 	Shutdown()
-	Initialize(log logging.Logger, timeouts *timeout.Manager)
+	Initialize(log logging.Logger, timeouts *timeout.Manager, vdrs validators.Manager, namespace string, registerer prometheus.Registerer)
 }
 
 // ExternalRouter routes messages from the network to the
@@ -34,6 +39,11 @@ type ExternalRouter interface {
 	PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 	PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
+	AppGossip(validatorID ids.ShortID, chainID ids.ID, msg []byte)
+	GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32)
+	StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
 }
 
 // InternalRouter deals with messages internal to this node
@@ -42,4 +52,6 @@ type InternalRouter interface {
 	GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 	GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID)
 	QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
+	GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32)
 }
@@ -4,14 +4,23 @@
 package router
 
 import (
+	"fmt"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
+// DefaultMaxHeld is the number of queries held for each chain that's still
+// bootstrapping. Once a chain is holding this many, the oldest is evicted to
+// hold the next.
+const DefaultMaxHeld = 1024
+
 // ChainRouter routes incoming messages from the validator network
 // to the consensus engines that the messages are intended for.
 // Note that consensus engines are uniquely identified by the ID of the chain
@@ -21,15 +30,43 @@ type ChainRouter struct {
 	lock     sync.RWMutex
 	chains   map[[32]byte]*handler.Handler
 	timeouts *timeout.Manager
+	// The validator set of each subnet. Queries and gossip for a chain are
+	// only accepted from the validators of the chain's subnet. If nil, they're
+	// accepted from any node.
+	vdrs    validators.Manager
+	metrics metrics
+	// True once the router is shut down. Messages are then dropped.
+	closed bool
+
+	// Queries for chains that are still bootstrapping, which their engines
+	// would drop, are held until the chains finish bootstrapping
+	heldLock sync.Mutex
+	held     map[[32]byte][]heldMessage
+	maxHeld  int
+}
+
+// heldMessage is a query held for a chain that's still bootstrapping
+type heldMessage struct {
+	validatorID ids.ShortID
+	requestID   uint32
+	deliver     func(chain *handler.Handler)
 }
 
 // Initialize the router
 // When this router receives an incoming message, it cancels the timeout in [timeouts]
 // associated with the request that caused the incoming message, if applicable
-func (sr *ChainRouter) Initialize(log logging.Logger, timeouts *timeout.Manager) {
+// Queries and gossip for a chain are only routed to it if they're from a
+// validator of the chain's subnet in [vdrs]. If [vdrs] is nil, they're routed
+// regardless of which node they're from.
+// The router's metrics are registered with [registerer] under [namespace]
+func (sr *ChainRouter) Initialize(log logging.Logger, timeouts *timeout.Manager, vdrs validators.Manager, namespace string, registerer prometheus.Registerer) {
 	sr.log = log
 	sr.chains = make(map[[32]byte]*handler.Handler)
 	sr.timeouts = timeouts
+	sr.vdrs = vdrs
+	sr.held = make(map[[32]byte][]heldMessage)
+	sr.maxHeld = DefaultMaxHeld
+	sr.metrics.Initialize(log, namespace, registerer)
 }
 
 // AddChain registers the specified chain so that incoming
@@ -38,7 +75,91 @@ func (sr *ChainRouter) AddChain(chain *handler.Handler) {
 	sr.lock.Lock()
 	defer sr.lock.Unlock()
 
+	if sr.closed {
+		// The chain's handler is dispatched after it's added
+		go chain.Shutdown()
+		return
+	}
 	sr.chains[chain.Context().ChainID.Key()] = chain
+	sr.metrics.numChains.Set(float64(len(sr.chains)))
+}
+
+// Bootstrapping marks the chain [chainID] as bootstrapping. Until Bootstrapped
+// is called with [chainID], the queries routed to the chain are held rather
+// than passed to its engine, which would drop them. This should be called
+// before the chain is added.
+func (sr *ChainRouter) Bootstrapping(chainID ids.ID) {
+	sr.heldLock.Lock()
+	defer sr.heldLock.Unlock()
+
+	if _, exists := sr.held[chainID.Key()]; !exists {
+		sr.held[chainID.Key()] = nil
+	}
+}
+
+// Bootstrapped marks the chain [chainID] as finished bootstrapping. The queries
+// held for the chain are passed to its engine, oldest first.
+func (sr *ChainRouter) Bootstrapped(chainID ids.ID) {
+	sr.heldLock.Lock()
+	msgs, exists := sr.held[chainID.Key()]
+	delete(sr.held, chainID.Key())
+	sr.metrics.numHeld.Sub(float64(len(msgs)))
+	sr.heldLock.Unlock()
+
+	if !exists || len(msgs) == 0 {
+		return
+	}
+
+	// This is called by the chain's engine as it finishes bootstrapping, so
+	// the held queries are passed to the chain by another goroutine, as the
+	// chain's queue may be full until the engine returns.
+	go sr.log.RecoverAndPanic(func() {
+		sr.lock.RLock()
+		defer sr.lock.RUnlock()
+
+		chain, exists := sr.chains[chainID.Key()]
+		if !exists {
+			for _, msg := range msgs {
+				sr.dropped(msg.validatorID, chainID, msg.requestID)
+			}
+			return
+		}
+		for _, msg := range msgs {
+			msg.deliver(chain)
+		}
+	})
+}
+
+// hold holds the query from [validatorID] for request [requestID] if the chain
+// [chainID] is bootstrapping. [deliver] passes the query to the chain once it
+// has finished. Returns true if the query was held.
+func (sr *ChainRouter) hold(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deliver func(chain *handler.Handler)) bool {
+	sr.heldLock.Lock()
+	defer sr.heldLock.Unlock()
+
+	msgs, bootstrapping := sr.held[chainID.Key()]
+	if !bootstrapping {
+		return false
+	}
+
+	if len(msgs) >= sr.maxHeld {
+		evicted := msgs[0]
+		msgs = msgs[1:]
+		sr.log.With(
+			logging.Field{Key: "chainID", Value: chainID.String()},
+			logging.Field{Key: "nodeID", Value: evicted.validatorID.String()},
+			logging.Field{Key: "requestID", Value: fmt.Sprint(evicted.requestID)},
+		).Debug("Evicted query held for a bootstrapping chain")
+		sr.metrics.numHeld.Dec()
+		sr.metrics.numEvicted.Inc()
+	}
+	sr.held[chainID.Key()] = append(msgs, heldMessage{
+		validatorID: validatorID,
+		requestID:   requestID,
+		deliver:     deliver,
+	})
+	sr.metrics.numHeld.Inc()
+	return true
 }
 
 // RemoveChain removes the specified chain so that incoming
@@ -50,6 +171,12 @@ func (sr *ChainRouter) RemoveChain(chainID ids.ID) {
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Shutdown()
 		delete(sr.chains, chainID.Key())
+		sr.metrics.numChains.Set(float64(len(sr.chains)))
+
+		sr.heldLock.Lock()
+		sr.metrics.numHeld.Sub(float64(len(sr.held[chainID.Key()])))
+		delete(sr.held, chainID.Key())
+		sr.heldLock.Unlock()
 	} else {
 		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
 	}
@@ -65,7 +192,7 @@ func (sr *ChainRouter) GetAcceptedFrontier(validatorID ids.ShortID, chainID ids.
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.GetAcceptedFrontier(validatorID, requestID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -80,7 +207,7 @@ func (sr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.AcceptedFrontier(validatorID, requestID, containerIDs)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -95,7 +222,7 @@ func (sr *ChainRouter) GetAcceptedFrontierFailed(validatorID ids.ShortID, chainI
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.GetAcceptedFrontierFailed(validatorID, requestID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -109,7 +236,7 @@ func (sr *ChainRouter) GetAccepted(validatorID ids.ShortID, chainID ids.ID, requ
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.GetAccepted(validatorID, requestID, containerIDs)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -124,7 +251,7 @@ func (sr *ChainRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, request
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Accepted(validatorID, requestID, containerIDs)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -139,7 +266,7 @@ func (sr *ChainRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.GetAcceptedFailed(validatorID, requestID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -152,7 +279,7 @@ func (sr *ChainRouter) Get(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Get(validatorID, requestID, containerID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -168,7 +295,7 @@ func (sr *ChainRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Put(validatorID, requestID, containerID, container)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -182,33 +309,41 @@ func (sr *ChainRouter) GetFailed(validatorID ids.ShortID, chainID ids.ID, reques
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.GetFailed(validatorID, requestID, containerID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
 // PushQuery routes an incoming PushQuery request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
+// If the chain is bootstrapping, the request is held until it has finished.
 func (sr *ChainRouter) PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	if chain, exists := sr.chains[chainID.Key()]; exists {
-		chain.PushQuery(validatorID, requestID, containerID, container)
-	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	deliver := func(chain *handler.Handler) { chain.PushQuery(validatorID, requestID, containerID, container) }
+	if chain, exists := sr.chains[chainID.Key()]; !exists {
+		sr.dropped(validatorID, chainID, requestID)
+	} else if !sr.validates(validatorID, chain) {
+		sr.rejected(validatorID, chain, requestID)
+	} else if !sr.hold(validatorID, chainID, requestID, deliver) {
+		deliver(chain)
 	}
 }
 
 // PullQuery routes an incoming PullQuery request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
+// If the chain is bootstrapping, the request is held until it has finished.
 func (sr *ChainRouter) PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	if chain, exists := sr.chains[chainID.Key()]; exists {
-		chain.PullQuery(validatorID, requestID, containerID)
-	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	deliver := func(chain *handler.Handler) { chain.PullQuery(validatorID, requestID, containerID) }
+	if chain, exists := sr.chains[chainID.Key()]; !exists {
+		sr.dropped(validatorID, chainID, requestID)
+	} else if !sr.validates(validatorID, chain) {
+		sr.rejected(validatorID, chain, requestID)
+	} else if !sr.hold(validatorID, chainID, requestID, deliver) {
+		deliver(chain)
 	}
 }
 
@@ -223,7 +358,7 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Chits(validatorID, requestID, votes)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
@@ -237,20 +372,161 @@ func (sr *ChainRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requ
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.QueryFailed(validatorID, requestID)
 	} else {
-		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+		sr.dropped(validatorID, chainID, requestID)
 	}
 }
 
-// Shutdown shuts down this router
-func (sr *ChainRouter) Shutdown() {
+// AppGossip routes an incoming AppGossip message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID].
+// Gossip isn't a response, so there's no timeout to cancel.
+func (sr *ChainRouter) AppGossip(validatorID ids.ShortID, chainID ids.ID, msg []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; !exists {
+		sr.dropped(validatorID, chainID, 0)
+	} else if !sr.validates(validatorID, chain) {
+		sr.rejected(validatorID, chain, 0)
+	} else {
+		chain.AppGossip(validatorID, msg)
+	}
+}
+
+// GetStateSummary routes an incoming GetStateSummary request from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummary(validatorID, requestID)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
+}
+
+// StateSummary routes an incoming StateSummary message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.StateSummary(validatorID, requestID, summary)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
+}
+
+// GetStateSummaryFailed routes an incoming GetStateSummaryFailed message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateSummaryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateSummaryFailed(validatorID, requestID)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
+}
+
+// GetStateChunk routes an incoming GetStateChunk request from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32) {
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	sr.shutdown()
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateChunk(validatorID, requestID, summaryID, index)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
 }
 
-func (sr *ChainRouter) shutdown() {
-	for _, chain := range sr.chains {
+// StateChunk routes an incoming StateChunk message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.StateChunk(validatorID, requestID, chunk)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
+}
+
+// GetStateChunkFailed routes an incoming GetStateChunkFailed message from the validator with ID
+// [validatorID] to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetStateChunkFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetStateChunkFailed(validatorID, requestID)
+	} else {
+		sr.dropped(validatorID, chainID, requestID)
+	}
+}
+
+// dropped logs that the message from [validatorID] for request [requestID] was
+// dropped because this validator isn't validating the chain [chainID]
+func (sr *ChainRouter) dropped(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.log.With(
+		logging.Field{Key: "chainID", Value: chainID.String()},
+		logging.Field{Key: "nodeID", Value: validatorID.String()},
+		logging.Field{Key: "requestID", Value: fmt.Sprint(requestID)},
+	).Warn("Message referenced a chain this validator is not validating")
+	sr.metrics.numDropped.Inc()
+}
+
+// validates returns true if the node [validatorID] validates the subnet of
+// [chain], or if the router doesn't know the validators of subnets
+func (sr *ChainRouter) validates(validatorID ids.ShortID, chain *handler.Handler) bool {
+	if sr.vdrs == nil {
+		return true
+	}
+	vdrs, exists := sr.vdrs.GetValidatorSet(chain.Context().SubnetID)
+	return exists && vdrs.Contains(validatorID)
+}
+
+// rejected logs that the message from [validatorID] for request [requestID]
+// was dropped because [validatorID] doesn't validate the subnet of [chain]
+func (sr *ChainRouter) rejected(validatorID ids.ShortID, chain *handler.Handler, requestID uint32) {
+	ctx := chain.Context()
+	sr.log.With(
+		logging.Field{Key: "chainID", Value: ctx.ChainID.String()},
+		logging.Field{Key: "subnetID", Value: ctx.SubnetID.String()},
+		logging.Field{Key: "nodeID", Value: validatorID.String()},
+		logging.Field{Key: "requestID", Value: fmt.Sprint(requestID)},
+	).Debug("Message came from a node that doesn't validate the chain's subnet")
+	sr.metrics.numRejected.Inc()
+}
+
+// Shutdown shuts down this router. Messages already queued for each chain are
+// handled before its engine is shut down, and later messages are dropped.
+// Returns once every chain has shut down.
+func (sr *ChainRouter) Shutdown() {
+	sr.lock.Lock()
+	chains := sr.chains
+	sr.chains = make(map[[32]byte]*handler.Handler)
+	sr.closed = true
+	sr.metrics.numChains.Set(0)
+	sr.lock.Unlock()
+
+	sr.heldLock.Lock()
+	sr.held = make(map[[32]byte][]heldMessage)
+	sr.metrics.numHeld.Set(0)
+	sr.heldLock.Unlock()
+
+	// Chains are shut down without the lock held, as handling their queued
+	// messages may route messages
+	for _, chain := range chains {
 		chain.Shutdown()
 	}
 }
@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// Test that queries for a bootstrapping chain are held until it finishes
+// bootstrapping, and that the oldest are evicted once too many are held
+func TestHoldQueriesWhileBootstrapping(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(time.Hour)
+
+	router := ChainRouter{}
+	router.Initialize(logging.NoLog{}, &tm, nil, "", prometheus.NewRegistry())
+	router.maxHeld = 2
+
+	ctx := snow.DefaultContextTest()
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+	engine.CantShutdown = false
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	wg := sync.WaitGroup{}
+	lock := sync.Mutex{}
+	delivered := []uint32(nil)
+	engine.PullQueryF = func(_ ids.ShortID, requestID uint32, _ ids.ID) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		delivered = append(delivered, requestID)
+		wg.Done()
+	}
+
+	handler := handler.Handler{}
+	handler.Initialize(&engine, nil, 1, 0)
+	go handler.Dispatch()
+
+	router.Bootstrapping(ctx.ChainID)
+	router.AddChain(&handler)
+
+	vdr := ids.NewShortID([20]byte{1})
+	for requestID := uint32(0); requestID < 3; requestID++ {
+		router.PullQuery(vdr, ctx.ChainID, requestID, ids.Empty)
+	}
+
+	lock.Lock()
+	if len(delivered) != 0 {
+		t.Fatalf("Queries should have been held while the chain is bootstrapping")
+	}
+	lock.Unlock()
+
+	wg.Add(2)
+	router.Bootstrapped(ctx.ChainID)
+	wg.Wait()
+
+	lock.Lock()
+	if len(delivered) != 2 || delivered[0] != 1 || delivered[1] != 2 {
+		t.Fatalf("Expected the newest queries, 1 and 2, to be delivered but got %v", delivered)
+	}
+	lock.Unlock()
+
+	// Once bootstrapped, queries are passed to the chain immediately
+	wg.Add(1)
+	router.PullQuery(vdr, ctx.ChainID, 3, ids.Empty)
+	wg.Wait()
+
+	router.Shutdown()
+}
+
+// Test that queries are only routed to a chain from validators of its subnet
+func TestRejectQueriesFromOtherSubnets(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(time.Hour)
+
+	ctx := snow.DefaultContextTest()
+	ctx.SubnetID = ids.NewID([32]byte{1})
+
+	vdr := ids.NewShortID([20]byte{1})
+	nonVdr := ids.NewShortID([20]byte{2})
+	subnetVdrs := validators.NewSet()
+	subnetVdrs.Add(validators.NewValidator(vdr, 1))
+	otherVdrs := validators.NewSet()
+	otherVdrs.Add(validators.NewValidator(nonVdr, 1))
+	vdrs := validators.NewManager()
+	vdrs.PutValidatorSet(ctx.SubnetID, subnetVdrs)
+	vdrs.PutValidatorSet(ids.Empty, otherVdrs)
+
+	router := ChainRouter{}
+	router.Initialize(logging.NoLog{}, &tm, vdrs, "", prometheus.NewRegistry())
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+	engine.CantShutdown = false
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	wg := sync.WaitGroup{}
+	queried := ids.ShortSet{}
+	engine.PullQueryF = func(validatorID ids.ShortID, _ uint32, _ ids.ID) {
+		queried.Add(validatorID)
+		wg.Done()
+	}
+
+	handler := handler.Handler{}
+	handler.Initialize(&engine, nil, 1, 0)
+	go handler.Dispatch()
+
+	router.AddChain(&handler)
+
+	// Messages are handled in order, so the first query has been dropped by
+	// the time the second is handled
+	wg.Add(1)
+	router.PullQuery(nonVdr, ctx.ChainID, 0, ids.Empty)
+	router.PullQuery(vdr, ctx.ChainID, 1, ids.Empty)
+	wg.Wait()
+
+	router.Shutdown()
+
+	if queried.Contains(nonVdr) {
+		t.Fatalf("Query from a node that doesn't validate the chain's subnet should have been dropped")
+	}
+	if !queried.Contains(vdr) {
+		t.Fatalf("Query from a validator of the chain's subnet should have been routed")
+	}
+}
@@ -20,4 +20,13 @@ type ExternalSender interface {
 	PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 	PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
+
+	// AppGossip sends [msg] to a sample of the validators of the subnet
+	// [subnetID], which validates the chain [chainID]
+	AppGossip(subnetID, chainID ids.ID, msg []byte)
+
+	GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32)
+	StateSummary(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetStateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32)
+	StateChunk(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
 }
@@ -6,6 +6,7 @@ package sender
 import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/networking/gossip"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 )
@@ -16,6 +17,7 @@ type Sender struct {
 	sender   ExternalSender // Actually does the sending over the network
 	router   router.Router
 	timeouts *timeout.Manager
+	gossip   *gossip.Filter // Limits the app-level gossip of this chain
 }
 
 // Initialize this sender
@@ -24,6 +26,7 @@ func (s *Sender) Initialize(ctx *snow.Context, sender ExternalSender, router rou
 	s.sender = sender
 	s.router = router
 	s.timeouts = timeouts
+	s.gossip = gossip.NewFilter(gossip.DefaultConfig)
 }
 
 // Context of this sender
@@ -31,6 +34,7 @@ func (s *Sender) Context() *snow.Context { return s.ctx }
 
 // GetAcceptedFrontier ...
 func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32) {
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Get Accepted Frontier Message", validatorIDs.List()...)
 	if validatorIDs.Contains(s.ctx.NodeID) {
 		validatorIDs.Remove(s.ctx.NodeID)
 		go s.router.GetAcceptedFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID)
@@ -47,6 +51,7 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 
 // AcceptedFrontier ...
 func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "Accepted Frontier Message")
 	if validatorID.Equals(s.ctx.NodeID) {
 		go s.router.AcceptedFrontier(validatorID, s.ctx.ChainID, requestID, containerIDs)
 		return
@@ -56,6 +61,7 @@ func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, con
 
 // GetAccepted ...
 func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, containerIDs ids.Set) {
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Get Accepted Message", validatorIDs.List()...)
 	if validatorIDs.Contains(s.ctx.NodeID) {
 		validatorIDs.Remove(s.ctx.NodeID)
 		go s.router.GetAccepted(s.ctx.NodeID, s.ctx.ChainID, requestID, containerIDs)
@@ -72,6 +78,7 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 
 // Accepted ...
 func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "Accepted Message")
 	if validatorID.Equals(s.ctx.NodeID) {
 		go s.router.Accepted(validatorID, s.ctx.ChainID, requestID, containerIDs)
 		return
@@ -85,6 +92,7 @@ func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerID
 // specified container.
 func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
 	s.ctx.Log.Verbo("Sending Get to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Get Message", validatorID)
 	// Add a timeout -- if we don't get a response before the timeout expires,
 	// send this consensus engine a GetFailed message
 	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
@@ -99,6 +107,7 @@ func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.
 // the contents of the specified container.
 func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Sending Put to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "Put Message")
 	s.sender.Put(validatorID, s.ctx.ChainID, requestID, containerID, container)
 }
 
@@ -108,6 +117,7 @@ func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.
 // their preferred frontier given the existence of the specified container.
 func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Sending PushQuery to validators %v. RequestID: %d. ContainerID: %s", validatorIDs, requestID, containerID)
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Push Query Message", validatorIDs.List()...)
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
 	if validatorIDs.Contains(s.ctx.NodeID) { // One of the validators in [validatorIDs] was myself
@@ -133,6 +143,7 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 // their preferred frontier.
 func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID) {
 	s.ctx.Log.Verbo("Sending PullQuery. RequestID: %d. ContainerID: %s", requestID, containerID)
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Pull Query Message", validatorIDs.List()...)
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
 	if validatorIDs.Contains(s.ctx.NodeID) { // One of the validators in [validatorIDs] was myself
@@ -155,6 +166,7 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 // Chits sends chits
 func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set) {
 	s.ctx.Log.Verbo("Sending Chits to validator %s. RequestID: %d. Votes: %s", validatorID, requestID, votes)
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "Chits Message")
 	// If [validatorID] is myself, send this message directly
 	// to my own router rather than sending it over the network
 	if validatorID.Equals(s.ctx.NodeID) {
@@ -163,3 +175,72 @@ func (s *Sender) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set)
 	}
 	s.sender.Chits(validatorID, s.ctx.ChainID, requestID, votes)
 }
+
+// AppGossip sends an app-level message to a sample of the validators of this
+// chain's subnet, unless this chain is gossiping too quickly or [msg] was
+// gossiped recently.
+func (s *Sender) AppGossip(msg []byte) {
+	if !s.gossip.Allow(msg) {
+		s.ctx.Log.Verbo("Dropping AppGossip. Message: %x", msg)
+		return
+	}
+	s.ctx.Log.Verbo("Sending AppGossip. Message: %x", msg)
+	s.sender.AppGossip(s.ctx.SubnetID, s.ctx.ChainID, msg)
+}
+
+// GetStateSummary requests a summary of the recent state of the chain from
+// each of [validatorIDs]
+func (s *Sender) GetStateSummary(validatorIDs ids.ShortSet, requestID uint32) {
+	s.ctx.Log.Verbo("Sending GetStateSummary to validators %v. RequestID: %d", validatorIDs, requestID)
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Get State Summary Message", validatorIDs.List()...)
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		go s.router.GetStateSummary(s.ctx.NodeID, s.ctx.ChainID, requestID)
+	}
+	validatorList := validatorIDs.List()
+	for _, validatorID := range validatorList {
+		vID := validatorID
+		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.router.GetStateSummaryFailed(vID, s.ctx.ChainID, requestID)
+		})
+	}
+	s.sender.GetStateSummary(validatorIDs, s.ctx.ChainID, requestID)
+}
+
+// StateSummary responds to a GetStateSummary message with a summary of the
+// recent state of the chain
+func (s *Sender) StateSummary(validatorID ids.ShortID, requestID uint32, summary []byte) {
+	s.ctx.Log.Verbo("Sending StateSummary to validator %s. RequestID: %d", validatorID, requestID)
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "State Summary Message")
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+		return
+	}
+	s.sender.StateSummary(validatorID, s.ctx.ChainID, requestID, summary)
+}
+
+// GetStateChunk requests the chunk with index [index] of the state summarized
+// by the summary with ID [summaryID] from the specified validator
+func (s *Sender) GetStateChunk(validatorID ids.ShortID, requestID uint32, summaryID ids.ID, index uint32) {
+	s.ctx.Log.Verbo("Sending GetStateChunk to validator %s. RequestID: %d. SummaryID: %s. Index: %d", validatorID, requestID, summaryID, index)
+	s.ctx.Tracer.RequestSent(s.ctx.ChainID, requestID, "Get State Chunk Message", validatorID)
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.GetStateChunk(validatorID, s.ctx.ChainID, requestID, summaryID, index)
+		return
+	}
+	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.router.GetStateChunkFailed(validatorID, s.ctx.ChainID, requestID)
+	})
+	s.sender.GetStateChunk(validatorID, s.ctx.ChainID, requestID, summaryID, index)
+}
+
+// StateChunk responds to a GetStateChunk message with the requested chunk
+func (s *Sender) StateChunk(validatorID ids.ShortID, requestID uint32, chunk []byte) {
+	s.ctx.Log.Verbo("Sending StateChunk to validator %s. RequestID: %d", validatorID, requestID)
+	s.ctx.Tracer.ResponseSent(s.ctx.ChainID, validatorID, requestID, "State Chunk Message")
+	if validatorID.Equals(s.ctx.NodeID) {
+		go s.router.StateChunk(validatorID, s.ctx.ChainID, requestID, chunk)
+		return
+	}
+	s.sender.StateChunk(validatorID, s.ctx.ChainID, requestID, chunk)
+}
@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
@@ -23,7 +25,7 @@ func TestTimeout(t *testing.T) {
 	go tm.Dispatch()
 
 	router := router.ChainRouter{}
-	router.Initialize(logging.NoLog{}, &tm)
+	router.Initialize(logging.NoLog{}, &tm, nil, "", prometheus.NewRegistry())
 
 	sender := Sender{}
 	sender.Initialize(snow.DefaultContextTest(), &ExternalSenderTest{}, &router, &tm)
@@ -43,7 +45,7 @@ func TestTimeout(t *testing.T) {
 	}
 
 	handler := handler.Handler{}
-	handler.Initialize(&engine, nil, 1)
+	handler.Initialize(&engine, nil, 1, 0)
 	go handler.Dispatch()
 
 	router.AddChain(&handler)
@@ -60,3 +62,72 @@ func TestTimeout(t *testing.T) {
 		t.Fatalf("Timeouts should have fired")
 	}
 }
+
+func TestAppGossipFilter(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize(time.Millisecond)
+
+	ctx := snow.DefaultContextTest()
+	externalSender := ExternalSenderTest{T: t}
+	externalSender.Default(true)
+
+	sender := Sender{}
+	sender.Initialize(ctx, &externalSender, &router.ChainRouter{}, &tm)
+
+	gossiped := 0
+	externalSender.AppGossipF = func(subnetID, chainID ids.ID, msg []byte) {
+		if !subnetID.Equals(ctx.SubnetID) {
+			t.Fatalf("Gossiped to the wrong subnet")
+		}
+		if !chainID.Equals(ctx.ChainID) {
+			t.Fatalf("Gossiped on the wrong chain")
+		}
+		gossiped++
+	}
+
+	sender.AppGossip([]byte{1})
+	sender.AppGossip([]byte{1})
+	sender.AppGossip([]byte{2})
+
+	if gossiped != 2 {
+		t.Fatalf("Should have gossiped 2 distinct messages but gossiped %d", gossiped)
+	}
+}
+
+func TestReadWorkers(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+
+	engine.ContextF = func() *snow.Context { return ctx }
+	engine.ShutdownF = func() {}
+
+	// Each Get waits for the other, so they're only both served if they're
+	// served concurrently
+	started := sync.WaitGroup{}
+	started.Add(2)
+	served := make(chan ids.ID, 2)
+	engine.GetF = func(_ ids.ShortID, _ uint32, containerID ids.ID) {
+		started.Done()
+		started.Wait()
+		served <- containerID
+	}
+
+	handler := handler.Handler{}
+	handler.Initialize(&engine, nil, 2, 2)
+	go handler.Dispatch()
+
+	handler.Get(ids.ShortEmpty, 0, ids.Empty.Prefix(0))
+	handler.Get(ids.ShortEmpty, 1, ids.Empty.Prefix(1))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-served:
+		case <-time.After(time.Second):
+			t.Fatalf("Read-only requests should have been served concurrently")
+		}
+	}
+
+	handler.Shutdown()
+}
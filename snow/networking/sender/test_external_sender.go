@@ -17,7 +17,10 @@ type ExternalSenderTest struct {
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
 	CantGet, CantPut,
-	CantPullQuery, CantPushQuery, CantChits bool
+	CantPullQuery, CantPushQuery, CantChits,
+	CantAppGossip,
+	CantGetStateSummary, CantStateSummary,
+	CantGetStateChunk, CantStateChunk bool
 
 	GetAcceptedFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32)
 	AcceptedFrontierF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
@@ -28,6 +31,11 @@ type ExternalSenderTest struct {
 	PushQueryF           func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 	PullQueryF           func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID)
 	ChitsF               func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
+	AppGossipF           func(subnetID, chainID ids.ID, msg []byte)
+	GetStateSummaryF     func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32)
+	StateSummaryF        func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summary []byte)
+	GetStateChunkF       func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32)
+	StateChunkF          func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte)
 }
 
 // Default set the default callable value to [cant]
@@ -41,6 +49,11 @@ func (s *ExternalSenderTest) Default(cant bool) {
 	s.CantPullQuery = cant
 	s.CantPushQuery = cant
 	s.CantChits = cant
+	s.CantAppGossip = cant
+	s.CantGetStateSummary = cant
+	s.CantStateSummary = cant
+	s.CantGetStateChunk = cant
+	s.CantStateChunk = cant
 }
 
 // GetAcceptedFrontier calls GetAcceptedFrontierF if it was initialized. If it
@@ -159,3 +172,68 @@ func (s *ExternalSenderTest) Chits(vdr ids.ShortID, chainID ids.ID, requestID ui
 		s.B.Fatalf("Unexpectedly called Chits")
 	}
 }
+
+// AppGossip calls AppGossipF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) AppGossip(subnetID, chainID ids.ID, msg []byte) {
+	if s.AppGossipF != nil {
+		s.AppGossipF(subnetID, chainID, msg)
+	} else if s.CantAppGossip && s.T != nil {
+		s.T.Fatalf("Unexpectedly called AppGossip")
+	} else if s.CantAppGossip && s.B != nil {
+		s.B.Fatalf("Unexpectedly called AppGossip")
+	}
+}
+
+// GetStateSummary calls GetStateSummaryF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) GetStateSummary(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	if s.GetStateSummaryF != nil {
+		s.GetStateSummaryF(validatorIDs, chainID, requestID)
+	} else if s.CantGetStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateSummary")
+	} else if s.CantGetStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GetStateSummary")
+	}
+}
+
+// StateSummary calls StateSummaryF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) StateSummary(vdr ids.ShortID, chainID ids.ID, requestID uint32, summary []byte) {
+	if s.StateSummaryF != nil {
+		s.StateSummaryF(vdr, chainID, requestID, summary)
+	} else if s.CantStateSummary && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateSummary")
+	} else if s.CantStateSummary && s.B != nil {
+		s.B.Fatalf("Unexpectedly called StateSummary")
+	}
+}
+
+// GetStateChunk calls GetStateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) GetStateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, summaryID ids.ID, index uint32) {
+	if s.GetStateChunkF != nil {
+		s.GetStateChunkF(vdr, chainID, requestID, summaryID, index)
+	} else if s.CantGetStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetStateChunk")
+	} else if s.CantGetStateChunk && s.B != nil {
+		s.B.Fatalf("Unexpectedly called GetStateChunk")
+	}
+}
+
+// StateChunk calls StateChunkF if it was initialized. If it wasn't initialized
+// and this function shouldn't be called and testing was initialized, then
+// testing will fail.
+func (s *ExternalSenderTest) StateChunk(vdr ids.ShortID, chainID ids.ID, requestID uint32, chunk []byte) {
+	if s.StateChunkF != nil {
+		s.StateChunkF(vdr, chainID, requestID, chunk)
+	} else if s.CantStateChunk && s.T != nil {
+		s.T.Fatalf("Unexpectedly called StateChunk")
+	} else if s.CantStateChunk && s.B != nil {
+		s.B.Fatalf("Unexpectedly called StateChunk")
+	}
+}
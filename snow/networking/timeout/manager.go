@@ -12,17 +12,38 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
-// Manager registers and fires timeouts for the snow API.
-type Manager struct{ tm timer.TimeoutManager }
+// Manager registers and fires timeouts for the snow API. The timeout adapts to
+// how quickly requests are answered.
+type Manager struct{ tm timer.AdaptiveTimeoutManager }
 
-// Initialize this timeout manager.
+// Initialize this timeout manager with a timeout that doesn't adapt.
 //
 // External requests are requests that depend on other nodes to perform an
 // action. Internal requests are requests that only exist inside this node.
 //
 // [duration] is the amount of time to allow for external requests
-// before the request times out.
-func (m *Manager) Initialize(duration time.Duration) { m.tm.Initialize(duration) }
+// before the request times out. It can't be negative.
+func (m *Manager) Initialize(duration time.Duration) {
+	err := m.InitializeAdaptive(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:    duration,
+		MinimumTimeout:    duration,
+		MaximumTimeout:    duration,
+		TimeoutMultiplier: 1,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// InitializeAdaptive initializes this timeout manager with a timeout that
+// adapts within the bounds of [config]
+func (m *Manager) InitializeAdaptive(config *timer.AdaptiveTimeoutConfig) error {
+	return m.tm.Initialize(config)
+}
+
+// CurrentTimeout returns the time a request that's registered now is allowed
+// before it times out
+func (m *Manager) CurrentTimeout() time.Duration { return m.tm.CurrentTimeout() }
 
 // Dispatch ...
 func (m *Manager) Dispatch() { m.tm.Dispatch() }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 func TestManagerFire(t *testing.T) {
@@ -46,3 +47,32 @@ func TestManagerCancel(t *testing.T) {
 		t.Fatalf("Should have cancelled the function")
 	}
 }
+
+func TestManagerAdapts(t *testing.T) {
+	manager := Manager{}
+	if err := manager.InitializeAdaptive(&timer.AdaptiveTimeoutConfig{
+		InitialTimeout:    time.Millisecond,
+		MinimumTimeout:    time.Millisecond,
+		MaximumTimeout:    time.Second,
+		TimeoutMultiplier: 2,
+		TimeoutReduction:  time.Millisecond,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	go manager.Dispatch()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0, wg.Done)
+	wg.Wait()
+
+	if timeout := manager.CurrentTimeout(); timeout != 2*time.Millisecond {
+		t.Fatalf("Timeout should have doubled to %s after a request timed out, but is %s", 2*time.Millisecond, timeout)
+	}
+
+	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 1, func() {})
+	manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 1)
+	if timeout := manager.CurrentTimeout(); timeout != time.Millisecond {
+		t.Fatalf("Timeout should have been reduced to %s after a request was answered, but is %s", time.Millisecond, timeout)
+	}
+}
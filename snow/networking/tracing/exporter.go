@@ -0,0 +1,26 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// LogExporter exports spans by logging them, with the span's fields attached
+// to the log message so they can be parsed out of a structured log
+type LogExporter struct{ Log logging.Logger }
+
+// Export implements the Exporter interface
+func (e LogExporter) Export(span Span) {
+	e.Log.With(
+		logging.Field{Key: "trace", Value: span.Trace.String()},
+		logging.Field{Key: "chainID", Value: span.Chain.String()},
+		logging.Field{Key: "nodeID", Value: span.Node.String()},
+		logging.Field{Key: "peerID", Value: span.Peer.String()},
+		logging.Field{Key: "start", Value: fmt.Sprint(span.Start.UnixNano())},
+		logging.Field{Key: "duration", Value: span.End.Sub(span.Start).String()},
+	).Info("Span %s", span.Name)
+}
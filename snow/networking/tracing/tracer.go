@@ -0,0 +1,171 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package tracing traces the messages a chain sends and handles, so the
+// latency of a request, such as a query, can be broken down across the nodes
+// it went through.
+//
+// A request and the responses to it are traced under the same ID, which each
+// node derives from the request rather than sending it along: the ID of the
+// node that sent the request, the chain and the request's ID. Joining the
+// spans the nodes export under an ID reconstructs the request's trace.
+package tracing
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// IDLen is the number of bytes in a trace ID
+const IDLen = 8
+
+// ID of a trace
+type ID [IDLen]byte
+
+// NewID returns the ID of the trace of the request with ID [requestID], sent
+// on the chain [chainID] by the node [requester]
+func NewID(requester ids.ShortID, chainID ids.ID, requestID uint32) ID {
+	p := wrappers.Packer{Bytes: make([]byte, hashing.AddrLen+hashing.HashLen+wrappers.IntLen)}
+	p.PackFixedBytes(requester.Bytes())
+	p.PackFixedBytes(chainID.Bytes())
+	p.PackInt(requestID)
+
+	id := ID{}
+	copy(id[:], hashing.ComputeHash256(p.Bytes))
+	return id
+}
+
+func (id ID) String() string { return hex.EncodeToString(id[:]) }
+
+// Span is something a node did for a request
+type Span struct {
+	Trace ID
+	Chain ids.ID
+	// The node the span happened on, and the node it sent a message to or
+	// handled a message from
+	Node, Peer ids.ShortID
+	// Name of the span, such as "Push Query Message handled"
+	Name string
+	// When the span started and ended. A span that only marks when something
+	// happened, such as a message being sent, starts when it ends.
+	Start, End time.Time
+}
+
+// Exporter exports the spans of traces, such as to a log or to a collector
+type Exporter interface {
+	Export(Span)
+}
+
+// Tracer records the spans of the requests a node sends and handles. A nil
+// Tracer traces nothing, so its methods may be called whether or not tracing
+// is enabled.
+type Tracer struct {
+	nodeID   ids.ShortID
+	exporter Exporter
+	clock    timer.Clock
+
+	lock sync.Mutex
+	// When the requests this node sent, which haven't been responded to yet,
+	// were sent to each peer
+	pending map[pendingKey]time.Time
+}
+
+type pendingKey struct {
+	trace ID
+	peer  [20]byte
+}
+
+// NewTracer returns a tracer of the requests the node [nodeID] sends and
+// handles, that exports their spans to [exporter]
+func NewTracer(nodeID ids.ShortID, exporter Exporter) *Tracer {
+	return &Tracer{
+		nodeID:   nodeID,
+		exporter: exporter,
+		pending:  make(map[pendingKey]time.Time),
+	}
+}
+
+// RequestSent records that this node sent the request [name] with ID
+// [requestID] to [peers] on the chain [chainID]
+func (t *Tracer) RequestSent(chainID ids.ID, requestID uint32, name string, peers ...ids.ShortID) {
+	if t == nil {
+		return
+	}
+	trace := NewID(t.nodeID, chainID, requestID)
+	now := t.clock.Time()
+
+	t.lock.Lock()
+	for _, peer := range peers {
+		t.pending[pendingKey{trace: trace, peer: peer.Key()}] = now
+	}
+	t.lock.Unlock()
+
+	for _, peer := range peers {
+		t.export(trace, chainID, peer, name+" sent", now, now)
+	}
+}
+
+// ResponseReceived records that [peer] responded with [name] to the request
+// with ID [requestID] this node sent on the chain [chainID], or that the
+// request failed. The span of the request, from when it was sent, ends.
+func (t *Tracer) ResponseReceived(chainID ids.ID, peer ids.ShortID, requestID uint32, name string) {
+	if t == nil {
+		return
+	}
+	trace := NewID(t.nodeID, chainID, requestID)
+	key := pendingKey{trace: trace, peer: peer.Key()}
+	now := t.clock.Time()
+
+	t.lock.Lock()
+	sent, ok := t.pending[key]
+	delete(t.pending, key)
+	t.lock.Unlock()
+
+	if !ok {
+		// The request wasn't traced, or was already responded to
+		sent = now
+	}
+	t.export(trace, chainID, peer, name+" received", sent, now)
+}
+
+// ResponseSent records that this node responded with [name] to the request
+// with ID [requestID] that [requester] sent on the chain [chainID]
+func (t *Tracer) ResponseSent(chainID ids.ID, requester ids.ShortID, requestID uint32, name string) {
+	if t == nil {
+		return
+	}
+	now := t.clock.Time()
+	t.export(NewID(requester, chainID, requestID), chainID, requester, name+" sent", now, now)
+}
+
+// Handled records that this node handled the message [name] from [peer] that
+// belongs to the trace [trace] on the chain [chainID]. The message was
+// received at [received], and waited to be handled until [start].
+func (t *Tracer) Handled(trace ID, chainID ids.ID, peer ids.ShortID, name string, received, start time.Time) {
+	if t == nil {
+		return
+	}
+	t.export(trace, chainID, peer, name+" queued", received, start)
+	t.export(trace, chainID, peer, name+" handled", start, t.clock.Time())
+}
+
+// Now returns the time spans are timed with
+func (t *Tracer) Now() time.Time { return t.clock.Time() }
+
+func (t *Tracer) export(trace ID, chainID ids.ID, peer ids.ShortID, name string, start, end time.Time) {
+	t.exporter.Export(Span{
+		Trace: trace,
+		Chain: chainID,
+		Node:  t.nodeID,
+		Peer:  peer,
+		Name:  name,
+		Start: start,
+		End:   end,
+	})
+}
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+type testExporter struct{ spans []Span }
+
+func (e *testExporter) Export(span Span) { e.spans = append(e.spans, span) }
+
+func TestTracerRequest(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requesterID := ids.NewShortID([20]byte{1})
+	responderID := ids.NewShortID([20]byte{2})
+
+	requesterSpans := &testExporter{}
+	requester := NewTracer(requesterID, requesterSpans)
+	responderSpans := &testExporter{}
+	responder := NewTracer(responderID, responderSpans)
+
+	start := time.Unix(1000, 0)
+	requester.clock.Set(start)
+	responder.clock.Set(start)
+
+	requester.RequestSent(chainID, 5, "Push Query Message", responderID)
+
+	responder.clock.Set(start.Add(time.Second))
+	received := responder.Now()
+	responder.clock.Set(start.Add(2 * time.Second))
+	responder.Handled(NewID(requesterID, chainID, 5), chainID, requesterID, "Push Query Message", received, responder.Now())
+	responder.ResponseSent(chainID, requesterID, 5, "Chits Message")
+
+	requester.clock.Set(start.Add(3 * time.Second))
+	requester.ResponseReceived(chainID, responderID, 5, "Chits Message")
+
+	spans := append(requesterSpans.spans, responderSpans.spans...)
+	if len(spans) != 5 {
+		t.Fatalf("Should have exported 5 spans but exported %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Trace != spans[0].Trace {
+			t.Fatalf("Span %q should have been in the trace of the request", span.Name)
+		}
+	}
+
+	// The request's span lasts from when it was sent to when it was responded
+	// to
+	response := requesterSpans.spans[1]
+	if response.Name != "Chits Message received" {
+		t.Fatalf("Unexpected span %q", response.Name)
+	}
+	if !response.Start.Equal(start) || response.End.Sub(response.Start) != 3*time.Second {
+		t.Fatalf("Response span should have lasted 3s from when the request was sent but lasted %s from %s", response.End.Sub(response.Start), response.Start)
+	}
+
+	// The response doesn't end the span again
+	requester.ResponseReceived(chainID, responderID, 5, "Chits Message")
+	if again := requesterSpans.spans[2]; !again.Start.Equal(again.End) {
+		t.Fatalf("A repeated response shouldn't have ended the request's span")
+	}
+}
+
+func TestTracerNil(t *testing.T) {
+	tracer := (*Tracer)(nil)
+	tracer.RequestSent(ids.Empty, 0, "Get Message", ids.ShortEmpty)
+	tracer.ResponseReceived(ids.Empty, ids.ShortEmpty, 0, "Put Message")
+	tracer.ResponseSent(ids.Empty, ids.ShortEmpty, 0, "Put Message")
+	tracer.Handled(ID{}, ids.Empty, ids.ShortEmpty, "Get Message", time.Time{}, time.Time{})
+}
@@ -0,0 +1,260 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package uptime tracks how long other nodes are connected to this node, so a
+// validator's uptime over its staking period can be observed locally.
+package uptime
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// BucketDuration is the granularity uptimes are recorded at. Time is recorded
+// per bucket, so an uptime since a time is the uptime since the start of the
+// bucket the time is in.
+const BucketDuration = time.Hour
+
+// Keys of the database are prefixed by what they record the time of
+const (
+	// This node running: prefix, bucket -> nanoseconds
+	runningPrefix byte = iota
+	// Another node being connected: prefix, node ID, bucket -> nanoseconds
+	connectedPrefix
+)
+
+var errNotRunning = errors.New("this node wasn't running during the period")
+
+// Manager records, in its own database, how long this node was running and how
+// long each other node was connected to it. A node's uptime is the fraction of
+// the time this node was running that the node was connected.
+//
+// Time is recorded as nodes disconnect and when the manager is flushed, so the
+// manager should be flushed periodically and when the node shuts down.
+type Manager struct {
+	nodeID    ids.ShortID
+	db        database.Database
+	retention time.Duration
+	clock     timer.Clock
+
+	lock sync.Mutex
+	// When this node's running time, and the time each connected node has
+	// been connected, was last recorded
+	runningSince   time.Time
+	connectedSince map[[20]byte]time.Time
+	// The oldest bucket that wasn't pruned
+	prunedBefore uint64
+}
+
+// NewManager returns a manager of the uptimes observed by the node [nodeID],
+// recorded in [db]. Time recorded longer than [retention] ago is pruned.
+func NewManager(nodeID ids.ShortID, db database.Database, retention time.Duration) *Manager {
+	m := &Manager{
+		nodeID:         nodeID,
+		db:             db,
+		retention:      retention,
+		connectedSince: make(map[[20]byte]time.Time),
+	}
+	m.runningSince = m.clock.Time()
+	return m
+}
+
+// Connected records that [nodeID] connected to this node
+func (m *Manager) Connected(nodeID ids.ShortID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, connected := m.connectedSince[nodeID.Key()]; !connected {
+		m.connectedSince[nodeID.Key()] = m.clock.Time()
+	}
+}
+
+// Disconnected records that [nodeID] disconnected from this node
+func (m *Manager) Disconnected(nodeID ids.ShortID) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := nodeID.Key()
+	since, connected := m.connectedSince[key]
+	if !connected {
+		return nil
+	}
+	delete(m.connectedSince, key)
+	return m.record(connectedKey(nodeID), since, m.clock.Time())
+}
+
+// Flush records the time since the time was last recorded, and prunes the time
+// recorded longer ago than the retention period
+func (m *Manager) Flush() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.clock.Time()
+	errs := wrappers.Errs{}
+	errs.Add(m.record([]byte{runningPrefix}, m.runningSince, now))
+	m.runningSince = now
+	for key, since := range m.connectedSince {
+		errs.Add(m.record(connectedKey(ids.NewShortID(key)), since, now))
+		m.connectedSince[key] = now
+	}
+	if !errs.Errored() {
+		errs.Add(m.prune(now))
+	}
+	return errs.Err
+}
+
+// Uptime returns the fraction of the time since [start] that this node was
+// running that [nodeID] was connected to it
+func (m *Manager) Uptime(nodeID ids.ShortID, start time.Time) (float64, error) {
+	if nodeID.Equals(m.nodeID) {
+		return 1, nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.clock.Time()
+	from := bucket(start)
+	running, err := m.total([]byte{runningPrefix}, from)
+	if err != nil {
+		return 0, err
+	}
+	running += now.Sub(m.runningSince)
+
+	connected, err := m.total(connectedKey(nodeID), from)
+	if err != nil {
+		return 0, err
+	}
+	if since, ok := m.connectedSince[nodeID.Key()]; ok {
+		connected += now.Sub(since)
+	}
+
+	if running <= 0 {
+		return 0, errNotRunning
+	}
+	if connected >= running {
+		return 1, nil
+	}
+	return float64(connected) / float64(running), nil
+}
+
+// Shutdown records the time since the time was last recorded
+func (m *Manager) Shutdown() error { return m.Flush() }
+
+// record adds the time from [from] to [to] to the buckets under [prefix]
+func (m *Manager) record(prefix []byte, from, to time.Time) error {
+	for from.Before(to) {
+		end := time.Unix(int64((bucket(from)+1)*uint64(BucketDuration/time.Second)), 0)
+		if end.After(to) {
+			end = to
+		}
+
+		key := bucketKey(prefix, bucket(from))
+		recorded, err := m.duration(key)
+		if err != nil {
+			return err
+		}
+		value := make([]byte, wrappers.LongLen)
+		binary.BigEndian.PutUint64(value, uint64(recorded+end.Sub(from)))
+		if err := m.db.Put(key, value); err != nil {
+			return err
+		}
+		from = end
+	}
+	return nil
+}
+
+// total returns the time recorded under [prefix] in the buckets from [from]
+func (m *Manager) total(prefix []byte, from uint64) (time.Duration, error) {
+	it := m.db.NewIteratorWithStartAndPrefix(bucketKey(prefix, from), prefix)
+	defer it.Release()
+
+	total := time.Duration(0)
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+wrappers.LongLen || len(it.Value()) != wrappers.LongLen {
+			continue
+		}
+		total += time.Duration(binary.BigEndian.Uint64(it.Value()))
+	}
+	return total, it.Error()
+}
+
+// prune deletes the buckets that ended before the retention period, once per
+// bucket
+func (m *Manager) prune(now time.Time) error {
+	if m.retention <= 0 {
+		return nil
+	}
+	cutoff := bucket(now.Add(-m.retention))
+	if cutoff <= m.prunedBefore {
+		return nil
+	}
+
+	batch := m.db.NewBatch()
+	it := m.db.NewIterator()
+	for it.Next() {
+		key := it.Key()
+		if len(key) < wrappers.LongLen {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(key)-wrappers.LongLen:]) < cutoff {
+			if err := batch.Delete(key); err != nil {
+				it.Release()
+				return err
+			}
+		}
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	m.prunedBefore = cutoff
+	return nil
+}
+
+func (m *Manager) duration(key []byte) (time.Duration, error) {
+	value, err := m.db.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	case len(value) != wrappers.LongLen:
+		return 0, nil
+	}
+	return time.Duration(binary.BigEndian.Uint64(value)), nil
+}
+
+// bucket returns the index of the bucket [t] is in
+func bucket(t time.Time) uint64 {
+	if t.Unix() < 0 {
+		return 0
+	}
+	return uint64(t.Unix()) / uint64(BucketDuration/time.Second)
+}
+
+func connectedKey(nodeID ids.ShortID) []byte {
+	key := make([]byte, 1+hashing.AddrLen)
+	key[0] = connectedPrefix
+	copy(key[1:], nodeID.Bytes())
+	return key
+}
+
+func bucketKey(prefix []byte, bucket uint64) []byte {
+	key := make([]byte, len(prefix)+wrappers.LongLen)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], bucket)
+	return key
+}
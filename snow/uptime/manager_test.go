@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package uptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func newTestManager(start time.Time, retention time.Duration) *Manager {
+	m := NewManager(ids.NewShortID([20]byte{1}), memdb.New(), retention)
+	m.clock.Set(start)
+	m.runningSince = start
+	return m
+}
+
+func TestUptime(t *testing.T) {
+	start := time.Unix(0, 0).Add(100 * BucketDuration)
+	m := newTestManager(start, 0)
+	nodeID := ids.NewShortID([20]byte{2})
+
+	m.Connected(nodeID)
+	m.clock.Set(start.Add(BucketDuration / 2))
+	if err := m.Disconnected(nodeID); err != nil {
+		t.Fatal(err)
+	}
+	m.clock.Set(start.Add(BucketDuration))
+
+	// Connected for half the time, before any of it was flushed
+	if uptime, err := m.Uptime(nodeID, start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 0.5 {
+		t.Fatalf("Uptime should have been 0.5 but was %f", uptime)
+	}
+
+	// Connected for the whole of the next bucket, across a flush
+	m.Connected(nodeID)
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	m.clock.Set(start.Add(2 * BucketDuration))
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if uptime, err := m.Uptime(nodeID, start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 0.75 {
+		t.Fatalf("Uptime should have been 0.75 but was %f", uptime)
+	}
+	if uptime, err := m.Uptime(nodeID, start.Add(BucketDuration)); err != nil {
+		t.Fatal(err)
+	} else if uptime != 1 {
+		t.Fatalf("Uptime since the second bucket should have been 1 but was %f", uptime)
+	}
+	if uptime, err := m.Uptime(ids.NewShortID([20]byte{3}), start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 0 {
+		t.Fatalf("Uptime of a node that never connected should have been 0 but was %f", uptime)
+	}
+	if uptime, err := m.Uptime(m.nodeID, start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 1 {
+		t.Fatalf("Uptime of this node should have been 1 but was %f", uptime)
+	}
+}
+
+func TestUptimeNotRunning(t *testing.T) {
+	start := time.Unix(0, 0).Add(100 * BucketDuration)
+	m := newTestManager(start, 0)
+
+	if _, err := m.Uptime(ids.NewShortID([20]byte{2}), start); err == nil {
+		t.Fatalf("Should have errored when this node wasn't running")
+	}
+}
+
+func TestUptimeRestart(t *testing.T) {
+	start := time.Unix(0, 0).Add(100 * BucketDuration)
+	db := memdb.New()
+	nodeID := ids.NewShortID([20]byte{2})
+
+	m := NewManager(ids.NewShortID([20]byte{1}), db, 0)
+	m.clock.Set(start)
+	m.runningSince = start
+	m.Connected(nodeID)
+	m.clock.Set(start.Add(BucketDuration))
+	if err := m.Shutdown(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Time this node wasn't running doesn't count against the node
+	restarted := start.Add(3 * BucketDuration)
+	m = NewManager(ids.NewShortID([20]byte{1}), db, 0)
+	m.clock.Set(restarted)
+	m.runningSince = restarted
+	m.clock.Set(restarted.Add(BucketDuration))
+
+	if uptime, err := m.Uptime(nodeID, start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 0.5 {
+		t.Fatalf("Uptime should have been 0.5 but was %f", uptime)
+	}
+}
+
+func TestUptimePrune(t *testing.T) {
+	start := time.Unix(0, 0).Add(100 * BucketDuration)
+	m := newTestManager(start, 2*BucketDuration)
+	nodeID := ids.NewShortID([20]byte{2})
+
+	m.Connected(nodeID)
+	m.clock.Set(start.Add(BucketDuration))
+	if err := m.Disconnected(nodeID); err != nil {
+		t.Fatal(err)
+	}
+	m.clock.Set(start.Add(4 * BucketDuration))
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bucket the node was connected in was pruned
+	if uptime, err := m.Uptime(nodeID, start); err != nil {
+		t.Fatal(err)
+	} else if uptime != 0 {
+		t.Fatalf("Uptime should have been 0 after pruning but was %f", uptime)
+	}
+}
@@ -49,14 +49,16 @@ func NewSet() Set { return &set{vdrMap: make(map[[20]byte]int)} }
 
 // set of validators. Validator function results are cached. Therefore, to
 // update a validators weight, one should ensure to call add with the updated
-// validator. Sample will run in O(NumValidators) time. All other functions run
-// in O(1) time.
+// validator. Sample will run in O(size) time, or in O(NumValidators) time if the
+// set was modified since the last sample. All other functions run in O(1) time.
 // set implements Set
 type set struct {
 	lock     sync.Mutex
 	vdrMap   map[[20]byte]int
 	vdrSlice []Validator
-	sampler  random.Weighted
+	sampler  random.Alias
+	// True if the validators were modified since the sampler was refreshed
+	modified bool
 }
 
 // Set implements the Set interface.
@@ -100,6 +102,7 @@ func (s *set) add(vdr Validator) {
 	s.vdrMap[vdrID.Key()] = i
 	s.vdrSlice = append(s.vdrSlice, vdr)
 	s.sampler.Weights = append(s.sampler.Weights, w)
+	s.modified = true
 }
 
 // Remove implements the Set interface.
@@ -132,6 +135,7 @@ func (s *set) remove(vdrID ids.ShortID) {
 	delete(s.vdrMap, iKey)
 	s.vdrSlice = s.vdrSlice[:e]
 	s.sampler.Weights = s.sampler.Weights[:e]
+	s.modified = true
 }
 
 // Contains implements the Set interface.
@@ -182,7 +186,13 @@ func (s *set) Sample(size int) []Validator {
 func (s *set) sample(size int) []Validator {
 	list := make([]Validator, size)[:0]
 
-	s.sampler.Replace() // Must replace, otherwise changes won't be reflected
+	// Must refresh, otherwise changes won't be reflected
+	if s.modified {
+		s.modified = false
+		s.sampler.Refresh()
+	} else {
+		s.sampler.Replace()
+	}
 	for ; size > 0 && s.sampler.CanSample(); size-- {
 		i := s.sampler.Sample()
 		list = append(list, s.vdrSlice[i])
@@ -147,3 +147,25 @@ func TestSamplerString(t *testing.T) {
 		t.Fatalf("Got:\n%s\nExpected:\n%s", str, expected)
 	}
 }
+
+func TestSamplerRemove(t *testing.T) {
+	vdr0 := GenerateRandomValidator(1)
+	vdr1 := GenerateRandomValidator(1)
+
+	s := NewSet()
+	s.Set([]Validator{vdr0, vdr1})
+
+	if sampled := s.Sample(2); len(sampled) != 2 {
+		t.Fatalf("Should have sampled 2 validators")
+	}
+
+	s.Remove(vdr0.ID())
+
+	for i := 0; i < 10; i++ {
+		if sampled := s.Sample(2); len(sampled) != 1 {
+			t.Fatalf("Should have sampled 1 validator")
+		} else if !sampled[0].ID().Equals(vdr1.ID()) {
+			t.Fatalf("Should have sampled vdr1")
+		}
+	}
+}
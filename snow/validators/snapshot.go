@@ -0,0 +1,17 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package validators
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Snapshotter reports the validator set of a subnet at a point in time
+type Snapshotter interface {
+	// Snapshot returns the validators of the subnet [subnetID] at [time],
+	// weighted by their stake at that time
+	Snapshot(subnetID ids.ID, time time.Time) (Set, error)
+}
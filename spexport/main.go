@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// spexport reads the state of the deprecated simple payment chains from a
+// node's database and prints it as genesis allocations of the AVM and the
+// Platform Chain, so that a network can move its funds off of those chains
+// before their VMs are removed.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/leveldb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/spchainvm"
+	"github.com/ava-labs/gecko/vms/spdagvm"
+)
+
+// export is what this tool prints
+type export struct {
+	// The funds on the Simple DAG Payments chain as holders of a fixed cap
+	// asset in the genesis of the AVM
+	AVMHolders []avm.Holder `json:"avmHolders"`
+
+	// IDs of the Simple DAG Payments UTXOs that can't be expressed as holders
+	UnexportedUTXOs []ids.ID `json:"unexportedUTXOs"`
+
+	// The accounts on the Simple Chain Payments chain as accounts in the
+	// genesis of the Platform Chain
+	PlatformAccounts []platformvm.APIAccount `json:"platformAccounts"`
+}
+
+func main() {
+	dbDir := flag.String("db-dir", "db", "Database directory of the node")
+	networkName := flag.String("network-id", genesis.LocalName, "Network ID of the node's database")
+	flag.Parse()
+
+	if err := run(*dbDir, *networkName); err != nil {
+		fmt.Fprintf(os.Stderr, "exporting the simple payment chains failed with: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbDir, networkName string) error {
+	networkID, err := genesis.NetworkID(networkName)
+	if err != nil {
+		return err
+	}
+
+	db, err := leveldb.New(path.Join(dbDir, genesis.NetworkName(networkID)), 0, 0, 0)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	dagDB, err := vmDB(db, networkID, spdagvm.ID)
+	if err != nil {
+		return err
+	}
+	utxos, err := spdagvm.ExportUTXOs(dagDB)
+	if err != nil {
+		return err
+	}
+
+	chainDB, err := vmDB(db, networkID, spchainvm.ID)
+	if err != nil {
+		return err
+	}
+	accounts, err := spchainvm.ExportAccounts(chainDB)
+	if err != nil {
+		return err
+	}
+
+	e := export{PlatformAccounts: spchainvm.PlatformAccounts(accounts)}
+	e.AVMHolders, e.UnexportedUTXOs, err = spdagvm.AVMHolders(utxos, uint64(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(e, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// vmDB returns the database of the VM of the genesis chain that runs [vmID]
+func vmDB(db database.Database, networkID uint32, vmID ids.ID) (database.Database, error) {
+	chain := genesis.VMGenesis(networkID, vmID)
+	if chain == nil {
+		return nil, fmt.Errorf("network %d has no genesis chain running VM %s", networkID, vmID)
+	}
+	// This is where the chain manager puts the state of a chain's VM
+	return prefixdb.New([]byte("vm"), prefixdb.New(chain.ID().Bytes(), db)), nil
+}
@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// Size, in bits, of the RSA keys that are generated
+	keySize = 4096
+
+	// The generated certificates are valid for as long as the ones that
+	// keys/genStaker.sh signs
+	certValidityYears = 1000
+)
+
+var errNoCert = errors.New("no PEM encoded certificate found")
+
+// NewCertAndKey generates an RSA key and a self-signed certificate for it,
+// and writes them, PEM encoded, to [keyPath] and [certPath]. The directories
+// they're written in are created if they don't exist.
+func NewCertAndKey(keyPath, certPath string) error {
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return fmt.Errorf("couldn't generate staking key: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(0),
+		NotBefore:             now,
+		NotAfter:              now.AddDate(certValidityYears, 0, 0),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("couldn't create staking certificate: %w", err)
+	}
+
+	if err := writePEM(keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)); err != nil {
+		return fmt.Errorf("couldn't write staking key: %w", err)
+	}
+	if err := writePEM(certPath, "CERTIFICATE", certBytes); err != nil {
+		return fmt.Errorf("couldn't write staking certificate: %w", err)
+	}
+	return nil
+}
+
+// NodeID returns the ID of the node that stakes with the PEM encoded
+// certificate in the file [certPath]
+func NodeID(certPath string) (ids.ShortID, error) {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem reading staking certificate: %w", err)
+	}
+	return NodeIDFromBytes(certBytes)
+}
+
+// NodeIDFromBytes returns the ID of the node that stakes with the PEM encoded
+// certificate [certBytes]
+func NodeIDFromBytes(certBytes []byte) (ids.ShortID, error) {
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return ids.ShortID{}, errNoCert
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem parsing staking certificate: %w", err)
+	}
+	nodeID, err := ids.ToShortID(hashing.PubkeyBytesToAddress(cert.Raw))
+	if err != nil {
+		return ids.ShortID{}, fmt.Errorf("problem deriving staker ID from certificate: %w", err)
+	}
+	return nodeID, nil
+}
+
+// writePEM writes [der] to [path] as a PEM block of type [blockType]. Only the
+// owner may read the file, as it may hold a private key.
+func writePEM(path, blockType string, der []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	buf := bytes.Buffer{}
+	if err := pem.Encode(&buf, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package staking
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewCertAndKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "staking")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	keyPath := filepath.Join(dir, "staking", "staker.key")
+	certPath := filepath.Join(dir, "staking", "staker.crt")
+	if err := NewCertAndKey(keyPath, certPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// The key and certificate must be usable for TLS
+	if _, err := tls.LoadX509KeyPair(certPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(keyPath); err != nil {
+		t.Fatal(err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Fatalf("Key should only be readable by its owner but has mode %s", info.Mode())
+	}
+
+	nodeID, err := NodeID(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodeID.IsZero() {
+		t.Fatalf("Should have derived a node ID")
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	// The ID of the first staker of the local network
+	nodeID, err := NodeID("../keys/keys1/staker.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"; nodeID.String() != expected {
+		t.Fatalf("Node ID should be %s but is %s", expected, nodeID)
+	}
+
+	if _, err := NodeIDFromBytes([]byte("not a certificate")); err != errNoCert {
+		t.Fatalf("Should have errored with %v but got %v", errNoCert, err)
+	}
+}
@@ -0,0 +1,214 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package bls implements BLS signatures over the BLS12-381 curve behind the
+// interfaces of the crypto package. Public keys are in G1 and signatures are
+// in G2, so public keys are small and signatures from many signers can be
+// verified together.
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+const (
+	// PublicKeyLen is the number of bytes in a compressed public key
+	PublicKeyLen = blst.BLST_P1_COMPRESS_BYTES
+
+	// PrivateKeyLen is the number of bytes in a private key
+	PrivateKeyLen = blst.BLST_SCALAR_BYTES
+
+	// SignatureLen is the number of bytes in a compressed signature
+	SignatureLen = blst.BLST_P2_COMPRESS_BYTES
+
+	// Number of random bytes a private key is derived from
+	ikmLen = 32
+
+	// Number of bits of the random scalars signatures are weighted by when
+	// they're verified in a batch
+	batchRandBits = 64
+)
+
+// Domain separation tag of the basic signature scheme, in which messages are
+// hashed to G2
+var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+var (
+	errWrongPublicKeySize  = errors.New("wrong public key size")
+	errWrongPrivateKeySize = errors.New("wrong private key size")
+	errInvalidPublicKey    = errors.New("invalid public key")
+	errInvalidPrivateKey   = errors.New("invalid private key")
+	errKeyGen              = errors.New("couldn't generate private key")
+)
+
+// Factory ...
+type Factory struct{}
+
+// NewPrivateKey implements the crypto.Factory interface
+func (*Factory) NewPrivateKey() (crypto.PrivateKey, error) {
+	ikm := [ikmLen]byte{}
+	if _, err := rand.Read(ikm[:]); err != nil {
+		return nil, err
+	}
+	sk := blst.KeyGen(ikm[:])
+	if sk == nil {
+		return nil, errKeyGen
+	}
+	return &PrivateKey{sk: sk}, nil
+}
+
+// ToPublicKey implements the crypto.Factory interface
+func (*Factory) ToPublicKey(b []byte) (crypto.PublicKey, error) {
+	if len(b) != PublicKeyLen {
+		return nil, errWrongPublicKeySize
+	}
+	pk := new(blst.P1Affine).Uncompress(b)
+	if pk == nil || !pk.KeyValidate() {
+		return nil, errInvalidPublicKey
+	}
+	return &PublicKey{pk: pk, bytes: b}, nil
+}
+
+// ToPrivateKey implements the crypto.Factory interface
+func (*Factory) ToPrivateKey(b []byte) (crypto.PrivateKey, error) {
+	if len(b) != PrivateKeyLen {
+		return nil, errWrongPrivateKeySize
+	}
+	sk := new(blst.SecretKey).Deserialize(b)
+	if sk == nil {
+		return nil, errInvalidPrivateKey
+	}
+	return &PrivateKey{sk: sk, bytes: b}, nil
+}
+
+// PublicKey ...
+type PublicKey struct {
+	pk    *blst.P1Affine
+	addr  ids.ShortID
+	bytes []byte
+}
+
+// Verify implements the crypto.PublicKey interface
+func (k *PublicKey) Verify(msg, sig []byte) bool {
+	s := toSignature(sig)
+	// The public key was validated when it was parsed
+	return s != nil && s.Verify(false, k.pk, false, msg, dst)
+}
+
+// VerifyHash implements the crypto.PublicKey interface
+func (k *PublicKey) VerifyHash(hash, sig []byte) bool {
+	return k.Verify(hash, sig)
+}
+
+// Address implements the crypto.PublicKey interface
+func (k *PublicKey) Address() ids.ShortID {
+	if k.addr.IsZero() {
+		addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(k.Bytes()))
+		if err != nil {
+			panic(err)
+		}
+		k.addr = addr
+	}
+	return k.addr
+}
+
+// Bytes implements the crypto.PublicKey interface
+func (k *PublicKey) Bytes() []byte {
+	if k.bytes == nil {
+		k.bytes = k.pk.Compress()
+	}
+	return k.bytes
+}
+
+// PrivateKey ...
+type PrivateKey struct {
+	sk    *blst.SecretKey
+	pk    *PublicKey
+	bytes []byte
+}
+
+// PublicKey implements the crypto.PrivateKey interface
+func (k *PrivateKey) PublicKey() crypto.PublicKey {
+	if k.pk == nil {
+		k.pk = &PublicKey{pk: new(blst.P1Affine).From(k.sk)}
+	}
+	return k.pk
+}
+
+// Sign implements the crypto.PrivateKey interface
+func (k *PrivateKey) Sign(msg []byte) ([]byte, error) {
+	return new(blst.P2Affine).Sign(k.sk, msg, dst).Compress(), nil
+}
+
+// SignHash implements the crypto.PrivateKey interface
+func (k *PrivateKey) SignHash(hash []byte) ([]byte, error) {
+	return k.Sign(hash)
+}
+
+// Bytes implements the crypto.PrivateKey interface
+func (k *PrivateKey) Bytes() []byte {
+	if k.bytes == nil {
+		k.bytes = k.sk.Serialize()
+	}
+	return k.bytes
+}
+
+// BatchVerify returns true if, for every i, [sigs][i] is a valid signature of
+// [msgs][i] by [pks][i]. It's faster than verifying each signature on its own,
+// as the pairings of all the signatures are checked at once. The signatures are
+// weighted by random scalars, so invalid signatures can't cancel each other
+// out.
+func BatchVerify(pks []*PublicKey, msgs, sigs [][]byte) bool {
+	if len(pks) != len(msgs) || len(pks) != len(sigs) {
+		return false
+	}
+	if len(pks) == 0 {
+		return true
+	}
+
+	points := make([]*blst.P1Affine, len(pks))
+	for i, pk := range pks {
+		points[i] = pk.pk
+	}
+	messages := make([]blst.Message, len(msgs))
+	for i, msg := range msgs {
+		messages[i] = msg
+	}
+	signatures := make([]*blst.P2Affine, len(sigs))
+	for i, sig := range sigs {
+		if signatures[i] = toSignature(sig); signatures[i] == nil {
+			return false
+		}
+	}
+
+	randErr := error(nil)
+	randFn := func(s *blst.Scalar) {
+		r := [blst.BLST_SCALAR_BYTES]byte{}
+		if _, err := rand.Read(r[:]); err != nil {
+			randErr = err
+		}
+		s.FromBEndian(r[:])
+	}
+	valid := new(blst.P2Affine).MultipleAggregateVerify(signatures, false, points, false, messages, dst, randFn, batchRandBits)
+	return valid && randErr == nil
+}
+
+// toSignature parses the compressed signature [sig]. Returns nil if it isn't a
+// valid point in G2's prime order subgroup.
+func toSignature(sig []byte) *blst.P2Affine {
+	if len(sig) != SignatureLen {
+		return nil
+	}
+	s := new(blst.P2Affine).Uncompress(sig)
+	if s == nil || !s.SigValidate(false) {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	f := Factory{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte{1, 2, 3}
+	sig, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub := key.PublicKey()
+	switch {
+	case len(sig) != SignatureLen:
+		t.Fatalf("Signature has %d bytes, expected %d", len(sig), SignatureLen)
+	case !pub.Verify(msg, sig):
+		t.Fatalf("Should have verified the signature")
+	case pub.Verify([]byte{1, 2, 4}, sig):
+		t.Fatalf("Shouldn't have verified the signature of a different message")
+	case pub.Verify(msg, sig[1:]):
+		t.Fatalf("Shouldn't have verified a truncated signature")
+	}
+
+	otherKey, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherKey.PublicKey().Verify(msg, sig) {
+		t.Fatalf("Shouldn't have verified the signature with a different key")
+	}
+}
+
+func TestGenRecreate(t *testing.T) {
+	f := Factory{}
+	for i := 0; i < 100; i++ {
+		sk, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recoveredSk, err := f.ToPrivateKey(sk.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sk.PublicKey().Bytes(), recoveredSk.PublicKey().Bytes()) {
+			t.Fatalf("Wrong public key")
+		}
+
+		pk, err := f.ToPublicKey(sk.PublicKey().Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pk.Address().Equals(sk.PublicKey().Address()) {
+			t.Fatalf("Wrong address")
+		}
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	f := Factory{}
+	if _, err := f.ToPublicKey(make([]byte, PublicKeyLen-1)); err == nil {
+		t.Fatalf("Should have errored due to the public key's size")
+	}
+	if _, err := f.ToPublicKey(make([]byte, PublicKeyLen)); err == nil {
+		t.Fatalf("Should have errored due to an invalid public key")
+	}
+	if _, err := f.ToPrivateKey(make([]byte, PrivateKeyLen+1)); err == nil {
+		t.Fatalf("Should have errored due to the private key's size")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	f := Factory{}
+
+	pks := []*PublicKey(nil)
+	msgs := [][]byte(nil)
+	sigs := [][]byte(nil)
+	for i := 0; i < 8; i++ {
+		sk, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte{byte(i)}
+		sig, err := sk.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pks = append(pks, sk.PublicKey().(*PublicKey))
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+
+	if !BatchVerify(nil, nil, nil) {
+		t.Fatalf("An empty batch should have verified")
+	}
+	if !BatchVerify(pks, msgs, sigs) {
+		t.Fatalf("Should have verified the batch")
+	}
+	if BatchVerify(pks, msgs[1:], sigs) {
+		t.Fatalf("Shouldn't have verified a batch with a missing message")
+	}
+
+	// Swapping two signatures makes both of them invalid
+	sigs[0], sigs[1] = sigs[1], sigs[0]
+	if BatchVerify(pks, msgs, sigs) {
+		t.Fatalf("Shouldn't have verified a batch with invalid signatures")
+	}
+}
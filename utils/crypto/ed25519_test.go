@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestED25519SignVerify(t *testing.T) {
+	f := FactoryED25519{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte{1, 2, 3}
+	sig, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub := key.PublicKey()
+	if !pub.Verify(msg, sig) {
+		t.Fatalf("Should have verified the signature")
+	}
+	if pub.Verify([]byte{1, 2, 4}, sig) {
+		t.Fatalf("Shouldn't have verified the signature of a different message")
+	}
+}
+
+func TestED25519GenRecreate(t *testing.T) {
+	f := FactoryED25519{}
+	sk, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoveredSk, err := f.ToPrivateKey(sk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk, err := f.ToPublicKey(sk.PublicKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recoveredSk.PublicKey().Bytes(), pk.Bytes()) {
+		t.Fatalf("Wrong public key")
+	}
+	if _, err := f.ToPublicKey(pk.Bytes()[1:]); err == nil {
+		t.Fatalf("Should have errored due to the public key's size")
+	}
+}
@@ -0,0 +1,128 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/go-ethereum/crypto/secp256k1"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+const (
+	// HardenedKeyStart is the index of the first hardened child of an HD key
+	HardenedKeyStart uint32 = 1 << 31
+
+	// HDPurpose is the purpose of the derivation paths of HD keys (BIP44)
+	HDPurpose = HardenedKeyStart + 44
+
+	// HDCoinType is the coin type of the derivation paths of HD keys (SLIP44)
+	HDCoinType = HardenedKeyStart + 9000
+
+	hdSeedKey = "Bitcoin seed"
+)
+
+var (
+	errSeedLength      = errors.New("seed must be between 16 and 64 bytes")
+	errUnhardenedChild = errors.New("only hardened children can be derived")
+	errUnusableKey     = errors.New("derived key is unusable")
+)
+
+// HDKey is a node in a tree of secp256k1 keys derived from a seed, as
+// specified by BIP32. Only hardened children are supported, so the keys of the
+// tree can only be derived from a seed or a private key.
+type HDKey struct {
+	key       [SECP256K1RSKLen]byte
+	chainCode [32]byte
+}
+
+// NewHDMasterKey returns the root of the tree of keys derived from [seed]
+func NewHDMasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, errSeedLength
+	}
+	mac := hmac.New(sha512.New, []byte(hdSeedKey))
+	mac.Write(seed)
+	return newHDKey(mac.Sum(nil), nil)
+}
+
+// Child returns the child of this key at [index], which must be hardened
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	if index < HardenedKeyStart {
+		return nil, errUnhardenedChild
+	}
+
+	data := make([]byte, 1+SECP256K1RSKLen+4)
+	copy(data[1:], k.key[:])
+	binary.BigEndian.PutUint32(data[1+SECP256K1RSKLen:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode[:])
+	mac.Write(data)
+	return newHDKey(mac.Sum(nil), k.key[:])
+}
+
+// Derive returns the descendant of this key at [path]
+func (k *HDKey) Derive(path []uint32) (*HDKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	return key, nil
+}
+
+// PrivateKey returns the private key of this node
+func (k *HDKey) PrivateKey() (*PrivateKeySECP256K1R, error) {
+	factory := FactorySECP256K1R{}
+	sk, err := factory.ToPrivateKey(k.key[:])
+	if err != nil {
+		return nil, err
+	}
+	return sk.(*PrivateKeySECP256K1R), nil
+}
+
+// ChainPath returns the path of the [index]'th key of the chain with ID
+// [chainID]: m/44'/9000'/chain'/0'/index', where chain is the first 31 bits of
+// [chainID]. [index] must be less than HardenedKeyStart.
+func ChainPath(chainID ids.ID, index uint32) []uint32 {
+	chain := binary.BigEndian.Uint32(chainID.Bytes()) &^ HardenedKeyStart
+	return []uint32{
+		HDPurpose,
+		HDCoinType,
+		HardenedKeyStart + chain,
+		HardenedKeyStart,
+		HardenedKeyStart + index,
+	}
+}
+
+// newHDKey returns the key described by the output of the HMAC that derives
+// it. If [parent] is nil, the key is a master key.
+func newHDKey(sum []byte, parent []byte) (*HDKey, error) {
+	n := secp256k1.S256().Params().N
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Cmp(n) >= 0 {
+		return nil, errUnusableKey
+	}
+	if parent != nil {
+		key.Add(key, new(big.Int).SetBytes(parent))
+		key.Mod(key, n)
+	}
+	if key.Sign() == 0 {
+		return nil, errUnusableKey
+	}
+
+	hdKey := &HDKey{}
+	keyBytes := key.Bytes()
+	copy(hdKey.key[SECP256K1RSKLen-len(keyBytes):], keyBytes)
+	copy(hdKey.chainCode[:], sum[32:])
+	return hdKey, nil
+}
@@ -0,0 +1,97 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Test vector 1 of BIP32
+func TestHDKeyVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewHDMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := hex.EncodeToString(master.key[:]); key != "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35" {
+		t.Fatalf("Wrong master key %s", key)
+	}
+	if chainCode := hex.EncodeToString(master.chainCode[:]); chainCode != "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508" {
+		t.Fatalf("Wrong master chain code %s", chainCode)
+	}
+
+	child, err := master.Derive([]uint32{HardenedKeyStart})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := hex.EncodeToString(child.key[:]); key != "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea" {
+		t.Fatalf("Wrong key at m/0' %s", key)
+	}
+	if chainCode := hex.EncodeToString(child.chainCode[:]); chainCode != "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141" {
+		t.Fatalf("Wrong chain code at m/0' %s", chainCode)
+	}
+
+	sk, err := child.PrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sk.Bytes(), child.key[:]) {
+		t.Fatalf("Private key should be the key of the node")
+	}
+}
+
+func TestHDKeyErrors(t *testing.T) {
+	if _, err := NewHDMasterKey(make([]byte, 15)); err == nil {
+		t.Fatalf("Should have errored because the seed is too short")
+	}
+	if _, err := NewHDMasterKey(make([]byte, 65)); err == nil {
+		t.Fatalf("Should have errored because the seed is too long")
+	}
+
+	master, err := NewHDMasterKey(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := master.Child(0); err == nil {
+		t.Fatalf("Should have errored because the child isn't hardened")
+	}
+}
+
+func TestChainPath(t *testing.T) {
+	seed := make([]byte, 32)
+	master, err := NewHDMasterKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain0 := ids.NewID([32]byte{0xff, 1})
+	chain1 := ids.NewID([32]byte{0xff, 2})
+
+	path := ChainPath(chain0, 3)
+	expected := []uint32{HDPurpose, HDCoinType, HardenedKeyStart + 0x7f010000, HardenedKeyStart, HardenedKeyStart + 3}
+	if len(path) != len(expected) {
+		t.Fatalf("Path should have %d indices but has %d", len(expected), len(path))
+	}
+	for i, index := range expected {
+		if path[i] != index {
+			t.Fatalf("Index %d of the path should be %d but is %d", i, index, path[i])
+		}
+	}
+
+	key0, err := master.Derive(ChainPath(chain0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := master.Derive(ChainPath(chain1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key0.key == key1.key {
+		t.Fatalf("Chains should derive different keys")
+	}
+}
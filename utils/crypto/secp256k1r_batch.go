@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var errBatchLenMismatch = errors.New("number of hashes and signatures differ")
+
+// RecoverHashPublicKeys returns the public keys that produced each of [sigs]
+// over the hash at the same index of [hashes]. The keys are recovered in
+// parallel, and are cached the same way as by RecoverHashPublicKey, so a later
+// recovery of any of them is free. If any signature is invalid, the error of
+// the first invalid one is returned.
+func (f *FactorySECP256K1R) RecoverHashPublicKeys(hashes, sigs [][]byte) ([]PublicKey, error) {
+	if len(hashes) != len(sigs) {
+		return nil, errBatchLenMismatch
+	}
+
+	keys := make([]PublicKey, len(sigs))
+	errs := make([]error, len(sigs))
+
+	workers := runtime.NumCPU()
+	if workers > len(sigs) {
+		workers = len(sigs)
+	}
+	if workers <= 1 {
+		for i, sig := range sigs {
+			if keys[i], errs[i] = f.RecoverHashPublicKey(hashes[i], sig); errs[i] != nil {
+				return nil, errs[i]
+			}
+		}
+		return keys, nil
+	}
+
+	// Each worker recovers the next signature no worker has taken yet, until
+	// none are left
+	next := int64(-1)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := int(atomic.AddInt64(&next, 1)); i < len(sigs); i = int(atomic.AddInt64(&next, 1)) {
+				keys[i], errs[i] = f.RecoverHashPublicKey(hashes[i], sigs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
@@ -73,3 +73,47 @@ func TestGenRecreate(t *testing.T) {
 		}
 	}
 }
+
+func TestRecoverHashPublicKeys(t *testing.T) {
+	f := FactorySECP256K1R{Cache: cache.LRU{Size: 16}}
+
+	hashes := [][]byte(nil)
+	sigs := [][]byte(nil)
+	keys := []PrivateKey(nil)
+	for i := 0; i < 16; i++ {
+		key, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := hashing.ComputeHash256([]byte{byte(i)})
+		sig, err := key.SignHash(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+		sigs = append(sigs, sig)
+		keys = append(keys, key)
+	}
+
+	pubs, err := f.RecoverHashPublicKeys(hashes, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, pub := range pubs {
+		if !bytes.Equal(pub.Bytes(), keys[i].PublicKey().Bytes()) {
+			t.Fatalf("Recovered the wrong public key for signature %d", i)
+		}
+		if cached, _ := f.RecoverHashPublicKey(hashes[i], sigs[i]); cached != pub {
+			t.Fatalf("Should have cached the public key of signature %d", i)
+		}
+	}
+
+	if _, err := f.RecoverHashPublicKeys(hashes[1:], sigs); err == nil {
+		t.Fatalf("Should have errored due to a missing hash")
+	}
+
+	sigs[3] = sigs[3][1:]
+	if _, err := f.RecoverHashPublicKeys(hashes, sigs); err == nil {
+		t.Fatalf("Should have errored due to an invalid signature")
+	}
+}
@@ -0,0 +1,174 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package formatting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Bech32 strings, as specified in BIP 173, are made of a human readable part,
+// the separator "1", and data in bech32Charset that ends with a 6 character
+// checksum
+const (
+	bech32Charset       = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Separator     = '1'
+	bech32ChecksumLen   = 6
+	bech32MaxLen        = 90
+	bech32MinHRPChar    = 33
+	bech32MaxHRPChar    = 126
+	bech32ChecksumConst = 1
+)
+
+var (
+	bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	errBech32TooLong      = errors.New("bech32 string is longer than 90 characters")
+	errBech32MixedCase    = errors.New("bech32 string has both lower and upper case characters")
+	errBech32NoSeparator  = errors.New("bech32 string is missing the separator or the checksum")
+	errBech32BadChecksum  = errors.New("invalid bech32 checksum")
+	errBech32EmptyHRP     = errors.New("bech32 human readable part is empty")
+	errBech32InvalidPad   = errors.New("invalid padding in bech32 data")
+	errBech32InvalidValue = errors.New("value doesn't fit in the number of bits of the input")
+)
+
+// FormatBech32 returns [payload] bech32 encoded with the human readable part
+// [hrp]
+func FormatBech32(hrp string, payload []byte) (string, error) {
+	if err := verifyBech32HRP(hrp); err != nil {
+		return "", err
+	}
+	hrp = strings.ToLower(hrp)
+
+	data, err := convertBits(payload, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, bech32Checksum(hrp, data)...)
+	if len(hrp)+1+len(data) > bech32MaxLen {
+		return "", errBech32TooLong
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(len(hrp) + 1 + len(data))
+	sb.WriteString(hrp)
+	sb.WriteByte(bech32Separator)
+	for _, b := range data {
+		sb.WriteByte(bech32Charset[b])
+	}
+	return sb.String(), nil
+}
+
+// ParseBech32 returns the human readable part and the payload of the bech32
+// string [str]
+func ParseBech32(str string) (string, []byte, error) {
+	if len(str) > bech32MaxLen {
+		return "", nil, errBech32TooLong
+	}
+	lower := strings.ToLower(str)
+	if lower != str && strings.ToUpper(str) != str {
+		return "", nil, errBech32MixedCase
+	}
+
+	sep := strings.LastIndexByte(lower, bech32Separator)
+	if sep < 0 || sep+1+bech32ChecksumLen > len(lower) {
+		return "", nil, errBech32NoSeparator
+	}
+	hrp := lower[:sep]
+	if err := verifyBech32HRP(hrp); err != nil {
+		return "", nil, err
+	}
+
+	data := make([]byte, len(lower)-sep-1)
+	for i := range data {
+		c := lower[sep+1+i]
+		index := strings.IndexByte(bech32Charset, c)
+		if index < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		data[i] = byte(index)
+	}
+	if bech32Polymod(append(bech32ExpandHRP(hrp), data...)) != bech32ChecksumConst {
+		return "", nil, errBech32BadChecksum
+	}
+
+	payload, err := convertBits(data[:len(data)-bech32ChecksumLen], 5, 8, false)
+	return hrp, payload, err
+}
+
+func verifyBech32HRP(hrp string) error {
+	if hrp == "" {
+		return errBech32EmptyHRP
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < bech32MinHRPChar || hrp[i] > bech32MaxHRPChar {
+			return fmt.Errorf("invalid character %q in bech32 human readable part", hrp[i])
+		}
+	}
+	return nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range bech32Generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	expanded := make([]byte, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded[i] = hrp[i] >> 5
+		expanded[len(hrp)+1+i] = hrp[i] & 31
+	}
+	return expanded
+}
+
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32ExpandHRP(hrp), data...)
+	values = append(values, make([]byte, bech32ChecksumLen)...)
+	mod := bech32Polymod(values) ^ bech32ChecksumConst
+
+	checksum := make([]byte, bech32ChecksumLen)
+	for i := range checksum {
+		checksum[i] = byte(mod>>uint(5*(bech32ChecksumLen-1-i))) & 31
+	}
+	return checksum
+}
+
+// convertBits regroups [data], whose values are [fromBits] wide, into values
+// that are [toBits] wide. If [pad], the last value is padded with zeros.
+// Otherwise, leftover bits must be zero and fewer than [fromBits].
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxValue := uint32(1)<<toBits - 1
+	converted := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, errBech32InvalidValue
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte(acc>>bits&maxValue))
+		}
+	}
+	switch {
+	case pad && bits > 0:
+		converted = append(converted, byte(acc<<(toBits-bits)&maxValue))
+	case !pad && (bits >= fromBits || acc<<(toBits-bits)&maxValue != 0):
+		return nil, errBech32InvalidPad
+	}
+	return converted, nil
+}
@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package formatting
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBech32Valid(t *testing.T) {
+	// Test vectors from BIP 173
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	}
+	for _, str := range valid {
+		hrp, payload, err := ParseBech32(str)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %s", str, err)
+		}
+		formatted, err := FormatBech32(hrp, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formatted != str && formatted != string(bytes.ToLower([]byte(str))) {
+			t.Fatalf("Expected %s, got %s", str, formatted)
+		}
+	}
+}
+
+func TestBech32Invalid(t *testing.T) {
+	// Test vectors from BIP 173
+	invalid := []string{
+		"\x201nwldj5",
+		"\x7f1axkwrx",
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"de1lg7wt\xff",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+	}
+	for _, str := range invalid {
+		if _, _, err := ParseBech32(str); err == nil {
+			t.Fatalf("Should have failed to parse %q", str)
+		}
+	}
+}
+
+func TestBech32RoundTrip(t *testing.T) {
+	payload := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 255}
+	str, err := FormatBech32("avax", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, parsed, err := ParseBech32(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hrp != "avax" || !bytes.Equal(parsed, payload) {
+		t.Fatalf("Parsed %s, 0x%x from %s", hrp, parsed, str)
+	}
+
+	corrupted := []byte(str)
+	corrupted[len(corrupted)-1] ^= 1
+	if _, _, err := ParseBech32(string(corrupted)); err == nil {
+		t.Fatalf("Should have failed to parse a string with a bad checksum")
+	}
+}
@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package formatting
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// hexPrefix starts every hex encoded string. As it isn't in the base-58
+// alphabet, strings in either encoding can be told apart.
+const hexPrefix = "0x"
+
+var errMissingHexPrefix = errors.New("missing 0x prefix")
+
+// Encoding is a format that API requests and replies give bytes in
+type Encoding uint8
+
+const (
+	// CB58Encoding is checksummed base-58. It's the default encoding.
+	CB58Encoding Encoding = iota
+	// HexEncoding is hex, prefixed with 0x
+	HexEncoding
+)
+
+// Encode returns [b] in this encoding
+func (enc Encoding) Encode(b []byte) string {
+	switch enc {
+	case HexEncoding:
+		return hexPrefix + hex.EncodeToString(b)
+	default:
+		return CB58{Bytes: b}.String()
+	}
+}
+
+// Decode returns the bytes that [str] gives in this encoding
+func (enc Encoding) Decode(str string) ([]byte, error) {
+	switch enc {
+	case HexEncoding:
+		if !strings.HasPrefix(str, hexPrefix) {
+			return nil, errMissingHexPrefix
+		}
+		return hex.DecodeString(str[len(hexPrefix):])
+	default:
+		cb58 := CB58{}
+		err := cb58.FromString(str)
+		return cb58.Bytes, err
+	}
+}
+
+func (enc Encoding) String() string {
+	switch enc {
+	case HexEncoding:
+		return "hex"
+	case CB58Encoding:
+		return "cb58"
+	default:
+		return fmt.Sprintf("Unknown encoding %d", uint8(enc))
+	}
+}
+
+// MarshalJSON ...
+func (enc Encoding) MarshalJSON() ([]byte, error) { return []byte("\"" + enc.String() + "\""), nil }
+
+// UnmarshalJSON ...
+func (enc *Encoding) UnmarshalJSON(b []byte) error {
+	str := string(b)
+	if str == "null" {
+		return nil
+	}
+	switch strings.ToLower(str) {
+	case "\"\"", "\"cb58\"":
+		*enc = CB58Encoding
+	case "\"hex\"":
+		*enc = HexEncoding
+	default:
+		return fmt.Errorf("unknown encoding %s. Expected \"cb58\" or \"hex\"", str)
+	}
+	return nil
+}
+
+// Decode returns the bytes that [str] gives in whichever encoding it's in: hex
+// if it's prefixed with 0x, and CB58 otherwise
+func Decode(str string) ([]byte, error) {
+	if strings.HasPrefix(str, hexPrefix) {
+		return HexEncoding.Decode(str)
+	}
+	return CB58Encoding.Decode(str)
+}
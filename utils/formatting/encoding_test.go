@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package formatting
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	b := []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 255}
+	tests := []struct {
+		enc      Encoding
+		expected string
+	}{
+		{CB58Encoding, "1NVSVezva3bAtJesnUj"},
+		{HexEncoding, "0x00010203040506070809ff"},
+	}
+	for _, test := range tests {
+		str := test.enc.Encode(b)
+		if str != test.expected {
+			t.Fatalf("%s: Expected %s, got %s", test.enc, test.expected, str)
+		}
+		decoded, err := test.enc.Decode(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, b) {
+			t.Fatalf("%s: Expected 0x%x, got 0x%x", test.enc, b, decoded)
+		}
+		if decoded, err := Decode(str); err != nil || !bytes.Equal(decoded, b) {
+			t.Fatalf("%s: Should have detected the encoding of %s", test.enc, str)
+		}
+	}
+
+	if _, err := HexEncoding.Decode("00010203"); err == nil {
+		t.Fatalf("Should have errored due to the missing prefix")
+	}
+	if _, err := CB58Encoding.Decode("0x00010203"); err == nil {
+		t.Fatalf("Should have errored due to invalid base-58")
+	}
+}
+
+func TestEncodingJSON(t *testing.T) {
+	args := struct {
+		Encoding Encoding `json:"encoding"`
+	}{}
+	if err := json.Unmarshal([]byte(`{"encoding":"hex"}`), &args); err != nil {
+		t.Fatal(err)
+	}
+	if args.Encoding != HexEncoding {
+		t.Fatalf("Expected %s, got %s", HexEncoding, args.Encoding)
+	}
+	if err := json.Unmarshal([]byte(`{"encoding":""}`), &args); err != nil {
+		t.Fatal(err)
+	}
+	if args.Encoding != CB58Encoding {
+		t.Fatalf("Expected %s, got %s", CB58Encoding, args.Encoding)
+	}
+	if err := json.Unmarshal([]byte(`{"encoding":"base64"}`), &args); err == nil {
+		t.Fatalf("Should have errored due to an unknown encoding")
+	}
+
+	b, err := json.Marshal(HexEncoding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"hex"` {
+		t.Fatalf("Expected \"hex\", got %s", b)
+	}
+}
@@ -19,6 +19,25 @@ type Config struct {
 	DisableLogging, DisableDisplaying, DisableContextualDisplaying, DisableFlushOnWrite, Assertions bool
 	LogLevel, DisplayLevel                                                                          Level
 	Directory, MsgPrefix                                                                            string
+
+	// Rotated log files older than this are deleted. If 0, rotated files
+	// aren't deleted by age.
+	MaxAge time.Duration
+	// Maximum number of bytes of each logger's log files. The oldest rotated
+	// files are deleted to stay under it. If 0, the size isn't limited.
+	MaxTotalSize int64
+	// If true, rotated log files are compressed with gzip
+	Compress bool
+
+	// If true, messages are written and displayed as JSON objects, one per
+	// line
+	JSONFormat bool
+	// Fields attached to every message written as JSON. Text messages are
+	// prefixed by MsgPrefix instead.
+	Fields []Field
+	// Levels of messages written to disk by the loggers with these names,
+	// overriding LogLevel. A chain's ID names each of the chain's loggers.
+	LoggerLevels map[string]Level
 }
 
 // DefaultConfig ...
@@ -29,6 +48,7 @@ func DefaultConfig() (Config, error) {
 		FileSize:         1 << 23, // 8 MB
 		RotationSize:     7,
 		FlushSize:        1,
+		Compress:         true,
 		DisplayLevel:     Info,
 		LogLevel:         Debug,
 		Directory:        dir,
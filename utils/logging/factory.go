@@ -4,7 +4,11 @@
 package logging
 
 import (
+	"fmt"
 	"path"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/ava-labs/gecko/ids"
 )
@@ -14,6 +18,19 @@ type Factory interface {
 	Make() (Logger, error)
 	MakeChain(chainID ids.ID, subdir string) (Logger, error)
 	MakeSubdir(subdir string) (Logger, error)
+
+	// SetLogLevel sets the level of messages written to disk by the loggers
+	// named [name]. If [name] is a chain's ID, it's set for each of the
+	// chain's loggers. If [name] is empty, it's set for every logger.
+	SetLogLevel(name string, level Level) error
+	// SetDisplayLevel sets the level of messages displayed by the loggers
+	// named [name]. If [name] is a chain's ID, it's set for each of the
+	// chain's loggers. If [name] is empty, it's set for every logger.
+	SetDisplayLevel(name string, level Level) error
+	// LoggerNames returns the names of the loggers this factory has made, in
+	// order
+	LoggerNames() []string
+
 	Close()
 }
 
@@ -21,54 +38,132 @@ type Factory interface {
 type factory struct {
 	config Config
 
-	loggers []Logger
+	lock sync.Mutex
+	// Maps the name of a logger to the loggers made with that name. The main
+	// logger is named "main", a subdirectory's logger is named [subdir] and a
+	// chain's logger is named [chainID]/[subdir].
+	loggers map[string][]Logger
 }
 
 // NewFactory ...
 func NewFactory(config Config) Factory {
 	return &factory{
-		config: config,
+		config:  config,
+		loggers: make(map[string][]Logger),
+	}
+}
+
+// configOf returns the config of the logger named [name]
+func (f *factory) configOf(name string, config Config) Config {
+	if level, ok := config.LoggerLevels[name]; ok {
+		config.LogLevel = level
+	} else if i := strings.IndexByte(name, '/'); i != -1 {
+		// Each of a chain's loggers is named by the chain's ID
+		if level, ok := config.LoggerLevels[name[:i]]; ok {
+			config.LogLevel = level
+		}
 	}
+	return config
+}
+
+func (f *factory) add(name string, l Logger) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.loggers[name] = append(f.loggers[name], l)
 }
 
 // Make ...
 func (f *factory) Make() (Logger, error) {
-	l, err := New(f.config)
+	l, err := New(f.configOf("main", f.config))
 	if err == nil {
-		f.loggers = append(f.loggers, l)
+		f.add("main", l)
 	}
 	return l, err
 }
 
 // MakeChain ...
 func (f *factory) MakeChain(chainID ids.ID, subdir string) (Logger, error) {
-	config := f.config
+	name := path.Join(chainID.String(), subdir)
+	config := f.configOf(name, f.config)
 	config.MsgPrefix = "SN " + chainID.String()
+	config.Fields = append(append([]Field(nil), config.Fields...), Field{Key: "chainID", Value: chainID.String()})
 	config.Directory = path.Join(config.Directory, "chain", chainID.String(), subdir)
 
 	log, err := New(config)
 	if err == nil {
-		f.loggers = append(f.loggers, log)
+		f.add(name, log)
 	}
 	return log, err
 }
 
 // MakeSubdir ...
 func (f *factory) MakeSubdir(subdir string) (Logger, error) {
-	config := f.config
+	config := f.configOf(subdir, f.config)
 	config.Directory = path.Join(config.Directory, subdir)
 
 	log, err := New(config)
 	if err == nil {
-		f.loggers = append(f.loggers, log)
+		f.add(subdir, log)
 	}
 	return log, err
 }
 
+// SetLogLevel ...
+func (f *factory) SetLogLevel(name string, level Level) error {
+	return f.apply(name, func(l Logger) { l.SetLogLevel(level) })
+}
+
+// SetDisplayLevel ...
+func (f *factory) SetDisplayLevel(name string, level Level) error {
+	return f.apply(name, func(l Logger) { l.SetDisplayLevel(level) })
+}
+
+// apply calls [set] on the loggers named [name], or on every logger if [name]
+// is empty. If [name] is a chain's ID, [set] is called on each of the chain's
+// loggers.
+func (f *factory) apply(name string, set func(Logger)) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	exists := false
+	for loggerName, loggers := range f.loggers {
+		if name != "" && loggerName != name && !strings.HasPrefix(loggerName, name+"/") {
+			continue
+		}
+		exists = true
+		for _, l := range loggers {
+			set(l)
+		}
+	}
+	if !exists && name != "" {
+		return fmt.Errorf("there is no logger named %q", name)
+	}
+	return nil
+}
+
+// LoggerNames ...
+func (f *factory) LoggerNames() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	names := make([]string, 0, len(f.loggers))
+	for name := range f.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Close ...
 func (f *factory) Close() {
-	for _, log := range f.loggers {
-		log.Stop()
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, loggers := range f.loggers {
+		for _, log := range loggers {
+			log.Stop()
+		}
 	}
-	f.loggers = nil
+	f.loggers = make(map[string][]Logger)
 }
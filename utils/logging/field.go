@@ -0,0 +1,11 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+// Field is context attached to a logged message, such as the ID of the chain
+// or request the message is about
+type Field struct {
+	Key   string
+	Value string
+}
@@ -5,9 +5,9 @@ package logging
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
-	"path"
 	"runtime"
 	"strings"
 	"sync"
@@ -21,10 +21,10 @@ type Log struct {
 	messages []string
 	size     int
 
-	wg                               sync.WaitGroup
-	flushLock, writeLock, configLock sync.Mutex
-	needsFlush                       *sync.Cond
-	w                                *bufio.Writer
+	wg                                           sync.WaitGroup
+	flushLock, writeLock, configLock, rotateLock sync.Mutex
+	needsFlush                                   *sync.Cond
+	w                                            *bufio.Writer
 
 	closed bool
 }
@@ -50,9 +50,7 @@ func (l *Log) run() {
 	l.writeLock.Lock()
 	defer l.writeLock.Unlock()
 
-	fileIndex := 0
-	filename := path.Join(l.config.Directory, fmt.Sprintf("%d.log", fileIndex))
-	f, err := os.Create(filename)
+	f, err := l.openCurrent()
 	if err != nil {
 		panic(err)
 	}
@@ -89,9 +87,7 @@ func (l *Log) run() {
 			l.w.Flush()
 			f.Close()
 
-			fileIndex = (fileIndex + 1) % l.config.RotationSize
-			filename := path.Join(l.config.Directory, fmt.Sprintf("%d.log", fileIndex))
-			f, err = os.Create(filename)
+			f, err = l.openCurrent()
 			if err != nil {
 				panic(err)
 			}
@@ -119,8 +115,9 @@ func (l *Log) Stop() {
 	l.wg.Wait()
 }
 
-// Should only be called from [Level] functions.
-func (l *Log) log(level Level, format string, args ...interface{}) {
+// Should only be called from [Level] functions. [fields] are attached to the
+// message.
+func (l *Log) log(level Level, fields []Field, format string, args ...interface{}) {
 	if l == nil {
 		return
 	}
@@ -135,7 +132,7 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 		return
 	}
 
-	output := l.format(level, format, args...)
+	output := l.format(level, fields, format, args...)
 
 	if shouldLog {
 		l.flushLock.Lock()
@@ -146,15 +143,18 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	}
 
 	if shouldDisplay {
-		if l.config.DisableContextualDisplaying {
+		switch {
+		case l.config.DisableContextualDisplaying:
 			fmt.Println(fmt.Sprintf(format, args...))
-		} else {
+		case l.config.JSONFormat:
+			fmt.Print(output)
+		default:
 			fmt.Print(level.Color().Wrap(output))
 		}
 	}
 }
 
-func (l *Log) format(level Level, format string, args ...interface{}) string {
+func (l *Log) format(level Level, fields []Field, format string, args ...interface{}) string {
 	loc := "?"
 	if _, file, no, ok := runtime.Caller(3); ok {
 		loc = fmt.Sprintf("%s#%d", file, no)
@@ -162,7 +162,15 @@ func (l *Log) format(level Level, format string, args ...interface{}) string {
 	if i := strings.Index(loc, "gecko/"); i != -1 {
 		loc = loc[i+5:]
 	}
-	text := fmt.Sprintf("%s: %s", loc, fmt.Sprintf(format, args...))
+	msg := fmt.Sprintf(format, args...)
+	if l.config.JSONFormat {
+		return l.formatJSON(level, loc, msg, fields)
+	}
+
+	text := fmt.Sprintf("%s: %s", loc, msg)
+	for _, field := range fields {
+		text += fmt.Sprintf(" %s=%s", field.Key, field.Value)
+	}
 
 	prefix := ""
 	if l.config.MsgPrefix != "" {
@@ -176,28 +184,50 @@ func (l *Log) format(level Level, format string, args ...interface{}) string {
 		text)
 }
 
+// formatJSON returns the message [msg], logged at [loc], as a JSON object
+func (l *Log) formatJSON(level Level, loc, msg string, fields []Field) string {
+	object := make(map[string]string, 4+len(l.config.Fields)+len(fields))
+	for _, field := range l.config.Fields {
+		object[field.Key] = field.Value
+	}
+	for _, field := range fields {
+		object[field.Key] = field.Value
+	}
+	object["time"] = time.Now().Format(time.RFC3339Nano)
+	object["level"] = strings.TrimSpace(level.String())
+	object["caller"] = loc
+	object["msg"] = msg
+
+	// Marshalling a map of strings can't fail
+	bytes, _ := json.Marshal(object)
+	return string(bytes) + "\n"
+}
+
 // Fatal ...
-func (l *Log) Fatal(format string, args ...interface{}) { l.log(Fatal, format, args...) }
+func (l *Log) Fatal(format string, args ...interface{}) { l.log(Fatal, nil, format, args...) }
 
 // Error ...
-func (l *Log) Error(format string, args ...interface{}) { l.log(Error, format, args...) }
+func (l *Log) Error(format string, args ...interface{}) { l.log(Error, nil, format, args...) }
 
 // Warn ...
-func (l *Log) Warn(format string, args ...interface{}) { l.log(Warn, format, args...) }
+func (l *Log) Warn(format string, args ...interface{}) { l.log(Warn, nil, format, args...) }
 
 // Info ...
-func (l *Log) Info(format string, args ...interface{}) { l.log(Info, format, args...) }
+func (l *Log) Info(format string, args ...interface{}) { l.log(Info, nil, format, args...) }
 
 // Debug ...
-func (l *Log) Debug(format string, args ...interface{}) { l.log(Debug, format, args...) }
+func (l *Log) Debug(format string, args ...interface{}) { l.log(Debug, nil, format, args...) }
 
 // Verbo ...
-func (l *Log) Verbo(format string, args ...interface{}) { l.log(Verbo, format, args...) }
+func (l *Log) Verbo(format string, args ...interface{}) { l.log(Verbo, nil, format, args...) }
+
+// With ...
+func (l *Log) With(fields ...Field) Logger { return &fieldLog{Log: l, fields: fields} }
 
 // AssertNoError ...
 func (l *Log) AssertNoError(err error) {
 	if err != nil {
-		l.log(Fatal, "%s", err)
+		l.log(Fatal, nil, "%s", err)
 	}
 	if l.config.Assertions && err != nil {
 		l.Stop()
@@ -208,7 +238,7 @@ func (l *Log) AssertNoError(err error) {
 // AssertTrue ...
 func (l *Log) AssertTrue(b bool, format string, args ...interface{}) {
 	if !b {
-		l.log(Fatal, format, args...)
+		l.log(Fatal, nil, format, args...)
 	}
 	if l.config.Assertions && !b {
 		l.Stop()
@@ -221,7 +251,7 @@ func (l *Log) AssertDeferredTrue(f func() bool, format string, args ...interface
 	// Note, the logger will only be notified here if assertions are enabled
 	if l.config.Assertions && !f() {
 		err := fmt.Sprintf(format, args...)
-		l.log(Fatal, err)
+		l.log(Fatal, nil, err)
 		l.Stop()
 		panic(err)
 	}
@@ -232,7 +262,7 @@ func (l *Log) AssertDeferredNoError(f func() error) {
 	if l.config.Assertions {
 		err := f()
 		if err != nil {
-			l.log(Fatal, "%s", err)
+			l.log(Fatal, nil, "%s", err)
 		}
 		if l.config.Assertions && err != nil {
 			l.Stop()
@@ -300,3 +330,39 @@ func (l *Log) SetContextualDisplayingEnabled(enabled bool) {
 
 	l.config.DisableContextualDisplaying = !enabled
 }
+
+// fieldLog is a Log that attaches fields to each message it logs
+type fieldLog struct {
+	*Log
+	fields []Field
+}
+
+// Fatal ...
+func (l *fieldLog) Fatal(format string, args ...interface{}) { l.log(Fatal, l.fields, format, args...) }
+
+// Error ...
+func (l *fieldLog) Error(format string, args ...interface{}) { l.log(Error, l.fields, format, args...) }
+
+// Warn ...
+func (l *fieldLog) Warn(format string, args ...interface{}) { l.log(Warn, l.fields, format, args...) }
+
+// Info ...
+func (l *fieldLog) Info(format string, args ...interface{}) { l.log(Info, l.fields, format, args...) }
+
+// Debug ...
+func (l *fieldLog) Debug(format string, args ...interface{}) { l.log(Debug, l.fields, format, args...) }
+
+// Verbo ...
+func (l *fieldLog) Verbo(format string, args ...interface{}) { l.log(Verbo, l.fields, format, args...) }
+
+// With ...
+func (l *fieldLog) With(fields ...Field) Logger {
+	return &fieldLog{
+		Log:    l.Log,
+		fields: append(append([]Field(nil), l.fields...), fields...),
+	}
+}
+
+// Stop has no effect, as the messages are written by the Log this was made
+// from
+func (l *fieldLog) Stop() {}
@@ -42,6 +42,11 @@ type Logger interface {
 	//  a panic the returned value is false
 	AssertDeferredTrue(f func() bool, format string, args ...interface{})
 
+	// Returns a logger that attaches [fields] to each message it logs, along
+	// with this logger's fields. The returned logger writes to this logger, so
+	// stopping it has no effect.
+	With(fields ...Field) Logger
+
 	// Recovers a panic, logs the error, and rethrows the panic.
 	StopOnPanic()
 	// If a function panics, this will log that panic and then re-panic ensuring
@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// File that messages are written to
+	currentLogFile = "current.log"
+
+	// Rotated files are named by the time they were rotated, so they sort in
+	// the order they were written
+	rotatedTimeFormat = "2006-01-02T15-04-05.000000000"
+	rotatedExtension  = ".log"
+	compressedSuffix  = ".gz"
+)
+
+// openCurrent opens a new file to write messages to. The file written by the
+// previous run of the logger, if any, is rotated first.
+func (l *Log) openCurrent() (*os.File, error) {
+	filename := path.Join(l.config.Directory, currentLogFile)
+	if _, err := os.Stat(filename); err == nil {
+		if err := l.rotateFile(); err != nil {
+			return nil, err
+		}
+	}
+	return os.Create(filename)
+}
+
+// rotateFile moves the file messages were written to aside. The rotated files
+// are then compressed and pruned in the background.
+func (l *Log) rotateFile() error {
+	rotated := path.Join(l.config.Directory, time.Now().Format(rotatedTimeFormat)+rotatedExtension)
+	if err := os.Rename(path.Join(l.config.Directory, currentLogFile), rotated); err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+
+		// Rotated files are compressed and pruned one rotation at a time
+		l.rotateLock.Lock()
+		defer l.rotateLock.Unlock()
+
+		if l.config.Compress {
+			if err := compressFile(rotated); err != nil {
+				l.Warn("failed to compress log file %s: %s", rotated, err)
+			}
+		}
+		if err := l.prune(); err != nil {
+			l.Warn("failed to delete old log files: %s", err)
+		}
+	}()
+	return nil
+}
+
+// compressFile replaces the file at [filename] with its gzip compression
+func compressFile(filename string) error {
+	src, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(filename + compressedSuffix)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(dst)
+	_, err = io.Copy(w, src)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filename + compressedSuffix)
+		return err
+	}
+	return os.Remove(filename)
+}
+
+// prune deletes the oldest rotated files until at most RotationSize are kept,
+// none is older than MaxAge, and the rotated files together with the current
+// file are at most MaxTotalSize bytes. A limit of 0 isn't enforced.
+func (l *Log) prune() error {
+	files, err := ioutil.ReadDir(l.config.Directory)
+	if err != nil {
+		return err
+	}
+
+	rotated := []os.FileInfo(nil)
+	totalSize := int64(0)
+	for _, file := range files {
+		name := file.Name()
+		if name == currentLogFile {
+			totalSize += file.Size()
+			continue
+		}
+		if file.IsDir() || !(strings.HasSuffix(name, rotatedExtension) || strings.HasSuffix(name, rotatedExtension+compressedSuffix)) {
+			continue
+		}
+		rotated = append(rotated, file)
+		totalSize += file.Size()
+	}
+	// Newest first
+	sort.Slice(rotated, func(i, j int) bool { return rotated[i].Name() > rotated[j].Name() })
+
+	now := time.Now()
+	for i := len(rotated) - 1; i >= 0; i-- {
+		file := rotated[i]
+		tooMany := l.config.RotationSize > 0 && i >= l.config.RotationSize
+		tooOld := l.config.MaxAge > 0 && now.Sub(file.ModTime()) > l.config.MaxAge
+		tooBig := l.config.MaxTotalSize > 0 && totalSize > l.config.MaxTotalSize
+		if !tooMany && !tooOld && !tooBig {
+			break
+		}
+		if err := os.Remove(path.Join(l.config.Directory, file.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		totalSize -= file.Size()
+	}
+	return nil
+}
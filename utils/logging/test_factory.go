@@ -19,5 +19,14 @@ func (NoFactory) MakeChain(ids.ID, string) (Logger, error) { return NoLog{}, nil
 // MakeSubdir ...
 func (NoFactory) MakeSubdir(string) (Logger, error) { return NoLog{}, nil }
 
+// SetLogLevel ...
+func (NoFactory) SetLogLevel(string, Level) error { return nil }
+
+// SetDisplayLevel ...
+func (NoFactory) SetDisplayLevel(string, Level) error { return nil }
+
+// LoggerNames ...
+func (NoFactory) LoggerNames() []string { return nil }
+
 // Close ...
 func (NoFactory) Close() {}
@@ -34,6 +34,9 @@ func (NoLog) Debug(format string, args ...interface{}) {}
 // Verbo ...
 func (NoLog) Verbo(format string, args ...interface{}) {}
 
+// With ...
+func (NoLog) With(...Field) Logger { return NoLog{} }
+
 // AssertNoError ...
 func (NoLog) AssertNoError(error) {}
 
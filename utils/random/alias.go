@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Alias implements the Sampler interface by sampling with Vose's alias method.
+//
+// The alias tables split the weights into len(Weights) equally likely columns,
+// each of which holds at most two elements, so a sample is drawn in O(1) time.
+// Sampled elements are skipped when they're drawn again. Once at least half of
+// the weight has been sampled, the tables are rebuilt without the sampled
+// elements, so a sample is drawn in expected O(1) time.
+//
+// Building the tables takes O(len(Weights)) time. After Weights are modified,
+// Refresh must be called.
+type Alias struct {
+	Weights []uint64
+
+	// The reason this is separated from Weights, is because it is set to 0
+	// after being sampled.
+	weights []uint64
+	// Elements sampled since the last Replace
+	sampled []int
+	// Weight of the elements that haven't been sampled
+	remaining uint64
+
+	// Weight of the elements the tables were built with
+	tableWeight uint64
+	// True if the tables were built after elements were sampled
+	partial bool
+	prob    []float64
+	alias   []int
+}
+
+func (s *Alias) init() {
+	if len(s.Weights) != len(s.weights) {
+		s.Refresh()
+	}
+}
+
+// Sample returns a number in [0, len(weights)) with probability proportional to
+// the weight of the item at that index. Assumes CanSample returns true. Sample
+// takes expected O(1) time.
+func (s *Alias) Sample() int {
+	i := s.SampleReplace()
+	s.remaining -= s.weights[i]
+	s.weights[i] = 0
+	s.sampled = append(s.sampled, i)
+	return i
+}
+
+// SampleReplace returns a number in [0, len(weights)) with probability
+// proportional to the weight of the item at that index. Assumes CanSample
+// returns true. SampleReplace takes expected O(1) time. The returned index is
+// not removed.
+func (s *Alias) SampleReplace() int {
+	s.init()
+	if s.remaining < s.tableWeight/2 {
+		s.build()
+		s.partial = true
+	}
+	for {
+		i := rand.Intn(len(s.prob))
+		if rand.Float64() >= s.prob[i] {
+			i = s.alias[i]
+		}
+		// Sampled elements, and elements that only appear in the tables due to
+		// rounding, are drawn again
+		if s.weights[i] > 0 {
+			return i
+		}
+	}
+}
+
+// CanSample returns true if there are items left that can be sampled
+func (s *Alias) CanSample() bool {
+	s.init()
+	return s.remaining > 0
+}
+
+// Replace all the sampled elements. Takes O(number of sampled elements) time,
+// unless the tables were rebuilt while sampling, in which case it takes
+// O(len(weights)) time.
+func (s *Alias) Replace() {
+	s.init()
+	for _, i := range s.sampled {
+		s.weights[i] = s.Weights[i]
+		s.remaining += s.Weights[i]
+	}
+	s.sampled = s.sampled[:0]
+
+	if s.partial {
+		s.partial = false
+		s.build()
+	}
+}
+
+// Refresh replaces all the sampled elements and rebuilds the tables from
+// Weights. Takes O(len(weights)) time.
+func (s *Alias) Refresh() {
+	// Attempt to malloc as few times as possible
+	if cap(s.weights) < len(s.Weights) {
+		s.weights = make([]uint64, len(s.Weights))
+	} else {
+		s.weights = s.weights[:len(s.Weights)]
+	}
+	copy(s.weights, s.Weights)
+
+	s.remaining = 0
+	for _, w := range s.Weights {
+		if w > math.MaxInt64-s.remaining {
+			panic("Weight too large")
+		}
+		s.remaining += w
+	}
+	s.sampled = s.sampled[:0]
+	s.partial = false
+	s.build()
+}
+
+// build the alias tables from the weights of the elements that haven't been
+// sampled
+func (s *Alias) build() {
+	n := len(s.weights)
+	if cap(s.prob) < n {
+		s.prob = make([]float64, n)
+		s.alias = make([]int, n)
+	} else {
+		s.prob = s.prob[:n]
+		s.alias = s.alias[:n]
+	}
+	s.tableWeight = s.remaining
+	if s.remaining == 0 {
+		return
+	}
+
+	// Scale the weights so that each column holds a weight of 1. Columns of
+	// elements whose weight is less than 1 are filled by elements whose weight
+	// is more than 1.
+	small := make([]int, 0, n)
+	large := make([]int, 0, n)
+	for i, w := range s.weights {
+		s.prob[i] = float64(w) * float64(n) / float64(s.remaining)
+		if s.prob[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		l := small[len(small)-1]
+		small = small[:len(small)-1]
+		g := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		s.alias[l] = g
+		s.prob[g] += s.prob[l] - 1
+		if s.prob[g] < 1 {
+			small = append(small, g)
+		} else {
+			large = append(large, g)
+		}
+	}
+	// Due to rounding, either list may have elements left over, which fill
+	// their own columns
+	for _, i := range large {
+		s.prob[i] = 1
+	}
+	for _, i := range small {
+		s.prob[i] = 1
+	}
+}
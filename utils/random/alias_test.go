@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	rand.Seed(0)
+
+	counts := [countSize]int{}
+	for i := 0; i < iterations; i++ {
+		s := &Alias{Weights: []uint64{0, 1, 2, 3, 4}}
+		subset := Subset(s, 1)
+		for _, j := range subset {
+			counts[j]++
+		}
+		if len(subset) != 1 {
+			t.Fatalf("Incorrect size")
+		}
+	}
+
+	for i := 0; i < countSize; i++ {
+		expected := float64(i) * iterations / 10
+		if math.Abs(float64(counts[i])-expected) > threshold {
+			t.Fatalf("Index seems biased: %s i=%d e=%f", fmt.Sprint(counts), i, expected)
+		}
+	}
+}
+
+func TestAliasSampleReplace(t *testing.T) {
+	rand.Seed(0)
+
+	counts := [countSize]int{}
+	s := &Alias{Weights: []uint64{0, 1, 2, 3, 4}}
+	for i := 0; i < iterations; i++ {
+		counts[s.SampleReplace()]++
+	}
+
+	for i := 0; i < countSize; i++ {
+		expected := float64(i) * iterations / 10
+		if math.Abs(float64(counts[i])-expected) > threshold {
+			t.Fatalf("Index seems biased: %s i=%d e=%f", fmt.Sprint(counts), i, expected)
+		}
+	}
+}
+
+func TestSubsetAlias(t *testing.T) {
+	s := &Alias{Weights: []uint64{1, 2, 3, 4, 5}}
+	subset := Subset(s, 5)
+	if len(subset) != 5 {
+		t.Fatalf("Returned wrong number of elements")
+	}
+	sort.Ints(subset)
+
+	for i := 0; i < 5; i++ {
+		if i != subset[i] {
+			t.Fatalf("Returned wrong element")
+		}
+	}
+	if s.CanSample() {
+		t.Fatalf("Shouldn't be able to sample")
+	}
+
+	// The tables were rebuilt while sampling, so replacing must restore them
+	s.Replace()
+	subset = Subset(s, 5)
+	if len(subset) != 5 {
+		t.Fatalf("Returned wrong number of elements after replacing")
+	}
+}
+
+func TestAliasRefresh(t *testing.T) {
+	s := &Alias{Weights: []uint64{0, 1, 0, 0, 0}}
+
+	if !s.CanSample() {
+		t.Fatalf("Should be able to sample")
+	}
+	if s.SampleReplace() != 1 {
+		t.Fatalf("Wrong sample")
+	}
+	if s.Sample() != 1 {
+		t.Fatalf("Wrong sample")
+	}
+	if s.CanSample() {
+		t.Fatalf("Shouldn't be able to sample")
+	}
+
+	s.Replace()
+
+	if !s.CanSample() {
+		t.Fatalf("Should be able to sample")
+	}
+	if s.Sample() != 1 {
+		t.Fatalf("Wrong sample")
+	}
+
+	s.Weights[1] = 0
+	s.Weights[2] = 1
+	s.Refresh()
+
+	if !s.CanSample() {
+		t.Fatalf("Should be able to sample")
+	}
+	if s.SampleReplace() != 2 {
+		t.Fatalf("Wrong sample")
+	}
+	if s.Sample() != 2 {
+		t.Fatalf("Wrong sample")
+	}
+	if s.CanSample() {
+		t.Fatalf("Shouldn't be able to sample")
+	}
+}
+
+func TestAliasWeightTooLarge(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Should have panicked due to the total weight overflowing")
+		}
+	}()
+
+	s := &Alias{Weights: []uint64{math.MaxInt64, 1}}
+	s.Refresh()
+}
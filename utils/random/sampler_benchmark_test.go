@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"fmt"
+	"testing"
+)
+
+var benchmarkSizes = []int{100, 1000, 10000}
+
+func benchmarkWeights(size int) []uint64 {
+	weights := make([]uint64, size)
+	for i := range weights {
+		weights[i] = uint64(Rand(1, 1<<30))
+	}
+	return weights
+}
+
+// BenchmarkSampleReplace measures drawing a single sample with replacement
+func BenchmarkSampleReplace(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		samplers := map[string]Sampler{
+			"uniform":  &Uniform{N: size},
+			"weighted": &Weighted{Weights: benchmarkWeights(size)},
+			"alias":    &Alias{Weights: benchmarkWeights(size)},
+		}
+		for _, name := range []string{"uniform", "weighted", "alias"} {
+			s := samplers[name]
+			s.Replace()
+			b.Run(fmt.Sprintf("%s_%d", name, size), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					s.SampleReplace()
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSubset measures drawing 20 samples without replacement, as done when
+// sampling validators for a query
+func BenchmarkSubset(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		samplers := map[string]Sampler{
+			"uniform":  &Uniform{N: size},
+			"weighted": &Weighted{Weights: benchmarkWeights(size)},
+			"alias":    &Alias{Weights: benchmarkWeights(size)},
+		}
+		for _, name := range []string{"uniform", "weighted", "alias"} {
+			s := samplers[name]
+			s.Replace()
+			b.Run(fmt.Sprintf("%s_%d", name, size), func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					Subset(s, 20)
+					s.Replace()
+				}
+			})
+		}
+	}
+}
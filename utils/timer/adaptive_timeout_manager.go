@@ -0,0 +1,244 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"container/heap"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errNegativeTimeout       = errors.New("minimum timeout can't be negative")
+	errInitialTimeoutRange   = errors.New("initial timeout must be between the minimum and maximum timeouts")
+	errSmallTimeoutIncrease  = errors.New("timeout multiplier must be at least 1")
+	errNegativeTimeoutReduce = errors.New("timeout reduction can't be negative")
+)
+
+// AdaptiveTimeoutConfig contains the parameters of an AdaptiveTimeoutManager
+type AdaptiveTimeoutConfig struct {
+	// Timeout of the first requests
+	InitialTimeout time.Duration
+	// Bounds of the timeout
+	MinimumTimeout, MaximumTimeout time.Duration
+	// The timeout is multiplied by this each time a request times out
+	TimeoutMultiplier float64
+	// The timeout is reduced by this each time a request is removed before it
+	// times out
+	TimeoutReduction time.Duration
+	// Where the time is read from. The system clock if nil.
+	Clock TimeSource
+}
+
+// Verify returns an error if the timeouts of [config] are out of order, or if
+// [config] would never change the timeout in the right direction
+func (config *AdaptiveTimeoutConfig) Verify() error {
+	switch {
+	case config.MinimumTimeout < 0:
+		return errNegativeTimeout
+	case config.InitialTimeout < config.MinimumTimeout || config.InitialTimeout > config.MaximumTimeout:
+		return errInitialTimeoutRange
+	case config.TimeoutMultiplier < 1:
+		return errSmallTimeoutIncrease
+	case config.TimeoutReduction < 0:
+		return errNegativeTimeoutReduce
+	default:
+		return nil
+	}
+}
+
+type adaptiveTimeout struct {
+	index    int
+	id       ids.ID
+	handler  func()
+	deadline time.Time
+}
+
+// timeoutQueue is a min-heap of timeouts, ordered by their deadlines
+type timeoutQueue []*adaptiveTimeout
+
+func (q timeoutQueue) Len() int           { return len(q) }
+func (q timeoutQueue) Less(i, j int) bool { return q[i].deadline.Before(q[j].deadline) }
+func (q timeoutQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+// Push implements the heap interface
+func (q *timeoutQueue) Push(x interface{}) {
+	timeout := x.(*adaptiveTimeout)
+	timeout.index = len(*q)
+	*q = append(*q, timeout)
+}
+
+// Pop implements the heap interface
+func (q *timeoutQueue) Pop() interface{} {
+	old := *q
+	timeout := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return timeout
+}
+
+// AdaptiveTimeoutManager is a manager for timeouts whose duration adapts to how
+// quickly the timed out operations complete. Each time an operation times out,
+// the timeout is increased, and each time an operation completes in time, the
+// timeout is reduced, within the bounds of its config. Operations that are
+// slow to complete are then given longer before they time out, and operations
+// that complete quickly aren't waited on for as long when they fail.
+type AdaptiveTimeoutManager struct {
+	lock sync.Mutex
+
+	config         AdaptiveTimeoutConfig
+	clock          TimeSource
+	currentTimeout time.Duration
+
+	timeoutMap   map[[32]byte]*adaptiveTimeout
+	timeoutQueue timeoutQueue
+	timer        *Timer // Timer that will fire to clear the timeouts
+}
+
+// Initialize this timeout manager with [config]
+func (tm *AdaptiveTimeoutManager) Initialize(config *AdaptiveTimeoutConfig) error {
+	if err := config.Verify(); err != nil {
+		return err
+	}
+	tm.config = *config
+	tm.clock = config.Clock
+	if tm.clock == nil {
+		tm.clock = &Clock{}
+	}
+	tm.currentTimeout = config.InitialTimeout
+	tm.timeoutMap = make(map[[32]byte]*adaptiveTimeout)
+	tm.timer = NewTimer(tm.Timeout)
+	return nil
+}
+
+// Dispatch ...
+func (tm *AdaptiveTimeoutManager) Dispatch() { tm.timer.Dispatch() }
+
+// Stop executing timeouts
+func (tm *AdaptiveTimeoutManager) Stop() { tm.timer.Stop() }
+
+// CurrentTimeout returns how long an operation that's put now is given before
+// it times out
+func (tm *AdaptiveTimeoutManager) CurrentTimeout() time.Duration {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	return tm.currentTimeout
+}
+
+// Put registers [handler] to be called once the current timeout passes, unless
+// [id] is removed before then. If [id] was already put, its previous handler is
+// replaced. Returns the time at which [handler] will be called.
+func (tm *AdaptiveTimeoutManager) Put(id ids.ID, handler func()) time.Time {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	return tm.put(id, handler)
+}
+
+// Remove the timeout of [id], as its operation completed. The timeout is
+// reduced, since the operation completed in time.
+func (tm *AdaptiveTimeoutManager) Remove(id ids.ID) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	if tm.remove(id) {
+		tm.currentTimeout -= tm.config.TimeoutReduction
+		if tm.currentTimeout < tm.config.MinimumTimeout {
+			tm.currentTimeout = tm.config.MinimumTimeout
+		}
+	}
+}
+
+// Timeout calls the handlers of the timeouts that have passed
+func (tm *AdaptiveTimeoutManager) Timeout() {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.timeout()
+}
+
+func (tm *AdaptiveTimeoutManager) timeout() {
+	currentTime := tm.clock.Time()
+	for {
+		handler := tm.removeExpiredHead(currentTime)
+		if handler == nil {
+			break
+		}
+
+		// Don't execute a callback with a lock held
+		tm.lock.Unlock()
+		handler()
+		tm.lock.Lock()
+	}
+	tm.registerTimeout()
+}
+
+func (tm *AdaptiveTimeoutManager) put(id ids.ID, handler func()) time.Time {
+	tm.remove(id)
+
+	timeout := &adaptiveTimeout{
+		id:       id,
+		handler:  handler,
+		deadline: tm.clock.Time().Add(tm.currentTimeout),
+	}
+	tm.timeoutMap[id.Key()] = timeout
+	heap.Push(&tm.timeoutQueue, timeout)
+
+	// The new timeout may be the first to pass, as the timeouts of earlier
+	// operations may have been longer
+	tm.registerTimeout()
+	return timeout.deadline
+}
+
+// remove the timeout of [id]. Returns true if it was pending.
+func (tm *AdaptiveTimeoutManager) remove(id ids.ID) bool {
+	key := id.Key()
+	timeout, exists := tm.timeoutMap[key]
+	if !exists {
+		return false
+	}
+	delete(tm.timeoutMap, key)
+	heap.Remove(&tm.timeoutQueue, timeout.index)
+	return true
+}
+
+// removeExpiredHead removes the timeout that passes first, if it passed by
+// [currentTime], and returns its handler. The timeout is increased, as the
+// operation didn't complete in time. Returns nil if no timeout has passed.
+func (tm *AdaptiveTimeoutManager) removeExpiredHead(currentTime time.Time) func() {
+	if tm.timeoutQueue.Len() == 0 {
+		return nil
+	}
+
+	head := tm.timeoutQueue[0]
+	if head.deadline.After(currentTime) {
+		return nil
+	}
+	tm.remove(head.id)
+
+	tm.currentTimeout = time.Duration(float64(tm.currentTimeout) * tm.config.TimeoutMultiplier)
+	if tm.currentTimeout > tm.config.MaximumTimeout {
+		tm.currentTimeout = tm.config.MaximumTimeout
+	}
+	return head.handler
+}
+
+func (tm *AdaptiveTimeoutManager) registerTimeout() {
+	if tm.timeoutQueue.Len() == 0 {
+		// There are no pending timeouts
+		tm.timer.Cancel()
+		return
+	}
+
+	head := tm.timeoutQueue[0]
+	tm.timer.SetTimeoutIn(head.deadline.Sub(tm.clock.Time()))
+}
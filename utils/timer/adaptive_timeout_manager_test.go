@@ -0,0 +1,160 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestAdaptiveTimeoutManagerFire(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	defer wg.Wait()
+
+	tm := AdaptiveTimeoutManager{}
+	if err := tm.Initialize(&AdaptiveTimeoutConfig{
+		InitialTimeout:    time.Millisecond,
+		MinimumTimeout:    time.Millisecond,
+		MaximumTimeout:    10 * time.Millisecond,
+		TimeoutMultiplier: 2,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	tm.Put(ids.NewID([32]byte{}), wg.Done)
+	tm.Put(ids.NewID([32]byte{1}), wg.Done)
+}
+
+func TestAdaptiveTimeoutManagerAdapts(t *testing.T) {
+	clock := NewMockClock(time.Unix(1000, 0))
+	tm := AdaptiveTimeoutManager{}
+	if err := tm.Initialize(&AdaptiveTimeoutConfig{
+		InitialTimeout:    10 * time.Second,
+		MinimumTimeout:    5 * time.Second,
+		MaximumTimeout:    30 * time.Second,
+		TimeoutMultiplier: 2,
+		TimeoutReduction:  4 * time.Second,
+		Clock:             clock,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fired := 0
+	deadline := tm.Put(ids.NewID([32]byte{1}), func() { fired++ })
+	if expected := clock.Time().Add(10 * time.Second); !deadline.Equal(expected) {
+		t.Fatalf("Expected deadline %s, got %s", expected, deadline)
+	}
+
+	clock.Advance(9 * time.Second)
+	tm.Timeout()
+	if fired != 0 {
+		t.Fatalf("Shouldn't have timed out before the deadline")
+	}
+
+	clock.Advance(time.Second)
+	tm.Timeout()
+	if fired != 1 {
+		t.Fatalf("Should have timed out at the deadline")
+	}
+	if timeout := tm.CurrentTimeout(); timeout != 20*time.Second {
+		t.Fatalf("Timeout should have doubled to %s, but is %s", 20*time.Second, timeout)
+	}
+
+	// A timeout never grows past the maximum
+	tm.Put(ids.NewID([32]byte{2}), func() { fired++ })
+	clock.Advance(20 * time.Second)
+	tm.Timeout()
+	if timeout := tm.CurrentTimeout(); fired != 2 || timeout != 30*time.Second {
+		t.Fatalf("Timeout should have been capped at %s, but is %s", 30*time.Second, timeout)
+	}
+
+	// Removing a pending timeout reduces the timeout, and removing it again
+	// doesn't
+	tm.Put(ids.NewID([32]byte{3}), func() { fired++ })
+	tm.Remove(ids.NewID([32]byte{3}))
+	tm.Remove(ids.NewID([32]byte{3}))
+	if timeout := tm.CurrentTimeout(); timeout != 26*time.Second {
+		t.Fatalf("Timeout should have been reduced to %s, but is %s", 26*time.Second, timeout)
+	}
+	clock.Advance(time.Minute)
+	tm.Timeout()
+	if fired != 2 {
+		t.Fatalf("Shouldn't have called the handler of a removed timeout")
+	}
+
+	// A timeout never shrinks below the minimum
+	for i := 0; i < 10; i++ {
+		tm.Put(ids.NewID([32]byte{4}), func() { fired++ })
+		tm.Remove(ids.NewID([32]byte{4}))
+	}
+	if timeout := tm.CurrentTimeout(); timeout != 5*time.Second {
+		t.Fatalf("Timeout should have been capped at %s, but is %s", 5*time.Second, timeout)
+	}
+}
+
+func TestAdaptiveTimeoutManagerOrder(t *testing.T) {
+	clock := NewMockClock(time.Unix(1000, 0))
+	tm := AdaptiveTimeoutManager{}
+	if err := tm.Initialize(&AdaptiveTimeoutConfig{
+		InitialTimeout:    20 * time.Second,
+		MinimumTimeout:    time.Second,
+		MaximumTimeout:    time.Minute,
+		TimeoutMultiplier: 1,
+		TimeoutReduction:  10 * time.Second,
+		Clock:             clock,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fired := []int(nil)
+	tm.Put(ids.NewID([32]byte{1}), func() { fired = append(fired, 1) })
+	tm.Put(ids.NewID([32]byte{2}), func() { fired = append(fired, 2) })
+	tm.Remove(ids.NewID([32]byte{2}))
+	// Put after the timeout was reduced, so it passes before the first one
+	tm.Put(ids.NewID([32]byte{3}), func() { fired = append(fired, 3) })
+
+	clock.Advance(10 * time.Second)
+	tm.Timeout()
+	if len(fired) != 1 || fired[0] != 3 {
+		t.Fatalf("Only the shorter timeout should have passed, but %v did", fired)
+	}
+	clock.Advance(10 * time.Second)
+	tm.Timeout()
+	if len(fired) != 2 || fired[1] != 1 {
+		t.Fatalf("Both timeouts should have passed, but %v did", fired)
+	}
+}
+
+func TestAdaptiveTimeoutConfigVerify(t *testing.T) {
+	valid := AdaptiveTimeoutConfig{
+		InitialTimeout:    2 * time.Second,
+		MinimumTimeout:    time.Second,
+		MaximumTimeout:    3 * time.Second,
+		TimeoutMultiplier: 1.5,
+		TimeoutReduction:  time.Millisecond,
+	}
+	if err := valid.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []func(*AdaptiveTimeoutConfig){
+		func(c *AdaptiveTimeoutConfig) { c.MinimumTimeout = -time.Second },
+		func(c *AdaptiveTimeoutConfig) { c.InitialTimeout = 500 * time.Millisecond },
+		func(c *AdaptiveTimeoutConfig) { c.InitialTimeout = 4 * time.Second },
+		func(c *AdaptiveTimeoutConfig) { c.TimeoutMultiplier = 0.5 },
+		func(c *AdaptiveTimeoutConfig) { c.TimeoutReduction = -time.Millisecond },
+	}
+	for i, modify := range invalid {
+		config := valid
+		modify(&config)
+		if err := config.Verify(); err == nil {
+			t.Fatalf("Config %d should have been invalid", i)
+		}
+	}
+}
@@ -7,6 +7,13 @@ import (
 	"time"
 )
 
+// TimeSource is anything that time can be read from. Code that reads the time
+// through a TimeSource can be given a MockClock in tests.
+type TimeSource interface {
+	Time() time.Time
+	Unix() uint64
+}
+
 // Clock acts as a thin wrapper around global time that allows for easy testing
 type Clock struct {
 	faked bool
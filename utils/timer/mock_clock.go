@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// MockClock is a TimeSource whose time only changes when it's set or advanced.
+// Unlike a Clock, it's safe to use from multiple goroutines, so it can be given
+// to code that reads the time on its own goroutines.
+type MockClock struct {
+	lock sync.Mutex
+	time time.Time
+}
+
+// NewMockClock returns a clock whose time is [t]
+func NewMockClock(t time.Time) *MockClock { return &MockClock{time: t} }
+
+// Set the time on the clock
+func (c *MockClock) Set(t time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.time = t
+}
+
+// Advance the time on the clock by [duration]
+func (c *MockClock) Advance(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.time = c.time.Add(duration)
+}
+
+// Time returns the time on this clock
+func (c *MockClock) Time() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.time
+}
+
+// Unix returns the unix time on this clock
+func (c *MockClock) Unix() uint64 {
+	unix := c.Time().Unix()
+	if unix < 0 {
+		unix = 0
+	}
+	return uint64(unix)
+}
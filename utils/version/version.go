@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	errMissingApp     = errors.New("version is missing the application name")
+	errWrongNumFields = errors.New("version should have a major, minor and patch number")
+)
+
+// Version of an application, as sent in the handshake. Its string form is
+// [App]/[Major].[Minor].[Patch], for example avalanche/0.0.1
+type Version struct {
+	App   string
+	Major int
+	Minor int
+	Patch int
+}
+
+// Parse [s] as a version
+func Parse(s string) (Version, error) {
+	slash := strings.IndexByte(s, '/')
+	if slash <= 0 {
+		return Version{}, errMissingApp
+	}
+	fields := strings.Split(s[slash+1:], ".")
+	if len(fields) != 3 {
+		return Version{}, errWrongNumFields
+	}
+	nums := [3]int{}
+	for i, field := range fields {
+		num, err := strconv.Atoi(field)
+		if err != nil || num < 0 {
+			return Version{}, fmt.Errorf("couldn't parse %q as a version number", field)
+		}
+		nums[i] = num
+	}
+	return Version{
+		App:   s[:slash],
+		Major: nums[0],
+		Minor: nums[1],
+		Patch: nums[2],
+	}, nil
+}
+
+// Compare returns a negative number if [v] is older than [o], 0 if they're the
+// same version and a positive number if [v] is newer than [o]. The application
+// names aren't compared.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Major != o.Major:
+		return v.Major - o.Major
+	case v.Minor != o.Minor:
+		return v.Minor - o.Minor
+	default:
+		return v.Patch - o.Patch
+	}
+}
+
+// Compatible returns true if [v] can talk to [o]. Applications can talk to
+// each other as long as they're the same application.
+func (v Version) Compatible(o Version) bool { return v.App == o.App }
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s/%d.%d.%d", v.App, v.Major, v.Minor, v.Patch)
+}
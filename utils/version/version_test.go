@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package version
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	v, err := Parse("avalanche/1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Version{App: "avalanche", Major: 1, Minor: 2, Patch: 3}); v != expected {
+		t.Fatalf("Parsed %v, expected %v", v, expected)
+	}
+	if s := v.String(); s != "avalanche/1.2.3" {
+		t.Fatalf("String returned %s", s)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"1.2.3",
+		"/1.2.3",
+		"avalanche/1.2",
+		"avalanche/1.2.3.4",
+		"avalanche/1.b.3",
+		"avalanche/1.-2.3",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("Should have failed to parse %q", s)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	older := Version{App: "avalanche", Major: 0, Minor: 9, Patch: 9}
+	newer := Version{App: "avalanche", Major: 1, Minor: 0, Patch: 0}
+	if older.Compare(newer) >= 0 {
+		t.Fatalf("%s should be older than %s", older, newer)
+	}
+	if newer.Compare(older) <= 0 {
+		t.Fatalf("%s should be newer than %s", newer, older)
+	}
+	if older.Compare(older) != 0 {
+		t.Fatalf("%s should be the same as itself", older)
+	}
+	if !older.Compatible(newer) {
+		t.Fatalf("%s should be compatible with %s", older, newer)
+	}
+	if older.Compatible(Version{App: "other"}) {
+		t.Fatalf("Different applications shouldn't be compatible")
+	}
+}
@@ -24,11 +24,23 @@ const (
 	IntLen = 4
 	// LongLen is the number of bytes per long
 	LongLen = 8
+	// MaxVarintLen is the maximum number of bytes per varint
+	MaxVarintLen = binary.MaxVarintLen64
+	// IPLen is the number of bytes per ip port pair
+	IPLen = 16 + ShortLen
 )
 
 var (
-	errBadLength      = errors.New("packer has insufficient length for input")
+	// ErrInsufficientLength is returned when unpacking a value that is longer
+	// than the bytes left in the packer
+	ErrInsufficientLength = errors.New("packer has insufficient length for input")
+	// ErrOversized is returned when packing a value would grow the byte array
+	// past the packer's maximum size
+	ErrOversized = errors.New("packer would exceed its maximum size")
+
 	errNegativeOffset = errors.New("negative offset")
+	errBadVarint      = errors.New("varint overflows a 64-bit integer")
+	errLongVarint     = errors.New("varint isn't minimally encoded")
 	errInvalidInput   = errors.New("input does not match expected format")
 	errBadType        = errors.New("wrong type passed")
 	errBadBool        = errors.New("unexpected value when unpacking bool")
@@ -55,7 +67,7 @@ func (p *Packer) CheckSpace(bytes int) {
 	case bytes < 0:
 		p.Add(errInvalidInput)
 	case len(p.Bytes)-p.Offset < bytes:
-		p.Add(errBadLength)
+		p.Add(ErrInsufficientLength)
 	}
 }
 
@@ -74,7 +86,7 @@ func (p *Packer) Expand(bytes int) {
 	}
 
 	if neededSize > p.MaxSize {
-		p.Add(errBadLength)
+		p.Add(ErrOversized)
 	} else if neededSize > cap(p.Bytes) {
 		p.Bytes = append(p.Bytes[:cap(p.Bytes)], make([]byte, neededSize-cap(p.Bytes))...)
 	} else {
@@ -174,6 +186,63 @@ func (p *Packer) UnpackLong() uint64 {
 	return val
 }
 
+// PackUvarint append an unsigned varint to the byte array. Takes between 1 and
+// MaxVarintLen bytes, depending on the size of [val].
+func (p *Packer) PackUvarint(val uint64) {
+	p.Expand(uvarintLen(val))
+	if p.Errored() {
+		return
+	}
+
+	p.Offset += binary.PutUvarint(p.Bytes[p.Offset:], val)
+}
+
+// UnpackUvarint unpack an unsigned varint from the byte array. Only the minimal
+// encoding of a value is accepted, so each value has exactly one encoding.
+func (p *Packer) UnpackUvarint() uint64 {
+	p.CheckSpace(0)
+	if p.Errored() {
+		return 0
+	}
+
+	val, n := binary.Uvarint(p.Bytes[p.Offset:])
+	switch {
+	case n == 0:
+		p.Add(ErrInsufficientLength)
+		return 0
+	case n < 0:
+		p.Add(errBadVarint)
+		return 0
+	case n != uvarintLen(val):
+		p.Add(errLongVarint)
+		return 0
+	}
+	p.Offset += n
+	return val
+}
+
+// PackVarint append a signed varint to the byte array. Values close to 0, both
+// positive and negative, take the fewest bytes.
+func (p *Packer) PackVarint(val int64) {
+	// Zig-zag encoding, as done by binary.PutVarint
+	p.PackUvarint(uint64(val<<1) ^ uint64(val>>63))
+}
+
+// UnpackVarint unpack a signed varint from the byte array
+func (p *Packer) UnpackVarint() int64 {
+	uval := p.UnpackUvarint()
+	return int64(uval>>1) ^ -int64(uval&1)
+}
+
+// uvarintLen returns the number of bytes [val] is packed into as a varint
+func uvarintLen(val uint64) int {
+	n := 1
+	for ; val >= 0x80; val >>= 7 {
+		n++
+	}
+	return n
+}
+
 // PackBool packs a bool into the byte array
 func (p *Packer) PackBool(b bool) {
 	if b {
@@ -198,7 +267,7 @@ func (p *Packer) UnpackBool() bool {
 }
 
 // PackFixedBytes append a byte slice, with no length descriptor to the byte
-// array
+// array. [bytes] is copied, so it may be modified once this returns.
 func (p *Packer) PackFixedBytes(bytes []byte) {
 	p.Expand(len(bytes))
 	if p.Errored() {
@@ -210,7 +279,9 @@ func (p *Packer) PackFixedBytes(bytes []byte) {
 }
 
 // UnpackFixedBytes unpack a byte slice, with no length descriptor from the byte
-// array
+// array. The returned slice isn't copied: it shares memory with the packer's
+// byte array, so it's only valid while the byte array isn't modified. Callers
+// that keep the slice after the byte array is reused must copy it.
 func (p *Packer) UnpackFixedBytes(size int) []byte {
 	p.CheckSpace(size)
 	if p.Errored() {
@@ -224,11 +295,15 @@ func (p *Packer) UnpackFixedBytes(size int) []byte {
 
 // PackBytes append a byte slice to the byte array
 func (p *Packer) PackBytes(bytes []byte) {
+	if uint64(len(bytes)) > math.MaxUint32 {
+		p.Add(errInvalidInput)
+	}
 	p.PackInt(uint32(len(bytes)))
 	p.PackFixedBytes(bytes)
 }
 
-// UnpackBytes unpack a byte slice from the byte array
+// UnpackBytes unpack a byte slice from the byte array. As with
+// UnpackFixedBytes, the returned slice shares memory with the byte array.
 func (p *Packer) UnpackBytes() []byte {
 	size := p.UnpackInt()
 	return p.UnpackFixedBytes(int(size))
@@ -242,9 +317,11 @@ func (p *Packer) PackFixedByteSlices(byteSlices [][]byte) {
 	}
 }
 
-// UnpackFixedByteSlices unpack a byte slice slice to the byte array
+// UnpackFixedByteSlices unpack a byte slice slice to the byte array. As with
+// UnpackFixedBytes, the returned slices share memory with the byte array.
 func (p *Packer) UnpackFixedByteSlices(size int) [][]byte {
 	sliceSize := p.UnpackInt()
+	p.checkElements(sliceSize, size)
 	bytes := [][]byte(nil)
 	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
 		bytes = append(bytes, p.UnpackFixedBytes(size))
@@ -295,6 +372,7 @@ func (p *Packer) PackIPs(ips []utils.IPDesc) {
 // UnpackIPs unpacks an ip port pair slice from the byte array
 func (p *Packer) UnpackIPs() []utils.IPDesc {
 	sliceSize := p.UnpackInt()
+	p.checkElements(sliceSize, IPLen)
 	ips := []utils.IPDesc(nil)
 	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
 		ips = append(ips, p.UnpackIP())
@@ -302,6 +380,22 @@ func (p *Packer) UnpackIPs() []utils.IPDesc {
 	return ips
 }
 
+// checkElements requires that there are enough bytes left in the byte array to
+// unpack [numElements] elements of [size] bytes each, so a corrupt length can't
+// make the packer loop over elements that can't be unpacked. Elements of 0
+// bytes are counted as 1 byte.
+func (p *Packer) checkElements(numElements uint32, size int) {
+	if p.Errored() || size < 0 {
+		return
+	}
+	if size == 0 {
+		size = 1
+	}
+	if uint64(numElements) > uint64(len(p.Bytes)-p.Offset)/uint64(size) {
+		p.Add(ErrInsufficientLength)
+	}
+}
+
 // TryPackByte attempts to pack the value as a byte
 func TryPackByte(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.(uint8); ok {
@@ -0,0 +1,80 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+// TestPackerFuzzRoundTrip checks that random values unpack to what was packed
+func TestPackerFuzzRoundTrip(t *testing.T) {
+	roundTrip := func(b byte, s uint16, i uint32, l uint64, u uint64, v int64, bs []byte, str string) bool {
+		if len(str) > MaxStringLen {
+			str = str[:MaxStringLen]
+		}
+
+		p := Packer{MaxSize: 1 << 20}
+		p.PackByte(b)
+		p.PackShort(s)
+		p.PackInt(i)
+		p.PackLong(l)
+		p.PackUvarint(u)
+		p.PackVarint(v)
+		p.PackBytes(bs)
+		p.PackStr(str)
+		if p.Errored() {
+			return false
+		}
+
+		p = Packer{Bytes: p.Bytes}
+		return p.UnpackByte() == b &&
+			p.UnpackShort() == s &&
+			p.UnpackInt() == i &&
+			p.UnpackLong() == l &&
+			p.UnpackUvarint() == u &&
+			p.UnpackVarint() == v &&
+			bytes.Equal(p.UnpackBytes(), bs) &&
+			p.UnpackStr() == str &&
+			!p.Errored() &&
+			p.Offset == len(p.Bytes)
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPackerFuzzUnpack checks that unpacking random bytes never panics, and
+// never reads past the end of the byte array
+func TestPackerFuzzUnpack(t *testing.T) {
+	unpack := func(b []byte) bool {
+		unpackers := []func(*Packer){
+			func(p *Packer) { p.UnpackByte() },
+			func(p *Packer) { p.UnpackShort() },
+			func(p *Packer) { p.UnpackInt() },
+			func(p *Packer) { p.UnpackLong() },
+			func(p *Packer) { p.UnpackUvarint() },
+			func(p *Packer) { p.UnpackVarint() },
+			func(p *Packer) { p.UnpackBool() },
+			func(p *Packer) { p.UnpackBytes() },
+			func(p *Packer) { p.UnpackStr() },
+			func(p *Packer) { p.UnpackFixedByteSlices(32) },
+			func(p *Packer) { p.UnpackIPs() },
+		}
+		for _, unpacker := range unpackers {
+			p := Packer{Bytes: b}
+			for !p.Errored() && p.Offset < len(p.Bytes) {
+				unpacker(&p)
+			}
+			if p.Offset > len(p.Bytes) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(unpack, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Fatal(err)
+	}
+}
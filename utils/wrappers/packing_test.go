@@ -5,6 +5,8 @@ package wrappers
 
 import (
 	"bytes"
+	"encoding/binary"
+	"math"
 	"testing"
 )
 
@@ -151,3 +153,125 @@ func TestPackBool(t *testing.T) {
 		t.Fatal("got back wrong values")
 	}
 }
+
+func TestPackerUvarint(t *testing.T) {
+	tests := []struct {
+		val      uint64
+		expected []byte
+	}{
+		{val: 0, expected: []byte{0x00}},
+		{val: 1, expected: []byte{0x01}},
+		{val: 0x7f, expected: []byte{0x7f}},
+		{val: 0x80, expected: []byte{0x80, 0x01}},
+		{val: 300, expected: []byte{0xac, 0x02}},
+		{val: math.MaxUint64, expected: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+	for _, test := range tests {
+		p := Packer{MaxSize: MaxVarintLen}
+		p.PackUvarint(test.val)
+		if p.Errored() {
+			t.Fatal(p.Err)
+		}
+		if !bytes.Equal(p.Bytes, test.expected) {
+			t.Fatalf("Packer.PackUvarint wrote:\n%v\nExpected:\n%v", p.Bytes, test.expected)
+		}
+
+		p = Packer{Bytes: p.Bytes}
+		if val := p.UnpackUvarint(); p.Errored() {
+			t.Fatal(p.Err)
+		} else if val != test.val {
+			t.Fatalf("Packer.UnpackUvarint returned %d but expected %d", val, test.val)
+		} else if p.Offset != len(test.expected) {
+			t.Fatalf("Packer.UnpackUvarint read %d byte(s) but expected %d byte(s)", p.Offset, len(test.expected))
+		}
+	}
+}
+
+func TestPackerUvarintInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   error
+	}{
+		{name: "empty", bytes: nil, err: ErrInsufficientLength},
+		{name: "truncated", bytes: []byte{0x80}, err: ErrInsufficientLength},
+		{name: "not minimal", bytes: []byte{0x81, 0x00}, err: errLongVarint},
+		{name: "overflow", bytes: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02}, err: errBadVarint},
+	}
+	for _, test := range tests {
+		p := Packer{Bytes: test.bytes}
+		p.UnpackUvarint()
+		if p.Err != test.err {
+			t.Fatalf("%s: expected error %v but got %v", test.name, test.err, p.Err)
+		}
+	}
+}
+
+func TestPackerVarint(t *testing.T) {
+	for _, val := range []int64{0, 1, -1, 63, -64, 64, math.MaxInt64, math.MinInt64} {
+		p := Packer{MaxSize: MaxVarintLen}
+		p.PackVarint(val)
+		if p.Errored() {
+			t.Fatal(p.Err)
+		}
+
+		expected := make([]byte, MaxVarintLen)
+		expected = expected[:binary.PutVarint(expected, val)]
+		if !bytes.Equal(p.Bytes, expected) {
+			t.Fatalf("Packer.PackVarint wrote:\n%v\nExpected:\n%v", p.Bytes, expected)
+		}
+
+		p = Packer{Bytes: p.Bytes}
+		if unpacked := p.UnpackVarint(); p.Errored() {
+			t.Fatal(p.Err)
+		} else if unpacked != val {
+			t.Fatalf("Packer.UnpackVarint returned %d but expected %d", unpacked, val)
+		}
+	}
+}
+
+func TestPackerMaxSize(t *testing.T) {
+	p := Packer{MaxSize: 3}
+	p.PackInt(1)
+	if p.Err != ErrOversized {
+		t.Fatalf("Should have errored with %v but got %v", ErrOversized, p.Err)
+	}
+
+	p = Packer{Bytes: []byte{0x01, 0x02, 0x03}}
+	p.UnpackInt()
+	if p.Err != ErrInsufficientLength {
+		t.Fatalf("Should have errored with %v but got %v", ErrInsufficientLength, p.Err)
+	}
+}
+
+func TestPackerUnpackFixedBytesAliases(t *testing.T) {
+	p := Packer{Bytes: []byte{0x01, 0x02, 0x03}}
+	unpacked := p.UnpackFixedBytes(2)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	p.Bytes[0] = 0x04
+	if unpacked[0] != 0x04 {
+		t.Fatalf("Packer.UnpackFixedBytes should share memory with the byte array")
+	}
+}
+
+func TestPackerUnpackSliceLength(t *testing.T) {
+	// Claims to hold MaxUint32 hashes, but holds none
+	p := Packer{Bytes: []byte{0xff, 0xff, 0xff, 0xff}}
+	if hashes := p.UnpackFixedByteSlices(32); len(hashes) != 0 {
+		t.Fatalf("Shouldn't have unpacked any hashes")
+	}
+	if p.Err != ErrInsufficientLength {
+		t.Fatalf("Should have errored with %v but got %v", ErrInsufficientLength, p.Err)
+	}
+
+	p = Packer{Bytes: []byte{0xff, 0xff, 0xff, 0xff}}
+	if ips := p.UnpackIPs(); len(ips) != 0 {
+		t.Fatalf("Shouldn't have unpacked any ips")
+	}
+	if p.Err != ErrInsufficientLength {
+		t.Fatalf("Should have errored with %v but got %v", ErrInsufficientLength, p.Err)
+	}
+}
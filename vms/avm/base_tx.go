@@ -6,9 +6,11 @@ package avm
 import (
 	"errors"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 )
 
@@ -29,10 +31,10 @@ var (
 type BaseTx struct {
 	metadata
 
-	NetID uint32                `serialize:"true"` // ID of the network this chain lives on
-	BCID  ids.ID                `serialize:"true"` // ID of the chain on which this transaction exists (prevents replay attacks)
-	Outs  []*TransferableOutput `serialize:"true"` // The outputs of this transaction
-	Ins   []*TransferableInput  `serialize:"true"` // The inputs to this transaction
+	NetID uint32                    `serialize:"true"` // ID of the network this chain lives on
+	BCID  ids.ID                    `serialize:"true"` // ID of the chain on which this transaction exists (prevents replay attacks)
+	Outs  []*ava.TransferableOutput `serialize:"true"` // The outputs of this transaction
+	Ins   []*ava.TransferableInput  `serialize:"true"` // The inputs to this transaction
 }
 
 // NetworkID is the ID of the network on which this transaction exists
@@ -43,15 +45,15 @@ func (t *BaseTx) ChainID() ids.ID { return t.BCID }
 
 // Outputs track which outputs this transaction is producing. The returned array
 // should not be modified.
-func (t *BaseTx) Outputs() []*TransferableOutput { return t.Outs }
+func (t *BaseTx) Outputs() []*ava.TransferableOutput { return t.Outs }
 
 // Inputs track which UTXOs this transaction is consuming. The returned array
 // should not be modified.
-func (t *BaseTx) Inputs() []*TransferableInput { return t.Ins }
+func (t *BaseTx) Inputs() []*ava.TransferableInput { return t.Ins }
 
 // InputUTXOs track which UTXOs this transaction is consuming.
-func (t *BaseTx) InputUTXOs() []*UTXOID {
-	utxos := []*UTXOID(nil)
+func (t *BaseTx) InputUTXOs() []*ava.UTXOID {
+	utxos := []*ava.UTXOID(nil)
 	for _, in := range t.Ins {
 		utxos = append(utxos, &in.UTXOID)
 	}
@@ -68,16 +70,16 @@ func (t *BaseTx) AssetIDs() ids.Set {
 }
 
 // UTXOs returns the UTXOs transaction is producing.
-func (t *BaseTx) UTXOs() []*UTXO {
+func (t *BaseTx) UTXOs() []*ava.UTXO {
 	txID := t.ID()
-	utxos := make([]*UTXO, len(t.Outs))
+	utxos := make([]*ava.UTXO, len(t.Outs))
 	for i, out := range t.Outs {
-		utxos[i] = &UTXO{
-			UTXOID: UTXOID{
+		utxos[i] = &ava.UTXO{
+			UTXOID: ava.UTXOID{
 				TxID:        txID,
 				OutputIndex: uint32(i),
 			},
-			Asset: Asset{
+			Asset: ava.Asset{
 				ID: out.AssetID(),
 			},
 			Out: out.Out,
@@ -88,6 +90,13 @@ func (t *BaseTx) UTXOs() []*UTXO {
 
 // SyntacticVerify that this transaction is well-formed.
 func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	return t.syntacticVerify(ctx, c, nil, nil)
+}
+
+// syntacticVerify that this transaction is well-formed. [importedIns] and
+// [exportedOuts] are included when verifying that the transaction doesn't
+// produce more funds than it consumes, but are otherwise not verified.
+func (t *BaseTx) syntacticVerify(ctx *snow.Context, c codec.Codec, importedIns []*ava.TransferableInput, exportedOuts []*ava.TransferableOutput) error {
 	switch {
 	case t == nil:
 		return errNilTx
@@ -102,7 +111,7 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 			return err
 		}
 	}
-	if !isSortedTransferableOutputs(t.Outs, c) {
+	if !ava.IsSortedTransferableOutputs(t.Outs, c) {
 		return errOutputsNotSorted
 	}
 
@@ -111,12 +120,15 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 			return err
 		}
 	}
-	if !isSortedAndUniqueTransferableInputs(t.Ins) {
+	if !ava.IsSortedAndUniqueTransferableInputs(t.Ins) {
 		return errInputsNotSortedUnique
 	}
 
+	ins := append(append([]*ava.TransferableInput(nil), t.Ins...), importedIns...)
+	outs := append(append([]*ava.TransferableOutput(nil), t.Outs...), exportedOuts...)
+
 	consumedFunds := map[[32]byte]uint64{}
-	for _, in := range t.Ins {
+	for _, in := range ins {
 		assetID := in.AssetID()
 		amount := in.Input().Amount()
 
@@ -129,7 +141,7 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 		}
 	}
 	producedFunds := map[[32]byte]uint64{}
-	for _, out := range t.Outs {
+	for _, out := range outs {
 		assetID := out.AssetID()
 		amount := out.Output().Amount()
 
@@ -154,6 +166,10 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 	return t.metadata.Verify()
 }
 
+// ExecuteWithSideEffects writes the batch with any additional side effects. A
+// BaseTx doesn't interact with other chains, so only the batch is written.
+func (t *BaseTx) ExecuteWithSideEffects(_ *VM, batch database.Batch) error { return batch.Write() }
+
 // SemanticVerify that this transaction is valid to be spent.
 func (t *BaseTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
 	for i, in := range t.Ins {
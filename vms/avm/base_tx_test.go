@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
@@ -77,9 +78,9 @@ func TestBaseTxSerialization(t *testing.T) {
 	tx := &Tx{UnsignedTx: &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -89,9 +90,9 @@ func TestBaseTxSerialization(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -100,7 +101,7 @@ func TestBaseTxSerialization(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -137,9 +138,9 @@ func TestBaseTxGetters(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -149,9 +150,9 @@ func TestBaseTxGetters(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -160,7 +161,7 @@ func TestBaseTxGetters(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -217,9 +218,9 @@ func TestBaseTxSyntacticVerify(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -229,9 +230,9 @@ func TestBaseTxSyntacticVerify(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -240,7 +241,7 @@ func TestBaseTxSyntacticVerify(t *testing.T) {
 					}),
 					OutputIndex: 0,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -288,9 +289,9 @@ func TestBaseTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 	tx := &BaseTx{
 		NetID: 0,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -300,9 +301,9 @@ func TestBaseTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -311,7 +312,7 @@ func TestBaseTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -342,9 +343,9 @@ func TestBaseTxSyntacticVerifyWrongChainID(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  ids.Empty,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -354,9 +355,9 @@ func TestBaseTxSyntacticVerifyWrongChainID(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -365,7 +366,7 @@ func TestBaseTxSyntacticVerifyWrongChainID(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -396,12 +397,12 @@ func TestBaseTxSyntacticVerifyInvalidOutput(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
+		Outs: []*ava.TransferableOutput{
 			nil,
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -410,7 +411,7 @@ func TestBaseTxSyntacticVerifyInvalidOutput(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -441,9 +442,9 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 2,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -452,8 +453,8 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 					},
 				},
 			},
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 1,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -463,9 +464,9 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -474,7 +475,7 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -505,9 +506,9 @@ func TestBaseTxSyntacticVerifyInvalidInput(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -517,7 +518,7 @@ func TestBaseTxSyntacticVerifyInvalidInput(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
+		Ins: []*ava.TransferableInput{
 			nil,
 		},
 	}
@@ -542,9 +543,9 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -554,9 +555,9 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -565,7 +566,7 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 					}),
 					OutputIndex: 0,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: math.MaxUint64,
 					Input: secp256k1fx.Input{
@@ -573,8 +574,8 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 					},
 				},
 			},
-			&TransferableInput{
-				UTXOID: UTXOID{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -583,7 +584,7 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 					}),
 					OutputIndex: 1,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 1,
 					Input: secp256k1fx.Input{
@@ -614,9 +615,9 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 2,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -625,8 +626,8 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 					},
 				},
 			},
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: math.MaxUint64,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -636,9 +637,9 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -647,7 +648,7 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 					}),
 					OutputIndex: 0,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 1,
 					Input: secp256k1fx.Input{
@@ -678,9 +679,9 @@ func TestBaseTxSyntacticVerifyInsufficientFunds(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: math.MaxUint64,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -690,9 +691,9 @@ func TestBaseTxSyntacticVerifyInsufficientFunds(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -701,7 +702,7 @@ func TestBaseTxSyntacticVerifyInsufficientFunds(t *testing.T) {
 					}),
 					OutputIndex: 0,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 1,
 					Input: secp256k1fx.Input{
@@ -732,9 +733,9 @@ func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 	tx := &BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{ID: asset},
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{ID: asset},
 				Out: &secp256k1fx.TransferOutput{
 					Amt: 12345,
 					OutputOwners: secp256k1fx.OutputOwners{
@@ -744,9 +745,9 @@ func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 				},
 			},
 		},
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID: ids.NewID([32]byte{
 						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
 						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
@@ -755,7 +756,7 @@ func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 					}),
 					OutputIndex: 0,
 				},
-				Asset: Asset{ID: asset},
+				Asset: ava.Asset{ID: asset},
 				In: &secp256k1fx.TransferInput{
 					Amt: 54321,
 					Input: secp256k1fx.Input{
@@ -784,6 +785,7 @@ func TestBaseTxSemanticVerify(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -800,13 +802,13 @@ func TestBaseTxSemanticVerify(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -874,6 +876,7 @@ func TestBaseTxSemanticVerifyUnknownFx(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -892,13 +895,13 @@ func TestBaseTxSemanticVerifyUnknownFx(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -949,6 +952,7 @@ func TestBaseTxSemanticVerifyWrongAssetID(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -967,13 +971,13 @@ func TestBaseTxSemanticVerifyWrongAssetID(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: asset,
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1041,6 +1045,7 @@ func TestBaseTxSemanticVerifyUnauthorizedFx(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{
 			&common.Fx{
@@ -1071,13 +1076,13 @@ func TestBaseTxSemanticVerifyUnauthorizedFx(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &TestTransferable{},
@@ -1138,6 +1143,7 @@ func TestBaseTxSemanticVerifyInvalidSignature(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -1154,13 +1160,13 @@ func TestBaseTxSemanticVerifyInvalidSignature(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1215,6 +1221,7 @@ func TestBaseTxSemanticVerifyMissingUTXO(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -1231,13 +1238,13 @@ func TestBaseTxSemanticVerifyMissingUTXO(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        ids.Empty,
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1305,6 +1312,7 @@ func TestBaseTxSemanticVerifyInvalidUTXO(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -1321,13 +1329,13 @@ func TestBaseTxSemanticVerifyInvalidUTXO(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: math.MaxUint32,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1394,6 +1402,7 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -1410,13 +1419,13 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 	pendingTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1429,9 +1438,9 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 				},
 			},
 		},
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				Out: &secp256k1fx.TransferOutput{
@@ -1489,13 +1498,13 @@ func TestBaseTxSemanticVerifyPendingInvalidUTXO(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        txID,
 					OutputIndex: 2,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1561,6 +1570,7 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -1577,13 +1587,13 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 	pendingTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1596,9 +1606,9 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 				},
 			},
 		},
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				Out: &secp256k1fx.TransferOutput{
@@ -1656,13 +1666,13 @@ func TestBaseTxSemanticVerifyPendingWrongAssetID(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        txID,
 					OutputIndex: 0,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: asset,
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1728,6 +1738,7 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{
 			&common.Fx{
@@ -1758,13 +1769,13 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 	pendingTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1777,9 +1788,9 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 				},
 			},
 		},
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				Out: &secp256k1fx.TransferOutput{
@@ -1837,13 +1848,13 @@ func TestBaseTxSemanticVerifyPendingUnauthorizedFx(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        txID,
 					OutputIndex: 0,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1893,6 +1904,7 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{
 			&common.Fx{
@@ -1923,13 +1935,13 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 	pendingTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -1942,9 +1954,9 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 				},
 			},
 		},
-		Outs: []*TransferableOutput{
-			&TransferableOutput{
-				Asset: Asset{
+		Outs: []*ava.TransferableOutput{
+			&ava.TransferableOutput{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				Out: &secp256k1fx.TransferOutput{
@@ -2002,13 +2014,13 @@ func TestBaseTxSemanticVerifyPendingInvalidSignature(t *testing.T) {
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        txID,
 					OutputIndex: 0,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -0,0 +1,351 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoKeys    = errors.New("no keys were provided to sign the transaction")
+	errNoOutputs = errors.New("no outputs to send")
+)
+
+// NewCodec returns a codec that serializes txs the same way as a chain running
+// the AVM with the nftfx and the secp256k1fx, such as the X-Chain. The types
+// are registered in the order the VM registers them.
+func NewCodec() codec.Codec {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&nftfx.MintOutput{})
+	c.RegisterType(&nftfx.TransferOutput{})
+	c.RegisterType(&nftfx.MintInput{})
+	c.RegisterType(&nftfx.TransferInput{})
+	c.RegisterType(&nftfx.Credential{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+	c.RegisterType(&ImportTx{})
+	c.RegisterType(&ExportTx{})
+	c.RegisterType(&MemoTx{})
+	return c
+}
+
+// Builder creates and signs txs without access to a node. The UTXOs being
+// spent and the keys that control them are supplied by the caller, so txs can
+// be built on an offline machine and issued with avm.issueRawTx.
+type Builder struct {
+	NetworkID uint32
+	ChainID   ids.ID
+	Codec     codec.Codec
+}
+
+// Spend returns inputs that consume enough of [utxos] to cover [amounts], a
+// map from asset ID to the amount of that asset needed. The keys in [kc] that
+// must sign each input are also returned. Only UTXOs that are spendable at
+// [time] are consumed. Returns the amount of each asset that the inputs
+// consume, which may be more than requested.
+func (b *Builder) Spend(
+	utxos []*ava.UTXO,
+	kc *secp256k1fx.Keychain,
+	amounts map[[32]byte]uint64,
+	time uint64,
+) ([]*ava.TransferableInput, [][]*crypto.PrivateKeySECP256K1R, map[[32]byte]uint64, error) {
+	spent := make(map[[32]byte]uint64, len(amounts))
+	ins := []*ava.TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		amount, needed := amounts[assetKey]
+		if !needed || spent[assetKey] >= amount {
+			continue
+		}
+
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue // The keychain can't spend this UTXO
+		}
+		input, ok := inputIntf.(ava.Transferable)
+		if !ok {
+			continue // The UTXO isn't fungible
+		}
+		newSpent, err := math.Add64(spent[assetKey], input.Amount())
+		if err != nil {
+			return nil, nil, nil, errSpendOverflow
+		}
+		spent[assetKey] = newSpent
+
+		ins = append(ins, &ava.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  ava.Asset{ID: assetID},
+			In:     input,
+		})
+		keys = append(keys, signers)
+	}
+
+	for assetKey, amount := range amounts {
+		if spent[assetKey] < amount {
+			return nil, nil, nil, errInsufficientFunds
+		}
+	}
+
+	sortTransferableInputsWithSigners(ins, keys)
+	return ins, keys, spent, nil
+}
+
+// SpendFee returns inputs that consume enough of [utxos] to burn [fee] of
+// [feeAssetID], and an output that sends any change to [changeAddr]. The keys
+// in [kc] that must sign each input are also returned. If [fee] is 0, nothing
+// is spent.
+func (b *Builder) SpendFee(
+	utxos []*ava.UTXO,
+	kc *secp256k1fx.Keychain,
+	feeAssetID ids.ID,
+	fee uint64,
+	changeAddr ids.ShortID,
+	time uint64,
+) ([]*ava.TransferableInput, []*ava.TransferableOutput, [][]*crypto.PrivateKeySECP256K1R, error) {
+	if fee == 0 {
+		return nil, nil, nil, nil
+	}
+
+	feeKey := feeAssetID.Key()
+	ins, keys, spent, err := b.Spend(utxos, kc, map[[32]byte]uint64{feeKey: fee}, time)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	outs := []*ava.TransferableOutput(nil)
+	if change := spent[feeKey] - fee; change > 0 {
+		outs = append(outs, &ava.TransferableOutput{
+			Asset: ava.Asset{ID: feeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: change,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+	return ins, outs, keys, nil
+}
+
+// SendOutput is an amount of an asset sent to an owner by a send tx. The
+// owner can't spend the funds until [Locktime].
+type SendOutput struct {
+	AssetID  ids.ID
+	Amount   uint64
+	To       *secp256k1fx.OutputOwners
+	Locktime uint64
+}
+
+// NewSendTx returns a signed tx that sends [amount] of [assetID] to [to] and
+// burns [fee] of [feeAssetID]. [to] can't spend the funds until [locktime].
+// The tx spends [utxos] using the keys in [kc]. Any change is sent to
+// [changeAddr]. [time] is the current chain time, used to skip UTXOs that are
+// still locked.
+func (b *Builder) NewSendTx(
+	utxos []*ava.UTXO,
+	kc *secp256k1fx.Keychain,
+	assetID ids.ID,
+	amount uint64,
+	feeAssetID ids.ID,
+	fee uint64,
+	to *secp256k1fx.OutputOwners,
+	locktime uint64,
+	changeAddr ids.ShortID,
+	time uint64,
+) (*Tx, error) {
+	return b.NewMultiSendTx(
+		utxos,
+		kc,
+		[]SendOutput{{
+			AssetID:  assetID,
+			Amount:   amount,
+			To:       to,
+			Locktime: locktime,
+		}},
+		feeAssetID,
+		fee,
+		changeAddr,
+		nil,
+		time,
+	)
+}
+
+// NewMultiSendTx returns a signed tx that makes each of the [sends] and burns
+// [fee] of [feeAssetID]. The tx spends [utxos] using the keys in [kc]. Any
+// change is sent to [changeAddr]. If [memo] isn't empty, the tx is a MemoTx
+// that carries it. [time] is the current chain time, used to skip UTXOs that
+// are still locked.
+func (b *Builder) NewMultiSendTx(
+	utxos []*ava.UTXO,
+	kc *secp256k1fx.Keychain,
+	sends []SendOutput,
+	feeAssetID ids.ID,
+	fee uint64,
+	changeAddr ids.ShortID,
+	memo []byte,
+	time uint64,
+) (*Tx, error) {
+	if len(sends) == 0 {
+		return nil, errNoOutputs
+	}
+	if len(memo) > MaxMemoSize {
+		return nil, errMemoTooLarge
+	}
+
+	amounts := map[[32]byte]uint64{}
+	// The assets change may be returned in, in the order they're first sent
+	changeAssetIDs := []ids.ID{}
+	outs := make([]*ava.TransferableOutput, 0, len(sends))
+	for _, send := range sends {
+		if send.Amount == 0 {
+			return nil, errInvalidAmount
+		}
+		assetKey := send.AssetID.Key()
+		if _, exists := amounts[assetKey]; !exists {
+			changeAssetIDs = append(changeAssetIDs, send.AssetID)
+		}
+		newAmount, err := math.Add64(amounts[assetKey], send.Amount)
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		amounts[assetKey] = newAmount
+
+		outs = append(outs, &ava.TransferableOutput{
+			Asset: ava.Asset{ID: send.AssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          send.Amount,
+				Locktime:     send.Locktime,
+				OutputOwners: *send.To,
+			},
+		})
+	}
+	if fee != 0 {
+		feeKey := feeAssetID.Key()
+		if _, exists := amounts[feeKey]; !exists {
+			changeAssetIDs = append(changeAssetIDs, feeAssetID)
+		}
+		newAmount, err := math.Add64(amounts[feeKey], fee)
+		if err != nil {
+			return nil, errSpendOverflow
+		}
+		amounts[feeKey] = newAmount
+	}
+
+	ins, keys, spent, err := b.Spend(utxos, kc, amounts, time)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, changeAssetID := range changeAssetIDs {
+		assetKey := changeAssetID.Key()
+		change := spent[assetKey] - amounts[assetKey]
+		if change == 0 {
+			continue
+		}
+		outs = append(outs, &ava.TransferableOutput{
+			Asset: ava.Asset{ID: changeAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: change,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+	ava.SortTransferableOutputs(outs, b.Codec)
+
+	baseTx := BaseTx{
+		NetID: b.NetworkID,
+		BCID:  b.ChainID,
+		Outs:  outs,
+		Ins:   ins,
+	}
+	tx := &Tx{UnsignedTx: &baseTx}
+	if len(memo) != 0 {
+		tx.UnsignedTx = &MemoTx{
+			BaseTx: baseTx,
+			Memo:   memo,
+		}
+	}
+	return tx, b.Sign(tx, keys)
+}
+
+// Sign adds a secp256k1fx credential to [tx] for each of the provided sets of
+// signers, in order, and initializes the tx with its signed bytes
+func (b *Builder) Sign(tx *Tx, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	for _, keys := range signers {
+		if len(keys) == 0 {
+			return errNoKeys
+		}
+	}
+	if err := tx.SignSECP256K1Fx(b.Codec, signers); err != nil {
+		return err
+	}
+
+	txBytes, err := b.Codec.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	tx.Initialize(txBytes)
+	return nil
+}
+
+// Burned returns the amount of [assetID] that [tx] consumes but doesn't
+// produce
+func Burned(tx UnsignedTx, assetID ids.ID) (uint64, error) {
+	ins := tx.Inputs()
+	outs := tx.Outputs()
+	switch t := tx.(type) {
+	case *ImportTx:
+		ins = append(append([]*ava.TransferableInput(nil), ins...), t.ImportedIns...)
+	case *ExportTx:
+		outs = append(append([]*ava.TransferableOutput(nil), outs...), t.ExportedOuts...)
+	}
+
+	consumed := uint64(0)
+	for _, in := range ins {
+		if !in.AssetID().Equals(assetID) {
+			continue
+		}
+		newConsumed, err := math.Add64(consumed, in.Input().Amount())
+		if err != nil {
+			return 0, errInputOverflow
+		}
+		consumed = newConsumed
+	}
+	produced := uint64(0)
+	for _, out := range outs {
+		if !out.AssetID().Equals(assetID) {
+			continue
+		}
+		newProduced, err := math.Add64(produced, out.Output().Amount())
+		if err != nil {
+			return 0, errOutputOverflow
+		}
+		produced = newProduced
+	}
+
+	if produced >= consumed {
+		return 0, nil
+	}
+	return consumed - produced, nil
+}
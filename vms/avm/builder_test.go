@@ -0,0 +1,238 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// newOfflineSendTx builds a signed send of asset1 from keys[0] to keys[1],
+// using only the UTXOs and keys a wallet would hold
+func newOfflineSendTx(t *testing.T, vm *VM, fee uint64) (*Tx, ids.ID) {
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := vm.GetUTXOs(addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	kc.Add(keys[0])
+
+	builder := Builder{
+		NetworkID: networkID,
+		ChainID:   chainID,
+		Codec:     vm.codec,
+	}
+	tx, err := builder.NewSendTx(
+		utxos,
+		kc,
+		assetID,
+		10,
+		assetID,
+		fee,
+		&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{keys[1].PublicKey().Address()},
+		},
+		0,
+		addr,
+		vm.clock.Unix(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, assetID
+}
+
+func TestBuilderIssueRawTx(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	tx, _ := newOfflineSendTx(t, vm, 0)
+
+	s := &Service{vm: vm}
+	reply := IssueTxReply{}
+	if err := s.IssueRawTx(nil, &IssueTxArgs{Tx: formatting.CB58{Bytes: tx.Bytes()}.String()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.TxID.Equals(tx.ID()) {
+		t.Fatalf("Issued the wrong tx")
+	}
+	if !vm.mempool.Has(tx.ID()) {
+		t.Fatalf("Tx should be pending issuance")
+	}
+}
+
+func TestBuilderFee(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	tx, assetID := newOfflineSendTx(t, vm, 5)
+
+	burned, err := Burned(tx.UnsignedTx, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if burned != 5 {
+		t.Fatalf("Tx should have burned %d but burned %d", 5, burned)
+	}
+	if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilderSpendFee(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	addr := keys[0].PublicKey().Address()
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	utxos, err := vm.GetUTXOs(addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kc := secp256k1fx.NewKeychain()
+	kc.Add(keys[0])
+
+	builder := Builder{
+		NetworkID: networkID,
+		ChainID:   chainID,
+		Codec:     vm.codec,
+	}
+	if ins, outs, signers, err := builder.SpendFee(utxos, kc, assetID, 0, addr, vm.clock.Unix()); err != nil {
+		t.Fatal(err)
+	} else if len(ins) != 0 || len(outs) != 0 || len(signers) != 0 {
+		t.Fatalf("Shouldn't have spent anything to pay no fee")
+	}
+
+	ins, outs, signers, err := builder.SpendFee(utxos, kc, assetID, 5, addr, vm.clock.Unix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != len(signers) {
+		t.Fatalf("Should have returned the signers of each input")
+	}
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs:  outs,
+		Ins:   ins,
+	}}
+	if burned, err := Burned(tx.UnsignedTx, assetID); err != nil {
+		t.Fatal(err)
+	} else if burned != 5 {
+		t.Fatalf("Tx should have burned %d but burned %d", 5, burned)
+	}
+	if err := builder.Sign(tx, signers); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.ava = assetID
+	vm.txFee = 5
+	if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := builder.SpendFee(utxos, kc, ids.Empty, 5, addr, vm.clock.Unix()); err != errInsufficientFunds {
+		t.Fatalf("Should have errored with %v but got %v", errInsufficientFunds, err)
+	}
+}
+
+func TestTxInsufficientFee(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	tx, assetID := newOfflineSendTx(t, vm, 4)
+
+	vm.ava = assetID
+	// The creation fee isn't charged to txs that don't create an asset
+	vm.txFee = 4
+	vm.creationTxFee = 5
+	if err := tx.verifyFee(vm); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.txFee = 5
+	if _, err := vm.IssueTx(tx.Bytes()); err != errInsufficientFee {
+		t.Fatalf("Should have errored with %v but got %v", errInsufficientFee, err)
+	}
+}
+
+func TestIssueRawTxMissingSignature(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	tx, _ := newOfflineSendTx(t, vm, 0)
+	cred := tx.Creds[0].Cred.(*secp256k1fx.Credential)
+	cred.Sigs[0] = emptySig
+
+	txBytes, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{vm: vm}
+	if err := s.IssueRawTx(nil, &IssueTxArgs{Tx: formatting.HexEncoding.Encode(txBytes), Encoding: formatting.HexEncoding}, &IssueTxReply{}); err != errMissingSignatures {
+		t.Fatalf("Should have errored due to a missing signature")
+	}
+}
+
+func TestNewCodec(t *testing.T) {
+	c := NewCodec()
+
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs: []*ava.TransferableOutput{&ava.TransferableOutput{
+			Asset: ava.Asset{ID: asset},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+				},
+			},
+		}},
+	}}
+	txBytes, err := c.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx := &Tx{}
+	if err := c.Unmarshal(txBytes, parsedTx); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsedTx.Outputs()[0].Out.(*secp256k1fx.TransferOutput); !ok {
+		t.Fatalf("Output should have been parsed as a secp256k1fx transfer output")
+	}
+}
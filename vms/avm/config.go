@@ -0,0 +1,20 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+// Config holds the settings of an AVM chain. It's read, as JSON, from the
+// configuration the chain is initialized with. Settings that aren't given keep
+// their default.
+type Config struct {
+	// IndexTransactions is true if accepted txs are indexed by the addresses
+	// they reference, so they can be returned by GetAddressTxs
+	IndexTransactions bool `json:"indexTransactions"`
+}
+
+// DefaultConfig returns the settings of a chain that isn't configured
+func DefaultConfig() Config {
+	return Config{
+		IndexTransactions: true,
+	}
+}
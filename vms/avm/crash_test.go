@@ -0,0 +1,99 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/corruptabledb"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// restartVM initializes a new VM over [db], as a node that was killed would
+// when it restarts
+// Assumes the context lock is held
+func restartVM(t *testing.T, db database.Database) *VM {
+	vm := &VM{}
+	if err := vm.Initialize(
+		ctx,
+		db,
+		BuildGenesisTest(t),
+		nil,
+		make(chan common.Message, 1),
+		[]*common.Fx{
+			&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			},
+			&common.Fx{
+				ID: nftfx.ID,
+				Fx: &nftfx.Fx{},
+			},
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+	return vm
+}
+
+// Kill the node after each write made while accepting a tx, and make sure that
+// when it restarts, either all or none of the tx's changes were made
+func TestAcceptCrashConsistency(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	for failAfter := 0; ; failAfter++ {
+		baseDB := memdb.New()
+		db := corruptabledb.New(baseDB)
+		vm, _ := setupKeystoreVMWithDB(t, db)
+
+		tx, _ := newOfflineSendTx(t, vm, 0)
+		if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+
+		db.FailWritesAfter(failAfter)
+		for _, pendingTx := range vm.PendingTxs() {
+			if err := pendingTx.Verify(); err != nil {
+				t.Fatal(err)
+			}
+			pendingTx.Accept()
+		}
+		crashed := db.Failed()
+		// The killed VM's database isn't closed, so only its timer is stopped
+		vm.timer.Stop()
+
+		restarted := restartVM(t, baseDB)
+		status, err := restarted.state.Status(tx.ID())
+		if err != nil && err != database.ErrNotFound {
+			t.Fatal(err)
+		}
+		accepted := status == choices.Accepted
+		if !crashed && !accepted {
+			t.Fatalf("The tx should have been accepted")
+		}
+
+		for _, utxoID := range tx.InputUTXOs() {
+			if _, err := restarted.state.UTXO(utxoID.InputID()); (err == nil) == accepted {
+				t.Fatalf("Failing after %d writes: the tx's accepted status is %v but its input %s wasn't spent accordingly", failAfter, accepted, utxoID.InputID())
+			}
+		}
+		for _, utxo := range tx.UTXOs() {
+			if _, err := restarted.state.UTXO(utxo.InputID()); (err == nil) != accepted {
+				t.Fatalf("Failing after %d writes: the tx's accepted status is %v but its output %s wasn't funded accordingly", failAfter, accepted, utxo.InputID())
+			}
+		}
+		restarted.Shutdown()
+
+		if !crashed {
+			return
+		}
+	}
+}
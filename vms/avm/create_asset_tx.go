@@ -10,6 +10,7 @@ import (
 	"unicode"
 
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 )
 
@@ -43,18 +44,18 @@ type CreateAssetTx struct {
 func (t *CreateAssetTx) InitialStates() []*InitialState { return t.States }
 
 // UTXOs returns the UTXOs transaction is producing.
-func (t *CreateAssetTx) UTXOs() []*UTXO {
+func (t *CreateAssetTx) UTXOs() []*ava.UTXO {
 	txID := t.ID()
 	utxos := t.BaseTx.UTXOs()
 
 	for _, state := range t.States {
 		for _, out := range state.Outs {
-			utxos = append(utxos, &UTXO{
-				UTXOID: UTXOID{
+			utxos = append(utxos, &ava.UTXO{
+				UTXOID: ava.UTXOID{
 					TxID:        txID,
 					OutputIndex: uint32(len(utxos)),
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: txID,
 				},
 				Out: out,
@@ -112,6 +113,19 @@ func (t *CreateAssetTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs
 
 // SemanticVerify that this transaction is well-formed.
 func (t *CreateAssetTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	// The initial outputs must be owned by the feature extension their state
+	// claims, otherwise they would be verified by the wrong one when spent
+	for _, state := range t.States {
+		for _, out := range state.Outs {
+			fxIndex, err := vm.getFx(out)
+			if err != nil {
+				return err
+			}
+			if uint32(fxIndex) != state.FxID {
+				return errIncompatibleFx
+			}
+		}
+	}
 	return t.BaseTx.SemanticVerify(vm, uTx, creds)
 }
 
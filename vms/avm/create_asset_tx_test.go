@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/verify"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
@@ -92,9 +93,9 @@ func TestCreateAssetTxSerialization(t *testing.T) {
 				0xbb, 0xbb, 0xbb, 0xbb, 0xaa, 0xaa, 0xaa, 0xaa,
 				0x99, 0x99, 0x99, 0x99, 0x88, 0x88, 0x88, 0x88,
 			}),
-			Outs: []*TransferableOutput{
-				&TransferableOutput{
-					Asset: Asset{
+			Outs: []*ava.TransferableOutput{
+				&ava.TransferableOutput{
+					Asset: ava.Asset{
 						ID: ids.NewID([32]byte{
 							0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
 							0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
@@ -123,9 +124,9 @@ func TestCreateAssetTxSerialization(t *testing.T) {
 					},
 				},
 			},
-			Ins: []*TransferableInput{
-				&TransferableInput{
-					UTXOID: UTXOID{
+			Ins: []*ava.TransferableInput{
+				&ava.TransferableInput{
+					UTXOID: ava.UTXOID{
 						TxID: ids.NewID([32]byte{
 							0xf1, 0xe1, 0xd1, 0xc1, 0xb1, 0xa1, 0x91, 0x81,
 							0x71, 0x61, 0x51, 0x41, 0x31, 0x21, 0x11, 0x01,
@@ -134,7 +135,7 @@ func TestCreateAssetTxSerialization(t *testing.T) {
 						}),
 						OutputIndex: 5,
 					},
-					Asset: Asset{
+					Asset: ava.Asset{
 						ID: ids.NewID([32]byte{
 							0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
 							0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
@@ -203,3 +204,50 @@ func TestCreateAssetTxSerialization(t *testing.T) {
 		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, result)
 	}
 }
+
+func TestCreateAssetTxIncompatibleFx(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	// The secp256k1fx is fx 0 and the nftfx is fx 1
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	newTx := func(fxID uint32) *Tx {
+		tx := &Tx{UnsignedTx: &CreateAssetTx{
+			BaseTx: BaseTx{
+				NetID: networkID,
+				BCID:  chainID,
+			},
+			Name:   "myAsset",
+			Symbol: "MA",
+			States: []*InitialState{
+				&InitialState{
+					FxID: fxID,
+					Outs: []verify.Verifiable{
+						&secp256k1fx.TransferOutput{
+							Amt: 12345,
+							OutputOwners: secp256k1fx.OutputOwners{
+								Threshold: 1,
+								Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+							},
+						},
+					},
+				},
+			},
+		}}
+		b, err := vm.codec.Marshal(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Initialize(b)
+		return tx
+	}
+
+	if _, err := vm.IssueTx(newTx(1).Bytes()); err == nil {
+		t.Fatalf("Should have errored because the nftfx doesn't own secp256k1fx outputs")
+	}
+	if _, err := vm.IssueTx(newTx(0).Bytes()); err != nil {
+		t.Fatal(err)
+	}
+}
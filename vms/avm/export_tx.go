@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportOutputs     = errors.New("no export outputs")
+	errWrongExportedOutput = errors.New("only secp256k1fx transfer outputs can be exported")
+)
+
+// ExportTx is a transaction that exports an asset to another blockchain.
+type ExportTx struct {
+	BaseTx `serialize:"true"`
+
+	DestinationChain ids.ID                    `serialize:"true"` // The chain that can import the exported outputs
+	ExportedOuts     []*ava.TransferableOutput `serialize:"true"` // The outputs that are placed into shared memory
+}
+
+// ExportedUTXOs returns the UTXOs that this transaction places into shared
+// memory. They are indexed after the outputs produced on this chain.
+func (t *ExportTx) ExportedUTXOs() []*ava.UTXO {
+	txID := t.ID()
+	utxos := make([]*ava.UTXO, len(t.ExportedOuts))
+	for i, out := range t.ExportedOuts {
+		utxos[i] = &ava.UTXO{
+			UTXOID: ava.UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(len(t.Outs) + i),
+			},
+			Asset: ava.Asset{
+				ID: out.AssetID(),
+			},
+			Out: out.Out,
+		}
+	}
+	return utxos
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ExportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ExportedOuts) == 0:
+		return errNoExportOutputs
+	}
+
+	for _, out := range t.ExportedOuts {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+	}
+	if !ava.IsSortedTransferableOutputs(t.ExportedOuts, c) {
+		return errOutputsNotSorted
+	}
+
+	return t.BaseTx.syntacticVerify(ctx, c, nil, t.ExportedOuts)
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ExportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	if vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	if !vm.isAtomicChain(t.DestinationChain) {
+		return errNotAtomicChain
+	}
+	for _, out := range t.ExportedOuts {
+		if assetID := out.AssetID(); !assetID.Equals(vm.ava) {
+			return errAssetNotAVA
+		}
+		if _, ok := out.Out.(*secp256k1fx.TransferOutput); !ok {
+			return errWrongExportedOutput
+		}
+	}
+	return t.BaseTx.SemanticVerify(vm, uTx, creds)
+}
+
+// ExecuteWithSideEffects places the exported UTXOs into shared memory. The
+// UTXOs are written atomically with [batch].
+func (t *ExportTx) ExecuteWithSideEffects(vm *VM, batch database.Batch) error {
+	if vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(t.DestinationChain)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(t.DestinationChain)
+
+	vsmDB := versiondb.New(smDB)
+
+	state := NewSharedState(vsmDB, t.DestinationChain)
+	for _, utxo := range t.ExportedUTXOs() {
+		if err := state.FundUTXO(utxo); err != nil {
+			return err
+		}
+	}
+
+	sharedBatch, err := vsmDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return core.WriteAll(batch, sharedBatch)
+}
@@ -0,0 +1,170 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/core"
+)
+
+var (
+	platformChainID = ids.Empty
+	evmChainID      = ids.Empty.Prefix(0)
+)
+
+// setupAtomicVM creates a keystore backed VM that treats asset1 as AVA and
+// that has access to shared memory. The context lock must be held by the
+// caller and the returned function must be called once the test completes.
+func setupAtomicVM(t *testing.T) (*VM, *Service, *core.SharedMemory, func()) {
+	// The chain's state and shared memory must share a base database so that
+	// atomic txs can write to both in one batch
+	baseDB := memdb.New()
+	vm, s := setupKeystoreVMWithDB(t, prefixdb.New([]byte{0}, baseDB))
+
+	sm := &core.SharedMemory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte{1}, baseDB))
+	ctx.SharedMemory = sm.NewBlockchainSharedMemory(chainID)
+
+	ava, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = ava
+	vm.platform = platformChainID
+	vm.evm = evmChainID
+
+	return vm, s, sm, func() {
+		ctx.SharedMemory = nil
+		vm.Shutdown()
+	}
+}
+
+func TestServiceExportAVA(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, sm, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+	to := keys[1].PublicKey().Address()
+
+	if err := s.ExportAVA(nil, &ExportAVAArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		To:       vm.Format(to.Bytes()),
+	}, &ExportAVAReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: addr,
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 300000-100 {
+		t.Fatalf("Wrong balance after exporting. Expected %d, got %d", 300000-100, balance.Balance)
+	}
+
+	platformSM := sm.NewBlockchainSharedMemory(platformChainID)
+	smDB := platformSM.GetDatabase(chainID)
+	defer platformSM.ReleaseDatabase(chainID)
+
+	state := NewSharedState(smDB, platformChainID)
+	utxoIDs, err := state.Funds(ids.NewID(hashing.ComputeHash256Array(to.Bytes())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("Expected %d exported utxo(s), but found %d", 1, len(utxoIDs))
+	}
+	utxo, err := state.UTXO(utxoIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, ok := utxo.Out.(ava.Transferable); !ok || out.Amount() != 100 {
+		t.Fatalf("Exported the wrong output")
+	}
+}
+
+func TestServiceExportAVAToEVM(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, sm, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	to := keys[1].PublicKey().Address()
+
+	if err := s.ExportAVA(nil, &ExportAVAArgs{
+		Username:         testUsername,
+		Password:         testPassword,
+		Amount:           100,
+		To:               vm.Format(to.Bytes()),
+		DestinationChain: evmChainID.String(),
+	}, &ExportAVAReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	evmSM := sm.NewBlockchainSharedMemory(evmChainID)
+	smDB := evmSM.GetDatabase(chainID)
+	defer evmSM.ReleaseDatabase(chainID)
+
+	utxoIDs, err := NewSharedState(smDB, evmChainID).Funds(ids.NewID(hashing.ComputeHash256Array(to.Bytes())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("Expected %d exported utxo(s), but found %d", 1, len(utxoIDs))
+	}
+
+	// Nothing was exported to the platform chain
+	platformSM := sm.NewBlockchainSharedMemory(platformChainID)
+	platformDB := platformSM.GetDatabase(chainID)
+	defer platformSM.ReleaseDatabase(chainID)
+
+	if utxoIDs, _ := NewSharedState(platformDB, platformChainID).Funds(ids.NewID(hashing.ComputeHash256Array(to.Bytes()))); len(utxoIDs) != 0 {
+		t.Fatalf("Shouldn't have exported to the platform chain")
+	}
+}
+
+func TestServiceExportAVAToUnknownChain(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, _, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	if err := s.ExportAVA(nil, &ExportAVAArgs{
+		Username:         testUsername,
+		Password:         testPassword,
+		Amount:           100,
+		To:               vm.Format(keys[1].PublicKey().Address().Bytes()),
+		DestinationChain: ids.Empty.Prefix(1).String(),
+	}, &ExportAVAReply{}); err == nil {
+		t.Fatalf("Should have errored due to AVA not being movable to the chain")
+	}
+}
+
+func TestExportTxSyntacticVerifyNoExportedOuts(t *testing.T) {
+	tx := &ExportTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}}
+	if err := tx.SyntacticVerify(ctx, nil, 0); err == nil {
+		t.Fatalf("Should have errored due to not exporting any outputs")
+	}
+}
@@ -13,7 +13,26 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct {
+	AVA         ids.ID
+	Platform    ids.ID
+	EVM         ids.ID // The C-Chain's ID, if AVA can be moved to and from it
+	MempoolSize int
+
+	// AVA, in nAVA, burned by each transaction that doesn't create an asset
+	TxFee uint64
+	// AVA, in nAVA, burned by each transaction that creates an asset
+	CreationTxFee uint64
+}
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{
+		ava:           f.AVA,
+		platform:      f.Platform,
+		evm:           f.EVM,
+		mempoolSize:   f.MempoolSize,
+		txFee:         f.TxFee,
+		creationTxFee: f.CreationTxFee,
+	}
+}
@@ -5,7 +5,6 @@ package avm
 
 import (
 	"github.com/ava-labs/gecko/ids"
-	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
 type parsedFx struct {
@@ -14,9 +13,21 @@ type parsedFx struct {
 }
 
 // Fx is the interface a feature extension must implement to support the AVM.
+//
+// The feature extensions a chain runs are given to the VM, in order, when it's
+// initialized. An asset names the feature extensions it supports by their
+// index in that order, in the FxID of its initial states. Every output, input,
+// operation and credential type is owned by the feature extension that
+// registered it, and the VM dispatches verification of each of them to their
+// owner.
 type Fx interface {
 	// Initialize this feature extension to be running under this VM. Should
 	// return an error if the VM is incompatible.
+	//
+	// The feature extension should register all of its types with the VM's
+	// codec here. The VM records that the types belong to this feature
+	// extension. Registration must be deterministic, as the order of
+	// registration defines the type IDs used in serialization.
 	Initialize(vm interface{}) error
 
 	// VerifyTransfer verifies that the specified transaction can spend the
@@ -32,14 +43,12 @@ type Fx interface {
 	VerifyOperation(tx interface{}, utxos, ins, creds, outs []interface{}) error
 }
 
-// FxTransferable is the interface a feature extension must provide to transfer
-// value between features extensions.
-type FxTransferable interface {
-	verify.Verifiable
-
-	// Amount returns how much value this output consumes of the asset in its
-	// transaction.
-	Amount() uint64
+// FxSignerRecoverer is the interface a feature extension may provide to recover
+// the signers of all the credentials of a transaction it owns at once, before
+// they're verified one at a time. Recovering the signers in a batch is faster
+// than recovering them one at a time.
+type FxSignerRecoverer interface {
+	RecoverSigners(tx interface{}, creds []interface{})
 }
 
 // FxAddressable is the interface a feature extension must provide to be able to
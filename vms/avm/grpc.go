@@ -0,0 +1,221 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// Number of accepted transactions buffered for a subscriber before it's
+	// considered to have fallen behind
+	subscriberBufferSize = 1024
+)
+
+var (
+	errSubscriberTooSlow = errors.New("subscriber fell behind the accepted transactions")
+
+	// Used to give each subscriber a unique name in the decision dispatcher
+	numSubscribers uint64
+)
+
+// GRPCServer serves the AVM API of a chain over gRPC
+// The chain of a request is expected to have been resolved by the caller, so
+// its Chain field is ignored.
+type GRPCServer struct {
+	apiproto.UnimplementedAVMServer
+
+	service *Service
+}
+
+// NewGRPCServer returns a gRPC server that answers requests using [vm]
+func NewGRPCServer(vm *VM) *GRPCServer { return &GRPCServer{service: &Service{vm: vm}} }
+
+// IssueTx implements the apiproto.AVMServer interface
+func (s *GRPCServer) IssueTx(_ context.Context, req *apiproto.AVMIssueTxRequest) (*apiproto.AVMIssueTxResponse, error) {
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	txID, err := s.service.vm.IssueTx(req.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &apiproto.AVMIssueTxResponse{TxId: txID.String()}, nil
+}
+
+// GetTxStatus implements the apiproto.AVMServer interface
+func (s *GRPCServer) GetTxStatus(_ context.Context, req *apiproto.GetTxStatusRequest) (*apiproto.GetTxStatusResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing txID '%s': %w", req.TxId, err)
+	}
+
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	reply := GetTxStatusReply{}
+	if err := s.service.GetTxStatus(nil, &GetTxStatusArgs{TxID: txID}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetTxStatusResponse{Status: reply.Status.String()}, nil
+}
+
+// GetTx implements the apiproto.AVMServer interface
+func (s *GRPCServer) GetTx(_ context.Context, req *apiproto.GetTxRequest) (*apiproto.GetTxResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing txID '%s': %w", req.TxId, err)
+	}
+
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	txBytes, err := s.service.getTx(txID)
+	if err != nil {
+		return nil, err
+	}
+	return &apiproto.GetTxResponse{Tx: txBytes}, nil
+}
+
+// GetUTXOs implements the apiproto.AVMServer interface
+func (s *GRPCServer) GetUTXOs(_ context.Context, req *apiproto.GetUTXOsRequest) (*apiproto.GetUTXOsResponse, error) {
+	args := GetUTXOsArgs{
+		Addresses: req.Addresses,
+		Limit:     json.Uint32(req.Limit),
+		// Hex is the cheapest encoding to decode the UTXOs from
+		Encoding: formatting.HexEncoding,
+	}
+	if req.StartIndex != nil {
+		args.StartIndex.Address = req.StartIndex.Address
+		if req.StartIndex.Utxo != "" {
+			utxoID, err := ids.FromString(req.StartIndex.Utxo)
+			if err != nil {
+				return nil, fmt.Errorf("problem parsing start index UTXO '%s': %w", req.StartIndex.Utxo, err)
+			}
+			args.StartIndex.UTXO = utxoID
+		}
+	}
+
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	reply := GetUTXOsReply{}
+	if err := s.service.GetUTXOs(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	resp := &apiproto.GetUTXOsResponse{
+		Utxos: make([][]byte, len(reply.UTXOs)),
+		More:  reply.More,
+	}
+	for i, utxo := range reply.UTXOs {
+		utxoBytes, err := formatting.HexEncoding.Decode(utxo)
+		if err != nil {
+			return nil, err
+		}
+		resp.Utxos[i] = utxoBytes
+	}
+	if reply.EndIndex.Address != "" {
+		resp.EndIndex = &apiproto.UTXOIndex{
+			Address: reply.EndIndex.Address,
+			Utxo:    reply.EndIndex.UTXO.String(),
+		}
+	}
+	return resp, nil
+}
+
+// GetBalance implements the apiproto.AVMServer interface
+func (s *GRPCServer) GetBalance(_ context.Context, req *apiproto.GetBalanceRequest) (*apiproto.GetBalanceResponse, error) {
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	reply := GetBalanceReply{}
+	if err := s.service.GetBalance(nil, &GetBalanceArgs{Address: req.Address, AssetID: req.AssetId}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetBalanceResponse{Balance: uint64(reply.Balance)}, nil
+}
+
+// GetAssetDescription implements the apiproto.AVMServer interface
+func (s *GRPCServer) GetAssetDescription(_ context.Context, req *apiproto.GetAssetDescriptionRequest) (*apiproto.GetAssetDescriptionResponse, error) {
+	s.service.vm.ctx.Lock.Lock()
+	defer s.service.vm.ctx.Lock.Unlock()
+
+	reply := GetAssetDescriptionReply{}
+	if err := s.service.GetAssetDescription(nil, &GetAssetDescriptionArgs{AssetID: req.AssetId}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetAssetDescriptionResponse{
+		AssetId:      reply.AssetID.String(),
+		Name:         reply.Name,
+		Symbol:       reply.Symbol,
+		Denomination: uint32(reply.Denomination),
+	}, nil
+}
+
+// SubscribeAcceptedTxs implements the apiproto.AVMServer interface
+// Transactions are streamed as they're accepted until the client goes away. If
+// the client doesn't keep up with the chain, the stream is ended with an error
+// rather than letting it hold up consensus.
+func (s *GRPCServer) SubscribeAcceptedTxs(_ *apiproto.SubscribeAcceptedTxsRequest, stream apiproto.AVM_SubscribeAcceptedTxsServer) error {
+	ctx := s.service.vm.ctx
+	acceptor := &txAcceptor{txs: make(chan *apiproto.AcceptedTx, subscriberBufferSize)}
+	name := fmt.Sprintf("grpc-subscriber-%d", atomic.AddUint64(&numSubscribers, 1))
+	if err := ctx.DecisionDispatcher.RegisterChain(ctx.ChainID, name, acceptor); err != nil {
+		return err
+	}
+	defer func() {
+		if err := ctx.DecisionDispatcher.DeregisterChain(ctx.ChainID, name); err != nil {
+			ctx.Log.Warn("failed to deregister %s: %s", name, err)
+		}
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case tx, ok := <-acceptor.txs:
+			if !ok {
+				return errSubscriberTooSlow
+			}
+			if err := stream.Send(tx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// txAcceptor passes the transactions accepted by a chain to a subscriber
+// without blocking. Once the subscriber's buffer is full, [txs] is closed and
+// later transactions are dropped.
+type txAcceptor struct {
+	lock   sync.Mutex
+	closed bool
+	txs    chan *apiproto.AcceptedTx
+}
+
+// Accept implements the triggers.Acceptor interface
+func (a *txAcceptor) Accept(_, txID ids.ID, tx []byte) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	select {
+	case a.txs <- &apiproto.AcceptedTx{TxId: txID.String(), Tx: tx}:
+	default:
+		a.closed = true
+		close(a.txs)
+	}
+	return nil
+}
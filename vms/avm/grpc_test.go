@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+// testTxStream is a SubscribeAcceptedTxs stream that passes sent transactions
+// to [txs]
+type testTxStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	txs chan *apiproto.AcceptedTx
+}
+
+func (s *testTxStream) Context() context.Context { return s.ctx }
+
+func (s *testTxStream) Send(tx *apiproto.AcceptedTx) error {
+	s.txs <- tx
+	return nil
+}
+
+func TestGRPCServer(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+	vm := GenesisVM(t)
+	defer func() {
+		ctx.Lock.Lock()
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	server := NewGRPCServer(vm)
+
+	statusResp, err := server.GetTxStatus(context.Background(), &apiproto.GetTxStatusRequest{TxId: genesisTx.ID().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusResp.Status != choices.Accepted.String() {
+		t.Fatalf("Expected status %s but got %s", choices.Accepted, statusResp.Status)
+	}
+
+	txResp, err := server.GetTx(context.Background(), &apiproto.GetTxRequest{TxId: genesisTx.ID().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(txResp.Tx, genesisTx.Bytes()) {
+		t.Fatalf("Wrong tx returned")
+	}
+
+	assetResp, err := server.GetAssetDescription(context.Background(), &apiproto.GetAssetDescriptionRequest{AssetId: genesisTx.ID().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assetResp.Name != "myFixedCapAsset" || assetResp.Symbol != "MFCA" {
+		t.Fatalf("Wrong asset description %+v", assetResp)
+	}
+
+	if _, err := server.GetTx(context.Background(), &apiproto.GetTxRequest{TxId: "not an ID"}); err == nil {
+		t.Fatalf("Should have errored because the tx ID is invalid")
+	}
+}
+
+func TestGRPCSubscribeAcceptedTxs(t *testing.T) {
+	server := NewGRPCServer(&VM{ctx: ctx})
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream := &testTxStream{ctx: streamCtx, txs: make(chan *apiproto.AcceptedTx, 1)}
+	done := make(chan error, 1)
+	go func() { done <- server.SubscribeAcceptedTxs(&apiproto.SubscribeAcceptedTxsRequest{}, stream) }()
+
+	// Transactions accepted before the subscriber registers aren't sent, so
+	// keep accepting until one is
+	txID := ids.NewID([32]byte{1})
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(5 * time.Second)
+	var tx *apiproto.AcceptedTx
+	for tx == nil {
+		select {
+		case <-ticker.C:
+			ctx.DecisionDispatcher.Accept(ctx.ChainID, txID, []byte{1, 2, 3})
+		case tx = <-stream.txs:
+		case <-timeout:
+			t.Fatalf("Timed out waiting for the accepted tx")
+		}
+	}
+	if tx.TxId != txID.String() || !bytes.Equal(tx.Tx, []byte{1, 2, 3}) {
+		t.Fatalf("Wrong tx sent %+v", tx)
+	}
+
+	cancel()
+	// Drain the stream so that the subscriber isn't blocked sending
+	go func() {
+		for range stream.txs {
+		}
+	}()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Expected %s but got %v", context.Canceled, err)
+	}
+}
+
+func TestTxAcceptorFallsBehind(t *testing.T) {
+	acceptor := &txAcceptor{txs: make(chan *apiproto.AcceptedTx, 1)}
+	if err := acceptor.Accept(ids.Empty, ids.NewID([32]byte{1}), nil); err != nil {
+		t.Fatal(err)
+	}
+	// The buffer is full, so the subscriber has fallen behind
+	if err := acceptor.Accept(ids.Empty, ids.NewID([32]byte{2}), nil); err != nil {
+		t.Fatal(err)
+	}
+	// Later transactions are dropped rather than sent on the closed channel
+	if err := acceptor.Accept(ids.Empty, ids.NewID([32]byte{3}), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if tx, ok := <-acceptor.txs; !ok || tx.TxId != ids.NewID([32]byte{1}).String() {
+		t.Fatalf("The buffered tx should have been kept")
+	}
+	if _, ok := <-acceptor.txs; ok {
+		t.Fatalf("The channel should have been closed")
+	}
+}
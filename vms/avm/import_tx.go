@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/core"
+)
+
+var (
+	errNoImportInputs = errors.New("no import inputs")
+	errAssetNotAVA    = errors.New("only AVA can be moved between chains")
+	errNoSharedMemory = errors.New("shared memory is not available")
+	errNotAtomicChain = errors.New("AVA can't be moved between this chain and the given chain")
+)
+
+// ImportTx is a transaction that imports an asset from another blockchain.
+type ImportTx struct {
+	BaseTx `serialize:"true"`
+
+	SourceChain ids.ID                   `serialize:"true"` // The chain the imported UTXOs were exported from
+	ImportedIns []*ava.TransferableInput `serialize:"true"` // The inputs that are consumed from shared memory
+}
+
+// InputUTXOs track which UTXOs this transaction is consuming.
+func (t *ImportTx) InputUTXOs() []*ava.UTXOID {
+	utxos := t.BaseTx.InputUTXOs()
+	for _, in := range t.ImportedIns {
+		// The imported UTXOs don't exist in this chain's state, so they are
+		// reported as symbolic without modifying the input
+		utxoID := in.UTXOID
+		utxoID.Symbol = true
+		utxos = append(utxos, &utxoID)
+	}
+	return utxos
+}
+
+// AssetIDs returns the IDs of the assets this transaction depends on
+func (t *ImportTx) AssetIDs() ids.Set {
+	assets := t.BaseTx.AssetIDs()
+	for _, in := range t.ImportedIns {
+		assets.Add(in.AssetID())
+	}
+	return assets
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ImportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ImportedIns) == 0:
+		return errNoImportInputs
+	}
+
+	for _, in := range t.ImportedIns {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+	}
+	if !ava.IsSortedAndUniqueTransferableInputs(t.ImportedIns) {
+		return errInputsNotSortedUnique
+	}
+
+	return t.BaseTx.syntacticVerify(ctx, c, t.ImportedIns, nil)
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ImportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	if err := t.BaseTx.SemanticVerify(vm, uTx, creds); err != nil {
+		return err
+	}
+
+	if vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	if !vm.isAtomicChain(t.SourceChain) {
+		return errNotAtomicChain
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(t.SourceChain)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(t.SourceChain)
+
+	state := NewSharedState(smDB, vm.ctx.ChainID)
+
+	offset := len(t.Ins)
+	for i, in := range t.ImportedIns {
+		cred := creds[i+offset]
+
+		fxIndex, err := vm.getFx(cred.Cred)
+		if err != nil {
+			return err
+		}
+		fx := vm.fxs[fxIndex].Fx
+
+		inAssetID := in.AssetID()
+		if !inAssetID.Equals(vm.ava) {
+			return errAssetNotAVA
+		}
+
+		utxo, err := state.UTXO(in.InputID())
+		if err != nil {
+			return errMissingUTXO
+		}
+		if utxoAssetID := utxo.AssetID(); !utxoAssetID.Equals(inAssetID) {
+			return errAssetIDMismatch
+		}
+
+		if !vm.verifyFxUsage(fxIndex, inAssetID) {
+			return errIncompatibleFx
+		}
+
+		if err := fx.VerifyTransfer(uTx, utxo.Out, in.In, cred.Cred); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecuteWithSideEffects removes the imported UTXOs from shared memory. The
+// removal is written atomically with [batch].
+func (t *ImportTx) ExecuteWithSideEffects(vm *VM, batch database.Batch) error {
+	if vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(t.SourceChain)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(t.SourceChain)
+
+	vsmDB := versiondb.New(smDB)
+
+	state := NewSharedState(vsmDB, vm.ctx.ChainID)
+	for _, in := range t.ImportedIns {
+		if err := state.SpendUTXO(in.InputID()); err != nil {
+			return err
+		}
+	}
+
+	sharedBatch, err := vsmDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return core.WriteAll(batch, sharedBatch)
+}
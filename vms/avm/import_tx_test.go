@@ -0,0 +1,197 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestServiceImportAVA(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, sm, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	// Simulate the platform chain exporting AVA to keys[0]
+	platformSM := sm.NewBlockchainSharedMemory(platformChainID)
+	smDB := platformSM.GetDatabase(chainID)
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: ids.NewID([32]byte{9})},
+		Asset:  ava.Asset{ID: vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 50,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+			},
+		},
+	}
+	if err := NewSharedState(smDB, chainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	platformSM.ReleaseDatabase(chainID)
+
+	if err := s.ImportAVA(nil, &ImportAVAArgs{
+		Username: testUsername,
+		Password: testPassword,
+		To:       addr,
+	}, &ImportAVAReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: addr,
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 300000+50 {
+		t.Fatalf("Wrong balance after importing. Expected %d, got %d", 300000+50, balance.Balance)
+	}
+
+	if err := s.ImportAVA(nil, &ImportAVAArgs{
+		Username: testUsername,
+		Password: testPassword,
+		To:       addr,
+	}, &ImportAVAReply{}); err == nil {
+		t.Fatalf("Should have errored due to the UTXO already being imported")
+	}
+}
+
+func TestServiceImportAVAFromEVM(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, sm, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	// Simulate the C-Chain exporting AVA to keys[0]
+	evmSM := sm.NewBlockchainSharedMemory(evmChainID)
+	smDB := evmSM.GetDatabase(chainID)
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: ids.NewID([32]byte{9})},
+		Asset:  ava.Asset{ID: vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 50,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+			},
+		},
+	}
+	if err := NewSharedState(smDB, chainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	evmSM.ReleaseDatabase(chainID)
+
+	// The AVA was exported from the C-Chain, not the platform chain
+	if err := s.ImportAVA(nil, &ImportAVAArgs{
+		Username: testUsername,
+		Password: testPassword,
+		To:       addr,
+	}, &ImportAVAReply{}); err == nil {
+		t.Fatalf("Should have errored due to the platform chain not exporting any AVA")
+	}
+
+	if err := s.ImportAVA(nil, &ImportAVAArgs{
+		Username:    testUsername,
+		Password:    testPassword,
+		To:          addr,
+		SourceChain: evmChainID.String(),
+	}, &ImportAVAReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: addr,
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 300000+50 {
+		t.Fatalf("Wrong balance after importing. Expected %d, got %d", 300000+50, balance.Balance)
+	}
+}
+
+func TestImportTxSyntacticVerifyNoImportedIns(t *testing.T) {
+	tx := &ImportTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+	}}
+	if err := tx.SyntacticVerify(ctx, nil, 0); err == nil {
+		t.Fatalf("Should have errored due to not importing any inputs")
+	}
+}
+
+func TestServiceGetAtomicUTXOs(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s, sm, shutdown := setupAtomicVM(t)
+	defer shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	// Simulate the platform chain exporting AVA to keys[0]
+	platformSM := sm.NewBlockchainSharedMemory(platformChainID)
+	smDB := platformSM.GetDatabase(chainID)
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: ids.NewID([32]byte{9})},
+		Asset:  ava.Asset{ID: vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 50,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+			},
+		},
+	}
+	if err := NewSharedState(smDB, chainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	platformSM.ReleaseDatabase(chainID)
+
+	reply := GetUTXOsReply{}
+	if err := s.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses:   []string{addr},
+		SourceChain: platformChainID.String(),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) != 1 {
+		t.Fatalf("Should have returned %d UTXOs but returned %d", 1, len(reply.UTXOs))
+	}
+	if reply.More {
+		t.Fatalf("Shouldn't have more UTXOs to return")
+	}
+	expected, err := vm.codec.Marshal(utxo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedStr := formatting.CB58{Bytes: expected}.String()
+	if reply.UTXOs[0] != expectedStr {
+		t.Fatalf("Should have returned %s but returned %s", expectedStr, reply.UTXOs[0])
+	}
+
+	if err := s.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses:   []string{addr},
+		SourceChain: chainID.String(),
+	}, &GetUTXOsReply{}); err == nil {
+		t.Fatalf("Should have errored due to the source chain being this chain")
+	}
+}
@@ -0,0 +1,43 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+const (
+	// MaxMemoSize is the maximum number of bytes in the memo of a MemoTx
+	MaxMemoSize = 256
+)
+
+var (
+	errNoMemo       = errors.New("memo tx has no memo")
+	errMemoTooLarge = errors.New("memo is too large")
+)
+
+// MemoTx is a BaseTx with a memo. The chain doesn't interpret the memo. It
+// lets the recipient attribute the transfer, for example an exchange
+// attributing a deposit to one of its accounts.
+type MemoTx struct {
+	BaseTx `serialize:"true"`
+
+	Memo []byte `serialize:"true"`
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *MemoTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.Memo) == 0:
+		return errNoMemo
+	case len(t.Memo) > MaxMemoSize:
+		return errMemoTooLarge
+	}
+	return t.BaseTx.SyntacticVerify(ctx, c, numFxs)
+}
@@ -0,0 +1,341 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+)
+
+const (
+	// defaultMempoolSize is the number of txs the mempool holds if no size is
+	// configured
+	defaultMempoolSize = 4096
+)
+
+var (
+	errMempoolFull     = errors.New("mempool is full and the tx doesn't pay enough to evict another tx")
+	errDuplicateIssued = errors.New("tx is already pending issuance")
+)
+
+type mempoolTx struct {
+	tx  snowstorm.Tx
+	fee uint64
+	seq uint64 // order in which the tx arrived
+
+	index   int  // index in the ready heap, if the tx is ready
+	blocked bool // true if the tx is waiting on an issued tx to be decided
+}
+
+// higherPriority returns true if [a] should be issued before [b]. Txs that pay
+// a higher fee are issued first, ties are issued in the order they arrived.
+func higherPriority(a, b *mempoolTx) bool {
+	if a.fee != b.fee {
+		return a.fee > b.fee
+	}
+	return a.seq < b.seq
+}
+
+// readyHeap orders the txs that can be issued so that the tx with the lowest
+// priority is first
+type readyHeap []*mempoolTx
+
+func (h readyHeap) Len() int           { return len(h) }
+func (h readyHeap) Less(i, j int) bool { return higherPriority(h[j], h[i]) }
+func (h readyHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push implements the heap interface
+func (h *readyHeap) Push(x interface{}) {
+	tx := x.(*mempoolTx)
+	tx.index = len(*h)
+	*h = append(*h, tx)
+}
+
+// Pop implements the heap interface
+func (h *readyHeap) Pop() interface{} {
+	old := *h
+	newLen := len(old) - 1
+	tx := old[newLen]
+	old[newLen] = nil
+	*h = old[:newLen]
+	return tx
+}
+
+// mempool holds the txs that are waiting to be issued to consensus and tracks
+// the txs that were issued to consensus but haven't been decided yet.
+//
+// Consensus drops a tx that conflicts with a tx it is already processing, so
+// the mempool never issues a tx that consumes an input of an undecided issued
+// tx. Such a tx is blocked until the issued tx is decided. If the issued tx is
+// accepted, the blocked tx is dropped. If it's rejected, the blocked tx is
+// issued in the next flush.
+//
+// When the mempool is full, the pending tx with the lowest priority is evicted
+// to make room for a tx with a higher priority.
+type mempool struct {
+	maxSize int
+	nextSeq uint64
+
+	// Pending txs that can be issued in the next flush
+	ready readyHeap
+
+	// All the pending txs, including the blocked ones
+	pending map[[32]byte]*mempoolTx
+
+	// Input ID -> pending txs that consume the input
+	spenders map[[32]byte]ids.Set
+
+	// Input ID -> blocked txs waiting on the issued tx that consumes the input.
+	// Entries may refer to txs that have since been removed, these are skipped
+	// when the input is released.
+	blocked map[[32]byte][]*mempoolTx
+
+	// Txs that were passed to consensus but haven't been decided
+	issued map[[32]byte]*mempoolTx
+
+	// Input ID -> issued tx that consumes the input
+	issuedInputs map[[32]byte]*mempoolTx
+}
+
+func newMempool(maxSize int) *mempool {
+	if maxSize <= 0 {
+		maxSize = defaultMempoolSize
+	}
+	return &mempool{
+		maxSize:      maxSize,
+		pending:      make(map[[32]byte]*mempoolTx),
+		spenders:     make(map[[32]byte]ids.Set),
+		blocked:      make(map[[32]byte][]*mempoolTx),
+		issued:       make(map[[32]byte]*mempoolTx),
+		issuedInputs: make(map[[32]byte]*mempoolTx),
+	}
+}
+
+// Len returns the number of pending txs
+func (m *mempool) Len() int { return len(m.pending) }
+
+// Ready returns the number of pending txs that can be issued
+func (m *mempool) Ready() int { return m.ready.Len() }
+
+// Has returns true if the tx is pending
+func (m *mempool) Has(txID ids.ID) bool {
+	_, exists := m.pending[txID.Key()]
+	return exists
+}
+
+// Issued returns true if the tx was passed to consensus and hasn't been
+// decided
+func (m *mempool) Issued(txID ids.ID) bool {
+	_, exists := m.issued[txID.Key()]
+	return exists
+}
+
+// Add the tx to the mempool. If the mempool is full, the pending tx with the
+// lowest priority is evicted and returned. If the provided tx would have the
+// lowest priority, it isn't added and an error is returned.
+func (m *mempool) Add(tx snowstorm.Tx, fee uint64) (snowstorm.Tx, error) {
+	txKey := tx.ID().Key()
+	if _, exists := m.pending[txKey]; exists {
+		return nil, errDuplicateIssued
+	}
+	if _, exists := m.issued[txKey]; exists {
+		return nil, errDuplicateIssued
+	}
+
+	newTx := &mempoolTx{
+		tx:  tx,
+		fee: fee,
+		seq: m.nextSeq,
+	}
+
+	var evicted snowstorm.Tx
+	if len(m.pending) >= m.maxSize {
+		if m.ready.Len() == 0 || !higherPriority(newTx, m.ready[0]) {
+			return nil, errMempoolFull
+		}
+		evicted = m.ready[0].tx
+		m.remove(evicted.ID())
+	}
+
+	m.nextSeq++
+	m.add(newTx)
+	return evicted, nil
+}
+
+// Flush returns the ready txs, ordered from the highest priority to the
+// lowest, and marks them as issued. Ready txs that conflict with an issued tx
+// are blocked rather than returned.
+func (m *mempool) Flush() []snowstorm.Tx {
+	ready := m.ready
+	m.ready = nil
+	sort.Slice(ready, func(i, j int) bool { return higherPriority(ready[i], ready[j]) })
+
+	txs := []snowstorm.Tx(nil)
+	for _, mTx := range ready {
+		if m.block(mTx) {
+			continue
+		}
+
+		txID := mTx.tx.ID()
+		m.remove(txID)
+
+		m.issued[txID.Key()] = mTx
+		for _, input := range mTx.tx.InputIDs().List() {
+			m.issuedInputs[input.Key()] = mTx
+		}
+		txs = append(txs, mTx.tx)
+	}
+	return txs
+}
+
+// Accept removes the accepted tx from the mempool. The txs that consume any
+// of its inputs can never be accepted, so they are dropped and returned.
+func (m *mempool) Accept(tx snowstorm.Tx) []snowstorm.Tx {
+	m.unissue(tx)
+	m.remove(tx.ID())
+
+	dropped := []snowstorm.Tx(nil)
+	for _, input := range tx.InputIDs().List() {
+		inputKey := input.Key()
+		if issuedTx, exists := m.issuedInputs[inputKey]; exists {
+			m.unissue(issuedTx.tx)
+			dropped = append(dropped, issuedTx.tx)
+		}
+		dropped = append(dropped, m.removeSpenders(input)...)
+	}
+	return dropped
+}
+
+// Reject removes the rejected tx from the mempool. The txs that consume any of
+// [outputs], the outputs the rejected tx would have produced, can never be
+// accepted, so they are dropped and returned.
+//
+// The txs that were blocked on the rejected tx become ready. A rejected tx may
+// also have caused consensus to drop issued txs that conflict with it, so
+// those are moved back into the mempool to be issued again. If consensus is
+// already processing one of them, it won't be added to consensus twice.
+func (m *mempool) Reject(tx snowstorm.Tx, outputs ids.Set) []snowstorm.Tx {
+	m.unissue(tx)
+	m.remove(tx.ID())
+
+	dropped := []snowstorm.Tx(nil)
+	for _, output := range outputs.List() {
+		dropped = append(dropped, m.removeSpenders(output)...)
+	}
+
+	for _, input := range tx.InputIDs().List() {
+		issuedTx, exists := m.issuedInputs[input.Key()]
+		if !exists {
+			continue
+		}
+		m.unissue(issuedTx.tx)
+
+		// The tx was already verified and admitted into the mempool once, so
+		// it doesn't need to evict another tx to be added back
+		m.add(&mempoolTx{
+			tx:  issuedTx.tx,
+			fee: issuedTx.fee,
+			seq: m.nextSeq,
+		})
+		m.nextSeq++
+	}
+	return dropped
+}
+
+// add the tx to the pending txs and mark it as ready
+func (m *mempool) add(mTx *mempoolTx) {
+	txID := mTx.tx.ID()
+	m.pending[txID.Key()] = mTx
+	for _, input := range mTx.tx.InputIDs().List() {
+		inputKey := input.Key()
+		spenders := m.spenders[inputKey]
+		spenders.Add(txID)
+		m.spenders[inputKey] = spenders
+	}
+	heap.Push(&m.ready, mTx)
+}
+
+// removeSpenders removes and returns the pending txs that consume [input]
+func (m *mempool) removeSpenders(input ids.ID) []snowstorm.Tx {
+	spenders := m.spenders[input.Key()]
+	removed := []snowstorm.Tx(nil)
+	for _, txID := range spenders.List() {
+		removed = append(removed, m.pending[txID.Key()].tx)
+		m.remove(txID)
+	}
+	return removed
+}
+
+// block the tx if it consumes an input of an issued tx. Returns true if the tx
+// was blocked.
+func (m *mempool) block(mTx *mempoolTx) bool {
+	for _, input := range mTx.tx.InputIDs().List() {
+		inputKey := input.Key()
+		if _, exists := m.issuedInputs[inputKey]; exists {
+			mTx.blocked = true
+			mTx.index = -1
+			m.blocked[inputKey] = append(m.blocked[inputKey], mTx)
+			return true
+		}
+	}
+	return false
+}
+
+// unissue removes the tx from the set of issued txs and marks the txs that
+// were blocked on it as ready
+func (m *mempool) unissue(tx snowstorm.Tx) {
+	txKey := tx.ID().Key()
+	if _, exists := m.issued[txKey]; !exists {
+		return
+	}
+	delete(m.issued, txKey)
+
+	for _, input := range tx.InputIDs().List() {
+		inputKey := input.Key()
+		delete(m.issuedInputs, inputKey)
+
+		for _, mTx := range m.blocked[inputKey] {
+			if current, exists := m.pending[mTx.tx.ID().Key()]; !exists || current != mTx || !mTx.blocked {
+				continue
+			}
+			mTx.blocked = false
+			heap.Push(&m.ready, mTx)
+		}
+		delete(m.blocked, inputKey)
+	}
+}
+
+// remove the tx from the pending txs, if it is pending
+func (m *mempool) remove(txID ids.ID) {
+	txKey := txID.Key()
+	mTx, exists := m.pending[txKey]
+	if !exists {
+		return
+	}
+	delete(m.pending, txKey)
+
+	for _, input := range mTx.tx.InputIDs().List() {
+		inputKey := input.Key()
+		spenders := m.spenders[inputKey]
+		spenders.Remove(txID)
+		if spenders.Len() == 0 {
+			delete(m.spenders, inputKey)
+		}
+	}
+
+	if !mTx.blocked && mTx.index >= 0 && mTx.index < m.ready.Len() && m.ready[mTx.index] == mTx {
+		heap.Remove(&m.ready, mTx.index)
+	}
+	// Blocked txs are removed lazily from [m.blocked]
+	mTx.blocked = false
+	mTx.index = -1
+}
@@ -0,0 +1,294 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+func newTestMempoolTx(i uint64, inputs ...ids.ID) *snowstorm.TestTx {
+	tx := &snowstorm.TestTx{
+		Identifier: ids.Empty.Prefix(i),
+		Stat:       choices.Processing,
+	}
+	tx.Ins.Add(inputs...)
+	return tx
+}
+
+func TestMempoolFlushOrdering(t *testing.T) {
+	m := newMempool(0)
+
+	tx0 := newTestMempoolTx(0, ids.Empty.Prefix(100))
+	tx1 := newTestMempoolTx(1, ids.Empty.Prefix(101))
+	tx2 := newTestMempoolTx(2, ids.Empty.Prefix(102))
+
+	if _, err := m.Add(tx0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(tx1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(tx2, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	txs := m.Flush()
+	switch {
+	case len(txs) != 3:
+		t.Fatalf("Should have flushed 3 txs but flushed %d", len(txs))
+	case !txs[0].ID().Equals(tx2.ID()):
+		t.Fatalf("The tx paying a fee should have been flushed first")
+	case !txs[1].ID().Equals(tx0.ID()), !txs[2].ID().Equals(tx1.ID()):
+		t.Fatalf("Txs paying the same fee should have been flushed in arrival order")
+	case m.Len() != 0:
+		t.Fatalf("Mempool should be empty after flushing")
+	case !m.Issued(tx0.ID()) || !m.Issued(tx1.ID()) || !m.Issued(tx2.ID()):
+		t.Fatalf("Flushed txs should be marked as issued")
+	}
+}
+
+func TestMempoolEviction(t *testing.T) {
+	m := newMempool(2)
+
+	tx0 := newTestMempoolTx(0, ids.Empty.Prefix(100))
+	tx1 := newTestMempoolTx(1, ids.Empty.Prefix(101))
+	tx2 := newTestMempoolTx(2, ids.Empty.Prefix(102))
+	tx3 := newTestMempoolTx(3, ids.Empty.Prefix(103))
+
+	if _, err := m.Add(tx0, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(tx1, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(tx1, 10); err != errDuplicateIssued {
+		t.Fatalf("Should have errored due to a duplicate tx")
+	}
+	if _, err := m.Add(tx2, 5); err != errMempoolFull {
+		t.Fatalf("Should have errored because the tx arrived after a tx paying the same fee")
+	}
+
+	evicted, err := m.Add(tx3, 15)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case evicted == nil || !evicted.ID().Equals(tx0.ID()):
+		t.Fatalf("Should have evicted the tx with the lowest priority")
+	case m.Has(tx0.ID()):
+		t.Fatalf("Evicted tx should have been removed")
+	case !m.Has(tx1.ID()) || !m.Has(tx3.ID()):
+		t.Fatalf("Mempool should contain the txs with the highest priority")
+	}
+}
+
+func TestMempoolBlocksConflicts(t *testing.T) {
+	m := newMempool(0)
+
+	utxo := ids.Empty.Prefix(100)
+	tx0 := newTestMempoolTx(0, utxo)
+	tx1 := newTestMempoolTx(1, utxo, ids.Empty.Prefix(101))
+
+	if _, err := m.Add(tx0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Add(tx1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if txs := m.Flush(); len(txs) != 1 || !txs[0].ID().Equals(tx0.ID()) {
+		t.Fatalf("Only the first of the conflicting txs should have been flushed")
+	}
+	if !m.Has(tx1.ID()) || m.Ready() != 0 {
+		t.Fatalf("The conflicting tx should be blocked")
+	}
+	if txs := m.Flush(); len(txs) != 0 {
+		t.Fatalf("A blocked tx shouldn't be flushed")
+	}
+
+	tx0.Reject()
+	if dropped := m.Reject(tx0, ids.Set{}); len(dropped) != 0 {
+		t.Fatalf("No txs should have been dropped")
+	}
+	if m.Issued(tx0.ID()) || m.Ready() != 1 {
+		t.Fatalf("The blocked tx should be ready after the conflict was rejected")
+	}
+	if txs := m.Flush(); len(txs) != 1 || !txs[0].ID().Equals(tx1.ID()) {
+		t.Fatalf("The previously blocked tx should have been flushed")
+	}
+}
+
+func TestMempoolAcceptDropsConflicts(t *testing.T) {
+	m := newMempool(0)
+
+	utxo := ids.Empty.Prefix(100)
+	tx0 := newTestMempoolTx(0, utxo)
+	tx1 := newTestMempoolTx(1, utxo)
+	tx2 := newTestMempoolTx(2, ids.Empty.Prefix(101))
+
+	for _, tx := range []snowstorm.Tx{tx0, tx1, tx2} {
+		if _, err := m.Add(tx, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if txs := m.Flush(); len(txs) != 2 {
+		t.Fatalf("Should have flushed 2 txs but flushed %d", len(txs))
+	}
+
+	tx0.Accept()
+	dropped := m.Accept(tx0)
+	switch {
+	case len(dropped) != 1 || !dropped[0].ID().Equals(tx1.ID()):
+		t.Fatalf("The blocked conflicting tx should have been dropped")
+	case m.Len() != 0 || m.Ready() != 0:
+		t.Fatalf("Mempool should be empty")
+	case m.Issued(tx0.ID()):
+		t.Fatalf("Accepted tx should no longer be issued")
+	case !m.Issued(tx2.ID()):
+		t.Fatalf("Unrelated tx should still be issued")
+	}
+}
+
+func TestMempoolRejectDropsDependents(t *testing.T) {
+	m := newMempool(0)
+
+	output := ids.Empty.Prefix(100)
+	tx0 := newTestMempoolTx(0, ids.Empty.Prefix(101))
+	tx1 := newTestMempoolTx(1, output)
+
+	if _, err := m.Add(tx0, 0); err != nil {
+		t.Fatal(err)
+	}
+	m.Flush()
+	if _, err := m.Add(tx1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tx0.Reject()
+	outputs := ids.Set{}
+	outputs.Add(output)
+	if dropped := m.Reject(tx0, outputs); len(dropped) != 1 || !dropped[0].ID().Equals(tx1.ID()) {
+		t.Fatalf("The tx spending the rejected tx's output should have been dropped")
+	}
+	if m.Len() != 0 {
+		t.Fatalf("Mempool should be empty")
+	}
+}
+
+func TestMempoolRejectReissuesDroppedConflicts(t *testing.T) {
+	m := newMempool(0)
+
+	utxo := ids.Empty.Prefix(100)
+	tx0 := newTestMempoolTx(0, utxo)
+	// remoteTx was issued into consensus by another node, so consensus dropped
+	// tx0
+	remoteTx := newTestMempoolTx(1, utxo)
+
+	if _, err := m.Add(tx0, 0); err != nil {
+		t.Fatal(err)
+	}
+	m.Flush()
+
+	remoteTx.Reject()
+	m.Reject(remoteTx, ids.Set{})
+	if m.Issued(tx0.ID()) || !m.Has(tx0.ID()) {
+		t.Fatalf("The conflicting tx should have been moved back into the mempool")
+	}
+	if txs := m.Flush(); len(txs) != 1 || !txs[0].ID().Equals(tx0.ID()) {
+		t.Fatalf("The conflicting tx should have been flushed again")
+	}
+}
+
+// issueConflictingSends issues two sends that each spend all of the asset1
+// UTXOs held by the keystore user
+func issueConflictingSends(t *testing.T, vm *VM, s *Service) (ids.ID, ids.ID) {
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: vm.Format(keys[0].PublicKey().Address().Bytes()),
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := vm.Format(keys[1].PublicKey().Address().Bytes())
+	txIDs := []ids.ID(nil)
+	for i := json.Uint64(0); i < 2; i++ {
+		reply := SendReply{}
+		if err := s.Send(nil, &SendArgs{
+			Username: testUsername,
+			Password: testPassword,
+			Amount:   balance.Balance - i,
+			AssetID:  "asset1",
+			To:       addr,
+		}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		txIDs = append(txIDs, reply.TxID)
+	}
+	return txIDs[0], txIDs[1]
+}
+
+func TestVMBlocksConflictingTxUntilReject(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, s := setupKeystoreVM(t)
+	// The timer handler grabs the context lock, so the lock must be released
+	// before the VM is shutdown
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	txID0, txID1 := issueConflictingSends(t, vm, s)
+
+	if txs := vm.PendingTxs(); len(txs) != 1 || !txs[0].ID().Equals(txID0) {
+		t.Fatalf("Only the first send should have been issued")
+	}
+	if !vm.mempool.Has(txID1) {
+		t.Fatalf("The conflicting send should be held in the mempool")
+	}
+
+	tx0, err := vm.GetTx(txID0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx0.Reject()
+
+	if txs := vm.PendingTxs(); len(txs) != 1 || !txs[0].ID().Equals(txID1) {
+		t.Fatalf("The conflicting send should have been issued after the first send was rejected")
+	}
+
+	tx1, err := vm.GetTx(txID1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx1.Accept()
+	if status := tx1.Status(); status != choices.Accepted {
+		t.Fatalf("Tx should have been accepted but was %s", status)
+	}
+	if vm.mempool.Issued(txID1) || vm.mempool.Len() != 0 {
+		t.Fatalf("Accepted tx should have been removed from the mempool")
+	}
+}
+
+func TestVMDropsConflictingTxOnAccept(t *testing.T) {
+	ctx.Lock.Lock()
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	defer ctx.Lock.Unlock()
+
+	txID0, _ := issueConflictingSends(t, vm, s)
+	vm.PendingTxs()
+
+	tx0, err := vm.GetTx(txID0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx0.Accept()
+
+	if vm.mempool.Len() != 0 {
+		t.Fatalf("Conflicting tx should have been dropped from the mempool")
+	}
+}
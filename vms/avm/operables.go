@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/verify"
 )
@@ -72,7 +73,7 @@ func isSortedOperableOutputs(outs []*OperableOutput, c codec.Codec) bool {
 
 // OperableInput ...
 type OperableInput struct {
-	UTXOID `serialize:"true"`
+	ava.UTXOID `serialize:"true"`
 
 	In verify.Verifiable `serialize:"true"`
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 )
 
@@ -93,7 +94,7 @@ func TestOperableInputVerifyNilFx(t *testing.T) {
 
 func TestOperableInputVerify(t *testing.T) {
 	oi := &OperableInput{
-		UTXOID: UTXOID{
+		UTXOID: ava.UTXOID{
 			TxID: ids.Empty,
 		},
 		In: &testVerifiable{},
@@ -109,28 +110,28 @@ func TestOperableInputVerify(t *testing.T) {
 func TestOperableInputSorting(t *testing.T) {
 	ins := []*OperableInput{
 		&OperableInput{
-			UTXOID: UTXOID{
+			UTXOID: ava.UTXOID{
 				TxID:        ids.Empty,
 				OutputIndex: 1,
 			},
 			In: &testVerifiable{},
 		},
 		&OperableInput{
-			UTXOID: UTXOID{
+			UTXOID: ava.UTXOID{
 				TxID:        ids.NewID([32]byte{1}),
 				OutputIndex: 1,
 			},
 			In: &testVerifiable{},
 		},
 		&OperableInput{
-			UTXOID: UTXOID{
+			UTXOID: ava.UTXOID{
 				TxID:        ids.Empty,
 				OutputIndex: 0,
 			},
 			In: &testVerifiable{},
 		},
 		&OperableInput{
-			UTXOID: UTXOID{
+			UTXOID: ava.UTXOID{
 				TxID:        ids.NewID([32]byte{1}),
 				OutputIndex: 0,
 			},
@@ -163,7 +164,7 @@ func TestOperableInputSorting(t *testing.T) {
 		t.Fatalf("OutputIndex expected: %s ; result: %s", ids.Empty, result)
 	}
 	ins = append(ins, &OperableInput{
-		UTXOID: UTXOID{
+		UTXOID: ava.UTXOID{
 			TxID:        ids.Empty,
 			OutputIndex: 1,
 		},
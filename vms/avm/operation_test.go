@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 )
 
@@ -21,7 +22,7 @@ func TestOperationVerifyNil(t *testing.T) {
 func TestOperationVerifyEmpty(t *testing.T) {
 	c := codec.NewDefault()
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 	}
@@ -33,7 +34,7 @@ func TestOperationVerifyEmpty(t *testing.T) {
 func TestOperationVerifyInvalidInput(t *testing.T) {
 	c := codec.NewDefault()
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Ins: []*OperableInput{
@@ -48,7 +49,7 @@ func TestOperationVerifyInvalidInput(t *testing.T) {
 func TestOperationVerifyInvalidOutput(t *testing.T) {
 	c := codec.NewDefault()
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Outs: []*OperableOutput{
@@ -63,19 +64,19 @@ func TestOperationVerifyInvalidOutput(t *testing.T) {
 func TestOperationVerifyInputsNotSorted(t *testing.T) {
 	c := codec.NewDefault()
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Ins: []*OperableInput{
 			&OperableInput{
-				UTXOID: UTXOID{
+				UTXOID: ava.UTXOID{
 					TxID:        ids.Empty,
 					OutputIndex: 1,
 				},
 				In: &testVerifiable{},
 			},
 			&OperableInput{
-				UTXOID: UTXOID{
+				UTXOID: ava.UTXOID{
 					TxID:        ids.Empty,
 					OutputIndex: 0,
 				},
@@ -93,7 +94,7 @@ func TestOperationVerifyOutputsNotSorted(t *testing.T) {
 	c.RegisterType(&TestTransferable{})
 
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Outs: []*OperableOutput{
@@ -113,7 +114,7 @@ func TestOperationVerifyOutputsNotSorted(t *testing.T) {
 func TestOperationVerify(t *testing.T) {
 	c := codec.NewDefault()
 	op := &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Outs: []*OperableOutput{
@@ -133,12 +134,12 @@ func TestOperationSorting(t *testing.T) {
 
 	ops := []*Operation{
 		&Operation{
-			Asset: Asset{
+			Asset: ava.Asset{
 				ID: ids.Empty,
 			},
 			Ins: []*OperableInput{
 				&OperableInput{
-					UTXOID: UTXOID{
+					UTXOID: ava.UTXOID{
 						TxID:        ids.Empty,
 						OutputIndex: 1,
 					},
@@ -147,12 +148,12 @@ func TestOperationSorting(t *testing.T) {
 			},
 		},
 		&Operation{
-			Asset: Asset{
+			Asset: ava.Asset{
 				ID: ids.Empty,
 			},
 			Ins: []*OperableInput{
 				&OperableInput{
-					UTXOID: UTXOID{
+					UTXOID: ava.UTXOID{
 						TxID:        ids.Empty,
 						OutputIndex: 0,
 					},
@@ -169,12 +170,12 @@ func TestOperationSorting(t *testing.T) {
 		t.Fatalf("Should be sorted")
 	}
 	ops = append(ops, &Operation{
-		Asset: Asset{
+		Asset: ava.Asset{
 			ID: ids.Empty,
 		},
 		Ins: []*OperableInput{
 			&OperableInput{
-				UTXOID: UTXOID{
+				UTXOID: ava.UTXOID{
 					TxID:        ids.Empty,
 					OutputIndex: 1,
 				},
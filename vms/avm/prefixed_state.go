@@ -5,9 +5,11 @@ package avm
 
 import (
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 const (
@@ -16,6 +18,8 @@ const (
 	txStatusID
 	fundsID
 	dbInitializedID
+	addressTxsID
+	addressTxCountID
 )
 
 var (
@@ -44,13 +48,16 @@ func (s *prefixedState) SetTx(id ids.ID, tx *Tx) error {
 	return s.state.SetTx(s.uniqueID(id, txID, s.tx), tx)
 }
 
+// TxKey returns the key the provided transaction is stored under.
+func (s *prefixedState) TxKey(id ids.ID) []byte { return s.uniqueID(id, txID, s.tx).Bytes() }
+
 // UTXO attempts to load a utxo from storage.
-func (s *prefixedState) UTXO(id ids.ID) (*UTXO, error) {
+func (s *prefixedState) UTXO(id ids.ID) (*ava.UTXO, error) {
 	return s.state.UTXO(s.uniqueID(id, utxoID, s.utxo))
 }
 
 // SetUTXO saves the provided utxo to storage.
-func (s *prefixedState) SetUTXO(id ids.ID, utxo *UTXO) error {
+func (s *prefixedState) SetUTXO(id ids.ID, utxo *ava.UTXO) error {
 	return s.state.SetUTXO(s.uniqueID(id, utxoID, s.utxo), utxo)
 }
 
@@ -84,6 +91,34 @@ func (s *prefixedState) SetFunds(id ids.ID, idSlice []ids.ID) error {
 	return s.state.SetIDs(s.uniqueID(id, fundsID, s.funds), idSlice)
 }
 
+// AddressTxCount returns the number of accepted txs that consumed or produced a
+// utxo referencing the 32 byte representation of an address.
+func (s *prefixedState) AddressTxCount(id ids.ID) (uint64, error) {
+	count, err := s.state.Uint64(id.Prefix(addressTxCountID))
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return count, err
+}
+
+// SetAddressTxCount saves the number of accepted txs that referenced the
+// address to storage.
+func (s *prefixedState) SetAddressTxCount(id ids.ID, count uint64) error {
+	return s.state.SetUint64(id.Prefix(addressTxCountID), count)
+}
+
+// AddressTx returns the ID of the [index]th accepted tx that referenced the
+// address. Txs are indexed in the order they were accepted.
+func (s *prefixedState) AddressTx(id ids.ID, index uint64) (ids.ID, error) {
+	return s.state.ID(id.Prefix(addressTxsID, index))
+}
+
+// SetAddressTx saves the ID of the [index]th accepted tx that referenced the
+// address to storage.
+func (s *prefixedState) SetAddressTx(id ids.ID, index uint64, txID ids.ID) error {
+	return s.state.SetID(id.Prefix(addressTxsID, index), txID)
+}
+
 func (s *prefixedState) uniqueID(id ids.ID, prefix uint64, cacher cache.Cacher) ids.ID {
 	if cachedIDIntf, found := cacher.Get(id); found {
 		return cachedIDIntf.(ids.ID)
@@ -126,7 +161,7 @@ func (s *prefixedState) removeUTXO(addrs [][]byte, utxoID ids.ID) error {
 }
 
 // FundUTXO adds the provided utxo to the database
-func (s *prefixedState) FundUTXO(utxo *UTXO) error {
+func (s *prefixedState) FundUTXO(utxo *ava.UTXO) error {
 	utxoID := utxo.InputID()
 	if err := s.SetUTXO(utxoID, utxo); err != nil {
 		return err
@@ -153,3 +188,32 @@ func (s *prefixedState) addUTXO(addrs [][]byte, utxoID ids.ID) error {
 	}
 	return nil
 }
+
+// IndexTx appends the provided tx to the txs of every address referenced by the
+// provided utxos
+func (s *prefixedState) IndexTx(txID ids.ID, utxos []*ava.UTXO) error {
+	addrs := ids.Set{}
+	for _, utxo := range utxos {
+		addressable, ok := utxo.Out.(FxAddressable)
+		if !ok {
+			continue
+		}
+		for _, addr := range addressable.Addresses() {
+			addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr)))
+		}
+	}
+
+	for _, addrID := range addrs.List() {
+		count, err := s.AddressTxCount(addrID)
+		if err != nil {
+			return err
+		}
+		if err := s.SetAddressTx(addrID, count, txID); err != nil {
+			return err
+		}
+		if err := s.SetAddressTxCount(addrID, count+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
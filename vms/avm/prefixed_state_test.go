@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
@@ -20,25 +21,25 @@ func TestPrefixedSetsAndGets(t *testing.T) {
 
 	vm.codec.RegisterType(&testVerifiable{})
 
-	utxo := &UTXO{
-		UTXOID: UTXOID{
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{
 			TxID:        ids.Empty,
 			OutputIndex: 1,
 		},
-		Asset: Asset{ID: ids.Empty},
+		Asset: ava.Asset{ID: ids.Empty},
 		Out:   &testVerifiable{},
 	}
 
 	tx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        ids.Empty,
 					OutputIndex: 0,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: asset,
 				},
 				In: &secp256k1fx.TransferInput{
@@ -120,12 +121,12 @@ func TestPrefixedFundingNoAddresses(t *testing.T) {
 
 	vm.codec.RegisterType(&testVerifiable{})
 
-	utxo := &UTXO{
-		UTXOID: UTXOID{
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{
 			TxID:        ids.Empty,
 			OutputIndex: 1,
 		},
-		Asset: Asset{ID: ids.Empty},
+		Asset: ava.Asset{ID: ids.Empty},
 		Out:   &testVerifiable{},
 	}
 
@@ -143,12 +144,12 @@ func TestPrefixedFundingAddresses(t *testing.T) {
 
 	vm.codec.RegisterType(&testAddressable{})
 
-	utxo := &UTXO{
-		UTXOID: UTXOID{
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{
 			TxID:        ids.Empty,
 			OutputIndex: 1,
 		},
-		Asset: Asset{ID: ids.Empty},
+		Asset: ava.Asset{ID: ids.Empty},
 		Out: &testAddressable{
 			Addrs: [][]byte{
 				[]byte{0},
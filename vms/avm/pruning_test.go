@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/pruning"
+)
+
+// Make sure a rejected tx is deleted once the retention window has passed, and
+// is still known to be rejected when it's parsed again
+func TestRejectedTxPruned(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	baseDB := memdb.New()
+	vm, _ := setupKeystoreVMWithDB(t, baseDB)
+	defer vm.timer.Stop()
+	vm.pruner = pruning.New(ctx, vm.db, time.Hour)
+	now := time.Unix(1000, 0)
+	vm.pruner.Clock().Set(now)
+
+	tx, _ := newOfflineSendTx(t, vm, 0)
+	if _, err := vm.IssueTx(tx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	pendingTxs := vm.PendingTxs()
+	if len(pendingTxs) != 1 {
+		t.Fatalf("Should have returned 1 tx")
+	}
+	pendingTxs[0].Reject()
+
+	vm.pruner.Clock().Set(now.Add(time.Hour))
+	if n, err := vm.pruner.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("Pruned %d keys, expected only the tx", n)
+	}
+
+	restarted := restartVM(t, baseDB)
+	if _, err := restarted.state.Tx(tx.ID()); err != database.ErrNotFound {
+		t.Fatalf("The tx should have been pruned")
+	}
+	if status, err := restarted.state.Status(tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != choices.Rejected {
+		t.Fatalf("The tx's status should have been kept as %s, but was %s", choices.Rejected, status)
+	}
+
+	// A vertex that contains the tx may be parsed again
+	parsed, err := restarted.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := parsed.Status(); status != choices.Rejected {
+		t.Fatalf("The parsed tx should have been %s, but was %s", choices.Rejected, status)
+	}
+	if status, err := restarted.state.Status(tx.ID()); err != nil {
+		t.Fatal(err)
+	} else if status != choices.Rejected {
+		t.Fatalf("Parsing the tx shouldn't have changed its status, but it's %s", status)
+	}
+}
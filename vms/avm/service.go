@@ -5,6 +5,7 @@ package avm
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -18,11 +19,14 @@ import (
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/json"
 	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 var (
+	errNilTxID                   = errors.New("nil transaction ID")
 	errUnknownAssetID            = errors.New("unknown asset ID")
 	errTxNotCreateAsset          = errors.New("transaction doesn't create an asset")
 	errNoHolders                 = errors.New("initialHolders must not be empty")
@@ -35,8 +39,21 @@ var (
 	errUnknownUTXO               = errors.New("unknown utxo")
 	errInvalidUTXO               = errors.New("invalid utxo")
 	errUnknownOutputType         = errors.New("unknown output type")
+	errTxsNotIndexed             = errors.New("this chain's txs aren't indexed by address")
 	errUnneededAddress           = errors.New("address not required to sign")
 	errUnknownCredentialType     = errors.New("unknown credential type")
+	errPayloadTooLarge           = errors.New("payload too large")
+	errNoNFTOwned                = errors.New("provided addresses don't own an NFT of the provided group")
+	errInvalidThreshold          = errors.New("threshold must be positive and no more than the number of addresses")
+	errInvalidSigners            = errors.New("number of signers must equal the threshold")
+	errUnknownInputType          = errors.New("unknown input type")
+	errStartAddressNotRequested  = errors.New("start index address must be one of the requested addresses")
+	errWrongNumberOfSignatures   = errors.New("credential has a different number of signatures than its input has signers")
+	errMissingSignatures         = errors.New("transaction is missing signatures")
+	errOutputsAndSingleOutput    = errors.New("outputs can't be given with amount, assetID or to")
+	errSourceChainIsThisChain    = errors.New("source chain must be a chain other than this one")
+
+	emptySig [crypto.SECP256K1RSigLen]byte
 )
 
 // Service defines the base service for the asset vm
@@ -44,7 +61,8 @@ type Service struct{ vm *VM }
 
 // IssueTxArgs are arguments for passing into IssueTx requests
 type IssueTxArgs struct {
-	Tx formatting.CB58 `json:"tx"`
+	Tx       string              `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
 }
 
 // IssueTxReply defines the IssueTx replies returned from the API
@@ -52,11 +70,57 @@ type IssueTxReply struct {
 	TxID ids.ID `json:"txID"`
 }
 
-// IssueTx attempts to issue a transaction into consensus
+// IssueTx attempts to issue a transaction into consensus. The transaction is
+// given in [args.Encoding], which is CB58 by default.
 func (service *Service) IssueTx(r *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
 	service.vm.ctx.Log.Verbo("IssueTx called with %s", args.Tx)
 
-	txID, err := service.vm.IssueTx(args.Tx.Bytes)
+	txBytes, err := args.Encoding.Decode(args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	txID, err := service.vm.IssueTx(txBytes)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// IssueRawTx issues a transaction that was built and signed without this
+// node's keystore, such as by a hardware wallet or an offline machine. Unlike
+// IssueTx, a transaction with a missing signature is rejected before it is
+// verified against the chain's state.
+func (service *Service) IssueRawTx(r *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
+	service.vm.ctx.Log.Verbo("IssueRawTx called with %s", args.Tx)
+
+	txBytes, err := args.Encoding.Decode(args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(txBytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	for _, cred := range tx.Creds {
+		var sigs [][crypto.SECP256K1RSigLen]byte
+		switch c := cred.Cred.(type) {
+		case *secp256k1fx.Credential:
+			sigs = c.Sigs
+		case *nftfx.Credential:
+			sigs = c.Sigs
+		default:
+			return errUnknownCredentialType
+		}
+		for _, sig := range sigs {
+			if sig == emptySig {
+				return errMissingSignatures
+			}
+		}
+	}
+
+	txID, err := service.vm.IssueTx(txBytes)
 	if err != nil {
 		return err
 	}
@@ -92,41 +156,252 @@ func (service *Service) GetTxStatus(r *http.Request, args *GetTxStatusArgs, repl
 	return nil
 }
 
+// GetTxArgs are arguments for passing into GetTx requests
+type GetTxArgs struct {
+	TxID     ids.ID              `json:"txID"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetTxReply defines the GetTx replies returned from the API
+type GetTxReply struct {
+	Tx       string              `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// GetTx returns the specified transaction in [args.Encoding], which is CB58 by
+// default
+func (service *Service) GetTx(r *http.Request, args *GetTxArgs, reply *GetTxReply) error {
+	service.vm.ctx.Log.Verbo("GetTx called with %s", args.TxID)
+
+	txBytes, err := service.getTx(args.TxID)
+	if err != nil {
+		return err
+	}
+
+	reply.Tx = args.Encoding.Encode(txBytes)
+	reply.Encoding = args.Encoding
+	return nil
+}
+
+// DecodeTxArgs are arguments for passing into DecodeTx requests
+type DecodeTxArgs struct {
+	Tx       string              `json:"tx"`
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// DecodeTxReply defines the DecodeTx replies returned from the API
+type DecodeTxReply struct {
+	TxID ids.ID             `json:"txID"`
+	Tx   stdjson.RawMessage `json:"tx"`
+}
+
+// DecodeTx returns the JSON representation of the transaction [args.Tx], which
+// is given in [args.Encoding], CB58 by default. The transaction isn't verified
+// or issued. Every serialized field of the transaction is in its JSON, so the
+// transaction can be inspected without parsing its bytes.
+func (service *Service) DecodeTx(r *http.Request, args *DecodeTxArgs, reply *DecodeTxReply) error {
+	service.vm.ctx.Log.Verbo("DecodeTx called with %s", args.Tx)
+
+	txBytes, err := args.Encoding.Decode(args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	tx := &Tx{}
+	if err := service.vm.codec.Unmarshal(txBytes, tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	tx.Initialize(txBytes)
+	txJSON, err := service.vm.codec.MarshalCanonicalJSON(tx)
+	if err != nil {
+		return fmt.Errorf("problem encoding transaction: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.Tx = txJSON
+	return nil
+}
+
+// getTx returns the bytes of the transaction [txID]
+func (service *Service) getTx(txID ids.ID) ([]byte, error) {
+	if txID.IsZero() {
+		return nil, errNilTxID
+	}
+
+	tx := UniqueTx{
+		vm:   service.vm,
+		txID: txID,
+	}
+	tx.refresh()
+	if tx.t.tx == nil {
+		return nil, errUnknownTx
+	}
+	return tx.t.tx.Bytes(), nil
+}
+
+// GetAddressTxsArgs are arguments for passing into GetAddressTxs requests
+type GetAddressTxsArgs struct {
+	Address    string      `json:"address"`
+	Limit      json.Uint32 `json:"limit"`
+	StartIndex json.Uint64 `json:"startIndex"`
+}
+
+// GetAddressTxsReply defines the GetAddressTxs replies returned from the API
+type GetAddressTxsReply struct {
+	TxIDs    []ids.ID    `json:"txIDs"`
+	EndIndex json.Uint64 `json:"endIndex"`
+	More     bool        `json:"more"`
+}
+
+// GetAddressTxs returns the IDs of the accepted transactions that consumed or
+// produced a UTXO referencing the specified address, in the order they were
+// accepted. At most [args.Limit] IDs are returned. If [args.Limit] is zero or
+// exceeds 1024, 1024 IDs are returned. To fetch the next page,
+// [reply.EndIndex] should be passed in as [args.StartIndex]. [reply.More] is
+// false once the last accepted transaction has been returned.
+func (service *Service) GetAddressTxs(r *http.Request, args *GetAddressTxsArgs, reply *GetAddressTxsReply) error {
+	service.vm.ctx.Log.Verbo("GetAddressTxs called with %s", args.Address)
+
+	if !service.vm.config.IndexTransactions {
+		return errTxsNotIndexed
+	}
+
+	addrBytes, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Address, err)
+	}
+
+	limit := uint64(args.Limit)
+	if limit == 0 || limit > maxAddressTxsToFetch {
+		limit = maxAddressTxsToFetch
+	}
+
+	addrID := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+	count, err := service.vm.state.AddressTxCount(addrID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving the number of txs of '%s': %w", args.Address, err)
+	}
+
+	reply.TxIDs = []ids.ID{}
+	index := uint64(args.StartIndex)
+	for ; index < count && uint64(len(reply.TxIDs)) < limit; index++ {
+		txID, err := service.vm.state.AddressTx(addrID, index)
+		if err != nil {
+			return fmt.Errorf("problem retrieving tx %d of '%s': %w", index, args.Address, err)
+		}
+		reply.TxIDs = append(reply.TxIDs, txID)
+	}
+	reply.EndIndex = json.Uint64(index)
+	reply.More = index < count
+	return nil
+}
+
+// Index is an address and an associated UTXO. It marks a position when
+// paginating over the UTXOs of a set of addresses.
+type Index struct {
+	Address string `json:"address"`
+	UTXO    ids.ID `json:"utxo"`
+}
+
 // GetUTXOsArgs are arguments for passing into GetUTXOs requests
 type GetUTXOsArgs struct {
-	Addresses []string `json:"addresses"`
+	Addresses  []string            `json:"addresses"`
+	Limit      json.Uint32         `json:"limit"`
+	StartIndex Index               `json:"startIndex"`
+	Encoding   formatting.Encoding `json:"encoding"`
+
+	// If provided, the UTXOs the chain [SourceChain] has exported to this
+	// chain that haven't been imported yet are returned instead
+	SourceChain string `json:"sourceChain"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
 type GetUTXOsReply struct {
-	UTXOs []formatting.CB58 `json:"utxos"`
+	NumFetched json.Uint32         `json:"numFetched"`
+	UTXOs      []string            `json:"utxos"`
+	EndIndex   Index               `json:"endIndex"`
+	More       bool                `json:"more"`
+	Encoding   formatting.Encoding `json:"encoding"`
 }
 
-// GetUTXOs creates an empty account with the name passed in
+// GetUTXOs returns the UTXOs referenced by the provided addresses. At most
+// [args.Limit] UTXOs are returned. If [args.Limit] is zero or exceeds 1024,
+// at most 1024 UTXOs are returned. If [args.StartIndex] is provided, UTXOs are
+// returned starting after that position. To fetch the next page,
+// [reply.EndIndex] should be passed in as [args.StartIndex]. [reply.More] is
+// false once all UTXOs have been returned. The UTXOs are returned in
+// [args.Encoding], which is CB58 by default. If [args.SourceChain] is provided,
+// the UTXOs that chain has exported to this chain, but that haven't been
+// imported yet, are returned instead, so an interrupted transfer between
+// chains can be completed.
 func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
 	service.vm.ctx.Log.Verbo("GetUTXOs called with %s", args.Addresses)
 
+	sourceChain := ids.ID{}
+	if args.SourceChain != "" {
+		chainID, err := service.vm.lookupChainID(args.SourceChain)
+		if err != nil {
+			return fmt.Errorf("problem parsing source chain '%s': %w", args.SourceChain, err)
+		}
+		if chainID.Equals(service.vm.ctx.ChainID) {
+			return errSourceChainIsThisChain
+		}
+		sourceChain = chainID
+	}
+
 	addrSet := ids.Set{}
+	addrStrs := make(map[[32]byte]string, len(args.Addresses))
 	for _, addr := range args.Addresses {
 		addrBytes, err := service.vm.Parse(addr)
 		if err != nil {
 			return err
 		}
-		addrSet.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
+		addrID := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		addrSet.Add(addrID)
+		addrStrs[addrID.Key()] = addr
 	}
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	startAddr := ids.ID{}
+	startUTXO := args.StartIndex.UTXO
+	if args.StartIndex.Address != "" {
+		addrBytes, err := service.vm.Parse(args.StartIndex.Address)
+		if err != nil {
+			return fmt.Errorf("problem parsing start index address '%s': %w", args.StartIndex.Address, err)
+		}
+		startAddr = ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		if !addrSet.Contains(startAddr) {
+			return errStartAddressNotRequested
+		}
+	}
+
+	var (
+		utxos            []*ava.UTXO
+		endAddr, endUTXO ids.ID
+		more             bool
+		err              error
+	)
+	if sourceChain.IsZero() {
+		utxos, endAddr, endUTXO, more, err = service.vm.GetPaginatedUTXOs(addrSet, startAddr, startUTXO, int(args.Limit))
+	} else {
+		utxos, endAddr, endUTXO, more, err = service.vm.GetPaginatedAtomicUTXOs(sourceChain, addrSet, startAddr, startUTXO, int(args.Limit))
+	}
 	if err != nil {
 		return err
 	}
 
-	reply.UTXOs = []formatting.CB58{}
+	reply.UTXOs = []string{}
 	for _, utxo := range utxos {
 		b, err := service.vm.codec.Marshal(utxo)
 		if err != nil {
 			return err
 		}
-		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: b})
+		reply.UTXOs = append(reply.UTXOs, args.Encoding.Encode(b))
+	}
+	reply.Encoding = args.Encoding
+	reply.NumFetched = json.Uint32(len(utxos))
+	reply.More = more
+	if !endAddr.IsZero() {
+		reply.EndIndex.Address = addrStrs[endAddr.Key()]
+		reply.EndIndex.UTXO = endUTXO
 	}
 	return nil
 }
@@ -214,7 +489,7 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 
 	for _, utxo := range utxos {
 		if utxo.AssetID().Equals(assetID) {
-			transferable, ok := utxo.Out.(FxTransferable)
+			transferable, ok := utxo.Out.(ava.Transferable)
 			if !ok {
 				continue
 			}
@@ -228,6 +503,87 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 	return nil
 }
 
+// GetAllBalancesArgs are arguments for passing into GetAllBalances requests
+type GetAllBalancesArgs struct {
+	Address string `json:"address"`
+}
+
+// Balance is the amount of an asset held by an address
+type Balance struct {
+	AssetID ids.ID      `json:"assetID"`
+	Balance json.Uint64 `json:"balance"`
+}
+
+// GetAllBalancesReply defines the GetAllBalances replies returned from the API
+type GetAllBalancesReply struct {
+	Balances []Balance `json:"balances"`
+}
+
+// GetAllBalances returns the balance of every asset held by the provided
+// address, sorted by assetID
+func (service *Service) GetAllBalances(r *http.Request, args *GetAllBalancesArgs, reply *GetAllBalancesReply) error {
+	service.vm.ctx.Log.Verbo("GetAllBalances called with address: %s", args.Address)
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return err
+	}
+
+	addrSet := ids.Set{}
+	addrSet.Add(ids.NewID(hashing.ComputeHash256Array(address)))
+
+	utxos, err := service.vm.GetUTXOs(addrSet)
+	if err != nil {
+		return err
+	}
+
+	assetIDs := []ids.ID{}
+	balances := make(map[[32]byte]uint64)
+	for _, utxo := range utxos {
+		transferable, ok := utxo.Out.(ava.Transferable)
+		if !ok {
+			continue
+		}
+		assetID := utxo.AssetID()
+		balance, exists := balances[assetID.Key()]
+		if !exists {
+			assetIDs = append(assetIDs, assetID)
+		}
+		newBalance, err := math.Add64(transferable.Amount(), balance)
+		if err != nil {
+			return err
+		}
+		balances[assetID.Key()] = newBalance
+	}
+	ids.SortIDs(assetIDs)
+
+	reply.Balances = make([]Balance, len(assetIDs))
+	for i, assetID := range assetIDs {
+		reply.Balances[i] = Balance{
+			AssetID: assetID,
+			Balance: json.Uint64(balances[assetID.Key()]),
+		}
+	}
+	return nil
+}
+
+// GetTxFeeReply defines the GetTxFee replies returned from the API
+type GetTxFeeReply struct {
+	// AVA, in nAVA, burned by each transaction that doesn't create an asset
+	TxFee json.Uint64 `json:"txFee"`
+	// AVA, in nAVA, burned by each transaction that creates an asset
+	CreationTxFee json.Uint64 `json:"creationTxFee"`
+}
+
+// GetTxFee returns the transaction fees of this chain
+func (service *Service) GetTxFee(r *http.Request, _ *struct{}, reply *GetTxFeeReply) error {
+	service.vm.ctx.Log.Verbo("GetTxFee called")
+
+	reply.TxFee = json.Uint64(service.vm.txFee)
+	reply.CreationTxFee = json.Uint64(service.vm.creationTxFee)
+	return nil
+}
+
 // CreateFixedCapAssetArgs are arguments for passing into CreateFixedCapAsset requests
 type CreateFixedCapAssetArgs struct {
 	Username       string    `json:"username"`
@@ -238,10 +594,12 @@ type CreateFixedCapAssetArgs struct {
 	InitialHolders []*Holder `json:"initialHolders"`
 }
 
-// Holder describes how much an address owns of an asset
+// Holder describes how much an address owns of an asset. The funds can't be
+// spent until [Locktime], a unix timestamp.
 type Holder struct {
-	Amount  json.Uint64 `json:"amount"`
-	Address string      `json:"address"`
+	Amount   json.Uint64 `json:"amount"`
+	Address  string      `json:"address"`
+	Locktime json.Uint64 `json:"locktime"`
 }
 
 // CreateFixedCapAssetReply defines the CreateFixedCapAsset replies returned from the API
@@ -261,8 +619,18 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		return errNoHolders
 	}
 
+	secpFxIndex, err := service.vm.getFx(&secp256k1fx.TransferOutput{})
+	if err != nil {
+		return fmt.Errorf("problem finding the secp256k1 feature extension: %w", err)
+	}
+
+	ins, outs, keys, err := service.spendUserFee(args.Username, args.Password, service.vm.creationTxFee)
+	if err != nil {
+		return err
+	}
+
 	initialState := &InitialState{
-		FxID: 0, // TODO: Should lookup secp256k1fx FxID
+		FxID: uint32(secpFxIndex),
 		Outs: []verify.Verifiable{},
 	}
 
@@ -270,6 +638,8 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		BaseTx: BaseTx{
 			NetID: service.vm.ctx.NetworkID,
 			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
 		},
 		Name:         args.Name,
 		Symbol:       args.Symbol,
@@ -280,32 +650,25 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 	}}
 
 	for _, holder := range args.InitialHolders {
-		address, err := service.vm.Parse(holder.Address)
-		if err != nil {
-			return err
-		}
-		addr, err := ids.ToShortID(address)
+		owners, err := service.vm.parseOwners(holder.Address)
 		if err != nil {
 			return err
 		}
 		initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-			Amt: uint64(holder.Amount),
-			OutputOwners: secp256k1fx.OutputOwners{
-				Threshold: 1,
-				Addrs:     []ids.ShortID{addr},
-			},
+			Amt:          uint64(holder.Amount),
+			Locktime:     uint64(holder.Locktime),
+			OutputOwners: *owners,
 		})
 	}
 	initialState.Sort(service.vm.codec)
 
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return fmt.Errorf("problem signing transaction: %w", err)
 	}
 
-	assetID, err := service.vm.IssueTx(b)
+	assetID, err := service.vm.issueSignedTx(tx)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
@@ -346,8 +709,18 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		return errNoMinters
 	}
 
+	secpFxIndex, err := service.vm.getFx(&secp256k1fx.MintOutput{})
+	if err != nil {
+		return fmt.Errorf("problem finding the secp256k1 feature extension: %w", err)
+	}
+
+	ins, outs, keys, err := service.spendUserFee(args.Username, args.Password, service.vm.creationTxFee)
+	if err != nil {
+		return err
+	}
+
 	initialState := &InitialState{
-		FxID: 0, // TODO: Should lookup secp256k1fx FxID
+		FxID: uint32(secpFxIndex),
 		Outs: []verify.Verifiable{},
 	}
 
@@ -355,6 +728,8 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		BaseTx: BaseTx{
 			NetID: service.vm.ctx.NetworkID,
 			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
 		},
 		Name:         args.Name,
 		Symbol:       args.Symbol,
@@ -386,14 +761,13 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 	}
 	initialState.Sort(service.vm.codec)
 
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return fmt.Errorf("problem signing transaction: %w", err)
 	}
 
-	assetID, err := service.vm.IssueTx(b)
+	assetID, err := service.vm.issueSignedTx(tx)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
@@ -416,31 +790,20 @@ type CreateAddressReply struct {
 func (service *Service) CreateAddress(r *http.Request, args *CreateAddressArgs, reply *CreateAddressReply) error {
 	service.vm.ctx.Log.Verbo("CreateAddress called for user '%s'", args.Username)
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	book, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return err
 	}
 
-	user := userState{vm: service.vm}
-
-	factory := crypto.FactorySECP256K1R{}
-	skIntf, err := factory.NewPrivateKey()
+	sk, err := service.vm.ctx.Keystore.NewKey(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("problem generating private key: %w", err)
 	}
-	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
-	if err := user.SetKey(db, sk); err != nil {
+	if err := book.Put(sk); err != nil {
 		return fmt.Errorf("problem saving private key: %w", err)
 	}
 
-	addresses, _ := user.Addresses(db)
-	addresses = append(addresses, ids.NewID(hashing.ComputeHash256Array(sk.PublicKey().Address().Bytes())))
-
-	if err := user.SetAddresses(db, addresses); err != nil {
-		return fmt.Errorf("problem saving address: %w", err)
-	}
-
 	reply.Address = service.vm.Format(sk.PublicKey().Address().Bytes())
 	return nil
 }
@@ -462,19 +825,17 @@ type ExportKeyReply struct {
 func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
 	service.vm.ctx.Log.Verbo("ExportKey called for user '%s'", args.Username)
 
-	address, err := service.vm.Parse(args.Address)
+	address, err := service.vm.parseAddress(args.Address)
 	if err != nil {
 		return fmt.Errorf("problem parsing address: %w", err)
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	book, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return err
 	}
 
-	user := userState{vm: service.vm}
-
-	sk, err := user.Key(db, ids.NewID(hashing.ComputeHash256Array(address)))
+	sk, err := book.Key(address)
 	if err != nil {
 		return fmt.Errorf("problem retrieving private key: %w", err)
 	}
@@ -500,13 +861,11 @@ type ImportKeyReply struct {
 func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *ImportKeyReply) error {
 	service.vm.ctx.Log.Verbo("ImportKey called for user '%s'", args.Username)
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	book, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving data: %w", err)
+		return err
 	}
 
-	user := userState{vm: service.vm}
-
 	factory := crypto.FactorySECP256K1R{}
 	skIntf, err := factory.ToPrivateKey(args.PrivateKey.Bytes)
 	if err != nil {
@@ -514,28 +873,45 @@ func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *I
 	}
 	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
 
-	if err := user.SetKey(db, sk); err != nil {
+	if err := book.Put(sk); err != nil {
 		return fmt.Errorf("problem saving key %w", err)
 	}
 
-	addresses, _ := user.Addresses(db)
-	addresses = append(addresses, ids.NewID(hashing.ComputeHash256Array(sk.PublicKey().Address().Bytes())))
-
-	if err := user.SetAddresses(db, addresses); err != nil {
-		return fmt.Errorf("problem saving addresses: %w", err)
-	}
-
 	reply.Address = service.vm.Format(sk.PublicKey().Address().Bytes())
 	return nil
 }
 
-// SendArgs are arguments for passing into Send requests
+// Recipient is an amount of an asset sent by a Send request
+type Recipient struct {
+	Amount  json.Uint64 `json:"amount"`
+	AssetID string      `json:"assetID"`
+	To      string      `json:"to"`
+
+	// The recipient can't spend the funds until this unix timestamp
+	Locktime json.Uint64 `json:"locktime"`
+}
+
+// SendArgs are arguments for passing into Send requests. Either a single
+// recipient is given by [Amount], [AssetID], [To] and [Locktime], or several
+// are given in [Outputs].
 type SendArgs struct {
 	Username string      `json:"username"`
 	Password string      `json:"password"`
 	Amount   json.Uint64 `json:"amount"`
 	AssetID  string      `json:"assetID"`
 	To       string      `json:"to"`
+
+	// The recipient can't spend the funds until this unix timestamp
+	Locktime json.Uint64 `json:"locktime"`
+
+	Outputs []Recipient `json:"outputs"`
+
+	// Address the change is sent to. If empty, the change is sent to one of
+	// the user's addresses.
+	ChangeAddr string `json:"changeAddr"`
+
+	// Memo stored in the tx, of at most MaxMemoSize bytes
+	Memo string `json:"memo"`
 }
 
 // SendReply defines the Send replies returned from the API
@@ -547,180 +923,135 @@ type SendReply struct {
 func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply) error {
 	service.vm.ctx.Log.Verbo("Send called with username: %s", args.Username)
 
-	if args.Amount == 0 {
-		return errInvalidAmount
-	}
+	outputs := args.Outputs
+	switch {
+	case len(outputs) == 0:
+		outputs = []Recipient{{
+			Amount:   args.Amount,
+			AssetID:  args.AssetID,
+			To:       args.To,
+			Locktime: args.Locktime,
+		}}
+	case args.Amount != 0 || args.AssetID != "" || args.To != "":
+		return errOutputsAndSingleOutput
+	}
+	if len(args.Memo) > MaxMemoSize {
+		return errMemoTooLarge
+	}
+
+	sends := make([]SendOutput, 0, len(outputs))
+	for _, output := range outputs {
+		if output.Amount == 0 {
+			return errInvalidAmount
+		}
 
-	assetID, err := service.vm.Lookup(args.AssetID)
-	if err != nil {
-		assetID, err = ids.FromString(args.AssetID)
+		assetID, err := service.vm.lookupAssetID(output.AssetID)
 		if err != nil {
-			return fmt.Errorf("asset '%s' not found", args.AssetID)
+			return err
+		}
+
+		to, err := service.vm.parseOwners(output.To)
+		if err != nil {
+			return fmt.Errorf("problem parsing to address: %w", err)
 		}
+
+		sends = append(sends, SendOutput{
+			AssetID:  assetID,
+			Amount:   uint64(output.Amount),
+			To:       to,
+			Locktime: uint64(output.Locktime),
+		})
 	}
 
-	toBytes, err := service.vm.Parse(args.To)
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
+		return err
 	}
-	to, err := ids.ToShortID(toBytes)
+
+	if len(kc.Keys) == 0 {
+		return errInsufficientFunds
+	}
+
+	changeAddr := kc.Keys[0].PublicKey().Address()
+	if args.ChangeAddr != "" {
+		changeAddr, err = service.vm.parseAddress(args.ChangeAddr)
+		if err != nil {
+			return fmt.Errorf("problem parsing change address: %w", err)
+		}
+	}
+
+	builder := Builder{
+		NetworkID: service.vm.ctx.NetworkID,
+		ChainID:   service.vm.ctx.ChainID,
+		Codec:     service.vm.codec,
+	}
+	tx, err := builder.NewMultiSendTx(
+		utxos,
+		kc,
+		sends,
+		service.vm.ava,
+		service.vm.txFee,
+		changeAddr,
+		[]byte(args.Memo),
+		service.vm.clock.Unix(),
+	)
 	if err != nil {
-		return fmt.Errorf("problem parsing to address: %w", err)
+		return err
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	txID, err := service.vm.IssueTx(tx.Bytes())
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return fmt.Errorf("problem issuing transaction: %w", err)
 	}
 
-	user := userState{vm: service.vm}
+	reply.TxID = txID
+	return nil
+}
 
-	addresses, _ := user.Addresses(db)
+// spendFee returns inputs that burn [fee] AVA from [utxos], an output that
+// returns the change to the first key in [kc], and the keys that must sign
+// each input
+func (service *Service) spendFee(utxos []*ava.UTXO, kc *secp256k1fx.Keychain, fee uint64) ([]*ava.TransferableInput, []*ava.TransferableOutput, [][]*crypto.PrivateKeySECP256K1R, error) {
+	if fee == 0 {
+		return nil, nil, nil, nil
+	}
+	if len(kc.Keys) == 0 {
+		return nil, nil, nil, errInsufficientFunds
+	}
+
+	builder := Builder{
+		NetworkID: service.vm.ctx.NetworkID,
+		ChainID:   service.vm.ctx.ChainID,
+		Codec:     service.vm.codec,
+	}
+	return builder.SpendFee(
+		utxos,
+		kc,
+		service.vm.ava,
+		fee,
+		kc.Keys[0].PublicKey().Address(),
+		service.vm.clock.Unix(),
+	)
+}
 
-	addrs := ids.Set{}
-	addrs.Add(addresses...)
-	utxos, err := service.vm.GetUTXOs(addrs)
-	if err != nil {
-		return fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+// spendUserFee is spendFee with the UTXOs and keys of the user [username]. The
+// user is only loaded if there's a fee to pay.
+func (service *Service) spendUserFee(username, password string, fee uint64) ([]*ava.TransferableInput, []*ava.TransferableOutput, [][]*crypto.PrivateKeySECP256K1R, error) {
+	if fee == 0 {
+		return nil, nil, nil, nil
 	}
 
-	kc := secp256k1fx.NewKeychain()
-	for _, addr := range addresses {
-		sk, err := user.Key(db, addr)
-		if err != nil {
-			return fmt.Errorf("problem retrieving private key: %w", err)
-		}
-		kc.Add(sk)
+	utxos, kc, err := service.vm.LoadUser(username, password)
+	if err != nil {
+		return nil, nil, nil, err
 	}
+	return service.spendFee(utxos, kc, fee)
+}
 
-	amountSpent := uint64(0)
-	time := service.vm.clock.Unix()
-
-	ins := []*TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
-	for _, utxo := range utxos {
-		if !utxo.AssetID().Equals(assetID) {
-			continue
-		}
-		inputIntf, signers, err := kc.Spend(utxo.Out, time)
-		if err != nil {
-			continue
-		}
-		input, ok := inputIntf.(FxTransferable)
-		if !ok {
-			continue
-		}
-		spent, err := math.Add64(amountSpent, input.Amount())
-		if err != nil {
-			return errSpendOverflow
-		}
-		amountSpent = spent
-
-		in := &TransferableInput{
-			UTXOID: utxo.UTXOID,
-			Asset:  Asset{ID: assetID},
-			In:     input,
-		}
-
-		ins = append(ins, in)
-		keys = append(keys, signers)
-
-		if amountSpent >= uint64(args.Amount) {
-			break
-		}
-	}
-
-	if amountSpent < uint64(args.Amount) {
-		return errInsufficientFunds
-	}
-
-	sortTransferableInputsWithSigners(ins, keys)
-
-	outs := []*TransferableOutput{
-		&TransferableOutput{
-			Asset: Asset{
-				ID: assetID,
-			},
-			Out: &secp256k1fx.TransferOutput{
-				Amt:      uint64(args.Amount),
-				Locktime: 0,
-				OutputOwners: secp256k1fx.OutputOwners{
-					Threshold: 1,
-					Addrs:     []ids.ShortID{to},
-				},
-			},
-		},
-	}
-
-	if amountSpent > uint64(args.Amount) {
-		changeAddr := kc.Keys[0].PublicKey().Address()
-		outs = append(outs,
-			&TransferableOutput{
-				Asset: Asset{
-					ID: assetID,
-				},
-				Out: &secp256k1fx.TransferOutput{
-					Amt:      amountSpent - uint64(args.Amount),
-					Locktime: 0,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
-				},
-			},
-		)
-	}
-
-	sortTransferableOutputs(outs, service.vm.codec)
-
-	tx := Tx{
-		UnsignedTx: &BaseTx{
-			NetID: service.vm.ctx.NetworkID,
-			BCID:  service.vm.ctx.ChainID,
-			Outs:  outs,
-			Ins:   ins,
-		},
-	}
-
-	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-	hash := hashing.ComputeHash256(unsignedBytes)
-
-	for _, credKeys := range keys {
-		cred := &secp256k1fx.Credential{}
-		for _, key := range credKeys {
-			sig, err := key.SignHash(hash)
-			if err != nil {
-				return fmt.Errorf("problem creating transaction: %w", err)
-			}
-			fixedSig := [crypto.SECP256K1RSigLen]byte{}
-			copy(fixedSig[:], sig)
-
-			cred.Sigs = append(cred.Sigs, fixedSig)
-		}
-		tx.Creds = append(tx.Creds, &Credential{Cred: cred})
-	}
-
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-
-	txID, err := service.vm.IssueTx(b)
-	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
-	}
-
-	reply.TxID = txID
-	return nil
-}
-
-type innerSortTransferableInputsWithSigners struct {
-	ins     []*TransferableInput
-	signers [][]*crypto.PrivateKeySECP256K1R
-}
+type innerSortTransferableInputsWithSigners struct {
+	ins     []*ava.TransferableInput
+	signers [][]*crypto.PrivateKeySECP256K1R
+}
 
 func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
 	iID, iIndex := ins.ins[i].InputSource()
@@ -741,10 +1072,10 @@ func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
 	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
 }
 
-func sortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
+func sortTransferableInputsWithSigners(ins []*ava.TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
 	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
-func isSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
+func isSortedAndUniqueTransferableInputsWithSigners(ins []*ava.TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
 	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
 
@@ -831,7 +1162,7 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 					},
 					Ops: []*Operation{
 						&Operation{
-							Asset: Asset{
+							Asset: ava.Asset{
 								ID: assetID,
 							},
 							Ins: []*OperableInput{
@@ -894,20 +1225,17 @@ type SignMintTxReply struct {
 func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply *SignMintTxReply) error {
 	service.vm.ctx.Log.Verbo("SignMintTx called")
 
-	minter, err := service.vm.Parse(args.Minter)
+	minter, err := service.vm.parseAddress(args.Minter)
 	if err != nil {
 		return fmt.Errorf("problem parsing address '%s': %w", args.Minter, err)
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	book, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return err
 	}
 
-	user := userState{vm: service.vm}
-
-	addr := ids.NewID(hashing.ComputeHash256Array(minter))
-	sk, err := user.Key(db, addr)
+	sk, err := book.Key(minter)
 	if err != nil {
 		return fmt.Errorf("problem retriving private key: %w", err)
 	}
@@ -944,7 +1272,7 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 	case *secp256k1fx.MintOutput:
 		size = int(out.Threshold)
 		for j, addr := range out.Addrs {
-			if bytes.Equal(addr.Bytes(), minter) {
+			if addr.Equals(minter) {
 				i = j
 				break
 			}
@@ -989,3 +1317,1018 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 	reply.Tx.Bytes = txBytes
 	return nil
 }
+
+// MintArgs are arguments for passing into Mint requests
+type MintArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+	AssetID  string      `json:"assetID"`
+	To       string      `json:"to"`
+}
+
+// MintReply defines the Mint replies returned from the API
+type MintReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// Mint issues a transaction that mints more of the variable cap asset using
+// the minting authority held by the user's keys
+func (service *Service) Mint(r *http.Request, args *MintArgs, reply *MintReply) error {
+	service.vm.ctx.Log.Verbo("Mint called with username: %s", args.Username)
+
+	if args.Amount == 0 {
+		return errInvalidMintAmount
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	to, err := service.vm.parseAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	feeIns, feeOuts, feeKeys, err := service.spendFee(utxos, kc, service.vm.txFee)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.MintOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		sigIndices, keys, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		outs := []*OperableOutput{
+			&OperableOutput{
+				Out: &secp256k1fx.MintOutput{
+					OutputOwners: out.OutputOwners,
+				},
+			},
+			&OperableOutput{
+				Out: &secp256k1fx.TransferOutput{
+					Amt: uint64(args.Amount),
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		}
+		sortOperableOutputs(outs, service.vm.codec)
+
+		tx := Tx{UnsignedTx: &OperationTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+				Outs:  feeOuts,
+				Ins:   feeIns,
+			},
+			Ops: []*Operation{
+				&Operation{
+					Asset: ava.Asset{ID: assetID},
+					Ins: []*OperableInput{
+						&OperableInput{
+							UTXOID: utxo.UTXOID,
+							In: &secp256k1fx.MintInput{
+								Input: secp256k1fx.Input{
+									SigIndices: sigIndices,
+								},
+							},
+						},
+					},
+					Outs: outs,
+				},
+			},
+		}}
+		if err := tx.SignSECP256K1Fx(service.vm.codec, append(feeKeys, keys)); err != nil {
+			return fmt.Errorf("problem signing transaction: %w", err)
+		}
+
+		txID, err := service.vm.issueSignedTx(&tx)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errAddressesCantMintAsset
+}
+
+// CreateNFTAssetArgs are arguments for passing into CreateNFTAsset requests
+type CreateNFTAssetArgs struct {
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	Name       string   `json:"name"`
+	Symbol     string   `json:"symbol"`
+	MinterSets []Owners `json:"minterSets"`
+}
+
+// CreateNFTAssetReply defines the CreateNFTAsset replies returned from the API
+type CreateNFTAssetReply struct {
+	AssetID ids.ID `json:"assetID"`
+}
+
+// CreateNFTAsset returns ID of the newly created non-fungible asset. Each
+// minter set is given authority over its own group of NFTs.
+func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs, reply *CreateNFTAssetReply) error {
+	service.vm.ctx.Log.Verbo("CreateNFTAsset called with name: %s symbol: %s number of minters: %d",
+		args.Name,
+		args.Symbol,
+		len(args.MinterSets),
+	)
+
+	if len(args.MinterSets) == 0 {
+		return errNoMinters
+	}
+
+	nftFxIndex, err := service.vm.getFxIndex(nftfx.ID)
+	if err != nil {
+		return fmt.Errorf("problem finding the nft feature extension: %w", err)
+	}
+
+	ins, outs, keys, err := service.spendUserFee(args.Username, args.Password, service.vm.creationTxFee)
+	if err != nil {
+		return err
+	}
+
+	initialState := &InitialState{
+		FxID: uint32(nftFxIndex),
+		Outs: []verify.Verifiable{},
+	}
+
+	tx := &Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+		Name:         args.Name,
+		Symbol:       args.Symbol,
+		Denomination: 0, // NFTs are non-fungible
+		States: []*InitialState{
+			initialState,
+		},
+	}}
+
+	for i, owner := range args.MinterSets {
+		minter := &nftfx.MintOutput{
+			GroupID: uint32(i),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: uint32(owner.Threshold),
+			},
+		}
+		for _, address := range owner.Minters {
+			addr, err := service.vm.parseAddress(address)
+			if err != nil {
+				return err
+			}
+			minter.Addrs = append(minter.Addrs, addr)
+		}
+		minter.Sort()
+		initialState.Outs = append(initialState.Outs, minter)
+	}
+	initialState.Sort(service.vm.codec)
+
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return fmt.Errorf("problem signing transaction: %w", err)
+	}
+
+	assetID, err := service.vm.issueSignedTx(tx)
+	if err != nil {
+		return err
+	}
+
+	reply.AssetID = assetID
+	return nil
+}
+
+// MintNFTArgs are arguments for passing into MintNFT requests
+type MintNFTArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	AssetID  string          `json:"assetID"`
+	Payload  formatting.CB58 `json:"payload"`
+	To       string          `json:"to"`
+}
+
+// MintNFTReply defines the MintNFT replies returned from the API
+type MintNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// MintNFT issues a transaction that mints a new NFT carrying [args.Payload]
+func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *MintNFTReply) error {
+	service.vm.ctx.Log.Verbo("MintNFT called with username: %s", args.Username)
+
+	if len(args.Payload.Bytes) > nftfx.MaxPayloadSize {
+		return errPayloadTooLarge
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	to, err := service.vm.parseAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	feeIns, feeOuts, feeKeys, err := service.spendFee(utxos, kc, service.vm.txFee)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.MintOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		sigIndices, keys, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		outs := []*OperableOutput{
+			&OperableOutput{
+				Out: &nftfx.MintOutput{
+					GroupID:      out.GroupID,
+					OutputOwners: out.OutputOwners,
+				},
+			},
+			&OperableOutput{
+				Out: &nftfx.TransferOutput{
+					GroupID: out.GroupID,
+					Payload: args.Payload.Bytes,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		}
+		sortOperableOutputs(outs, service.vm.codec)
+
+		tx := Tx{UnsignedTx: &OperationTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+				Outs:  feeOuts,
+				Ins:   feeIns,
+			},
+			Ops: []*Operation{
+				&Operation{
+					Asset: ava.Asset{ID: assetID},
+					Ins: []*OperableInput{
+						&OperableInput{
+							UTXOID: utxo.UTXOID,
+							In: &nftfx.MintInput{
+								Input: secp256k1fx.Input{
+									SigIndices: sigIndices,
+								},
+							},
+						},
+					},
+					Outs: outs,
+				},
+			},
+		}}
+		// The credentials of the fee inputs come before those of the operation
+		if err := tx.SignSECP256K1Fx(service.vm.codec, feeKeys); err != nil {
+			return fmt.Errorf("problem signing transaction: %w", err)
+		}
+		if err := tx.SignNFTFx(service.vm.codec, [][]*crypto.PrivateKeySECP256K1R{keys}); err != nil {
+			return fmt.Errorf("problem signing transaction: %w", err)
+		}
+
+		txID, err := service.vm.issueSignedTx(&tx)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errAddressesCantMintAsset
+}
+
+// SendNFTArgs are arguments for passing into SendNFT requests
+type SendNFTArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	AssetID  string      `json:"assetID"`
+	GroupID  json.Uint32 `json:"groupID"`
+	To       string      `json:"to"`
+}
+
+// SendNFTReply defines the SendNFT replies returned from the API
+type SendNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// SendNFT sends an NFT of the group [args.GroupID] owned by the user to [args.To]
+func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *SendNFTReply) error {
+	service.vm.ctx.Log.Verbo("SendNFT called with username: %s", args.Username)
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	to, err := service.vm.parseAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	feeIns, feeOuts, feeKeys, err := service.spendFee(utxos, kc, service.vm.txFee)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) || out.GroupID != uint32(args.GroupID) {
+			continue
+		}
+		sigIndices, keys, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		tx := Tx{UnsignedTx: &OperationTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+				Outs:  feeOuts,
+				Ins:   feeIns,
+			},
+			Ops: []*Operation{
+				&Operation{
+					Asset: ava.Asset{ID: assetID},
+					Ins: []*OperableInput{
+						&OperableInput{
+							UTXOID: utxo.UTXOID,
+							In: &nftfx.TransferInput{
+								Input: secp256k1fx.Input{
+									SigIndices: sigIndices,
+								},
+							},
+						},
+					},
+					Outs: []*OperableOutput{
+						&OperableOutput{
+							Out: &nftfx.TransferOutput{
+								GroupID: out.GroupID,
+								Payload: out.Payload,
+								OutputOwners: secp256k1fx.OutputOwners{
+									Threshold: 1,
+									Addrs:     []ids.ShortID{to},
+								},
+							},
+						},
+					},
+				},
+			},
+		}}
+		// The credentials of the fee inputs come before those of the operation
+		if err := tx.SignSECP256K1Fx(service.vm.codec, feeKeys); err != nil {
+			return fmt.Errorf("problem signing transaction: %w", err)
+		}
+		if err := tx.SignNFTFx(service.vm.codec, [][]*crypto.PrivateKeySECP256K1R{keys}); err != nil {
+			return fmt.Errorf("problem signing transaction: %w", err)
+		}
+
+		txID, err := service.vm.issueSignedTx(&tx)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+
+	return errNoNFTOwned
+}
+
+// CreateMultisigAddressArgs are arguments for passing into
+// CreateMultisigAddress requests
+type CreateMultisigAddressArgs struct {
+	Threshold json.Uint32 `json:"threshold"`
+	Addresses []string    `json:"addresses"`
+}
+
+// CreateMultisigAddressReply defines the CreateMultisigAddress replies returned
+// from the API
+type CreateMultisigAddressReply struct {
+	Address string `json:"address"`
+}
+
+// CreateMultisigAddress returns an address that can be sent funds that require
+// [args.Threshold] of the [args.Addresses] to sign in order to be spent
+func (service *Service) CreateMultisigAddress(r *http.Request, args *CreateMultisigAddressArgs, reply *CreateMultisigAddressReply) error {
+	service.vm.ctx.Log.Verbo("CreateMultisigAddress called with threshold: %d number of addresses: %d",
+		args.Threshold,
+		len(args.Addresses),
+	)
+
+	if args.Threshold == 0 || int(args.Threshold) > len(args.Addresses) {
+		return errInvalidThreshold
+	}
+
+	owners := &secp256k1fx.OutputOwners{Threshold: uint32(args.Threshold)}
+	for _, address := range args.Addresses {
+		addr, err := service.vm.parseAddress(address)
+		if err != nil {
+			return fmt.Errorf("problem parsing address '%s': %w", address, err)
+		}
+		owners.Addrs = append(owners.Addrs, addr)
+	}
+	owners.Sort()
+	if err := owners.Verify(); err != nil {
+		return err
+	}
+
+	addr, err := service.vm.formatOwners(owners)
+	if err != nil {
+		return fmt.Errorf("problem formatting address: %w", err)
+	}
+	reply.Address = addr
+	return nil
+}
+
+// CreateSpendTxArgs are arguments for passing into CreateSpendTx requests
+type CreateSpendTxArgs struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Amount  json.Uint64 `json:"amount"`
+	AssetID string      `json:"assetID"`
+	Signers []string    `json:"signers"`
+}
+
+// CreateSpendTxReply defines the CreateSpendTx replies returned from the API
+type CreateSpendTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// CreateSpendTx returns a newly created unsigned transaction that sends funds
+// held by [args.From] to [args.To]. Change is returned to [args.From]. If
+// [args.Signers] is empty, the first addresses of [args.From] up to the
+// threshold are expected to sign. The returned transaction should be signed
+// with SignTx.
+func (service *Service) CreateSpendTx(r *http.Request, args *CreateSpendTxArgs, reply *CreateSpendTxReply) error {
+	service.vm.ctx.Log.Verbo("CreateSpendTx called with from: %s to: %s", args.From, args.To)
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+
+	assetID, err := service.vm.lookupAssetID(args.AssetID)
+	if err != nil {
+		return err
+	}
+
+	from, err := service.vm.parseOwners(args.From)
+	if err != nil {
+		return fmt.Errorf("problem parsing from address: %w", err)
+	}
+	to, err := service.vm.parseOwners(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address: %w", err)
+	}
+
+	sigIndices := []uint32{}
+	if len(args.Signers) == 0 {
+		for i := uint32(0); i < from.Threshold; i++ {
+			sigIndices = append(sigIndices, i)
+		}
+	}
+	for _, signer := range args.Signers {
+		addr, err := service.vm.parseAddress(signer)
+		if err != nil {
+			return fmt.Errorf("problem parsing signer address '%s': %w", signer, err)
+		}
+		index := -1
+		for i, owner := range from.Addrs {
+			if owner.Equals(addr) {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return errUnneededAddress
+		}
+		sigIndices = append(sigIndices, uint32(index))
+	}
+	utils.SortUint32(sigIndices)
+	if uint32(len(sigIndices)) != from.Threshold || !utils.IsSortedAndUniqueUint32(sigIndices) {
+		return errInvalidSigners
+	}
+
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(from.Addrs[0].Bytes())))
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	amountSpent := uint64(0)
+	time := service.vm.clock.Unix()
+
+	ins := []*ava.TransferableInput{}
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(assetID) {
+			continue
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || out.Locktime > time || !out.OutputOwners.Equals(from) {
+			continue
+		}
+		spent, err := math.Add64(amountSpent, out.Amt)
+		if err != nil {
+			return errSpendOverflow
+		}
+		amountSpent = spent
+
+		ins = append(ins, &ava.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  ava.Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: out.Amt,
+				Input: secp256k1fx.Input{
+					SigIndices: sigIndices,
+				},
+			},
+		})
+
+		if amountSpent >= uint64(args.Amount) {
+			break
+		}
+	}
+
+	if amountSpent < uint64(args.Amount) {
+		return errInsufficientFunds
+	}
+
+	ava.SortTransferableInputs(ins)
+
+	outs := []*ava.TransferableOutput{
+		&ava.TransferableOutput{
+			Asset: ava.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          uint64(args.Amount),
+				OutputOwners: *to,
+			},
+		},
+	}
+	if amountSpent > uint64(args.Amount) {
+		outs = append(outs, &ava.TransferableOutput{
+			Asset: ava.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amountSpent - uint64(args.Amount),
+				OutputOwners: *from,
+			},
+		})
+	}
+	ava.SortTransferableOutputs(outs, service.vm.codec)
+
+	tx := Tx{UnsignedTx: &BaseTx{
+		NetID: service.vm.ctx.NetworkID,
+		BCID:  service.vm.ctx.ChainID,
+		Outs:  outs,
+		Ins:   ins,
+	}}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	return nil
+}
+
+// SignTxArgs are arguments for passing into SignTx requests
+type SignTxArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Tx       formatting.CB58 `json:"tx"`
+}
+
+// SignTxReply defines the SignTx replies returned from the API
+type SignTxReply struct {
+	Tx                formatting.CB58 `json:"tx"`
+	MissingSignatures json.Uint32     `json:"missingSignatures"`
+}
+
+// SignTx adds the signatures that the user is able to produce to the provided
+// transaction. Signatures that were already provided are kept, so a
+// transaction can be passed between the holders of a multisig output until
+// [reply.MissingSignatures] is zero, at which point it can be issued.
+func (service *Service) SignTx(r *http.Request, args *SignTxArgs, reply *SignTxReply) error {
+	service.vm.ctx.Log.Verbo("SignTx called with username: %s", args.Username)
+
+	_, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+
+	type spend struct {
+		utxoID *ava.UTXOID
+		in     verify.Verifiable
+	}
+	spends := []spend{}
+	for _, in := range tx.Inputs() {
+		spends = append(spends, spend{utxoID: &in.UTXOID, in: in.In})
+	}
+	if opTx, ok := tx.UnsignedTx.(*OperationTx); ok {
+		for _, op := range opTx.Operations() {
+			for _, in := range op.Ins {
+				spends = append(spends, spend{utxoID: &in.UTXOID, in: in.In})
+			}
+		}
+	}
+
+	if len(tx.Creds) > len(spends) {
+		return errWrongNumberOfCredentials
+	}
+
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	missing := uint32(0)
+	for i, spend := range spends {
+		utxo, err := service.vm.getUTXO(spend.utxoID)
+		if err != nil {
+			return err
+		}
+
+		var owners *secp256k1fx.OutputOwners
+		switch out := utxo.Out.(type) {
+		case *secp256k1fx.TransferOutput:
+			owners = &out.OutputOwners
+		case *secp256k1fx.MintOutput:
+			owners = &out.OutputOwners
+		case *nftfx.TransferOutput:
+			owners = &out.OutputOwners
+		case *nftfx.MintOutput:
+			owners = &out.OutputOwners
+		default:
+			return errUnknownOutputType
+		}
+
+		var (
+			input   *secp256k1fx.Input
+			newCred verify.Verifiable
+		)
+		switch in := spend.in.(type) {
+		case *secp256k1fx.TransferInput:
+			input, newCred = &in.Input, &secp256k1fx.Credential{}
+		case *secp256k1fx.MintInput:
+			input, newCred = &in.Input, &secp256k1fx.Credential{}
+		case *nftfx.TransferInput:
+			input, newCred = &in.Input, &nftfx.Credential{}
+		case *nftfx.MintInput:
+			input, newCred = &in.Input, &nftfx.Credential{}
+		default:
+			return errUnknownInputType
+		}
+
+		if i == len(tx.Creds) {
+			tx.Creds = append(tx.Creds, &Credential{Cred: newCred})
+		}
+
+		var cred *secp256k1fx.Credential
+		switch c := tx.Creds[i].Cred.(type) {
+		case *secp256k1fx.Credential:
+			cred = c
+		case *nftfx.Credential:
+			cred = &c.Credential
+		default:
+			return errUnknownCredentialType
+		}
+		switch len(cred.Sigs) {
+		case 0:
+			cred.Sigs = make([][crypto.SECP256K1RSigLen]byte, len(input.SigIndices))
+		case len(input.SigIndices):
+		default:
+			return errWrongNumberOfSignatures
+		}
+
+		for j, index := range input.SigIndices {
+			if cred.Sigs[j] != emptySig {
+				continue
+			}
+			if index >= uint32(len(owners.Addrs)) {
+				return errInvalidUTXO
+			}
+			sk, exists := kc.Get(owners.Addrs[index])
+			if !exists {
+				missing++
+				continue
+			}
+			sig, err := sk.SignHash(hash)
+			if err != nil {
+				return fmt.Errorf("problem signing transaction: %w", err)
+			}
+			copy(cred.Sigs[j][:], sig)
+		}
+	}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	reply.MissingSignatures = json.Uint32(missing)
+	return nil
+}
+
+// ExportAVAArgs are arguments for passing into ExportAVA requests
+type ExportAVAArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+	To       string      `json:"to"`
+
+	// The chain the AVA is sent to, the platform chain by default
+	DestinationChain string `json:"destinationChain"`
+}
+
+// ExportAVAReply defines the ExportAVA replies returned from the API
+type ExportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ExportAVA sends AVA from this chain to [args.DestinationChain], the platform
+// chain or the C-Chain. After the transaction is accepted, the AVA must be
+// imported on that chain to complete the transfer.
+func (service *Service) ExportAVA(r *http.Request, args *ExportAVAArgs, reply *ExportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ExportAVA called with username: %s", args.Username)
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+
+	destinationChain, err := service.vm.lookupAtomicChain(args.DestinationChain)
+	if err != nil {
+		return fmt.Errorf("problem parsing destination chain '%s': %w", args.DestinationChain, err)
+	}
+
+	to, err := service.vm.parseAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address: %w", err)
+	}
+
+	utxos, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	// The exported AVA and the tx fee are spent
+	amountWithFee, err := math.Add64(uint64(args.Amount), service.vm.txFee)
+	if err != nil {
+		return errSpendOverflow
+	}
+
+	amountSpent := uint64(0)
+	time := service.vm.clock.Unix()
+
+	ins := []*ava.TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(service.vm.ava) {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(ava.Transferable)
+		if !ok {
+			continue
+		}
+		spent, err := math.Add64(amountSpent, input.Amount())
+		if err != nil {
+			return errSpendOverflow
+		}
+		amountSpent = spent
+
+		ins = append(ins, &ava.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  ava.Asset{ID: service.vm.ava},
+			In:     input,
+		})
+		keys = append(keys, signers)
+
+		if amountSpent >= amountWithFee {
+			break
+		}
+	}
+
+	if amountSpent < amountWithFee {
+		return errInsufficientFunds
+	}
+
+	sortTransferableInputsWithSigners(ins, keys)
+
+	exportedOuts := []*ava.TransferableOutput{
+		&ava.TransferableOutput{
+			Asset: ava.Asset{ID: service.vm.ava},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: uint64(args.Amount),
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		},
+	}
+
+	outs := []*ava.TransferableOutput{}
+	if amountSpent > amountWithFee {
+		changeAddr := kc.Keys[0].PublicKey().Address()
+		outs = append(outs, &ava.TransferableOutput{
+			Asset: ava.Asset{ID: service.vm.ava},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: amountSpent - amountWithFee,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{changeAddr},
+				},
+			},
+		})
+	}
+
+	tx := Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+		DestinationChain: destinationChain,
+		ExportedOuts:     exportedOuts,
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.issueSignedTx(&tx)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// ImportAVAArgs are arguments for passing into ImportAVA requests
+type ImportAVAArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	To       string `json:"to"`
+
+	// The chain the AVA was exported from, the platform chain by default
+	SourceChain string `json:"sourceChain"`
+}
+
+// ImportAVAReply defines the ImportAVA replies returned from the API
+type ImportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ImportAVA imports all the AVA that [args.SourceChain], the platform chain or
+// the C-Chain, has exported to the user's addresses, sending it to [args.To]
+func (service *Service) ImportAVA(r *http.Request, args *ImportAVAArgs, reply *ImportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ImportAVA called with username: %s", args.Username)
+
+	sourceChain, err := service.vm.lookupAtomicChain(args.SourceChain)
+	if err != nil {
+		return fmt.Errorf("problem parsing source chain '%s': %w", args.SourceChain, err)
+	}
+
+	to, err := service.vm.parseAddress(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address: %w", err)
+	}
+
+	_, kc, err := service.vm.LoadUser(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	addrs := ids.Set{}
+	for _, addr := range kc.Addresses().List() {
+		addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	}
+
+	utxos, err := service.vm.GetAtomicUTXOs(sourceChain, addrs)
+	if err != nil {
+		return fmt.Errorf("problem retrieving shared UTXOs: %w", err)
+	}
+
+	amount := uint64(0)
+	time := service.vm.clock.Unix()
+
+	ins := []*ava.TransferableInput{}
+	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	for _, utxo := range utxos {
+		if !utxo.AssetID().Equals(service.vm.ava) {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(ava.Transferable)
+		if !ok {
+			continue
+		}
+		newAmount, err := math.Add64(amount, input.Amount())
+		if err != nil {
+			return errSpendOverflow
+		}
+		amount = newAmount
+
+		ins = append(ins, &ava.TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  ava.Asset{ID: service.vm.ava},
+			In:     input,
+		})
+		keys = append(keys, signers)
+	}
+
+	if len(ins) == 0 {
+		return errNoImportInputs
+	}
+	// The tx fee is paid out of the imported AVA
+	if amount <= service.vm.txFee {
+		return errInsufficientFunds
+	}
+
+	sortTransferableInputsWithSigners(ins, keys)
+
+	tx := Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs: []*ava.TransferableOutput{&ava.TransferableOutput{
+				Asset: ava.Asset{ID: service.vm.ava},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amount - service.vm.txFee,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			}},
+		},
+		SourceChain: sourceChain,
+		ImportedIns: ins,
+	}}
+	if err := tx.SignSECP256K1Fx(service.vm.codec, keys); err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.issueSignedTx(&tx)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	return nil
+}
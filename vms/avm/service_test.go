@@ -4,14 +4,95 @@
 package avm
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
+var (
+	testUsername = "bob"
+	testPassword = "launch"
+)
+
+// setupKeystoreVM creates a VM, running both the secp256k1fx and the nftfx,
+// whose keystore contains a user that holds keys[0]. The context lock must be
+// held by the caller.
+func setupKeystoreVM(t *testing.T) (*VM, *Service) { return setupKeystoreVMWithDB(t, memdb.New()) }
+
+// setupKeystoreVMWithDB is the same as setupKeystoreVM but stores the VM's
+// state in [db]
+func setupKeystoreVMWithDB(t *testing.T, db database.Database) (*VM, *Service) {
+	ks := &keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: testUsername,
+		Password: testPassword,
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Keystore = ks.NewBlockchainKeyStore(chainID)
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		db,
+		BuildGenesisTest(t),
+		nil,
+		make(chan common.Message, 1),
+		[]*common.Fx{
+			&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			},
+			&common.Fx{
+				ID: nftfx.ID,
+				Fx: &nftfx.Fx{},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	s := &Service{vm: vm}
+	if err := s.ImportKey(nil, &ImportKeyArgs{
+		Username:   testUsername,
+		Password:   testPassword,
+		PrivateKey: formatting.CB58{Bytes: keys[0].Bytes()},
+	}, &ImportKeyReply{}); err != nil {
+		t.Fatal(err)
+	}
+	return vm, s
+}
+
+// acceptPendingTxs accepts every transaction currently pending in the VM
+func acceptPendingTxs(t *testing.T, vm *VM) {
+	for _, tx := range vm.PendingTxs() {
+		if err := tx.Verify(); err != nil {
+			t.Fatal(err)
+		}
+		tx.Accept()
+		if status := tx.Status(); status != choices.Accepted {
+			t.Fatalf("Tx should have been accepted but was %s", status)
+		}
+	}
+}
+
 func TestGetAssetDescription(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -23,6 +104,7 @@ func TestGetAssetDescription(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -67,6 +149,7 @@ func TestGetBalance(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -109,6 +192,7 @@ func TestCreateFixedCapAsset(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -152,6 +236,7 @@ func TestCreateVariableCapAsset(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -186,3 +271,779 @@ func TestCreateVariableCapAsset(t *testing.T) {
 		t.Fatalf("Wrong assetID returned from CreateFixedCapAsset %s", reply.AssetID)
 	}
 }
+
+func TestServiceMint(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	reply := MintReply{}
+	if err := s.Mint(nil, &MintArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		AssetID:  "asset3",
+		To:       addr,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: addr,
+		AssetID: "asset3",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 100 {
+		t.Fatalf("Wrong balance after minting. Expected %d, got %d", 100, balance.Balance)
+	}
+}
+
+func TestServiceTxFees(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	feeAssetID, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.ava = feeAssetID
+	vm.txFee = 5
+	vm.creationTxFee = 50
+
+	fees := GetTxFeeReply{}
+	if err := s.GetTxFee(nil, nil, &fees); err != nil {
+		t.Fatal(err)
+	}
+	if fees.TxFee != 5 || fees.CreationTxFee != 50 {
+		t.Fatalf("Wrong fees returned. Expected %d and %d, got %d and %d", 5, 50, fees.TxFee, fees.CreationTxFee)
+	}
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+	before := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{Address: addr, AssetID: "asset1"}, &before); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Mint(nil, &MintArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		AssetID:  "asset3",
+		To:       addr,
+	}, &MintReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Name:     "test nft",
+		Symbol:   "nft",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{addr},
+			},
+		},
+	}, &CreateNFTAssetReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	after := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{Address: addr, AssetID: "asset1"}, &after); err != nil {
+		t.Fatal(err)
+	}
+	if expected := before.Balance - 55; after.Balance != expected {
+		t.Fatalf("Wrong balance after paying the fees. Expected %d, got %d", expected, after.Balance)
+	}
+}
+
+func TestServiceMintUnauthorized(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	if err := s.Mint(nil, &MintArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		AssetID:  "asset1",
+		To:       vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &MintReply{}); err == nil {
+		t.Fatalf("Should have errored due to minting a fixed cap asset")
+	}
+}
+
+func TestServiceNFT(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	createReply := CreateNFTAssetReply{}
+	if err := s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Name:     "myNFT",
+		Symbol:   "NFT",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{addr},
+			},
+		},
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	payload := []byte{'h', 'e', 'l', 'l', 'o'}
+	if err := s.MintNFT(nil, &MintNFTArgs{
+		Username: testUsername,
+		Password: testPassword,
+		AssetID:  createReply.AssetID.String(),
+		Payload:  formatting.CB58{Bytes: payload},
+		To:       addr,
+	}, &MintNFTReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	to := ids.NewShortID([20]byte{1})
+	if err := s.SendNFT(nil, &SendNFTArgs{
+		Username: testUsername,
+		Password: testPassword,
+		AssetID:  createReply.AssetID.String(),
+		GroupID:  0,
+		To:       vm.Format(to.Bytes()),
+	}, &SendNFTReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	addrs := ids.Set{}
+	addrs.Add(ids.NewID(hashing.ComputeHash256Array(to.Bytes())))
+	utxos, err := vm.GetUTXOs(addrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatalf("Expected the recipient to own %d utxo(s), but found %d", 1, len(utxos))
+	}
+	out, ok := utxos[0].Out.(*nftfx.TransferOutput)
+	if !ok {
+		t.Fatalf("Expected an NFT transfer output")
+	}
+	if !bytes.Equal(out.Payload, payload) {
+		t.Fatalf("NFT payload was modified")
+	}
+
+	if err := s.SendNFT(nil, &SendNFTArgs{
+		Username: testUsername,
+		Password: testPassword,
+		AssetID:  createReply.AssetID.String(),
+		GroupID:  0,
+		To:       addr,
+	}, &SendNFTReply{}); err == nil {
+		t.Fatalf("Should have errored due to the NFT already being sent")
+	}
+}
+
+func TestServiceMultisig(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr0 := vm.Format(keys[0].PublicKey().Address().Bytes())
+	addr1 := vm.Format(keys[1].PublicKey().Address().Bytes())
+	addr2 := vm.Format(keys[2].PublicKey().Address().Bytes())
+
+	multisigReply := CreateMultisigAddressReply{}
+	if err := s.CreateMultisigAddress(nil, &CreateMultisigAddressArgs{
+		Threshold: 2,
+		Addresses: []string{addr1, addr0},
+	}, &multisigReply); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   1000,
+		AssetID:  "asset1",
+		To:       multisigReply.Address,
+	}, &SendReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	spendReply := CreateSpendTxReply{}
+	if err := s.CreateSpendTx(nil, &CreateSpendTxArgs{
+		From:    multisigReply.Address,
+		To:      addr2,
+		Amount:  100,
+		AssetID: "asset1",
+	}, &spendReply); err != nil {
+		t.Fatal(err)
+	}
+
+	signReply := SignTxReply{}
+	if err := s.SignTx(nil, &SignTxArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Tx:       spendReply.Tx,
+	}, &signReply); err != nil {
+		t.Fatal(err)
+	}
+	if signReply.MissingSignatures != 1 {
+		t.Fatalf("Expected %d missing signature(s), but found %d", 1, signReply.MissingSignatures)
+	}
+
+	if err := s.ImportKey(nil, &ImportKeyArgs{
+		Username:   testUsername,
+		Password:   testPassword,
+		PrivateKey: formatting.CB58{Bytes: keys[1].Bytes()},
+	}, &ImportKeyReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SignTx(nil, &SignTxArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Tx:       signReply.Tx,
+	}, &signReply); err != nil {
+		t.Fatal(err)
+	}
+	if signReply.MissingSignatures != 0 {
+		t.Fatalf("Expected %d missing signature(s), but found %d", 0, signReply.MissingSignatures)
+	}
+
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: signReply.Tx.String()}, &IssueTxReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: addr2,
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 100 {
+		t.Fatalf("Wrong balance after spending. Expected %d, got %d", 100, balance.Balance)
+	}
+}
+
+func TestServiceSendLocked(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr1 := vm.Format(keys[1].PublicKey().Address().Bytes())
+	addr2 := vm.Format(keys[2].PublicKey().Address().Bytes())
+
+	now := time.Unix(1000, 0)
+	vm.clock.Set(now)
+	defer vm.clock.Sync()
+
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   1000,
+		AssetID:  "asset1",
+		To:       addr1,
+		Locktime: json.Uint64(now.Add(time.Hour).Unix()),
+	}, &SendReply{}); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	spendArgs := &CreateSpendTxArgs{
+		From:    addr1,
+		To:      addr2,
+		Amount:  100,
+		AssetID: "asset1",
+	}
+	if err := s.CreateSpendTx(nil, spendArgs, &CreateSpendTxReply{}); err == nil {
+		t.Fatalf("Should have errored because the funds are locked")
+	}
+
+	vm.clock.Set(now.Add(time.Hour))
+	if err := s.CreateSpendTx(nil, spendArgs, &CreateSpendTxReply{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServiceSendMultipleOutputsWithMemo(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr1 := vm.Format(keys[1].PublicKey().Address().Bytes())
+	addr2 := vm.Format(keys[2].PublicKey().Address().Bytes())
+
+	changeReply := CreateAddressReply{}
+	if err := s.CreateAddress(nil, &CreateAddressArgs{
+		Username: testUsername,
+		Password: testPassword,
+	}, &changeReply); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		AssetID:  "asset1",
+		To:       addr1,
+		Outputs: []Recipient{{
+			Amount:  200,
+			AssetID: "asset1",
+			To:      addr2,
+		}},
+	}, &SendReply{}); err == nil {
+		t.Fatalf("Should have errored due to giving both a single output and outputs")
+	}
+
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   100,
+		AssetID:  "asset1",
+		To:       addr1,
+		Memo:     string(make([]byte, MaxMemoSize+1)),
+	}, &SendReply{}); err == nil {
+		t.Fatalf("Should have errored due to the memo being too large")
+	}
+
+	reply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Outputs: []Recipient{
+			{
+				Amount:  100,
+				AssetID: "asset1",
+				To:      addr1,
+			},
+			{
+				Amount:  200,
+				AssetID: "asset1",
+				To:      addr2,
+			},
+		},
+		ChangeAddr: changeReply.Address,
+		Memo:       "deposit 42",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	txBytes, err := s.getTx(reply.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := Tx{}
+	if err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	memoTx, ok := tx.UnsignedTx.(*MemoTx)
+	if !ok {
+		t.Fatalf("Tx should have been a memo tx but was %T", tx.UnsignedTx)
+	}
+	if memo := string(memoTx.Memo); memo != "deposit 42" {
+		t.Fatalf("Tx should have had memo %q but had %q", "deposit 42", memo)
+	}
+
+	for addr, expected := range map[string]uint64{addr1: 100, addr2: 200} {
+		balance := GetBalanceReply{}
+		if err := s.GetBalance(nil, &GetBalanceArgs{
+			Address: addr,
+			AssetID: "asset1",
+		}, &balance); err != nil {
+			t.Fatal(err)
+		}
+		if uint64(balance.Balance) != expected {
+			t.Fatalf("Wrong balance of %s. Expected %d, got %d", addr, expected, balance.Balance)
+		}
+	}
+
+	balance := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: changeReply.Address,
+		AssetID: "asset1",
+	}, &balance); err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance == 0 {
+		t.Fatalf("The change should have been sent to the change address")
+	}
+}
+
+func TestServiceCreateMultisigAddressInvalidThreshold(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	if err := s.CreateMultisigAddress(nil, &CreateMultisigAddressArgs{
+		Threshold: 2,
+		Addresses: []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+	}, &CreateMultisigAddressReply{}); err == nil {
+		t.Fatalf("Should have errored due to the threshold exceeding the number of addresses")
+	}
+}
+
+func TestServiceSignTxWrongNumberOfSignatures(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	spendReply := CreateSpendTxReply{}
+	if err := s.CreateSpendTx(nil, &CreateSpendTxArgs{
+		From:    vm.Format(keys[0].PublicKey().Address().Bytes()),
+		To:      vm.Format(keys[1].PublicKey().Address().Bytes()),
+		Amount:  100,
+		AssetID: "asset1",
+	}, &spendReply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Tx{}
+	if err := vm.codec.Unmarshal(spendReply.Tx.Bytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{
+		Sigs: make([][crypto.SECP256K1RSigLen]byte, 2),
+	}})
+	txBytes, err := vm.codec.Marshal(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SignTx(nil, &SignTxArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Tx:       formatting.CB58{Bytes: txBytes},
+	}, &SignTxReply{}); err != errWrongNumberOfSignatures {
+		t.Fatalf("Should have errored due to the credential having too many signatures")
+	}
+}
+
+func TestServiceGetTxAndAddressTxs(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	to := ids.NewShortID([20]byte{2})
+	toAddr := vm.Format(to.Bytes())
+
+	addrTxs := GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: toAddr}, &addrTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(addrTxs.TxIDs) != 0 {
+		t.Fatalf("Expected an unused address to have no txs, but found %d", len(addrTxs.TxIDs))
+	}
+
+	sendReply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   10,
+		AssetID:  "asset1",
+		To:       toAddr,
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	txReply := GetTxReply{}
+	if err := s.GetTx(nil, &GetTxArgs{TxID: sendReply.TxID, Encoding: formatting.HexEncoding}, &txReply); err != nil {
+		t.Fatal(err)
+	}
+	if txReply.Encoding != formatting.HexEncoding {
+		t.Fatalf("GetTx returned the tx in %s rather than hex", txReply.Encoding)
+	}
+	txBytes, err := formatting.HexEncoding.Decode(txReply.Tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := Tx{}
+	if err := vm.codec.Unmarshal(txBytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	tx.Initialize(txBytes)
+	if !tx.ID().Equals(sendReply.TxID) {
+		t.Fatalf("GetTx returned the wrong tx")
+	}
+
+	for _, addr := range []string{toAddr, vm.Format(keys[0].PublicKey().Address().Bytes())} {
+		if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: addr}, &addrTxs); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, txID := range addrTxs.TxIDs {
+			found = found || txID.Equals(sendReply.TxID)
+		}
+		if !found {
+			t.Fatalf("Expected %s to reference tx %s", addr, sendReply.TxID)
+		}
+	}
+
+	if err := s.GetTx(nil, &GetTxArgs{TxID: ids.NewID([32]byte{1})}, &txReply); err == nil {
+		t.Fatalf("Should have errored due to an unknown tx")
+	}
+}
+
+func TestServiceDecodeTx(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	sendReply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   10,
+		AssetID:  "asset1",
+		To:       vm.Format(ids.NewShortID([20]byte{2}).Bytes()),
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+
+	txReply := GetTxReply{}
+	if err := s.GetTx(nil, &GetTxArgs{TxID: sendReply.TxID}, &txReply); err != nil {
+		t.Fatal(err)
+	}
+	decodeReply := DecodeTxReply{}
+	if err := s.DecodeTx(nil, &DecodeTxArgs{Tx: txReply.Tx}, &decodeReply); err != nil {
+		t.Fatal(err)
+	}
+	if !decodeReply.TxID.Equals(sendReply.TxID) {
+		t.Fatalf("Wrong ID returned from DecodeTx. Expected %s, got %s", sendReply.TxID, decodeReply.TxID)
+	}
+
+	// The JSON holds the whole tx
+	tx := Tx{}
+	if err := vm.codec.UnmarshalCanonicalJSON(decodeReply.Tx, &tx); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := vm.codec.Marshal(&tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := formatting.CB58{Bytes: txBytes}.String()
+	if decoded != txReply.Tx {
+		t.Fatalf("Wrong tx after re-encoding the decoded tx. Expected %s, got %s", txReply.Tx, decoded)
+	}
+
+	if err := s.DecodeTx(nil, &DecodeTxArgs{Tx: formatting.CB58{Bytes: []byte{1}}.String()}, &decodeReply); err == nil {
+		t.Fatalf("Should have errored due to an invalid tx")
+	}
+}
+
+func TestServiceGetAddressTxsPagination(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	toAddr := vm.Format(ids.NewShortID([20]byte{2}).Bytes())
+
+	txIDs := []ids.ID{}
+	for i := 1; i <= 3; i++ {
+		sendReply := SendReply{}
+		if err := s.Send(nil, &SendArgs{
+			Username: testUsername,
+			Password: testPassword,
+			Amount:   json.Uint64(i),
+			AssetID:  "asset1",
+			To:       toAddr,
+		}, &sendReply); err != nil {
+			t.Fatal(err)
+		}
+		acceptPendingTxs(t, vm)
+		txIDs = append(txIDs, sendReply.TxID)
+	}
+
+	reply := GetAddressTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{
+		Address: toAddr,
+		Limit:   2,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(reply.TxIDs) != 2:
+		t.Fatalf("Expected %d txs, but found %d", 2, len(reply.TxIDs))
+	case !reply.TxIDs[0].Equals(txIDs[0]) || !reply.TxIDs[1].Equals(txIDs[1]):
+		t.Fatalf("Txs should have been returned in the order they were accepted")
+	case !reply.More:
+		t.Fatalf("Should have reported that more txs remain")
+	}
+
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{
+		Address:    toAddr,
+		Limit:      2,
+		StartIndex: reply.EndIndex,
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(reply.TxIDs) != 1:
+		t.Fatalf("Expected %d txs, but found %d", 1, len(reply.TxIDs))
+	case !reply.TxIDs[0].Equals(txIDs[2]):
+		t.Fatalf("Wrong tx returned on the last page")
+	case reply.More:
+		t.Fatalf("Should have reported that no txs remain")
+	}
+}
+
+func TestServiceGetAddressTxsNotIndexed(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+	vm.config.IndexTransactions = false
+
+	toAddr := vm.Format(ids.NewShortID([20]byte{2}).Bytes())
+	sendReply := SendReply{}
+	if err := s.Send(nil, &SendArgs{
+		Username: testUsername,
+		Password: testPassword,
+		Amount:   10,
+		AssetID:  "asset1",
+		To:       toAddr,
+	}, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	acceptPendingTxs(t, vm)
+
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: toAddr}, &GetAddressTxsReply{}); err != errTxsNotIndexed {
+		t.Fatalf("Should have errored because txs aren't indexed")
+	}
+	addrID := ids.NewID(hashing.ComputeHash256Array(ids.NewShortID([20]byte{2}).Bytes()))
+	if count, err := vm.state.AddressTxCount(addrID); err != nil {
+		t.Fatal(err)
+	} else if count != 0 {
+		t.Fatalf("The accepted tx shouldn't have been indexed")
+	}
+}
+
+func TestServiceGetUTXOsPagination(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	all := GetUTXOsReply{}
+	if err := s.GetUTXOs(nil, &GetUTXOsArgs{Addresses: []string{addr}}, &all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all.UTXOs) < 3 {
+		t.Fatalf("Expected the genesis to fund at least %d utxos, but found %d", 3, len(all.UTXOs))
+	}
+	if all.More {
+		t.Fatalf("Should have reported that all utxos were returned")
+	}
+
+	fetched := [][]byte{}
+	args := GetUTXOsArgs{
+		Addresses: []string{addr},
+		Limit:     2,
+		Encoding:  formatting.HexEncoding,
+	}
+	for {
+		page := GetUTXOsReply{}
+		if err := s.GetUTXOs(nil, &args, &page); err != nil {
+			t.Fatal(err)
+		}
+		for _, utxo := range page.UTXOs {
+			utxoBytes, err := formatting.HexEncoding.Decode(utxo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fetched = append(fetched, utxoBytes)
+		}
+		if !page.More {
+			break
+		}
+		args.StartIndex = page.EndIndex
+	}
+
+	if len(fetched) != len(all.UTXOs) {
+		t.Fatalf("Expected %d paginated utxos, but found %d", len(all.UTXOs), len(fetched))
+	}
+	for i, utxo := range all.UTXOs {
+		utxoBytes, err := formatting.CB58Encoding.Decode(utxo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(utxoBytes, fetched[i]) {
+			t.Fatalf("Paginated utxo %d doesn't match", i)
+		}
+	}
+}
+
+func TestServiceGetAllBalances(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, s := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	reply := GetAllBalancesReply{}
+	if err := s.GetAllBalances(nil, &GetAllBalancesArgs{Address: addr}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Balances) != 1 {
+		t.Fatalf("Expected %d balance(s), but found %d", 1, len(reply.Balances))
+	}
+
+	asset1, err := vm.Lookup("asset1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := reply.Balances[0]; !balance.AssetID.Equals(asset1) || balance.Balance != 300000 {
+		t.Fatalf("Wrong balance. Expected %d of %s, got %d of %s", 300000, asset1, balance.Balance, balance.AssetID)
+	}
+}
@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// sharedCodec serializes the UTXOs that are placed into shared memory. Only the
+// secp256k1fx types are registered, so the encoding doesn't depend on the fxs
+// that either chain is running.
+var sharedCodec codec.Codec
+
+func init() {
+	sharedCodec = codec.NewDefault()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		sharedCodec.RegisterType(&secp256k1fx.MintOutput{}),
+		sharedCodec.RegisterType(&secp256k1fx.TransferOutput{}),
+		sharedCodec.RegisterType(&secp256k1fx.MintInput{}),
+		sharedCodec.RegisterType(&secp256k1fx.TransferInput{}),
+		sharedCodec.RegisterType(&secp256k1fx.Credential{}),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// SharedState is the set of UTXOs that one chain has exported to another chain
+// through shared memory.
+type SharedState struct {
+	state *prefixedState
+}
+
+// NewSharedState returns the UTXOs in the shared database [db] that can be
+// imported by the [destination] chain. The UTXOs are keyed the same way
+// regardless of how [db] is wrapped, so a versiondb can be used to batch
+// changes.
+func NewSharedState(db database.Database, destination ids.ID) *SharedState {
+	return &SharedState{state: &prefixedState{
+		state: &state{
+			c:     &cache.LRU{Size: stateCacheSize},
+			codec: sharedCodec,
+			db:    prefixdb.NewNested(destination.Bytes(), db),
+		},
+
+		utxo:  &cache.LRU{Size: idCacheSize},
+		funds: &cache.LRU{Size: idCacheSize},
+	}}
+}
+
+// UTXO attempts to load a utxo from shared memory.
+func (s *SharedState) UTXO(id ids.ID) (*ava.UTXO, error) { return s.state.UTXO(id) }
+
+// Funds returns the IDs of the UTXOs that reference the address whose hash is
+// [id].
+func (s *SharedState) Funds(id ids.ID) ([]ids.ID, error) { return s.state.Funds(id) }
+
+// FundUTXO adds the provided utxo to shared memory.
+func (s *SharedState) FundUTXO(utxo *ava.UTXO) error { return s.state.FundUTXO(utxo) }
+
+// SpendUTXO removes the provided utxo from shared memory.
+func (s *SharedState) SpendUTXO(id ids.ID) error { return s.state.SpendUTXO(id) }
@@ -7,8 +7,11 @@ import (
 	"errors"
 
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/codec"
 )
 
 var (
@@ -18,8 +21,9 @@ var (
 // state is a thin wrapper around a database to provide, caching, serialization,
 // and de-serialization.
 type state struct {
-	c  cache.Cacher
-	vm *VM
+	c     cache.Cacher
+	codec codec.Codec
+	db    database.Database
 }
 
 // Tx attempts to load a transaction from storage.
@@ -31,14 +35,14 @@ func (s *state) Tx(id ids.ID) (*Tx, error) {
 		return nil, errCacheTypeMismatch
 	}
 
-	bytes, err := s.vm.db.Get(id.Bytes())
+	bytes, err := s.db.Get(id.Bytes())
 	if err != nil {
 		return nil, err
 	}
 
 	// The key was in the database
 	tx := &Tx{}
-	if err := s.vm.codec.Unmarshal(bytes, tx); err != nil {
+	if err := s.codec.Unmarshal(bytes, tx); err != nil {
 		return nil, err
 	}
 	tx.Initialize(bytes)
@@ -51,30 +55,30 @@ func (s *state) Tx(id ids.ID) (*Tx, error) {
 func (s *state) SetTx(id ids.ID, tx *Tx) error {
 	if tx == nil {
 		s.c.Evict(id)
-		return s.vm.db.Delete(id.Bytes())
+		return s.db.Delete(id.Bytes())
 	}
 
 	s.c.Put(id, tx)
-	return s.vm.db.Put(id.Bytes(), tx.Bytes())
+	return s.db.Put(id.Bytes(), tx.Bytes())
 }
 
 // UTXO attempts to load a utxo from storage.
-func (s *state) UTXO(id ids.ID) (*UTXO, error) {
+func (s *state) UTXO(id ids.ID) (*ava.UTXO, error) {
 	if utxoIntf, found := s.c.Get(id); found {
-		if utxo, ok := utxoIntf.(*UTXO); ok {
+		if utxo, ok := utxoIntf.(*ava.UTXO); ok {
 			return utxo, nil
 		}
 		return nil, errCacheTypeMismatch
 	}
 
-	bytes, err := s.vm.db.Get(id.Bytes())
+	bytes, err := s.db.Get(id.Bytes())
 	if err != nil {
 		return nil, err
 	}
 
 	// The key was in the database
-	utxo := &UTXO{}
-	if err := s.vm.codec.Unmarshal(bytes, utxo); err != nil {
+	utxo := &ava.UTXO{}
+	if err := s.codec.Unmarshal(bytes, utxo); err != nil {
 		return nil, err
 	}
 
@@ -83,19 +87,19 @@ func (s *state) UTXO(id ids.ID) (*UTXO, error) {
 }
 
 // SetUTXO saves the provided utxo to storage.
-func (s *state) SetUTXO(id ids.ID, utxo *UTXO) error {
+func (s *state) SetUTXO(id ids.ID, utxo *ava.UTXO) error {
 	if utxo == nil {
 		s.c.Evict(id)
-		return s.vm.db.Delete(id.Bytes())
+		return s.db.Delete(id.Bytes())
 	}
 
-	bytes, err := s.vm.codec.Marshal(utxo)
+	bytes, err := s.codec.Marshal(utxo)
 	if err != nil {
 		return err
 	}
 
 	s.c.Put(id, utxo)
-	return s.vm.db.Put(id.Bytes(), bytes)
+	return s.db.Put(id.Bytes(), bytes)
 }
 
 // Status returns a status from storage.
@@ -107,13 +111,13 @@ func (s *state) Status(id ids.ID) (choices.Status, error) {
 		return choices.Unknown, errCacheTypeMismatch
 	}
 
-	bytes, err := s.vm.db.Get(id.Bytes())
+	bytes, err := s.db.Get(id.Bytes())
 	if err != nil {
 		return choices.Unknown, err
 	}
 
 	var status choices.Status
-	s.vm.codec.Unmarshal(bytes, &status)
+	s.codec.Unmarshal(bytes, &status)
 
 	s.c.Put(id, status)
 	return status, nil
@@ -123,16 +127,16 @@ func (s *state) Status(id ids.ID) (choices.Status, error) {
 func (s *state) SetStatus(id ids.ID, status choices.Status) error {
 	if status == choices.Unknown {
 		s.c.Evict(id)
-		return s.vm.db.Delete(id.Bytes())
+		return s.db.Delete(id.Bytes())
 	}
 
 	s.c.Put(id, status)
 
-	bytes, err := s.vm.codec.Marshal(status)
+	bytes, err := s.codec.Marshal(status)
 	if err != nil {
 		return err
 	}
-	return s.vm.db.Put(id.Bytes(), bytes)
+	return s.db.Put(id.Bytes(), bytes)
 }
 
 // IDs returns a slice of IDs from storage
@@ -144,13 +148,13 @@ func (s *state) IDs(id ids.ID) ([]ids.ID, error) {
 		return nil, errCacheTypeMismatch
 	}
 
-	bytes, err := s.vm.db.Get(id.Bytes())
+	bytes, err := s.db.Get(id.Bytes())
 	if err != nil {
 		return nil, err
 	}
 
 	idSlice := []ids.ID(nil)
-	if err := s.vm.codec.Unmarshal(bytes, &idSlice); err != nil {
+	if err := s.codec.Unmarshal(bytes, &idSlice); err != nil {
 		return nil, err
 	}
 
@@ -162,15 +166,83 @@ func (s *state) IDs(id ids.ID) ([]ids.ID, error) {
 func (s *state) SetIDs(id ids.ID, idSlice []ids.ID) error {
 	if len(idSlice) == 0 {
 		s.c.Evict(id)
-		return s.vm.db.Delete(id.Bytes())
+		return s.db.Delete(id.Bytes())
 	}
 
 	s.c.Put(id, idSlice)
 
-	bytes, err := s.vm.codec.Marshal(idSlice)
+	bytes, err := s.codec.Marshal(idSlice)
 	if err != nil {
 		return err
 	}
 
-	return s.vm.db.Put(id.Bytes(), bytes)
+	return s.db.Put(id.Bytes(), bytes)
+}
+
+// ID returns an ID from storage
+func (s *state) ID(id ids.ID) (ids.ID, error) {
+	if idIntf, found := s.c.Get(id); found {
+		if id, ok := idIntf.(ids.ID); ok {
+			return id, nil
+		}
+		return ids.ID{}, errCacheTypeMismatch
+	}
+
+	bytes, err := s.db.Get(id.Bytes())
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	value, err := ids.ToID(bytes)
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	s.c.Put(id, value)
+	return value, nil
+}
+
+// SetID saves an ID to the database
+func (s *state) SetID(id ids.ID, value ids.ID) error {
+	if value.IsZero() {
+		s.c.Evict(id)
+		return s.db.Delete(id.Bytes())
+	}
+
+	s.c.Put(id, value)
+	return s.db.Put(id.Bytes(), value.Bytes())
+}
+
+// Uint64 returns a uint64 from storage
+func (s *state) Uint64(id ids.ID) (uint64, error) {
+	if valueIntf, found := s.c.Get(id); found {
+		if value, ok := valueIntf.(uint64); ok {
+			return value, nil
+		}
+		return 0, errCacheTypeMismatch
+	}
+
+	bytes, err := s.db.Get(id.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var value uint64
+	if err := s.codec.Unmarshal(bytes, &value); err != nil {
+		return 0, err
+	}
+
+	s.c.Put(id, value)
+	return value, nil
+}
+
+// SetUint64 saves a uint64 to the database
+func (s *state) SetUint64(id ids.ID, value uint64) error {
+	s.c.Put(id, value)
+
+	bytes, err := s.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(id.Bytes(), bytes)
 }
@@ -6,10 +6,12 @@ package avm
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 
@@ -71,8 +73,11 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 			switch assetType {
 			case "fixedCap":
 				initialState := &InitialState{
-					FxID: 0, // TODO: Should lookup secp256k1fx FxID
+					FxID: 0, // The secp256k1fx is the only fx in the genesis codec
 				}
+				// The supply of the asset must fit in a uint64, so balances of
+				// the asset can't overflow
+				supply := uint64(0)
 				for _, state := range initialStates {
 					b, err := json.Marshal(state)
 					if err != nil {
@@ -90,8 +95,12 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 					if err != nil {
 						return err
 					}
+					if supply, err = math.Add64(supply, uint64(holder.Amount)); err != nil {
+						return fmt.Errorf("supply of asset %s overflows: %w", assetAlias, err)
+					}
 					initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-						Amt: uint64(holder.Amount),
+						Amt:      uint64(holder.Amount),
+						Locktime: uint64(holder.Locktime),
 						OutputOwners: secp256k1fx.OutputOwners{
 							Threshold: 1,
 							Addrs:     []ids.ShortID{addr},
@@ -102,7 +111,7 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 				asset.States = append(asset.States, initialState)
 			case "variableCap":
 				initialState := &InitialState{
-					FxID: 0, // TODO: Should lookup secp256k1fx FxID
+					FxID: 0, // The secp256k1fx is the only fx in the genesis codec
 				}
 				for _, state := range initialStates {
 					b, err := json.Marshal(state)
@@ -116,7 +125,7 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 
 					out := &secp256k1fx.MintOutput{
 						OutputOwners: secp256k1fx.OutputOwners{
-							Threshold: 1,
+							Threshold: uint32(owners.Threshold),
 						},
 					}
 					for _, address := range owners.Minters {
@@ -131,6 +140,9 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 						out.Addrs = append(out.Addrs, addr)
 					}
 					out.Sort()
+					if err := out.Verify(); err != nil {
+						return err
+					}
 
 					initialState.Outs = append(initialState.Outs, out)
 				}
@@ -4,9 +4,13 @@
 package avm
 
 import (
+	"math"
 	"testing"
 
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 func TestBuildGenesis(t *testing.T) {
@@ -80,7 +84,7 @@ func TestBuildGenesis(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expected := "1112YAVd1YsJ7JBDMQssciuuu9ySgebznWfmfT8JSw5vUKERtP4WGyitE7z38J8tExNmvK2kuwHsUP3erfcncXBWmJkdnd9nDJoj9tCiQHJmW1pstNQn3zXHdTnw6KJcG8Ro36ahknQkuy9ZSXgnZtpFhqUuwSd7mPj8vzZcqJMXLXorCBfvhwypTbZKogM9tUshyUfngfkg256ZsoU2ufMjhTG14PBBrgJkXD2F38uVSXWvYbubMVWDZbDnUzbyD3Azrs2Hydf8Paio6aNjwfwc1py61oXS5ehC55wiYbKpfzwE4px3bfYBu9yV6rvhivksB56vop9LEo8Pdo71tFAMkhR5toZmYcqRKyLXAnYqonUgmPsyxNwU22as8oscT5dj3Qxy1jsg6bEp6GwQepNqsWufGYx6Hiby2r5hyRZeYdk6xsXMPGBSBWUXhKX3ReTxBnjcrVE2Zc3G9eMvRho1tKzt7ppkutpcQemdDy2dxGryMqaFmPJaTaqcH2vB197KgVFbPgmHZY3ufUdfpVzzHax365pwCmzQD2PQh8hCqEP7rfV5e8uXKQiSynngoNDM4ak145zTpcUaX8htMGinfs45aKQvo5WHcD6ccRnHzc7dyXN8xJRnMznsuRN7D6k66DdbfDYhc2NbVUgXRAF4wSNTtsuZGxCGTEjQyYaoUoJowGXvnxmXAWHvLyMJswNizBeYgw1agRg5qB4AEKX96BFXhJq3MbsBRiypLR6nSuZgPFhCrLdBtstxEC2SPQNuUVWW9Qy68dDWQ3Fxx95n1pnjVru9wDJFoemg2imXRR"
+	expected := "1112YAVd1YsJ7JBDMQssciuuu9ySgebznWfmfT8JSw5vUKERtP4WGyitE7z38J8tExNmvK2kuwHsUP3erfcncXBWmJkdnd9nDJoj9tCiQHJmW1pstNQn3zXHdTnw6KJcG8Ro36ahknQkuy9ZSXgnZtpFhqUuwSd7mPj8vzZcqJMXLXorCBfvhwypTbZKogM9tUshyUfngfkg256ZsoU2ufMjhTG14PBBrgJkXD2F38uVSXWvYbubMVWDZbDnUzbyD3Azrs2Hydf8Paio6aNjwfwc1py61oXS5ehC55wiYbKpfzwE4px3bfYBu9yV6rvhivksB56vop9LEo8Pdo71tFAMkhR5toZmYcqRKyLXAnYqonUgmPsyxNwU22as8oscT5dj3Qxy1jsg6bEp6GwQepNqsWufGYx6Hiby2r5hyRZeYdk6xsXMPGBSBWUXhKX3ReTxBnjcrVE2Zc3G9eMvRho1tKzt7ppkutpcQemdDy2dxGryMqaFmPJaTaqcH2vB197KgVFbPgmHZY3ufUdfpVzzHax365pwCmzQD2PQh8hCqEP7rfV5e8uXKQiSynngoNDM4ak147HxYf5FwsviJzsGUMzBPfUDGyexqWjM1BWYyJSyEdzsZya67bav5sRGXA41sHyGqngwD4H4rSrc3nzdfN2dknzUXVTFD931nsdoabtehqiz4fSyXXgw1ECL4KEyTMqs3L8E6csc6ctQVn44k6Vm4ao86jFBzLqgULg61RvBqyK9TH46tGHKL2A2FD4arumfP5mxk7GQDFWt51o8mWJyw3oY92PyGjwfsrZEXx"
 
 	cb58 := formatting.CB58{}
 	if err := cb58.FromString(expected); err != nil {
@@ -96,3 +100,105 @@ func TestBuildGenesis(t *testing.T) {
 		)
 	}
 }
+
+func TestBuildGenesisLockedAndMultisig(t *testing.T) {
+	ss := StaticService{}
+
+	addr0 := keys[0].PublicKey().Address()
+	addr1 := keys[1].PublicKey().Address()
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset": AssetDefinition{
+			Name:   "myVestedAsset",
+			Symbol: "MVA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:   1000,
+						Address:  addr0.String(),
+						Locktime: 12345,
+					},
+				},
+				"variableCap": []interface{}{
+					Owners{
+						Threshold: 2,
+						Minters:   []string{addr0.String(), addr1.String()},
+					},
+				},
+			},
+		},
+	}}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+
+	genesis := Genesis{}
+	if err := c.Unmarshal(reply.Bytes.Bytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	if len(genesis.Txs) != 1 || len(genesis.Txs[0].States) != 2 {
+		t.Fatalf("Genesis should have one asset with two initial states")
+	}
+	for _, state := range genesis.Txs[0].States {
+		switch out := state.Outs[0].(type) {
+		case *secp256k1fx.TransferOutput:
+			if out.Locktime != 12345 {
+				t.Fatalf("Holder should be locked until %d but is locked until %d", 12345, out.Locktime)
+			}
+		case *secp256k1fx.MintOutput:
+			if out.Threshold != 2 {
+				t.Fatalf("Minters should have a threshold of %d but have %d", 2, out.Threshold)
+			}
+		default:
+			t.Fatalf("Unexpected output type %T", out)
+		}
+	}
+
+	args.GenesisData["asset"].InitialState["variableCap"] = []interface{}{
+		Owners{
+			Threshold: 3,
+			Minters:   []string{addr0.String(), addr1.String()},
+		},
+	}
+	if err := ss.BuildGenesis(nil, &args, &reply); err == nil {
+		t.Fatalf("Should have errored because the minters can't meet the threshold")
+	}
+}
+
+func TestBuildGenesisSupplyOverflow(t *testing.T) {
+	ss := StaticService{}
+
+	addr := keys[0].PublicKey().Address()
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset": AssetDefinition{
+			Name:   "myOverflowingAsset",
+			Symbol: "MOA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:  json.Uint64(math.MaxUint64),
+						Address: addr.String(),
+					},
+					Holder{
+						Amount:  1,
+						Address: addr.String(),
+					},
+				},
+			},
+		},
+	}}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err == nil {
+		t.Fatalf("Should have errored because the supply of the asset overflows")
+	}
+}
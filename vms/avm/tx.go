@@ -6,14 +6,21 @@ package avm
 import (
 	"errors"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
-
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 var (
 	errWrongNumberOfCredentials = errors.New("should have the same number of credentials as inputs")
+	errInsufficientFee          = errors.New("tx doesn't burn enough AVA to pay the tx fee")
 )
 
 // UnsignedTx ...
@@ -24,14 +31,15 @@ type UnsignedTx interface {
 
 	NetworkID() uint32
 	ChainID() ids.ID
-	Outputs() []*TransferableOutput
-	Inputs() []*TransferableInput
+	Outputs() []*ava.TransferableOutput
+	Inputs() []*ava.TransferableInput
 
 	AssetIDs() ids.Set
-	InputUTXOs() []*UTXOID
-	UTXOs() []*UTXO
+	InputUTXOs() []*ava.UTXOID
+	UTXOs() []*ava.UTXO
 	SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error
 	SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error
+	ExecuteWithSideEffects(vm *VM, batch database.Batch) error
 }
 
 // Tx is the core operation that can be performed. The tx uses the UTXO model.
@@ -79,5 +87,85 @@ func (t *Tx) SemanticVerify(vm *VM, uTx *UniqueTx) error {
 		return errNilTx
 	}
 
+	if err := t.verifyFee(vm); err != nil {
+		return err
+	}
+
+	t.recoverSigners(vm, uTx)
 	return t.UnsignedTx.SemanticVerify(vm, uTx, t.Creds)
 }
+
+// verifyFee verifies that this transaction burns at least the fee [vm]
+// charges for it. Txs that create an asset are charged the creation fee.
+func (t *Tx) verifyFee(vm *VM) error {
+	fee := vm.txFee
+	if _, ok := t.UnsignedTx.(*CreateAssetTx); ok {
+		fee = vm.creationTxFee
+	}
+	if fee == 0 {
+		return nil
+	}
+	burned, err := Burned(t.UnsignedTx, vm.ava)
+	if err != nil {
+		return err
+	}
+	if burned < fee {
+		return errInsufficientFee
+	}
+	return nil
+}
+
+// recoverSigners gives the credentials of this transaction to the feature
+// extensions that own them, so those that can recover the signers of their
+// credentials in a batch do so. Credentials of unknown feature extensions are
+// skipped, as they fail verification anyway.
+func (t *Tx) recoverSigners(vm *VM, uTx *UniqueTx) {
+	fxCreds := make(map[int][]interface{})
+	for _, cred := range t.Creds {
+		if fxIndex, err := vm.getFx(cred.Cred); err == nil {
+			fxCreds[fxIndex] = append(fxCreds[fxIndex], cred.Cred)
+		}
+	}
+	for fxIndex, creds := range fxCreds {
+		if fx, ok := vm.fxs[fxIndex].Fx.(FxSignerRecoverer); ok {
+			fx.RecoverSigners(uTx, creds)
+		}
+	}
+}
+
+// SignSECP256K1Fx appends a secp256k1fx credential for each of the provided
+// sets of signers, in order.
+func (t *Tx) SignSECP256K1Fx(c codec.Codec, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	return t.sign(c, signers, func(sigs [][crypto.SECP256K1RSigLen]byte) verify.Verifiable {
+		return &secp256k1fx.Credential{Sigs: sigs}
+	})
+}
+
+// SignNFTFx appends an nftfx credential for each of the provided sets of
+// signers, in order.
+func (t *Tx) SignNFTFx(c codec.Codec, signers [][]*crypto.PrivateKeySECP256K1R) error {
+	return t.sign(c, signers, func(sigs [][crypto.SECP256K1RSigLen]byte) verify.Verifiable {
+		return &nftfx.Credential{Credential: secp256k1fx.Credential{Sigs: sigs}}
+	})
+}
+
+func (t *Tx) sign(c codec.Codec, signers [][]*crypto.PrivateKeySECP256K1R, newCred func([][crypto.SECP256K1RSigLen]byte) verify.Verifiable) error {
+	unsignedBytes, err := c.Marshal(&t.UnsignedTx)
+	if err != nil {
+		return err
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	for _, keys := range signers {
+		sigs := make([][crypto.SECP256K1RSigLen]byte, len(keys))
+		for i, key := range keys {
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return err
+			}
+			copy(sigs[i][:], sig)
+		}
+		t.Creds = append(t.Creds, &Credential{Cred: newCred(sigs)})
+	}
+	return nil
+}
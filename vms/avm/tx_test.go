@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
@@ -48,13 +49,13 @@ func TestTxInvalidCredential(t *testing.T) {
 		UnsignedTx: &OperationTx{BaseTx: BaseTx{
 			NetID: networkID,
 			BCID:  chainID,
-			Ins: []*TransferableInput{
-				&TransferableInput{
-					UTXOID: UTXOID{
+			Ins: []*ava.TransferableInput{
+				&ava.TransferableInput{
+					UTXOID: ava.UTXOID{
 						TxID:        ids.Empty,
 						OutputIndex: 0,
 					},
-					Asset: Asset{
+					Asset: ava.Asset{
 						ID: asset,
 					},
 					In: &secp256k1fx.TransferInput{
@@ -102,13 +103,13 @@ func TestTxInvalidUnsignedTx(t *testing.T) {
 		UnsignedTx: &OperationTx{BaseTx: BaseTx{
 			NetID: networkID,
 			BCID:  chainID,
-			Ins: []*TransferableInput{
-				&TransferableInput{
-					UTXOID: UTXOID{
+			Ins: []*ava.TransferableInput{
+				&ava.TransferableInput{
+					UTXOID: ava.UTXOID{
 						TxID:        ids.Empty,
 						OutputIndex: 0,
 					},
-					Asset: Asset{
+					Asset: ava.Asset{
 						ID: asset,
 					},
 					In: &secp256k1fx.TransferInput{
@@ -120,12 +121,12 @@ func TestTxInvalidUnsignedTx(t *testing.T) {
 						},
 					},
 				},
-				&TransferableInput{
-					UTXOID: UTXOID{
+				&ava.TransferableInput{
+					UTXOID: ava.UTXOID{
 						TxID:        ids.Empty,
 						OutputIndex: 0,
 					},
-					Asset: Asset{
+					Asset: ava.Asset{
 						ID: asset,
 					},
 					In: &secp256k1fx.TransferInput{
@@ -177,13 +178,13 @@ func TestTxInvalidNumberOfCredentials(t *testing.T) {
 			BaseTx: BaseTx{
 				NetID: networkID,
 				BCID:  chainID,
-				Ins: []*TransferableInput{
-					&TransferableInput{
-						UTXOID: UTXOID{
+				Ins: []*ava.TransferableInput{
+					&ava.TransferableInput{
+						UTXOID: ava.UTXOID{
 							TxID:        ids.Empty,
 							OutputIndex: 0,
 						},
-						Asset: Asset{
+						Asset: ava.Asset{
 							ID: asset,
 						},
 						In: &secp256k1fx.TransferInput{
@@ -199,12 +200,12 @@ func TestTxInvalidNumberOfCredentials(t *testing.T) {
 			},
 			Ops: []*Operation{
 				&Operation{
-					Asset: Asset{
+					Asset: ava.Asset{
 						ID: asset,
 					},
 					Ins: []*OperableInput{
 						&OperableInput{
-							UTXOID: UTXOID{
+							UTXOID: ava.UTXOID{
 								TxID:        ids.Empty,
 								OutputIndex: 1,
 							},
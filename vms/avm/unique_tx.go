@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 var (
@@ -32,8 +33,8 @@ type txState struct {
 
 	tx         *Tx
 	inputs     ids.Set
-	inputUTXOs []*UTXOID
-	utxos      []*UTXO
+	inputUTXOs []*ava.UTXOID
+	utxos      []*ava.UTXO
 	deps       []snowstorm.Tx
 
 	status choices.Status
@@ -98,8 +99,27 @@ func (tx *UniqueTx) Accept() {
 		return
 	}
 
+	txID := tx.ID()
+
+	// The utxos whose addresses should reference this tx in the index
+	indexedUTXOs := []*ava.UTXO(nil)
+
 	// Remove spent utxos
-	for _, utxoID := range tx.InputIDs().List() {
+	for _, input := range tx.InputUTXOs() {
+		if input.Symbolic() {
+			// If the UTXO is symbolic, it can't be spent from this chain's
+			// state
+			continue
+		}
+
+		utxoID := input.InputID()
+		utxo, err := tx.vm.state.UTXO(utxoID)
+		if err != nil {
+			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
+			return
+		}
+		indexedUTXOs = append(indexedUTXOs, utxo)
+
 		if err := tx.vm.state.SpendUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return
@@ -108,20 +128,39 @@ func (tx *UniqueTx) Accept() {
 
 	// Add new utxos
 	for _, utxo := range tx.UTXOs() {
+		indexedUTXOs = append(indexedUTXOs, utxo)
+
 		if err := tx.vm.state.FundUTXO(utxo); err != nil {
 			tx.vm.ctx.Log.Error("Failed to fund utxo %s due to %s", utxoID, err)
 			return
 		}
 	}
 
-	txID := tx.ID()
+	if tx.vm.config.IndexTransactions {
+		if err := tx.vm.state.IndexTx(txID, indexedUTXOs); err != nil {
+			tx.vm.ctx.Log.Error("Failed to index tx %s due to %s", txID, err)
+			return
+		}
+	}
+
 	tx.vm.ctx.Log.Verbo("Accepting Tx: %s", txID)
 
-	if err := tx.vm.db.Commit(); err != nil {
+	commitBatch, err := tx.vm.db.CommitBatch()
+	if err != nil {
+		tx.vm.ctx.Log.Error("Failed to calculate CommitBatch for %s due to %s", tx.txID, err)
+		return
+	}
+
+	// Any changes this tx makes to shared memory are written in the same batch
+	// as the changes to this chain's state
+	if err := tx.t.tx.ExecuteWithSideEffects(tx.vm, commitBatch); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", tx.txID, err)
+		return
 	}
+	tx.vm.db.Abort()
 
 	tx.vm.pubsub.Publish("accepted", txID)
+	tx.vm.decideTx(tx)
 
 	tx.t.deps = nil // Needed to prevent a memory leak
 }
@@ -136,11 +175,19 @@ func (tx *UniqueTx) Reject() {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Debug("Rejecting Tx: %s", txID)
 
+	// Rejected txs are only kept for the retention window. Their status is
+	// kept, so a rejected tx that's parsed again, such as from a vertex that
+	// contains it, is still known to be rejected.
+	if err := tx.vm.pruner.MarkMortal(tx.vm.state.TxKey(txID)); err != nil {
+		tx.vm.ctx.Log.Error("Failed to mark tx %s as mortal due to %s", txID, err)
+	}
+
 	if err := tx.vm.db.Commit(); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit reject %s due to %s", tx.txID, err)
 	}
 
 	tx.vm.pubsub.Publish("rejected", txID)
+	tx.vm.decideTx(tx)
 
 	tx.t.deps = nil // Needed to prevent a memory leak
 }
@@ -160,6 +207,9 @@ func (tx *UniqueTx) Dependencies() []snowstorm.Tx {
 
 	txIDs := ids.Set{}
 	for _, in := range tx.InputUTXOs() {
+		if in.Symbolic() {
+			continue
+		}
 		txID, _ := in.InputSource()
 		if !txIDs.Contains(txID) {
 			txIDs.Add(txID)
@@ -195,7 +245,7 @@ func (tx *UniqueTx) InputIDs() ids.Set {
 }
 
 // InputUTXOs returns the utxos that will be consumed on tx acceptance
-func (tx *UniqueTx) InputUTXOs() []*UTXOID {
+func (tx *UniqueTx) InputUTXOs() []*ava.UTXOID {
 	tx.refresh()
 	if tx.t.tx == nil || len(tx.t.inputUTXOs) != 0 {
 		return tx.t.inputUTXOs
@@ -205,7 +255,7 @@ func (tx *UniqueTx) InputUTXOs() []*UTXOID {
 }
 
 // UTXOs returns the utxos that will be added to the UTXO set on tx acceptance
-func (tx *UniqueTx) UTXOs() []*UTXO {
+func (tx *UniqueTx) UTXOs() []*ava.UTXO {
 	tx.refresh()
 	if tx.t.tx == nil || len(tx.t.utxos) != 0 {
 		return tx.t.utxos
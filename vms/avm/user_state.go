@@ -4,50 +4,81 @@
 package avm
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/addressbook"
 )
 
-var addresses = ids.Empty
-
-type userState struct{ vm *VM }
+// Before users' keys were kept in their address books, the AVM stored the
+// hashes of a user's addresses under [legacyAddressesKey] in the user's
+// database on this chain, and each key under the hash of its address
+var legacyAddressesKey = ids.Empty.Bytes()
 
-func (s *userState) SetAddresses(db database.Database, addrs []ids.ID) error {
-	bytes, err := s.vm.codec.Marshal(addrs)
+// getUser returns the address book of the user [username]. Keys this chain
+// stored in its own database before keys were kept in address books are moved
+// into the address book first.
+func (vm *VM) getUser(username, password string) (*addressbook.Book, error) {
+	db, err := vm.ctx.Keystore.GetDatabase(username, password)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("problem retrieving user: %w", err)
 	}
-	return db.Put(addresses.Bytes(), bytes)
-}
-
-func (s *userState) Addresses(db database.Database) ([]ids.ID, error) {
-	bytes, err := db.Get(addresses.Bytes())
+	sharedDB, err := vm.ctx.Keystore.GetSharedDatabase(username, password)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("problem retrieving user: %w", err)
 	}
-	addresses := []ids.ID{}
-	if err := s.vm.codec.Unmarshal(bytes, &addresses); err != nil {
-		return nil, err
+	book := addressbook.New(sharedDB)
+	if err := vm.migrateKeys(db, book); err != nil {
+		return nil, fmt.Errorf("problem moving keys to the address book: %w", err)
 	}
-	return addresses, nil
+	return book, nil
 }
 
-func (s *userState) SetKey(db database.Database, sk *crypto.PrivateKeySECP256K1R) error {
-	return db.Put(hashing.ComputeHash256(sk.PublicKey().Address().Bytes()), sk.Bytes())
-}
+// migrateKeys moves the keys stored in [db] the legacy way into [book]
+func (vm *VM) migrateKeys(db database.Database, book *addressbook.Book) error {
+	addrsBytes, err := db.Get(legacyAddressesKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	addrs := []ids.ID{}
+	if err := vm.codec.Unmarshal(addrsBytes, &addrs); err != nil {
+		return err
+	}
 
-func (s *userState) Key(db database.Database, address ids.ID) (*crypto.PrivateKeySECP256K1R, error) {
 	factory := crypto.FactorySECP256K1R{}
-
-	bytes, err := db.Get(address.Bytes())
-	if err != nil {
-		return nil, err
+	batch := db.NewBatch()
+	for _, addr := range addrs {
+		skBytes, err := db.Get(addr.Bytes())
+		if err != nil {
+			return err
+		}
+		sk, err := factory.ToPrivateKey(skBytes)
+		if err != nil {
+			return err
+		}
+		// Keys are put in the book before they're deleted, so that a key is
+		// never lost. Putting a key that's already in the book does nothing,
+		// so an interrupted migration can be repeated.
+		if err := book.Put(sk.(*crypto.PrivateKeySECP256K1R)); err != nil {
+			return err
+		}
+		if err := batch.Delete(addr.Bytes()); err != nil {
+			return err
+		}
 	}
-	sk, err := factory.ToPrivateKey(bytes)
-	if err != nil {
-		return nil, err
+	if err := batch.Delete(legacyAddressesKey); err != nil {
+		return err
 	}
-	return sk.(*crypto.PrivateKeySECP256K1R), nil
+	return batch.Write()
+}
+
+// addressID returns the ID UTXOs owned by [addr] are indexed under
+func addressID(addr ids.ShortID) ids.ID {
+	return ids.NewID(hashing.ComputeHash256Array(addr.Bytes()))
 }
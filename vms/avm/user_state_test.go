@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/addressbook"
+)
+
+func TestLoadUserMigratesLegacyKeys(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm, _ := setupKeystoreVM(t)
+	defer vm.Shutdown()
+
+	// Store keys[1] the way the AVM stored keys before address books
+	db, err := vm.ctx.Keystore.GetDatabase(testUsername, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyAddrs := []ids.ID{addressID(keys[1].PublicKey().Address())}
+	addrsBytes, err := vm.codec.Marshal(legacyAddrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(legacyAddressesKey, addrsBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(legacyAddrs[0].Bytes(), keys[1].Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, kc, err := vm.LoadUser(testUsername, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys[:2] {
+		if _, ok := kc.Get(key.PublicKey().Address()); !ok {
+			t.Fatalf("User should have had the key of %s", key.PublicKey().Address())
+		}
+	}
+	if has, err := db.Has(legacyAddressesKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Legacy addresses should have been deleted")
+	}
+
+	// The key is in the address book the other chains share
+	sharedDB, err := vm.ctx.Keystore.GetSharedDatabase(testUsername, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addressbook.New(sharedDB).Key(keys[1].PublicKey().Address()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,14 +4,14 @@
 package avm
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
@@ -24,8 +24,12 @@ import (
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/components/pruning"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 const (
@@ -35,6 +39,14 @@ const (
 	idCacheSize    = 10000
 	txCacheSize    = 10000
 	addressSep     = "-"
+
+	// maxUTXOsToFetch is the maximum number of UTXOs that will be returned by
+	// a single paginated UTXO request
+	maxUTXOsToFetch = 1024
+
+	// maxAddressTxsToFetch is the maximum number of tx IDs that will be
+	// returned by a single paginated address tx request
+	maxAddressTxsToFetch = 1024
 )
 
 var (
@@ -57,6 +69,8 @@ type VM struct {
 
 	codec codec.Codec
 
+	config Config
+
 	pubsub *cjson.PubSubServer
 
 	// State management
@@ -65,14 +79,32 @@ type VM struct {
 	// Transaction issuing
 	timer        *timer.Timer
 	batchTimeout time.Duration
-	txs          []snowstorm.Tx
+	mempoolSize  int
+	mempool      *mempool
 	toEngine     chan<- common.Message
+	appSender    common.AppSender // Gossips pending txs, if set
 
 	baseDB database.Database
 	db     *versiondb.Database
+	pruner *pruning.Pruner
 
 	typeToFxIndex map[reflect.Type]int
 	fxs           []*parsedFx
+
+	// The asset that can be atomically moved to and from the platform chain
+	// and the C-Chain
+	ava ids.ID
+
+	// The ID of the platform chain
+	platform ids.ID
+
+	// The ID of the C-Chain. If empty, AVA can't be moved to or from it.
+	evm ids.ID
+
+	// AVA burned by each transaction, in nAVA. Transactions that create an
+	// asset burn [creationTxFee], others burn [txFee].
+	txFee         uint64
+	creationTxFee uint64
 }
 
 type codecRegistry struct {
@@ -86,8 +118,16 @@ func (cr *codecRegistry) RegisterType(val interface{}) error {
 	cr.typeToFxIndex[valType] = cr.index
 	return cr.codec.RegisterType(val)
 }
-func (cr *codecRegistry) Marshal(val interface{}) ([]byte, error)   { return cr.codec.Marshal(val) }
-func (cr *codecRegistry) Unmarshal(b []byte, val interface{}) error { return cr.codec.Unmarshal(b, val) }
+func (cr *codecRegistry) Marshal(val interface{}) ([]byte, error) { return cr.codec.Marshal(val) }
+func (cr *codecRegistry) Unmarshal(b []byte, val interface{}) error {
+	return cr.codec.Unmarshal(b, val)
+}
+func (cr *codecRegistry) MarshalCanonicalJSON(val interface{}) ([]byte, error) {
+	return cr.codec.MarshalCanonicalJSON(val)
+}
+func (cr *codecRegistry) UnmarshalCanonicalJSON(b []byte, val interface{}) error {
+	return cr.codec.UnmarshalCanonicalJSON(b, val)
+}
 
 /*
  ******************************************************************************
@@ -100,13 +140,21 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	genesisBytes []byte,
+	configBytes []byte,
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
 	vm.ctx = ctx
 	vm.toEngine = toEngine
+	vm.config = DefaultConfig()
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &vm.config); err != nil {
+			return fmt.Errorf("couldn't parse the chain's config: %w", err)
+		}
+	}
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
+	vm.pruner = pruning.New(ctx, vm.db, ctx.StateRetention)
 	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.Aliaser.Initialize()
 
@@ -117,25 +165,12 @@ func (vm *VM) Initialize(
 		vm.pubsub.Register("accepted"),
 		vm.pubsub.Register("rejected"),
 		vm.pubsub.Register("verified"),
+		vm.pubsub.Register("pending"),
 	)
 	if errs.Errored() {
 		return errs.Err
 	}
 
-	vm.state = &prefixedState{
-		state: &state{
-			c:  &cache.LRU{Size: stateCacheSize},
-			vm: vm,
-		},
-
-		tx:       &cache.LRU{Size: idCacheSize},
-		utxo:     &cache.LRU{Size: idCacheSize},
-		txStatus: &cache.LRU{Size: idCacheSize},
-		funds:    &cache.LRU{Size: idCacheSize},
-
-		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
-	}
-
 	c := codec.NewDefault()
 	c.RegisterType(&BaseTx{})
 	c.RegisterType(&CreateAssetTx{})
@@ -164,8 +199,34 @@ func (vm *VM) Initialize(
 		}
 	}
 
+	// The atomic txs and memo txs are registered after the fxs so that the
+	// type IDs of the fx types are unchanged
+	errs.Add(
+		c.RegisterType(&ImportTx{}),
+		c.RegisterType(&ExportTx{}),
+		c.RegisterType(&MemoTx{}),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+
 	vm.codec = c
 
+	vm.state = &prefixedState{
+		state: &state{
+			c:     &cache.LRU{Size: stateCacheSize},
+			codec: vm.codec,
+			db:    vm.db,
+		},
+
+		tx:       &cache.LRU{Size: idCacheSize},
+		utxo:     &cache.LRU{Size: idCacheSize},
+		txStatus: &cache.LRU{Size: idCacheSize},
+		funds:    &cache.LRU{Size: idCacheSize},
+
+		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
+	}
+
 	if err := vm.initAliases(genesisBytes); err != nil {
 		return err
 	}
@@ -183,7 +244,9 @@ func (vm *VM) Initialize(
 		vm.FlushTxs()
 	})
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
+	vm.pruner.Start()
 	vm.batchTimeout = batchTimeout
+	vm.mempool = newMempool(vm.mempoolSize)
 
 	return vm.db.Commit()
 }
@@ -191,6 +254,7 @@ func (vm *VM) Initialize(
 // Shutdown implements the avalanche.DAGVM interface
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	vm.pruner.Stop()
 	if err := vm.baseDB.Close(); err != nil {
 		vm.ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -198,37 +262,29 @@ func (vm *VM) Shutdown() {
 
 // CreateHandlers implements the avalanche.DAGVM interface
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
-	rpcServer := rpc.NewServer()
-	codec := cjson.NewCodec()
-	rpcServer.RegisterCodec(codec, "application/json")
-	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	rpcServer.RegisterService(&Service{vm: vm}, "avm") // name this service "avm"
-
-	return map[string]*common.HTTPHandler{
-		"":        &common.HTTPHandler{Handler: rpcServer},
-		"/pubsub": &common.HTTPHandler{LockOptions: common.NoLock, Handler: vm.pubsub},
+	builder := core.NewHandlerBuilder()
+	builder.AddService("", "avm", &Service{vm: vm}, common.WriteLock) // name this service "avm"
+	builder.AddHandler("/pubsub", vm.pubsub, common.NoLock)
+	handlers, err := builder.Build()
+	if err != nil {
+		vm.ctx.Log.Error("creating the AVM's handlers failed with %s", err)
 	}
+	return handlers
 }
 
 // CreateStaticHandlers implements the avalanche.DAGVM interface
 func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := cjson.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&StaticService{}, "avm") // name this service "avm"
-	return map[string]*common.HTTPHandler{
-		"": &common.HTTPHandler{LockOptions: common.WriteLock, Handler: newServer},
-	}
+	builder := core.NewHandlerBuilder()
+	builder.AddService("", "avm", &StaticService{}, common.WriteLock) // name this service "avm"
+	handlers, _ := builder.Build()
+	return handlers
 }
 
 // PendingTxs implements the avalanche.DAGVM interface
 func (vm *VM) PendingTxs() []snowstorm.Tx {
 	vm.timer.Cancel()
 
-	txs := vm.txs
-	vm.txs = nil
-	return txs
+	return vm.mempool.Flush()
 }
 
 // ParseTx implements the avalanche.DAGVM interface
@@ -260,20 +316,25 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 	if err := tx.Verify(); err != nil {
 		return ids.ID{}, err
 	}
-	vm.issueTx(tx)
+	if err := vm.issueTx(tx); err != nil {
+		return ids.ID{}, err
+	}
+	// This node may not issue the next vertex, so other validators are told
+	// about the tx too
+	vm.gossipTx(tx)
 	return tx.ID(), nil
 }
 
 // GetUTXOs returns the utxos that at least one of the provided addresses is
 // referenced in.
-func (vm *VM) GetUTXOs(addrs ids.Set) ([]*UTXO, error) {
+func (vm *VM) GetUTXOs(addrs ids.Set) ([]*ava.UTXO, error) {
 	utxoIDs := ids.Set{}
 	for _, addr := range addrs.List() {
 		utxos, _ := vm.state.Funds(addr)
 		utxoIDs.Add(utxos...)
 	}
 
-	utxos := []*UTXO{}
+	utxos := []*ava.UTXO{}
 	for _, utxoID := range utxoIDs.List() {
 		utxo, err := vm.state.UTXO(utxoID)
 		if err != nil {
@@ -284,6 +345,145 @@ func (vm *VM) GetUTXOs(addrs ids.Set) ([]*UTXO, error) {
 	return utxos, nil
 }
 
+// GetAtomicUTXOs returns the UTXOs referenced by [addrs] that the chain
+// [sourceChain] has exported to this chain and that haven't been imported yet
+func (vm *VM) GetAtomicUTXOs(sourceChain ids.ID, addrs ids.Set) ([]*ava.UTXO, error) {
+	if vm.ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(sourceChain)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(sourceChain)
+
+	state := NewSharedState(smDB, vm.ctx.ChainID)
+
+	utxoIDs := ids.Set{}
+	for _, addr := range addrs.List() {
+		utxos, _ := state.Funds(addr)
+		utxoIDs.Add(utxos...)
+	}
+
+	utxos := []*ava.UTXO{}
+	for _, utxoID := range utxoIDs.List() {
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// GetPaginatedUTXOs returns at most [limit] UTXOs referenced by [addrs].
+// Addresses and the UTXOs referenced by each address are iterated in sorted
+// order. If [startAddr] is non-zero, the iteration resumes after the UTXO
+// [startUTXOID] of the address [startAddr]. The returned address and UTXO ID
+// form the cursor that should be passed in to fetch the next page. The returned
+// bool is true if the iteration stopped before every UTXO was visited. A UTXO
+// referenced by multiple addresses may be returned on more than one page.
+func (vm *VM) GetPaginatedUTXOs(addrs ids.Set, startAddr, startUTXOID ids.ID, limit int) ([]*ava.UTXO, ids.ID, ids.ID, bool, error) {
+	return paginateUTXOs(vm.state, addrs, startAddr, startUTXOID, limit)
+}
+
+// GetPaginatedAtomicUTXOs is GetPaginatedUTXOs over the UTXOs that the chain
+// [sourceChain] has exported to this chain and that haven't been imported yet
+func (vm *VM) GetPaginatedAtomicUTXOs(sourceChain ids.ID, addrs ids.Set, startAddr, startUTXOID ids.ID, limit int) ([]*ava.UTXO, ids.ID, ids.ID, bool, error) {
+	if vm.ctx.SharedMemory == nil {
+		return nil, ids.ID{}, ids.ID{}, false, errNoSharedMemory
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(sourceChain)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(sourceChain)
+
+	return paginateUTXOs(NewSharedState(smDB, vm.ctx.ChainID), addrs, startAddr, startUTXOID, limit)
+}
+
+// utxoSource is a set of UTXOs indexed by the addresses they reference
+type utxoSource interface {
+	Funds(id ids.ID) ([]ids.ID, error)
+	UTXO(id ids.ID) (*ava.UTXO, error)
+}
+
+// paginateUTXOs implements the pagination of GetPaginatedUTXOs over [source]
+func paginateUTXOs(source utxoSource, addrs ids.Set, startAddr, startUTXOID ids.ID, limit int) ([]*ava.UTXO, ids.ID, ids.ID, bool, error) {
+	if limit <= 0 || limit > maxUTXOsToFetch {
+		limit = maxUTXOsToFetch
+	}
+	if startUTXOID.IsZero() {
+		startUTXOID = ids.Empty
+	}
+
+	addrList := addrs.List()
+	ids.SortIDs(addrList)
+
+	seen := ids.Set{}
+	utxos := []*ava.UTXO{}
+	lastAddr, lastUTXOID := startAddr, startUTXOID
+	for _, addr := range addrList {
+		addrCmp := 1
+		if !startAddr.IsZero() {
+			addrCmp = bytes.Compare(addr.Bytes(), startAddr.Bytes())
+		}
+		if addrCmp < 0 {
+			continue
+		}
+
+		funds, _ := source.Funds(addr)
+		utxoIDs := append([]ids.ID(nil), funds...)
+		ids.SortIDs(utxoIDs)
+
+		for _, utxoID := range utxoIDs {
+			if addrCmp == 0 && bytes.Compare(utxoID.Bytes(), startUTXOID.Bytes()) <= 0 {
+				continue
+			}
+			if len(utxos) >= limit {
+				return utxos, lastAddr, lastUTXOID, true, nil
+			}
+			lastAddr, lastUTXOID = addr, utxoID
+
+			if seen.Contains(utxoID) {
+				continue
+			}
+			seen.Add(utxoID)
+
+			utxo, err := source.UTXO(utxoID)
+			if err != nil {
+				return nil, ids.ID{}, ids.ID{}, false, err
+			}
+			utxos = append(utxos, utxo)
+		}
+	}
+	return utxos, lastAddr, lastUTXOID, false, nil
+}
+
+// LoadUser returns the UTXOs controlled by the keys in the address book of
+// the user [username], along with a keychain containing those keys.
+func (vm *VM) LoadUser(username, password string) ([]*ava.UTXO, *secp256k1fx.Keychain, error) {
+	book, err := vm.getUser(username, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	addresses, err := book.Addresses()
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving addresses: %w", err)
+	}
+
+	addrs := ids.Set{}
+	kc := secp256k1fx.NewKeychain()
+	for _, addr := range addresses {
+		sk, err := book.Key(addr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("problem retrieving private key: %w", err)
+		}
+		kc.Add(sk)
+		addrs.Add(addressID(addr))
+	}
+
+	utxos, err := vm.GetUTXOs(addrs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+	return utxos, kc, nil
+}
+
 /*
  ******************************************************************************
  *********************************** Fx API ***********************************
@@ -296,6 +496,37 @@ func (vm *VM) Clock() *timer.Clock { return &vm.clock }
 // Codec returns a reference to the internal codec of this VM
 func (vm *VM) Codec() codec.Codec { return vm.codec }
 
+/*
+ ******************************************************************************
+ ********************************** Gossip API ********************************
+ ******************************************************************************
+ */
+
+// SetAppSender implements the common.AppVM interface
+func (vm *VM) SetAppSender(sender common.AppSender) { vm.appSender = sender }
+
+// AppGossip implements the common.AppVM interface. The gossiped tx is added to
+// the mempool and gossiped on, unless this VM already knows about it.
+func (vm *VM) AppGossip(nodeID ids.ShortID, msg []byte) error {
+	tx, err := vm.parseTx(msg)
+	if err != nil {
+		return err
+	}
+	txID := tx.ID()
+	if tx.Status() != choices.Processing || vm.mempool.Has(txID) || vm.mempool.Issued(txID) {
+		return nil
+	}
+	if err := tx.Verify(); err != nil {
+		return err
+	}
+	if err := vm.issueTx(tx); err != nil {
+		return err
+	}
+	vm.ctx.Log.Verbo("Added tx %s gossiped by %s to the mempool", txID, nodeID)
+	vm.gossipTx(tx)
+	return nil
+}
+
 /*
  ******************************************************************************
  ********************************** Timer API *********************************
@@ -305,7 +536,7 @@ func (vm *VM) Codec() codec.Codec { return vm.codec }
 // FlushTxs into consensus
 func (vm *VM) FlushTxs() {
 	vm.timer.Cancel()
-	if len(vm.txs) != 0 {
+	if vm.mempool.Ready() != 0 {
 		select {
 		case vm.toEngine <- common.PendingTxs:
 		default:
@@ -384,6 +615,11 @@ func (vm *VM) initState(genesisBytes []byte) error {
 				return err
 			}
 		}
+		if vm.config.IndexTransactions {
+			if err := vm.state.IndexTx(txID, tx.UTXOs()); err != nil {
+				return err
+			}
+		}
 	}
 
 	return vm.state.SetDBInitialized(choices.Processing)
@@ -418,14 +654,88 @@ func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
 	return tx, nil
 }
 
-func (vm *VM) issueTx(tx snowstorm.Tx) {
-	vm.txs = append(vm.txs, tx)
+// issueTx adds the tx to the mempool and announces it on the pending channel.
+// If the mempool is full, the pending tx with the lowest priority is evicted.
+func (vm *VM) issueTx(tx *UniqueTx) error {
+	evicted, err := vm.mempool.Add(tx, vm.burned(tx))
+	if err != nil {
+		return err
+	}
+	if evicted != nil {
+		vm.ctx.Log.Debug("Evicted tx %s from the mempool", evicted.ID())
+	}
+	vm.pubsub.Publish("pending", tx.ID())
+
 	switch {
-	case len(vm.txs) == batchSize:
+	case vm.mempool.Ready() >= batchSize:
 		vm.FlushTxs()
-	case len(vm.txs) == 1:
+	case vm.mempool.Ready() == 1:
 		vm.timer.SetTimeoutIn(vm.batchTimeout)
 	}
+	return nil
+}
+
+// gossipTx sends the tx to other validators, if this VM was given a sender
+func (vm *VM) gossipTx(tx *UniqueTx) {
+	if vm.appSender != nil {
+		vm.appSender.AppGossip(tx.Bytes())
+	}
+}
+
+// burned returns the amount of AVA that the tx consumes but doesn't produce.
+// What's burned beyond the required fee is a voluntary payment that lets a tx
+// be issued ahead of the txs that arrived before it.
+func (vm *VM) burned(tx *UniqueTx) uint64 {
+	tx.refresh()
+	if tx.t.tx == nil {
+		return 0
+	}
+	fee, err := Burned(tx.t.tx.UnsignedTx, vm.ava)
+	if err != nil {
+		return 0
+	}
+	return fee
+}
+
+// decideTx updates the mempool after consensus decided the tx. Pending txs
+// that can no longer be accepted are dropped. A rejected tx can't be issued
+// again under the same ID, but the txs that were waiting on it are issued in
+// the next flush.
+func (vm *VM) decideTx(tx *UniqueTx) {
+	var dropped []snowstorm.Tx
+	if tx.Status() == choices.Accepted {
+		dropped = vm.mempool.Accept(tx)
+	} else {
+		outputs := ids.Set{}
+		for _, utxo := range tx.UTXOs() {
+			outputs.Add(utxo.InputID())
+		}
+		dropped = vm.mempool.Reject(tx, outputs)
+	}
+	for _, droppedTx := range dropped {
+		vm.ctx.Log.Debug("Dropping tx %s from the mempool due to decided tx %s", droppedTx.ID(), tx.ID())
+	}
+
+	switch {
+	case vm.mempool.Ready() >= batchSize:
+		vm.FlushTxs()
+	case vm.mempool.Ready() != 0:
+		vm.timer.SetTimeoutIn(vm.batchTimeout)
+	}
+}
+
+// issueSignedTx marshals and issues the provided transaction
+func (vm *VM) issueSignedTx(tx *Tx) (ids.ID, error) {
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := vm.IssueTx(b)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem issuing transaction: %w", err)
+	}
+	return txID, nil
 }
 
 func (vm *VM) getFx(val interface{}) (int, error) {
@@ -437,6 +747,42 @@ func (vm *VM) getFx(val interface{}) (int, error) {
 	return fx, nil
 }
 
+// getUTXO returns the UTXO referenced by [utxoID]. The UTXO may either be in the
+// current UTXO set or be produced by a transaction that is still processing.
+func (vm *VM) getUTXO(utxoID *ava.UTXOID) (*ava.UTXO, error) {
+	if utxo, err := vm.state.UTXO(utxoID.InputID()); err == nil {
+		return utxo, nil
+	}
+
+	inputTx, inputIndex := utxoID.InputSource()
+	parent := UniqueTx{
+		vm:   vm,
+		txID: inputTx,
+	}
+
+	if err := parent.Verify(); err != nil {
+		return nil, errMissingUTXO
+	} else if status := parent.Status(); status.Decided() {
+		return nil, errMissingUTXO
+	}
+
+	parentUTXOs := parent.UTXOs()
+	if uint32(len(parentUTXOs)) <= inputIndex || int(inputIndex) < 0 {
+		return nil, errInvalidUTXO
+	}
+	return parentUTXOs[int(inputIndex)], nil
+}
+
+// getFxIndex returns the index of the feature extension with the provided ID
+func (vm *VM) getFxIndex(fxID ids.ID) (int, error) {
+	for i, fx := range vm.fxs {
+		if fx.ID.Equals(fxID) {
+			return i, nil
+		}
+	}
+	return 0, errUnknownFx
+}
+
 func (vm *VM) verifyFxUsage(fxID int, assetID ids.ID) bool {
 	tx := &UniqueTx{
 		vm:   vm,
@@ -467,12 +813,9 @@ func (vm *VM) Parse(addrStr string) ([]byte, error) {
 	addressParts := strings.SplitN(addrStr, addressSep, 2)
 	bcAlias := addressParts[0]
 	rawAddr := addressParts[1]
-	bcID, err := vm.ctx.BCLookup.Lookup(bcAlias)
+	bcID, err := vm.lookupChainID(bcAlias)
 	if err != nil {
-		bcID, err = ids.FromString(bcAlias)
-		if err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 	if !bcID.Equals(vm.ctx.ChainID) {
 		return nil, errWrongBlockchainID
@@ -482,6 +825,98 @@ func (vm *VM) Parse(addrStr string) ([]byte, error) {
 	return cb58.Bytes, err
 }
 
+// lookupChainID returns the ID of the chain referenced by either its alias or
+// its string representation
+func (vm *VM) lookupChainID(chain string) (ids.ID, error) {
+	if chainID, err := vm.ctx.BCLookup.Lookup(chain); err == nil {
+		return chainID, nil
+	}
+	return ids.FromString(chain)
+}
+
+// lookupAtomicChain returns the ID of the chain referenced by either its alias
+// or its string representation, which AVA must be able to be moved to and
+// from. If [chain] is empty, the platform chain's ID is returned.
+func (vm *VM) lookupAtomicChain(chain string) (ids.ID, error) {
+	if chain == "" {
+		return vm.platform, nil
+	}
+	chainID, err := vm.lookupChainID(chain)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	if !vm.isAtomicChain(chainID) {
+		return ids.ID{}, errNotAtomicChain
+	}
+	return chainID, nil
+}
+
+// isAtomicChain returns true if AVA can be moved between this chain and the
+// chain [chainID] through shared memory
+func (vm *VM) isAtomicChain(chainID ids.ID) bool {
+	return chainID.Equals(vm.platform) || (!vm.evm.IsZero() && chainID.Equals(vm.evm))
+}
+
+// lookupAssetID returns the ID of the asset referenced by either its alias or
+// its string representation
+func (vm *VM) lookupAssetID(asset string) (ids.ID, error) {
+	if assetID, err := vm.Lookup(asset); err == nil {
+		return assetID, nil
+	}
+	if assetID, err := ids.FromString(asset); err == nil {
+		return assetID, nil
+	}
+	return ids.ID{}, fmt.Errorf("asset '%s' not found", asset)
+}
+
+// parseAddress returns the short ID of the provided formatted address
+func (vm *VM) parseAddress(addrStr string) (ids.ShortID, error) {
+	addrBytes, err := vm.Parse(addrStr)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return ids.ToShortID(addrBytes)
+}
+
+// parseOwners returns the owners referenced by the provided formatted address.
+// The address may either be a single address or a multisig address, as
+// returned by formatOwners.
+func (vm *VM) parseOwners(addrStr string) (*secp256k1fx.OutputOwners, error) {
+	addrBytes, err := vm.Parse(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	if addr, err := ids.ToShortID(addrBytes); err == nil {
+		return &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		}, nil
+	}
+	owners := &secp256k1fx.OutputOwners{}
+	if err := vm.codec.Unmarshal(addrBytes, owners); err != nil {
+		return nil, errInvalidAddress
+	}
+	if err := owners.Verify(); err != nil {
+		return nil, err
+	}
+	if len(owners.Addrs) == 0 {
+		return nil, errInvalidAddress
+	}
+	return owners, nil
+}
+
+// formatOwners returns a multisig address that represents the provided owners
+func (vm *VM) formatOwners(owners *secp256k1fx.OutputOwners) (string, error) {
+	if owners.Threshold == 1 && len(owners.Addrs) == 1 {
+		return vm.Format(owners.Addrs[0].Bytes()), nil
+	}
+	b, err := vm.codec.Marshal(owners)
+	if err != nil {
+		return "", err
+	}
+	return vm.Format(b), nil
+}
+
 // Format ...
 func (vm *VM) Format(b []byte) string {
 	var bcAlias string
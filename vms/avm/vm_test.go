@@ -6,6 +6,7 @@ package avm
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
@@ -15,6 +16,7 @@ import (
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/units"
+	"github.com/ava-labs/gecko/vms/components/ava"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
@@ -171,6 +173,7 @@ func GenesisVM(t *testing.T) *VM {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -297,7 +300,7 @@ func TestTxSerialization(t *testing.T) {
 		},
 		Ops: []*Operation{
 			&Operation{
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: asset,
 				},
 				Outs: []*OperableOutput{
@@ -317,8 +320,8 @@ func TestTxSerialization(t *testing.T) {
 	for _, key := range keys {
 		addr := key.PublicKey().Address()
 
-		unsignedTx.Outs = append(unsignedTx.Outs, &TransferableOutput{
-			Asset: Asset{
+		unsignedTx.Outs = append(unsignedTx.Outs, &ava.TransferableOutput{
+			Asset: ava.Asset{
 				ID: asset,
 			},
 			Out: &secp256k1fx.TransferOutput{
@@ -362,6 +365,7 @@ func TestInvalidGenesis(t *testing.T) {
 		/*context=*/ ctx,
 		/*db=*/ memdb.New(),
 		/*genesisState=*/ nil,
+		/*configBytes=*/ nil,
 		/*engineMessenger=*/ make(chan common.Message, 1),
 		/*fxs=*/ nil,
 	)
@@ -370,6 +374,27 @@ func TestInvalidGenesis(t *testing.T) {
 	}
 }
 
+func TestInvalidConfig(t *testing.T) {
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		/*context=*/ ctx,
+		/*db=*/ memdb.New(),
+		/*genesisState=*/ BuildGenesisTest(t),
+		/*configBytes=*/ []byte("{"),
+		/*engineMessenger=*/ make(chan common.Message, 1),
+		/*fxs=*/ []*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err == nil {
+		t.Fatalf("Should have errored due to an invalid config")
+	}
+}
+
 func TestInvalidFx(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -381,6 +406,7 @@ func TestInvalidFx(t *testing.T) {
 		/*context=*/ ctx,
 		/*db=*/ memdb.New(),
 		/*genesisState=*/ genesisBytes,
+		/*configBytes=*/ nil,
 		/*engineMessenger=*/ make(chan common.Message, 1),
 		/*fxs=*/ []*common.Fx{
 			nil,
@@ -402,6 +428,7 @@ func TestFxInitializationFailure(t *testing.T) {
 		/*context=*/ ctx,
 		/*db=*/ memdb.New(),
 		/*genesisState=*/ genesisBytes,
+		/*configBytes=*/ nil,
 		/*engineMessenger=*/ make(chan common.Message, 1),
 		/*fxs=*/ []*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -428,6 +455,7 @@ func TestIssueTx(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		issuer,
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -444,13 +472,13 @@ func TestIssueTx(t *testing.T) {
 	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
 		NetID: networkID,
 		BCID:  chainID,
-		Ins: []*TransferableInput{
-			&TransferableInput{
-				UTXOID: UTXOID{
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
 					TxID:        genesisTx.ID(),
 					OutputIndex: 1,
 				},
-				Asset: Asset{
+				Asset: ava.Asset{
 					ID: genesisTx.ID(),
 				},
 				In: &secp256k1fx.TransferInput{
@@ -511,6 +539,199 @@ func TestIssueTx(t *testing.T) {
 	}
 }
 
+// Test that a tx is flushed right away if there are already more ready txs
+// than fit in a batch, such as after a decided conflict unblocked several txs
+func TestIssueTxFlushesFullMempool(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	issuer := make(chan common.Message, 1)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		nil,
+		issuer,
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only a full batch can cause a flush
+	vm.batchTimeout = time.Hour
+
+	for i := 0; i <= batchSize; i++ {
+		if _, err := vm.mempool.Add(newTestMempoolTx(uint64(i), ids.Empty.Prefix(uint64(i))), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*ava.TransferableInput{
+			&ava.TransferableInput{
+				UTXOID: ava.UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: ava.Asset{
+					ID: genesisTx.ID(),
+				},
+				In: &secp256k1fx.TransferInput{
+					Amt: 50000,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{
+							0,
+						},
+					},
+				},
+			},
+		},
+	}}}
+
+	unsignedBytes, err := vm.codec.Marshal(&newTx.UnsignedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := keys[0]
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixedSig := [crypto.SECP256K1RSigLen]byte{}
+	copy(fixedSig[:], sig)
+
+	newTx.Creds = append(newTx.Creds, &Credential{
+		Cred: &secp256k1fx.Credential{
+			Sigs: [][crypto.SECP256K1RSigLen]byte{
+				fixedSig,
+			},
+		},
+	})
+
+	b, err := vm.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx.Initialize(b)
+
+	if _, err := vm.IssueTx(newTx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-issuer:
+		if msg != common.PendingTxs {
+			t.Fatalf("Wrong message")
+		}
+	default:
+		t.Fatalf("Should have flushed the mempool once it held more than a batch of ready txs")
+	}
+}
+
+func TestGossipTx(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	newVM := func(issuer chan common.Message) *VM {
+		vm := &VM{}
+		err := vm.Initialize(
+			ctx,
+			memdb.New(),
+			genesisBytes,
+			nil,
+			issuer,
+			[]*common.Fx{&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm.batchTimeout = 0
+		return vm
+	}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	issuerVM := newVM(make(chan common.Message, 1))
+	receiverVM := newVM(make(chan common.Message, 1))
+
+	issuerSender := &common.SenderTest{T: t}
+	issuerSender.Default(true)
+	issuerVM.SetAppSender(issuerSender)
+	receiverSender := &common.SenderTest{T: t}
+	receiverSender.Default(true)
+	receiverVM.SetAppSender(receiverSender)
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	newTx := &Tx{UnsignedTx: &OperationTx{BaseTx: BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*ava.TransferableInput{&ava.TransferableInput{
+			UTXOID: ava.UTXOID{
+				TxID:        genesisTx.ID(),
+				OutputIndex: 1,
+			},
+			Asset: ava.Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   50000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+	}}}
+	if err := newTx.SignSECP256K1Fx(issuerVM.codec, [][]*crypto.PrivateKeySECP256K1R{{keys[0]}}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := issuerVM.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx.Initialize(b)
+
+	var gossiped []byte
+	issuerSender.AppGossipF = func(msg []byte) { gossiped = msg }
+	if _, err := issuerVM.IssueTx(newTx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(gossiped, newTx.Bytes()) {
+		t.Fatalf("Should have gossiped the issued tx")
+	}
+
+	regossiped := 0
+	receiverSender.AppGossipF = func(msg []byte) { regossiped++ }
+	nodeID := ids.NewShortID([20]byte{1})
+	if err := receiverVM.AppGossip(nodeID, gossiped); err != nil {
+		t.Fatal(err)
+	}
+	if !receiverVM.mempool.Has(newTx.ID()) {
+		t.Fatalf("Should have added the gossiped tx to the mempool")
+	}
+	// A tx that's already known isn't gossiped again
+	if err := receiverVM.AppGossip(nodeID, gossiped); err != nil {
+		t.Fatal(err)
+	}
+	if regossiped != 1 {
+		t.Fatalf("Should have gossiped the tx on once but gossiped it %d times", regossiped)
+	}
+
+	if err := receiverVM.AppGossip(nodeID, []byte{1}); err == nil {
+		t.Fatalf("Should have errored on an unparsable tx")
+	}
+}
+
 func TestGenesisGetUTXOs(t *testing.T) {
 	genesisBytes := BuildGenesisTest(t)
 
@@ -520,6 +741,7 @@ func TestGenesisGetUTXOs(t *testing.T) {
 		ctx,
 		memdb.New(),
 		genesisBytes,
+		nil,
 		make(chan common.Message, 1),
 		[]*common.Fx{&common.Fx{
 			ID: ids.Empty,
@@ -0,0 +1,111 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package addressbook
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+var (
+	// Keys are stored under [keyPrefix], by the address they control
+	keyPrefix = []byte("keys")
+	// The addresses in the book are stored under [addressesKey], in the order
+	// they were added
+	addressesKey = []byte("addresses")
+
+	errUnknownAddress = errors.New("address isn't in the address book")
+	errWrongKey       = errors.New("stored key doesn't control its address")
+)
+
+// Book of the addresses a keystore user controls and the keys that control
+// them. A book is stored in the database the keystore shares between every
+// chain, so a key added to it on one chain can be used on all of them.
+type Book struct {
+	db      database.Database
+	keys    database.Database
+	codec   codec.Codec
+	factory crypto.FactorySECP256K1R
+}
+
+// New returns the address book stored in [db], which should be the user's
+// database that's shared between chains
+func New(db database.Database) *Book {
+	return &Book{
+		db:    db,
+		keys:  prefixdb.NewNested(keyPrefix, db),
+		codec: codec.NewDefault(),
+	}
+}
+
+// Addresses returns the addresses in the book, in the order they were added
+func (b *Book) Addresses() ([]ids.ShortID, error) {
+	addrsBytes, err := b.db.Get(addressesKey)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	addrs := []ids.ShortID(nil)
+	return addrs, b.codec.Unmarshal(addrsBytes, &addrs)
+}
+
+// Contains returns true if [addr] is in the book
+func (b *Book) Contains(addr ids.ShortID) (bool, error) { return b.keys.Has(addr.Bytes()) }
+
+// Key returns the key that controls [addr]
+func (b *Book) Key(addr ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
+	skBytes, err := b.keys.Get(addr.Bytes())
+	if err == database.ErrNotFound {
+		return nil, errUnknownAddress
+	}
+	if err != nil {
+		return nil, err
+	}
+	skIntf, err := b.factory.ToPrivateKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	sk := skIntf.(*crypto.PrivateKeySECP256K1R)
+	if !sk.PublicKey().Address().Equals(addr) {
+		return nil, errWrongKey
+	}
+	return sk, nil
+}
+
+// Put [sk] in the book, under the address it controls. Putting a key that's
+// already in the book does nothing.
+func (b *Book) Put(sk *crypto.PrivateKeySECP256K1R) error {
+	addr := sk.PublicKey().Address()
+	if contains, err := b.Contains(addr); err != nil || contains {
+		return err
+	}
+
+	addrs, err := b.Addresses()
+	if err != nil {
+		return err
+	}
+	addrsBytes, err := b.codec.Marshal(append(addrs, addr))
+	if err != nil {
+		return err
+	}
+
+	// The key and the address are written atomically, so that an address is
+	// never listed without its key
+	vdb := versiondb.New(b.db)
+	if err := prefixdb.NewNested(keyPrefix, vdb).Put(addr.Bytes(), sk.Bytes()); err != nil {
+		return err
+	}
+	if err := vdb.Put(addressesKey, addrsBytes); err != nil {
+		return err
+	}
+	return vdb.Commit()
+}
@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package addressbook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func newKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	sk, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk.(*crypto.PrivateKeySECP256K1R)
+}
+
+func TestBookEmpty(t *testing.T) {
+	book := New(memdb.New())
+
+	addrs, err := book.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 0 {
+		t.Fatalf("Empty book shouldn't have any addresses")
+	}
+	if _, err := book.Key(newKey(t).PublicKey().Address()); err == nil {
+		t.Fatalf("Should have errored due to the address not being in the book")
+	}
+}
+
+func TestBookPut(t *testing.T) {
+	db := memdb.New()
+	book := New(db)
+
+	sk0, sk1 := newKey(t), newKey(t)
+	for _, sk := range []*crypto.PrivateKeySECP256K1R{sk0, sk1, sk0} {
+		if err := book.Put(sk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The book is read from the database, as another chain would
+	book = New(db)
+	addrs, err := book.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("Book should have %d addresses but has %d", 2, len(addrs))
+	}
+	for i, sk := range []*crypto.PrivateKeySECP256K1R{sk0, sk1} {
+		addr := sk.PublicKey().Address()
+		if !addrs[i].Equals(addr) {
+			t.Fatalf("Address %d should have been %s but was %s", i, addr, addrs[i])
+		}
+		if contains, err := book.Contains(addr); err != nil {
+			t.Fatal(err)
+		} else if !contains {
+			t.Fatalf("Book should contain %s", addr)
+		}
+		key, err := book.Key(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(key.Bytes(), sk.Bytes()) {
+			t.Fatalf("Book returned the wrong key for %s", addr)
+		}
+	}
+}
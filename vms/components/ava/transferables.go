@@ -1,7 +1,7 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-package avm
+package ava
 
 import (
 	"bytes"
@@ -21,15 +21,25 @@ var (
 	errNilTransferableFxInput = errors.New("nil transferable feature extension input is not valid")
 )
 
+// Transferable is the interface a feature extension must provide to transfer
+// value between features extensions.
+type Transferable interface {
+	verify.Verifiable
+
+	// Amount returns how much value this output consumes of the asset in its
+	// transaction.
+	Amount() uint64
+}
+
 // TransferableOutput ...
 type TransferableOutput struct {
 	Asset `serialize:"true"`
 
-	Out FxTransferable `serialize:"true"`
+	Out Transferable `serialize:"true"`
 }
 
 // Output returns the feature extension output that this Output is using.
-func (out *TransferableOutput) Output() FxTransferable { return out.Out }
+func (out *TransferableOutput) Output() Transferable { return out.Out }
 
 // Verify implements the verify.Verifiable interface
 func (out *TransferableOutput) Verify() error {
@@ -75,10 +85,13 @@ func (outs *innerSortTransferableOutputs) Less(i, j int) bool {
 func (outs *innerSortTransferableOutputs) Len() int      { return len(outs.outs) }
 func (outs *innerSortTransferableOutputs) Swap(i, j int) { o := outs.outs; o[j], o[i] = o[i], o[j] }
 
-func sortTransferableOutputs(outs []*TransferableOutput, c codec.Codec) {
+// SortTransferableOutputs sorts output objects
+func SortTransferableOutputs(outs []*TransferableOutput, c codec.Codec) {
 	sort.Sort(&innerSortTransferableOutputs{outs: outs, codec: c})
 }
-func isSortedTransferableOutputs(outs []*TransferableOutput, c codec.Codec) bool {
+
+// IsSortedTransferableOutputs returns true if output objects are sorted
+func IsSortedTransferableOutputs(outs []*TransferableOutput, c codec.Codec) bool {
 	return sort.IsSorted(&innerSortTransferableOutputs{outs: outs, codec: c})
 }
 
@@ -87,11 +100,11 @@ type TransferableInput struct {
 	UTXOID `serialize:"true"`
 	Asset  `serialize:"true"`
 
-	In FxTransferable `serialize:"true"`
+	In Transferable `serialize:"true"`
 }
 
 // Input returns the feature extension input that this Input is using.
-func (in *TransferableInput) Input() FxTransferable { return in.In }
+func (in *TransferableInput) Input() Transferable { return in.In }
 
 // Verify implements the verify.Verifiable interface
 func (in *TransferableInput) Verify() error {
@@ -123,7 +136,10 @@ func (ins innerSortTransferableInputs) Less(i, j int) bool {
 func (ins innerSortTransferableInputs) Len() int      { return len(ins) }
 func (ins innerSortTransferableInputs) Swap(i, j int) { ins[j], ins[i] = ins[i], ins[j] }
 
-func sortTransferableInputs(ins []*TransferableInput) { sort.Sort(innerSortTransferableInputs(ins)) }
-func isSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
+// SortTransferableInputs sorts input objects
+func SortTransferableInputs(ins []*TransferableInput) { sort.Sort(innerSortTransferableInputs(ins)) }
+
+// IsSortedAndUniqueTransferableInputs returns true if input objects are sorted and unique
+func IsSortedAndUniqueTransferableInputs(ins []*TransferableInput) bool {
 	return utils.IsSortedAndUnique(innerSortTransferableInputs(ins))
 }
@@ -1,7 +1,7 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-package avm
+package ava
 
 import (
 	"bytes"
@@ -85,11 +85,11 @@ func TestTransferableOutputSorting(t *testing.T) {
 		},
 	}
 
-	if isSortedTransferableOutputs(outs, c) {
+	if IsSortedTransferableOutputs(outs, c) {
 		t.Fatalf("Shouldn't be sorted")
 	}
-	sortTransferableOutputs(outs, c)
-	if !isSortedTransferableOutputs(outs, c) {
+	SortTransferableOutputs(outs, c)
+	if !IsSortedTransferableOutputs(outs, c) {
 		t.Fatalf("Should be sorted")
 	}
 	if result := outs[0].Out.(*TestTransferable).Val; result != 0 {
@@ -243,11 +243,11 @@ func TestTransferableInputSorting(t *testing.T) {
 		},
 	}
 
-	if isSortedAndUniqueTransferableInputs(ins) {
+	if IsSortedAndUniqueTransferableInputs(ins) {
 		t.Fatalf("Shouldn't be sorted")
 	}
-	sortTransferableInputs(ins)
-	if !isSortedAndUniqueTransferableInputs(ins) {
+	SortTransferableInputs(ins)
+	if !IsSortedAndUniqueTransferableInputs(ins) {
 		t.Fatalf("Should be sorted")
 	}
 
@@ -260,7 +260,7 @@ func TestTransferableInputSorting(t *testing.T) {
 		In:    &TestTransferable{},
 	})
 
-	if isSortedAndUniqueTransferableInputs(ins) {
+	if IsSortedAndUniqueTransferableInputs(ins) {
 		t.Fatalf("Shouldn't be unique")
 	}
 }
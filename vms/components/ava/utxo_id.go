@@ -1,7 +1,7 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-package avm
+package ava
 
 import (
 	"errors"
@@ -20,6 +20,10 @@ type UTXOID struct {
 	TxID        ids.ID `serialize:"true"`
 	OutputIndex uint32 `serialize:"true"`
 
+	// Symbol is true if the UTXO is consumed from shared memory rather than
+	// from this chain's UTXO set
+	Symbol bool
+
 	// Cached:
 	id ids.ID
 }
@@ -35,6 +39,10 @@ func (utxo *UTXOID) InputID() ids.ID {
 	return utxo.id
 }
 
+// Symbolic returns if this is the ID of a UTXO in the DB, or if it is a
+// symbolic input
+func (utxo *UTXOID) Symbolic() bool { return utxo.Symbol }
+
 // Verify implements the verify.Verifiable interface
 func (utxo *UTXOID) Verify() error {
 	switch {
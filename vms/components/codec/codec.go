@@ -6,7 +6,10 @@ package codec
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"sync"
 	"unicode"
 
 	"github.com/ava-labs/gecko/utils/wrappers"
@@ -27,8 +30,19 @@ const (
 const (
 	defaultMaxSize        = 1 << 19 // default max size, in bytes, of something being marshalled by Marshal()
 	defaultMaxSliceLength = 1 << 19 // default max length of a slice being marshalled by Marshal()
+	defaultMaxDepth       = 32      // max number of nested values (pointers, slices, arrays, structs, interfaces) in something being marshalled
+	initialBufferSize     = 1 << 10 // initial size, in bytes, of the buffers Marshal() packs into
 )
 
+// Buffers that Marshal() packs into. They are reused across calls, so that
+// marshaling only allocates the returned byte slice once the buffer has grown
+// large enough.
+var packerPool = sync.Pool{
+	New: func() interface{} {
+		return &wrappers.Packer{Bytes: make([]byte, 0, initialBufferSize)}
+	},
+}
+
 // ErrBadCodec is returned when one tries to perform an operation
 // using an unknown codec
 var (
@@ -43,6 +57,8 @@ var (
 	errUnmarshalUnexportedField  = errors.New("can't deserialize into an unexported field")
 	errOutOfMemory               = errors.New("out of memory")
 	errSliceTooLarge             = errors.New("slice too large")
+	errTooDeep                   = errors.New("value is nested too deeply")
+	errBadMaxLen                 = errors.New("maxLen tag must be a non-negative integer")
 )
 
 // Verify that the codec is a known codec value. Returns nil if the codec is
@@ -73,9 +89,30 @@ func (c Type) String() string {
 type codec struct {
 	maxSize     int
 	maxSliceLen int
+	maxDepth    int
 
 	typeIDToType map[uint32]reflect.Type
 	typeToTypeID map[reflect.Type]uint32
+
+	// The serialized fields of each struct type this codec has seen
+	fields *fieldCache
+}
+
+// serializedField is a field of a struct that is serialized
+type serializedField struct {
+	// Index of the field in its struct
+	index int
+	// Max length of the field, if it is a slice with a maxLen tag. Otherwise,
+	// -1.
+	maxLen int
+}
+
+// fieldCache caches the serialized fields of struct types, so that looking up
+// a struct's tags only happens the first time a value of its type is
+// marshaled or unmarshaled
+type fieldCache struct {
+	lock   sync.RWMutex
+	fields map[reflect.Type][]serializedField
 }
 
 // Codec marshals and unmarshals
@@ -83,6 +120,8 @@ type Codec interface {
 	RegisterType(interface{}) error
 	Marshal(interface{}) ([]byte, error)
 	Unmarshal([]byte, interface{}) error
+	MarshalCanonicalJSON(interface{}) ([]byte, error)
+	UnmarshalCanonicalJSON([]byte, interface{}) error
 }
 
 // New returns a new codec
@@ -90,8 +129,10 @@ func New(maxSize, maxSliceLen int) Codec {
 	return codec{
 		maxSize:      maxSize,
 		maxSliceLen:  maxSliceLen,
+		maxDepth:     defaultMaxDepth,
 		typeIDToType: map[uint32]reflect.Type{},
 		typeToTypeID: map[reflect.Type]uint32{},
+		fields:       &fieldCache{fields: map[reflect.Type][]serializedField{}},
 	}
 }
 
@@ -123,6 +164,9 @@ func (c codec) RegisterType(val interface{}) error {
 //    you must call codec.RegisterType([instance of the type that fulfills the interface]).
 // 7) nil slices will be unmarshaled as an empty slice of the appropriate type
 // 8) Serialized fields must be exported
+// 9) To limit the length of a slice field to less than the codec's maximum slice length,
+//    add the tag `maxLen:"[max length]"` to it
+// 10) A value can't be nested more than 32 levels deep
 
 // Marshal returns the byte representation of [value]
 // If you want to marshal an interface, [value] must be a pointer
@@ -132,110 +176,115 @@ func (c codec) Marshal(value interface{}) ([]byte, error) {
 		return nil, errNil
 	}
 
-	return c.marshal(reflect.ValueOf(value))
+	p := packerPool.Get().(*wrappers.Packer)
+	defer packerPool.Put(p)
+	*p = wrappers.Packer{MaxSize: c.maxSize, Bytes: p.Bytes[:0]}
+
+	if err := c.marshal(reflect.ValueOf(value), p, math.MaxInt32, 0); err != nil {
+		return nil, err
+	}
+	bytes := make([]byte, len(p.Bytes))
+	copy(bytes, p.Bytes)
+	return bytes, nil
 }
 
-// Marshal [value] to bytes
-func (c codec) marshal(value reflect.Value) ([]byte, error) {
-	p := wrappers.Packer{MaxSize: c.maxSize, Bytes: []byte{}}
-	t := value.Type()
+// Marshal [value] into [p]
+// If [value] is a slice, it may have at most [maxSliceLen] elements
+// [depth] is the number of values [value] is nested in
+func (c codec) marshal(value reflect.Value, p *wrappers.Packer, maxSliceLen, depth int) error {
+	if depth > c.maxDepth {
+		return errTooDeep
+	}
 
 	valueKind := value.Kind()
 	switch valueKind {
 	case reflect.Interface, reflect.Ptr, reflect.Slice:
 		if value.IsNil() {
-			return nil, errNil
+			return errNil
 		}
 	}
 
 	switch valueKind {
 	case reflect.Uint8:
 		p.PackByte(uint8(value.Uint()))
-		return p.Bytes, p.Err
 	case reflect.Int8:
 		p.PackByte(uint8(value.Int()))
-		return p.Bytes, p.Err
 	case reflect.Uint16:
 		p.PackShort(uint16(value.Uint()))
-		return p.Bytes, p.Err
 	case reflect.Int16:
 		p.PackShort(uint16(value.Int()))
-		return p.Bytes, p.Err
 	case reflect.Uint32:
 		p.PackInt(uint32(value.Uint()))
-		return p.Bytes, p.Err
 	case reflect.Int32:
 		p.PackInt(uint32(value.Int()))
-		return p.Bytes, p.Err
 	case reflect.Uint64:
 		p.PackLong(value.Uint())
-		return p.Bytes, p.Err
 	case reflect.Int64:
 		p.PackLong(uint64(value.Int()))
-		return p.Bytes, p.Err
 	case reflect.Uintptr, reflect.Ptr:
-		return c.marshal(value.Elem())
+		return c.marshal(value.Elem(), p, maxSliceLen, depth+1)
 	case reflect.String:
 		p.PackStr(value.String())
-		return p.Bytes, p.Err
 	case reflect.Bool:
 		p.PackBool(value.Bool())
-		return p.Bytes, p.Err
 	case reflect.Interface:
 		typeID, ok := c.typeToTypeID[reflect.TypeOf(value.Interface())] // Get the type ID of the value being marshaled
 		if !ok {
-			return nil, fmt.Errorf("can't marshal unregistered type '%v'", reflect.TypeOf(value.Interface()).String())
+			return fmt.Errorf("can't marshal unregistered type '%v'", reflect.TypeOf(value.Interface()).String())
 		}
 		p.PackInt(typeID)
-		bytes, err := c.Marshal(value.Interface())
-		if err != nil {
-			return nil, err
+		return c.marshal(value.Elem(), p, math.MaxInt32, depth+1)
+	case reflect.Slice:
+		numElts := value.Len() // # elements in the slice (assumed to be <= 2^31 - 1)
+		if numElts > maxSliceLen {
+			return errSliceTooLarge
 		}
-		p.PackFixedBytes(bytes)
-		if p.Errored() {
-			return nil, p.Err
+		p.PackInt(uint32(numElts))
+		if value.Type().Elem().Kind() == reflect.Uint8 { // Pack a byte slice all at once
+			p.PackFixedBytes(value.Bytes())
+			break
 		}
-		return p.Bytes, err
-	case reflect.Array, reflect.Slice:
-		numElts := value.Len() // # elements in the slice/array (assumed to be <= 2^31 - 1)
-		// If this is a slice, pack the number of elements in the slice
-		if valueKind == reflect.Slice {
-			p.PackInt(uint32(numElts))
+		for i := 0; i < numElts; i++ { // Pack each element in the slice
+			if err := c.marshal(value.Index(i), p, math.MaxInt32, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		numElts := value.Len()
+		if value.Type().Elem().Kind() == reflect.Uint8 && value.CanAddr() { // Pack a byte array all at once
+			p.PackFixedBytes(value.Slice(0, numElts).Bytes())
+			break
 		}
-		for i := 0; i < numElts; i++ { // Pack each element in the slice/array
-			eltBytes, err := c.marshal(value.Index(i))
-			if err != nil {
-				return nil, err
+		for i := 0; i < numElts; i++ { // Pack each element in the array
+			if err := c.marshal(value.Index(i), p, math.MaxInt32, depth+1); err != nil {
+				return err
 			}
-			p.PackFixedBytes(eltBytes)
 		}
-		return p.Bytes, p.Err
 	case reflect.Struct:
-		for i := 0; i < t.NumField(); i++ { // Go through all fields of this struct
-			field := t.Field(i)
-			if !shouldSerialize(field) { // Skip fields we don't need to serialize
-				continue
-			}
-			if unicode.IsLower(rune(field.Name[0])) { // Can only marshal exported fields
-				return nil, errMarshalUnexportedField
-			}
-			fieldVal := value.Field(i) // The field we're serializing
+		fields, err := c.fields.get(value.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields { // Go through the fields we need to serialize
+			fieldVal := value.Field(field.index) // The field we're serializing
 			if fieldVal.Kind() == reflect.Slice && fieldVal.IsNil() {
 				p.PackInt(0)
 				continue
 			}
-			fieldBytes, err := c.marshal(fieldVal) // Serialize the field
-			if err != nil {
-				return nil, err
+			fieldMaxSliceLen := math.MaxInt32
+			if field.maxLen >= 0 {
+				fieldMaxSliceLen = field.maxLen
+			}
+			if err := c.marshal(fieldVal, p, fieldMaxSliceLen, depth+1); err != nil { // Serialize the field
+				return err
 			}
-			p.PackFixedBytes(fieldBytes)
 		}
-		return p.Bytes, p.Err
 	case reflect.Invalid:
-		return nil, errUnmarshalNil
+		return errUnmarshalNil
 	default:
-		return nil, errUnknownType
+		return errUnknownType
 	}
+	return p.Err
 }
 
 // Unmarshal unmarshals [bytes] into [dest], where
@@ -259,7 +308,7 @@ func (c codec) Unmarshal(bytes []byte, dest interface{}) error {
 
 	destVal := destPtr.Elem()
 
-	err := c.unmarshal(p, destVal)
+	err := c.unmarshal(p, destVal, c.maxSliceLen, 0)
 	if err != nil {
 		return err
 	}
@@ -272,7 +321,13 @@ func (c codec) Unmarshal(bytes []byte, dest interface{}) error {
 
 // Unmarshal bytes from [p] into [field]
 // [field] must be addressable
-func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
+// If [field] is a slice, it may have at most [maxSliceLen] elements
+// [depth] is the number of values [field] is nested in
+func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value, maxSliceLen, depth int) error {
+	if depth > c.maxDepth {
+		return errTooDeep
+	}
+
 	kind := field.Kind()
 	switch kind {
 	case reflect.Uint8:
@@ -295,22 +350,45 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 		field.SetBool(p.UnpackBool())
 	case reflect.Slice:
 		sliceLen := int(p.UnpackInt()) // number of elements in the slice
-		if sliceLen < 0 || sliceLen > c.maxSliceLen {
+		if p.Errored() {
+			return p.Err
+		}
+		if sliceLen < 0 || sliceLen > maxSliceLen {
 			return errSliceTooLarge
 		}
 
+		if field.Type().Elem().Kind() == reflect.Uint8 { // Unpack a byte slice all at once
+			bytes := p.UnpackFixedBytes(sliceLen)
+			if p.Errored() {
+				return p.Err
+			}
+			// [bytes] is a subslice of the input, so copy it
+			slice := make([]byte, sliceLen)
+			copy(slice, bytes)
+			field.SetBytes(slice)
+			break
+		}
+
 		// First set [field] to be a slice of the appropriate type/capacity (right now [field] is nil)
 		slice := reflect.MakeSlice(field.Type(), sliceLen, sliceLen)
 		field.Set(slice)
 		// Unmarshal each element into the appropriate index of the slice
 		for i := 0; i < sliceLen; i++ {
-			if err := c.unmarshal(p, field.Index(i)); err != nil {
+			if err := c.unmarshal(p, field.Index(i), c.maxSliceLen, depth+1); err != nil {
 				return err
 			}
 		}
 	case reflect.Array:
+		if field.Type().Elem().Kind() == reflect.Uint8 { // Unpack a byte array all at once
+			bytes := p.UnpackFixedBytes(field.Len())
+			if p.Errored() {
+				return p.Err
+			}
+			copy(field.Slice(0, field.Len()).Bytes(), bytes)
+			break
+		}
 		for i := 0; i < field.Len(); i++ {
-			if err := c.unmarshal(p, field.Index(i)); err != nil {
+			if err := c.unmarshal(p, field.Index(i), c.maxSliceLen, depth+1); err != nil {
 				return err
 			}
 		}
@@ -326,25 +404,25 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 		}
 		concreteInstancePtr := reflect.New(typ) // instance of the proper type
 		// Unmarshal into the struct
-		if err := c.unmarshal(p, concreteInstancePtr.Elem()); err != nil {
+		if err := c.unmarshal(p, concreteInstancePtr.Elem(), c.maxSliceLen, depth+1); err != nil {
 			return err
 		}
 		// And assign the filled struct to the field
 		field.Set(concreteInstancePtr.Elem())
 	case reflect.Struct:
-		// Type of this struct
-		structType := reflect.TypeOf(field.Interface())
+		// The fields of this struct we need to unmarshal
+		fields, err := c.fields.get(field.Type())
+		if err != nil {
+			return err
+		}
 		// Go through all the fields and umarshal into each
-		for i := 0; i < structType.NumField(); i++ {
-			structField := structType.Field(i)
-			if !shouldSerialize(structField) { // Skip fields we don't need to unmarshal
-				continue
-			}
-			if unicode.IsLower(rune(structField.Name[0])) { // Only unmarshal into exported field
-				return errUnmarshalUnexportedField
+		for _, structField := range fields {
+			fieldMaxSliceLen := c.maxSliceLen
+			if structField.maxLen >= 0 && structField.maxLen < fieldMaxSliceLen {
+				fieldMaxSliceLen = structField.maxLen
 			}
-			field := field.Field(i)                       // Get the field
-			if err := c.unmarshal(p, field); err != nil { // Unmarshal into the field
+			field := field.Field(structField.index)                                  // Get the field
+			if err := c.unmarshal(p, field, fieldMaxSliceLen, depth+1); err != nil { // Unmarshal into the field
 				return err
 			}
 			if p.Errored() { // If there was an error just return immediately
@@ -357,7 +435,7 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 		// Create a new pointer to a new value of the underlying type
 		underlyingValue := reflect.New(underlyingType)
 		// Fill the value
-		if err := c.unmarshal(p, underlyingValue.Elem()); err != nil {
+		if err := c.unmarshal(p, underlyingValue.Elem(), maxSliceLen, depth+1); err != nil {
 			return err
 		}
 		// Assign to the top-level struct's member
@@ -370,6 +448,45 @@ func (c codec) unmarshal(p *wrappers.Packer, field reflect.Value) error {
 	return p.Err
 }
 
+// get returns the fields of the struct type [t] that are serialized, in the
+// order they are serialized in
+func (fc *fieldCache) get(t reflect.Type) ([]serializedField, error) {
+	fc.lock.RLock()
+	fields, ok := fc.fields[t]
+	fc.lock.RUnlock()
+	if ok {
+		return fields, nil
+	}
+
+	fields = []serializedField(nil)
+	for i := 0; i < t.NumField(); i++ { // Go through all fields of this struct
+		field := t.Field(i)
+		if !shouldSerialize(field) { // Skip fields we don't need to serialize
+			continue
+		}
+		if unicode.IsLower(rune(field.Name[0])) { // Can only serialize exported fields
+			return nil, errMarshalUnexportedField
+		}
+		maxLen := -1
+		if tag, ok := field.Tag.Lookup("maxLen"); ok {
+			tagLen, err := strconv.Atoi(tag)
+			if err != nil || tagLen < 0 {
+				return nil, errBadMaxLen
+			}
+			maxLen = tagLen
+		}
+		fields = append(fields, serializedField{
+			index:  i,
+			maxLen: maxLen,
+		})
+	}
+
+	fc.lock.Lock()
+	fc.fields[t] = fields
+	fc.lock.Unlock()
+	return fields, nil
+}
+
 // Returns true iff [field] should be serialized
 func shouldSerialize(field reflect.StructField) bool {
 	if field.Tag.Get("serialize") == "true" {
@@ -538,3 +538,122 @@ func TestTooLargeUnmarshal(t *testing.T) {
 		t.Fatalf("Should have errored due to too many bytes provided")
 	}
 }
+
+// Ensure a slice longer than the limit in its maxLen tag can't be marshaled or
+// unmarshaled
+func TestMaxLenTag(t *testing.T) {
+	type limited struct {
+		Slice []byte `serialize:"true" maxLen:"2"`
+	}
+
+	codec := NewDefault()
+
+	bytes, err := codec.Marshal(&limited{Slice: []byte{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshaled := limited{}
+	if err := codec.Unmarshal(bytes, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := codec.Marshal(&limited{Slice: []byte{1, 2, 3}}); err != errSliceTooLarge {
+		t.Fatalf("Should have errored due to the slice being too long")
+	}
+	if err := codec.Unmarshal([]byte{0x00, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03}, &unmarshaled); err != errSliceTooLarge {
+		t.Fatalf("Should have errored due to the slice being too long")
+	}
+}
+
+// Ensure a maxLen tag that isn't a length errors
+func TestBadMaxLenTag(t *testing.T) {
+	type badTag struct {
+		Slice []byte `serialize:"true" maxLen:"two"`
+	}
+
+	codec := NewDefault()
+	if _, err := codec.Marshal(&badTag{}); err != errBadMaxLen {
+		t.Fatalf("Should have errored due to the bad maxLen tag")
+	}
+	if err := codec.Unmarshal([]byte{0x00, 0x00, 0x00, 0x00}, &badTag{}); err != errBadMaxLen {
+		t.Fatalf("Should have errored due to the bad maxLen tag")
+	}
+}
+
+// Ensure values that are nested too deeply can't be marshaled or unmarshaled
+func TestTooDeep(t *testing.T) {
+	type deep struct {
+		Nested [][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][]byte `serialize:"true"`
+	}
+
+	codec := NewDefault()
+
+	if _, err := codec.Marshal(&deep{Nested: make([][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][][]byte, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each slice holds one slice, all the way down
+	b := []byte{}
+	for i := 0; i < 33; i++ {
+		b = append(b, 0x00, 0x00, 0x00, 0x01)
+	}
+	b = append(b, 0x00, 0x00, 0x00, 0x00)
+	if err := codec.Unmarshal(b, &deep{}); err != errTooDeep {
+		t.Fatalf("Should have errored due to the value being nested too deeply")
+	}
+}
+
+// Ensure byte slices and byte arrays, including those of named byte types,
+// are serialized the same way as any other slice or array
+func TestBytes(t *testing.T) {
+	type myByte byte
+	type byteStruct struct {
+		Slice      []byte     `serialize:"true"`
+		Array      [2]byte    `serialize:"true"`
+		NamedSlice []myByte   `serialize:"true"`
+		NamedArray [2]myByte  `serialize:"true"`
+		NilSlice   []byte     `serialize:"true"`
+		Nested     [][2]uint8 `serialize:"true"`
+	}
+
+	codec := NewDefault()
+	value := byteStruct{
+		Slice:      []byte{1, 2, 3},
+		Array:      [2]byte{4, 5},
+		NamedSlice: []myByte{6},
+		NamedArray: [2]myByte{7, 8},
+		Nested:     [][2]uint8{{9, 10}},
+	}
+	expected := []byte{
+		0x00, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03, // Slice
+		0x04, 0x05, // Array
+		0x00, 0x00, 0x00, 0x01, 0x06, // NamedSlice
+		0x07, 0x08, // NamedArray
+		0x00, 0x00, 0x00, 0x00, // NilSlice
+		0x00, 0x00, 0x00, 0x01, 0x09, 0x0a, // Nested
+	}
+
+	result, err := codec.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(result, expected) {
+		t.Fatalf("expected %v but got %v", expected, result)
+	}
+
+	unmarshaled := byteStruct{}
+	if err := codec.Unmarshal(result, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	value.NilSlice = []byte{}
+	if !reflect.DeepEqual(value, unmarshaled) {
+		t.Fatalf("expected %v but got %v", value, unmarshaled)
+	}
+
+	// The unmarshaled value shouldn't share memory with the bytes it was
+	// unmarshaled from
+	result[4] = 0xff
+	if unmarshaled.Slice[0] != 1 {
+		t.Fatal("unmarshaled byte slice was modified by modifying the input")
+	}
+}
@@ -0,0 +1,369 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+// JSON notes:
+// 1) MarshalCanonicalJSON encodes the same fields Marshal does, so a value's JSON holds
+//    exactly the information in its byte representation
+// 2) Structs are encoded as objects, keyed by the names of their serialized
+//    fields with the first letter lowercased. The fields of an embedded struct
+//    are encoded as fields of the struct that embeds it.
+// 3) Interface typed values are encoded as {"type": [name], "value": [value]},
+//    where [name] is the package qualified name of the registered type, such
+//    as "secp256k1fx.TransferOutput"
+// 4) Integers are encoded as decimal strings, and byte slices and arrays as
+//    CB58 strings
+// 5) Values of types that implement json.Marshaler, such as ids.ID, are
+//    encoded by their MarshalJSON method
+// 6) Object keys are sorted, so the JSON of a value is unique
+
+const (
+	jsonTypeKey  = "type"
+	jsonValueKey = "value"
+)
+
+var (
+	errJSONFieldCollision = errors.New("embedded struct has a field with the same name as a field of the struct that embeds it")
+	errJSONMissingField   = errors.New("JSON is missing a serialized field")
+
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// MarshalCanonicalJSON returns the JSON representation of [value]
+// If you want to marshal an interface, [value] must be a pointer
+// to the interface
+func (c codec) MarshalCanonicalJSON(value interface{}) ([]byte, error) {
+	if value == nil {
+		return nil, errNil
+	}
+	jsonValue, err := c.marshalJSON(reflect.ValueOf(value), 0)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonValue)
+}
+
+// marshalJSON returns a value that encoding/json marshals into the JSON
+// representation of [value]
+// [depth] is the number of values [value] is nested in
+func (c codec) marshalJSON(value reflect.Value, depth int) (interface{}, error) {
+	if depth > c.maxDepth {
+		return nil, errTooDeep
+	}
+
+	valueKind := value.Kind()
+	switch valueKind {
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			return nil, errNil
+		}
+	}
+
+	if valueKind != reflect.Interface && value.Type().Implements(jsonMarshalerType) {
+		return value.Interface(), nil
+	}
+
+	switch valueKind {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Ptr:
+		return c.marshalJSON(value.Elem(), depth+1)
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Interface:
+		valueType := reflect.TypeOf(value.Interface())
+		if _, ok := c.typeToTypeID[valueType]; !ok {
+			return nil, fmt.Errorf("can't marshal unregistered type '%v'", valueType.String())
+		}
+		elem, err := c.marshalJSON(value.Elem(), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			jsonTypeKey:  typeName(valueType),
+			jsonValueKey: elem,
+		}, nil
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(bytes), value)
+			return formatting.CB58{Bytes: bytes}, nil
+		}
+		elts := make([]interface{}, value.Len())
+		for i := range elts {
+			elt, err := c.marshalJSON(value.Index(i), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			elts[i] = elt
+		}
+		return elts, nil
+	case reflect.Struct:
+		obj := map[string]interface{}{}
+		if err := c.marshalJSONFields(value, obj, depth); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case reflect.Invalid:
+		return nil, errUnmarshalNil
+	default:
+		return nil, errUnknownType
+	}
+}
+
+// marshalJSONFields adds the serialized fields of the struct [value] to [obj]
+func (c codec) marshalJSONFields(value reflect.Value, obj map[string]interface{}, depth int) error {
+	fields, err := c.fields.get(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		structField := value.Type().Field(field.index)
+		fieldVal := value.Field(field.index)
+		if isEmbeddedStruct(structField) {
+			if err := c.marshalJSONFields(fieldVal, obj, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := jsonName(structField)
+		if _, exists := obj[name]; exists {
+			return errJSONFieldCollision
+		}
+		fieldJSON, err := c.marshalJSON(fieldVal, depth+1)
+		if err != nil {
+			return err
+		}
+		obj[name] = fieldJSON
+	}
+	return nil
+}
+
+// UnmarshalCanonicalJSON unmarshals the JSON representation [bytes] into
+// [dest], where [dest] must be a pointer or interface
+func (c codec) UnmarshalCanonicalJSON(bytes []byte, dest interface{}) error {
+	if len(bytes) > c.maxSize {
+		return errSliceTooLarge
+	}
+	if dest == nil {
+		return errNil
+	}
+
+	destPtr := reflect.ValueOf(dest)
+	if destPtr.Kind() != reflect.Ptr {
+		return errNeedPointer
+	}
+	return c.unmarshalJSON(bytes, destPtr.Elem(), 0)
+}
+
+// Unmarshal the JSON [bytes] into [field]
+// [field] must be addressable
+// [depth] is the number of values [field] is nested in
+func (c codec) unmarshalJSON(bytes []byte, field reflect.Value, depth int) error {
+	if depth > c.maxDepth {
+		return errTooDeep
+	}
+
+	kind := field.Kind()
+	if kind != reflect.Interface && reflect.PtrTo(field.Type()).Implements(jsonUnmarshalerType) {
+		return field.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(bytes)
+	}
+
+	switch kind {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		str := ""
+		if err := json.Unmarshal(bytes, &str); err != nil {
+			return err
+		}
+		val, err := strconv.ParseUint(str, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(val)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		str := ""
+		if err := json.Unmarshal(bytes, &str); err != nil {
+			return err
+		}
+		val, err := strconv.ParseInt(str, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(val)
+	case reflect.Bool:
+		val := false
+		if err := json.Unmarshal(bytes, &val); err != nil {
+			return err
+		}
+		field.SetBool(val)
+	case reflect.String:
+		val := ""
+		if err := json.Unmarshal(bytes, &val); err != nil {
+			return err
+		}
+		field.SetString(val)
+	case reflect.Slice, reflect.Array:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			cb58 := formatting.CB58{}
+			if err := cb58.UnmarshalJSON(bytes); err != nil {
+				return err
+			}
+			if kind == reflect.Slice {
+				if len(cb58.Bytes) > c.maxSliceLen {
+					return errSliceTooLarge
+				}
+				field.SetBytes(cb58.Bytes)
+			} else {
+				if len(cb58.Bytes) != field.Len() {
+					return fmt.Errorf("expected %d bytes but got %d", field.Len(), len(cb58.Bytes))
+				}
+				reflect.Copy(field, reflect.ValueOf(cb58.Bytes))
+			}
+			break
+		}
+
+		elts := []json.RawMessage(nil)
+		if err := json.Unmarshal(bytes, &elts); err != nil {
+			return err
+		}
+		if kind == reflect.Slice {
+			if len(elts) > c.maxSliceLen {
+				return errSliceTooLarge
+			}
+			field.Set(reflect.MakeSlice(field.Type(), len(elts), len(elts)))
+		} else if len(elts) != field.Len() {
+			return fmt.Errorf("expected %d elements but got %d", field.Len(), len(elts))
+		}
+		for i, elt := range elts {
+			if err := c.unmarshalJSON(elt, field.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Interface:
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(bytes, &obj); err != nil {
+			return err
+		}
+		name := ""
+		if err := json.Unmarshal(obj[jsonTypeKey], &name); err != nil {
+			return err
+		}
+		typ, ok := c.typeForName(name)
+		if !ok {
+			return errUnmarshalUnregisteredType
+		}
+		if !typ.Implements(field.Type()) {
+			return fmt.Errorf("type '%s' doesn't implement '%s'", name, field.Type().String())
+		}
+		elem, ok := obj[jsonValueKey]
+		if !ok {
+			return errJSONMissingField
+		}
+		concreteInstancePtr := reflect.New(typ)
+		if err := c.unmarshalJSON(elem, concreteInstancePtr.Elem(), depth+1); err != nil {
+			return err
+		}
+		field.Set(concreteInstancePtr.Elem())
+	case reflect.Struct:
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(bytes, &obj); err != nil {
+			return err
+		}
+		return c.unmarshalJSONFields(obj, field, depth)
+	case reflect.Ptr:
+		underlyingValue := reflect.New(field.Type().Elem())
+		if err := c.unmarshalJSON(bytes, underlyingValue.Elem(), depth+1); err != nil {
+			return err
+		}
+		field.Set(underlyingValue)
+	case reflect.Invalid:
+		return errUnmarshalNil
+	default:
+		return errUnknownType
+	}
+	return nil
+}
+
+// unmarshalJSONFields sets the serialized fields of the struct [field] from
+// [obj]
+func (c codec) unmarshalJSONFields(obj map[string]json.RawMessage, field reflect.Value, depth int) error {
+	fields, err := c.fields.get(field.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		structField := field.Type().Field(f.index)
+		fieldVal := field.Field(f.index)
+		if isEmbeddedStruct(structField) {
+			if err := c.unmarshalJSONFields(obj, fieldVal, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fieldJSON, ok := obj[jsonName(structField)]
+		if !ok {
+			return fmt.Errorf("%w: %s", errJSONMissingField, jsonName(structField))
+		}
+		if err := c.unmarshalJSON(fieldJSON, fieldVal, depth+1); err != nil {
+			return err
+		}
+		if f.maxLen >= 0 && fieldVal.Kind() == reflect.Slice && fieldVal.Len() > f.maxLen {
+			return errSliceTooLarge
+		}
+	}
+	return nil
+}
+
+// typeForName returns the registered type whose name is [name]
+func (c codec) typeForName(name string) (reflect.Type, bool) {
+	for typ := range c.typeToTypeID {
+		if typeName(typ) == name {
+			return typ, true
+		}
+	}
+	return nil, false
+}
+
+// typeName returns the package qualified name of [typ], ignoring pointers
+func typeName(typ reflect.Type) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.String()
+}
+
+// jsonName returns the key [field] is encoded under
+func jsonName(field reflect.StructField) string {
+	r, size := utf8.DecodeRuneInString(field.Name)
+	return string(unicode.ToLower(r)) + field.Name[size:]
+}
+
+// isEmbeddedStruct returns true if [field] is an embedded struct, whose fields
+// are encoded as fields of the struct that embeds it
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	typ := field.Type
+	return typ.Kind() == reflect.Struct && !typ.Implements(jsonMarshalerType)
+}
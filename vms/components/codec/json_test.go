@@ -0,0 +1,127 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+type myEmbeddingStruct struct {
+	MyInnerStruct `serialize:"true"`
+	Num           uint64 `serialize:"true"`
+	Bytes         []byte `serialize:"true"`
+	F             Foo    `serialize:"true"`
+	Skipped       int
+}
+
+// Test the JSON representation of a struct
+func TestMarshalCanonicalJSON(t *testing.T) {
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+	codec.RegisterType(&MyInnerStruct2{})
+
+	val := myEmbeddingStruct{
+		MyInnerStruct: MyInnerStruct{Str: "hi"},
+		Num:           5,
+		Bytes:         []byte{1, 2, 3},
+		F:             &MyInnerStruct2{Bool: true},
+		Skipped:       1,
+	}
+	valJSON, err := codec.MarshalCanonicalJSON(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"bytes":"3DUyVE7YU","f":{"type":"codec.MyInnerStruct2","value":{"bool":true}},"num":"5","str":"hi"}`
+	if string(valJSON) != expected {
+		t.Fatalf("Expected: %s\nResult: %s", expected, valJSON)
+	}
+
+	unmarshaled := myEmbeddingStruct{}
+	if err := codec.UnmarshalCanonicalJSON(valJSON, &unmarshaled); err != nil {
+		t.Fatal(err)
+	}
+	unmarshaled.Skipped = val.Skipped
+	valBytes, err := codec.Marshal(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshaledBytes, err := codec.Marshal(unmarshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(valBytes, unmarshaledBytes) {
+		t.Fatal("expected unmarshaled struct to be same as original struct")
+	}
+}
+
+// Test that a complicated struct is the same after a round trip through JSON
+func TestJSONRoundTrip(t *testing.T) {
+	temp := Foo(&MyInnerStruct{})
+	myStructInstance := myStruct{
+		InnerStruct:  MyInnerStruct{"hello"},
+		InnerStruct2: &MyInnerStruct{"yello"},
+		Member1:      -1,
+		MySlice:      []byte{1, 2, 3, 4},
+		MySlice2:     []string{"one", "two", "three"},
+		MySlice3:     []MyInnerStruct{MyInnerStruct{"a"}, MyInnerStruct{"b"}, MyInnerStruct{"c"}},
+		MySlice4:     []*MyInnerStruct2{&MyInnerStruct2{true}, &MyInnerStruct2{}},
+		MySlice5:     []Foo{&MyInnerStruct2{true}, &MyInnerStruct2{}},
+		MyArray:      [4]byte{5, 6, 7, 8},
+		MyArray2:     [5]string{"four", "five", "six", "seven"},
+		MyArray3:     [3]MyInnerStruct{MyInnerStruct{"d"}, MyInnerStruct{"e"}, MyInnerStruct{"f"}},
+		MyArray4:     [2]*MyInnerStruct2{&MyInnerStruct2{}, &MyInnerStruct2{true}},
+		MyInterface:  &MyInnerStruct{"yeet"},
+		InnerStruct3: MyInnerStruct3{
+			Str: "str",
+			M1: MyInnerStruct{
+				Str: "other str",
+			},
+			F: &MyInnerStruct2{},
+		},
+		MyPointer: &temp,
+	}
+
+	codec := NewDefault()
+	codec.RegisterType(&MyInnerStruct{})
+	codec.RegisterType(&MyInnerStruct2{})
+
+	myStructJSON, err := codec.MarshalCanonicalJSON(myStructInstance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	myStructUnmarshaled := &myStruct{}
+	if err := codec.UnmarshalCanonicalJSON(myStructJSON, myStructUnmarshaled); err != nil {
+		t.Fatal(err)
+	}
+
+	myStructBytes, err := codec.Marshal(myStructInstance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshaledBytes, err := codec.Marshal(myStructUnmarshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(myStructBytes, unmarshaledBytes) {
+		t.Fatal("expected unmarshaled struct to be same as original struct")
+	}
+
+	// The JSON of a value is unique
+	unmarshaledJSON, err := codec.MarshalCanonicalJSON(myStructUnmarshaled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(myStructJSON, unmarshaledJSON) {
+		t.Fatalf("expected the same JSON but got:\n%s\n%s", myStructJSON, unmarshaledJSON)
+	}
+}
+
+// Test that JSON missing a serialized field is rejected
+func TestUnmarshalCanonicalJSONMissingField(t *testing.T) {
+	codec := NewDefault()
+	if err := codec.UnmarshalCanonicalJSON([]byte(`{"bool":true,"extra":"1"}`), &MyInnerStruct{}); err == nil {
+		t.Fatal("should have errored due to the missing field")
+	}
+}
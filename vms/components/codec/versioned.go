@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errUnknownVersion = errors.New("unknown codec version")
+)
+
+// VersionedCodec marshals values with one of several codecs, each identified
+// by a version. The marshaled bytes start with the version of the codec that
+// marshaled them, so a VM can change its serialization format by registering
+// a codec with a new version while still unmarshaling bytes of older versions.
+type VersionedCodec interface {
+	// RegisterCodec makes [codec] the codec of [version]
+	RegisterCodec(version uint16, codec Codec) error
+
+	// Marshal returns the byte representation of [value], marshaled with the
+	// codec of [version]
+	Marshal(version uint16, value interface{}) ([]byte, error)
+
+	// Unmarshal unmarshals [bytes] into [dest] with the codec of the version
+	// [bytes] start with, and returns that version
+	Unmarshal(bytes []byte, dest interface{}) (uint16, error)
+}
+
+type versionedCodec struct {
+	codecs map[uint16]Codec
+}
+
+// NewVersioned returns a new versioned codec with no codecs registered
+func NewVersioned() VersionedCodec {
+	return &versionedCodec{codecs: map[uint16]Codec{}}
+}
+
+// RegisterCodec implements the VersionedCodec interface
+func (vc *versionedCodec) RegisterCodec(version uint16, codec Codec) error {
+	if _, exists := vc.codecs[version]; exists {
+		return fmt.Errorf("a codec of version %d has already been registered", version)
+	}
+	vc.codecs[version] = codec
+	return nil
+}
+
+// Marshal implements the VersionedCodec interface
+func (vc *versionedCodec) Marshal(version uint16, value interface{}) ([]byte, error) {
+	codec, exists := vc.codecs[version]
+	if !exists {
+		return nil, errUnknownVersion
+	}
+	bytes, err := codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	size := wrappers.ShortLen + len(bytes)
+	p := wrappers.Packer{MaxSize: size, Bytes: make([]byte, 0, size)}
+	p.PackShort(version)
+	p.PackFixedBytes(bytes)
+	return p.Bytes, p.Err
+}
+
+// Unmarshal implements the VersionedCodec interface
+func (vc *versionedCodec) Unmarshal(bytes []byte, dest interface{}) (uint16, error) {
+	p := wrappers.Packer{Bytes: bytes}
+	version := p.UnpackShort()
+	if p.Errored() {
+		return 0, p.Err
+	}
+	codec, exists := vc.codecs[version]
+	if !exists {
+		return version, errUnknownVersion
+	}
+	return version, codec.Unmarshal(bytes[p.Offset:], dest)
+}
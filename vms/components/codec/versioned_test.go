@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"bytes"
+	"testing"
+)
+
+// A format that gained a field in its second version
+type formatV0 struct {
+	Num uint32 `serialize:"true"`
+}
+
+type formatV1 struct {
+	Num uint32 `serialize:"true"`
+	Str string `serialize:"true"`
+}
+
+func TestVersionedCodec(t *testing.T) {
+	vc := NewVersioned()
+	if err := vc.RegisterCodec(0, NewDefault()); err != nil {
+		t.Fatal(err)
+	}
+	if err := vc.RegisterCodec(1, NewDefault()); err != nil {
+		t.Fatal(err)
+	}
+	if err := vc.RegisterCodec(1, NewDefault()); err == nil {
+		t.Fatal("should have errored because version 1 already has a codec")
+	}
+
+	v0Bytes, err := vc.Marshal(0, &formatV0{Num: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x05}
+	if !bytes.Equal(v0Bytes, expected) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, v0Bytes)
+	}
+
+	v1Bytes, err := vc.Marshal(1, &formatV1{Num: 5, Str: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x05, 0x00, 0x01, 'a'}
+	if !bytes.Equal(v1Bytes, expected) {
+		t.Fatalf("\nExpected: 0x%x\nResult:   0x%x", expected, v1Bytes)
+	}
+
+	v0 := formatV0{}
+	if version, err := vc.Unmarshal(v0Bytes, &v0); err != nil {
+		t.Fatal(err)
+	} else if version != 0 {
+		t.Fatalf("expected version %d but got %d", 0, version)
+	} else if v0.Num != 5 {
+		t.Fatalf("expected %d but got %d", 5, v0.Num)
+	}
+
+	v1 := formatV1{}
+	if version, err := vc.Unmarshal(v1Bytes, &v1); err != nil {
+		t.Fatal(err)
+	} else if version != 1 {
+		t.Fatalf("expected version %d but got %d", 1, version)
+	} else if v1.Num != 5 || v1.Str != "a" {
+		t.Fatalf("unexpected value %+v", v1)
+	}
+}
+
+func TestVersionedCodecUnknownVersion(t *testing.T) {
+	vc := NewVersioned()
+	if err := vc.RegisterCodec(0, NewDefault()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := vc.Marshal(1, &formatV0{}); err != errUnknownVersion {
+		t.Fatal("should have errored because version 1 has no codec")
+	}
+	if _, err := vc.Unmarshal([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x05}, &formatV0{}); err != errUnknownVersion {
+		t.Fatal("should have errored because version 1 has no codec")
+	}
+	if _, err := vc.Unmarshal([]byte{0x00}, &formatV0{}); err == nil {
+		t.Fatal("should have errored because the bytes are too short to hold a version")
+	}
+}
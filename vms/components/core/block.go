@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/vms/components/missing"
+	"github.com/ava-labs/gecko/vms/components/state"
 )
 
 var (
@@ -60,11 +61,17 @@ func (b *Block) Accept() {
 	b.VM.lastAccepted = b.ID() // Change state of VM
 }
 
-// Reject sets this block's status to Rejected and saves the status in state
+// Reject sets this block's status to Rejected and saves the status in state.
+// The block is marked mortal, so it's pruned once the retention window has
+// passed. Its status is kept, so the block is still known to be rejected if
+// it's parsed again.
 // Recall that b.vm.DB.Commit() must be called to persist to the DB
 func (b *Block) Reject() {
 	b.SetStatus(choices.Rejected)
 	b.VM.State.PutStatus(b.VM.DB, b.ID(), choices.Rejected)
+	if err := b.VM.Pruner.MarkMortal(b.VM.State.Key(state.BlockTypeID, b.ID())); err != nil {
+		b.VM.Ctx.Log.Error("Failed to mark block %s as mortal due to %s", b.ID(), err)
+	}
 }
 
 // Status returns the status of this block
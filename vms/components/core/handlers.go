@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Middleware wraps an HTTP handler. For example, it may authenticate requests
+// before passing them on to the handler.
+type Middleware func(http.Handler) http.Handler
+
+// Verifiable is implemented by the arguments of API methods that can check
+// themselves. If Verify returns an error, the method isn't called and the
+// caller gets an invalid params error.
+type Verifiable interface {
+	Verify() error
+}
+
+// HandlerBuilder builds the HTTP handlers a VM returns from CreateHandlers and
+// CreateStaticHandlers, so that all VMs serve their APIs the same way
+type HandlerBuilder struct {
+	// Applied to every handler
+	middleware []Middleware
+	handlers   map[string]*common.HTTPHandler
+	errs       wrappers.Errs
+}
+
+// NewHandlerBuilder returns a builder whose handlers are all wrapped in
+// [middleware]
+func NewHandlerBuilder(middleware ...Middleware) *HandlerBuilder {
+	return &HandlerBuilder{
+		middleware: middleware,
+		handlers:   make(map[string]*common.HTTPHandler),
+	}
+}
+
+// AddService serves the JSON-RPC service [service], named [name], at
+// [extension]. Calls to it hold [lock] and go through [middleware], after the
+// builder's middleware.
+func (b *HandlerBuilder) AddService(extension, name string, service interface{}, lock common.LockOption, middleware ...Middleware) {
+	handler, err := NewService(name, service)
+	if err != nil {
+		b.errs.Add(err)
+		return
+	}
+	b.AddHandler(extension, handler, lock, middleware...)
+}
+
+// AddHandler serves [handler] at [extension]. Calls to it hold [lock] and go
+// through [middleware], after the builder's middleware.
+func (b *HandlerBuilder) AddHandler(extension string, handler http.Handler, lock common.LockOption, middleware ...Middleware) {
+	if _, exists := b.handlers[extension]; exists {
+		b.errs.Add(fmt.Errorf("a handler is already served at %q", extension))
+		return
+	}
+	// The first middleware is the outermost
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		handler = b.middleware[i](handler)
+	}
+	b.handlers[extension] = &common.HTTPHandler{LockOptions: lock, Handler: handler}
+}
+
+// Build returns the handlers, keyed by their extensions
+func (b *HandlerBuilder) Build() (map[string]*common.HTTPHandler, error) {
+	if b.errs.Errored() {
+		return nil, b.errs.Err
+	}
+	return b.handlers, nil
+}
+
+// NewService returns a handler that serves the JSON-RPC service [service]
+// under the name [name]
+// [service] should be a gorilla RPC service (see https://www.gorillatoolkit.org/pkg/rpc/v2)
+// Arguments that implement Verifiable are verified before the method is
+// called.
+func NewService(name string, service interface{}) (http.Handler, error) {
+	server := rpc.NewServer()
+	codec := json.NewCodec()
+	server.RegisterCodec(codec, "application/json")
+	server.RegisterCodec(codec, "application/json;charset=UTF-8")
+	server.RegisterValidateRequestFunc(verifyArgs)
+	if err := server.RegisterService(service, name); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
+
+// verifyArgs verifies the arguments of a call, if they're Verifiable
+func verifyArgs(_ *rpc.RequestInfo, args interface{}) error {
+	verifiable, ok := args.(Verifiable)
+	if !ok {
+		return nil
+	}
+	if err := verifiable.Verify(); err != nil {
+		return &json2.Error{
+			Code:    json2.E_BAD_PARAMS,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
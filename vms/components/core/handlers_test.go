@@ -0,0 +1,119 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var errEmptyName = errors.New("name is empty")
+
+type testService struct{}
+
+type HelloArgs struct{ Name string }
+
+func (args *HelloArgs) Verify() error {
+	if args.Name == "" {
+		return errEmptyName
+	}
+	return nil
+}
+
+type HelloReply struct{ Greeting string }
+
+func (*testService) Hello(_ *http.Request, args *HelloArgs, reply *HelloReply) error {
+	reply.Greeting = "hello " + args.Name
+	return nil
+}
+
+func call(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandlerBuilderService(t *testing.T) {
+	builder := NewHandlerBuilder()
+	builder.AddService("", "test", &testService{}, common.ReadLock)
+	handlers, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, ok := handlers[""]
+	if !ok {
+		t.Fatal("service should be served at the empty extension")
+	}
+	if handler.LockOptions != common.ReadLock {
+		t.Fatalf("service should hold lock %d but holds %d", common.ReadLock, handler.LockOptions)
+	}
+
+	w := call(handler.Handler, `{"jsonrpc":"2.0","method":"test.hello","params":[{"Name":"gecko"}],"id":1}`)
+	if !strings.Contains(w.Body.String(), `"Greeting":"hello gecko"`) {
+		t.Fatalf("unexpected response %s", w.Body.String())
+	}
+
+	// Arguments that don't verify are rejected as invalid params
+	w = call(handler.Handler, `{"jsonrpc":"2.0","method":"test.hello","params":[{"Name":""}],"id":1}`)
+	if !strings.Contains(w.Body.String(), `"code":-32602`) || !strings.Contains(w.Body.String(), errEmptyName.Error()) {
+		t.Fatalf("expected an invalid params error but the response was %s", w.Body.String())
+	}
+}
+
+func TestHandlerBuilderMiddleware(t *testing.T) {
+	order := []string(nil)
+	middleware := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	deny := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+	}
+
+	builder := NewHandlerBuilder(middleware("builder"))
+	builder.AddHandler("/open", http.NotFoundHandler(), common.NoLock, middleware("first"), middleware("second"))
+	builder.AddService("/closed", "test", &testService{}, common.WriteLock, deny)
+	handlers, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call(handlers["/open"].Handler, "")
+	if expected := "builder,first,second"; strings.Join(order, ",") != expected {
+		t.Fatalf("middleware should have run in the order %s but ran in %s", expected, strings.Join(order, ","))
+	}
+
+	w := call(handlers["/closed"].Handler, `{"jsonrpc":"2.0","method":"test.hello","params":[{"Name":"gecko"}],"id":1}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("middleware should have rejected the call but the status was %d", w.Code)
+	}
+}
+
+func TestHandlerBuilderErrors(t *testing.T) {
+	builder := NewHandlerBuilder()
+	builder.AddHandler("", http.NotFoundHandler(), common.NoLock)
+	builder.AddHandler("", http.NotFoundHandler(), common.NoLock)
+	if _, err := builder.Build(); err == nil {
+		t.Fatal("should have errored because two handlers are served at the same extension")
+	}
+
+	builder = NewHandlerBuilder()
+	builder.AddService("", "test", &struct{}{}, common.WriteLock)
+	if _, err := builder.Build(); err == nil {
+		t.Fatal("should have errored because the service has no methods")
+	}
+}
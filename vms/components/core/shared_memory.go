@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type rcLock struct {
+	lock  sync.Mutex
+	count int
+}
+
+// SharedMemory is the node wide memory that blockchains use to atomically move
+// state between each other. Every pair of blockchains is given a database that
+// only they share. While a blockchain holds the database of a pair, the other
+// blockchain of the pair is blocked from accessing it.
+type SharedMemory struct {
+	lock  sync.Mutex
+	log   logging.Logger
+	db    database.Database
+	locks map[[32]byte]*rcLock
+}
+
+// Initialize the SharedMemory
+func (sm *SharedMemory) Initialize(log logging.Logger, db database.Database) {
+	sm.log = log
+	sm.db = db
+	sm.locks = make(map[[32]byte]*rcLock)
+}
+
+// NewBlockchainSharedMemory returns a new BlockchainSharedMemory
+func (sm *SharedMemory) NewBlockchainSharedMemory(id ids.ID) *BlockchainSharedMemory {
+	return &BlockchainSharedMemory{
+		blockchainID: id,
+		sm:           sm,
+	}
+}
+
+// GetDatabase returns and locks the provided DB
+func (sm *SharedMemory) GetDatabase(id ids.ID) database.Database {
+	lock := sm.makeLock(id)
+	lock.Lock()
+
+	return prefixdb.New(id.Bytes(), sm.db)
+}
+
+// ReleaseDatabase unlocks the provided DB
+func (sm *SharedMemory) ReleaseDatabase(id ids.ID) {
+	lock := sm.releaseLock(id)
+	lock.Unlock()
+}
+
+func (sm *SharedMemory) makeLock(id ids.ID) *sync.Mutex {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	key := id.Key()
+	rc, exists := sm.locks[key]
+	if !exists {
+		rc = &rcLock{}
+		sm.locks[key] = rc
+	}
+	rc.count++
+	return &rc.lock
+}
+
+func (sm *SharedMemory) releaseLock(id ids.ID) *sync.Mutex {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+
+	key := id.Key()
+	rc, exists := sm.locks[key]
+	if !exists {
+		panic("Attempting to free an unknown lock")
+	}
+	rc.count--
+	if rc.count == 0 {
+		delete(sm.locks, key)
+	}
+	return &rc.lock
+}
+
+// sharedID calculates the ID of the database shared by the two provided
+// blockchains. The ID is independent of the order of the blockchains.
+func (sm *SharedMemory) sharedID(id1, id2 ids.ID) ids.ID {
+	if bytes.Compare(id1.Bytes(), id2.Bytes()) == 1 {
+		id1, id2 = id2, id1
+	}
+
+	pairBytes := make([]byte, 0, 2*hashing.HashLen)
+	pairBytes = append(pairBytes, id1.Bytes()...)
+	pairBytes = append(pairBytes, id2.Bytes()...)
+	return ids.NewID(hashing.ComputeHash256Array(pairBytes))
+}
+
+// BlockchainSharedMemory provides the API for a blockchain to interact with
+// shared memory of another blockchain
+type BlockchainSharedMemory struct {
+	blockchainID ids.ID
+	sm           *SharedMemory
+}
+
+// GetDatabase returns and locks the database shared with the provided
+// blockchain. ReleaseDatabase must be called once the database is no longer
+// being used.
+func (bsm *BlockchainSharedMemory) GetDatabase(id ids.ID) database.Database {
+	sharedID := bsm.sm.sharedID(id, bsm.blockchainID)
+	return bsm.sm.GetDatabase(sharedID)
+}
+
+// ReleaseDatabase unlocks the database shared with the provided blockchain
+func (bsm *BlockchainSharedMemory) ReleaseDatabase(id ids.ID) {
+	sharedID := bsm.sm.sharedID(id, bsm.blockchainID)
+	bsm.sm.ReleaseDatabase(sharedID)
+}
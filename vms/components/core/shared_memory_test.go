@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	blockchainID0 = ids.Empty.Prefix(0)
+	blockchainID1 = ids.Empty.Prefix(1)
+	blockchainID2 = ids.Empty.Prefix(2)
+)
+
+func TestSharedMemory(t *testing.T) {
+	sm := SharedMemory{}
+	sm.Initialize(logging.NoLog{}, memdb.New())
+
+	sm0 := sm.NewBlockchainSharedMemory(blockchainID0)
+	sm1 := sm.NewBlockchainSharedMemory(blockchainID1)
+
+	key := []byte{1}
+	value := []byte{2}
+
+	db0 := sm0.GetDatabase(blockchainID1)
+	if err := db0.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	sm0.ReleaseDatabase(blockchainID1)
+
+	db1 := sm1.GetDatabase(blockchainID0)
+	defer sm1.ReleaseDatabase(blockchainID0)
+
+	if result, err := db1.Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(result, value) {
+		t.Fatalf("Shared database returned the wrong value")
+	}
+}
+
+func TestSharedMemoryIsolated(t *testing.T) {
+	sm := SharedMemory{}
+	sm.Initialize(logging.NoLog{}, memdb.New())
+
+	sm0 := sm.NewBlockchainSharedMemory(blockchainID0)
+	sm2 := sm.NewBlockchainSharedMemory(blockchainID2)
+
+	key := []byte{1}
+
+	db0 := sm0.GetDatabase(blockchainID1)
+	if err := db0.Put(key, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	sm0.ReleaseDatabase(blockchainID1)
+
+	db2 := sm2.GetDatabase(blockchainID0)
+	defer sm2.ReleaseDatabase(blockchainID0)
+
+	if has, err := db2.Has(key); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Database shared with a different blockchain should be isolated")
+	}
+}
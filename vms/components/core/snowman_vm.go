@@ -5,17 +5,18 @@ package core
 
 import (
 	"errors"
-
-	"github.com/gorilla/rpc/v2"
+	"net/http"
 
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
-	"github.com/ava-labs/gecko/utils/json"
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/vms/components/pruning"
 	"github.com/ava-labs/gecko/vms/components/state"
 )
 
@@ -41,12 +42,19 @@ type SnowmanVM struct {
 	// The context of this vm
 	Ctx *snow.Context
 
+	// Prunes rejected blocks from [DB] once the context's retention window has
+	// passed
+	Pruner *pruning.Pruner
+
 	// ID of the preferred block
 	preferred ids.ID
 
 	// ID of the last accepted block
 	lastAccepted ids.ID
 
+	// Indexes the accepted blocks by height
+	heights *smeng.HeightIndex
+
 	// unmarshals bytes to a block
 	unmarshalBlockFunc func([]byte) (snowman.Block, error)
 
@@ -63,6 +71,11 @@ func (svm *SnowmanVM) Preferred() ids.ID { return svm.preferred }
 // LastAccepted returns the block most recently accepted
 func (svm *SnowmanVM) LastAccepted() ids.ID { return svm.lastAccepted }
 
+// GetBlockIDAtHeight returns the ID of the accepted block with height [height]
+func (svm *SnowmanVM) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	return svm.heights.GetBlockIDAtHeight(height)
+}
+
 // ParseBlock parses [bytes] to a block
 func (svm *SnowmanVM) ParseBlock(bytes []byte) (snowman.Block, error) {
 	return svm.unmarshalBlockFunc(bytes)
@@ -83,6 +96,7 @@ func (svm *SnowmanVM) GetBlock(ID ids.ID) (snowman.Block, error) {
 
 // Shutdown this vm
 func (svm *SnowmanVM) Shutdown() {
+	svm.Pruner.Stop()
 	svm.DB.Commit()              // Flush DB
 	svm.DB.GetDatabase().Close() // close underlying database
 	svm.DB.Close()               // close versionDB
@@ -125,16 +139,17 @@ func (svm *SnowmanVM) NotifyBlockReady() {
 //     By default the LockOption is WriteLock
 //     [lockOption] should have either 0 or 1 elements. Elements beside the first are ignored.
 func (svm *SnowmanVM) NewHandler(name string, service interface{}, lockOption ...common.LockOption) *common.HTTPHandler {
-	server := rpc.NewServer()
-	server.RegisterCodec(json.NewCodec(), "application/json")
-	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
-	server.RegisterService(service, name)
-
 	var lock common.LockOption = common.WriteLock
 	if len(lockOption) != 0 {
 		lock = lockOption[0]
 	}
-	return &common.HTTPHandler{LockOptions: lock, Handler: server}
+	handler, err := NewService(name, service)
+	if err != nil {
+		handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		})
+	}
+	return &common.HTTPHandler{LockOptions: lock, Handler: handler}
 }
 
 // Initialize this vm.
@@ -149,6 +164,9 @@ func (svm *SnowmanVM) Initialize(
 	svm.Ctx = ctx
 	svm.ToEngine = toEngine
 	svm.DB = versiondb.New(db)
+	svm.Pruner = pruning.New(ctx, svm.DB, ctx.StateRetention)
+	// Only accepted blocks are indexed, so the index is written to directly
+	svm.heights = smeng.NewHeightIndex(svm, prefixdb.New([]byte("height"), db))
 
 	var err error
 	svm.State, err = NewSnowmanState(unmarshalBlockFunc)
@@ -163,5 +181,6 @@ func (svm *SnowmanVM) Initialize(
 		svm.preferred = svm.lastAccepted
 	}
 
+	svm.Pruner.Start()
 	return nil
 }
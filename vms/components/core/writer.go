@@ -0,0 +1,21 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"github.com/ava-labs/gecko/database"
+)
+
+// WriteAll atomically writes [batches] along with [baseBatch]. All of the
+// batches must be writing to the same base database, which allows a chain to
+// commit its own state and its changes to shared memory together.
+func WriteAll(baseBatch database.Batch, batches ...database.Batch) error {
+	baseBatch = baseBatch.Inner()
+	for _, batch := range batches {
+		if err := batch.Inner().Replay(baseBatch); err != nil {
+			return err
+		}
+	}
+	return baseBatch.Write()
+}
@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+)
+
+func TestWriteAll(t *testing.T) {
+	baseDB := memdb.New()
+	chainDB := versiondb.New(prefixdb.New([]byte{0}, baseDB))
+	sharedDB := versiondb.New(prefixdb.New([]byte{1}, prefixdb.New([]byte{2}, baseDB)))
+
+	key1 := []byte{3}
+	value1 := []byte{4}
+	key2 := []byte{5}
+	value2 := []byte{6}
+
+	if err := chainDB.Put(key1, value1); err != nil {
+		t.Fatal(err)
+	} else if err := sharedDB.Put(key2, value2); err != nil {
+		t.Fatal(err)
+	}
+
+	chainBatch, err := chainDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedBatch, err := sharedDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteAll(chainBatch, sharedBatch); err != nil {
+		t.Fatal(err)
+	}
+	chainDB.Abort()
+	sharedDB.Abort()
+
+	if value, err := chainDB.GetDatabase().Get(key1); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, value1) {
+		t.Fatalf("Wrong value returned: 0x%x ; Expected: 0x%x", value, value1)
+	} else if value, err := sharedDB.GetDatabase().Get(key2); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, value2) {
+		t.Fatalf("Wrong value returned: 0x%x ; Expected: 0x%x", value, value2)
+	}
+}
@@ -0,0 +1,206 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pruning
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// pruneFrequency is how often expired keys are pruned
+	pruneFrequency = time.Minute
+
+	// maxPrunedPerRound is the most keys deleted while the context's lock is
+	// held, so pruning a large backlog doesn't stall the chain
+	maxPrunedPerRound = 1024
+
+	timeLen = 8
+)
+
+var (
+	// Keys are scheduled to be pruned under [schedulePrefix], ordered by the
+	// time they were marked mortal. The time each key was marked mortal is
+	// stored under [mortalPrefix], so it can be unscheduled.
+	schedulePrefix = []byte("pruning schedule")
+	mortalPrefix   = []byte("pruning mortal")
+)
+
+// Pruner deletes keys of a VM's database a retention window after they're
+// marked mortal. Keys are immortal, and kept forever, unless they're marked
+// mortal. VMs mark the keys of state that's no longer needed once it's
+// decided, such as rejected containers, as mortal.
+//
+// Keys are deleted from the database underneath the VM's versiondb, so values
+// the VM has cached may still be returned after they're pruned.
+type Pruner struct {
+	ctx       *snow.Context
+	db        *versiondb.Database
+	retention time.Duration
+	clock     timer.Clock
+	repeater  *timer.Repeater
+}
+
+// New returns a pruner of [db] that deletes keys [retention] after they're
+// marked mortal. If [retention] is 0, nothing is pruned, and marking keys is a
+// no-op.
+func New(ctx *snow.Context, db *versiondb.Database, retention time.Duration) *Pruner {
+	p := &Pruner{
+		ctx:       ctx,
+		db:        db,
+		retention: retention,
+	}
+	p.repeater = timer.NewRepeater(func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		if n, err := p.Prune(); err != nil {
+			ctx.Log.Error("pruning failed due to %s", err)
+		} else if n > 0 {
+			ctx.Log.Debug("pruned %d keys", n)
+		}
+	}, pruneFrequency)
+	return p
+}
+
+// Enabled returns true if this pruner deletes keys
+func (p *Pruner) Enabled() bool { return p.retention > 0 }
+
+// Clock returns the clock used to decide when keys expire
+func (p *Pruner) Clock() *timer.Clock { return &p.clock }
+
+// Start pruning in the background
+func (p *Pruner) Start() {
+	if p.Enabled() {
+		go p.ctx.Log.RecoverAndPanic(p.repeater.Dispatch)
+	}
+}
+
+// Stop pruning in the background
+func (p *Pruner) Stop() {
+	if p.Enabled() {
+		p.repeater.Stop()
+	}
+}
+
+// MarkMortal schedules [keys] to be pruned once the retention window has
+// passed. The schedule is written to the VM's versiondb, so it's committed
+// along with the state that made [keys] mortal.
+func (p *Pruner) MarkMortal(keys ...[]byte) error {
+	if !p.Enabled() {
+		return nil
+	}
+
+	schedule := prefixdb.NewNested(schedulePrefix, p.db)
+	mortal := prefixdb.NewNested(mortalPrefix, p.db)
+	now := make([]byte, timeLen)
+	binary.BigEndian.PutUint64(now, p.clock.Unix())
+	for _, key := range keys {
+		if err := p.unschedule(schedule, mortal, key); err != nil {
+			return err
+		}
+		if err := schedule.Put(scheduleKey(now, key), nil); err != nil {
+			return err
+		}
+		if err := mortal.Put(key, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarkImmortal unschedules [keys] from being pruned
+func (p *Pruner) MarkImmortal(keys ...[]byte) error {
+	if !p.Enabled() {
+		return nil
+	}
+
+	schedule := prefixdb.NewNested(schedulePrefix, p.db)
+	mortal := prefixdb.NewNested(mortalPrefix, p.db)
+	for _, key := range keys {
+		if err := p.unschedule(schedule, mortal, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune deletes up to [maxPrunedPerRound] keys whose retention window has
+// passed, and returns how many were deleted
+// Assumes the context's lock is held
+func (p *Pruner) Prune() (int, error) {
+	if !p.Enabled() {
+		return 0, nil
+	}
+
+	now, retention := p.clock.Unix(), uint64(p.retention/time.Second)
+	if now < retention {
+		return 0, nil
+	}
+	cutoff := make([]byte, timeLen)
+	binary.BigEndian.PutUint64(cutoff, now-retention)
+
+	// Deletes are written underneath the VM's versiondb, so they're never
+	// committed with state the VM hasn't finished writing
+	vdb := versiondb.New(p.db.GetDatabase())
+	schedule := prefixdb.NewNested(schedulePrefix, vdb)
+	mortal := prefixdb.NewNested(mortalPrefix, vdb)
+
+	expired := [][]byte(nil)
+	it := schedule.NewIterator()
+	for len(expired) < maxPrunedPerRound && it.Next() {
+		if bytes.Compare(it.Key()[:timeLen], cutoff) > 0 {
+			break
+		}
+		expired = append(expired, append([]byte(nil), it.Key()...))
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, scheduled := range expired {
+		key := scheduled[timeLen:]
+		if err := vdb.Delete(key); err != nil {
+			return 0, err
+		}
+		if err := schedule.Delete(scheduled); err != nil {
+			return 0, err
+		}
+		if err := mortal.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), vdb.Commit()
+}
+
+// unschedule [key] from being pruned, if it's scheduled
+func (p *Pruner) unschedule(schedule, mortal database.Database, key []byte) error {
+	marked, err := mortal.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		return nil
+	case err != nil:
+		return err
+	}
+	if err := schedule.Delete(scheduleKey(marked, key)); err != nil {
+		return err
+	}
+	return mortal.Delete(key)
+}
+
+func scheduleKey(marked, key []byte) []byte {
+	scheduled := make([]byte, timeLen+len(key))
+	copy(scheduled, marked)
+	copy(scheduled[timeLen:], key)
+	return scheduled
+}
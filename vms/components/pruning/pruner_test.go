@@ -0,0 +1,171 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pruning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/snow"
+)
+
+func newPruner(t *testing.T, retention time.Duration) (*Pruner, *versiondb.Database) {
+	db := versiondb.New(memdb.New())
+	p := New(snow.DefaultContextTest(), db, retention)
+	p.Clock().Set(time.Unix(1000, 0))
+	for _, key := range []string{"rejected", "status", "accepted"} {
+		if err := db.Put([]byte(key), []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return p, db
+}
+
+func assertHas(t *testing.T, db *versiondb.Database, key string, expected bool) {
+	if has, err := db.Has([]byte(key)); err != nil {
+		t.Fatal(err)
+	} else if has != expected {
+		t.Fatalf("Has(%s) returned %v, expected %v", key, has, expected)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	p, db := newPruner(t, time.Hour)
+
+	if err := p.MarkMortal([]byte("rejected"), []byte("status")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is pruned within the retention window
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour - time.Second))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("Pruned %d keys within the retention window", n)
+	}
+	assertHas(t, db, "rejected", true)
+
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 2 {
+		t.Fatalf("Pruned %d keys, expected 2", n)
+	}
+	assertHas(t, db, "rejected", false)
+	assertHas(t, db, "status", false)
+	assertHas(t, db, "accepted", true)
+
+	// The schedule is pruned along with the keys
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("Pruned %d keys again", n)
+	}
+}
+
+func TestPruneUncommitted(t *testing.T) {
+	p, db := newPruner(t, time.Hour)
+
+	if err := p.MarkMortal([]byte("rejected")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys aren't mortal until the VM commits them as mortal
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("Pruned %d keys that weren't committed as mortal", n)
+	}
+	assertHas(t, db, "rejected", true)
+}
+
+func TestMarkImmortal(t *testing.T) {
+	p, db := newPruner(t, time.Hour)
+
+	if err := p.MarkMortal([]byte("rejected"), []byte("status")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.MarkImmortal([]byte("status")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("Pruned %d keys, expected 1", n)
+	}
+	assertHas(t, db, "rejected", false)
+	assertHas(t, db, "status", true)
+}
+
+func TestMarkMortalAgain(t *testing.T) {
+	p, db := newPruner(t, time.Hour)
+
+	if err := p.MarkMortal([]byte("rejected")); err != nil {
+		t.Fatal(err)
+	}
+	// Marking a key again restarts its retention window
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Minute))
+	if err := p.MarkMortal([]byte("rejected")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("Pruned %d keys before their retention window restarted", n)
+	}
+
+	p.Clock().Set(time.Unix(1000, 0).Add(time.Hour + time.Minute))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("Pruned %d keys, expected 1", n)
+	}
+	assertHas(t, db, "rejected", false)
+}
+
+func TestPruningDisabled(t *testing.T) {
+	p, db := newPruner(t, 0)
+
+	if err := p.MarkMortal([]byte("rejected")); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.Clock().Set(time.Unix(1000, 0).Add(365 * 24 * time.Hour))
+	if n, err := p.Prune(); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("Pruned %d keys while pruning is disabled", n)
+	}
+	assertHas(t, db, "rejected", true)
+
+	// Only the keys the test put are in the database
+	it := db.GetDatabase().NewIterator()
+	defer it.Release()
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != 3 {
+		t.Fatalf("Database has %d keys, expected 3", count)
+	}
+}
@@ -68,6 +68,10 @@ type State interface {
 	// GetTime gets the time associated with [key] in [db]
 	GetTime(db database.Database, key ids.ID) (time.Time, error)
 
+	// Key returns the key the value of type [typeID] whose key is [key] is
+	// stored under in the database
+	Key(typeID uint64, key ids.ID) []byte
+
 	// Register a new type.
 	// When values that were Put with [typeID] are retrieved from the database,
 	// they will be unmarshaled from bytes using [unmarshal].
@@ -221,6 +225,9 @@ func (s *state) uniqueID(ID ids.ID, typeID uint64) ids.ID {
 	return uID
 }
 
+// Key implements the State interface
+func (s *state) Key(typeID uint64, key ids.ID) []byte { return s.uniqueID(key, typeID).Bytes() }
+
 // NewState returns a new State
 func NewState() State {
 	state := &state{
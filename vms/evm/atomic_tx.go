@@ -0,0 +1,296 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/coreth/core/state"
+
+	"github.com/ava-labs/go-ethereum/common"
+	"github.com/ava-labs/go-ethereum/core/types"
+	"github.com/ava-labs/go-ethereum/rlp"
+
+	ethcrypto "github.com/ava-labs/go-ethereum/crypto"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/components/addressbook"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/core"
+)
+
+var (
+	errNilTx             = errors.New("nil tx")
+	errWrongNetworkID    = errors.New("tx was issued with a different network ID")
+	errInvalidID         = errors.New("tx has an invalid ID")
+	errNoSharedMemory    = errors.New("shared memory is not available")
+	errNoAtomicChain     = errors.New("AVA can't be moved to or from this chain")
+	errConflictingImport = errors.New("a processing ancestor block imports the same UTXO")
+)
+
+// x2cRate is the number of wei an account's balance holds for each nAVA moved
+// to this chain, so that 1 AVA is 10^18 wei, as 1 ether is
+var x2cRate = big.NewInt(1000000000)
+
+// Codec serializes the atomic transactions of this chain
+var Codec codec.Codec
+
+func init() {
+	Codec = codec.NewDefault()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		Codec.RegisterType(&UnsignedImportTx{}),
+		Codec.RegisterType(&ImportTx{}),
+
+		Codec.RegisterType(&UnsignedExportTx{}),
+		Codec.RegisterType(&ExportTx{}),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// AtomicTx moves $AVA between this chain and the X-Chain through the memory
+// they share. A block holds at most one atomic tx, in its extra data. The tx
+// changes the balances of the EVM's accounts when the block is processed, and
+// the shared memory when the block is accepted.
+type AtomicTx interface {
+	initialize(vm *VM) error
+
+	// ID of this transaction
+	ID() ids.ID
+
+	// Bytes of this transaction, as it's placed in a block
+	Bytes() []byte
+
+	// InputUTXOs returns the IDs of the UTXOs in shared memory this
+	// transaction consumes
+	InputUTXOs() ids.Set
+
+	// SemanticVerify returns nil if this transaction can be issued at
+	// [currentTime], given the UTXOs in shared memory
+	SemanticVerify(currentTime uint64) error
+
+	// EVMStateTransfer applies the changes this transaction makes to the
+	// balances of the EVM's accounts to [state]. It returns an error, and
+	// leaves [state] unchanged, if the changes can't be applied.
+	EVMStateTransfer(state *state.StateDB) error
+
+	// Write the changes this transaction makes to the memory shared with the
+	// X-Chain into [sharedDB]
+	AtomicAccept(sharedDB database.Database) error
+}
+
+// parseAtomicTx returns the atomic transaction [b] is the bytes of
+func (vm *VM) parseAtomicTx(b []byte) (AtomicTx, error) {
+	var tx AtomicTx
+	if err := Codec.Unmarshal(b, &tx); err != nil {
+		return nil, err
+	}
+	return tx, tx.initialize(vm)
+}
+
+// atomicTxBytes returns the bytes of the atomic transaction [block] holds. They
+// are the extra data of the block's body, which OnFinalizeAndAssemble sets. The
+// header's Extra field, which holds the random hid, is never read.
+func atomicTxBytes(block *types.Block) []byte { return block.ExtraData() }
+
+// extractAtomicTx returns the atomic transaction [block] holds, or nil if it
+// holds none
+func (vm *VM) extractAtomicTx(block *types.Block) (AtomicTx, error) {
+	txBytes := atomicTxBytes(block)
+	if len(txBytes) == 0 {
+		return nil, nil
+	}
+	return vm.parseAtomicTx(txBytes)
+}
+
+// issueAtomicTx adds [tx] to the atomic transactions that the next blocks this
+// node builds will hold, and asks the engine to build a block
+func (vm *VM) issueAtomicTx(tx AtomicTx) error {
+	if err := tx.SemanticVerify(vm.clock.Unix()); err != nil {
+		return err
+	}
+	if err := vm.addPendingAtomicTx(tx); err != nil {
+		return err
+	}
+	return vm.tryBlockGen()
+}
+
+// addPendingAtomicTx adds [tx] to the pending atomic transactions. It is kept
+// in the database until a block that holds it is accepted or it is dropped, so
+// that it is still pending if this node restarts.
+func (vm *VM) addPendingAtomicTx(tx AtomicTx) error {
+	vm.atomicLock.Lock()
+	defer vm.atomicLock.Unlock()
+
+	if err := vm.pendingAtomicTxDB().Put(tx.ID().Bytes(), tx.Bytes()); err != nil {
+		return err
+	}
+	vm.pendingAtomicTxs = append(vm.pendingAtomicTxs, tx)
+	return nil
+}
+
+// restorePendingAtomicTxs adds the atomic transactions that were pending when
+// this node stopped to the pending ones. Those that are no longer valid are
+// dropped.
+func (vm *VM) restorePendingAtomicTxs() error {
+	vm.atomicLock.Lock()
+	defer vm.atomicLock.Unlock()
+
+	db := vm.pendingAtomicTxDB()
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	dropped := [][]byte(nil)
+	currentTime := vm.clock.Unix()
+	for iter.Next() {
+		tx, err := vm.parseAtomicTx(iter.Value())
+		if err == nil {
+			err = tx.SemanticVerify(currentTime)
+		}
+		if err != nil {
+			vm.ctx.Log.Debug("dropping pending atomic tx due to %s", err)
+			dropped = append(dropped, append([]byte(nil), iter.Key()...))
+			continue
+		}
+		vm.pendingAtomicTxs = append(vm.pendingAtomicTxs, tx)
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	for _, key := range dropped {
+		if err := db.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingAtomicTxDB returns the database, within this chain's, that holds the
+// pending atomic transactions
+func (vm *VM) pendingAtomicTxDB() database.Database {
+	return prefixdb.New([]byte(pendingAtomicTxsPrefix), vm.chaindb.Database)
+}
+
+// dropAtomicTx removes [tx] from the pending atomic transactions in the
+// database, as it can't be applied due to [err]. Assumes [vm.atomicLock] is
+// held.
+func (vm *VM) dropAtomicTx(tx AtomicTx, err error) {
+	vm.ctx.Log.Debug("dropping atomic tx %s due to %s", tx.ID(), err)
+	if err := vm.pendingAtomicTxDB().Delete(tx.ID().Bytes()); err != nil {
+		vm.ctx.Log.Error("couldn't remove dropped atomic tx %s from the database: %s", tx.ID(), err)
+	}
+}
+
+// numPendingAtomicTxs returns the number of issued atomic transactions that no
+// block this node built holds yet
+func (vm *VM) numPendingAtomicTxs() int {
+	vm.atomicLock.Lock()
+	defer vm.atomicLock.Unlock()
+
+	return len(vm.pendingAtomicTxs)
+}
+
+// nextAtomicTx removes the issued atomic transactions from the pending ones
+// until one can be applied to [state], and returns the bytes of that one. It
+// returns nil if none can be applied. The transactions that can't be applied
+// are dropped.
+func (vm *VM) nextAtomicTx(state *state.StateDB) []byte {
+	vm.atomicLock.Lock()
+	defer vm.atomicLock.Unlock()
+
+	currentTime := vm.clock.Unix()
+	for len(vm.pendingAtomicTxs) > 0 {
+		tx := vm.pendingAtomicTxs[0]
+		vm.pendingAtomicTxs = vm.pendingAtomicTxs[1:]
+
+		if err := tx.SemanticVerify(currentTime); err != nil {
+			vm.dropAtomicTx(tx, err)
+			continue
+		}
+		if err := tx.EVMStateTransfer(state); err != nil {
+			vm.dropAtomicTx(tx, err)
+			continue
+		}
+		return tx.Bytes()
+	}
+	return nil
+}
+
+// acceptAtomicTx makes [blk] the last accepted block, removes [tx] from the
+// pending atomic transactions and writes the changes [tx], which [blk] holds,
+// makes to the memory shared with the X-Chain. All are written atomically, so a
+// node that restarts neither misses nor re-applies the changes.
+func (vm *VM) acceptAtomicTx(blk *Block, tx AtomicTx) error {
+	if vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+
+	vm.metalock.Lock()
+	defer vm.metalock.Unlock()
+
+	lastAccepted, err := rlp.EncodeToBytes(blk.ethBlock.Hash())
+	if err != nil {
+		return err
+	}
+	vdb := versiondb.New(vm.chaindb.Database)
+	if err := vdb.Put([]byte(lastAcceptedKey), lastAccepted); err != nil {
+		return err
+	}
+	if err := prefixdb.New([]byte(pendingAtomicTxsPrefix), vdb).Delete(tx.ID().Bytes()); err != nil {
+		return err
+	}
+	batch, err := vdb.CommitBatch()
+	if err != nil {
+		return err
+	}
+
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.avm)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.avm)
+
+	vsmDB := versiondb.New(smDB)
+	if err := tx.AtomicAccept(vsmDB); err != nil {
+		return err
+	}
+	sharedBatch, err := vsmDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := core.WriteAll(batch, sharedBatch); err != nil {
+		return err
+	}
+
+	// Metadata written back after this must not be older than [blk]
+	vm.lastAccepted = blk
+	return nil
+}
+
+// userKey returns the key that controls [addr], from the address book of the
+// keystore user [username]
+func (vm *VM) userKey(username, password string, addr ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
+	sharedDB, err := vm.ctx.Keystore.GetSharedDatabase(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving user: %w", err)
+	}
+	return addressbook.New(sharedDB).Key(addr)
+}
+
+// ethAddress returns the address of the EVM account that [key] controls
+func ethAddress(key crypto.PublicKey) (common.Address, error) {
+	pk, err := ethcrypto.DecompressPubkey(key.Bytes())
+	if err != nil {
+		return common.Address{}, err
+	}
+	return ethcrypto.PubkeyToAddress(*pk), nil
+}
@@ -0,0 +1,410 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ava-labs/coreth/core/rawdb"
+	"github.com/ava-labs/coreth/core/state"
+
+	"github.com/ava-labs/go-ethereum/common"
+	"github.com/ava-labs/go-ethereum/core/types"
+	"github.com/ava-labs/go-ethereum/rlp"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	testNetworkID  uint32 = 10
+	testChainID           = ids.Empty.Prefix(0)
+	testAVMChainID        = ids.Empty.Prefix(1)
+	testAVAAssetID        = ids.Empty.Prefix(2)
+	testTxFee      uint64 = 10
+)
+
+func testKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	skIntf, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return skIntf.(*crypto.PrivateKeySECP256K1R)
+}
+
+// atomicVM returns a VM, without a chain, that can move AVA to and from the
+// X-Chain through the returned shared memory
+func atomicVM() (*VM, *core.SharedMemory) {
+	// The chain's database and shared memory must share a base database for
+	// accepted atomic txs to be written atomically with the chain's state
+	baseDB := memdb.New()
+	sm := &core.SharedMemory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte{1}, baseDB))
+
+	ctx := snow.DefaultContextTest()
+	ctx.NetworkID = testNetworkID
+	ctx.ChainID = testChainID
+	ctx.SharedMemory = sm.NewBlockchainSharedMemory(testChainID)
+
+	return &VM{
+		ctx:     ctx,
+		ava:     testAVAAssetID,
+		avm:     testAVMChainID,
+		txFee:   testTxFee,
+		chaindb: Database{prefixdb.New([]byte{0}, baseDB)},
+	}, sm
+}
+
+// atomicBlock returns a block, at height [height], that holds [tx]
+func atomicBlock(vm *VM, height int64, tx AtomicTx) *Block {
+	ethBlock := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(height)})
+	ethBlock.SetExtraData(tx.Bytes())
+	return &Block{
+		id:       ids.NewID(ethBlock.Hash()),
+		ethBlock: ethBlock,
+		vm:       vm,
+	}
+}
+
+// lastAcceptedHash returns the hash of the last accepted block that [vm] wrote
+// to its database
+func lastAcceptedHash(t *testing.T, vm *VM) common.Hash {
+	b, err := vm.chaindb.Get([]byte(lastAcceptedKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hash common.Hash
+	if err := rlp.DecodeBytes(b, &hash); err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func newStateDB(t *testing.T) *state.StateDB {
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return statedb
+}
+
+// exportToEVM simulates the X-Chain exporting [amount] AVA to [addr]
+func exportToEVM(t *testing.T, vm *VM, sm *core.SharedMemory, addr ids.ShortID, amount uint64) {
+	avmSM := sm.NewBlockchainSharedMemory(testAVMChainID)
+	smDB := avmSM.GetDatabase(vm.ctx.ChainID)
+	defer avmSM.ReleaseDatabase(vm.ctx.ChainID)
+
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: ids.Empty.Prefix(amount)},
+		Asset:  ava.Asset{ID: testAVAAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	if err := avm.NewSharedState(smDB, vm.ctx.ChainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportTx(t *testing.T) {
+	vm, sm := atomicVM()
+
+	key := testKey(t)
+	addr := key.PublicKey().Address()
+	to := common.HexToAddress(GenesisTestAddr)
+	exportToEVM(t, vm, sm, addr, 500)
+
+	utxos, err := vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatalf("Expected %d importable UTXO(s) but found %d", 1, len(utxos))
+	}
+
+	tx, err := vm.newImportTx(utxos, to, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx, err := vm.parseAtomicTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsedTx.ID().Equals(tx.ID()) {
+		t.Fatalf("Parsed tx %s rather than %s", parsedTx.ID(), tx.ID())
+	}
+	if err := parsedTx.SemanticVerify(vm.clock.Unix()); err != nil {
+		t.Fatal(err)
+	}
+
+	statedb := newStateDB(t)
+	if err := parsedTx.EVMStateTransfer(statedb); err != nil {
+		t.Fatal(err)
+	}
+	expected := new(big.Int).Mul(big.NewInt(500-int64(testTxFee)), x2cRate)
+	if balance := statedb.GetBalance(to); balance.Cmp(expected) != 0 {
+		t.Fatalf("Balance should be %s but is %s", expected, balance)
+	}
+
+	blk := atomicBlock(vm, 1, parsedTx)
+	if err := vm.acceptAtomicTx(blk, parsedTx); err != nil {
+		t.Fatal(err)
+	}
+	if hash := lastAcceptedHash(t, vm); hash != blk.ethBlock.Hash() {
+		t.Fatalf("Last accepted block should be %s but is %s", blk.ethBlock.Hash().Hex(), hash.Hex())
+	}
+	if utxos, err := vm.getImportableUTXOs(addr); err != nil {
+		t.Fatal(err)
+	} else if len(utxos) != 0 {
+		t.Fatalf("Shouldn't be able to import the UTXOs twice")
+	}
+	if err := parsedTx.SemanticVerify(vm.clock.Unix()); err == nil {
+		t.Fatalf("Should have failed to verify the import of spent UTXOs")
+	}
+
+	// Accepting the import again fails, as its UTXOs were spent, and doesn't
+	// change the last accepted block
+	if err := vm.acceptAtomicTx(atomicBlock(vm, 2, parsedTx), parsedTx); err == nil {
+		t.Fatalf("Should have failed to accept the import of spent UTXOs")
+	}
+	if hash := lastAcceptedHash(t, vm); hash != blk.ethBlock.Hash() {
+		t.Fatalf("Last accepted block should be %s but is %s", blk.ethBlock.Hash().Hex(), hash.Hex())
+	}
+}
+
+func TestImportTxWrongSigner(t *testing.T) {
+	vm, sm := atomicVM()
+
+	key := testKey(t)
+	addr := key.PublicKey().Address()
+	exportToEVM(t, vm, sm, addr, 500)
+
+	utxos, err := vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := vm.newImportTx(utxos, common.HexToAddress(GenesisTestAddr), testKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SemanticVerify(vm.clock.Unix()); err == nil {
+		t.Fatalf("Should have failed to verify the import of another key's UTXOs")
+	}
+}
+
+func TestExportTx(t *testing.T) {
+	vm, sm := atomicVM()
+
+	key := testKey(t)
+	from, err := ethAddress(key.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := testKey(t).PublicKey().Address()
+
+	statedb := newStateDB(t)
+	statedb.AddBalance(from, new(big.Int).Mul(big.NewInt(500), x2cRate))
+
+	tx, err := vm.newExportTx(100, 0, to, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx, err := vm.parseAtomicTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.SemanticVerify(vm.clock.Unix()); err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.EVMStateTransfer(statedb); err != nil {
+		t.Fatal(err)
+	}
+	expected := new(big.Int).Mul(big.NewInt(500-100-int64(testTxFee)), x2cRate)
+	if balance := statedb.GetBalance(from); balance.Cmp(expected) != 0 {
+		t.Fatalf("Balance should be %s but is %s", expected, balance)
+	}
+	if nonce := statedb.GetNonce(from); nonce != 1 {
+		t.Fatalf("Nonce should be %d but is %d", 1, nonce)
+	}
+
+	// The tx can't be replayed, as its nonce was used
+	if err := parsedTx.EVMStateTransfer(statedb); err != errInvalidNonce {
+		t.Fatalf("Should have failed with %s, but got %v", errInvalidNonce, err)
+	}
+
+	if err := vm.acceptAtomicTx(atomicBlock(vm, 1, parsedTx), parsedTx); err != nil {
+		t.Fatal(err)
+	}
+
+	avmSM := sm.NewBlockchainSharedMemory(testAVMChainID)
+	smDB := avmSM.GetDatabase(testChainID)
+	defer avmSM.ReleaseDatabase(testChainID)
+
+	state := avm.NewSharedState(smDB, testAVMChainID)
+	utxoIDs, err := state.Funds(ids.NewID(hashing.ComputeHash256Array(to.Bytes())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("Expected %d exported UTXO(s), but found %d", 1, len(utxoIDs))
+	}
+	utxo, err := state.UTXO(utxoIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); !ok || out.Amount() != 100 {
+		t.Fatalf("Exported the wrong output")
+	}
+}
+
+func TestExportTxInsufficientFunds(t *testing.T) {
+	vm, _ := atomicVM()
+
+	key := testKey(t)
+	from, err := ethAddress(key.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statedb := newStateDB(t)
+	statedb.AddBalance(from, new(big.Int).Mul(big.NewInt(100), x2cRate))
+
+	// The tx fee is debited on top of the exported AVA
+	tx, err := vm.newExportTx(100, 0, testKey(t).PublicKey().Address(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.EVMStateTransfer(statedb); err != errInsufficientFunds {
+		t.Fatalf("Should have failed with %s, but got %v", errInsufficientFunds, err)
+	}
+	if nonce := statedb.GetNonce(from); nonce != 0 {
+		t.Fatalf("Nonce should be %d but is %d", 0, nonce)
+	}
+}
+
+func TestAtomicTxBlockRoundTrip(t *testing.T) {
+	vm, sm := atomicVM()
+
+	key := testKey(t)
+	addr := key.PublicKey().Address()
+	exportToEVM(t, vm, sm, addr, 500)
+
+	utxos, err := vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newImportTx(utxos, common.HexToAddress(GenesisTestAddr), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The header's Extra field holds the hid, as OnHeaderNew sets it
+	hid := hashing.ComputeHash256(tx.Bytes())
+	header := &types.Header{
+		Number: big.NewInt(1),
+		Extra:  hid,
+	}
+	block := types.NewBlockWithHeader(header)
+	block.SetExtraData(tx.Bytes())
+
+	blockBytes, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedBlock := new(types.Block)
+	if err := rlp.DecodeBytes(blockBytes, parsedBlock); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsedBlock.Extra(), hid) {
+		t.Fatalf("Header extra should be the hid")
+	}
+
+	parsedTx, err := vm.extractAtomicTx(parsedBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsedTx == nil {
+		t.Fatalf("Block should hold an atomic tx")
+	}
+	if !parsedTx.ID().Equals(tx.ID()) {
+		t.Fatalf("Extracted tx %s rather than %s", parsedTx.ID(), tx.ID())
+	}
+
+	emptyBlock := types.NewBlockWithHeader(header)
+	if emptyTx, err := vm.extractAtomicTx(emptyBlock); err != nil {
+		t.Fatal(err)
+	} else if emptyTx != nil {
+		t.Fatalf("Block without extra data shouldn't hold an atomic tx")
+	}
+}
+
+func TestPendingAtomicTxsRestored(t *testing.T) {
+	vm, sm := atomicVM()
+
+	key := testKey(t)
+	addr := key.PublicKey().Address()
+	exportToEVM(t, vm, sm, addr, 500)
+
+	utxos, err := vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newImportTx(utxos, common.HexToAddress(GenesisTestAddr), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.addPendingAtomicTx(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	// restart returns a VM that uses the same databases as [vm]
+	restart := func() *VM {
+		restarted := &VM{
+			ctx:     vm.ctx,
+			ava:     vm.ava,
+			avm:     vm.avm,
+			txFee:   vm.txFee,
+			chaindb: vm.chaindb,
+		}
+		if err := restarted.restorePendingAtomicTxs(); err != nil {
+			t.Fatal(err)
+		}
+		return restarted
+	}
+
+	restarted := restart()
+	if num := restarted.numPendingAtomicTxs(); num != 1 {
+		t.Fatalf("Expected %d pending atomic tx(s) but found %d", 1, num)
+	}
+	if !restarted.pendingAtomicTxs[0].ID().Equals(tx.ID()) {
+		t.Fatalf("Restored tx %s rather than %s", restarted.pendingAtomicTxs[0].ID(), tx.ID())
+	}
+
+	// Once a block that holds the tx is accepted, it is no longer pending
+	if err := restarted.acceptAtomicTx(atomicBlock(restarted, 1, tx), tx); err != nil {
+		t.Fatal(err)
+	}
+	if num := restart().numPendingAtomicTxs(); num != 0 {
+		t.Fatalf("Expected %d pending atomic tx(s) but found %d", 0, num)
+	}
+}
@@ -27,7 +27,27 @@ func (b *Block) ID() ids.ID { return b.id }
 // Accept implements the snowman.Block interface
 func (b *Block) Accept() {
 	b.vm.ctx.Log.Verbo("Block %s is accepted", b.ID())
+
+	// If the changes the atomic tx makes to shared memory can't be written,
+	// this chain and the X-Chain would no longer agree, so the block isn't
+	// accepted
+	tx, err := b.vm.extractAtomicTx(b.ethBlock)
+	if err != nil {
+		b.vm.ctx.Log.Fatal("couldn't parse the atomic tx of block %s: %s", b.ID(), err)
+		return
+	}
+	if tx != nil {
+		if err := b.vm.acceptAtomicTx(b, tx); err != nil {
+			b.vm.ctx.Log.Fatal("couldn't accept atomic tx %s of block %s: %s", tx.ID(), b.ID(), err)
+			return
+		}
+	}
 	b.vm.updateStatus(b.ID(), choices.Accepted)
+
+	b.vm.acceptedHeads.Send(b.ethBlock.Header())
+	if logs := b.vm.blockLogs(b.ethBlock); len(logs) != 0 {
+		b.vm.acceptedLogs.Send(logs)
+	}
 }
 
 // Reject implements the snowman.Block interface
@@ -59,7 +79,34 @@ func (b *Block) Parent() snowman.Block {
 
 // Verify implements the snowman.Block interface
 func (b *Block) Verify() error {
-	_, err := b.vm.chain.InsertChain([]*types.Block{b.ethBlock})
+	tx, err := b.vm.extractAtomicTx(b.ethBlock)
+	if err != nil {
+		return err
+	}
+	if tx != nil {
+		if err := tx.SemanticVerify(b.ethBlock.Time()); err != nil {
+			return err
+		}
+
+		// The UTXOs the tx imports are only removed from shared memory when
+		// the block that holds it is accepted, so they must not be imported
+		// by a block this one extends that is still processing
+		inputs := tx.InputUTXOs()
+		for ancestor := b.Parent().(*Block); ancestor.Status() == choices.Processing; ancestor = ancestor.Parent().(*Block) {
+			ancestorTx, err := b.vm.extractAtomicTx(ancestor.ethBlock)
+			if err != nil {
+				return err
+			}
+			if ancestorTx == nil {
+				continue
+			}
+			if ancestorInputs := ancestorTx.InputUTXOs(); ancestorInputs.Overlaps(inputs) {
+				return errConflictingImport
+			}
+		}
+	}
+
+	_, err = b.vm.chain.InsertChain([]*types.Block{b.ethBlock})
 	return err
 }
 
@@ -0,0 +1,20 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+// Config holds the settings of an EVM chain that may differ between networks.
+// A zero value keeps the setting from the chain's genesis or the node's
+// default.
+type Config struct {
+	// ChainID replaces the chain ID in the chain's genesis
+	ChainID uint64 `json:"chainID"`
+
+	// GasLimit replaces the gas limit of the genesis block and is the gas
+	// limit of the blocks this node builds
+	GasLimit uint64 `json:"gasLimit"`
+
+	// MinGasPrice is the lowest gas price, in wei, of the transactions this
+	// node accepts into its pool and puts into blocks
+	MinGasPrice uint64 `json:"minGasPrice"`
+}
@@ -0,0 +1,215 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ava-labs/coreth/core/state"
+
+	"github.com/ava-labs/go-ethereum/common"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportAmount = errors.New("no $AVA is being exported")
+	errWrongSigner    = errors.New("tx wasn't signed by the key of the account it debits")
+	errInvalidNonce   = errors.New("tx's nonce isn't the nonce of the account it debits")
+	errOutputOverflow = errors.New("exported $AVA and the tx fee overflow")
+)
+
+// UnsignedExportTx is an unsigned ExportTx
+type UnsignedExportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// The account the exported $AVA and the tx fee are debited from. It's the
+	// account of the key that signs this transaction.
+	From common.Address `serialize:"true"`
+
+	// The nonce of [From] that this transaction uses. Like an EVM transaction,
+	// it increments the nonce, so it can't be replayed.
+	Nonce uint64 `serialize:"true"`
+
+	// Amount of $AVA, in nAVA, to export
+	Amount uint64 `serialize:"true"`
+
+	// Address on the X-Chain that will be able to import the $AVA
+	To ids.ShortID `serialize:"true"`
+}
+
+// ExportTx moves $AVA from the balance of an EVM account to the X-Chain
+type ExportTx struct {
+	UnsignedExportTx `serialize:"true"`
+
+	// Signature of the key that controls [From]
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ExportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ExportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.SyntacticVerify() has been called and returned nil
+func (tx *ExportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ExportTx
+func (tx *ExportTx) Bytes() []byte { return tx.bytes }
+
+// InputUTXOs returns an empty set, as the exported $AVA is paid from the
+// balance of an account rather than from UTXOs
+func (tx *ExportTx) InputUTXOs() ids.Set { return ids.Set{} }
+
+// ExportedUTXO returns the UTXO that this transaction places into the memory
+// shared with the X-Chain
+func (tx *ExportTx) ExportedUTXO() *ava.UTXO {
+	return &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: tx.ID()},
+		Asset:  ava.Asset{ID: tx.vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: tx.Amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{tx.To},
+			},
+		},
+	}
+}
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ExportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.Amount == 0:
+		return errNoExportAmount
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	from, err := ethAddress(key)
+	if err != nil {
+		return err
+	}
+	if from != tx.From {
+		return errWrongSigner
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify returns nil if this transaction is well-formed and the
+// $AVA can be exported. Whether [tx.From] can pay for it is only known once
+// the transaction is applied to the EVM's state.
+func (tx *ExportTx) SemanticVerify(uint64) error {
+	if err := tx.SyntacticVerify(); err != nil {
+		return err
+	}
+
+	switch {
+	case tx.vm.avm.IsZero():
+		return errNoAtomicChain
+	case tx.vm.ctx.SharedMemory == nil:
+		return errNoSharedMemory
+	}
+	return nil
+}
+
+// EVMStateTransfer debits the exported $AVA and the tx fee from [tx.From] and
+// increments its nonce
+func (tx *ExportTx) EVMStateTransfer(state *state.StateDB) error {
+	burned, err := math.Add64(tx.Amount, tx.vm.txFee)
+	if err != nil {
+		return errOutputOverflow
+	}
+	amount := new(big.Int).Mul(new(big.Int).SetUint64(burned), x2cRate)
+
+	switch {
+	case state.GetNonce(tx.From) != tx.Nonce:
+		return errInvalidNonce
+	case state.GetBalance(tx.From).Cmp(amount) < 0:
+		return errInsufficientFunds
+	}
+	state.SubBalance(tx.From, amount)
+	state.SetNonce(tx.From, tx.Nonce+1)
+	return nil
+}
+
+// AtomicAccept places the exported UTXO into [sharedDB], the memory shared with
+// the X-Chain
+func (tx *ExportTx) AtomicAccept(sharedDB database.Database) error {
+	return avm.NewSharedState(sharedDB, tx.vm.avm).FundUTXO(tx.ExportedUTXO())
+}
+
+// newExportTx returns a transaction that exports [amount] $AVA from the account
+// of [key], using the account's nonce [nonce], to the address [to] on the
+// X-Chain
+func (vm *VM) newExportTx(amount, nonce uint64, to ids.ShortID, key *crypto.PrivateKeySECP256K1R) (*ExportTx, error) {
+	from, err := ethAddress(key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &ExportTx{
+		UnsignedExportTx: UnsignedExportTx{
+			NetworkID: vm.ctx.NetworkID,
+			From:      from,
+			Nonce:     nonce,
+			Amount:    amount,
+			To:        to,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
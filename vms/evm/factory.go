@@ -13,7 +13,24 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct {
+	Config Config
+
+	// AVA is the asset that can be atomically moved to and from the X-Chain,
+	// whose ID is AVM. If AVM is empty, AVA can't be moved.
+	AVA ids.ID
+	AVM ids.ID
+
+	// AVA, in nAVA, burned by each transaction that moves AVA
+	TxFee uint64
+}
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{
+		config: f.Config, // Use the chain settings from the node's config
+		ava:    f.AVA,
+		avm:    f.AVM,
+		txFee:  f.TxFee,
+	}
+}
@@ -0,0 +1,301 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/coreth/core/state"
+
+	"github.com/ava-labs/go-ethereum/common"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoImportInputs     = errors.New("no import inputs")
+	errInputsNotUnique    = errors.New("inputs are not unique")
+	errNoImportAmount     = errors.New("no $AVA is being imported")
+	errAssetNotAVA        = errors.New("only AVA can be moved to this chain")
+	errWrongUTXOType      = errors.New("UTXO isn't a secp256k1fx transfer output")
+	errUnspendableUTXO    = errors.New("UTXO can't be spent by the signer of the tx")
+	errInsufficientFunds  = errors.New("insufficient funds")
+	errImportAmountTooBig = errors.New("imported $AVA doesn't cover the amount and the tx fee")
+)
+
+// UnsignedImportTx is an unsigned ImportTx
+type UnsignedImportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// The UTXOs that the X-Chain exported to this chain that are consumed by
+	// this transaction
+	Ins []*ava.UTXOID `serialize:"true"`
+
+	// $AVA, in nAVA, credited to [To]. The imported $AVA must pay for it and
+	// the tx fee.
+	Amount uint64 `serialize:"true"`
+
+	// The account the $AVA is credited to
+	To common.Address `serialize:"true"`
+}
+
+// ImportTx moves $AVA that was exported from the X-Chain into the balance of
+// an EVM account
+type ImportTx struct {
+	UnsignedImportTx `serialize:"true"`
+
+	// Signature of the key that is able to spend each of the imported UTXOs
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ImportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ImportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.SyntacticVerify() has been called and returned nil
+func (tx *ImportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ImportTx
+func (tx *ImportTx) Bytes() []byte { return tx.bytes }
+
+// InputUTXOs returns the IDs of the UTXOs this transaction consumes
+func (tx *ImportTx) InputUTXOs() ids.Set {
+	set := ids.Set{}
+	for _, in := range tx.Ins {
+		set.Add(in.InputID())
+	}
+	return set
+}
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ImportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case len(tx.Ins) == 0:
+		return errNoImportInputs
+	case tx.Amount == 0:
+		return errNoImportAmount
+	}
+
+	for _, in := range tx.Ins {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+	}
+	if tx.InputUTXOs().Len() != len(tx.Ins) {
+		return errInputsNotUnique
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify returns nil if the imported UTXOs are in shared memory, the
+// signer of this transaction can spend them at [currentTime], and they pay
+// for the imported amount and the tx fee
+func (tx *ImportTx) SemanticVerify(currentTime uint64) error {
+	if err := tx.SyntacticVerify(); err != nil {
+		return err
+	}
+
+	if tx.vm.avm.IsZero() {
+		return errNoAtomicChain
+	}
+	if tx.vm.ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	smDB := tx.vm.ctx.SharedMemory.GetDatabase(tx.vm.avm)
+	defer tx.vm.ctx.SharedMemory.ReleaseDatabase(tx.vm.avm)
+
+	state := avm.NewSharedState(smDB, tx.vm.ctx.ChainID)
+
+	address := tx.Key().Address()
+	consumed := uint64(0)
+	for _, in := range tx.Ins {
+		utxoID := in.InputID()
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return fmt.Errorf("couldn't find UTXO %s in shared memory: %w", utxoID, err)
+		}
+		out, err := tx.vm.verifySpend(utxo, address, currentTime)
+		if err != nil {
+			return err
+		}
+		if consumed, err = math.Add64(consumed, out.Amount()); err != nil {
+			return err
+		}
+	}
+
+	produced, err := math.Add64(tx.Amount, tx.vm.txFee)
+	if err != nil {
+		return errImportAmountTooBig
+	}
+	if consumed < produced {
+		return errImportAmountTooBig
+	}
+	return nil
+}
+
+// EVMStateTransfer credits the imported $AVA to [tx.To]
+func (tx *ImportTx) EVMStateTransfer(state *state.StateDB) error {
+	amount := new(big.Int).Mul(new(big.Int).SetUint64(tx.Amount), x2cRate)
+	state.AddBalance(tx.To, amount)
+	return nil
+}
+
+// AtomicAccept removes the imported UTXOs from [sharedDB], the memory shared
+// with the X-Chain
+func (tx *ImportTx) AtomicAccept(sharedDB database.Database) error {
+	state := avm.NewSharedState(sharedDB, tx.vm.ctx.ChainID)
+	for _, in := range tx.Ins {
+		if err := state.SpendUTXO(in.InputID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySpend returns the output of [utxo] if it's $AVA that [address] can
+// spend at [currentTime]
+func (vm *VM) verifySpend(utxo *ava.UTXO, address ids.ShortID, currentTime uint64) (*secp256k1fx.TransferOutput, error) {
+	if assetID := utxo.AssetID(); !assetID.Equals(vm.ava) {
+		return nil, errAssetNotAVA
+	}
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return nil, errWrongUTXOType
+	}
+	if out.Locktime > currentTime || out.Threshold != 1 || !containsShortID(out.Addrs, address) {
+		return nil, errUnspendableUTXO
+	}
+	return out, nil
+}
+
+// containsShortID returns true if [addr] is in [addrs]
+func containsShortID(addrs []ids.ShortID, addr ids.ShortID) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// getImportableUTXOs returns the UTXOs that the X-Chain has exported to this
+// chain that [address] can currently spend
+func (vm *VM) getImportableUTXOs(address ids.ShortID) ([]*ava.UTXO, error) {
+	if vm.avm.IsZero() {
+		return nil, errNoAtomicChain
+	}
+	if vm.ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.avm)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.avm)
+
+	state := avm.NewSharedState(smDB, vm.ctx.ChainID)
+
+	// If no UTXOs reference the address, an error is returned
+	utxoIDs, _ := state.Funds(ids.NewID(hashing.ComputeHash256Array(address.Bytes())))
+
+	currentTime := vm.clock.Unix()
+	utxos := []*ava.UTXO{}
+	for _, utxoID := range utxoIDs {
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := vm.verifySpend(utxo, address, currentTime); err == nil {
+			utxos = append(utxos, utxo)
+		}
+	}
+	return utxos, nil
+}
+
+// newImportTx returns a transaction that imports [utxos], which the X-Chain
+// exported to this chain and [key] can spend. The imported $AVA, minus the tx
+// fee, is credited to [to].
+func (vm *VM) newImportTx(utxos []*ava.UTXO, to common.Address, key *crypto.PrivateKeySECP256K1R) (*ImportTx, error) {
+	ins := []*ava.UTXOID{}
+	amount := uint64(0)
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return nil, errWrongUTXOType
+		}
+		var err error
+		if amount, err = math.Add64(amount, out.Amount()); err != nil {
+			return nil, err
+		}
+		ins = append(ins, &utxo.UTXOID)
+	}
+	if amount <= vm.txFee {
+		return nil, errInsufficientFunds
+	}
+
+	tx := &ImportTx{
+		UnsignedImportTx: UnsignedImportTx{
+			NetworkID: vm.ctx.NetworkID,
+			Ins:       ins,
+			Amount:    amount - vm.txFee,
+			To:        to,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
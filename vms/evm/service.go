@@ -15,10 +15,22 @@ import (
 	"github.com/ava-labs/go-ethereum/common/hexutil"
 	"github.com/ava-labs/go-ethereum/core/types"
 	"github.com/ava-labs/go-ethereum/crypto"
+	"github.com/ava-labs/go-ethereum/eth/filters"
+	"github.com/ava-labs/go-ethereum/rpc"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
 )
 
 const (
 	version = "Athereum 1.0"
+
+	// number of accepted headers buffered for each newHeads subscription
+	headsBufferSize = 16
+
+	// number of accepted blocks whose logs are buffered for each logs
+	// subscription
+	logsBufferSize = 16
 )
 
 // test constants
@@ -27,6 +39,215 @@ const (
 	GenesisTestKey  = "0xabd71b35d559563fea757f0f5edbde286fb8c043105b15abb7cd57189306d7d1"
 )
 
+// EthAPI overrides the subscriptions of the eth namespace that must follow
+// consensus rather than block insertion
+type EthAPI struct{ vm *VM }
+
+// NewHeads sends a notification with the header of each block when the block
+// is accepted. Blocks are inserted into the chain when they are verified,
+// before consensus decides them, so the headers of blocks that are later
+// rejected are never sent.
+func (api *EthAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	headers := make(chan *types.Header, headsBufferSize)
+	sub := api.vm.acceptedHeads.Subscribe(headers)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				if err := notifier.Notify(rpcSub.ID, header); err != nil {
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// Logs sends a notification with each log of a block that matches [crit] when
+// the block is accepted. Like NewHeads, it follows consensus rather than block
+// insertion, so the logs of blocks that are later rejected are never sent.
+func (api *EthAPI) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	blockLogs := make(chan []*types.Log, logsBufferSize)
+	sub := api.vm.acceptedLogs.Subscribe(blockLogs)
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-blockLogs:
+				for _, log := range filterLogs(logs, crit) {
+					if err := notifier.Notify(rpcSub.ID, log); err != nil {
+						return
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// filterLogs returns the logs in [logs] that match the addresses and topics of
+// [crit]. A log matches if it was emitted by one of the addresses, or there
+// are none, and each of its topics is one of the topics given for its
+// position, or there are none for that position.
+func filterLogs(logs []*types.Log, crit filters.FilterCriteria) []*types.Log {
+	matched := []*types.Log(nil)
+	for _, log := range logs {
+		if logMatches(log, crit) {
+			matched = append(matched, log)
+		}
+	}
+	return matched
+}
+
+func logMatches(log *types.Log, crit filters.FilterCriteria) bool {
+	if len(crit.Addresses) != 0 && !containsAddress(crit.Addresses, log.Address) {
+		return false
+	}
+	if len(crit.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range crit.Topics {
+		if len(topics) != 0 && !containsHash(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// AvaAPI moves AVA between this chain and the X-Chain
+type AvaAPI struct{ vm *VM }
+
+// ImportAVAArgs are the arguments to ImportAVA
+type ImportAVAArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The X-Chain address, in the user's address book, the AVA was exported to
+	From ids.ShortID `json:"from"`
+
+	// The account the imported AVA is credited to
+	To common.Address `json:"to"`
+}
+
+// AtomicTxReply is the reply to ImportAVA and ExportAVA. The tx isn't gossiped:
+// only the node it was issued to puts it in a block. It stays pending across
+// restarts of that node. If the block it's put in is rejected, that node only
+// puts it in another block after it restarts.
+type AtomicTxReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ImportAVA imports all the AVA that the X-Chain has exported to [args.From],
+// crediting it, minus the tx fee, to [args.To]
+func (api *AvaAPI) ImportAVA(ctx context.Context, args ImportAVAArgs) (*AtomicTxReply, error) {
+	api.vm.ctx.Log.Verbo("ImportAVA called with username: %s", args.Username)
+
+	key, err := api.vm.userKey(args.Username, args.Password, args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	utxos, err := api.vm.getImportableUTXOs(args.From)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving shared UTXOs: %w", err)
+	}
+	if len(utxos) == 0 {
+		return nil, errNoImportInputs
+	}
+
+	tx, err := api.vm.newImportTx(utxos, args.To, key)
+	if err != nil {
+		return nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	if err := api.vm.issueAtomicTx(tx); err != nil {
+		return nil, err
+	}
+	return &AtomicTxReply{TxID: tx.ID()}, nil
+}
+
+// ExportAVAArgs are the arguments to ExportAVA
+type ExportAVAArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The X-Chain address, in the user's address book, whose key controls the
+	// account the AVA is debited from
+	From ids.ShortID `json:"from"`
+
+	// Amount of AVA, in nAVA, to export. The tx fee is debited on top of it.
+	Amount json.Uint64 `json:"amount"`
+
+	// The X-Chain address that will be able to import the AVA
+	To ids.ShortID `json:"to"`
+}
+
+// ExportAVA exports [args.Amount] AVA from the account of [args.From]'s key to
+// [args.To] on the X-Chain
+func (api *AvaAPI) ExportAVA(ctx context.Context, args ExportAVAArgs) (*AtomicTxReply, error) {
+	api.vm.ctx.Log.Verbo("ExportAVA called with username: %s", args.Username)
+
+	key, err := api.vm.userKey(args.Username, args.Password, args.From)
+	if err != nil {
+		return nil, err
+	}
+	from, err := ethAddress(key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+
+	// The nonce follows the account's transactions in the tx pool, which are
+	// placed before the export in the block that holds it
+	nonce := api.vm.chain.GetTxPool().Nonce(from)
+
+	tx, err := api.vm.newExportTx(uint64(args.Amount), nonce, args.To, key)
+	if err != nil {
+		return nil, fmt.Errorf("problem creating transaction: %w", err)
+	}
+	if err := api.vm.issueAtomicTx(tx); err != nil {
+		return nil, err
+	}
+	return &AtomicTxReply{TxID: tx.ID()}, nil
+}
+
 // DebugAPI introduces helper functions for debuging
 type DebugAPI struct{ vm *VM }
 
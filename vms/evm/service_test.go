@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/go-ethereum/common"
+	"github.com/ava-labs/go-ethereum/core/types"
+	"github.com/ava-labs/go-ethereum/rpc"
+)
+
+func newEthAPIClient(t *testing.T, vm *VM) *rpc.Client {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &EthAPI{vm}); err != nil {
+		t.Fatal(err)
+	}
+	return rpc.DialInProc(server)
+}
+
+func TestEthAPINewHeads(t *testing.T) {
+	vm := &VM{}
+	client := newEthAPIClient(t, vm)
+	defer client.Close()
+
+	heads := make(chan *types.Header, 1)
+	sub, err := client.EthSubscribe(context.Background(), heads, "newHeads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		Extra:      []byte{},
+	}
+	vm.acceptedHeads.Send(header)
+
+	select {
+	case head := <-heads:
+		if head.Hash() != header.Hash() {
+			t.Fatalf("Sent the header %s rather than %s", head.Hash().Hex(), header.Hash().Hex())
+		}
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-time.After(time.Second):
+		t.Fatalf("Should have sent the accepted block's header")
+	}
+}
+
+func TestEthAPILogs(t *testing.T) {
+	vm := &VM{}
+	client := newEthAPIClient(t, vm)
+	defer client.Close()
+
+	addr := common.HexToAddress(GenesisTestAddr)
+	otherAddr := common.HexToAddress("0x01")
+	topic := common.HexToHash("0x02")
+	otherTopic := common.HexToHash("0x03")
+
+	logs := make(chan types.Log, 4)
+	sub, err := client.EthSubscribe(context.Background(), logs, "logs", map[string]interface{}{
+		"address": addr,
+		"topics":  [][]common.Hash{{topic}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	newLog := func(addr common.Address, index uint, topics ...common.Hash) *types.Log {
+		return &types.Log{
+			Address: addr,
+			Topics:  topics,
+			Data:    []byte{},
+			Index:   index,
+		}
+	}
+	vm.acceptedLogs.Send([]*types.Log{
+		newLog(addr, 0, topic),
+		newLog(otherAddr, 1, topic),
+		newLog(addr, 2, otherTopic),
+		newLog(addr, 3),
+		newLog(addr, 4, topic, otherTopic),
+	})
+
+	for _, expected := range []uint{0, 4} {
+		select {
+		case log := <-logs:
+			if log.Index != expected {
+				t.Fatalf("Sent log %d rather than log %d", log.Index, expected)
+			}
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatalf("Should have sent log %d", expected)
+		}
+	}
+
+	select {
+	case log := <-logs:
+		t.Fatalf("Sent log %d, which doesn't match the subscription", log.Index)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -15,11 +15,13 @@ import (
 
 	"github.com/ava-labs/coreth"
 	"github.com/ava-labs/coreth/core"
+	"github.com/ava-labs/coreth/core/state"
 	"github.com/ava-labs/coreth/eth"
 	"github.com/ava-labs/coreth/node"
 
 	"github.com/ava-labs/go-ethereum/common"
 	"github.com/ava-labs/go-ethereum/core/types"
+	"github.com/ava-labs/go-ethereum/event"
 	"github.com/ava-labs/go-ethereum/rlp"
 	"github.com/ava-labs/go-ethereum/rpc"
 
@@ -29,13 +31,15 @@ import (
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/timer"
 
 	commonEng "github.com/ava-labs/gecko/snow/engine/common"
 )
 
 const (
-	lastAcceptedKey = "snowman_lastAccepted"
+	lastAcceptedKey        = "snowman_lastAccepted"
+	pendingAtomicTxsPrefix = "snowman_pendingAtomicTxs"
 )
 
 const (
@@ -69,6 +73,21 @@ func maxDuration(x, y time.Duration) time.Duration {
 type VM struct {
 	ctx *snow.Context
 
+	// Settings that override the chain's genesis and the node's defaults
+	config Config
+
+	ava   ids.ID // ID of the AVA asset
+	avm   ids.ID // ID of the X-Chain. If empty, AVA can't be moved to or from it.
+	txFee uint64 // AVA, in nAVA, burned by each atomic tx
+
+	factory crypto.FactorySECP256K1R
+	clock   timer.Clock
+
+	// Atomic txs issued to this node that no block it built holds yet. They
+	// are also kept in the database, under pendingAtomicTxsPrefix.
+	atomicLock       sync.Mutex
+	pendingAtomicTxs []AtomicTx
+
 	chainID           *big.Int
 	networkID         uint64
 	chain             *coreth.ETHChain
@@ -86,6 +105,13 @@ type VM struct {
 	lastAccepted                 *Block
 	writingMetadata              uint32
 
+	// Headers of the blocks that are accepted, sent in the order the blocks
+	// are accepted
+	acceptedHeads event.Feed
+	// Logs of the blocks that are accepted, sent as one slice per block in
+	// the order the blocks are accepted
+	acceptedLogs event.Feed
+
 	bdlock          sync.Mutex
 	blockDelayTimer *timer.Timer
 	bdTimerState    int8
@@ -107,6 +133,7 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	b []byte,
+	configBytes []byte,
 	toEngine chan<- commonEng.Message,
 	fxs []*commonEng.Fx,
 ) error {
@@ -116,11 +143,25 @@ func (vm *VM) Initialize(
 
 	vm.ctx = ctx
 	vm.chaindb = Database{db}
+	if len(configBytes) > 0 {
+		// Settings given when the chain was created take precedence over the
+		// node's
+		if err := json.Unmarshal(configBytes, &vm.config); err != nil {
+			return err
+		}
+	}
+
 	g := new(core.Genesis)
 	err := json.Unmarshal(b, g)
 	if err != nil {
 		return err
 	}
+	if vm.config.ChainID != 0 {
+		g.Config.ChainID = new(big.Int).SetUint64(vm.config.ChainID)
+	}
+	if vm.config.GasLimit != 0 {
+		g.GasLimit = vm.config.GasLimit
+	}
 
 	vm.chainID = g.Config.ChainID
 
@@ -129,6 +170,14 @@ func (vm *VM) Initialize(
 	config.Genesis = g
 	config.Miner.ManualMining = true
 	config.Miner.DisableUncle = true
+	if vm.config.GasLimit != 0 {
+		config.Miner.GasFloor = vm.config.GasLimit
+		config.Miner.GasCeil = vm.config.GasLimit
+	}
+	if vm.config.MinGasPrice != 0 {
+		config.Miner.GasPrice = new(big.Int).SetUint64(vm.config.MinGasPrice)
+		config.TxPool.PriceLimit = vm.config.MinGasPrice
+	}
 	if err := config.SetGCMode("archive"); err != nil {
 		panic(err)
 	}
@@ -144,8 +193,20 @@ func (vm *VM) Initialize(
 		}
 		header.Extra = append(header.Extra, hid...)
 	})
+	chain.SetOnFinalizeAndAssemble(func(state *state.StateDB, txs []*types.Transaction) ([]byte, error) {
+		// The extra data of the block's body is the atomic tx it holds, if
+		// any. It is kept apart from the hid in the header's Extra field.
+		return vm.nextAtomicTx(state), nil
+	})
+	chain.SetOnExtraStateChange(func(block *types.Block, state *state.StateDB) error {
+		tx, err := vm.extractAtomicTx(block)
+		if err != nil || tx == nil {
+			return err
+		}
+		return tx.EVMStateTransfer(state)
+	})
 	chain.SetOnSeal(func(block *types.Block) error {
-		if len(block.Transactions()) == 0 {
+		if len(block.Transactions()) == 0 && len(atomicTxBytes(block)) == 0 {
 			// this could happen due to the async logic of geth tx pool
 			vm.newBlockChan <- nil
 			return errEmptyBlock
@@ -234,6 +295,10 @@ func (vm *VM) Initialize(
 	}
 	vm.ctx.Log.Info(fmt.Sprintf("lastAccepted = %s", vm.lastAccepted.ethBlock.Hash().Hex()))
 
+	if err := vm.restorePendingAtomicTxs(); err != nil {
+		return err
+	}
+
 	// TODO: shutdown this go routine
 	go vm.ctx.Log.RecoverAndPanic(func() {
 		vm.txSubmitChan = vm.chain.GetTxSubmitCh()
@@ -326,10 +391,14 @@ func (vm *VM) LastAccepted() ids.ID {
 func (vm *VM) CreateHandlers() map[string]*commonEng.HTTPHandler {
 	handler := vm.chain.NewRPCHandler()
 	vm.chain.AttachEthService(handler, []string{"eth", "personal", "txpool"})
+	// Registered after the eth service so that its subscriptions replace the
+	// ones of the eth service that follow block insertion
+	handler.RegisterName("eth", &EthAPI{vm})
 	handler.RegisterName("net", &NetAPI{vm})
 	handler.RegisterName("snowman", &SnowmanAPI{vm})
 	handler.RegisterName("web3", &Web3API{})
 	handler.RegisterName("debug", &DebugAPI{vm})
+	handler.RegisterName("ava", &AvaAPI{vm})
 
 	return map[string]*commonEng.HTTPHandler{
 		"/rpc": &commonEng.HTTPHandler{LockOptions: commonEng.NoLock, Handler: handler},
@@ -387,6 +456,7 @@ func (vm *VM) tryBlockGen() error {
 	if err != nil {
 		return err
 	}
+	size += vm.numPendingAtomicTxs()
 	if size == 0 {
 		return nil
 	}
@@ -466,6 +536,16 @@ func (vm *VM) getBlock(id ids.ID) *Block {
 	return block
 }
 
+// blockLogs returns the logs emitted by the transactions of [block], in the
+// order they were emitted
+func (vm *VM) blockLogs(block *types.Block) []*types.Log {
+	logs := []*types.Log(nil)
+	for _, receipt := range vm.chain.BlockChain().GetReceiptsByHash(block.Hash()) {
+		logs = append(logs, receipt.Logs...)
+	}
+	return logs
+}
+
 func (vm *VM) issueRemoteTxs(txs []*types.Transaction) error {
 	errs := vm.chain.AddRemoteTxs(txs)
 	for _, err := range errs {
@@ -28,6 +28,7 @@ type VMFactory interface {
 //   3) Associate a VM with an alias
 //   4) Get the ID of the VM by the VM's alias
 //   5) Get the aliases of a VM
+//   6) List the registered VMs and their versions
 type Manager interface {
 	// Returns a factory that can create new instances of the VM
 	// with the given ID
@@ -45,6 +46,9 @@ type Manager interface {
 
 	// Give an alias to a VM
 	Alias(ids.ID, string) error
+
+	// Return the registered VMs, sorted by ID
+	VMs() []VMInfo
 }
 
 // Implements Manager
@@ -57,6 +61,12 @@ type manager struct {
 	// Value: A factory that creates new instances of that VM
 	vmFactories map[[32]byte]VMFactory
 
+	// Key: The key underlying a VM's ID
+	// Value: The versions of that VM, and the error its chains fail with if
+	// this node can't run it
+	versions    map[[32]byte]Versions
+	versionErrs map[[32]byte]error
+
 	// The node's API server.
 	// [manager] adds routes to this server to expose new API endpoints/services
 	apiServer *api.Server
@@ -68,6 +78,8 @@ type manager struct {
 func NewManager(apiServer *api.Server, log logging.Logger) Manager {
 	m := &manager{
 		vmFactories: make(map[[32]byte]VMFactory),
+		versions:    make(map[[32]byte]Versions),
+		versionErrs: make(map[[32]byte]error),
 		apiServer:   apiServer,
 		log:         log,
 	}
@@ -76,9 +88,12 @@ func NewManager(apiServer *api.Server, log logging.Logger) Manager {
 }
 
 // Return a factory that can create new instances of the vm whose
-// ID is [vmID]
+// ID is [vmID]. Returns an error if this node can't run the vm.
 func (m *manager) GetVMFactory(vmID ids.ID) (VMFactory, error) {
 	if factory, ok := m.vmFactories[vmID.Key()]; ok {
+		if err := m.versionErrs[vmID.Key()]; err != nil {
+			return nil, err
+		}
 		return factory, nil
 	}
 	return nil, fmt.Errorf("no vm with ID '%v' has been registered", vmID)
@@ -86,7 +101,8 @@ func (m *manager) GetVMFactory(vmID ids.ID) (VMFactory, error) {
 }
 
 // Map [vmID] to [factory]. [factory] creates new instances of the vm whose
-// ID is [vmID]
+// ID is [vmID]. A vm this node can't run is still registered, so its chains
+// fail to be created with the reason it can't be run.
 func (m *manager) RegisterVMFactory(vmID ids.ID, factory VMFactory) error {
 	key := vmID.Key()
 	if _, exists := m.vmFactories[key]; exists {
@@ -97,12 +113,39 @@ func (m *manager) RegisterVMFactory(vmID ids.ID, factory VMFactory) error {
 	}
 
 	m.vmFactories[key] = factory
+	versions, err := checkVersions(vmID, factory)
+	m.versions[key] = versions
+	if err != nil {
+		m.log.Warn("%s", err)
+		m.versionErrs[key] = err
+		return nil
+	}
 
 	// add the static API endpoints
 	m.addStaticAPIEndpoints(vmID)
 	return nil
 }
 
+// VMs returns the registered vms, sorted by ID
+func (m *manager) VMs() []VMInfo {
+	vmIDs := make([]ids.ID, 0, len(m.vmFactories))
+	for key := range m.vmFactories {
+		vmIDs = append(vmIDs, ids.NewID(key))
+	}
+	ids.SortIDs(vmIDs)
+
+	vms := make([]VMInfo, len(vmIDs))
+	for i, vmID := range vmIDs {
+		vms[i] = VMInfo{
+			ID:       vmID,
+			Aliases:  m.Aliases(vmID),
+			Versions: m.versions[vmID.Key()],
+			Err:      m.versionErrs[vmID.Key()],
+		}
+	}
+	return vms
+}
+
 // VMs can expose a static API (one that does not depend on the state of a particular chain.)
 // This method adds to the node's API server the static API of the VM with ID [vmID].
 // This allows clients to call the VM's static API methods.
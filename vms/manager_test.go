@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type testFactory struct{}
+
+func (testFactory) New() interface{} { return struct{}{} }
+
+type testVersionedFactory struct {
+	testFactory
+	versions Versions
+}
+
+func (f testVersionedFactory) Versions() (Versions, error) { return f.versions, nil }
+
+func TestRegisterVMFactoryVersions(t *testing.T) {
+	m := NewManager(nil, logging.NoLog{})
+
+	builtinID := ids.NewID([32]byte{1})
+	pluginID := ids.NewID([32]byte{2})
+	oldPluginID := ids.NewID([32]byte{3})
+	factories := map[[32]byte]VMFactory{
+		builtinID.Key():   testFactory{},
+		pluginID.Key():    testVersionedFactory{versions: Versions{VM: "v1.0.0", Interface: InterfaceVersion}},
+		oldPluginID.Key(): testVersionedFactory{versions: Versions{VM: "v0.1.0", Interface: InterfaceVersion - 1}},
+	}
+	for key, factory := range factories {
+		if err := m.RegisterVMFactory(ids.NewID(key), factory); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := m.GetVMFactory(builtinID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetVMFactory(pluginID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.GetVMFactory(oldPluginID); err == nil {
+		t.Fatalf("Should have errored because the VM implements an old interface version")
+	}
+
+	vms := m.VMs()
+	switch {
+	case len(vms) != 3:
+		t.Fatalf("Returned %d VMs, expected 3", len(vms))
+	case !vms[0].ID.Equals(builtinID) || vms[0].Interface != InterfaceVersion || vms[0].Err != nil:
+		t.Fatalf("Returned the wrong info for the built-in VM: %+v", vms[0])
+	case !vms[1].ID.Equals(pluginID) || vms[1].VM != "v1.0.0" || vms[1].Err != nil:
+		t.Fatalf("Returned the wrong info for the plugin VM: %+v", vms[1])
+	case !vms[2].ID.Equals(oldPluginID) || vms[2].VM != "v0.1.0" || vms[2].Err == nil:
+		t.Fatalf("Returned the wrong info for the old plugin VM: %+v", vms[2])
+	}
+}
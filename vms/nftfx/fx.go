@@ -0,0 +1,156 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOutputType     = errors.New("wrong output type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+
+	errWrongNumberOfOutputs     = errors.New("wrong number of outputs for an operation")
+	errWrongNumberOfInputs      = errors.New("wrong number of inputs for an operation")
+	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
+
+	errWrongMintCreated   = errors.New("wrong mint output created from the operation")
+	errWrongUniqueID      = errors.New("wrong unique ID provided")
+	errWrongBytes         = errors.New("wrong bytes provided")
+	errCantTransfer       = errors.New("cant transfer with this fx")
+	errNoNFTsMinted       = errors.New("operation must mint at least one NFT")
+	errMultipleMintsFound = errors.New("operation can only produce one mint output")
+)
+
+// Fx implements non-fungible assets. Ownership checks are delegated to the
+// secp256k1fx.
+type Fx struct{ secp256k1fx.Fx }
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	vm := vmIntf.(secp256k1fx.VM)
+	c := vm.Codec()
+	c.RegisterType(&MintOutput{})
+	c.RegisterType(&TransferOutput{})
+	c.RegisterType(&MintInput{})
+	c.RegisterType(&TransferInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation ...
+func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	switch {
+	case !ok:
+		return errWrongTxType
+	case len(utxosIntf) != 1 || len(insIntf) != 1:
+		return errWrongNumberOfInputs
+	case len(credsIntf) != 1:
+		return errWrongNumberOfCredentials
+	}
+
+	cred, ok := credsIntf[0].(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+
+	switch in := insIntf[0].(type) {
+	case *MintInput:
+		utxo, ok := utxosIntf[0].(*MintOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyMintOperation(tx, utxo, in, cred, outsIntf)
+	case *TransferInput:
+		utxo, ok := utxosIntf[0].(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyTransferOperation(tx, utxo, in, cred, outsIntf)
+	default:
+		return errWrongInputType
+	}
+}
+
+func (fx *Fx) verifyMintOperation(tx secp256k1fx.Tx, utxo *MintOutput, in *MintInput, cred *Credential, outsIntf []interface{}) error {
+	var newMint *MintOutput
+	newNFTs := []*TransferOutput(nil)
+	for _, outIntf := range outsIntf {
+		switch out := outIntf.(type) {
+		case *MintOutput:
+			if newMint != nil {
+				return errMultipleMintsFound
+			}
+			newMint = out
+		case *TransferOutput:
+			newNFTs = append(newNFTs, out)
+		default:
+			return errWrongOutputType
+		}
+	}
+
+	switch {
+	case newMint == nil:
+		return errWrongNumberOfOutputs
+	case len(newNFTs) == 0:
+		return errNoNFTsMinted
+	}
+
+	if err := verify.All(utxo, in, cred, newMint); err != nil {
+		return err
+	}
+
+	if utxo.GroupID != newMint.GroupID || !utxo.Equals(&newMint.OutputOwners) {
+		return errWrongMintCreated
+	}
+
+	for _, nft := range newNFTs {
+		if err := nft.Verify(); err != nil {
+			return err
+		}
+		if nft.GroupID != utxo.GroupID {
+			return errWrongUniqueID
+		}
+	}
+
+	return fx.VerifyCredentials(tx, &in.Input, &cred.Credential, &utxo.OutputOwners)
+}
+
+func (fx *Fx) verifyTransferOperation(tx secp256k1fx.Tx, utxo *TransferOutput, in *TransferInput, cred *Credential, outsIntf []interface{}) error {
+	if len(outsIntf) != 1 {
+		return errWrongNumberOfOutputs
+	}
+	out, ok := outsIntf[0].(*TransferOutput)
+	if !ok {
+		return errWrongOutputType
+	}
+
+	if err := verify.All(utxo, in, cred, out); err != nil {
+		return err
+	}
+
+	switch {
+	case utxo.GroupID != out.GroupID:
+		return errWrongUniqueID
+	case !bytes.Equal(utxo.Payload, out.Payload):
+		return errWrongBytes
+	}
+
+	return fx.VerifyCredentials(tx, &in.Input, &cred.Credential, &utxo.OutputOwners)
+}
+
+// VerifyTransfer ...
+func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransfer }
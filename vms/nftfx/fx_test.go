@@ -0,0 +1,292 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	txBytes  = []byte{0, 1, 2, 3, 4, 5}
+	sigBytes = [crypto.SECP256K1RSigLen]byte{
+		0x0e, 0x33, 0x4e, 0xbc, 0x67, 0xa7, 0x3f, 0xe8,
+		0x24, 0x33, 0xac, 0xa3, 0x47, 0x88, 0xa6, 0x3d,
+		0x58, 0xe5, 0x8e, 0xf0, 0x3a, 0xd5, 0x84, 0xf1,
+		0xbc, 0xa3, 0xb2, 0xd2, 0x5d, 0x51, 0xd6, 0x9b,
+		0x0f, 0x28, 0x5d, 0xcd, 0x3f, 0x71, 0x17, 0x0a,
+		0xf9, 0xbf, 0x2d, 0xb1, 0x10, 0x26, 0x5c, 0xe9,
+		0xdc, 0xc3, 0x9d, 0x7a, 0x01, 0x50, 0x9d, 0xe8,
+		0x35, 0xbd, 0xcb, 0x29, 0x3a, 0xd1, 0x49, 0x32,
+		0x00,
+	}
+	addrBytes = [hashing.AddrLen]byte{
+		0x01, 0x5c, 0xce, 0x6c, 0x55, 0xd6, 0xb5, 0x09,
+		0x84, 0x5c, 0x8c, 0x4e, 0x30, 0xbe, 0xd9, 0x8d,
+		0x39, 0x1a, 0xe7, 0xf0,
+	}
+)
+
+type testVM struct{ clock timer.Clock }
+
+func (vm *testVM) Codec() codec.Codec { return codec.NewDefault() }
+
+func (vm *testVM) Clock() *timer.Clock { return &vm.clock }
+
+type testTx struct{ bytes []byte }
+
+func (tx *testTx) UnsignedBytes() []byte { return tx.bytes }
+
+func owners() secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID(addrBytes),
+		},
+	}
+}
+
+func input() secp256k1fx.Input {
+	return secp256k1fx.Input{SigIndices: []uint32{0}}
+}
+
+func credential() *Credential {
+	return &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+}
+
+func TestFxInitialize(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyMintOperation(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: owners(),
+	}
+	in := &MintInput{Input: input()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: owners(),
+		},
+		&TransferOutput{
+			GroupID:      1,
+			Payload:      []byte{'h', 'e', 'l', 'l', 'o'},
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongGroupID(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: owners(),
+	}
+	in := &MintInput{Input: input()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: owners(),
+		},
+		&TransferOutput{
+			GroupID:      2,
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err == nil {
+		t.Fatalf("Should have errored due to minting into the wrong group")
+	}
+}
+
+func TestFxVerifyMintOperationNoNFTs(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: owners(),
+	}
+	in := &MintInput{Input: input()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err == nil {
+		t.Fatalf("Should have errored due to not minting any NFTs")
+	}
+}
+
+func TestFxVerifyMintOperationChangedOwners(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: owners(),
+	}
+	in := &MintInput{Input: input()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID: 1,
+		},
+		&TransferOutput{
+			GroupID:      1,
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err == nil {
+		t.Fatalf("Should have errored due to modifying the minters")
+	}
+}
+
+func TestFxVerifyTransferOperation(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{2},
+		OutputOwners: owners(),
+	}
+	in := &TransferInput{Input: input()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{2},
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.ShortEmpty},
+			},
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferOperationWrongPayload(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{2},
+		OutputOwners: owners(),
+	}
+	in := &TransferInput{Input: input()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID:      1,
+			Payload:      []byte{3},
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err == nil {
+		t.Fatalf("Should have errored due to a modified payload")
+	}
+}
+
+func TestFxVerifyTransferOperationWrongSigner(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID: 1,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.ShortEmpty},
+		},
+	}
+	in := &TransferInput{Input: input()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID:      1,
+			OutputOwners: owners(),
+		},
+	}
+
+	if err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{credential()}, outs); err == nil {
+		t.Fatalf("Should have errored due to the wrong signer")
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	vm := testVM{}
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyTransfer(nil, nil, nil, nil); err == nil {
+		t.Fatalf("NFTs shouldn't be transferable as fungible outputs")
+	}
+}
+
+func TestTransferOutputPayloadTooLarge(t *testing.T) {
+	out := &TransferOutput{
+		Payload:      make([]byte, MaxPayloadSize+1),
+		OutputOwners: owners(),
+	}
+	if err := out.Verify(); err == nil {
+		t.Fatalf("Should have errored due to the payload being too large")
+	}
+}
@@ -0,0 +1,13 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// MintInput spends a MintOutput to create new NFTs
+type MintInput struct {
+	secp256k1fx.Input `serialize:"true"`
+}
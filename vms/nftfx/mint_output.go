@@ -0,0 +1,24 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// MintOutput grants its owners the ability to mint new NFTs of a group
+type MintOutput struct {
+	GroupID                  uint32 `serialize:"true"`
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// Verify ...
+func (out *MintOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+const (
+	// MaxPayloadSize is the maximum size that can be placed into a payload
+	MaxPayloadSize = 1 << 10
+)
+
+var (
+	errNilOutput         = errors.New("nil output")
+	errPayloadTooLarge   = errors.New("payload too large")
+	errOutputUnspendable = errors.New("output is unspendable")
+)
+
+// TransferOutput is a single NFT of a group, carrying an arbitrary payload
+type TransferOutput struct {
+	GroupID                  uint32 `serialize:"true"`
+	Payload                  []byte `serialize:"true"`
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// Verify ...
+func (out *TransferOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case len(out.Payload) > MaxPayloadSize:
+		return errPayloadTooLarge
+	case len(out.Addrs) == 0:
+		return errOutputUnspendable
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
@@ -20,6 +20,11 @@ type Abort struct {
 //
 // This function also sets onAcceptDB database if the verification passes.
 func (a *Abort) Verify() error {
+	// A block that was already verified isn't verified again
+	if a.onAcceptDB != nil {
+		return nil
+	}
+
 	// Abort is a decision, so its parent must be a proposal
 	if parent, ok := a.parentBlock().(*ProposalBlock); ok {
 		a.onAcceptDB, a.onAcceptFunc = parent.onAbort()
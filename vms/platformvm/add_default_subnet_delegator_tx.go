@@ -11,28 +11,30 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 // UnsignedAddDefaultSubnetDelegatorTx is an unsigned addDefaultSubnetDelegatorTx
 type UnsignedAddDefaultSubnetDelegatorTx struct {
 	DurationValidator `serialize:"true"`
-	NetworkID         uint32      `serialize:"true"`
-	Nonce             uint64      `serialize:"true"`
-	Destination       ids.ShortID `serialize:"true"`
+	NetworkID         uint32                    `serialize:"true"`
+	Ins               []*ava.TransferableInput  `serialize:"true"`
+	Outs              []*ava.TransferableOutput `serialize:"true"`
+	Destination       ids.ShortID               `serialize:"true"`
 }
 
 // addDefaultSubnetDelegatorTx is a transaction that, if it is in a
 // ProposalBlock that is accepted and followed by a Commit block, adds a
 // delegator to the pending validator set of the default subnet. (That is, the
 // validator in the tx will have their weight increase at some point in the
-// future.) The transaction fee will be paid from the account who signed the
-// transaction.
+// future.) The delegated $AVA and the transaction fee will be paid from UTXOs
+// that the signer of the transaction can spend.
 type addDefaultSubnetDelegatorTx struct {
 	UnsignedAddDefaultSubnetDelegatorTx `serialize:"true"`
 
-	// Sig is the signature of the public key whose corresponding account pays
-	// the tx fee for this tx. ie the account with ID == [public key].Address()
-	// pays the tx fee
+	// Sig is the signature of the public key whose address is able to spend
+	// each of the inputs
 	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
 
 	vm       *VM
@@ -55,7 +57,7 @@ func (tx *addDefaultSubnetDelegatorTx) initialize(vm *VM) error {
 func (tx *addDefaultSubnetDelegatorTx) ID() ids.ID { return tx.id }
 
 // SyntacticVerify return nil iff [tx] is valid
-// If [tx] is valid, sets [tx.accountID]
+// If [tx] is valid, sets [tx.senderID]
 func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
@@ -68,16 +70,17 @@ func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 		return errWrongNetworkID
 	case tx.NodeID.IsZero():
 		return errInvalidID
-	case tx.Wght < MinimumStakeAmount: // Ensure validator is staking at least the minimum amount
+	case tx.Wght < tx.vm.stakingParameters().MinStake: // Ensure validator is staking at least the minimum amount
 		return errWeightTooSmall
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.vm.stakingParameters().verifyStakingDuration(tx.Duration()); err != nil {
+		return err
+	}
+
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
 	}
 
 	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetDelegatorTx)
@@ -87,7 +90,7 @@ func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 		return err
 	}
 
-	// get account to pay tx fee from
+	// get the key that is paying the staked $AVA and tx fee
 	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
 	if err != nil {
 		return err
@@ -115,22 +118,6 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 			validatorStartTime)
 	}
 
-	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
-	// to the default subnet, providing the staked $AVA.
-	// The ID of this account is the address associated with the public key that signed this tx
-	accountID := tx.senderID
-	account, err := tx.vm.getAccount(db, accountID)
-	if err != nil {
-		return nil, nil, nil, nil, errDBAccount
-	}
-
-	// The account if this block's proposal is committed and the validator is added
-	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-
 	// Ensure that the period this validator validates the specified subnet is a subnet of the time they validate the default subnet
 	// First, see if they're currently validating the default subnet
 	currentEvents, err := tx.vm.getCurrentValidators(db, DefaultSubnetID)
@@ -164,13 +151,17 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 
 	pendingEvents.Add(tx) // add validator to set of pending validators
 
-	// If this proposal is committed, update the pending validator set to include the validator,
-	// update the validator's account by removing the staked $AVA
+	// If this proposal is committed, update the pending validator set to include the delegator
+	// and spend the delegated $AVA and tx fee
 	onCommitDB := versiondb.New(db)
 	if err := tx.vm.putPendingValidators(onCommitDB, pendingEvents, DefaultSubnetID); err != nil {
 		return nil, nil, nil, nil, err
 	}
-	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
+	burned, err := math.Add64(tx.Weight(), tx.vm.txFee)
+	if err != nil {
+		return nil, nil, nil, nil, errOutputOverflow
+	}
+	if err := tx.vm.semanticVerifySpend(onCommitDB, tx.ID(), tx.senderID, tx.Ins, tx.Outs, burned); err != nil {
 		return nil, nil, nil, nil, err
 	}
 
@@ -187,7 +178,6 @@ func (tx *addDefaultSubnetDelegatorTx) InitiallyPrefersCommit() bool {
 }
 
 func (vm *VM) newAddDefaultSubnetDelegatorTx(
-	nonce,
 	weight,
 	startTime,
 	endTime uint64,
@@ -196,6 +186,11 @@ func (vm *VM) newAddDefaultSubnetDelegatorTx(
 	networkID uint32,
 	key *crypto.PrivateKeySECP256K1R,
 ) (*addDefaultSubnetDelegatorTx, error) {
+	ins, outs, err := vm.spend(vm.DB, key.PublicKey().Address(), weight, vm.txFee)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := &addDefaultSubnetDelegatorTx{
 		UnsignedAddDefaultSubnetDelegatorTx: UnsignedAddDefaultSubnetDelegatorTx{
 			DurationValidator: DurationValidator{
@@ -207,7 +202,8 @@ func (vm *VM) newAddDefaultSubnetDelegatorTx(
 				End:   endTime,
 			},
 			NetworkID:   networkID,
-			Nonce:       nonce,
+			Ins:         ins,
+			Outs:        outs,
 			Destination: destination,
 		},
 	}
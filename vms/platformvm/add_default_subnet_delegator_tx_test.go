@@ -22,7 +22,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 2: Tx ID is nil
 	tx, err := vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -41,7 +40,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 3: Wrong network ID
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -59,7 +57,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 4: Missing Node ID
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -78,7 +75,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 5: Not enough weight
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		MinimumStakeAmount-1,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -96,7 +92,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 6: Validation length is too short
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
@@ -115,7 +110,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 7: Validation length is too long
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
@@ -134,7 +128,6 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 8: Valid
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -158,7 +151,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	// but stops validating non-default subnet after stops validating default subnet
 	// (note that defaultKey is a genesis validator)
 	tx, err := vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix())+1,
@@ -180,7 +172,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	// default subnet validation period
 	// (note that defaultKey is a genesis validator)
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix())+1,
@@ -209,7 +200,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	DSEndTime := DSStartTime.Add(5 * MinimumStakingDuration)
 
 	addDSTx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,                   // nonce
 		defaultStakeAmount,               // stake amount
 		uint64(DSStartTime.Unix()),       // start time
 		uint64(DSEndTime.Unix()),         // end time
@@ -225,7 +215,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 
 	// Case 3: Proposed validator isn't in pending or current validator sets
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(DSStartTime.Unix()),
 		uint64(DSEndTime.Unix()),
@@ -258,7 +247,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	// Case 4: Proposed validator is pending validator of default subnet
 	// but starts validating non-default subnet before default subnet
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(DSStartTime.Unix())-1, // start validating non-default subnet before default subnet
 		uint64(DSEndTime.Unix()),
@@ -279,7 +267,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	// Case 5: Proposed validator is pending validator of default subnet
 	// but stops validating non-default subnet after default subnet
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(DSStartTime.Unix()),
 		uint64(DSEndTime.Unix())+1, // stop validating non-default subnet after stopping validating default subnet
@@ -300,7 +287,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	// Case 6: Proposed validator is pending validator of default subnet
 	// and period validating non-default subnet is subset of time validating default subnet
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(DSStartTime.Unix()), // same start time as for default subnet
 		uint64(DSEndTime.Unix()),   // same end time as for default subnet
@@ -312,10 +298,17 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, _, _, err = tx.SemanticVerify(vm.DB)
+	onCommitDB, _, _, _, err := tx.SemanticVerify(vm.DB)
 	if err != nil {
 		t.Fatalf("should have passed verification")
 	}
+	balance, err := vm.getBalance(onCommitDB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedBalance := defaultBalance - defaultStakeAmount - vm.txFee; balance != expectedBalance {
+		t.Fatalf("delegated $AVA should have been deducted: expected balance %d but was %d", expectedBalance, balance)
+	}
 
 	// Case 7: Proposed validator start validating at/before current timestamp
 	// First, advance the timestamp
@@ -325,9 +318,8 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,              // nonce
-		defaultStakeAmount,          // weight
-		uint64(newTimestamp.Unix()), // start time
+		defaultStakeAmount,                                      // weight
+		uint64(newTimestamp.Unix()),                             // start time
 		uint64(newTimestamp.Add(MinimumStakingDuration).Unix()), // end time
 		defaultKey.PublicKey().Address(),                        // node ID
 		defaultKey.PublicKey().Address(),                        // destination
@@ -348,8 +340,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// Case 7: Account that pays tx fee doesn't have enough $AVA to pay tx fee
-	txFeeSaved := txFee
-	txFee = 1 // Do this so test works even when txFee is 0
 
 	// Create new key whose account has no $AVA
 	factory := crypto.FactorySECP256K1R{}
@@ -358,8 +348,7 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tx, err = vm.newAddDefaultSubnetDelegatorTx(
-		1,                                         // nonce (new account has nonce 0 so use nonce 1)
+	if _, err := vm.newAddDefaultSubnetDelegatorTx(
 		defaultStakeAmount,                        // weight
 		uint64(defaultValidateStartTime.Unix()),   // start time
 		uint64(defaultValidateEndTime.Unix()),     // end time
@@ -367,13 +356,46 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 		defaultKey.PublicKey().Address(),          // destination
 		testNetworkID,                             // network ID
 		newAcctKey.(*crypto.PrivateKeySECP256K1R), // tx fee payer
+	); err != errInsufficientFunds {
+		t.Fatal("should have failed because payer account has no $AVA to pay fee")
+	}
+}
+
+func TestGetValidatorsAggregatesDelegators(t *testing.T) {
+	vm := defaultVM()
+
+	delTx, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		testNetworkID,
+		defaultKey,
 	)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, _, _, err = tx.SemanticVerify(vm.DB)
-	if err == nil {
-		t.Fatal("should have failed verification because payer account has no $AVA to pay fee")
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
 	}
-	txFee = txFeeSaved // Reset tx fee
+	numValidators := currentValidators.Len()
+	currentValidators.Add(delTx)
+
+	vdrs := vm.getValidators(currentValidators)
+	if len(vdrs) != numValidators {
+		t.Fatalf("delegator shouldn't have been reported as a separate validator")
+	}
+	for _, vdr := range vdrs {
+		if !vdr.ID().Equals(defaultKey.PublicKey().Address()) {
+			continue
+		}
+		if expectedWeight := 2 * defaultStakeAmount; vdr.Weight() != expectedWeight {
+			t.Fatalf("expected weight %d but was %d", expectedWeight, vdr.Weight())
+		}
+		return
+	}
+	t.Fatalf("delegated validator wasn't reported")
 }
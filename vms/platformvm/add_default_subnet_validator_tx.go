@@ -13,12 +13,15 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 var (
 	errNilTx          = errors.New("nil tx is invalid")
 	errWrongNetworkID = errors.New("tx was issued with a different network ID")
 	errWeightTooSmall = errors.New("weight of this validator is too low")
+	errWeightTooLarge = errors.New("weight of this validator is too high")
 	errStakeTooShort  = errors.New("staking period is too short")
 	errStakeTooLong   = errors.New("staking period is too long")
 	errTooManyShares  = fmt.Errorf("a staker can only require at most %d shares from delegators", NumberOfShares)
@@ -27,10 +30,11 @@ var (
 // UnsignedAddDefaultSubnetValidatorTx is an unsigned addDefaultSubnetValidatorTx
 type UnsignedAddDefaultSubnetValidatorTx struct {
 	DurationValidator `serialize:"true"`
-	NetworkID         uint32      `serialize:"true"`
-	Nonce             uint64      `serialize:"true"`
-	Destination       ids.ShortID `serialize:"true"`
-	Shares            uint32      `serialize:"true"`
+	NetworkID         uint32                    `serialize:"true"`
+	Ins               []*ava.TransferableInput  `serialize:"true"`
+	Outs              []*ava.TransferableOutput `serialize:"true"`
+	Destination       ids.ShortID               `serialize:"true"`
+	Shares            uint32                    `serialize:"true"`
 }
 
 // addDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
@@ -62,7 +66,7 @@ func (tx *addDefaultSubnetValidatorTx) initialize(vm *VM) error {
 func (tx *addDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
 
 // SyntacticVerify that this transaction is well formed
-// If [tx] is valid, this method also populates [tx.accountID]
+// If [tx] is valid, this method also populates [tx.senderID]
 func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
@@ -77,18 +81,21 @@ func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
 		return errInvalidID
 	case tx.Destination.IsZero():
 		return errInvalidID
-	case tx.Wght < MinimumStakeAmount: // Ensure validator is staking at least the minimum amount
+	case tx.Wght < tx.vm.stakingParameters().MinStake: // Ensure validator is staking at least the minimum amount
 		return errWeightTooSmall
+	case tx.Wght > tx.vm.stakingParameters().MaxStake: // Ensure validator is staking at most the maximum amount
+		return errWeightTooLarge
 	case tx.Shares > NumberOfShares: // Ensure delegators shares are in the allowed amount
 		return errTooManyShares
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.vm.stakingParameters().verifyStakingDuration(tx.Duration()); err != nil {
+		return err
+	}
+
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
 	}
 
 	// Byte representation of the unsigned transaction
@@ -125,26 +132,6 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 			startTime)
 	}
 
-	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
-	// to the default subnet, providing the staked $AVA.
-	// The ID of this account is the address associated with the public key that signed this tx
-	accountID := tx.senderID
-	account, err := tx.vm.getAccount(db, accountID)
-	if err != nil {
-		return nil, nil, nil, nil, errDBAccount
-	}
-
-	// If the transaction adds a validator to the default subnet, also deduct
-	// staked $AVA
-	amount := tx.Weight()
-
-	// The account if this block's proposal is committed and the validator is added
-	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(amount, tx.Nonce)
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-
 	// Ensure the proposed validator is not already a validator of the specified subnet
 	currentEvents, err := tx.vm.getCurrentValidators(db, DefaultSubnetID)
 	if err != nil {
@@ -171,13 +158,17 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 
 	pendingEvents.Add(tx) // add validator to set of pending validators
 
-	// If this proposal is committed, update the pending validator set to include the validator,
-	// update the validator's account by removing the staked $AVA
+	// If this proposal is committed, update the pending validator set to include the validator
+	// and spend the staked $AVA and tx fee
 	onCommitDB := versiondb.New(db)
 	if err := tx.vm.putPendingValidators(onCommitDB, pendingEvents, DefaultSubnetID); err != nil {
 		return nil, nil, nil, nil, err
 	}
-	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
+	burned, err := math.Add64(tx.Weight(), tx.vm.txFee)
+	if err != nil {
+		return nil, nil, nil, nil, errOutputOverflow
+	}
+	if err := tx.vm.semanticVerifySpend(onCommitDB, tx.ID(), tx.senderID, tx.Ins, tx.Outs, burned); err != nil {
 		return nil, nil, nil, nil, err
 	}
 
@@ -197,8 +188,13 @@ func (tx *addDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
 }
 
 // NewAddDefaultSubnetValidatorTx returns a new NewAddDefaultSubnetValidatorTx
-func (vm *VM) newAddDefaultSubnetValidatorTx(nonce, stakeAmt, startTime, endTime uint64, nodeID, destination ids.ShortID, shares, networkID uint32, key *crypto.PrivateKeySECP256K1R,
+func (vm *VM) newAddDefaultSubnetValidatorTx(stakeAmt, startTime, endTime uint64, nodeID, destination ids.ShortID, shares, networkID uint32, key *crypto.PrivateKeySECP256K1R,
 ) (*addDefaultSubnetValidatorTx, error) {
+	ins, outs, err := vm.spend(vm.DB, key.PublicKey().Address(), stakeAmt, vm.txFee)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := &addDefaultSubnetValidatorTx{
 		UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
 			NetworkID: networkID,
@@ -210,7 +206,8 @@ func (vm *VM) newAddDefaultSubnetValidatorTx(nonce, stakeAmt, startTime, endTime
 				Start: startTime,
 				End:   endTime,
 			},
-			Nonce:       nonce,
+			Ins:         ins,
+			Outs:        outs,
 			Destination: destination,
 			Shares:      shares,
 		},
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 )
 
@@ -21,7 +22,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 2: ID is nil
 	tx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -41,7 +41,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 3: Wrong Network ID
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -60,7 +59,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 4: Node ID is nil
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -80,7 +78,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 5: Destination ID is nil
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -100,7 +97,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 6: Stake amount too small
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		MinimumStakeAmount-1,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -119,7 +115,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 7: Too many shares
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -138,7 +133,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 8.1: Validation length is too short
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
@@ -157,7 +151,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 8.2: Validation length is negative
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Unix())-1,
@@ -176,7 +169,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 9: Validation length is too long
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
@@ -195,7 +187,6 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 10: Valid
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -213,13 +204,84 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	}
 }
 
+// Test that AddDefaultSubnetValidatorTx.SyntacticVerify enforces the VM's
+// staking parameters
+func TestAddDefaultSubnetValidatorTxStakingParameters(t *testing.T) {
+	vm := defaultVM()
+	// The test key can afford to stake at most defaultStakeAmount, so the
+	// maximum stake is below that
+	vm.staking = StakingParameters{
+		MinStake:         defaultStakeAmount - 1,
+		MaxStake:         defaultStakeAmount - 1,
+		MinStakeDuration: defaultValidateEndTime.Sub(defaultValidateStartTime),
+		MaxStakeDuration: defaultValidateEndTime.Sub(defaultValidateStartTime),
+	}
+	if err := vm.staking.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Case 1: Stake amount too large
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errWeightTooLarge {
+		t.Fatalf("should have errored with %s but got %v", errWeightTooLarge, err)
+	}
+
+	// Case 2: Validation length is too short for this VM, though not for the
+	// default staking parameters
+	tx, err = vm.newAddDefaultSubnetValidatorTx(
+		defaultStakeAmount-1,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix())-1,
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errStakeTooShort {
+		t.Fatalf("should have errored with %s but got %v", errStakeTooShort, err)
+	}
+
+	// Case 3: Valid
+	tx, err = vm.newAddDefaultSubnetValidatorTx(
+		defaultStakeAmount-1,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Test AddDefaultSubnetValidatorTx.SemanticVerify
 func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	vm := defaultVM()
 
 	// Case 1: Validator's start time too early
 	tx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix())-1,
 		uint64(defaultValidateEndTime.Unix()),
@@ -237,9 +299,22 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// Case 2: Validator doesn't have enough $AVA to cover stake amount
+	if _, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultBalance-vm.txFee+1,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		defaultKey.PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	); err != errInsufficientFunds {
+		t.Fatal("should've errored because validator doesn't have enough $AVA to cover stake")
+	}
+
+	// Case 2.1: Validator's stake was already spent
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
-		defaultBalance-txFee+1,
+		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		defaultKey.PublicKey().Address(),
@@ -251,13 +326,18 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
-		t.Fatal("should've errored because validator doesn't have enough $AVA to cover stake")
+	spentDB := versiondb.New(vm.DB)
+	for _, in := range tx.Ins {
+		if err := vm.removeUTXO(spentDB, in.InputID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, _, _, err := tx.SemanticVerify(spentDB); err == nil {
+		t.Fatal("should've errored because the validator's stake was already spent")
 	}
 
 	// Case 3: Validator already validating default subnet
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -281,7 +361,6 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 	startTime := defaultGenesisTime.Add(1 * time.Second)
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,           // nonce
 		defaultStakeAmount,       // stake amount
 		uint64(startTime.Unix()), // start time
 		uint64(startTime.Add(MinimumStakingDuration).Unix()), // end time
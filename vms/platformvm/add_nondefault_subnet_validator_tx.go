@@ -13,6 +13,7 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 var (
@@ -29,15 +30,18 @@ type UnsignedAddNonDefaultSubnetValidatorTx struct {
 	// ID of the network
 	NetworkID uint32 `serialize:"true"`
 
-	// Next unused nonce of the account paying the tx fee
-	Nonce uint64 `serialize:"true"`
+	// The UTXOs the tx fee is paid from
+	Ins []*ava.TransferableInput `serialize:"true"`
+
+	// The UTXOs this transaction produces, e.g. change
+	Outs []*ava.TransferableOutput `serialize:"true"`
 }
 
 // addNonDefaultSubnetValidatorTx is a transaction that, if it is in a ProposeAddValidator block that
 // is accepted and followed by a Commit block, adds a validator to the pending validator set of a subnet
 // other than the default subnet.
 // (That is, the validator in the tx will validate at some point in the future.)
-// The transaction fee will be paid from the account whose ID is [Sigs[0].Address()]
+// The transaction fee will be paid from UTXOs that the signer of [PayerSig] can spend
 type addNonDefaultSubnetValidatorTx struct {
 	UnsignedAddNonDefaultSubnetValidatorTx `serialize:"true"`
 
@@ -47,9 +51,8 @@ type addNonDefaultSubnetValidatorTx struct {
 	// Each element of ControlSigs is the signature of one of those keys
 	ControlSigs [][crypto.SECP256K1RSigLen]byte `serialize:"true"`
 
-	// PayerSig is the signature of the public key whose corresponding account pays
-	// the tx fee for this tx
-	// ie the account with ID == [public key].Address() pays the tx fee
+	// PayerSig is the signature of the public key that pays the tx fee for
+	// this tx, ie [public key].Address() must be able to spend each input
 	PayerSig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
 
 	vm         *VM
@@ -76,7 +79,7 @@ func (tx *addNonDefaultSubnetValidatorTx) initialize(vm *VM) error {
 func (tx *addNonDefaultSubnetValidatorTx) ID() ids.ID { return tx.id }
 
 // SyntacticVerify return nil iff [tx] is valid
-// If [tx] is valid, sets [tx.accountID]
+// If [tx] is valid, sets [tx.senderID]
 func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
@@ -98,11 +101,12 @@ func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 	}
 
 	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
+	if err := tx.vm.stakingParameters().verifyStakingDuration(tx.Duration()); err != nil {
+		return err
+	}
+
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
 	}
 
 	// Byte representation of the unsigned transaction
@@ -113,22 +117,26 @@ func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 	}
 	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
 
-	tx.controlIDs = make([]ids.ShortID, len(tx.ControlSigs))
-	// recover control signatures
-	for i, sig := range tx.ControlSigs {
-		key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:])
-		if err != nil {
-			return err
-		}
-		tx.controlIDs[i] = key.Address()
+	// recover the control signatures and the signature of the key that is
+	// paying the tx fee, which is last, in one batch
+	hashes := make([][]byte, len(tx.ControlSigs)+1)
+	sigs := make([][]byte, len(tx.ControlSigs)+1)
+	for i := range tx.ControlSigs {
+		hashes[i] = unsignedBytesHash
+		sigs[i] = tx.ControlSigs[i][:]
 	}
-
-	// get account to pay tx fee from
-	key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
+	hashes[len(tx.ControlSigs)] = unsignedBytesHash
+	sigs[len(tx.ControlSigs)] = tx.PayerSig[:]
+	keys, err := tx.vm.factory.RecoverHashPublicKeys(hashes, sigs)
 	if err != nil {
 		return err
 	}
-	tx.senderID = key.Address()
+
+	tx.controlIDs = make([]ids.ShortID, len(tx.ControlSigs))
+	for i, key := range keys[:len(tx.ControlSigs)] {
+		tx.controlIDs[i] = key.Address()
+	}
+	tx.senderID = keys[len(tx.ControlSigs)].Address()
 
 	return nil
 }
@@ -233,22 +241,6 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 			validatorStartTime)
 	}
 
-	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
-	// to the default subnet, providing the staked $AVA.
-	// The ID of this account is the address associated with the public key that signed this tx
-	accountID := tx.senderID
-	account, err := tx.vm.getAccount(db, accountID)
-	if err != nil {
-		return nil, nil, nil, nil, errDBAccount
-	}
-
-	// The account if this block's proposal is committed and the validator is added
-	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
-	if err != nil {
-		return nil, nil, nil, nil, err
-	}
-
 	// Ensure the proposed validator is not already a validator of the specified subnet
 	currentEvents, err := tx.vm.getCurrentValidators(db, tx.Subnet)
 	if err != nil {
@@ -279,14 +271,14 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 
 	pendingEvents.Add(tx) // add validator to set of pending validators
 
-	// If this proposal is committed, update the pending validator set to include the validator,
-	// update the validator's account by removing the staked $AVA
+	// If this proposal is committed, update the pending validator set to include the validator
+	// and spend the tx fee
 	onCommitDB := versiondb.New(db)
 	if err := tx.vm.putPendingValidators(onCommitDB, pendingEvents, tx.Subnet); err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't put current validators: %v", err)
 	}
-	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
-		return nil, nil, nil, nil, fmt.Errorf("couldn't put account: %v", err)
+	if err := tx.vm.semanticVerifySpend(onCommitDB, tx.ID(), tx.senderID, tx.Ins, tx.Outs, tx.vm.txFee); err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	// If this proposal is aborted, chain state doesn't change
@@ -302,7 +294,6 @@ func (tx *addNonDefaultSubnetValidatorTx) InitiallyPrefersCommit() bool {
 }
 
 func (vm *VM) newAddNonDefaultSubnetValidatorTx(
-	nonce,
 	weight,
 	startTime,
 	endTime uint64,
@@ -312,6 +303,11 @@ func (vm *VM) newAddNonDefaultSubnetValidatorTx(
 	controlKeys []*crypto.PrivateKeySECP256K1R,
 	payerKey *crypto.PrivateKeySECP256K1R,
 ) (*addNonDefaultSubnetValidatorTx, error) {
+	ins, outs, err := vm.spend(vm.DB, payerKey.PublicKey().Address(), 0, vm.txFee)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := &addNonDefaultSubnetValidatorTx{
 		UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
 			SubnetValidator: SubnetValidator{
@@ -326,7 +322,8 @@ func (vm *VM) newAddNonDefaultSubnetValidatorTx(
 				Subnet: subnetID,
 			},
 			NetworkID: networkID,
-			Nonce:     nonce,
+			Ins:       ins,
+			Outs:      outs,
 		},
 	}
 
@@ -23,7 +23,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 2: Tx ID is nil
 	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -43,7 +42,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 3: Wrong network ID
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -62,7 +60,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 4: Missing Node ID
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -82,7 +79,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 5: Missing Subnet ID
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -102,7 +98,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 6: No weight
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		0,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -121,7 +116,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 7: ControlSigs not sorted
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix())-1,
@@ -142,7 +136,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 8: Validation length is too short
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
@@ -162,7 +155,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 9: Validation length is too long
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
@@ -182,7 +174,6 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 
 	// Case 10: Valid
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -207,7 +198,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// but stops validating non-default subnet after stops validating default subnet
 	// (note that defaultKey is a genesis validator)
 	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix())+1,
@@ -230,7 +220,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// default subnet validation period
 	// (note that defaultKey is a genesis validator)
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
@@ -269,7 +258,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	DSEndTime := DSStartTime.Add(5 * MinimumStakingDuration)
 
 	addDSTx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,                   // nonce
 		defaultStakeAmount,               // stake amount
 		uint64(DSStartTime.Unix()),       // start time
 		uint64(DSEndTime.Unix()),         // end time
@@ -285,7 +273,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 
 	// Case 3: Proposed validator isn't in pending or current validator sets
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(DSStartTime.Unix()), // start validating non-default subnet before default subnet
 		uint64(DSEndTime.Unix()),
@@ -319,7 +306,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Case 4: Proposed validator is pending validator of default subnet
 	// but starts validating non-default subnet before default subnet
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(DSStartTime.Unix())-1, // start validating non-default subnet before default subnet
 		uint64(DSEndTime.Unix()),
@@ -341,7 +327,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Case 5: Proposed validator is pending validator of default subnet
 	// but stops validating non-default subnet after default subnet
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(DSStartTime.Unix()),
 		uint64(DSEndTime.Unix())+1, // stop validating non-default subnet after stopping validating default subnet
@@ -363,7 +348,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Case 6: Proposed validator is pending validator of default subnet
 	// and period validating non-default subnet is subset of time validating default subnet
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(DSStartTime.Unix()), // same start time as for default subnet
 		uint64(DSEndTime.Unix()),   // same end time as for default subnet
@@ -389,7 +373,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,              // nonce
 		defaultWeight,               // weight
 		uint64(newTimestamp.Unix()), // start time
 		uint64(newTimestamp.Add(MinimumStakingDuration).Unix()), // end time
@@ -413,8 +396,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// Case 7: Account that pays tx fee doesn't have enough $AVA to pay tx fee
-	txFeeSaved := txFee
-	txFee = 1 // Do this so test works even when txFee is 0
 
 	// Create new key whose account has no $AVA
 	factory := crypto.FactorySECP256K1R{}
@@ -423,8 +404,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		1,                                       // nonce (new account has nonce 0 so use nonce 1)
+	if _, err := vm.newAddNonDefaultSubnetValidatorTx(
 		defaultWeight,                           // weight
 		uint64(defaultValidateStartTime.Unix()), // start time
 		uint64(defaultValidateEndTime.Unix()),   // end time
@@ -433,20 +413,13 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		testNetworkID,                           // network ID
 		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
 		newAcctKey.(*crypto.PrivateKeySECP256K1R), // tx fee payer
-	)
-	if err != nil {
-		t.Fatal(err)
-	}
-	_, _, _, _, err = tx.SemanticVerify(vm.DB)
-	if err == nil {
-		t.Fatal("should have failed verification because payer account has no $AVA to pay fee")
+	); err != errInsufficientFunds {
+		t.Fatal("should have failed because payer account has no $AVA to pay fee")
 	}
-	txFee = txFeeSaved // Reset tx fee
 
 	// Case 8: Proposed validator already validating the non-default subnet
 	// First, add validator as validator of non-default subnet
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                          // nonce
 		defaultWeight,                           // weight
 		uint64(defaultValidateStartTime.Unix()), // start time
 		uint64(defaultValidateEndTime.Unix()),   // end time
@@ -470,7 +443,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Node with ID nodeIDKey.PublicKey().Address() now validating subnet with ID testSubnet1.ID
 
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                          // nonce
 		defaultWeight,                           // weight
 		uint64(defaultValidateStartTime.Unix()), // start time
 		uint64(defaultValidateEndTime.Unix()),   // end time
@@ -499,7 +471,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 
 	// Case 9: Too many signatures
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix())+1, // end time
@@ -520,7 +491,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 
 	// Case 10: Too few signatures
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()), // end time
@@ -541,7 +511,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 
 	// Case 10: Control Signature from invalid key
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()), // end time
@@ -563,7 +532,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Case 11: Proposed validator in pending validator set for subnet
 	// First, add validator to pending validator set of subnet
 	tx, err = vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,                      // nonce
 		defaultWeight,                       // weight
 		uint64(defaultGenesisTime.Unix())+1, // start time
 		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix())+1, // end time
@@ -599,7 +567,6 @@ func TestAddNonDefaultSubnetValidatorMarshal(t *testing.T) {
 
 	// valid tx
 	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
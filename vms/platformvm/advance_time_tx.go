@@ -16,7 +16,8 @@ import (
 // followed by a commit block) the staker set is also updated accordingly.
 // It must be that:
 //   * proposed timestamp > [current chain time]
-//   * proposed timestamp <= [time for next staker to be removed]
+//   * proposed timestamp <= [time for next staker to be added or removed]
+//   * proposed timestamp <= [local time] + [maximum clock drift]
 type advanceTimeTx struct {
 	// Unix time this block proposes increasing the timestamp to
 	Time uint64 `serialize:"true"`
@@ -37,7 +38,7 @@ func (tx *advanceTimeTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
-	case tx.vm.clock.Time().Add(Delta).Before(tx.Timestamp()):
+	case tx.vm.clock.Time().Add(tx.vm.clockDrift()).Before(tx.Timestamp()):
 		return errTimeTooAdvanced
 	default:
 		return nil
@@ -127,8 +128,11 @@ func (tx *advanceTimeTx) SemanticVerify(db database.Database) (*versiondb.Databa
 			return
 		}
 		for _, subnet := range subnets {
+			if !tx.vm.validates(subnet.ID) {
+				continue
+			}
 			if err := tx.vm.updateValidators(subnet.ID); err != nil {
-				tx.vm.Ctx.Log.Debug("failed to update validators on the default subnet: %s", err)
+				tx.vm.Ctx.Log.Error("failed to update validators on subnet %s: %s", subnet.ID, err)
 			}
 		}
 		if err := tx.vm.updateValidators(DefaultSubnetID); err != nil {
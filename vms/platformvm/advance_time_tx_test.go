@@ -35,6 +35,26 @@ func TestAdvanceTimeTxSyntacticVerify(t *testing.T) {
 	}
 }
 
+// Ensure the maximum clock drift bounds how far ahead of local time a proposed
+// timestamp may be
+func TestAdvanceTimeTxMaxClockDrift(t *testing.T) {
+	vm := defaultVM()
+	vm.maxClockDrift = time.Minute
+
+	tx := &advanceTimeTx{
+		Time: uint64(defaultGenesisTime.Add(time.Minute).Unix()),
+		vm:   vm,
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatalf("should've passed verification but got: %v", err)
+	}
+
+	tx.Time = uint64(defaultGenesisTime.Add(time.Minute).Add(1 * time.Second).Unix())
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should've failed verification because timestamp is further ahead of local time than the maximum drift")
+	}
+}
+
 // Ensure semantic verification fails when proposed timestamp is at or before current timestamp
 func TestAdvanceTimeTxTimestampTooEarly(t *testing.T) {
 	vm := defaultVM()
@@ -60,7 +80,6 @@ func TestAdvanceTimeTxTimestampTooLate(t *testing.T) {
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(pendingValidatorStartTime.Unix()),
 		uint64(pendingValidatorEndTime.Unix()),
@@ -125,7 +144,6 @@ func TestAdvanceTimeTxUpdateValidators(t *testing.T) {
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(pendingValidatorStartTime.Unix()),
 		uint64(pendingValidatorEndTime.Unix()),
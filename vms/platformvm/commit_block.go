@@ -20,6 +20,11 @@ type Commit struct {
 //
 // This function also sets the onCommit databases if the verification passes.
 func (c *Commit) Verify() error {
+	// A block that was already verified isn't verified again
+	if c.onAcceptDB != nil {
+		return nil
+	}
+
 	// the parent of an Commit block should always be a proposal
 	if parent, ok := c.parentBlock().(*ProposalBlock); ok {
 		c.onAcceptDB, c.onAcceptFunc = parent.onCommit()
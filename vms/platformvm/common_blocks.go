@@ -182,6 +182,11 @@ type CommonDecisionBlock struct {
 
 	// to be executed if this block is accepted
 	onAcceptFunc func()
+
+	// writes the changes to the state of the chain along with any changes this
+	// block makes to shared memory. If nil, only the state of the chain is
+	// written.
+	onAcceptWrite func(batch database.Batch) error
 }
 
 // initialize this block
@@ -208,6 +213,24 @@ func (cdb *CommonDecisionBlock) onAccept() database.Database {
 	return cdb.onAcceptDB
 }
 
+// commit the vm's database to the underlying database, along with any changes
+// this block makes to shared memory
+func (cdb *CommonDecisionBlock) commit() error {
+	if cdb.onAcceptWrite == nil {
+		return cdb.vm.DB.Commit()
+	}
+
+	batch, err := cdb.vm.DB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := cdb.onAcceptWrite(batch); err != nil {
+		return err
+	}
+	cdb.vm.DB.Abort()
+	return nil
+}
+
 // Accept implements the snowman.Block interface
 func (cdb *CommonDecisionBlock) Accept() {
 	cdb.VM.Ctx.Log.Verbo("Accepting block with ID %s", cdb.ID())
@@ -218,7 +241,7 @@ func (cdb *CommonDecisionBlock) Accept() {
 	if err := cdb.onAcceptDB.Commit(); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit onAcceptDB")
 	}
-	if err := cdb.vm.DB.Commit(); err != nil {
+	if err := cdb.commit(); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit vm's DB")
 	}
 
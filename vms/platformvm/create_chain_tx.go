@@ -7,11 +7,11 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 var (
@@ -24,9 +24,14 @@ type UnsignedCreateChainTx struct {
 	// ID of the network this blockchain exists on
 	NetworkID uint32 `serialize:"true"`
 
-	// Next unused nonce of account paying the transaction fee for this transaction.
-	// Currently unused, as there are no tx fees.
-	Nonce uint64 `serialize:"true"`
+	// The UTXOs the transaction fee is paid from
+	Ins []*ava.TransferableInput `serialize:"true"`
+
+	// The UTXOs this transaction produces, e.g. change
+	Outs []*ava.TransferableOutput `serialize:"true"`
+
+	// ID of the subnet that validates the new chain
+	SubnetID ids.ID `serialize:"true"`
 
 	// A human readable name for the chain; need not be unique
 	ChainName string `serialize:"true"`
@@ -39,6 +44,11 @@ type UnsignedCreateChainTx struct {
 
 	// Byte representation of state of the new chain
 	GenesisData []byte `serialize:"true"`
+
+	// Byte representation of the configuration the new chain's VM is
+	// initialized with. Chains running the same VM may be configured
+	// differently.
+	ConfigData []byte `serialize:"true"`
 }
 
 // CreateChainTx is a proposal to create a chain
@@ -88,6 +98,9 @@ func (tx *CreateChainTx) SyntacticVerify() error {
 	case !ids.IsSortedAndUniqueIDs(tx.FxIDs):
 		return errFxIDsNotSortedAndUnique
 	}
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
+	}
 
 	unsignedIntf := interface{}(&tx.UnsignedCreateChainTx)
 	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
@@ -110,6 +123,13 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
+	// Ensure the subnet that will validate the chain exists
+	if !tx.SubnetID.Equals(DefaultSubnetID) {
+		if _, err := tx.vm.getSubnet(db, tx.SubnetID); err != nil {
+			return nil, err
+		}
+	}
+
 	currentChains, err := tx.vm.getChains(db) // chains that currently exist
 	if err != nil {
 		return nil, errDBChains
@@ -124,34 +144,13 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
-	// Deduct tx fee from payer's account
-	account, err := tx.vm.getAccount(db, tx.Key().Address())
-	if err != nil {
-		return nil, err
-	}
-	account, err = account.Remove(0, tx.Nonce)
-	if err != nil {
-		return nil, err
-	}
-	if err := tx.vm.putAccount(db, account); err != nil {
+	// Pay the tx fee
+	if err := tx.vm.semanticVerifySpend(db, tx.ID(), tx.Key().Address(), tx.Ins, tx.Outs, tx.vm.creationTxFee); err != nil {
 		return nil, err
 	}
 
 	// If this proposal is committed, create the new blockchain using the chain manager
-	onAccept := func() {
-		chainParams := chains.ChainParameters{
-			ID:          tx.ID(),
-			GenesisData: tx.GenesisData,
-			VMAlias:     tx.VMID.String(),
-		}
-		for _, fxID := range tx.FxIDs {
-			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
-		}
-		// TODO: Not sure how else to make this not nil pointer error during tests
-		if tx.vm.ChainManager != nil {
-			tx.vm.ChainManager.CreateChain(chainParams)
-		}
-	}
+	onAccept := func() { tx.vm.createChain(tx) }
 
 	return onAccept, nil
 }
@@ -166,12 +165,20 @@ func (chains createChainList) Bytes() []byte {
 	return bytes
 }
 
-func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fxIDs []ids.ID, chainName string, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*CreateChainTx, error) {
+func (vm *VM) newCreateChainTx(subnetID ids.ID, genesisData, configData []byte, vmID ids.ID, fxIDs []ids.ID, chainName string, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*CreateChainTx, error) {
+	ins, outs, err := vm.spend(vm.DB, key.PublicKey().Address(), 0, vm.creationTxFee)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := &CreateChainTx{
 		UnsignedCreateChainTx: UnsignedCreateChainTx{
 			NetworkID:   networkID,
-			Nonce:       nonce,
+			Ins:         ins,
+			Outs:        outs,
+			SubnetID:    subnetID,
 			GenesisData: genesisData,
+			ConfigData:  configData,
 			VMID:        vmID,
 			FxIDs:       fxIDs,
 			ChainName:   chainName,
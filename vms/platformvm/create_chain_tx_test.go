@@ -23,7 +23,8 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 
 	// Case 2: network ID is wrong
 	tx, err := vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
 		nil,
 		avm.ID,
 		nil,
@@ -42,7 +43,8 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 
 	// case 3: tx ID is empty
 	tx, err = vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
 		nil,
 		avm.ID,
 		nil,
@@ -60,7 +62,8 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 
 	// Case 4: vm ID is empty
 	tx, err = vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
 		nil,
 		avm.ID,
 		nil,
@@ -82,7 +85,8 @@ func TestSemanticVerify(t *testing.T) {
 
 	// create a tx
 	tx, err := vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
 		nil,
 		avm.ID,
 		nil,
@@ -118,7 +122,8 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 
 	// create a tx
 	tx, err := vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
 		nil,
 		avm.ID,
 		nil,
@@ -140,3 +145,25 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 		t.Fatalf("should have failed because there is already a chain with ID %s", tx.id)
 	}
 }
+
+func TestSemanticVerifyMissingSubnet(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newCreateChainTx(
+		ids.Empty.Prefix(1), // subnet that doesn't exist
+		nil,
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.SemanticVerify(vm.DB); err == nil {
+		t.Fatalf("should have failed because the subnet doesn't exist")
+	}
+}
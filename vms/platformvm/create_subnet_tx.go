@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/ava"
 )
 
 const maxThreshold = 25
@@ -32,9 +33,11 @@ type UnsignedCreateSubnetTx struct {
 	// NetworkID is the ID of the network this tx was issued on
 	NetworkID uint32 `serialize:"true"`
 
-	// Next unused nonce of account paying the transaction fee for this transaction.
-	// Currently unused, as there are no tx fees.
-	Nonce uint64 `serialize:"true"`
+	// The UTXOs the transaction fee is paid from
+	Ins []*ava.TransferableInput `serialize:"true"`
+
+	// The UTXOs this transaction produces, e.g. change
+	Outs []*ava.TransferableOutput `serialize:"true"`
 
 	// Each element in ControlKeys is the address of a public key
 	// In order to add a validator to this subnet, a tx must be signed
@@ -48,8 +51,8 @@ type CreateSubnetTx struct {
 	UnsignedCreateSubnetTx `serialize:"true"`
 
 	// The public key that signed this transaction
-	// The transaction fee will be paid from the corresponding account
-	// (ie the account whose ID is [key].Address())
+	// The transaction fee will be paid from UTXOs that [key].Address() can
+	// spend
 	// [key] is non-nil iff this tx is valid
 	key crypto.PublicKey
 
@@ -75,6 +78,9 @@ func (tx *CreateSubnetTx) SyntacticVerify() error {
 	case tx.Threshold > uint16(len(tx.ControlKeys)):
 		return errThresholdExceedsKeysLen
 	}
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
+	}
 
 	// Byte representation of the unsigned transaction
 	unsignedIntf := interface{}(&tx.UnsignedCreateSubnetTx)
@@ -115,16 +121,8 @@ func (tx *CreateSubnetTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
-	// Deduct tx fee from payer's account
-	account, err := tx.vm.getAccount(db, tx.key.Address())
-	if err != nil {
-		return nil, err
-	}
-	account, err = account.Remove(0, tx.Nonce)
-	if err != nil {
-		return nil, err
-	}
-	if err := tx.vm.putAccount(db, account); err != nil {
+	// Pay the tx fee
+	if err := tx.vm.semanticVerifySpend(db, tx.ID, tx.key.Address(), tx.Ins, tx.Outs, tx.vm.creationTxFee); err != nil {
 		return nil, err
 	}
 
@@ -156,15 +154,20 @@ func (tx *CreateSubnetTx) initialize(vm *VM) error {
 	return nil
 }
 
-func (vm *VM) newCreateSubnetTx(networkID uint32, nonce uint64, controlKeys []ids.ShortID,
+func (vm *VM) newCreateSubnetTx(networkID uint32, controlKeys []ids.ShortID,
 	threshold uint16, payerKey *crypto.PrivateKeySECP256K1R,
 ) (*CreateSubnetTx, error) {
+	ins, outs, err := vm.spend(vm.DB, payerKey.PublicKey().Address(), 0, vm.creationTxFee)
+	if err != nil {
+		return nil, err
+	}
 
 	tx := &CreateSubnetTx{
 		UnsignedCreateSubnetTx: UnsignedCreateSubnetTx{
 			vm:          vm,
 			NetworkID:   networkID,
-			Nonce:       nonce,
+			Ins:         ins,
+			Outs:        outs,
 			ControlKeys: controlKeys,
 			Threshold:   threshold,
 		},
@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
@@ -14,11 +15,10 @@ func TestTxHeapStart(t *testing.T) {
 	txHeap := EventHeap{SortByStartTime: true}
 
 	validator0, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{1}),                   // node ID
+		123,                         // stake amount
+		1,                           // startTime
+		3,                           // endTime
+		ids.NewShortID([20]byte{1}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -29,11 +29,10 @@ func TestTxHeapStart(t *testing.T) {
 	}
 
 	validator1, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -44,11 +43,10 @@ func TestTxHeapStart(t *testing.T) {
 	}
 
 	validator2, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		2,   // startTime
-		4,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		2,                          // startTime
+		4,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -68,11 +66,17 @@ func TestTxHeapStart(t *testing.T) {
 		t.Fatalf("TxHeap.Timestamp returned %s, expected %s", timestamp, validator1.StartTime())
 	}
 
+	// validator0 and validator1 are tied, so the tx with the lower ID is first
+	expected := validator0
+	if bytes.Compare(validator1.ID().Bytes(), validator0.ID().Bytes()) == -1 {
+		expected = validator1
+	}
+
 	txHeap.Add(validator0)
 	if timestamp := txHeap.Timestamp(); !timestamp.Equal(validator0.StartTime()) {
 		t.Fatalf("TxHeap.Timestamp returned %s, expected %s", timestamp, validator0.StartTime())
-	} else if top := txHeap.Peek(); !top.ID().Equals(validator0.ID()) {
-		t.Fatalf("TxHeap prioritized %s, expected %s", top.ID(), validator0.ID())
+	} else if top := txHeap.Peek(); !top.ID().Equals(expected.ID()) {
+		t.Fatalf("TxHeap prioritized %s, expected %s", top.ID(), expected.ID())
 	}
 }
 
@@ -81,11 +85,10 @@ func TestTxHeapStop(t *testing.T) {
 	txHeap := EventHeap{}
 
 	validator0, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{1}),                   // node ID
+		123,                         // stake amount
+		1,                           // startTime
+		3,                           // endTime
+		ids.NewShortID([20]byte{1}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -96,11 +99,10 @@ func TestTxHeapStop(t *testing.T) {
 	}
 
 	validator1, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -111,11 +113,10 @@ func TestTxHeapStop(t *testing.T) {
 	}
 
 	validator2, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		2,   // startTime
-		4,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		2,                          // startTime
+		4,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -135,11 +136,17 @@ func TestTxHeapStop(t *testing.T) {
 		t.Fatalf("TxHeap.Timestamp returned %s, expected %s", timestamp, validator1.EndTime())
 	}
 
+	// validator0 and validator1 are tied, so the tx with the lower ID is first
+	expected := validator0
+	if bytes.Compare(validator1.ID().Bytes(), validator0.ID().Bytes()) == -1 {
+		expected = validator1
+	}
+
 	txHeap.Add(validator0)
 	if timestamp := txHeap.Timestamp(); !timestamp.Equal(validator0.EndTime()) {
 		t.Fatalf("TxHeap.Timestamp returned %s, expected %s", timestamp, validator0.EndTime())
-	} else if top := txHeap.Txs[0]; !top.ID().Equals(validator0.ID()) {
-		t.Fatalf("TxHeap prioritized %s, expected %s", top.ID(), validator0.ID())
+	} else if top := txHeap.Txs[0]; !top.ID().Equals(expected.ID()) {
+		t.Fatalf("TxHeap prioritized %s, expected %s", top.ID(), expected.ID())
 	}
 }
 
@@ -148,11 +155,10 @@ func TestTxHeapStartValidatorVsDelegatorOrdering(t *testing.T) {
 	txHeap := EventHeap{SortByStartTime: true}
 
 	validator, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -163,11 +169,10 @@ func TestTxHeapStartValidatorVsDelegatorOrdering(t *testing.T) {
 	}
 
 	delegator, err := vm.newAddDefaultSubnetDelegatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // network ID
 		keys[0], // key
@@ -189,11 +194,10 @@ func TestTxHeapStopValidatorVsDelegatorOrdering(t *testing.T) {
 	txHeap := EventHeap{}
 
 	validator, err := vm.newAddDefaultSubnetValidatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // shares
 		0,       // network ID
@@ -204,11 +208,10 @@ func TestTxHeapStopValidatorVsDelegatorOrdering(t *testing.T) {
 	}
 
 	delegator, err := vm.newAddDefaultSubnetDelegatorTx(
-		5,   // nonce
-		123, // stake amount
-		1,   // startTime
-		3,   // endTime
-		ids.NewShortID([20]byte{}),                    // node ID
+		123,                        // stake amount
+		1,                          // startTime
+		3,                          // endTime
+		ids.NewShortID([20]byte{}), // node ID
 		ids.NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7}), // destination
 		0,       // network ID
 		keys[0], // key
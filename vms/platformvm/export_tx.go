@@ -0,0 +1,182 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportAmount = errors.New("no $AVA is being exported")
+)
+
+// UnsignedExportTx is an unsigned ExportTx
+type UnsignedExportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// The UTXOs the exported $AVA and the tx fee are paid from
+	Ins []*ava.TransferableInput `serialize:"true"`
+
+	// The UTXOs this transaction produces on the platform chain, e.g. change
+	Outs []*ava.TransferableOutput `serialize:"true"`
+
+	// Amount of $AVA to export
+	Amount uint64 `serialize:"true"`
+
+	// Address on the X-Chain that will be able to import the $AVA
+	To ids.ShortID `serialize:"true"`
+}
+
+// ExportTx moves $AVA from UTXOs controlled by the signer of this transaction
+// to the X-Chain
+type ExportTx struct {
+	UnsignedExportTx `serialize:"true"`
+
+	// Signature of the key that is able to spend each of the inputs
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ExportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ExportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ExportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ExportTx
+func (tx *ExportTx) Bytes() []byte { return tx.bytes }
+
+// ExportedUTXO returns the UTXO that this transaction places into the memory
+// shared with the X-Chain
+func (tx *ExportTx) ExportedUTXO() *ava.UTXO {
+	return &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: tx.ID()},
+		Asset:  ava.Asset{ID: tx.vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: tx.Amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{tx.To},
+			},
+		},
+	}
+}
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ExportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.Amount == 0:
+		return errNoExportAmount
+	}
+	if err := syntacticVerifySpend(tx.Ins, tx.Outs); err != nil {
+		return err
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ExportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	if tx.vm.Ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+
+	// Spend the exported $AVA and the tx fee
+	burned, err := math.Add64(tx.Amount, tx.vm.txFee)
+	if err != nil {
+		return nil, errOutputOverflow
+	}
+	if err := tx.vm.semanticVerifySpend(db, tx.ID(), tx.Key().Address(), tx.Ins, tx.Outs, burned); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// AtomicAccept places the exported UTXO into [sharedDB], the memory shared with
+// the X-Chain
+func (tx *ExportTx) AtomicAccept(sharedDB database.Database) error {
+	return avm.NewSharedState(sharedDB, tx.vm.avm).FundUTXO(tx.ExportedUTXO())
+}
+
+// newExportTx returns a transaction that exports [amount] $AVA, paid from UTXOs
+// that [key] can spend, to the address [to] on the X-Chain
+func (vm *VM) newExportTx(networkID uint32, amount uint64, to ids.ShortID, key *crypto.PrivateKeySECP256K1R) (*ExportTx, error) {
+	ins, outs, err := vm.spend(vm.DB, key.PublicKey().Address(), amount, vm.txFee)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &ExportTx{
+		UnsignedExportTx: UnsignedExportTx{
+			NetworkID: networkID,
+			Ins:       ins,
+			Outs:      outs,
+			Amount:    amount,
+			To:        to,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
@@ -0,0 +1,103 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	testAVMChainID = ids.Empty.Prefix(0)
+	testAVAAssetID = ids.Empty.Prefix(1)
+)
+
+// defaultAtomicVM returns a VM that can move $AVA to and from the X-Chain. The
+// state of the VM and shared memory are stored in the same base database so
+// that accepting an atomic tx writes to both in one batch.
+func defaultAtomicVM() (*VM, *core.SharedMemory) {
+	baseDB := memdb.New()
+	vm := defaultVMWithDB(prefixdb.New([]byte{0}, baseDB))
+	vm.avm = testAVMChainID
+
+	sm := &core.SharedMemory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte{1}, baseDB))
+	vm.Ctx.SharedMemory = sm.NewBlockchainSharedMemory(vm.Ctx.ChainID)
+	return vm, sm
+}
+
+func TestExportTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newExportTx(testNetworkID, 0, keys[1].PublicKey().Address(), keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatalf("should have errored because no $AVA is exported")
+	}
+}
+
+func TestExportTxAccept(t *testing.T) {
+	vm, sm := defaultAtomicVM()
+
+	to := keys[1].PublicKey().Address()
+	tx, err := vm.newExportTx(testNetworkID, 100, to, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept()
+
+	balance, err := vm.getBalance(vm.DB, keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - 100 - vm.txFee; balance != expected {
+		t.Fatalf("balance should be %d but is %d", expected, balance)
+	}
+
+	// The X-Chain should be able to import the exported $AVA
+	avmSM := sm.NewBlockchainSharedMemory(testAVMChainID)
+	smDB := avmSM.GetDatabase(vm.Ctx.ChainID)
+	defer avmSM.ReleaseDatabase(vm.Ctx.ChainID)
+
+	state := avm.NewSharedState(smDB, testAVMChainID)
+	utxoIDs, err := state.Funds(ids.NewID(hashing.ComputeHash256Array(to.Bytes())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("expected %d exported UTXO(s) but found %d", 1, len(utxoIDs))
+	}
+	utxo, err := state.UTXO(utxoIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assetID := utxo.AssetID(); !assetID.Equals(testAVAAssetID) {
+		t.Fatalf("exported the wrong asset")
+	}
+	if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); !ok || out.Amount() != 100 {
+		t.Fatalf("exported the wrong output")
+	}
+}
@@ -4,6 +4,8 @@
 package platformvm
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/validators"
@@ -18,6 +20,23 @@ var (
 type Factory struct {
 	ChainManager chains.Manager
 	Validators   validators.Manager
+	AVA          ids.ID
+	AVM          ids.ID
+
+	// How far ahead of this node's clock a proposed chain timestamp may be.
+	// If zero, Delta is used.
+	MaxClockDrift time.Duration
+
+	// $AVA, in nAVA, burned by each transaction that doesn't create a subnet
+	// or blockchain
+	TxFee uint64
+	// $AVA, in nAVA, burned by each transaction that creates a subnet or
+	// blockchain
+	CreationTxFee uint64
+
+	// Bounds of the stakes on the default subnet. If zero,
+	// DefaultStakingParameters are used.
+	Staking StakingParameters
 }
 
 // New returns a new instance of the Platform Chain
@@ -25,5 +44,12 @@ func (f *Factory) New() interface{} {
 	return &VM{
 		ChainManager: f.ChainManager,
 		Validators:   f.Validators,
+		ava:          f.AVA,
+		avm:          f.AVM,
+
+		maxClockDrift: f.MaxClockDrift,
+		txFee:         f.TxFee,
+		creationTxFee: f.CreationTxFee,
+		staking:       f.Staking,
 	}
 }
@@ -0,0 +1,168 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/gecko/api/apiproto"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+// GRPCServer serves the platform API over gRPC
+type GRPCServer struct {
+	apiproto.UnimplementedPlatformServer
+
+	service *Service
+}
+
+// NewGRPCServer returns a gRPC server that answers requests using [vm]
+func NewGRPCServer(vm *VM) *GRPCServer { return &GRPCServer{service: &Service{vm: vm}} }
+
+// GetCurrentValidators implements the apiproto.PlatformServer interface
+func (s *GRPCServer) GetCurrentValidators(_ context.Context, req *apiproto.GetValidatorsRequest) (*apiproto.GetValidatorsResponse, error) {
+	subnetID, err := parseSubnetID(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.service.vm.Ctx.Lock.Lock()
+	defer s.service.vm.Ctx.Lock.Unlock()
+
+	reply := GetCurrentValidatorsReply{}
+	args := GetCurrentValidatorsArgs{
+		SubnetID:   subnetID,
+		Limit:      json.Uint32(req.Limit),
+		StartIndex: json.Uint64(req.StartIndex),
+	}
+	if err := s.service.GetCurrentValidators(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	return validatorsResponse(reply.Validators, reply.EndIndex, reply.More), nil
+}
+
+// GetPendingValidators implements the apiproto.PlatformServer interface
+func (s *GRPCServer) GetPendingValidators(_ context.Context, req *apiproto.GetValidatorsRequest) (*apiproto.GetValidatorsResponse, error) {
+	subnetID, err := parseSubnetID(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.service.vm.Ctx.Lock.Lock()
+	defer s.service.vm.Ctx.Lock.Unlock()
+
+	reply := GetPendingValidatorsReply{}
+	args := GetPendingValidatorsArgs{
+		SubnetID:   subnetID,
+		Limit:      json.Uint32(req.Limit),
+		StartIndex: json.Uint64(req.StartIndex),
+	}
+	if err := s.service.GetPendingValidators(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	return validatorsResponse(reply.Validators, reply.EndIndex, reply.More), nil
+}
+
+// GetSubnets implements the apiproto.PlatformServer interface
+func (s *GRPCServer) GetSubnets(_ context.Context, req *apiproto.GetSubnetsRequest) (*apiproto.GetSubnetsResponse, error) {
+	args := GetSubnetsArgs{IDs: make([]ids.ID, len(req.Ids))}
+	for i, idStr := range req.Ids {
+		id, err := ids.FromString(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing subnet ID '%s': %w", idStr, err)
+		}
+		args.IDs[i] = id
+	}
+
+	s.service.vm.Ctx.Lock.Lock()
+	defer s.service.vm.Ctx.Lock.Unlock()
+
+	reply := GetSubnetsResponse{}
+	if err := s.service.GetSubnets(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	resp := &apiproto.GetSubnetsResponse{Subnets: make([]*apiproto.Subnet, len(reply.Subnets))}
+	for i, subnet := range reply.Subnets {
+		controlKeys := make([]string, len(subnet.ControlKeys))
+		for j, key := range subnet.ControlKeys {
+			controlKeys[j] = key.String()
+		}
+		resp.Subnets[i] = &apiproto.Subnet{
+			Id:          subnet.ID.String(),
+			ControlKeys: controlKeys,
+			Threshold:   uint32(subnet.Threshold),
+		}
+	}
+	return resp, nil
+}
+
+// GetBlockchainStatus implements the apiproto.PlatformServer interface
+func (s *GRPCServer) GetBlockchainStatus(_ context.Context, req *apiproto.GetBlockchainStatusRequest) (*apiproto.GetBlockchainStatusResponse, error) {
+	s.service.vm.Ctx.Lock.Lock()
+	defer s.service.vm.Ctx.Lock.Unlock()
+
+	reply := GetBlockchainStatusReply{}
+	if err := s.service.GetBlockchainStatus(nil, &GetBlockchainStatusArgs{BlockchainID: req.BlockchainId}, &reply); err != nil {
+		return nil, err
+	}
+	return &apiproto.GetBlockchainStatusResponse{Status: reply.Status.String()}, nil
+}
+
+// IssueTx implements the apiproto.PlatformServer interface
+func (s *GRPCServer) IssueTx(_ context.Context, req *apiproto.PlatformIssueTxRequest) (*apiproto.PlatformIssueTxResponse, error) {
+	s.service.vm.Ctx.Lock.Lock()
+	defer s.service.vm.Ctx.Lock.Unlock()
+
+	txID, err := s.service.issueTx(req.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &apiproto.PlatformIssueTxResponse{TxId: txID.String()}, nil
+}
+
+// parseSubnetID parses [subnetID], which may be empty to refer to the default
+// subnet
+func parseSubnetID(subnetID string) (ids.ID, error) {
+	if subnetID == "" {
+		return ids.ID{}, nil
+	}
+	id, err := ids.FromString(subnetID)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem parsing subnet ID '%s': %w", subnetID, err)
+	}
+	return id, nil
+}
+
+func validatorsResponse(stakers []APIStaker, endIndex json.Uint64, more bool) *apiproto.GetValidatorsResponse {
+	resp := &apiproto.GetValidatorsResponse{
+		Validators: make([]*apiproto.Staker, len(stakers)),
+		EndIndex:   uint64(endIndex),
+		More:       more,
+	}
+	for i, staker := range stakers {
+		validator := &apiproto.Staker{
+			Id:        staker.ID.String(),
+			StartTime: uint64(staker.StartTime),
+			EndTime:   uint64(staker.EndTime),
+			TxId:      staker.TxID.String(),
+			Delegator: staker.Delegator,
+		}
+		if staker.StakeAmount != nil {
+			validator.StakeAmount = uint64(*staker.StakeAmount)
+		}
+		if staker.Weight != nil {
+			validator.Weight = uint64(*staker.Weight)
+		}
+		if staker.Destination != nil {
+			validator.Destination = staker.Destination.String()
+		}
+		if staker.DelegationFeeRate != nil {
+			validator.DelegationFeeRate = uint32(*staker.DelegationFeeRate)
+		}
+		resp.Validators[i] = validator
+	}
+	return resp
+}
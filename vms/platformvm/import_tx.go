@@ -0,0 +1,319 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoImportInputs      = errors.New("no import inputs")
+	errInputsNotUnique     = errors.New("inputs are not unique")
+	errNoSharedMemory      = errors.New("shared memory is not available")
+	errAssetNotAVA         = errors.New("only AVA can be held on the platform chain")
+	errUTXOAlreadyImported = errors.New("UTXO has already been imported")
+)
+
+// UnsignedImportTx is an unsigned ImportTx
+type UnsignedImportTx struct {
+	// ID of the network this transaction exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// The UTXOs that the X-Chain exported to this chain that are consumed by
+	// this transaction
+	Ins []*ava.UTXOID `serialize:"true"`
+
+	// The UTXOs this transaction produces on the platform chain. The imported
+	// $AVA, minus the tx fee, must be paid to these outputs.
+	Outs []*ava.TransferableOutput `serialize:"true"`
+}
+
+// ImportTx moves $AVA that was exported from the X-Chain into the platform
+// chain's UTXO set
+type ImportTx struct {
+	UnsignedImportTx `serialize:"true"`
+
+	// Signature of the key that is able to spend each of the imported UTXOs
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ImportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ImportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ImportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ImportTx
+func (tx *ImportTx) Bytes() []byte { return tx.bytes }
+
+// InputUTXOs returns the IDs of the UTXOs this transaction consumes
+func (tx *ImportTx) InputUTXOs() ids.Set {
+	set := ids.Set{}
+	for _, in := range tx.Ins {
+		set.Add(in.InputID())
+	}
+	return set
+}
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ImportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case len(tx.Ins) == 0:
+		return errNoImportInputs
+	}
+
+	for _, in := range tx.Ins {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+	}
+	if tx.InputUTXOs().Len() != len(tx.Ins) {
+		return errInputsNotUnique
+	}
+	if err := syntacticVerifySpend(nil, tx.Outs); err != nil {
+		return err
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ImportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	if tx.vm.Ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+	smDB := tx.vm.Ctx.SharedMemory.GetDatabase(tx.vm.avm)
+	defer tx.vm.Ctx.SharedMemory.ReleaseDatabase(tx.vm.avm)
+
+	state := avm.NewSharedState(smDB, tx.vm.Ctx.ChainID)
+
+	currentTime, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+
+	address := tx.Key().Address()
+	amount := uint64(0)
+	for _, in := range tx.Ins {
+		utxoID := in.InputID()
+
+		// The UTXO is only removed from shared memory once this tx is
+		// accepted, so a processing ancestor block may have already imported
+		// it
+		if tx.vm.isImported(db, utxoID) {
+			return nil, errUTXOAlreadyImported
+		}
+		if err := tx.vm.putImported(db, utxoID); err != nil {
+			return nil, err
+		}
+
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find UTXO %s in shared memory: %w", utxoID, err)
+		}
+		out, err := tx.vm.verifySpend(utxo, address, uint64(currentTime.Unix()))
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err = math.Add64(amount, out.Amount())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the imported $AVA to the UTXO set
+	produced, err := tx.vm.produceOutputs(db, tx.ID(), tx.Outs)
+	if err != nil {
+		return nil, err
+	}
+	if produced, err = math.Add64(produced, tx.vm.txFee); err != nil {
+		return nil, errOutputOverflow
+	}
+	if amount < produced {
+		return nil, errInsufficientFunds
+	}
+
+	return nil, nil
+}
+
+// AtomicAccept removes the imported UTXOs from [sharedDB], the memory shared
+// with the X-Chain
+func (tx *ImportTx) AtomicAccept(sharedDB database.Database) error {
+	state := avm.NewSharedState(sharedDB, tx.vm.Ctx.ChainID)
+	for _, in := range tx.Ins {
+		if err := state.SpendUTXO(in.InputID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAtomicUTXOs returns the UTXOs referenced by [addrs] that the chain
+// [sourceChain] has exported to this chain and that haven't been imported yet
+func (vm *VM) getAtomicUTXOs(sourceChain ids.ID, addrs []ids.ShortID) ([]*ava.UTXO, error) {
+	if vm.Ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+	smDB := vm.Ctx.SharedMemory.GetDatabase(sourceChain)
+	defer vm.Ctx.SharedMemory.ReleaseDatabase(sourceChain)
+
+	state := avm.NewSharedState(smDB, vm.Ctx.ChainID)
+
+	utxoIDs := ids.Set{}
+	for _, addr := range addrs {
+		// If no UTXOs reference the address, an error is returned
+		funds, _ := state.Funds(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+		utxoIDs.Add(funds...)
+	}
+
+	utxos := []*ava.UTXO{}
+	for _, utxoID := range utxoIDs.List() {
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// getImportableUTXOs returns the IDs of the UTXOs that the X-Chain has exported
+// to this chain that [address] can currently import
+func (vm *VM) getImportableUTXOs(address ids.ShortID) ([]*ava.UTXOID, error) {
+	utxos, err := vm.getAtomicUTXOs(vm.avm, []ids.ShortID{address})
+	if err != nil {
+		return nil, err
+	}
+
+	currentTime, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	ins := []*ava.UTXOID{}
+	for _, utxo := range utxos {
+		if _, err := vm.verifySpend(utxo, address, uint64(currentTime.Unix())); err == nil {
+			ins = append(ins, &utxo.UTXOID)
+		}
+	}
+	return ins, nil
+}
+
+// containsAddress returns true if [addr] is in [addrs]
+func containsAddress(addrs []ids.ShortID, addr ids.ShortID) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// newImportTx returns a transaction that imports the UTXOs [ins] that the
+// X-Chain exported to this chain. The imported $AVA, minus the tx fee, is paid
+// to the address of [key], which must be able to spend each of the UTXOs.
+func (vm *VM) newImportTx(networkID uint32, ins []*ava.UTXOID, key *crypto.PrivateKeySECP256K1R) (*ImportTx, error) {
+	if vm.Ctx.SharedMemory == nil {
+		return nil, errNoSharedMemory
+	}
+	smDB := vm.Ctx.SharedMemory.GetDatabase(vm.avm)
+	defer vm.Ctx.SharedMemory.ReleaseDatabase(vm.avm)
+
+	state := avm.NewSharedState(smDB, vm.Ctx.ChainID)
+
+	amount := uint64(0)
+	for _, in := range ins {
+		utxo, err := state.UTXO(in.InputID())
+		if err != nil {
+			return nil, err
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return nil, errWrongUTXOType
+		}
+		if amount, err = math.Add64(amount, out.Amount()); err != nil {
+			return nil, errInputOverflow
+		}
+	}
+	if amount < vm.txFee {
+		return nil, errInsufficientFunds
+	}
+
+	outs := []*ava.TransferableOutput{}
+	if amount > vm.txFee {
+		outs = append(outs, vm.newOutput(amount-vm.txFee, key.PublicKey().Address()))
+	}
+
+	tx := &ImportTx{
+		UnsignedImportTx: UnsignedImportTx{
+			NetworkID: networkID,
+			Ins:       ins,
+			Outs:      outs,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
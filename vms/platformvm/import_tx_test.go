@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// exportToPlatform simulates the X-Chain exporting [amount] $AVA to [addr]
+func exportToPlatform(t *testing.T, vm *VM, sm *core.SharedMemory, addr ids.ShortID, amount uint64) *ava.UTXO {
+	avmSM := sm.NewBlockchainSharedMemory(testAVMChainID)
+	smDB := avmSM.GetDatabase(vm.Ctx.ChainID)
+	defer avmSM.ReleaseDatabase(vm.Ctx.ChainID)
+
+	utxo := &ava.UTXO{
+		UTXOID: ava.UTXOID{TxID: ids.Empty.Prefix(amount)},
+		Asset:  ava.Asset{ID: testAVAAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	if err := avm.NewSharedState(smDB, vm.Ctx.ChainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	return utxo
+}
+
+func TestImportTxAccept(t *testing.T) {
+	vm, sm := defaultAtomicVM()
+
+	addr := keys[0].PublicKey().Address()
+	exportToPlatform(t, vm, sm, addr, 500)
+
+	ins, err := vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 1 {
+		t.Fatalf("expected %d importable UTXO(s) but found %d", 1, len(ins))
+	}
+
+	tx, err := vm.newImportTx(testNetworkID, ins, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.Ctx.Lock.Lock()
+	vm.unissuedDecisionTxs = append(vm.unissuedDecisionTxs, tx)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Lock.Unlock()
+
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	blk.Accept()
+
+	balance, err := vm.getBalance(vm.DB, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance + 500 - vm.txFee; balance != expected {
+		t.Fatalf("balance should be %d but is %d", expected, balance)
+	}
+
+	// The imported UTXO should have been removed from shared memory
+	ins, err = vm.getImportableUTXOs(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 0 {
+		t.Fatalf("expected %d importable UTXO(s) but found %d", 0, len(ins))
+	}
+}
+
+func TestImportTxDoubleImport(t *testing.T) {
+	vm, sm := defaultAtomicVM()
+
+	addr := keys[0].PublicKey().Address()
+	utxo := exportToPlatform(t, vm, sm, addr, 500)
+
+	tx1, err := vm.newImportTx(testNetworkID, []*ava.UTXOID{&utxo.UTXOID}, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx2, err := vm.newImportTx(testNetworkID, []*ava.UTXOID{&utxo.UTXOID}, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The state of a block that contains tx1 and hasn't been accepted yet
+	db := versiondb.New(vm.DB)
+	if _, err := tx1.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx2.SemanticVerify(db); err == nil {
+		t.Fatalf("should have errored because the UTXO was already imported")
+	}
+}
+
+func TestImportTxWrongOwner(t *testing.T) {
+	vm, sm := defaultAtomicVM()
+
+	utxo := exportToPlatform(t, vm, sm, keys[1].PublicKey().Address(), 500)
+
+	tx, err := vm.newImportTx(testNetworkID, []*ava.UTXOID{&utxo.UTXOID}, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatalf("should have errored because the signer can't spend the UTXO")
+	}
+}
+
+func TestServiceGetAtomicUTXOs(t *testing.T) {
+	vm, sm := defaultAtomicVM()
+	service := Service{vm: vm}
+
+	addr := keys[0].PublicKey().Address()
+	utxo := exportToPlatform(t, vm, sm, addr, 500)
+
+	reply := GetUTXOsReply{}
+	if err := service.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses:   []ids.ShortID{addr},
+		SourceChain: testAVMChainID.String(),
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.UTXOs) != 1 {
+		t.Fatalf("expected %d UTXO(s) but found %d", 1, len(reply.UTXOs))
+	}
+	expected, err := Codec.Marshal(utxo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reply.UTXOs[0].Bytes, expected) {
+		t.Fatalf("returned the wrong UTXO")
+	}
+
+	if err := service.GetUTXOs(nil, &GetUTXOsArgs{
+		Addresses:   []ids.ShortID{addr},
+		SourceChain: vm.Ctx.ChainID.String(),
+	}, &GetUTXOsReply{}); err == nil {
+		t.Fatalf("should have errored because the source chain is the platform chain")
+	}
+}
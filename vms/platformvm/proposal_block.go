@@ -39,6 +39,10 @@ type ProposalBlock struct {
 	onCommitFunc func()
 	// The function to execute if this block's proposal is aborted
 	onAbortFunc func()
+
+	// This block's options. nil before Options is called on this block
+	commit *Commit
+	abort  *Abort
 }
 
 // Initialize this block.
@@ -84,7 +88,13 @@ func (pb *ProposalBlock) onAbort() (*versiondb.Database, func()) {
 // The parent block must either be a Commit or an Abort block.
 //
 // If this block is valid, this function also sets pas.onCommit and pas.onAbort.
+// A block that was already verified isn't verified again, so that its options
+// keep building on the same databases.
 func (pb *ProposalBlock) Verify() error {
+	if pb.onCommitDB != nil {
+		return nil
+	}
+
 	// pdb is the database if this block's parent is accepted
 	var pdb database.Database
 	parent := pb.parentBlock()
@@ -107,24 +117,119 @@ func (pb *ProposalBlock) Verify() error {
 }
 
 // Options returns the possible children of this block in preferential order.
+// The options are only built, and stored, the first time they're asked for.
 func (pb *ProposalBlock) Options() [2]snowman.Block {
-	blockID := pb.ID()
+	if pb.commit == nil || pb.abort == nil {
+		blockID := pb.ID()
 
-	commit := pb.vm.newCommitBlock(blockID)
-	abort := pb.vm.newAbortBlock(blockID)
+		pb.commit = pb.vm.newCommitBlock(blockID)
+		pb.abort = pb.vm.newAbortBlock(blockID)
 
-	if err := pb.vm.State.PutBlock(pb.vm.DB, commit); err != nil {
-		pb.vm.Ctx.Log.Warn(errDBPutBlock.Error())
-	}
-	if err := pb.vm.State.PutBlock(pb.vm.DB, abort); err != nil {
-		pb.vm.Ctx.Log.Warn(errDBPutBlock.Error())
+		if err := pb.vm.State.PutBlock(pb.vm.DB, pb.commit); err != nil {
+			pb.vm.Ctx.Log.Warn(errDBPutBlock.Error())
+		}
+		if err := pb.vm.State.PutBlock(pb.vm.DB, pb.abort); err != nil {
+			pb.vm.Ctx.Log.Warn(errDBPutBlock.Error())
+		}
+		pb.vm.DB.Commit()
 	}
-	pb.vm.DB.Commit()
 
 	if pb.Tx.InitiallyPrefersCommit() {
-		return [2]snowman.Block{commit, abort}
+		return [2]snowman.Block{pb.commit, pb.abort}
+	}
+	return [2]snowman.Block{pb.abort, pb.commit}
+}
+
+// Accept implements the snowman.Block interface. This node no longer needs to
+// propose the block's tx again.
+func (pb *ProposalBlock) Accept() {
+	if tx, ok := pb.Tx.(TimedTx); ok {
+		pb.vm.proposedTxs.Remove(tx.ID())
+	}
+	pb.CommonBlock.Accept()
+}
+
+// Reject implements the snowman.Block interface.
+//
+// The rejected proposal is proposed again if it's still needed. Proposals to
+// advance the chain time or to reward a validator are rebuilt from the chain's
+// state, and a proposal to add a staker is re-issued if this node proposed it.
+// Either way, the VM checks whether it should build a block, as the rejected
+// proposal may have been the only block it was going to build.
+func (pb *ProposalBlock) Reject() {
+	pb.vm.Ctx.Log.Verbo("Rejecting block with ID %s", pb.ID())
+
+	pb.CommonBlock.Reject()
+
+	if tx, ok := pb.Tx.(TimedTx); ok && pb.vm.shouldRepropose(tx) {
+		pb.vm.unissuedEvents.Add(tx)
+	}
+	// The engine may still prefer this block, so the VM checks once the
+	// engine has updated its preference
+	pb.vm.timer.SetTimeoutIn(0)
+}
+
+// shouldRepropose returns true if [tx], the tx of a rejected proposal block,
+// should be proposed again. It should be if this node proposed it, it's not
+// already waiting to be proposed, and no other block holds it. Another block
+// may hold the same tx if another node proposed it too.
+func (vm *VM) shouldRepropose(tx TimedTx) bool {
+	txID := tx.ID()
+	if !vm.proposedTxs.Contains(txID) {
+		return false
+	}
+	vm.proposedTxs.Remove(txID)
+
+	for _, unissued := range vm.unissuedEvents.Txs {
+		if unissued.ID().Equals(txID) {
+			return false
+		}
+	}
+
+	// A proposal block is kept in memory while it's processing, and after it's
+	// accepted until one of its options is. Until then, its tx isn't in the
+	// staker sets.
+	for _, blk := range vm.currentBlocks {
+		if pb, ok := blk.(*ProposalBlock); ok {
+			if proposed, ok := pb.Tx.(TimedTx); ok && proposed.ID().Equals(txID) {
+				return false
+			}
+		}
+	}
+
+	isStaker, err := vm.isStaker(vm.DB, tx)
+	if err != nil {
+		vm.Ctx.Log.Error("couldn't check whether tx %s is a staker due to %s", txID, err)
+		return false
+	}
+	return !isStaker
+}
+
+// isStaker returns true if [tx] is in the current or pending staker set of its
+// subnet in [db]
+func (vm *VM) isStaker(db database.Database, tx TimedTx) (bool, error) {
+	subnetID := DefaultSubnetID
+	if tx, ok := tx.(*addNonDefaultSubnetValidatorTx); ok {
+		subnetID = tx.SubnetID()
+	}
+
+	current, err := vm.getCurrentValidators(db, subnetID)
+	if err != nil {
+		return false, err
+	}
+	pending, err := vm.getPendingValidators(db, subnetID)
+	if err != nil {
+		return false, err
+	}
+	txID := tx.ID()
+	for _, stakers := range []*EventHeap{current, pending} {
+		for _, staker := range stakers.Txs {
+			if staker.ID().Equals(txID) {
+				return true, nil
+			}
+		}
 	}
-	return [2]snowman.Block{abort, commit}
+	return false, nil
 }
 
 // newProposalBlock creates a new block that proposes to issue a transaction.
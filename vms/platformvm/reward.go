@@ -4,8 +4,10 @@
 package platformvm
 
 import (
-	"math"
+	stdmath "math"
 	"time"
+
+	"github.com/ava-labs/gecko/utils/math"
 )
 
 // reward returns the amount of $AVA to reward the staker with
@@ -16,10 +18,33 @@ func reward(duration time.Duration, amount uint64, inflationRate float64) uint64
 	years := duration.Hours() / (365. * 24.)
 
 	// Total value of this transaction
-	value := float64(amount) * math.Pow(inflationRate, years)
+	value := float64(amount) * stdmath.Pow(inflationRate, years)
 
 	// Amount of the reward in $AVA
 	reward := value - float64(amount)
 
+	// Converting a float that doesn't fit in a uint64 is undefined, so a reward
+	// that large is capped
+	if reward >= stdmath.MaxUint64 {
+		return stdmath.MaxUint64
+	}
 	return uint64(reward)
 }
+
+// splitReward splits the [reward] earned by a delegator between the delegator
+// and the validator it delegated to. The validator receives [shares] out of
+// NumberOfShares of the reward. Returns the delegator's and the validator's
+// portions of the reward.
+func splitReward(reward uint64, shares uint32) (uint64, uint64) {
+	// Because shares <= NumberOfShares this will never underflow
+	delegatorShares := NumberOfShares - uint64(shares)
+	// Because delegatorShares <= NumberOfShares this will never overflow
+	delegatorReward := delegatorShares * (reward / NumberOfShares)
+	// Delay rounding as long as possible for small numbers
+	if optimisticReward, err := math.Mul64(delegatorShares, reward); err == nil {
+		delegatorReward = optimisticReward / NumberOfShares
+	}
+
+	// Because delegatorReward <= reward this will never underflow
+	return delegatorReward, reward - delegatorReward
+}
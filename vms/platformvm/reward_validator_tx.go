@@ -22,11 +22,11 @@ var (
 // validator that is currently validating from the validator set.
 //
 // If this transaction is accepted and the next block accepted is a *Commit
-// block, the validator is removed and the account that the validator specified
+// block, the validator is removed and the address that the validator specified
 // receives the staked $AVA as well as a validating reward.
 //
 // If this transaction is accepted and the next block accepted is an *Abort
-// block, the validator is removed and the account that the validator specified
+// block, the validator is removed and the address that the validator specified
 // receives the staked $AVA but no reward.
 type rewardValidatorTx struct {
 	// ID of the tx that created the delegator/validator being removed/rewarded
@@ -96,15 +96,15 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 	heap.Pop(currentEvents) // Remove validator from the validator set
 
 	onCommitDB := versiondb.New(db)
-	// If this tx's proposal is committed, remove the validator from the validator set and update the
-	// account balance to reflect the return of staked $AVA and their reward.
+	// If this tx's proposal is committed, remove the validator from the validator set and return
+	// the staked $AVA and their reward.
 	if err := tx.vm.putCurrentValidators(onCommitDB, currentEvents, DefaultSubnetID); err != nil {
 		return nil, nil, nil, nil, errDBPutCurrentValidators
 	}
 
 	onAbortDB := versiondb.New(db)
-	// If this tx's proposal is aborted, remove the validator from the validator set and update the
-	// account balance to reflect the return of staked $AVA. The validator receives no reward.
+	// If this tx's proposal is aborted, remove the validator from the validator set and return
+	// the staked $AVA. The validator receives no reward.
 	if err := tx.vm.putCurrentValidators(onAbortDB, currentEvents, DefaultSubnetID); err != nil {
 		return nil, nil, nil, nil, errDBPutCurrentValidators
 	}
@@ -120,34 +120,14 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 			tx.vm.Ctx.Log.Error("error while calculating balance with reward: %s", err)
 		}
 
-		accountID := vdrTx.Destination
-		account, err := tx.vm.getAccount(db, accountID) // account receiving staked $AVA (and, if applicable, reward)
-		// Error is likely because the staked $AVA is being sent to a new
-		// account that isn't in the platform chain's state yet.
-		// Create the account
-		// TODO: We should have a keyNotFound error to distinguish this case from others
-		if err != nil {
-			account = newAccount(accountID, 0, 0)
-		}
-
-		accountWithReward := account // The state of the account if the validator earned a validating reward
-		accountNoReward := account   // The state of the account if the validator didn't earn a validating reward
-		if newAccount, err := account.Add(amountWithReward); err == nil {
-			accountWithReward = newAccount
-		} else {
-			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
-		}
-		if newAccount, err := account.Add(amount); err == nil {
-			accountNoReward = newAccount
-		} else {
-			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
-		}
-
-		if err := tx.vm.putAccount(onCommitDB, accountWithReward); err != nil {
-			return nil, nil, nil, nil, errDBPutAccount
+		// The staked $AVA (and, if applicable, reward) is returned to the
+		// destination as a new UTXO following the outputs of the staking tx
+		outputIndex := uint32(len(vdrTx.Outs))
+		if err := tx.vm.putUTXO(onCommitDB, tx.vm.newUTXO(vdrTx.ID(), outputIndex, amountWithReward, vdrTx.Destination)); err != nil {
+			return nil, nil, nil, nil, errDBPutUTXO
 		}
-		if err := tx.vm.putAccount(onAbortDB, accountNoReward); err != nil {
-			return nil, nil, nil, nil, errDBPutAccount
+		if err := tx.vm.putUTXO(onAbortDB, tx.vm.newUTXO(vdrTx.ID(), outputIndex, amount, vdrTx.Destination)); err != nil {
+			return nil, nil, nil, nil, errDBPutUTXO
 		}
 	case *addDefaultSubnetDelegatorTx:
 		parentTx, err := currentEvents.getDefaultSubnetStaker(vdrTx.NodeID)
@@ -158,18 +138,7 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		duration := vdrTx.Duration()
 		amount := vdrTx.Wght
 		reward := reward(duration, amount, InflationRate)
-
-		// Because parentTx.Shares <= NumberOfShares this will never underflow
-		delegatorShares := NumberOfShares - uint64(parentTx.Shares)
-		// Because delegatorShares <= NumberOfShares this will never overflow
-		delegatorReward := delegatorShares * (reward / NumberOfShares)
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := math.Mul64(delegatorShares, reward); err == nil {
-			delegatorReward = optimisticReward / NumberOfShares
-		}
-
-		// Because delegatorReward <= reward this will never underflow
-		validatorReward := reward - delegatorReward
+		delegatorReward, validatorReward := splitReward(reward, parentTx.Shares)
 
 		delegatorAmountWithReward, err := math.Add64(amount, delegatorReward)
 		if err != nil {
@@ -177,55 +146,23 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 			tx.vm.Ctx.Log.Error("error while calculating balance with reward: %s", err)
 		}
 
-		delegatorAccountID := vdrTx.Destination
-		delegatorAccount, err := tx.vm.getAccount(db, delegatorAccountID) // account receiving staked $AVA (and, if applicable, reward)
-		// Error is likely because the staked $AVA is being sent to a new
-		// account that isn't in the platform chain's state yet.
-		// Create the account
-		// TODO: We should have a keyNotFound error to distinguish this case from others
-		if err != nil {
-			delegatorAccount = newAccount(delegatorAccountID, 0, 0)
-		}
-
-		delegatorAccountWithReward := delegatorAccount // The state of the account if the validator earned a validating reward
-		delegatorAccountNoReward := delegatorAccount   // The state of the account if the validator didn't earn a validating reward
-		if newAccount, err := delegatorAccount.Add(delegatorAmountWithReward); err == nil {
-			delegatorAccountWithReward = newAccount
-		} else {
-			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
-		}
-		if newAccount, err := delegatorAccount.Add(amount); err == nil {
-			delegatorAccountNoReward = newAccount
-		} else {
-			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
-		}
-
-		if err := tx.vm.putAccount(onCommitDB, delegatorAccountWithReward); err != nil {
-			return nil, nil, nil, nil, errDBPutAccount
+		// The delegated $AVA (and, if applicable, reward) is returned to the
+		// delegator's destination as a new UTXO following the outputs of the
+		// delegation tx
+		outputIndex := uint32(len(vdrTx.Outs))
+		if err := tx.vm.putUTXO(onCommitDB, tx.vm.newUTXO(vdrTx.ID(), outputIndex, delegatorAmountWithReward, vdrTx.Destination)); err != nil {
+			return nil, nil, nil, nil, errDBPutUTXO
 		}
-		if err := tx.vm.putAccount(onAbortDB, delegatorAccountNoReward); err != nil {
-			return nil, nil, nil, nil, errDBPutAccount
-		}
-
-		validatorAccountID := parentTx.Destination
-		validatorAccount, err := tx.vm.getAccount(onCommitDB, validatorAccountID) // account receiving staked $AVA (and, if applicable, reward)
-		// Error is likely because the staked $AVA is being sent to a new
-		// account that isn't in the platform chain's state yet.
-		// Create the account
-		// TODO: We should have a keyNotFound error to distinguish this case from others
-		if err != nil {
-			validatorAccount = newAccount(validatorAccountID, 0, 0)
-		}
-
-		validatorAccountWithReward := validatorAccount // The state of the account if the validator earned a validating reward
-		if newAccount, err := validatorAccount.Add(validatorReward); err == nil {
-			validatorAccountWithReward = newAccount
-		} else {
-			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
+		if err := tx.vm.putUTXO(onAbortDB, tx.vm.newUTXO(vdrTx.ID(), outputIndex, amount, vdrTx.Destination)); err != nil {
+			return nil, nil, nil, nil, errDBPutUTXO
 		}
 
-		if err := tx.vm.putAccount(onCommitDB, validatorAccountWithReward); err != nil {
-			return nil, nil, nil, nil, errDBPutAccount
+		// The validator's share of the reward is paid to the validator's
+		// destination
+		if validatorReward > 0 {
+			if err := tx.vm.putUTXO(onCommitDB, tx.vm.newUTXO(vdrTx.ID(), outputIndex+1, validatorReward, parentTx.Destination)); err != nil {
+				return nil, nil, nil, nil, errDBPutUTXO
+			}
 		}
 	default:
 		return nil, nil, nil, nil, errShouldBeDSValidator
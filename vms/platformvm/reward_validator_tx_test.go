@@ -119,11 +119,11 @@ func TestRewardValidatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// account should have gotten validator reward
-	account, err := vm.getAccount(onCommitDB, nextToRemove.Destination)
+	balance, err := vm.getBalance(onCommitDB, nextToRemove.Destination)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance <= defaultBalance-txFee {
+	if balance <= defaultBalance-vm.txFee {
 		t.Fatal("expected account balance to have increased due to receiving validator reward")
 	}
 }
@@ -143,8 +143,15 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	}
 	key2 := keyIntf2.(*crypto.PrivateKeySECP256K1R)
 
+	// Give the stakers the $AVA they stake
+	for i, key := range []*crypto.PrivateKeySECP256K1R{key1, key2} {
+		utxo := vm.newUTXO(ids.Empty.Prefix(uint64(i)), 0, defaultStakeAmount+vm.txFee, key.PublicKey().Address())
+		if err := vm.putUTXO(vm.DB, utxo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	vdrTx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,     // nonce
 		defaultStakeAmount, // stakeAmt
 		uint64(defaultValidateEndTime.Add(-365*24*time.Hour).Unix())-1,
 		uint64(defaultValidateEndTime.Unix())-1,
@@ -159,7 +166,6 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	delTx, err := vm.newAddDefaultSubnetDelegatorTx(
-		defaultNonce+1,     // nonce
 		defaultStakeAmount, // stakeAmt
 		uint64(defaultValidateEndTime.Add(-365*24*time.Hour).Unix())-1,
 		uint64(defaultValidateEndTime.Unix())-1,
@@ -172,6 +178,13 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// The stake is spent when the stakers start staking
+	for _, in := range append(vdrTx.Ins, delTx.Ins...) {
+		if err := vm.removeUTXO(vm.DB, in.InputID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
 	if err != nil {
 		t.Fatal(err)
@@ -195,21 +208,21 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// account should have gotten validator reward
-	account, err := vm.getAccount(onCommitDB, vdrTx.Destination)
+	balance, err := vm.getBalance(onCommitDB, vdrTx.Destination)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := defaultStakeAmount / 100; account.Balance != expectedBalance {
-		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
+	if expectedBalance := defaultStakeAmount / 100; balance != expectedBalance {
+		t.Fatalf("expected account balance to be %d was %d", expectedBalance, balance)
 	}
 
 	// account should have gotten validator reward
-	account, err = vm.getAccount(onCommitDB, delTx.Destination)
+	balance, err = vm.getBalance(onCommitDB, delTx.Destination)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := (defaultStakeAmount * 103) / 100; account.Balance != expectedBalance {
-		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
+	if expectedBalance := (defaultStakeAmount * 103) / 100; balance != expectedBalance {
+		t.Fatalf("expected account balance to be %d was %d", expectedBalance, balance)
 	}
 
 	tx, err = vm.newRewardValidatorTx(vdrTx.ID())
@@ -223,11 +236,11 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// account should have gotten validator reward
-	account, err = vm.getAccount(onCommitDB, vdrTx.Destination)
+	balance, err = vm.getBalance(onCommitDB, vdrTx.Destination)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := (defaultStakeAmount * 21) / 20; account.Balance != expectedBalance {
-		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
+	if expectedBalance := (defaultStakeAmount * 21) / 20; balance != expectedBalance {
+		t.Fatalf("expected account balance to be %d was %d", expectedBalance, balance)
 	}
 }
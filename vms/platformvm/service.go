@@ -5,31 +5,43 @@ package platformvm
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"time"
 
 	"github.com/gorilla/rpc/v2/json2"
 
-	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
+)
+
+const (
+	// maxValidatorsToFetch is the maximum number of stakers returned by a
+	// call to GetCurrentValidators or GetPendingValidators
+	maxValidatorsToFetch = 1024
 )
 
 var (
-	errMissingDecisionBlock = errors.New("should have a decision block within the past two blocks")
-	errParsingID            = errors.New("error parsing ID")
-	errGetAccount           = errors.New("error retrieving account information")
-	errGetAccounts          = errors.New("error getting accounts controlled by specified user")
-	errGetUser              = errors.New("error while getting user. Does user exist?")
-	errNoMethodWithGenesis  = errors.New("no method was provided but genesis data was provided")
-	errCreatingTransaction  = errors.New("problem while creating transaction")
-	errNoDestination        = errors.New("call is missing field 'stakeDestination'")
-	errNoSource             = errors.New("call is missing field 'stakeSource'")
-	errGetStakeSource       = errors.New("couldn't get account specified in 'stakeSource'")
+	errMissingDecisionBlock   = errors.New("should have a decision block within the past two blocks")
+	errParsingID              = errors.New("error parsing ID")
+	errGetAccount             = errors.New("error retrieving account information")
+	errGetAccounts            = errors.New("error getting accounts controlled by specified user")
+	errGetUser                = errors.New("error while getting user. Does user exist?")
+	errNoMethodWithGenesis    = errors.New("no method was provided but genesis data was provided")
+	errCreatingTransaction    = errors.New("problem while creating transaction")
+	errNoDestination          = errors.New("call is missing field 'stakeDestination'")
+	errNoSource               = errors.New("call is missing field 'stakeSource'")
+	errGetStakeSource         = errors.New("couldn't get account specified in 'stakeSource'")
+	errNoImportableUTXOs      = errors.New("no $AVA has been exported to this account")
+	errSourceChainIsThisChain = errors.New("source chain must be a chain other than the platform chain")
 )
 
 var key *crypto.PrivateKeySECP256K1R
@@ -127,19 +139,143 @@ func (service *Service) GetSubnets(_ *http.Request, args *GetSubnetsArgs, respon
  ******************************************************
  */
 
+// APIStaker is a validator, or a delegator of a validator, of a subnet
+type APIStaker struct {
+	APIValidator
+
+	// ID of the tx that added the staker
+	TxID ids.ID `json:"txID"`
+
+	// Address the staked $AVA, and any reward, is paid to when the staker is
+	// done staking. Only set for stakers of the default subnet.
+	Destination *ids.ShortID `json:"destination,omitempty"`
+
+	// Number of shares, out of NumberOfShares, of the rewards of delegators
+	// that the validator keeps. Only set for validators of the default subnet.
+	DelegationFeeRate *json.Uint32 `json:"delegationFeeRate,omitempty"`
+
+	// True if the staker delegates its stake to the validator with ID [ID]
+	Delegator bool `json:"delegator"`
+}
+
+// GetTimestampArgs are the arguments for calling GetTimestamp
+type GetTimestampArgs struct{}
+
+// GetTimestampReply is the response from GetTimestamp
+type GetTimestampReply struct {
+	// Current timestamp of the chain, in unix time
+	Timestamp json.Uint64 `json:"timestamp"`
+}
+
+// GetTimestamp returns the current timestamp of the chain. The timestamp
+// determines which stakers are validating, and only moves forward when an
+// AdvanceTimeTx is accepted.
+func (service *Service) GetTimestamp(_ *http.Request, args *GetTimestampArgs, reply *GetTimestampReply) error {
+	service.vm.Ctx.Log.Debug("GetTimestamp called")
+
+	timestamp, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain's timestamp: %w", err)
+	}
+	reply.Timestamp = json.Uint64(timestamp.Unix())
+	return nil
+}
+
+// GetTxFeeReply is the response from GetTxFee
+type GetTxFeeReply struct {
+	// $AVA, in nAVA, burned by each transaction that doesn't create a subnet
+	// or blockchain
+	TxFee json.Uint64 `json:"txFee"`
+	// $AVA, in nAVA, burned by each transaction that creates a subnet or
+	// blockchain
+	CreationTxFee json.Uint64 `json:"creationTxFee"`
+}
+
+// GetTxFee returns the transaction fees of this chain
+func (service *Service) GetTxFee(_ *http.Request, _ *struct{}, reply *GetTxFeeReply) error {
+	service.vm.Ctx.Log.Debug("GetTxFee called")
+
+	reply.TxFee = json.Uint64(service.vm.txFee)
+	reply.CreationTxFee = json.Uint64(service.vm.creationTxFee)
+	return nil
+}
+
+// GetStakingParametersReply is the response from GetStakingParameters
+type GetStakingParametersReply struct {
+	// $AVA, in nAVA, a default subnet validator or delegator must stake at
+	// least. A default subnet validator may stake at most MaxStake.
+	MinStake json.Uint64 `json:"minStake"`
+	MaxStake json.Uint64 `json:"maxStake"`
+	// How long, in seconds, a staker may stake for
+	MinStakeDuration json.Uint64 `json:"minStakeDuration"`
+	MaxStakeDuration json.Uint64 `json:"maxStakeDuration"`
+}
+
+// GetStakingParameters returns how much, and for how long, a staker on the
+// default subnet may stake
+func (service *Service) GetStakingParameters(_ *http.Request, _ *struct{}, reply *GetStakingParametersReply) error {
+	service.vm.Ctx.Log.Debug("GetStakingParameters called")
+
+	params := service.vm.stakingParameters()
+	reply.MinStake = json.Uint64(params.MinStake)
+	reply.MaxStake = json.Uint64(params.MaxStake)
+	reply.MinStakeDuration = json.Uint64(params.MinStakeDuration / time.Second)
+	reply.MaxStakeDuration = json.Uint64(params.MaxStakeDuration / time.Second)
+	return nil
+}
+
+// GetBlockByHeightArgs are the arguments for calling GetBlockByHeight
+type GetBlockByHeightArgs struct {
+	// Height of the block. The genesis block has height 0.
+	Height json.Uint64 `json:"height"`
+}
+
+// GetBlockByHeightReply is the response from GetBlockByHeight
+type GetBlockByHeightReply struct {
+	BlockID ids.ID          `json:"blockID"`
+	Bytes   formatting.CB58 `json:"bytes"`
+}
+
+// GetBlockByHeight returns the accepted block with height [args.Height]
+func (service *Service) GetBlockByHeight(_ *http.Request, args *GetBlockByHeightArgs, reply *GetBlockByHeightReply) error {
+	service.vm.Ctx.Log.Debug("GetBlockByHeight called with height %d", args.Height)
+
+	blkID, err := service.vm.GetBlockIDAtHeight(uint64(args.Height))
+	if err != nil {
+		return fmt.Errorf("couldn't get the block at height %d: %w", args.Height, err)
+	}
+	blk, err := service.vm.GetBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	reply.BlockID = blkID
+	reply.Bytes.Bytes = blk.Bytes()
+	return nil
+}
+
 // GetCurrentValidatorsArgs are the arguments for calling GetCurrentValidators
 type GetCurrentValidatorsArgs struct {
 	// Subnet we're listing the validators of
 	// If omitted, defaults to default subnet
 	SubnetID ids.ID `json:"subnetID"`
+
+	// Maximum number of stakers to return and the index of the first one
+	Limit      json.Uint32 `json:"limit"`
+	StartIndex json.Uint64 `json:"startIndex"`
 }
 
 // GetCurrentValidatorsReply are the results from calling GetCurrentValidators
 type GetCurrentValidatorsReply struct {
-	Validators []APIValidator `json:"validators"`
+	Validators []APIStaker `json:"validators"`
+	EndIndex   json.Uint64 `json:"endIndex"`
+	More       bool        `json:"more"`
 }
 
-// GetCurrentValidators returns the list of current validators
+// GetCurrentValidators returns the current stakers of [args.SubnetID], ordered
+// by the time they stop staking. At most [args.Limit] stakers are returned. If
+// [args.Limit] is zero or exceeds 1024, 1024 stakers are returned. To fetch the
+// next page, [reply.EndIndex] should be passed in as [args.StartIndex].
+// [reply.More] is false once the last staker has been returned.
 func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentValidatorsArgs, reply *GetCurrentValidatorsReply) error {
 	service.vm.Ctx.Log.Debug("GetCurrentValidators called")
 
@@ -152,27 +288,7 @@ func (service *Service) GetCurrentValidators(_ *http.Request, args *GetCurrentVa
 		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
 	}
 
-	reply.Validators = make([]APIValidator, validators.Len())
-	for i, tx := range validators.Txs {
-		vdr := tx.Vdr()
-		weight := json.Uint64(vdr.Weight())
-		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
-				ID:          vdr.ID(),
-				StartTime:   json.Uint64(tx.StartTime().Unix()),
-				EndTime:     json.Uint64(tx.EndTime().Unix()),
-				StakeAmount: &weight,
-			}
-		} else {
-			reply.Validators[i] = APIValidator{
-				ID:        vdr.ID(),
-				StartTime: json.Uint64(tx.StartTime().Unix()),
-				EndTime:   json.Uint64(tx.EndTime().Unix()),
-				Weight:    &weight,
-			}
-		}
-	}
-
+	reply.Validators, reply.EndIndex, reply.More = getStakers(validators, args.SubnetID, args.StartIndex, args.Limit)
 	return nil
 }
 
@@ -181,14 +297,22 @@ type GetPendingValidatorsArgs struct {
 	// Subnet we're getting the pending validators of
 	// If omitted, defaults to default subnet
 	SubnetID ids.ID `json:"subnetID"`
+
+	// Maximum number of stakers to return and the index of the first one
+	Limit      json.Uint32 `json:"limit"`
+	StartIndex json.Uint64 `json:"startIndex"`
 }
 
 // GetPendingValidatorsReply are the results from calling GetPendingValidators
 type GetPendingValidatorsReply struct {
-	Validators []APIValidator `json:"validators"`
+	Validators []APIStaker `json:"validators"`
+	EndIndex   json.Uint64 `json:"endIndex"`
+	More       bool        `json:"more"`
 }
 
-// GetPendingValidators returns the list of current validators
+// GetPendingValidators returns the pending stakers of [args.SubnetID], ordered
+// by the time they start staking. Results are paginated the same way as
+// GetCurrentValidators.
 func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingValidatorsArgs, reply *GetPendingValidatorsReply) error {
 	service.vm.Ctx.Log.Debug("GetPendingValidators called")
 
@@ -201,28 +325,60 @@ func (service *Service) GetPendingValidators(_ *http.Request, args *GetPendingVa
 		return fmt.Errorf("couldn't get validators of subnet with ID %s. Does it exist?", args.SubnetID)
 	}
 
-	reply.Validators = make([]APIValidator, validators.Len())
-	for i, tx := range validators.Txs {
+	reply.Validators, reply.EndIndex, reply.More = getStakers(validators, args.SubnetID, args.StartIndex, args.Limit)
+	return nil
+}
+
+// getStakers returns at most [limit] of the stakers in [events], in the order
+// they leave the heap, starting from the staker at [startIndex]. Also returns
+// the index after the last staker returned and whether there are more stakers.
+func getStakers(events *EventHeap, subnetID ids.ID, startIndex json.Uint64, limit json.Uint32) ([]APIStaker, json.Uint64, bool) {
+	if limit == 0 || limit > maxValidatorsToFetch {
+		limit = maxValidatorsToFetch
+	}
+
+	// Sort a copy of the heap so the stakers are in a deterministic order
+	sorted := &EventHeap{
+		SortByStartTime: events.SortByStartTime,
+		Txs:             append([]TimedTx(nil), events.Txs...),
+	}
+	sort.Sort(sorted)
+
+	stakers := []APIStaker{}
+	index := uint64(startIndex)
+	for ; index < uint64(sorted.Len()) && len(stakers) < int(limit); index++ {
+		tx := sorted.Txs[index]
 		vdr := tx.Vdr()
 		weight := json.Uint64(vdr.Weight())
-		if args.SubnetID.Equals(DefaultSubnetID) {
-			reply.Validators[i] = APIValidator{
-				ID:          vdr.ID(),
-				StartTime:   json.Uint64(tx.StartTime().Unix()),
-				EndTime:     json.Uint64(tx.EndTime().Unix()),
-				StakeAmount: &weight,
-			}
-		} else {
-			reply.Validators[i] = APIValidator{
+		staker := APIStaker{
+			APIValidator: APIValidator{
 				ID:        vdr.ID(),
 				StartTime: json.Uint64(tx.StartTime().Unix()),
 				EndTime:   json.Uint64(tx.EndTime().Unix()),
-				Weight:    &weight,
-			}
+			},
+			TxID: tx.ID(),
+		}
+		if !subnetID.Equals(DefaultSubnetID) {
+			staker.Weight = &weight
+			stakers = append(stakers, staker)
+			continue
 		}
-	}
 
-	return nil
+		staker.StakeAmount = &weight
+		switch tx := tx.(type) {
+		case *addDefaultSubnetValidatorTx:
+			destination := tx.Destination
+			shares := json.Uint32(tx.Shares)
+			staker.Destination = &destination
+			staker.DelegationFeeRate = &shares
+		case *addDefaultSubnetDelegatorTx:
+			destination := tx.Destination
+			staker.Destination = &destination
+			staker.Delegator = true
+		}
+		stakers = append(stakers, staker)
+	}
+	return stakers, json.Uint64(index), index < uint64(sorted.Len())
 }
 
 // SampleValidatorsArgs are the arguments for calling SampleValidators
@@ -282,22 +438,19 @@ type GetAccountArgs struct {
 // GetAccountReply is the response from calling GetAccount
 type GetAccountReply struct {
 	Address ids.ShortID `json:"address"`
-	Nonce   json.Uint64 `json:"nonce"`
 	Balance json.Uint64 `json:"balance"`
 }
 
-// GetAccount details given account ID
+// GetAccount returns the amount of $AVA held in UTXOs that the account can
+// spend
 func (service *Service) GetAccount(_ *http.Request, args *GetAccountArgs, reply *GetAccountReply) error {
-	account, err := service.vm.getAccount(service.vm.DB, args.Address)
-	if err != nil && err != database.ErrNotFound {
+	balance, err := service.vm.getBalance(service.vm.DB, args.Address)
+	if err != nil {
 		return errGetAccount
-	} else if err == database.ErrNotFound {
-		account = newAccount(args.Address, 0, 0)
 	}
 
-	reply.Address = account.Address
-	reply.Balance = json.Uint64(account.Balance)
-	reply.Nonce = json.Uint64(account.Nonce)
+	reply.Address = args.Address
+	reply.Balance = json.Uint64(balance)
 	return nil
 }
 
@@ -317,17 +470,12 @@ type ListAccountsReply struct {
 func (service *Service) ListAccounts(_ *http.Request, args *ListAccountsArgs, reply *ListAccountsReply) error {
 	service.vm.Ctx.Log.Debug("platform.listAccounts called for user '%s'", args.Username)
 
-	// db holds the user's info that pertains to the Platform Chain
-	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	// The user
+	user, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
 		return errGetUser
 	}
 
-	// The user
-	user := user{
-		db: userDB,
-	}
-
 	// IDs of accounts controlled by this user
 	accountIDs, err := user.getAccountIDs()
 	if err != nil {
@@ -336,17 +484,14 @@ func (service *Service) ListAccounts(_ *http.Request, args *ListAccountsArgs, re
 
 	var accounts []APIAccount
 	for _, accountID := range accountIDs {
-		account, err := service.vm.getAccount(service.vm.DB, accountID) // Get account whose ID is [accountID]
-		if err != nil && err != database.ErrNotFound {
-			service.vm.Ctx.Log.Error("couldn't get account from database: %v", err)
+		balance, err := service.vm.getBalance(service.vm.DB, accountID) // Get the balance of [accountID]
+		if err != nil {
+			service.vm.Ctx.Log.Error("couldn't get balance from database: %v", err)
 			continue
-		} else if err == database.ErrNotFound {
-			account = newAccount(accountID, 0, 0)
 		}
 		accounts = append(accounts, APIAccount{
 			Address: accountID,
-			Nonce:   json.Uint64(account.Nonce),
-			Balance: json.Uint64(account.Balance),
+			Balance: json.Uint64(balance),
 		})
 	}
 	reply.Accounts = accounts
@@ -363,7 +508,8 @@ type CreateAccountArgs struct {
 
 	// The private key that controls the new account.
 	// If omitted, will generate a new private key belonging
-	// to the user.
+	// to the user. If the user was created from a mnemonic, the
+	// key is derived from its seed.
 	PrivateKey string `json:"privateKey"`
 }
 
@@ -380,26 +526,21 @@ type CreateAccountReply struct {
 func (service *Service) CreateAccount(_ *http.Request, args *CreateAccountArgs, reply *CreateAccountReply) error {
 	service.vm.Ctx.Log.Debug("platform.createAccount called for user '%s'", args.Username)
 
-	// userDB holds the user's info that pertains to the Platform Chain
-	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	// The user creating a new account
+	user, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
 		return errGetUser
 	}
 
-	// The user creating a new account
-	user := user{
-		db: userDB,
-	}
-
 	// private key that controls the new account
 	var privKey *crypto.PrivateKeySECP256K1R
 	// If no private key supplied in args, create a new one
 	if args.PrivateKey == "" {
-		privKeyInt, err := service.vm.factory.NewPrivateKey() // The private key that controls the new account
-		if err != nil {                                       // The account ID is [private key].PublicKey().Address()
+		// The account ID is [private key].PublicKey().Address()
+		privKey, err = service.vm.Ctx.Keystore.NewKey(args.Username, args.Password)
+		if err != nil {
 			return errors.New("problem generating private key")
 		}
-		privKey = privKeyInt.(*crypto.PrivateKeySECP256K1R)
 	} else { // parse provided private key
 		byteFormatter := formatting.CB58{}
 		err := byteFormatter.FromString(args.PrivateKey)
@@ -422,10 +563,261 @@ func (service *Service) CreateAccount(_ *http.Request, args *CreateAccountArgs,
 	return nil
 }
 
+/*
+ ******************************************************
+ ***************** Address Management *****************
+ ******************************************************
+ */
+
+// CreateAddressArgs are the arguments for calling CreateAddress
+type CreateAddressArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CreateAddressReply is the response from calling CreateAddress
+type CreateAddressReply struct {
+	Address ids.ShortID `json:"address"`
+}
+
+// CreateAddress creates an address controlled by the user [args.Username]. If
+// the user was created from a mnemonic, the address's key is derived from its
+// seed.
+func (service *Service) CreateAddress(_ *http.Request, args *CreateAddressArgs, reply *CreateAddressReply) error {
+	service.vm.Ctx.Log.Debug("platform.createAddress called for user '%s'", args.Username)
+
+	user, err := service.vm.getUser(args.Username, args.Password)
+	if err != nil {
+		return errGetUser
+	}
+
+	privKey, err := service.vm.Ctx.Keystore.NewKey(args.Username, args.Password)
+	if err != nil {
+		return fmt.Errorf("problem generating private key: %w", err)
+	}
+	if err := user.putAccount(privKey); err != nil {
+		return fmt.Errorf("problem saving private key: %w", err)
+	}
+
+	reply.Address = privKey.PublicKey().Address()
+	return nil
+}
+
+// ListAddressesArgs are the arguments for calling ListAddresses
+type ListAddressesArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ListAddressesReply is the response from calling ListAddresses
+type ListAddressesReply struct {
+	Addresses []ids.ShortID `json:"addresses"`
+}
+
+// ListAddresses returns the addresses controlled by the user [args.Username]
+func (service *Service) ListAddresses(_ *http.Request, args *ListAddressesArgs, reply *ListAddressesReply) error {
+	service.vm.Ctx.Log.Debug("platform.listAddresses called for user '%s'", args.Username)
+
+	user, err := service.vm.getUser(args.Username, args.Password)
+	if err != nil {
+		return errGetUser
+	}
+
+	addresses, err := user.getAccountIDs()
+	if err != nil {
+		return errGetAccounts
+	}
+	reply.Addresses = addresses
+	return nil
+}
+
+// ExportKeyArgs are the arguments for calling ExportKey
+type ExportKeyArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Address  ids.ShortID `json:"address"`
+}
+
+// ExportKeyReply is the response from calling ExportKey
+type ExportKeyReply struct {
+	// The private key that controls [args.Address]
+	PrivateKey formatting.CB58 `json:"privateKey"`
+}
+
+// ExportKey returns the private key that controls [args.Address], which must
+// be controlled by the user [args.Username]
+func (service *Service) ExportKey(_ *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
+	service.vm.Ctx.Log.Debug("platform.exportKey called for user '%s'", args.Username)
+
+	key, err := service.getKey(args.Username, args.Password, args.Address)
+	if err != nil {
+		return err
+	}
+	reply.PrivateKey.Bytes = key.Bytes()
+	return nil
+}
+
+// ImportKeyArgs are the arguments for calling ImportKey
+type ImportKeyArgs struct {
+	Username   string          `json:"username"`
+	Password   string          `json:"password"`
+	PrivateKey formatting.CB58 `json:"privateKey"`
+}
+
+// ImportKeyReply is the response from calling ImportKey
+type ImportKeyReply struct {
+	// The address controlled by [args.PrivateKey]
+	Address ids.ShortID `json:"address"`
+}
+
+// ImportKey adds a private key to the user [args.Username]
+func (service *Service) ImportKey(_ *http.Request, args *ImportKeyArgs, reply *ImportKeyReply) error {
+	service.vm.Ctx.Log.Debug("platform.importKey called for user '%s'", args.Username)
+
+	user, err := service.vm.getUser(args.Username, args.Password)
+	if err != nil {
+		return errGetUser
+	}
+
+	pk, err := service.vm.factory.ToPrivateKey(args.PrivateKey.Bytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing private key: %w", err)
+	}
+	privKey := pk.(*crypto.PrivateKeySECP256K1R)
+	if err := user.putAccount(privKey); err != nil {
+		return fmt.Errorf("problem saving private key: %w", err)
+	}
+
+	reply.Address = privKey.PublicKey().Address()
+	return nil
+}
+
 type genericTx struct {
 	Tx interface{} `serialize:"true"`
 }
 
+/*
+ ******************************************************
+ **************** Get Pending Rewards ****************
+ ******************************************************
+ */
+
+// APIReward is a reward that an account will receive when a staker finishes
+// staking
+type APIReward struct {
+	// ID of the tx that added the staker
+	TxID ids.ID `json:"txID"`
+
+	// ID of the node being validated
+	NodeID ids.ShortID `json:"nodeID"`
+
+	// Unix time the staker stops staking, at which point the reward is paid
+	EndTime json.Uint64 `json:"endTime"`
+
+	// Amount of $AVA the account will be rewarded with
+	Reward json.Uint64 `json:"reward"`
+}
+
+// GetValidatorUptimeArgs are the arguments for calling GetValidatorUptime
+type GetValidatorUptimeArgs struct {
+	NodeID ids.ShortID `json:"nodeID"`
+}
+
+// GetValidatorUptimeReply are the results from calling GetValidatorUptime
+type GetValidatorUptimeReply struct {
+	// Fraction of the time this node was running, since the validator started
+	// validating, that the validator was connected to it
+	Uptime    float64     `json:"uptime"`
+	StartTime json.Uint64 `json:"startTime"`
+}
+
+// GetValidatorUptime returns the uptime of the current default subnet
+// validator [args.NodeID] since it started validating, as observed by this
+// node
+func (service *Service) GetValidatorUptime(_ *http.Request, args *GetValidatorUptimeArgs, reply *GetValidatorUptimeReply) error {
+	service.vm.Ctx.Log.Debug("platform.getValidatorUptime called with %s", args.NodeID)
+
+	uptime, startTime, err := service.vm.uptime(service.vm.DB, args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the uptime of %s: %w", args.NodeID, err)
+	}
+	reply.Uptime = uptime
+	reply.StartTime = json.Uint64(startTime.Unix())
+	return nil
+}
+
+// GetPendingRewardsArgs are the arguments for calling GetPendingRewards
+type GetPendingRewardsArgs struct {
+	// Account that rewards are paid to
+	Address ids.ShortID `json:"address"`
+}
+
+// GetPendingRewardsReply are the results from calling GetPendingRewards
+type GetPendingRewardsReply struct {
+	// Rewards that will be paid to the account
+	Rewards []APIReward `json:"rewards"`
+
+	// Sum of [Rewards]
+	Total json.Uint64 `json:"total"`
+}
+
+// GetPendingRewards returns the rewards that [args.Address] will receive when
+// the current stakers of the default subnet finish staking. An account is
+// rewarded for the validators and delegators that pay their stake back to it.
+// An account is also rewarded with a validator's share of the rewards of the
+// delegators that delegated to that validator.
+//
+// Rewards are only paid if the staker's proposal to be rewarded is committed,
+// so these are the rewards the account will receive in the best case.
+func (service *Service) GetPendingRewards(_ *http.Request, args *GetPendingRewardsArgs, reply *GetPendingRewardsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getPendingRewards called")
+
+	stakers, err := service.vm.getCurrentValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators of the default subnet: %v", err)
+	}
+
+	total := uint64(0)
+	for _, staker := range stakers.Txs {
+		amount := uint64(0)
+		switch staker := staker.(type) {
+		case *addDefaultSubnetValidatorTx:
+			if staker.Destination.Equals(args.Address) {
+				amount = reward(staker.Duration(), staker.Wght, InflationRate)
+			}
+		case *addDefaultSubnetDelegatorTx:
+			parentTx, err := stakers.getDefaultSubnetStaker(staker.NodeID)
+			if err != nil {
+				return err
+			}
+			delegatorReward, validatorReward := splitReward(reward(staker.Duration(), staker.Wght, InflationRate), parentTx.Shares)
+			if staker.Destination.Equals(args.Address) {
+				amount += delegatorReward
+			}
+			if parentTx.Destination.Equals(args.Address) {
+				amount += validatorReward
+			}
+		}
+		if amount == 0 {
+			continue
+		}
+
+		newTotal, err := math.Add64(total, amount)
+		if err != nil {
+			return err
+		}
+		total = newTotal
+		reply.Rewards = append(reply.Rewards, APIReward{
+			TxID:    staker.ID(),
+			NodeID:  staker.Vdr().ID(),
+			EndTime: json.Uint64(staker.EndTime().Unix()),
+			Reward:  json.Uint64(amount),
+		})
+	}
+	reply.Total = json.Uint64(total)
+	return nil
+}
+
 /*
  ******************************************************
  ************ Add Validators to Subnets ***************
@@ -436,8 +828,9 @@ type genericTx struct {
 type AddDefaultSubnetValidatorArgs struct {
 	APIDefaultSubnetValidator
 
-	// Next unused nonce of the account the staked $AVA and tx fee are paid from
-	PayerNonce json.Uint64 `json:"payerNonce"`
+	// Address that pays the staked $AVA and tx fee. The returned transaction
+	// must be signed by this address' key.
+	Payer ids.ShortID `json:"payer"`
 }
 
 // AddDefaultSubnetValidatorResponse is the response from a call to AddDefaultSubnetValidator
@@ -455,6 +848,11 @@ func (service *Service) AddDefaultSubnetValidator(_ *http.Request, args *AddDefa
 		args.ID = service.vm.Ctx.NodeID
 	}
 
+	ins, outs, err := service.vm.spend(service.vm.DB, args.Payer, args.weight(), service.vm.txFee)
+	if err != nil {
+		return fmt.Errorf("problem spending the payer's UTXOs: %w", err)
+	}
+
 	// Create the transaction
 	tx := addDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
 		DurationValidator: DurationValidator{
@@ -465,7 +863,8 @@ func (service *Service) AddDefaultSubnetValidator(_ *http.Request, args *AddDefa
 			Start: uint64(args.StartTime),
 			End:   uint64(args.EndTime),
 		},
-		Nonce:       uint64(args.PayerNonce),
+		Ins:         ins,
+		Outs:        outs,
 		Destination: args.Destination,
 		NetworkID:   service.vm.Ctx.NetworkID,
 		Shares:      uint32(args.DelegationFeeRate),
@@ -486,8 +885,9 @@ type AddDefaultSubnetDelegatorArgs struct {
 
 	Destination ids.ShortID `json:"destination"`
 
-	// Next unused nonce of the account the staked $AVA and tx fee are paid from
-	PayerNonce json.Uint64 `json:"payerNonce"`
+	// Address that pays the staked $AVA and tx fee. The returned transaction
+	// must be signed by this address' key.
+	Payer ids.ShortID `json:"payer"`
 }
 
 // AddDefaultSubnetDelegatorResponse is the response from a call to AddDefaultSubnetDelegator
@@ -506,6 +906,11 @@ func (service *Service) AddDefaultSubnetDelegator(_ *http.Request, args *AddDefa
 		args.ID = service.vm.Ctx.NodeID
 	}
 
+	ins, outs, err := service.vm.spend(service.vm.DB, args.Payer, args.weight(), service.vm.txFee)
+	if err != nil {
+		return fmt.Errorf("problem spending the payer's UTXOs: %w", err)
+	}
+
 	// Create the transaction
 	tx := addDefaultSubnetDelegatorTx{UnsignedAddDefaultSubnetDelegatorTx: UnsignedAddDefaultSubnetDelegatorTx{
 		DurationValidator: DurationValidator{
@@ -517,7 +922,8 @@ func (service *Service) AddDefaultSubnetDelegator(_ *http.Request, args *AddDefa
 			End:   uint64(args.EndTime),
 		},
 		NetworkID:   service.vm.Ctx.NetworkID,
-		Nonce:       uint64(args.PayerNonce),
+		Ins:         ins,
+		Outs:        outs,
 		Destination: args.Destination,
 	}}
 
@@ -537,8 +943,9 @@ type AddNonDefaultSubnetValidatorArgs struct {
 	// ID of subnet to validate
 	SubnetID ids.ID `json:"subnetID"`
 
-	// Next unused nonce of the account the tx fee is paid from
-	PayerNonce json.Uint64 `json:"payerNonce"`
+	// Address that pays the tx fee. The returned transaction must be signed
+	// by this address' key.
+	Payer ids.ShortID `json:"payer"`
 }
 
 // AddNonDefaultSubnetValidatorResponse is the response from a call to AddNonDefaultSubnetValidator
@@ -550,6 +957,11 @@ type AddNonDefaultSubnetValidatorResponse struct {
 // AddNonDefaultSubnetValidator adds a validator to a subnet other than the default subnet
 // Returns the unsigned transaction, which must be signed using Sign
 func (service *Service) AddNonDefaultSubnetValidator(_ *http.Request, args *AddNonDefaultSubnetValidatorArgs, response *AddNonDefaultSubnetValidatorResponse) error {
+	ins, outs, err := service.vm.spend(service.vm.DB, args.Payer, 0, service.vm.txFee)
+	if err != nil {
+		return fmt.Errorf("problem spending the payer's UTXOs: %w", err)
+	}
+
 	tx := addNonDefaultSubnetValidatorTx{
 		UnsignedAddNonDefaultSubnetValidatorTx: UnsignedAddNonDefaultSubnetValidatorTx{
 			SubnetValidator: SubnetValidator{
@@ -564,7 +976,8 @@ func (service *Service) AddNonDefaultSubnetValidator(_ *http.Request, args *AddN
 				Subnet: args.SubnetID,
 			},
 			NetworkID: service.vm.Ctx.NetworkID,
-			Nonce:     uint64(args.PayerNonce),
+			Ins:       ins,
+			Outs:      outs,
 		},
 		ControlSigs: nil,
 		PayerSig:    [crypto.SECP256K1RSigLen]byte{},
@@ -614,11 +1027,10 @@ func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignRespons
 	service.vm.Ctx.Log.Debug("platform.sign called")
 
 	// Get the key of the Signer
-	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	user, err := service.vm.getUser(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("couldn't get data for user '%s'. Does user exist?", args.Username)
 	}
-	user := user{db: db}
 
 	key, err := user.getKey(args.Signer) // Key of [args.Signer]
 	if err != nil {
@@ -776,7 +1188,10 @@ func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubn
 // IssueTxArgs are the arguments to IssueTx
 type IssueTxArgs struct {
 	// Tx being sent to the network
-	Tx formatting.CB58 `json:"tx"`
+	Tx string `json:"tx"`
+
+	// Encoding of Tx. CB58 by default.
+	Encoding formatting.Encoding `json:"encoding"`
 }
 
 // IssueTxResponse is the response from IssueTx
@@ -787,31 +1202,80 @@ type IssueTxResponse struct {
 
 // IssueTx issues the transaction [args.Tx] to the network
 func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *IssueTxResponse) error {
-	genTx := genericTx{}
-	if err := Codec.Unmarshal(args.Tx.Bytes, &genTx); err != nil {
+	txBytes, err := args.Encoding.Decode(args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
+	}
+	txID, err := service.issueTx(txBytes)
+	if err != nil {
 		return err
 	}
+	response.TxID = txID
+	return nil
+}
+
+// issueTx issues the transaction [txBytes] to the network, and returns its ID
+func (service *Service) issueTx(txBytes []byte) (ids.ID, error) {
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(txBytes, &genTx); err != nil {
+		return ids.ID{}, err
+	}
 
 	switch tx := genTx.Tx.(type) {
 	case TimedTx:
 		if err := tx.initialize(service.vm); err != nil {
-			return fmt.Errorf("error initializing tx: %s", err)
+			return ids.ID{}, fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedEvents.Push(tx)
 		defer service.vm.resetTimer()
-		response.TxID = tx.ID()
-		return nil
+		return tx.ID(), nil
 	case *CreateSubnetTx:
 		if err := tx.initialize(service.vm); err != nil {
-			return fmt.Errorf("error initializing tx: %s", err)
+			return ids.ID{}, fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
 		defer service.vm.resetTimer()
-		response.TxID = tx.ID
-		return nil
+		return tx.ID, nil
 	default:
-		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		return ids.ID{}, errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+	}
+}
+
+// DecodeTxArgs are the arguments to DecodeTx
+type DecodeTxArgs struct {
+	// Tx being decoded
+	Tx string `json:"tx"`
+
+	// Encoding of Tx. CB58 by default.
+	Encoding formatting.Encoding `json:"encoding"`
+}
+
+// DecodeTxResponse is the response from DecodeTx
+type DecodeTxResponse struct {
+	// JSON representation of the transaction
+	Tx stdjson.RawMessage `json:"tx"`
+}
+
+// DecodeTx returns the JSON representation of the transaction [args.Tx], in
+// the form IssueTx accepts. The transaction isn't verified or issued.
+func (service *Service) DecodeTx(_ *http.Request, args *DecodeTxArgs, response *DecodeTxResponse) error {
+	service.vm.Ctx.Log.Debug("platform.decodeTx called")
+
+	txBytes, err := args.Encoding.Decode(args.Tx)
+	if err != nil {
+		return fmt.Errorf("problem decoding transaction: %w", err)
 	}
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(txBytes, &genTx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+	txJSON, err := Codec.MarshalCanonicalJSON(&genTx.Tx)
+	if err != nil {
+		return fmt.Errorf("problem encoding transaction: %w", err)
+	}
+
+	response.Tx = txJSON
+	return nil
 }
 
 /*
@@ -825,8 +1289,9 @@ type CreateSubnetArgs struct {
 	// The ID member of APISubnet is ignored
 	APISubnet
 
-	// Nonce of the account that pays the transaction fee
-	PayerNonce json.Uint64 `json:"payerNonce"`
+	// Address that pays the tx fee. The returned transaction must be signed
+	// by this address' key.
+	Payer ids.ShortID `json:"payer"`
 }
 
 // CreateSubnetResponse is the response from a call to CreateSubnet
@@ -840,11 +1305,17 @@ type CreateSubnetResponse struct {
 func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, response *CreateSubnetResponse) error {
 	service.vm.Ctx.Log.Debug("platform.createSubnet called")
 
+	ins, outs, err := service.vm.spend(service.vm.DB, args.Payer, 0, service.vm.creationTxFee)
+	if err != nil {
+		return fmt.Errorf("problem spending the payer's UTXOs: %w", err)
+	}
+
 	// Create the transaction
 	tx := CreateSubnetTx{
 		UnsignedCreateSubnetTx: UnsignedCreateSubnetTx{
 			NetworkID:   service.vm.Ctx.NetworkID,
-			Nonce:       uint64(args.PayerNonce),
+			Ins:         ins,
+			Outs:        outs,
 			ControlKeys: args.ControlKeys,
 			Threshold:   uint16(args.Threshold),
 		},
@@ -871,6 +1342,10 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 
 // CreateBlockchainArgs is the arguments for calling CreateBlockchain
 type CreateBlockchainArgs struct {
+	// ID of the subnet that validates the new blockchain
+	// If omitted, defaults to default subnet
+	SubnetID ids.ID `json:"subnetID"`
+
 	// ID of the VM the new blockchain is running
 	VMID string `json:"vmID"`
 
@@ -886,6 +1361,10 @@ type CreateBlockchainArgs struct {
 	Method      string      `json:"method"`
 	Endpoint    string      `json:"endpoint"`
 	GenesisData interface{} `json:"genesisData"`
+
+	// The configuration the new blockchain's VM is initialized with. Its
+	// format is defined by the VM.
+	ConfigData formatting.CB58 `json:"configData"`
 }
 
 // CreateGenesisReply is the reply from a call to CreateGenesis
@@ -942,9 +1421,12 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		return errNoMethodWithGenesis
 	}
 
+	if args.SubnetID.IsZero() {
+		args.SubnetID = DefaultSubnetID
+	}
+
 	// TODO: Should use the key store to sign this transaction.
-	// TODO: Nonce shouldn't always be 0
-	tx, err := service.vm.newCreateChainTx(0, genesisBytes, vmID, fxIDs, args.Name, service.vm.Ctx.NetworkID, key)
+	tx, err := service.vm.newCreateChainTx(args.SubnetID, genesisBytes, args.ConfigData.Bytes, vmID, fxIDs, args.Name, service.vm.Ctx.NetworkID, key)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
 	}
@@ -958,6 +1440,42 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 	return nil
 }
 
+// ValidatesArgs are the arguments to Validates
+type ValidatesArgs struct {
+	// ID of the subnet whose blockchains are returned
+	// If omitted, defaults to default subnet
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// ValidatesResponse is the response from calling Validates
+type ValidatesResponse struct {
+	// IDs of the blockchains validated by the subnet
+	BlockchainIDs []ids.ID `json:"blockchainIDs"`
+}
+
+// Validates returns the IDs of the blockchains validated by [args.SubnetID]
+func (service *Service) Validates(_ *http.Request, args *ValidatesArgs, response *ValidatesResponse) error {
+	service.vm.Ctx.Log.Debug("platform.validates called")
+
+	if args.SubnetID.IsZero() {
+		args.SubnetID = DefaultSubnetID
+	} else if _, err := service.vm.getSubnet(service.vm.DB, args.SubnetID); err != nil {
+		return fmt.Errorf("couldn't get subnet with ID %s: %w", args.SubnetID, err)
+	}
+
+	chains, err := service.vm.getChains(service.vm.DB)
+	if err != nil {
+		return errDBChains
+	}
+	response.BlockchainIDs = []ids.ID{}
+	for _, chain := range chains {
+		if chain.SubnetID.Equals(args.SubnetID) {
+			response.BlockchainIDs = append(response.BlockchainIDs, chain.ID())
+		}
+	}
+	return nil
+}
+
 // GetBlockchainStatusArgs is the arguments for calling GetBlockchainStatus
 // [BlockchainID] is the blockchain to get the status of.
 type GetBlockchainStatusArgs struct {
@@ -1026,3 +1544,174 @@ func (service *Service) chainExists(blockID ids.ID, chainID ids.ID) (bool, error
 
 	return false, nil
 }
+
+/*
+ ******************************************************
+ ************ Import/Export $AVA to/from the X-Chain **
+ ******************************************************
+ */
+
+// ExportAVAArgs are the arguments to ExportAVA
+type ExportAVAArgs struct {
+	// User that controls [From]
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The address whose UTXOs the exported $AVA is paid from
+	From ids.ShortID `json:"from"`
+
+	// Amount of $AVA to export
+	Amount json.Uint64 `json:"amount"`
+
+	// Address on the X-Chain that can import the $AVA
+	To ids.ShortID `json:"to"`
+}
+
+// ExportAVAReply is the reply from ExportAVA
+type ExportAVAReply struct {
+	// ID of the transaction that exports the $AVA
+	TxID ids.ID `json:"txID"`
+}
+
+// ExportAVA issues a transaction that moves [args.Amount] $AVA from UTXOs that
+// [args.From] can spend to the X-Chain. Once the transaction is accepted, the $AVA must
+// be imported on the X-Chain by [args.To].
+func (service *Service) ExportAVA(_ *http.Request, args *ExportAVAArgs, reply *ExportAVAReply) error {
+	service.vm.Ctx.Log.Debug("platform.exportAVA called")
+
+	key, err := service.getKey(args.Username, args.Password, args.From)
+	if err != nil {
+		return err
+	}
+
+	tx, err := service.vm.newExportTx(service.vm.Ctx.NetworkID, uint64(args.Amount), args.To, key)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+	service.vm.resetTimer()
+
+	reply.TxID = tx.ID()
+	return nil
+}
+
+// ImportAVAArgs are the arguments to ImportAVA
+type ImportAVAArgs struct {
+	// User that controls [To]
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// The address the imported $AVA is sent to
+	To ids.ShortID `json:"to"`
+}
+
+// ImportAVAReply is the reply from ImportAVA
+type ImportAVAReply struct {
+	// ID of the transaction that imports the $AVA
+	TxID ids.ID `json:"txID"`
+}
+
+// ImportAVA issues a transaction that moves all of the $AVA that the X-Chain
+// has exported to [args.To] into UTXOs that [args.To] can spend
+func (service *Service) ImportAVA(_ *http.Request, args *ImportAVAArgs, reply *ImportAVAReply) error {
+	service.vm.Ctx.Log.Debug("platform.importAVA called")
+
+	key, err := service.getKey(args.Username, args.Password, args.To)
+	if err != nil {
+		return err
+	}
+
+	ins, err := service.vm.getImportableUTXOs(args.To)
+	if err != nil {
+		return err
+	}
+	if len(ins) == 0 {
+		return errNoImportableUTXOs
+	}
+
+	tx, err := service.vm.newImportTx(service.vm.Ctx.NetworkID, ins, key)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+	service.vm.resetTimer()
+
+	reply.TxID = tx.ID()
+	return nil
+}
+
+// GetUTXOsArgs are the arguments to GetUTXOs
+type GetUTXOsArgs struct {
+	// The addresses whose UTXOs are returned
+	Addresses []ids.ShortID `json:"addresses"`
+
+	// If provided, the UTXOs the chain [SourceChain] has exported to this
+	// chain that haven't been imported yet are returned instead
+	SourceChain string `json:"sourceChain"`
+}
+
+// GetUTXOsReply is the reply from GetUTXOs
+type GetUTXOsReply struct {
+	// The UTXOs referenced by the addresses
+	UTXOs []formatting.CB58 `json:"utxos"`
+}
+
+// GetUTXOs returns the UTXOs that reference at least one of [args.Addresses].
+// If [args.SourceChain] is provided, the UTXOs that chain has exported to this
+// chain, but that haven't been imported yet, are returned instead, so an
+// interrupted transfer between chains can be completed.
+func (service *Service) GetUTXOs(_ *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
+	service.vm.Ctx.Log.Debug("platform.getUTXOs called")
+
+	var (
+		utxos []*ava.UTXO
+		err   error
+	)
+	if args.SourceChain == "" {
+		utxos, err = service.vm.getUTXOs(service.vm.DB, args.Addresses)
+	} else {
+		sourceChain, lookupErr := service.vm.Ctx.BCLookup.Lookup(args.SourceChain)
+		if lookupErr != nil {
+			if sourceChain, lookupErr = ids.FromString(args.SourceChain); lookupErr != nil {
+				return fmt.Errorf("problem parsing source chain '%s': %w", args.SourceChain, lookupErr)
+			}
+		}
+		if sourceChain.Equals(service.vm.Ctx.ChainID) {
+			return errSourceChainIsThisChain
+		}
+		utxos, err = service.vm.getAtomicUTXOs(sourceChain, args.Addresses)
+	}
+	if err != nil {
+		return fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	reply.UTXOs = make([]formatting.CB58, len(utxos))
+	for i, utxo := range utxos {
+		utxoBytes, err := Codec.Marshal(utxo)
+		if err != nil {
+			return fmt.Errorf("problem serializing UTXO: %w", err)
+		}
+		reply.UTXOs[i].Bytes = utxoBytes
+	}
+	return nil
+}
+
+// getKey returns the key that controls the account [address], which must be
+// controlled by the user [username]
+func (service *Service) getKey(username, password string, address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
+	user, err := service.vm.getUser(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get data for user '%s'. Does user exist?", username)
+	}
+
+	key, err := user.getKey(address)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' doesn't control account %s", username, address)
+	}
+	if !bytes.Equal(key.PublicKey().Address().Bytes(), address.Bytes()) { // sanity check
+		return nil, errors.New("got unexpected key from database")
+	}
+	return key, nil
+}
@@ -4,12 +4,22 @@
 package platformvm
 
 import (
+	"bytes"
 	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/components/addressbook"
 )
 
 func TestAddDefaultSubnetValidator(t *testing.T) {
-	expectedJSONString := `{"startTime":"0","endtime":"0","id":null,"destination":null,"delegationFeeRate":"0","payerNonce":"0"}`
+	expectedJSONString := `{"startTime":"0","endtime":"0","id":null,"destination":null,"delegationFeeRate":"0","payer":null}`
 	args := AddDefaultSubnetValidatorArgs{}
 	bytes, err := json.Marshal(&args)
 	if err != nil {
@@ -32,3 +42,397 @@ func TestCreateBlockchainArgsParsing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGetPendingRewards(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	// Delegate to the genesis validator of keys[0], which keeps all of its
+	// delegators' rewards, and pay the stake back to keys[1]
+	delTx, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultStakeAmount,
+		uint64(defaultValidateStartTime.Unix()),
+		uint64(defaultValidateEndTime.Unix()),
+		keys[0].PublicKey().Address(),
+		keys[1].PublicKey().Address(),
+		testNetworkID,
+		keys[1],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	currentValidators.Add(delTx)
+	if err := vm.putCurrentValidators(vm.DB, currentValidators, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	stakeReward := reward(delTx.Duration(), defaultStakeAmount, InflationRate)
+
+	reply := GetPendingRewardsReply{}
+	if err := service.GetPendingRewards(nil, &GetPendingRewardsArgs{Address: keys[0].PublicKey().Address()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Rewards) != 2 {
+		t.Fatalf("Should have been rewarded for validating and for the delegation but got %d rewards", len(reply.Rewards))
+	}
+	if expected := 2 * stakeReward; uint64(reply.Total) != expected {
+		t.Fatalf("Expected total reward %d but got %d", expected, reply.Total)
+	}
+
+	reply = GetPendingRewardsReply{}
+	if err := service.GetPendingRewards(nil, &GetPendingRewardsArgs{Address: keys[1].PublicKey().Address()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Rewards) != 1 {
+		t.Fatalf("Delegator's share of the reward should be empty but got %d rewards", len(reply.Rewards))
+	}
+	if uint64(reply.Total) != stakeReward {
+		t.Fatalf("Expected total reward %d but got %d", stakeReward, reply.Total)
+	}
+}
+
+type testUptimes struct {
+	uptime float64
+	start  time.Time
+}
+
+func (u *testUptimes) Uptime(_ ids.ShortID, start time.Time) (float64, error) {
+	u.start = start
+	return u.uptime, nil
+}
+
+func TestGetValidatorUptime(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+	nodeID := keys[0].PublicKey().Address()
+
+	reply := GetValidatorUptimeReply{}
+	if err := service.GetValidatorUptime(nil, &GetValidatorUptimeArgs{NodeID: nodeID}, &reply); err == nil {
+		t.Fatalf("Should have errored when uptimes aren't tracked")
+	}
+
+	uptimes := &testUptimes{uptime: 0.8}
+	vm.Ctx.Uptimes = uptimes
+	if err := service.GetValidatorUptime(nil, &GetValidatorUptimeArgs{NodeID: nodeID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Uptime != 0.8 {
+		t.Fatalf("Uptime should have been 0.8 but was %f", reply.Uptime)
+	}
+	// The uptime is measured from when the validator started validating
+	if !uptimes.start.Equal(defaultValidateStartTime) || int64(reply.StartTime) != defaultValidateStartTime.Unix() {
+		t.Fatalf("Uptime should have been measured from %s but was measured from %s", defaultValidateStartTime, uptimes.start)
+	}
+
+	if err := service.GetValidatorUptime(nil, &GetValidatorUptimeArgs{NodeID: ids.NewShortID([20]byte{1})}, &reply); err == nil {
+		t.Fatalf("Should have errored for a node that isn't validating")
+	}
+}
+
+func TestGetTimestamp(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetTimestampReply{}
+	if err := service.GetTimestamp(nil, &GetTimestampArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultGenesisTime.Unix(); int64(reply.Timestamp) != expected {
+		t.Fatalf("Expected timestamp %d but got %d", expected, reply.Timestamp)
+	}
+
+	// Advancing the local clock doesn't advance the chain's timestamp
+	vm.clock.Set(defaultGenesisTime.Add(time.Hour))
+	if err := service.GetTimestamp(nil, &GetTimestampArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultGenesisTime.Unix(); int64(reply.Timestamp) != expected {
+		t.Fatalf("Expected timestamp %d but got %d", expected, reply.Timestamp)
+	}
+}
+
+func TestGetTxFee(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetTxFeeReply{}
+	if err := service.GetTxFee(nil, nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if uint64(reply.TxFee) != defaultTxFee {
+		t.Fatalf("Expected tx fee %d but got %d", defaultTxFee, reply.TxFee)
+	}
+	if uint64(reply.CreationTxFee) != defaultCreationTxFee {
+		t.Fatalf("Expected creation tx fee %d but got %d", defaultCreationTxFee, reply.CreationTxFee)
+	}
+}
+
+func TestGetStakingParameters(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetStakingParametersReply{}
+	if err := service.GetStakingParameters(nil, nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if uint64(reply.MinStake) != MinimumStakeAmount {
+		t.Fatalf("Expected minimum stake %d but got %d", MinimumStakeAmount, reply.MinStake)
+	}
+	if uint64(reply.MaxStakeDuration) != uint64(MaximumStakingDuration/time.Second) {
+		t.Fatalf("Expected maximum staking duration %s but got %ds", MaximumStakingDuration, reply.MaxStakeDuration)
+	}
+}
+
+func TestValidates(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	defaultChain, err := vm.newCreateChainTx(DefaultSubnetID, nil, nil, avm.ID, nil, "default", testNetworkID, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	subnetChain, err := vm.newCreateChainTx(testSubnet1.ID, nil, nil, avm.ID, nil, "subnet", testNetworkID, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putChains(vm.DB, []*CreateChainTx{defaultChain, subnetChain}); err != nil {
+		t.Fatal(err)
+	}
+
+	response := ValidatesResponse{}
+	if err := service.Validates(nil, &ValidatesArgs{SubnetID: testSubnet1.ID}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.BlockchainIDs) != 1 || !response.BlockchainIDs[0].Equals(subnetChain.ID()) {
+		t.Fatalf("Subnet should only validate %s but validates %v", subnetChain.ID(), response.BlockchainIDs)
+	}
+
+	response = ValidatesResponse{}
+	if err := service.Validates(nil, &ValidatesArgs{}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.BlockchainIDs) != 1 || !response.BlockchainIDs[0].Equals(defaultChain.ID()) {
+		t.Fatalf("Default subnet should only validate %s but validates %v", defaultChain.ID(), response.BlockchainIDs)
+	}
+
+	if err := service.Validates(nil, &ValidatesArgs{SubnetID: ids.Empty.Prefix(1)}, &ValidatesResponse{}); err == nil {
+		t.Fatalf("Should have failed because the subnet doesn't exist")
+	}
+}
+
+func TestGetCurrentValidatorsPagination(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	seen := ids.ShortSet{}
+	args := GetCurrentValidatorsArgs{Limit: 2}
+	for {
+		reply := GetCurrentValidatorsReply{}
+		if err := service.GetCurrentValidators(nil, &args, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if len(reply.Validators) > 2 {
+			t.Fatalf("Returned %d validators but the limit is 2", len(reply.Validators))
+		}
+		for _, vdr := range reply.Validators {
+			if seen.Contains(vdr.ID) {
+				t.Fatalf("Validator %s was returned twice", vdr.ID)
+			}
+			seen.Add(vdr.ID)
+			if vdr.StakeAmount == nil || uint64(*vdr.StakeAmount) != defaultStakeAmount {
+				t.Fatalf("Validator should have reported its stake amount")
+			}
+			if vdr.DelegationFeeRate == nil || uint32(*vdr.DelegationFeeRate) != NumberOfShares {
+				t.Fatalf("Validator should have reported its delegation fee rate")
+			}
+			if vdr.Destination == nil || !vdr.Destination.Equals(vdr.ID) {
+				t.Fatalf("Validator should have reported its destination")
+			}
+		}
+		if !reply.More {
+			break
+		}
+		args.StartIndex = reply.EndIndex
+	}
+	if seen.Len() != len(keys) {
+		t.Fatalf("Should have returned %d validators but returned %d", len(keys), seen.Len())
+	}
+}
+
+func TestAddressManagement(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := &keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	createReply := CreateAddressReply{}
+	if err := service.CreateAddress(nil, &CreateAddressArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &createReply); err != nil {
+		t.Fatal(err)
+	}
+
+	importReply := ImportKeyReply{}
+	if err := service.ImportKey(nil, &ImportKeyArgs{
+		Username:   "bob",
+		Password:   "launch",
+		PrivateKey: formatting.CB58{Bytes: keys[0].Bytes()},
+	}, &importReply); err != nil {
+		t.Fatal(err)
+	}
+	if expected := keys[0].PublicKey().Address(); !importReply.Address.Equals(expected) {
+		t.Fatalf("Imported key should control %s but controls %s", expected, importReply.Address)
+	}
+
+	listReply := ListAddressesReply{}
+	if err := service.ListAddresses(nil, &ListAddressesArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &listReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(listReply.Addresses) != 2 {
+		t.Fatalf("Should have listed %d addresses but listed %d", 2, len(listReply.Addresses))
+	}
+	if !listReply.Addresses[0].Equals(createReply.Address) || !listReply.Addresses[1].Equals(importReply.Address) {
+		t.Fatalf("Listed the wrong addresses")
+	}
+
+	exportReply := ExportKeyReply{}
+	if err := service.ExportKey(nil, &ExportKeyArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  importReply.Address,
+	}, &exportReply); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(exportReply.PrivateKey.Bytes, keys[0].Bytes()) {
+		t.Fatalf("Exported the wrong key")
+	}
+
+	if err := service.ExportKey(nil, &ExportKeyArgs{
+		Username: "bob",
+		Password: "launch",
+		Address:  keys[1].PublicKey().Address(),
+	}, &ExportKeyReply{}); err == nil {
+		t.Fatalf("Should have errored due to the user not controlling the address")
+	}
+}
+
+func TestAddressBookSharedBetweenChains(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	ks := &keystore.Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+	if err := ks.CreateUser(nil, &keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	vm.Ctx.Keystore = ks.NewBlockchainKeyStore(vm.Ctx.ChainID)
+
+	// Store keys[0] the way this chain stored keys before address books
+	db, err := vm.Ctx.Keystore.GetDatabase("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountIDsBytes, err := Codec.Marshal([]ids.ShortID{keys[0].PublicKey().Address()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(accountIDsKey, accountIDsBytes); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(keys[0].PublicKey().Address().Bytes(), keys[0].Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add keys[1] to the address book on another chain
+	otherChain := ks.NewBlockchainKeyStore(ids.NewID([32]byte{1}))
+	sharedDB, err := otherChain.GetSharedDatabase("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := addressbook.New(sharedDB).Put(keys[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	listReply := ListAddressesReply{}
+	if err := service.ListAddresses(nil, &ListAddressesArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &listReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(listReply.Addresses) != 2 {
+		t.Fatalf("Should have listed %d addresses but listed %d", 2, len(listReply.Addresses))
+	}
+	if has, err := db.Has(accountIDsKey); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Legacy account IDs should have been deleted")
+	}
+	for _, key := range keys[:2] {
+		if _, err := service.getKey("bob", "launch", key.PublicKey().Address()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDecodeTx(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	tx := addDefaultSubnetValidatorTx{UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
+		DurationValidator: DurationValidator{
+			Validator: Validator{
+				NodeID: keys[0].PublicKey().Address(),
+				Wght:   MinimumStakeAmount,
+			},
+			Start: uint64(defaultGenesisTime.Unix()),
+			End:   uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()),
+		},
+		Destination: keys[1].PublicKey().Address(),
+		NetworkID:   vm.Ctx.NetworkID,
+		Shares:      NumberOfShares,
+	}}
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := DecodeTxResponse{}
+	if err := service.DecodeTx(nil, &DecodeTxArgs{
+		Tx: formatting.CB58{Bytes: txBytes}.String(),
+	}, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	// The JSON holds the whole tx
+	genTx := genericTx{}
+	if err := Codec.UnmarshalCanonicalJSON(response.Tx, &genTx.Tx); err != nil {
+		t.Fatal(err)
+	}
+	decodedBytes, err := Codec.Marshal(genTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decodedBytes, txBytes) {
+		t.Fatalf("Decoded the wrong tx")
+	}
+}
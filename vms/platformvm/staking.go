@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	stdmath "math"
+	"time"
+)
+
+var (
+	errStakeBounds         = errors.New("the minimum stake must be positive and at most the maximum stake")
+	errStakeDurationBounds = errors.New("the minimum staking duration must be positive and at most the maximum staking duration")
+)
+
+// DefaultStakingParameters are the staking parameters of a VM that wasn't
+// given any
+var DefaultStakingParameters = StakingParameters{
+	MinStake:         MinimumStakeAmount,
+	MaxStake:         stdmath.MaxUint64,
+	MinStakeDuration: MinimumStakingDuration,
+	MaxStakeDuration: MaximumStakingDuration,
+}
+
+// StakingParameters bound how much, and for how long, a staker on the default
+// subnet may stake. They're set per network.
+type StakingParameters struct {
+	// $AVA, in nAVA, a default subnet validator or delegator must stake at
+	// least. A default subnet validator may stake at most MaxStake.
+	MinStake, MaxStake uint64
+	// How long a staker may stake for
+	MinStakeDuration, MaxStakeDuration time.Duration
+}
+
+// Verify returns an error if no stake satisfies the parameters
+func (p StakingParameters) Verify() error {
+	switch {
+	case p.MinStake == 0 || p.MinStake > p.MaxStake:
+		return errStakeBounds
+	case p.MinStakeDuration <= 0 || p.MinStakeDuration > p.MaxStakeDuration:
+		return errStakeDurationBounds
+	default:
+		return nil
+	}
+}
+
+// verifyStakingDuration returns an error if stakers can't stake for
+// [duration]
+func (p StakingParameters) verifyStakingDuration(duration time.Duration) error {
+	switch {
+	case duration < p.MinStakeDuration:
+		return errStakeTooShort
+	case duration > p.MaxStakeDuration:
+		return errStakeTooLong
+	default:
+		return nil
+	}
+}
@@ -20,6 +20,17 @@ type DecisionTx interface {
 	SemanticVerify(database.Database) (onAccept func(), err error)
 }
 
+// AtomicTx is a DecisionTx that moves $AVA to or from the X-Chain through
+// shared memory
+type AtomicTx interface {
+	DecisionTx
+
+	// Write the changes this transaction makes to the memory shared with the
+	// X-Chain into [sharedDB]. These changes are committed atomically with the
+	// changes to the state of this chain.
+	AtomicAccept(sharedDB database.Database) error
+}
+
 // StandardBlock being accepted results in the transactions contained in the
 // block to be accepted and committed to the chain.
 type StandardBlock struct {
@@ -58,6 +69,7 @@ func (sb *StandardBlock) Verify() error {
 
 	sb.onAcceptDB = versiondb.New(pdb)
 	funcs := []func(){}
+	atomicTxs := []AtomicTx{}
 	for _, tx := range sb.Txs {
 		onAccept, err := tx.SemanticVerify(sb.onAcceptDB)
 		if err != nil {
@@ -66,6 +78,9 @@ func (sb *StandardBlock) Verify() error {
 		if onAccept != nil {
 			funcs = append(funcs, onAccept)
 		}
+		if atomicTx, ok := tx.(AtomicTx); ok {
+			atomicTxs = append(atomicTxs, atomicTx)
+		}
 	}
 
 	if numFuncs := len(funcs); numFuncs == 1 {
@@ -78,11 +93,40 @@ func (sb *StandardBlock) Verify() error {
 		}
 	}
 
+	if len(atomicTxs) > 0 {
+		sb.onAcceptWrite = func(batch database.Batch) error {
+			return sb.vm.writeAtomicTxs(batch, atomicTxs)
+		}
+	}
+
 	sb.vm.currentBlocks[sb.ID().Key()] = sb
 	sb.parentBlock().addChild(sb)
 	return nil
 }
 
+// writeAtomicTxs writes [batch] along with the changes [txs] make to the
+// memory shared with the X-Chain
+func (vm *VM) writeAtomicTxs(batch database.Batch, txs []AtomicTx) error {
+	if vm.Ctx.SharedMemory == nil {
+		return errNoSharedMemory
+	}
+	smDB := vm.Ctx.SharedMemory.GetDatabase(vm.avm)
+	defer vm.Ctx.SharedMemory.ReleaseDatabase(vm.avm)
+
+	vsmDB := versiondb.New(smDB)
+	for _, tx := range txs {
+		if err := tx.AtomicAccept(vsmDB); err != nil {
+			return err
+		}
+	}
+
+	sharedBatch, err := vsmDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	return core.WriteAll(batch, sharedBatch)
+}
+
 // newStandardBlock returns a new *StandardBlock where the block's parent, a
 // decision block, has ID [parentID].
 func (vm *VM) newStandardBlock(parentID ids.ID, txs []DecisionTx) (*StandardBlock, error) {
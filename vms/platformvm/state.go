@@ -10,19 +10,23 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 // This file contains methods of VM that deal with getting/putting values from database
 
 var (
-	errEmptyAccountAddress = errors.New("account has empty address")
+	errEmptyAddress = errors.New("empty address")
 )
 
 // TODO: Cache prefixed IDs or use different way of keying into database
 const (
 	currentValidatorsPrefix uint64 = iota
 	pendingValidatorsPrefix
+	importedUTXOsPrefix
 )
 
 // get the validators currently validating the specified subnet
@@ -103,47 +107,120 @@ func (vm *VM) putPendingValidators(db database.Database, validators *EventHeap,
 	return nil
 }
 
-// get the account with the specified Address
-// If account does not exist in database, return new account
-func (vm *VM) getAccount(db database.Database, address ids.ShortID) (Account, error) {
+// returns true if the UTXO with ID [utxoID] has been imported from shared memory
+func (vm *VM) isImported(db database.Database, utxoID ids.ID) bool {
+	return vm.State.GetStatus(db, utxoID.Prefix(importedUTXOsPrefix)) == choices.Accepted
+}
+
+// mark the UTXO with ID [utxoID] as imported from shared memory
+func (vm *VM) putImported(db database.Database, utxoID ids.ID) error {
+	return vm.State.PutStatus(db, utxoID.Prefix(importedUTXOsPrefix), choices.Accepted)
+}
+
+// get the UTXO with ID [utxoID] from the platform chain's UTXO set
+func (vm *VM) getUTXO(db database.Database, utxoID ids.ID) (*ava.UTXO, error) {
+	utxoIntf, err := vm.State.Get(db, utxoTypeID, utxoID)
+	if err != nil {
+		return nil, err
+	}
+	utxo, ok := utxoIntf.(*ava.UTXO)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve *ava.UTXO from database but got different type")
+		return nil, errDBUTXO
+	}
+	return utxo, nil
+}
+
+// put [utxo] in the platform chain's UTXO set and index it under each of the
+// addresses that can spend it
+func (vm *VM) putUTXO(db database.Database, utxo *ava.UTXO) error {
+	utxoID := utxo.InputID()
+	if err := vm.State.Put(db, utxoTypeID, utxoID, &storedUTXO{UTXO: *utxo}); err != nil {
+		return errDBPutUTXO
+	}
+
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	for _, address := range out.Addrs {
+		utxoIDs, err := vm.getUTXOIDs(db, address)
+		if err != nil {
+			return err
+		}
+		if err := vm.putUTXOIDs(db, address, append(utxoIDs, utxoID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove the UTXO with ID [utxoID] from the platform chain's UTXO set
+func (vm *VM) removeUTXO(db database.Database, utxoID ids.ID) error {
+	utxo, err := vm.getUTXO(db, utxoID)
+	if err != nil {
+		return err
+	}
+	if err := vm.State.Put(db, utxoTypeID, utxoID, nil); err != nil {
+		return errDBPutUTXO
+	}
+
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return errWrongUTXOType
+	}
+	for _, address := range out.Addrs {
+		utxoIDs, err := vm.getUTXOIDs(db, address)
+		if err != nil {
+			return err
+		}
+		for i, id := range utxoIDs {
+			if id.Equals(utxoID) {
+				utxoIDs = append(utxoIDs[:i], utxoIDs[i+1:]...)
+				break
+			}
+		}
+		if err := vm.putUTXOIDs(db, address, utxoIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get the IDs of the UTXOs that [address] is able to spend
+// If there are none, returns an empty list
+func (vm *VM) getUTXOIDs(db database.Database, address ids.ShortID) (utxoIDList, error) {
 	if address.IsZero() {
-		return Account{}, errEmptyAccountAddress
+		return nil, errEmptyAddress
 	}
 
 	longID := address.LongID()
-
-	// see if account exists
-	exists, err := vm.State.Has(db, accountTypeID, longID)
+	has, err := vm.State.Has(db, utxoIDsTypeID, longID)
 	if err != nil {
-		return Account{}, err
+		return nil, err
 	}
-	if !exists { // account doesn't exist so return new, empty account
-		return Account{
-			Address: address,
-			Nonce:   0,
-			Balance: 0,
-		}, nil
+	if !has {
+		return utxoIDList{}, nil
 	}
 
-	accountInterface, err := vm.State.Get(db, accountTypeID, longID)
+	utxoIDsIntf, err := vm.State.Get(db, utxoIDsTypeID, longID)
 	if err != nil {
-		return Account{}, err
+		return nil, err
 	}
-	account, ok := accountInterface.(Account)
+	utxoIDs, ok := utxoIDsIntf.(utxoIDList)
 	if !ok {
-		vm.Ctx.Log.Warn("expected to retrieve Account from database but got different type")
-		return Account{}, errDBAccount
+		vm.Ctx.Log.Warn("expected to retrieve utxoIDList from database but got different type")
+		return nil, errDBUTXO
 	}
-	return account, nil
+	return utxoIDs, nil
 }
 
-// put an account in [db]
-func (vm *VM) putAccount(db database.Database, account Account) error {
-	err := vm.State.Put(db, accountTypeID, account.Address.LongID(), account)
-	if err != nil {
-		return errDBPutAccount
+// put the IDs of the UTXOs that [address] is able to spend
+func (vm *VM) putUTXOIDs(db database.Database, address ids.ShortID, utxoIDs utxoIDList) error {
+	if len(utxoIDs) == 0 {
+		return vm.State.Put(db, utxoIDsTypeID, address.LongID(), nil)
 	}
-	return nil
+	return vm.State.Put(db, utxoIDsTypeID, address.LongID(), utxoIDs)
 }
 
 // get the blockchains that exist
@@ -244,14 +321,25 @@ func (vm *VM) registerDBTypes() {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
 
-	unmarshalAccountFunc := func(bytes []byte) (interface{}, error) {
-		var account Account
-		if err := Codec.Unmarshal(bytes, &account); err != nil {
+	unmarshalUTXOFunc := func(bytes []byte) (interface{}, error) {
+		utxo := storedUTXO{}
+		if err := Codec.Unmarshal(bytes, &utxo); err != nil {
+			return nil, err
+		}
+		return &utxo.UTXO, nil
+	}
+	if err := vm.State.RegisterType(utxoTypeID, unmarshalUTXOFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
+	unmarshalUTXOIDsFunc := func(bytes []byte) (interface{}, error) {
+		utxoIDs := utxoIDList{}
+		if err := Codec.Unmarshal(bytes, &utxoIDs); err != nil {
 			return nil, err
 		}
-		return account, nil
+		return utxoIDs, nil
 	}
-	if err := vm.State.RegisterType(accountTypeID, unmarshalAccountFunc); err != nil {
+	if err := vm.State.RegisterType(utxoIDsTypeID, unmarshalUTXOIDsFunc); err != nil {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
 
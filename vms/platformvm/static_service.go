@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/math"
 )
 
 // Note that since an AVA network has exactly one Platform Chain,
@@ -22,6 +23,7 @@ import (
 var (
 	errAccountHasNoValue    = errors.New("account has no value")
 	errValidatorAddsNoValue = errors.New("validator would have already unstaked")
+	errSupplyOverflow       = errors.New("total $AVA in the genesis overflows")
 )
 
 // StaticService defines the static API methods exposed by the platform VM
@@ -31,7 +33,6 @@ type StaticService struct{}
 // that exists at the chain's genesis.
 type APIAccount struct {
 	Address ids.ShortID `json:"address"`
-	Nonce   json.Uint64 `json:"nonce"`
 	Balance json.Uint64 `json:"balance"`
 }
 
@@ -71,11 +72,13 @@ type APIDefaultSubnetValidator struct {
 // APIChain defines a chain that exists
 // at the network's genesis.
 // [GenesisData] is the initial state of the chain.
+// [ConfigData] is the configuration the chain's VM is initialized with.
 // [VMID] is the ID of the VM this chain runs.
 // [FxIDs] are the IDs of the Fxs the chain supports.
 // [Name] is a human-readable, non-unique name for the chain.
 type APIChain struct {
 	GenesisData formatting.CB58 `json:"genesisData"`
+	ConfigData  formatting.CB58 `json:"configData"`
 	VMID        ids.ID          `json:"vmID"`
 	FxIDs       []ids.ID        `json:"fxIDs"`
 	Name        string          `json:"name"`
@@ -127,12 +130,21 @@ func (g *Genesis) Initialize() error {
 
 // BuildGenesis build the genesis state of the Platform Chain (and thereby the AVA network.)
 func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, reply *BuildGenesisReply) error {
+	// The $AVA held by accounts and staked by validators must fit in a uint64,
+	// so balances and stakes can't overflow
+	supply := uint64(0)
+
 	// Specify the accounts on the Platform chain that exist at genesis.
 	accounts := []Account(nil)
 	for _, account := range args.Accounts {
 		if account.Balance == 0 {
 			return errAccountHasNoValue
 		}
+		newSupply, err := math.Add64(supply, uint64(account.Balance))
+		if err != nil {
+			return errSupplyOverflow
+		}
+		supply = newSupply
 		accounts = append(accounts, newAccount(
 			account.Address, // ID
 			0,               // nonce
@@ -150,6 +162,11 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 		if uint64(validator.EndTime) <= uint64(args.Time) {
 			return errValidatorAddsNoValue
 		}
+		newSupply, err := math.Add64(supply, weight)
+		if err != nil {
+			return errSupplyOverflow
+		}
+		supply = newSupply
 
 		tx := &addDefaultSubnetValidatorTx{
 			UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
@@ -162,7 +179,6 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 					End:   uint64(validator.EndTime),
 				},
 				NetworkID:   uint32(args.NetworkID),
-				Nonce:       0,
 				Destination: validator.Destination,
 			},
 		}
@@ -182,11 +198,12 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 		tx := &CreateChainTx{
 			UnsignedCreateChainTx: UnsignedCreateChainTx{
 				NetworkID:   uint32(args.NetworkID),
-				Nonce:       0,
+				SubnetID:    DefaultSubnetID,
 				ChainName:   chain.Name,
 				VMID:        chain.VMID,
 				FxIDs:       chain.FxIDs,
 				GenesisData: chain.GenesisData.Bytes,
+				ConfigData:  chain.ConfigData.Bytes,
 			},
 		}
 		if err := tx.initialize(nil); err != nil {
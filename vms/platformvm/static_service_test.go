@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"bytes"
+	stdmath "math"
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
@@ -40,6 +41,10 @@ func TestBuildGenesis(t *testing.T) {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x13, 0x4d, 0x79, 0x20, 0x46,
 		0x61, 0x76, 0x6f, 0x72, 0x69, 0x74, 0x65, 0x20,
 		0x45, 0x70, 0x69, 0x73, 0x6f, 0x64, 0x65, 0x53,
@@ -59,7 +64,8 @@ func TestBuildGenesis(t *testing.T) {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x05,
 	}
 
 	addr, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
@@ -213,3 +219,37 @@ func TestBuildGenesisInvalidEndtime(t *testing.T) {
 		t.Fatalf("Should have errored due to an invalid end time")
 	}
 }
+
+func TestBuildGenesisSupplyOverflow(t *testing.T) {
+	id, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	account := APIAccount{
+		Address: id,
+		Balance: json.Uint64(stdmath.MaxUint64),
+	}
+	weight := json.Uint64(987654321)
+	validator := APIDefaultSubnetValidator{
+		APIValidator: APIValidator{
+			StartTime: 0,
+			EndTime:   15,
+			Weight:    &weight,
+			ID:        id,
+		},
+		Destination: id,
+	}
+
+	args := BuildGenesisArgs{
+		Accounts: []APIAccount{
+			account,
+		},
+		Validators: []APIDefaultSubnetValidator{
+			validator,
+		},
+		Time: 5,
+	}
+	reply := BuildGenesisReply{}
+
+	ss := StaticService{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != errSupplyOverflow {
+		t.Fatalf("Should have errored due to the supply overflowing")
+	}
+}
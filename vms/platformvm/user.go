@@ -9,91 +9,115 @@ import (
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/vms/components/addressbook"
 )
 
-// Key in the database whose corresponding value is the list of
-// account IDs this user controls
+// Before users' keys were kept in their address books, the list of account
+// IDs a user controls was stored under this key in the user's database on
+// this chain, and each key under the ID of the account it controls
 var accountIDsKey = ids.Empty.Bytes()
 
 var errDbNil = errors.New("db uninitialized")
 
 type user struct {
-	// This user's database, acquired from the keystore
-	db database.Database
+	// This user's address book, acquired from the keystore and shared with the
+	// other chains
+	book *addressbook.Book
+}
+
+// getUser returns the user [username]. Keys this chain stored in the user's
+// database on this chain before keys were kept in address books are moved
+// into the user's address book first.
+func (vm *VM) getUser(username, password string) (*user, error) {
+	db, err := vm.Ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, err
+	}
+	sharedDB, err := vm.Ctx.Keystore.GetSharedDatabase(username, password)
+	if err != nil {
+		return nil, err
+	}
+	book := addressbook.New(sharedDB)
+	if err := migrateKeys(db, book); err != nil {
+		return nil, err
+	}
+	return &user{book: book}, nil
+}
+
+// migrateKeys moves the keys stored in [db] the legacy way into [book]
+func migrateKeys(db database.Database, book *addressbook.Book) error {
+	accountIDsBytes, err := db.Get(accountIDsKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return errDB
+	}
+	accountIDs := []ids.ShortID{}
+	if err := Codec.Unmarshal(accountIDsBytes, &accountIDs); err != nil {
+		return err
+	}
+
+	factory := crypto.FactorySECP256K1R{}
+	batch := db.NewBatch()
+	for _, accountID := range accountIDs {
+		skBytes, err := db.Get(accountID.Bytes())
+		if err != nil {
+			return errDB
+		}
+		sk, err := factory.ToPrivateKey(skBytes)
+		if err != nil {
+			return err
+		}
+		// Keys are put in the book before they're deleted, so that a key is
+		// never lost. Putting a key that's already in the book does nothing,
+		// so an interrupted migration can be repeated.
+		if err := book.Put(sk.(*crypto.PrivateKeySECP256K1R)); err != nil {
+			return err
+		}
+		if err := batch.Delete(accountID.Bytes()); err != nil {
+			return errDB
+		}
+	}
+	if err := batch.Delete(accountIDsKey); err != nil {
+		return errDB
+	}
+	return batch.Write()
 }
 
 // Get the IDs of the accounts controlled by this user
 func (u *user) getAccountIDs() ([]ids.ShortID, error) {
-	if u.db == nil {
+	if u.book == nil {
 		return nil, errDbNil
 	}
 
-	// If user has no accounts, return empty list
-	hasAccounts, err := u.db.Has(accountIDsKey)
+	accountIDs, err := u.book.Addresses()
 	if err != nil {
 		return nil, errDB
 	}
-	if !hasAccounts {
+	// If user has no accounts, return empty list
+	if accountIDs == nil {
 		return make([]ids.ShortID, 0), nil
 	}
-	// User has accounts. Get them.
-	bytes, err := u.db.Get(accountIDsKey)
-	if err != nil {
-		return nil, errDB
-	}
-	accountIDs := []ids.ShortID{}
-	if err := Codec.Unmarshal(bytes, &accountIDs); err != nil {
-		return nil, err
-	}
 	return accountIDs, nil
 }
 
 // controlsAccount returns true iff this user controls the account
 // with the specified ID
 func (u *user) controlsAccount(ID ids.ShortID) (bool, error) {
-	if u.db == nil {
+	if u.book == nil {
 		return false, errDbNil
 	}
-
-	if _, err := u.db.Get(ID.Bytes()); err == nil {
-		return true, nil
-	}
-	return false, nil
+	return u.book.Contains(ID)
 }
 
 // putAccount persists that this user controls the account whose ID is
 // [privKey].PublicKey().Address()
 func (u *user) putAccount(privKey *crypto.PrivateKeySECP256K1R) error {
-	newAccountID := privKey.PublicKey().Address() // Account thie privKey controls
-	controlsAccount, err := u.controlsAccount(newAccountID)
-	if err != nil {
-		return err
-	}
-	if controlsAccount { // user already controls this account. Do nothing.
-		return nil
-	}
-
-	err = u.db.Put(newAccountID.Bytes(), privKey.Bytes()) // Account ID --> private key
-	if err != nil {
-		return errDB
-	}
-
-	accountIDs := make([]ids.ShortID, 0) // Add account to list of accounts user controls
-	userHasAccounts, err := u.db.Has(accountIDsKey)
-	if err != nil {
-		return errDB
-	}
-	if userHasAccounts { // Get accountIDs this user already controls, if they exist
-		if accountIDs, err = u.getAccountIDs(); err != nil {
-			return errDB
-		}
+	if u.book == nil {
+		return errDbNil
 	}
-	accountIDs = append(accountIDs, newAccountID)
-	bytes, err := Codec.Marshal(accountIDs)
-	if err != nil {
-		return err
-	}
-	if err := u.db.Put(accountIDsKey, bytes); err != nil {
+	if err := u.book.Put(privKey); err != nil {
 		return errDB
 	}
 	return nil
@@ -101,21 +125,8 @@ func (u *user) putAccount(privKey *crypto.PrivateKeySECP256K1R) error {
 
 // Key returns the private key that controls the account with the specified ID
 func (u *user) getKey(accountID ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
-	if u.db == nil {
+	if u.book == nil {
 		return nil, errDbNil
 	}
-
-	factory := crypto.FactorySECP256K1R{}
-	bytes, err := u.db.Get(accountID.Bytes())
-	if err != nil {
-		return nil, err
-	}
-	sk, err := factory.ToPrivateKey(bytes)
-	if err != nil {
-		return nil, err
-	}
-	if sk, ok := sk.(*crypto.PrivateKeySECP256K1R); ok {
-		return sk, nil
-	}
-	return nil, errDB
+	return u.book.Key(accountID)
 }
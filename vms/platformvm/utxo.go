@@ -0,0 +1,348 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errInputsNotSortedUnique = errors.New("inputs not sorted and unique")
+	errOutputsNotSorted      = errors.New("outputs not sorted")
+	errWrongInputType        = errors.New("only secp256k1fx transfer inputs can be spent")
+	errWrongOutputType       = errors.New("only secp256k1fx transfer outputs can be produced")
+	errMultisigOutput        = errors.New("outputs must be spendable by a single signature")
+	errWrongSigIndices       = errors.New("input must be signed by exactly the signer of the tx")
+	errInputAmountMismatch   = errors.New("input amount doesn't match the amount of the UTXO it spends")
+	errInputOverflow         = errors.New("inputs overflowed uint64")
+	errOutputOverflow        = errors.New("outputs overflowed uint64")
+	errInsufficientFunds     = errors.New("insufficient funds")
+	errWrongUTXOType         = errors.New("only secp256k1fx transfer outputs can be spent")
+	errUnspendableUTXO       = errors.New("UTXO can't be spent by the signer of the tx")
+)
+
+// storedUTXO is an unspent output in the platform chain's state. All of the
+// $AVA that isn't staked is held in UTXOs that can be spent by a single
+// address.
+type storedUTXO struct {
+	ava.UTXO `serialize:"true"`
+}
+
+// Bytes returns the binary representation of [u]
+func (u *storedUTXO) Bytes() []byte {
+	bytes, _ := Codec.Marshal(u)
+	return bytes
+}
+
+// utxoIDList is a list of UTXO IDs
+type utxoIDList []ids.ID
+
+// Bytes returns the binary representation of [lst]
+func (lst utxoIDList) Bytes() []byte {
+	bytes, _ := Codec.Marshal(lst)
+	return bytes
+}
+
+// syntacticVerifySpend verifies that [ins] and [outs] are well-formed
+func syntacticVerifySpend(ins []*ava.TransferableInput, outs []*ava.TransferableOutput) error {
+	for _, in := range ins {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+	}
+	if !ava.IsSortedAndUniqueTransferableInputs(ins) {
+		return errInputsNotSortedUnique
+	}
+	for _, out := range outs {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+		// Only UTXOs with a threshold of 1 can be spent on this chain, so an
+		// output that needs more signatures would lock its $AVA forever
+		transferOut, ok := out.Out.(*secp256k1fx.TransferOutput)
+		if !ok {
+			return errWrongOutputType
+		}
+		if transferOut.Threshold != 1 {
+			return errMultisigOutput
+		}
+	}
+	if !ava.IsSortedTransferableOutputs(outs, Codec) {
+		return errOutputsNotSorted
+	}
+	return nil
+}
+
+// semanticVerifySpend consumes the UTXOs spent by [ins] and produces [outs] as
+// the outputs of the transaction [txID]. Each input must be spendable by
+// [address], the signer of the transaction, and the inputs must provide
+// [burned] more $AVA than the outputs, e.g. staked $AVA and the tx fee. Any
+// excess $AVA is burned. The resulting UTXO set is written to [db].
+func (vm *VM) semanticVerifySpend(
+	db database.Database,
+	txID ids.ID,
+	address ids.ShortID,
+	ins []*ava.TransferableInput,
+	outs []*ava.TransferableOutput,
+	burned uint64,
+) error {
+	consumed, err := vm.consumeInputs(db, address, ins)
+	if err != nil {
+		return err
+	}
+	produced, err := vm.produceOutputs(db, txID, outs)
+	if err != nil {
+		return err
+	}
+	if produced, err = math.Add64(produced, burned); err != nil {
+		return errOutputOverflow
+	}
+	if consumed < produced {
+		return errInsufficientFunds
+	}
+	return nil
+}
+
+// consumeInputs removes the UTXOs spent by [ins] from [db] and returns the
+// amount of $AVA they hold. Each input must be spendable by [address].
+func (vm *VM) consumeInputs(db database.Database, address ids.ShortID, ins []*ava.TransferableInput) (uint64, error) {
+	currentTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return 0, err
+	}
+
+	consumed := uint64(0)
+	for _, in := range ins {
+		if assetID := in.AssetID(); !assetID.Equals(vm.ava) {
+			return 0, errAssetNotAVA
+		}
+		input, ok := in.In.(*secp256k1fx.TransferInput)
+		if !ok {
+			return 0, errWrongInputType
+		}
+
+		utxoID := in.InputID()
+		spentUTXO, err := vm.getUTXO(db, utxoID)
+		if err != nil {
+			return 0, fmt.Errorf("couldn't find UTXO %s: %w", utxoID, err)
+		}
+		out, err := vm.verifySpend(spentUTXO, address, uint64(currentTime.Unix()))
+		if err != nil {
+			return 0, err
+		}
+		if len(input.SigIndices) != 1 ||
+			int(input.SigIndices[0]) >= len(out.Addrs) ||
+			!out.Addrs[input.SigIndices[0]].Equals(address) {
+			return 0, errWrongSigIndices
+		}
+		if input.Amount() != out.Amount() {
+			return 0, errInputAmountMismatch
+		}
+		if consumed, err = math.Add64(consumed, input.Amount()); err != nil {
+			return 0, errInputOverflow
+		}
+
+		if err := vm.removeUTXO(db, utxoID); err != nil {
+			return 0, err
+		}
+	}
+	return consumed, nil
+}
+
+// produceOutputs adds [outs] to [db] as the outputs of the transaction [txID]
+// and returns the amount of $AVA they hold
+func (vm *VM) produceOutputs(db database.Database, txID ids.ID, outs []*ava.TransferableOutput) (uint64, error) {
+	produced := uint64(0)
+	for i, out := range outs {
+		if assetID := out.AssetID(); !assetID.Equals(vm.ava) {
+			return 0, errAssetNotAVA
+		}
+		if _, ok := out.Out.(*secp256k1fx.TransferOutput); !ok {
+			return 0, errWrongOutputType
+		}
+		newProduced, err := math.Add64(produced, out.Output().Amount())
+		if err != nil {
+			return 0, errOutputOverflow
+		}
+		produced = newProduced
+
+		if err := vm.putUTXO(db, &ava.UTXO{
+			UTXOID: ava.UTXOID{
+				TxID:        txID,
+				OutputIndex: uint32(i),
+			},
+			Asset: out.Asset,
+			Out:   out.Out,
+		}); err != nil {
+			return 0, err
+		}
+	}
+	return produced, nil
+}
+
+// verifySpend returns the output of [utxo] if it can be spent by [address] at
+// time [currentTime]
+func (vm *VM) verifySpend(utxo *ava.UTXO, address ids.ShortID, currentTime uint64) (*secp256k1fx.TransferOutput, error) {
+	if assetID := utxo.AssetID(); !assetID.Equals(vm.ava) {
+		return nil, errAssetNotAVA
+	}
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return nil, errWrongUTXOType
+	}
+	if out.Locktime > currentTime || out.Threshold != 1 || !containsAddress(out.Addrs, address) {
+		return nil, errUnspendableUTXO
+	}
+	return out, nil
+}
+
+// spend returns inputs that consume enough of the UTXOs in [db] that are
+// spendable by [address] to pay [amount] plus [fee], and an output that
+// returns the change to [address]
+func (vm *VM) spend(db database.Database, address ids.ShortID, amount, fee uint64) ([]*ava.TransferableInput, []*ava.TransferableOutput, error) {
+	amountWithFee, err := math.Add64(amount, fee)
+	if err != nil {
+		return nil, nil, errOutputOverflow
+	}
+
+	currentTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	utxoIDs, err := vm.getUTXOIDs(db, address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spent := uint64(0)
+	ins := []*ava.TransferableInput{}
+	for _, utxoID := range utxoIDs {
+		if spent >= amountWithFee {
+			break
+		}
+
+		spendable, err := vm.getUTXO(db, utxoID)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, err := vm.verifySpend(spendable, address, uint64(currentTime.Unix()))
+		if err != nil {
+			continue // [address] can't spend this UTXO yet
+		}
+		if spent, err = math.Add64(spent, out.Amount()); err != nil {
+			return nil, nil, errInputOverflow
+		}
+
+		sigIndex := uint32(0)
+		for i, addr := range out.Addrs {
+			if addr.Equals(address) {
+				sigIndex = uint32(i)
+			}
+		}
+		ins = append(ins, &ava.TransferableInput{
+			UTXOID: spendable.UTXOID,
+			Asset:  spendable.Asset,
+			In: &secp256k1fx.TransferInput{
+				Amt:   out.Amount(),
+				Input: secp256k1fx.Input{SigIndices: []uint32{sigIndex}},
+			},
+		})
+	}
+	if spent < amountWithFee {
+		return nil, nil, errInsufficientFunds
+	}
+	ava.SortTransferableInputs(ins)
+
+	outs := []*ava.TransferableOutput{}
+	if change := spent - amountWithFee; change > 0 {
+		outs = append(outs, vm.newOutput(change, address))
+	}
+	return ins, outs, nil
+}
+
+// getBalance returns the amount of $AVA held in UTXOs that [address] can spend
+func (vm *VM) getBalance(db database.Database, address ids.ShortID) (uint64, error) {
+	currentTime, err := vm.getTimestamp(db)
+	if err != nil {
+		return 0, err
+	}
+
+	utxoIDs, err := vm.getUTXOIDs(db, address)
+	if err != nil {
+		return 0, err
+	}
+
+	balance := uint64(0)
+	for _, utxoID := range utxoIDs {
+		held, err := vm.getUTXO(db, utxoID)
+		if err != nil {
+			return 0, err
+		}
+		out, err := vm.verifySpend(held, address, uint64(currentTime.Unix()))
+		if err != nil {
+			continue
+		}
+		if balance, err = math.Add64(balance, out.Amount()); err != nil {
+			return 0, errInputOverflow
+		}
+	}
+	return balance, nil
+}
+
+// getUTXOs returns the UTXOs that reference at least one of [addrs]
+func (vm *VM) getUTXOs(db database.Database, addrs []ids.ShortID) ([]*ava.UTXO, error) {
+	utxoIDs := ids.Set{}
+	for _, addr := range addrs {
+		addrUTXOIDs, err := vm.getUTXOIDs(db, addr)
+		if err != nil {
+			return nil, err
+		}
+		utxoIDs.Add(addrUTXOIDs...)
+	}
+
+	utxos := []*ava.UTXO{}
+	for _, utxoID := range utxoIDs.List() {
+		utxo, err := vm.getUTXO(db, utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
+// newOutput returns an output that pays [amount] $AVA to [address]
+func (vm *VM) newOutput(amount uint64, address ids.ShortID) *ava.TransferableOutput {
+	return &ava.TransferableOutput{
+		Asset: ava.Asset{ID: vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{address},
+			},
+		},
+	}
+}
+
+// newUTXO returns a UTXO that pays [amount] $AVA to [address]
+func (vm *VM) newUTXO(txID ids.ID, outputIndex uint32, amount uint64, address ids.ShortID) *ava.UTXO {
+	out := vm.newOutput(amount, address)
+	return &ava.UTXO{
+		UTXOID: ava.UTXOID{
+			TxID:        txID,
+			OutputIndex: outputIndex,
+		},
+		Asset: out.Asset,
+		Out:   out.Out,
+	}
+}
@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/ava"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestSpendReturnsChange(t *testing.T) {
+	vm := defaultVM()
+	addr := keys[0].PublicKey().Address()
+
+	ins, outs, err := vm.spend(vm.DB, addr, 10, vm.txFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 1 {
+		t.Fatalf("expected %d input(s) but got %d", 1, len(ins))
+	}
+	if len(outs) != 1 {
+		t.Fatalf("expected %d output(s) but got %d", 1, len(outs))
+	}
+	if expected := defaultBalance - 10 - vm.txFee; outs[0].Output().Amount() != expected {
+		t.Fatalf("change should be %d but is %d", expected, outs[0].Output().Amount())
+	}
+	if err := syntacticVerifySpend(ins, outs); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := vm.spend(vm.DB, addr, defaultBalance-vm.txFee+1, vm.txFee); err != errInsufficientFunds {
+		t.Fatalf("should have errored because the address doesn't hold enough $AVA")
+	}
+}
+
+func TestSemanticVerifySpend(t *testing.T) {
+	vm := defaultVM()
+	addr := keys[0].PublicKey().Address()
+	txID := ids.Empty.Prefix(2)
+
+	ins, outs, err := vm.spend(vm.DB, addr, 10, vm.txFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vm.semanticVerifySpend(versiondb.New(vm.DB), txID, keys[1].PublicKey().Address(), ins, outs, 10+vm.txFee); err == nil {
+		t.Fatalf("should have errored because the signer can't spend the UTXOs")
+	}
+	if err := vm.semanticVerifySpend(versiondb.New(vm.DB), txID, addr, ins, outs, 11+vm.txFee); err != errInsufficientFunds {
+		t.Fatalf("should have errored because the inputs don't cover the outputs")
+	}
+
+	db := versiondb.New(vm.DB)
+	if err := vm.semanticVerifySpend(db, txID, addr, ins, outs, 10+vm.txFee); err != nil {
+		t.Fatal(err)
+	}
+	balance, err := vm.getBalance(db, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := defaultBalance - 10 - vm.txFee; balance != expected {
+		t.Fatalf("balance should be %d but is %d", expected, balance)
+	}
+	if err := vm.semanticVerifySpend(db, ids.Empty.Prefix(3), addr, ins, outs, 10+vm.txFee); err == nil {
+		t.Fatalf("should have errored because the UTXOs were already spent")
+	}
+}
+
+func TestSyntacticVerifySpendMultisigOutput(t *testing.T) {
+	vm := defaultVM()
+	addr := keys[0].PublicKey().Address()
+
+	ins, outs, err := vm.spend(vm.DB, addr, 10, vm.txFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Pay the 10 $AVA to an output that needs 2 of 2 signatures
+	addrs := []ids.ShortID{addr, keys[1].PublicKey().Address()}
+	ids.SortShortIDs(addrs)
+	outs = append(outs, &ava.TransferableOutput{
+		Asset: ava.Asset{ID: vm.ava},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 10,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs:     addrs,
+			},
+		},
+	})
+	ava.SortTransferableOutputs(outs, Codec)
+
+	if err := syntacticVerifySpend(ins, outs); err != errMultisigOutput {
+		t.Fatalf("should have errored because the output can't be spent on this chain")
+	}
+}
@@ -20,21 +20,24 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 const (
 	// For putting/getting values from state
-	accountTypeID uint64 = iota
+	utxoTypeID uint64 = iota
 	validatorsTypeID
 	chainsTypeID
 	blockTypeID
 	subnetsTypeID
+	utxoIDsTypeID
 
 	// Delta is the synchrony bound used for safe decision making
 	Delta = 10 * time.Second // TODO change to longer period (2 minutes?) before release
@@ -45,7 +48,8 @@ const (
 	// BatchSize is the number of decision transaction to place into a block
 	BatchSize = 30
 
-	// TODO: Incorporate these constants + turn them into governable parameters
+	// The staking parameters of a VM that wasn't given any. See
+	// DefaultStakingParameters.
 
 	// MinimumStakeAmount is the minimum amount of $AVA one must bond to be a staker
 	MinimumStakeAmount = 10 * units.MicroAva
@@ -61,6 +65,10 @@ const (
 	// NumberOfShares is the number of shares that a delegator is
 	// rewarded
 	NumberOfShares = 1000000
+
+	// Number of public keys recovered from transaction signatures that are
+	// cached, so transactions verified more than once are only recovered once
+	recoverCacheSize = 2048
 )
 
 var (
@@ -70,6 +78,9 @@ var (
 	// DefaultSubnetID is the ID of the default subnet
 	DefaultSubnetID = ids.Empty
 
+	// ChainID is the ID of the platform chain
+	ChainID = ids.Empty
+
 	timestampKey         = ids.NewID([32]byte{'t', 'i', 'm', 'e'})
 	currentValidatorsKey = ids.NewID([32]byte{'c', 'u', 'r', 'r', 'e', 'n', 't'})
 	pendingValidatorsKey = ids.NewID([32]byte{'p', 'e', 'n', 'd', 'i', 'n', 'g'})
@@ -87,13 +98,14 @@ var (
 	errDBPutCurrentValidators = errors.New("couldn't put current validators in database")
 	errDBPendingValidators    = errors.New("couldn't retrieve pending validators from database")
 	errDBPutPendingValidators = errors.New("couldn't put pending validators in database")
-	errDBAccount              = errors.New("couldn't retrieve account from database")
-	errDBPutAccount           = errors.New("couldn't put account in database")
+	errDBUTXO                 = errors.New("couldn't retrieve UTXO from database")
+	errDBPutUTXO              = errors.New("couldn't put UTXO in database")
 	errDBChains               = errors.New("couldn't retrieve chain list from database")
 	errDBPutChains            = errors.New("couldn't put chain list in database")
 	errDBPutBlock             = errors.New("couldn't put block in database")
 	errRegisteringType        = errors.New("error registering type with database")
 	errMissingBlock           = errors.New("missing block")
+	errNoUptimes              = errors.New("this node doesn't track uptimes")
 )
 
 // Codec does serialization and deserialization
@@ -126,6 +138,15 @@ func init() {
 
 		Codec.RegisterType(&advanceTimeTx{}),
 		Codec.RegisterType(&rewardValidatorTx{}),
+
+		Codec.RegisterType(&UnsignedImportTx{}),
+		Codec.RegisterType(&ImportTx{}),
+
+		Codec.RegisterType(&UnsignedExportTx{}),
+		Codec.RegisterType(&ExportTx{}),
+
+		Codec.RegisterType(&secp256k1fx.TransferInput{}),
+		Codec.RegisterType(&secp256k1fx.TransferOutput{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -144,9 +165,28 @@ type VM struct {
 	// Used to create and use keys.
 	factory crypto.FactorySECP256K1R
 
+	// The ID of the $AVA asset on the X-Chain
+	ava ids.ID
+
+	// The ID of the X-Chain, which $AVA can be moved to and from
+	avm ids.ID
+
 	// Used to get time. Useful for faking time during tests.
 	clock timer.Clock
 
+	// How far ahead of [clock] a proposed chain timestamp may be. If zero,
+	// Delta is used.
+	maxClockDrift time.Duration
+
+	// $AVA burned by each transaction, in nAVA. Transactions that create a
+	// subnet or a blockchain burn [creationTxFee], others burn [txFee].
+	txFee         uint64
+	creationTxFee uint64
+
+	// Bounds of the stakes on the default subnet. If zero,
+	// DefaultStakingParameters are used.
+	staking StakingParameters
+
 	// Key: block ID
 	// Value: the block
 	currentBlocks map[[32]byte]Block
@@ -155,6 +195,11 @@ type VM struct {
 	unissuedEvents      *EventHeap
 	unissuedDecisionTxs []DecisionTx
 
+	// IDs of the txs this node took from [unissuedEvents] and proposed in
+	// blocks it built that haven't been decided yet. If such a block is
+	// rejected, its tx is proposed again.
+	proposedTxs ids.Set
+
 	// This timer goes off when it is time for the next validator to add/leave the validator set
 	// When it goes off resetTimer() is called, triggering creation of a new block
 	timer *timer.Timer
@@ -166,6 +211,7 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	genesisBytes []byte,
+	configBytes []byte,
 	msgs chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
@@ -174,6 +220,7 @@ func (vm *VM) Initialize(
 	if len(fxs) != 0 {
 		return errUnsupportedFXs
 	}
+	vm.factory.Cache.Size = recoverCacheSize
 
 	// Initialize the inner VM, which has a lot of boiler-plate logic
 	vm.SnowmanVM = &core.SnowmanVM{}
@@ -195,10 +242,16 @@ func (vm *VM) Initialize(
 			return err
 		}
 
-		// Persist accounts that exist at genesis
-		for _, account := range genesis.Accounts {
-			if err := vm.putAccount(vm.DB, account); err != nil {
-				return errDBPutAccount
+		// Migrate the accounts that exist at genesis into the UTXO set. Each
+		// account's balance becomes a UTXO that only its address can spend.
+		genesisID := ids.NewID(hashing.ComputeHash256Array(genesisBytes))
+		for i, account := range genesis.Accounts {
+			if account.Balance == 0 {
+				continue
+			}
+			utxo := vm.newUTXO(genesisID, uint32(i), account.Balance, account.Address)
+			if err := vm.putUTXO(vm.DB, utxo); err != nil {
+				return err
 			}
 		}
 
@@ -269,6 +322,23 @@ func (vm *VM) Initialize(
 		ctx.Log.Error("failed to initialize the current validator set: %s", err)
 		return err
 	}
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		return err
+	}
+	for _, subnet := range subnets {
+		if !vm.validates(subnet.ID) {
+			continue
+		}
+		if err := vm.updateValidators(subnet.ID); err != nil {
+			ctx.Log.Error("failed to initialize the current validator set of subnet %s: %s", subnet.ID, err)
+			return err
+		}
+	}
+	// Subnets whitelisted while the node runs are tracked from then on
+	if vm.ChainManager != nil {
+		vm.ChainManager.AddSubnetTracker(vm)
+	}
 
 	// Create all of the chains that the database says exist
 	if err := vm.initBlockchains(); err != nil {
@@ -290,22 +360,52 @@ func (vm *VM) initBlockchains() error {
 		return err
 	}
 	for _, chain := range existingChains { // Create each blockchain
-		chainParams := chains.ChainParameters{
-			ID:          chain.ID(),
-			GenesisData: chain.GenesisData,
-			VMAlias:     chain.VMID.String(),
-		}
-		for _, fxID := range chain.FxIDs {
-			chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
-		}
-		vm.ChainManager.CreateChain(chainParams)
+		vm.createChain(chain)
 	}
 	return nil
 }
 
+// validates returns true if this node validates the subnet [subnetID]. Every
+// node validates the default subnet. Other subnets are only validated if they
+// have been whitelisted.
+func (vm *VM) validates(subnetID ids.ID) bool {
+	return subnetID.Equals(DefaultSubnetID) || (vm.ChainManager != nil && vm.ChainManager.Validates(subnetID))
+}
+
+// TrackSubnet implements the chains.SubnetTracker interface
+func (vm *VM) TrackSubnet(subnetID ids.ID) error {
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	if _, err := vm.getSubnet(vm.DB, subnetID); err != nil {
+		return fmt.Errorf("subnet %s doesn't exist yet", subnetID)
+	}
+	return vm.updateValidators(subnetID)
+}
+
+// createChain asks the chain manager to create the chain [tx] describes. The
+// chain manager only creates it if this node validates the chain's subnet.
+func (vm *VM) createChain(tx *CreateChainTx) {
+	chainParams := chains.ChainParameters{
+		ID:          tx.ID(),
+		SubnetID:    tx.SubnetID,
+		GenesisData: tx.GenesisData,
+		ConfigData:  tx.ConfigData,
+		VMAlias:     tx.VMID.String(),
+	}
+	for _, fxID := range tx.FxIDs {
+		chainParams.FxAliases = append(chainParams.FxAliases, fxID.String())
+	}
+	// TODO: Not sure how else to make this not nil pointer error during tests
+	if vm.ChainManager != nil {
+		vm.ChainManager.CreateChain(chainParams)
+	}
+}
+
 // Shutdown this blockchain
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	vm.Pruner.Stop()
 	if err := vm.DB.Close(); err != nil {
 		vm.Ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -425,6 +525,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 			if err := vm.State.PutBlock(vm.DB, blk); err != nil {
 				return nil, err
 			}
+			vm.proposedTxs.Add(tx.ID())
 			return blk, vm.DB.Commit()
 		}
 		vm.Ctx.Log.Debug("dropping tx to add validator because start time too late")
@@ -671,10 +772,55 @@ func (vm *VM) getValidators(validatorEvents *EventHeap) []validators.Validator {
 	return vdrList
 }
 
+// Snapshot returns the validators of [subnetID] at [t]. Stakers are removed
+// from state once they finish staking, so [t] must not be before the current
+// chain time. The context lock must be held when this is called.
+//
+// Snapshot implements the validators.Snapshotter interface.
+func (vm *VM) Snapshot(subnetID ids.ID, t time.Time) (validators.Set, error) {
+	currentTime, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		return nil, err
+	}
+	if t.Before(currentTime) {
+		return nil, fmt.Errorf("can't snapshot validators at %s as it's before the chain time %s", t, currentTime)
+	}
+
+	current, err := vm.getCurrentValidators(vm.DB, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := vm.getPendingValidators(vm.DB, subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A staker validates from its start time until, but not including, its
+	// end time
+	stakers := &EventHeap{}
+	for _, events := range []*EventHeap{current, pending} {
+		for _, tx := range events.Txs {
+			if !tx.StartTime().After(t) && tx.EndTime().After(t) {
+				stakers.Txs = append(stakers.Txs, tx)
+			}
+		}
+	}
+
+	snapshot := validators.NewSet()
+	snapshot.Set(vm.getValidators(stakers))
+	return snapshot, nil
+}
+
 func (vm *VM) updateValidators(subnetID ids.ID) error {
 	validatorSet, ok := vm.Validators.GetValidatorSet(subnetID)
 	if !ok {
-		return fmt.Errorf("couldn't get the validator sampler of the %s subnet", subnetID)
+		if !vm.validates(subnetID) {
+			return fmt.Errorf("couldn't get the validator sampler of the %s subnet", subnetID)
+		}
+		// This is the first time the validators of a whitelisted subnet are
+		// tracked
+		validatorSet = validators.NewSet()
+		vm.Validators.PutValidatorSet(subnetID, validatorSet)
 	}
 
 	currentValidators, err := vm.getCurrentValidators(vm.DB, subnetID)
@@ -686,3 +832,42 @@ func (vm *VM) updateValidators(subnetID ids.ID) error {
 	validatorSet.Set(validators)
 	return nil
 }
+
+// Clock returns a reference to the internal clock of this VM
+func (vm *VM) Clock() *timer.Clock { return &vm.clock }
+
+// clockDrift returns how far ahead of this node's clock a proposed chain
+// timestamp may be
+func (vm *VM) clockDrift() time.Duration {
+	if vm.maxClockDrift == 0 {
+		return Delta
+	}
+	return vm.maxClockDrift
+}
+
+// stakingParameters returns the bounds of the stakes on the default subnet
+func (vm *VM) stakingParameters() StakingParameters {
+	if vm.staking == (StakingParameters{}) {
+		return DefaultStakingParameters
+	}
+	return vm.staking
+}
+
+// uptime returns the uptime, as observed by this node, of the default subnet
+// validator [nodeID] since it started validating, and when it started
+// validating. Rewarding a validator may depend on its uptime.
+func (vm *VM) uptime(db database.Database, nodeID ids.ShortID) (float64, time.Time, error) {
+	if vm.Ctx.Uptimes == nil {
+		return 0, time.Time{}, errNoUptimes
+	}
+	validators, err := vm.getCurrentValidators(db, DefaultSubnetID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	validator, err := validators.getDefaultSubnetStaker(nodeID)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	uptime, err := vm.Ctx.Uptimes.Uptime(nodeID, validator.StartTime())
+	return uptime, validator.StartTime(), err
+}
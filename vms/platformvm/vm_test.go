@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -18,6 +20,7 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/vms/components/core"
 	"github.com/ava-labs/gecko/vms/timestampvm"
 )
@@ -41,6 +44,10 @@ var (
 	// balance of accounts that exist at genesis
 	defaultBalance = 100 * MinimumStakeAmount
 
+	// $AVA burned by transactions on defaultVM
+	defaultTxFee         = 100 * units.NanoAva
+	defaultCreationTxFee = 1000 * units.NanoAva
+
 	// At genesis this account has AVA and is validating the default subnet
 	defaultKey *crypto.PrivateKeySECP256K1R
 
@@ -80,7 +87,7 @@ func init() {
 		keys = append(keys, pk.(*crypto.PrivateKeySECP256K1R))
 	}
 
-	defaultStakeAmount = defaultBalance - txFee
+	defaultStakeAmount = defaultBalance - defaultTxFee
 
 	defaultKey = keys[0]
 
@@ -94,7 +101,10 @@ func defaultContext() *snow.Context {
 	return ctx
 }
 
-func defaultVM() *VM {
+func defaultVM() *VM { return defaultVMWithDB(memdb.New()) }
+
+// defaultVMWithDB is the same as defaultVM but stores the VM's state in [db]
+func defaultVMWithDB(db database.Database) *VM {
 	genesisAccounts := GenesisAccounts()
 	genesisValidators := GenesisCurrentValidators()
 	genesisChains := make([]*CreateChainTx, 0)
@@ -112,7 +122,10 @@ func defaultVM() *VM {
 	}
 
 	vm := &VM{
-		SnowmanVM: &core.SnowmanVM{},
+		SnowmanVM:     &core.SnowmanVM{},
+		ava:           testAVAAssetID,
+		txFee:         defaultTxFee,
+		creationTxFee: defaultCreationTxFee,
 	}
 
 	defaultSubnet := validators.NewSet()
@@ -120,17 +133,15 @@ func defaultVM() *VM {
 	vm.Validators.PutValidatorSet(DefaultSubnetID, defaultSubnet)
 
 	vm.clock.Set(defaultGenesisTime)
-	db := memdb.New()
 	msgChan := make(chan common.Message, 1)
 	ctx := defaultContext()
-	if err := vm.Initialize(ctx, db, genesisBytes, msgChan, nil); err != nil {
+	if err := vm.Initialize(ctx, db, genesisBytes, nil, msgChan, nil); err != nil {
 		panic(err)
 	}
 
 	// Create 1 non-default subnet and store it in testSubnet1
 	tx, err := vm.newCreateSubnetTx(
 		testNetworkID,
-		0,
 		[]ids.ShortID{keys[0].PublicKey().Address(), keys[1].PublicKey().Address(), keys[2].PublicKey().Address()}, // control keys are keys[0], keys[1], keys[2]
 		2, // 2 sigs from keys[0], keys[1], keys[2] needed to add validator to this subnet
 		keys[0],
@@ -190,22 +201,30 @@ func GenesisAccounts() []Account {
 	return accounts
 }
 
-// Returns the validators validating at genesis in tests
+// Returns the validators validating at genesis in tests. Genesis validators
+// don't spend any UTXOs, as their stake is created at genesis.
 func GenesisCurrentValidators() *EventHeap {
 	vm := &VM{}
 	validators := &EventHeap{SortByStartTime: false}
 	for _, key := range keys {
-		validator, _ := vm.newAddDefaultSubnetValidatorTx(
-			defaultNonce,                            // nonce
-			defaultStakeAmount,                      // weight
-			uint64(defaultValidateStartTime.Unix()), // start time
-			uint64(defaultValidateEndTime.Unix()),   // end time
-			key.PublicKey().Address(),               // nodeID
-			key.PublicKey().Address(),               // destination
-			NumberOfShares,                          // shares
-			testNetworkID,                           // network ID
-			key,                                     // key paying tx fee and stake
-		)
+		validator := &addDefaultSubnetValidatorTx{
+			UnsignedAddDefaultSubnetValidatorTx: UnsignedAddDefaultSubnetValidatorTx{
+				NetworkID: testNetworkID,
+				DurationValidator: DurationValidator{
+					Validator: Validator{
+						NodeID: key.PublicKey().Address(),
+						Wght:   defaultStakeAmount,
+					},
+					Start: uint64(defaultValidateStartTime.Unix()),
+					End:   uint64(defaultValidateEndTime.Unix()),
+				},
+				Destination: key.PublicKey().Address(),
+				Shares:      NumberOfShares,
+			},
+		}
+		if err := validator.initialize(vm); err != nil {
+			panic(err)
+		}
 		heap.Push(validators, validator)
 	}
 	return validators
@@ -225,20 +244,21 @@ func TestGenesis(t *testing.T) {
 		t.Fatal("genesis block should be accepted")
 	}
 
-	// Ensure all the genesis accounts are stored
+	// Ensure the $AVA held by the genesis accounts is spendable
 	for _, account := range GenesisAccounts() {
-		vmAccount, err := vm.getAccount(vm.DB, account.Address)
+		balance, err := vm.getBalance(vm.DB, account.Address)
 		if err != nil {
-			t.Fatal("couldn't find account in vm's db")
+			t.Fatal(err)
 		}
-		if !vmAccount.Address.Equals(account.Address) {
-			t.Fatal("account IDs should match")
+		if balance != account.Balance {
+			t.Fatalf("balance should be %d but is %d", account.Balance, balance)
 		}
-		if vmAccount.Balance != account.Balance {
-			t.Fatal("balances should match")
+		utxoIDs, err := vm.getUTXOIDs(vm.DB, account.Address)
+		if err != nil {
+			t.Fatal(err)
 		}
-		if vmAccount.Nonce != account.Nonce {
-			t.Fatal("nonces should match")
+		if len(utxoIDs) != 1 {
+			t.Fatalf("genesis account should hold 1 UTXO but holds %d", len(utxoIDs))
 		}
 	}
 
@@ -290,7 +310,6 @@ func TestAddDefaultSubnetValidatorCommit(t *testing.T) {
 
 	// create valid tx
 	tx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
@@ -357,7 +376,6 @@ func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 
 	// create valid tx
 	tx, err := vm.newAddDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
@@ -418,6 +436,164 @@ func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 	}
 }
 
+// Test that the options of a proposal block are only built once, and that a
+// rejected proposal to add a validator is re-issued
+func TestProposalBlockReject(t *testing.T) {
+	vm := defaultVM()
+	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+	endTime := startTime.Add(MinimumStakingDuration)
+	key, _ := vm.factory.NewPrivateKey()
+	ID := key.PublicKey().Address()
+
+	// create valid tx
+	tx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		ID,
+		ID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// trigger block creation
+	vm.unissuedEvents.Add(tx)
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := blk.(*ProposalBlock)
+
+	options := block.Options()
+	if again := block.Options(); options[0] != again[0] || options[1] != again[1] {
+		t.Fatalf("Options should only have been built once")
+	}
+
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if parent := block.parentBlock().(*Commit); len(parent.children) != 1 {
+		t.Fatalf("Block should have been added to its parent once but was added %d times", len(parent.children))
+	}
+
+	if vm.unissuedEvents.Len() != 0 {
+		t.Fatalf("The tx should have been issued")
+	}
+	block.Reject()
+	if status := block.Status(); status != choices.Rejected {
+		t.Fatalf("Block should have been rejected but is %s", status)
+	}
+	if vm.unissuedEvents.Len() != 1 || !vm.unissuedEvents.Peek().ID().Equals(tx.ID()) {
+		t.Fatalf("The tx of the rejected block should have been re-issued")
+	}
+}
+
+// Test that a rejected proposal to add a validator isn't re-issued when another
+// block, on a sibling branch, holds the same tx and is accepted
+func TestProposalBlockRejectAcceptedElsewhere(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer vm.Ctx.Lock.Unlock()
+
+	// Each tx is paid by a different key, so they don't spend the same UTXOs
+	newTx := func(payer *crypto.PrivateKeySECP256K1R) *addDefaultSubnetValidatorTx {
+		startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
+		endTime := startTime.Add(MinimumStakingDuration)
+		key, _ := vm.factory.NewPrivateKey()
+		ID := key.PublicKey().Address()
+		tx, err := vm.newAddDefaultSubnetValidatorTx(
+			defaultStakeAmount,
+			uint64(startTime.Unix()),
+			uint64(endTime.Unix()),
+			ID,
+			ID,
+			NumberOfShares,
+			testNetworkID,
+			payer,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// The first proposal's options are the parents of the two branches
+	vm.unissuedEvents.Add(newTx(keys[0]))
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := blk.(*ProposalBlock)
+	if err := first.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	options := first.Options()
+	commit := options[0].(*Commit)
+	abort := options[1].(*Abort)
+	first.Accept()
+	if err := commit.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := abort.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// This node proposes the tx after the commit, and another node proposes
+	// it after the abort
+	tx := newTx(keys[1])
+	vm.unissuedEvents.Add(tx)
+	vm.SetPreference(commit.ID())
+	blk, err = vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proposed := blk.(*ProposalBlock)
+	if !proposed.ParentID().Equals(commit.ID()) {
+		t.Fatalf("The proposal should have been built on the commit")
+	}
+	if err := proposed.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	sibling, err := vm.newProposalBlock(abort.ID(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sibling.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The abort branch is accepted, so this node's proposal is rejected while
+	// the other one holds the tx
+	abort.Accept()
+	commit.Reject()
+	proposed.Reject()
+	if vm.unissuedEvents.Len() != 0 {
+		t.Fatalf("The tx shouldn't have been re-issued while another block holds it")
+	}
+
+	sibling.Accept()
+	siblingCommit := sibling.Options()[0].(*Commit)
+	if err := siblingCommit.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	siblingCommit.Accept()
+	if isStaker, err := vm.isStaker(vm.DB, tx); err != nil {
+		t.Fatal(err)
+	} else if !isStaker {
+		t.Fatalf("The tx should have been added to the pending validators")
+	}
+}
+
 // Accept proposal to add validator to non-default subnet
 func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 	vm := defaultVM()
@@ -428,7 +604,6 @@ func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 	// note that [startTime, endTime] is a subset of time that keys[0]
 	// validates default subnet ([defaultValidateStartTime, defaultValidateEndTime])
 	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
@@ -501,7 +676,6 @@ func TestAddNonDefaultSubnetValidatorReject(t *testing.T) {
 	// note that [startTime, endTime] is a subset of time that keys[0]
 	// validates default subnet ([defaultValidateStartTime, defaultValidateEndTime])
 	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+1,
 		defaultWeight,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
@@ -746,6 +920,64 @@ func TestRewardValidatorReject(t *testing.T) {
 	}
 }
 
+// Ensure snapshots include exactly the stakers validating at the given time
+func TestSnapshot(t *testing.T) {
+	vm := defaultVM()
+
+	key, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID := key.PublicKey().Address()
+	startTime := defaultGenesisTime.Add(time.Minute)
+	endTime := startTime.Add(MinimumStakingDuration)
+
+	pendingTx, err := vm.newAddDefaultSubnetValidatorTx(
+		defaultStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(endTime.Unix()),
+		nodeID,
+		nodeID,
+		NumberOfShares,
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.putPendingValidators(vm.DB, &EventHeap{SortByStartTime: true, Txs: []TimedTx{pendingTx}}, DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := vm.Snapshot(DefaultSubnetID, defaultValidateStartTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Len() != len(keys) || snapshot.Contains(nodeID) {
+		t.Fatalf("Snapshot should only contain the genesis validators")
+	}
+
+	snapshot, err = vm.Snapshot(DefaultSubnetID, startTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Len() != len(keys)+1 || !snapshot.Contains(nodeID) {
+		t.Fatalf("Snapshot should contain the pending validator once it starts validating")
+	}
+
+	snapshot, err = vm.Snapshot(DefaultSubnetID, endTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Contains(nodeID) {
+		t.Fatalf("Snapshot shouldn't contain the validator once it stops validating")
+	}
+
+	if _, err := vm.Snapshot(DefaultSubnetID, defaultGenesisTime.Add(-time.Second)); err == nil {
+		t.Fatalf("Should have failed to snapshot a time before the chain time")
+	}
+}
+
 // Ensure BuildBlock errors when there is no block to build
 func TestUnneededBuildBlock(t *testing.T) {
 	vm := defaultVM()
@@ -755,13 +987,28 @@ func TestUnneededBuildBlock(t *testing.T) {
 	}
 }
 
+// chainManager records the chains it is asked to create
+type chainManager struct {
+	chains.Manager
+
+	created []chains.ChainParameters
+}
+
+func (cm *chainManager) CreateChain(chain chains.ChainParameters) {
+	cm.created = append(cm.created, chain)
+}
+
 // test acceptance of proposal to create a new chain
 func TestCreateChain(t *testing.T) {
 	vm := defaultVM()
+	cm := &chainManager{}
+	vm.ChainManager = cm
 
+	configData := []byte{1, 2, 3}
 	tx, err := vm.newCreateChainTx(
-		defaultNonce+1,
+		DefaultSubnetID,
 		nil,
+		configData,
 		timestampvm.ID,
 		nil,
 		"name ",
@@ -801,13 +1048,21 @@ func TestCreateChain(t *testing.T) {
 		t.Fatal("should've created new chain but didn't")
 	}
 
+	// Verify the chain's VM is initialized with its configuration
+	if len(cm.created) != 1 {
+		t.Fatalf("should have asked the chain manager to create %d chain(s) but asked for %d", 1, len(cm.created))
+	}
+	if created := cm.created[0]; !created.ID.Equals(tx.ID()) || !bytes.Equal(created.ConfigData, configData) {
+		t.Fatal("should have created the chain with its configuration")
+	}
+
 	// Verify tx fee was deducted
-	account, err := vm.getAccount(vm.DB, tx.Key().Address())
+	balance, err := vm.getBalance(vm.DB, tx.Key().Address())
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance != defaultBalance-txFee {
-		t.Fatal("should have deducted txFee from balance")
+	if balance != defaultBalance-vm.creationTxFee {
+		t.Fatal("should have deducted the creation tx fee from balance")
 	}
 }
 
@@ -821,7 +1076,6 @@ func TestCreateSubnet(t *testing.T) {
 
 	createSubnetTx, err := vm.newCreateSubnetTx(
 		testNetworkID,
-		defaultNonce+1,
 		[]ids.ShortID{
 			keys[0].PublicKey().Address(),
 			keys[1].PublicKey().Address(),
@@ -863,12 +1117,12 @@ func TestCreateSubnet(t *testing.T) {
 	}
 
 	// Verify tx fee was deducted
-	account, err := vm.getAccount(vm.DB, createSubnetTx.key.Address())
+	balance, err := vm.getBalance(vm.DB, createSubnetTx.key.Address())
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance != defaultBalance-txFee {
-		t.Fatal("should have deducted txFee from balance")
+	if balance != defaultBalance-vm.creationTxFee {
+		t.Fatal("should have deducted the creation tx fee from balance")
 	}
 
 	// Now that we've created a new subnet, add a validator to that subnet
@@ -876,7 +1130,6 @@ func TestCreateSubnet(t *testing.T) {
 	endTime := startTime.Add(MinimumStakingDuration)
 	// [startTime, endTime] is subset of time keys[0] validates default subent so tx is valid
 	addValidatorTx, err := vm.newAddNonDefaultSubnetValidatorTx(
-		defaultNonce+2,
 		defaultWeight,
 		uint64(startTime.Unix()),
 		uint64(endTime.Unix()),
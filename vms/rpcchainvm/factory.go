@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"github.com/ava-labs/gecko/vms"
+)
+
+// Factory ...
+type Factory struct {
+	// Path of the plugin's executable
+	Path string
+
+	// Directory the plugin writes its logs to, in a subdirectory named by the
+	// chain's ID. If empty, the plugin uses the default log directory.
+	LogDirectory string
+}
+
+// New returns a VM that runs in a new instance of the plugin
+// The plugin process is started when the VM is initialized
+func (f *Factory) New() interface{} {
+	return &VMClient{
+		path:         f.Path,
+		logDirectory: f.LogDirectory,
+	}
+}
+
+// Versions implements the vms.VersionedFactory interface. The plugin is
+// started to read the versions from its handshake.
+func (f *Factory) Versions() (vms.Versions, error) {
+	hs, err := probe(f.Path)
+	if err != nil {
+		return vms.Versions{}, err
+	}
+	return vms.Versions{
+		VM:        hs.vmVersion,
+		Interface: hs.protocolVersion,
+	}, nil
+}
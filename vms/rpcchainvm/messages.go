@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+// Empty is the argument and reply of calls that have none
+type Empty struct{}
+
+// InitializeArgs are the arguments of VM.Initialize
+type InitializeArgs struct {
+	NetworkID    uint32
+	ChainID      []byte
+	NodeID       []byte
+	GenesisBytes []byte
+	ConfigBytes  []byte
+
+	// Directory the plugin writes its logs to, in a subdirectory named by the
+	// chain's ID
+	LogDirectory string
+}
+
+// BlockArgs are the arguments of calls that take a block ID
+type BlockArgs struct{ ID []byte }
+
+// ParseBlockArgs are the arguments of VM.ParseBlock
+type ParseBlockArgs struct{ Bytes []byte }
+
+// BlockReply describes a block returned by the VM
+type BlockReply struct {
+	ID       []byte
+	ParentID []byte
+	Status   uint32
+	Bytes    []byte
+}
+
+// StatusReply is the reply of VM.BlockStatus
+type StatusReply struct{ Status uint32 }
+
+// IDReply is the reply of VM.LastAccepted
+type IDReply struct{ ID []byte }
+
+// Handler describes an HTTP handler of the VM
+type Handler struct {
+	Extension   string
+	LockOptions common.LockOption
+}
+
+// CreateHandlersReply is the reply of VM.CreateHandlers
+type CreateHandlersReply struct{ Handlers []Handler }
+
+// HTTPRequest is an HTTP request to one of the VM's handlers
+type HTTPRequest struct {
+	Extension  string
+	Method     string
+	URL        string
+	Host       string
+	RemoteAddr string
+	Header     http.Header
+	Body       []byte
+}
+
+// HTTPResponse is the response of one of the VM's handlers to an HTTPRequest
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// NotifyArgs are the arguments of Messenger.Notify
+type NotifyArgs struct{ Message common.Message }
@@ -0,0 +1,299 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/vms"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+// A plugin is started by the node as a child process. It listens for
+// connections on a loopback address and writes the handshake line
+//
+//     GECKO_PLUGIN|[protocol version]|[address]|[VM version]
+//
+// to stdout. Plugins built before the VM version was added leave it out. The node then makes two connections to that address and writes
+// a single byte to each, saying what the connection is for:
+//   * On the VM connection the node calls the plugin's VM, which is
+//     registered as "VM".
+//   * On the broker connection the plugin calls the node, which registers
+//     the chain's database as "Database" and a "Messenger" that forwards
+//     messages to the consensus engine.
+// When its connections are closed, the plugin exits. Anything else the plugin
+// writes to stdout or stderr is logged by the node.
+
+const (
+	handshakePrefix = "GECKO_PLUGIN"
+
+	// Version of the protocol between the node and plugins, which is the
+	// interface between the node and VMs
+	protocolVersion = vms.InterfaceVersion
+
+	// Time the plugin has to write the handshake after it's started
+	handshakeTimeout = 10 * time.Second
+
+	roleVM     byte = 0
+	roleBroker byte = 1
+)
+
+var (
+	errHandshakeTimeout = errors.New("plugin didn't complete the handshake in time")
+	errPluginExited     = errors.New("plugin exited before completing the handshake")
+	errBadRole          = errors.New("unexpected connection to the plugin")
+)
+
+// Versioned is implemented by VMs that report their version to the node
+type Versioned interface {
+	Version() (string, error)
+}
+
+// handshake is what a plugin writes once it's listening for the node
+type handshake struct {
+	protocolVersion uint32
+	addr            string
+	vmVersion       string
+}
+
+// parseHandshake parses the handshake [line] written by a plugin
+func parseHandshake(line string) (handshake, error) {
+	fields := strings.SplitN(line, "|", 4)
+	if len(fields) < 3 || fields[0] != handshakePrefix {
+		return handshake{}, fmt.Errorf("plugin wrote a malformed handshake: %s", line)
+	}
+	version, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return handshake{}, fmt.Errorf("plugin wrote a malformed handshake: %s", line)
+	}
+	hs := handshake{
+		protocolVersion: uint32(version),
+		addr:            fields[2],
+	}
+	if len(fields) == 4 {
+		hs.vmVersion = fields[3]
+	}
+	return hs, nil
+}
+
+// Serve [vm] to the node that started this process
+// This should be called by the main function of the plugin. It returns once
+// the node closes its connections.
+func Serve(vm smeng.ChainVM) error {
+	vmVersion := ""
+	if versioned, ok := vm.(Versioned); ok {
+		version, err := versioned.Version()
+		if err != nil {
+			return err
+		}
+		// The version is the last field of the handshake, so it can't span
+		// lines
+		vmVersion = strings.Replace(version, "\n", " ", -1)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s|%d|%s|%s\n", handshakePrefix, protocolVersion, listener.Addr(), vmVersion)
+
+	// Only the node may connect
+	conns := [2]net.Conn{}
+	for i := 0; i < len(conns); i++ {
+		conn, err := listener.Accept()
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		role := []byte{0}
+		if _, err := io.ReadFull(conn, role); err != nil {
+			listener.Close()
+			return err
+		}
+		if int(role[0]) >= len(conns) || conns[role[0]] != nil {
+			listener.Close()
+			return errBadRole
+		}
+		conns[role[0]] = conn
+	}
+	if err := listener.Close(); err != nil {
+		return err
+	}
+
+	return serve(vm, conns[roleVM], conns[roleBroker])
+}
+
+// serve [vm] on [vmConn], making calls to the node on [brokerConn]
+func serve(vm smeng.ChainVM, vmConn, brokerConn net.Conn) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName("VM", NewServer(vm, rpc.NewClient(brokerConn))); err != nil {
+		return err
+	}
+	server.ServeConn(vmConn)
+	return nil
+}
+
+// start the plugin process and return the address it's listening on
+func (vm *VMClient) start() (string, error) {
+	handshakes := make(chan string, 1)
+	vm.exited = make(chan struct{})
+	vm.cmd = exec.Command(vm.path)
+	vm.cmd.Stdout = &lineWriter{
+		log: func(line string) {
+			if strings.HasPrefix(line, handshakePrefix+"|") {
+				select {
+				case handshakes <- line:
+				default:
+				}
+				return
+			}
+			vm.ctx.Log.Info("plugin %s: %s", vm.path, line)
+		},
+	}
+	vm.cmd.Stderr = &lineWriter{
+		log: func(line string) { vm.ctx.Log.Warn("plugin %s: %s", vm.path, line) },
+	}
+	if err := vm.cmd.Start(); err != nil {
+		return "", err
+	}
+	go func() {
+		err := vm.cmd.Wait()
+		close(vm.exited)
+
+		vm.lock.Lock()
+		defer vm.lock.Unlock()
+
+		// A crashed plugin only halts its own chain
+		if !vm.shuttingDown {
+			vm.ctx.Log.Error("plugin %s exited unexpectedly: %v", vm.path, err)
+		}
+	}()
+
+	select {
+	case line := <-handshakes:
+		hs, err := parseHandshake(line)
+		if err != nil {
+			vm.kill()
+			return "", err
+		}
+		if hs.protocolVersion != protocolVersion {
+			vm.kill()
+			return "", fmt.Errorf("plugin speaks protocol version %d but the node speaks %d", hs.protocolVersion, protocolVersion)
+		}
+		return hs.addr, nil
+	case <-vm.exited:
+		return "", errPluginExited
+	case <-time.After(handshakeTimeout):
+		vm.kill()
+		return "", errHandshakeTimeout
+	}
+}
+
+// probe starts the plugin at [path] to read its handshake, then kills it
+func probe(path string) (handshake, error) {
+	handshakes := make(chan string, 1)
+	cmd := exec.Command(path)
+	cmd.Stdout = &lineWriter{
+		log: func(line string) {
+			if strings.HasPrefix(line, handshakePrefix+"|") {
+				select {
+				case handshakes <- line:
+				default:
+				}
+			}
+		},
+	}
+	if err := cmd.Start(); err != nil {
+		return handshake{}, err
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	defer func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	}()
+
+	select {
+	case line := <-handshakes:
+		return parseHandshake(line)
+	case <-exited:
+		return handshake{}, errPluginExited
+	case <-time.After(handshakeTimeout):
+		return handshake{}, errHandshakeTimeout
+	}
+}
+
+// kill the plugin process
+func (vm *VMClient) kill() {
+	vm.lock.Lock()
+	vm.shuttingDown = true
+	vm.lock.Unlock()
+
+	if err := vm.cmd.Process.Kill(); err != nil {
+		vm.ctx.Log.Debug("killing plugin %s failed with %s", vm.path, err)
+	}
+}
+
+// dial the plugin listening on [addr]
+func dial(addr string) (net.Conn, net.Conn, error) {
+	vmConn, err := dialRole(addr, roleVM)
+	if err != nil {
+		return nil, nil, err
+	}
+	brokerConn, err := dialRole(addr, roleBroker)
+	if err != nil {
+		vmConn.Close()
+		return nil, nil, err
+	}
+	return vmConn, brokerConn, nil
+}
+
+func dialRole(addr string, role byte) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, handshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write([]byte{role}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// lineWriter calls [log] with each line written to it
+type lineWriter struct {
+	log func(string)
+
+	lock sync.Mutex
+	buf  []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.log(string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+}
@@ -0,0 +1,335 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/rpc"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/rpcdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/vms/components/missing"
+)
+
+const (
+	// Time the plugin has to exit after it was shut down before it's killed
+	shutdownTimeout = 5 * time.Second
+)
+
+var (
+	errUnsupportedFxs = errors.New("plugin VMs don't support feature extensions")
+)
+
+// VMClient is a ChainVM that runs in a plugin process and is called over
+// net/rpc
+type VMClient struct {
+	// Path of the plugin's executable
+	path string
+	// Directory the plugin writes its logs to
+	logDirectory string
+
+	ctx    *snow.Context
+	client *rpc.Client
+	conns  []net.Conn
+
+	cmd *exec.Cmd
+	// Closed when the plugin process exits
+	exited chan struct{}
+
+	lock         sync.Mutex
+	shuttingDown bool
+}
+
+// Initialize implements the ChainVM interface
+func (vm *VMClient) Initialize(
+	ctx *snow.Context,
+	db database.Database,
+	genesisBytes []byte,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) error {
+	if len(fxs) != 0 {
+		return errUnsupportedFxs
+	}
+	vm.ctx = ctx
+
+	addr, err := vm.start()
+	if err != nil {
+		return err
+	}
+	vmConn, brokerConn, err := dial(addr)
+	if err != nil {
+		vm.kill()
+		return err
+	}
+	if err := vm.initialize(vmConn, brokerConn, db, genesisBytes, configBytes, toEngine); err != nil {
+		vmConn.Close()
+		brokerConn.Close()
+		vm.kill()
+		return err
+	}
+	return nil
+}
+
+// initialize the plugin, which is served on [vmConn]. The plugin makes calls
+// to the node over [brokerConn].
+func (vm *VMClient) initialize(
+	vmConn, brokerConn net.Conn,
+	db database.Database,
+	genesisBytes []byte,
+	configBytes []byte,
+	toEngine chan<- common.Message,
+) error {
+	vm.conns = []net.Conn{vmConn, brokerConn}
+	vm.client = rpc.NewClient(vmConn)
+
+	broker := rpc.NewServer()
+	if err := broker.RegisterName("Database", rpcdb.NewServer(db)); err != nil {
+		return err
+	}
+	if err := broker.RegisterName("Messenger", &Messenger{toEngine: toEngine}); err != nil {
+		return err
+	}
+	go broker.ServeConn(brokerConn)
+
+	return vm.client.Call("VM.Initialize", &InitializeArgs{
+		NetworkID:    vm.ctx.NetworkID,
+		ChainID:      vm.ctx.ChainID.Bytes(),
+		NodeID:       vm.ctx.NodeID.Bytes(),
+		GenesisBytes: genesisBytes,
+		ConfigBytes:  configBytes,
+		LogDirectory: vm.logDirectory,
+	}, &Empty{})
+}
+
+// Shutdown implements the ChainVM interface
+func (vm *VMClient) Shutdown() {
+	vm.lock.Lock()
+	vm.shuttingDown = true
+	vm.lock.Unlock()
+
+	if vm.client != nil {
+		if err := vm.client.Call("VM.Shutdown", &Empty{}, &Empty{}); err != nil {
+			vm.ctx.Log.Error("shutting down the plugin VM failed with %s", err)
+		}
+	}
+	for _, conn := range vm.conns {
+		conn.Close()
+	}
+	if vm.cmd == nil {
+		return
+	}
+
+	// Closing its connections makes the plugin exit
+	select {
+	case <-vm.exited:
+	case <-time.After(shutdownTimeout):
+		vm.ctx.Log.Warn("plugin %s didn't exit after being shut down, killing it", vm.path)
+		vm.kill()
+	}
+}
+
+// CreateHandlers implements the ChainVM interface
+func (vm *VMClient) CreateHandlers() map[string]*common.HTTPHandler {
+	reply := CreateHandlersReply{}
+	if err := vm.client.Call("VM.CreateHandlers", &Empty{}, &reply); err != nil {
+		vm.ctx.Log.Error("creating the plugin VM's handlers failed with %s", err)
+		return nil
+	}
+
+	handlers := make(map[string]*common.HTTPHandler, len(reply.Handlers))
+	for _, handler := range reply.Handlers {
+		handlers[handler.Extension] = &common.HTTPHandler{
+			// The plugin locks its own state the way the handler asked for
+			LockOptions: common.NoLock,
+			Handler: &httpHandler{
+				vm:        vm,
+				extension: handler.Extension,
+			},
+		}
+	}
+	return handlers
+}
+
+// BuildBlock implements the ChainVM interface
+func (vm *VMClient) BuildBlock() (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.BuildBlock", &Empty{}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply)
+}
+
+// ParseBlock implements the ChainVM interface
+func (vm *VMClient) ParseBlock(bytes []byte) (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.ParseBlock", &ParseBlockArgs{Bytes: bytes}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply)
+}
+
+// GetBlock implements the ChainVM interface
+func (vm *VMClient) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.GetBlock", &BlockArgs{ID: blkID.Bytes()}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply)
+}
+
+// SetPreference implements the ChainVM interface
+func (vm *VMClient) SetPreference(blkID ids.ID) {
+	if err := vm.client.Call("VM.SetPreference", &BlockArgs{ID: blkID.Bytes()}, &Empty{}); err != nil {
+		vm.ctx.Log.Error("setting the plugin VM's preference failed with %s", err)
+	}
+}
+
+// LastAccepted implements the ChainVM interface
+func (vm *VMClient) LastAccepted() ids.ID {
+	reply := IDReply{}
+	if err := vm.client.Call("VM.LastAccepted", &Empty{}, &reply); err != nil {
+		vm.ctx.Log.Error("getting the plugin VM's last accepted block failed with %s", err)
+		return ids.Empty
+	}
+	blkID, err := ids.ToID(reply.ID)
+	if err != nil {
+		vm.ctx.Log.Error("plugin VM returned an invalid last accepted block: %s", err)
+		return ids.Empty
+	}
+	return blkID
+}
+
+func (vm *VMClient) newBlock(reply *BlockReply) (*blockClient, error) {
+	blkID, err := ids.ToID(reply.ID)
+	if err != nil {
+		return nil, err
+	}
+	parentID, err := ids.ToID(reply.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return &blockClient{
+		vm:       vm,
+		id:       blkID,
+		parentID: parentID,
+		status:   choices.Status(reply.Status),
+		bytes:    reply.Bytes,
+	}, nil
+}
+
+// blockClient is a block of a VMClient
+type blockClient struct {
+	vm *VMClient
+
+	id       ids.ID
+	parentID ids.ID
+	status   choices.Status
+	bytes    []byte
+}
+
+func (b *blockClient) ID() ids.ID { return b.id }
+
+func (b *blockClient) Accept() {
+	if err := b.vm.client.Call("VM.BlockAccept", &BlockArgs{ID: b.id.Bytes()}, &Empty{}); err != nil {
+		b.vm.ctx.Log.Error("accepting block %s failed with %s", b.id, err)
+	}
+	b.status = choices.Accepted
+}
+
+func (b *blockClient) Reject() {
+	if err := b.vm.client.Call("VM.BlockReject", &BlockArgs{ID: b.id.Bytes()}, &Empty{}); err != nil {
+		b.vm.ctx.Log.Error("rejecting block %s failed with %s", b.id, err)
+	}
+	b.status = choices.Rejected
+}
+
+func (b *blockClient) Status() choices.Status {
+	// Once decided, a block's status never changes
+	if b.status.Decided() {
+		return b.status
+	}
+	reply := StatusReply{}
+	if err := b.vm.client.Call("VM.BlockStatus", &BlockArgs{ID: b.id.Bytes()}, &reply); err != nil {
+		b.vm.ctx.Log.Error("getting the status of block %s failed with %s", b.id, err)
+		return b.status
+	}
+	b.status = choices.Status(reply.Status)
+	return b.status
+}
+
+func (b *blockClient) Parent() snowman.Block {
+	if parent, err := b.vm.GetBlock(b.parentID); err == nil {
+		return parent
+	}
+	return &missing.Block{BlkID: b.parentID}
+}
+
+func (b *blockClient) Verify() error {
+	return b.vm.client.Call("VM.BlockVerify", &BlockArgs{ID: b.id.Bytes()}, &Empty{})
+}
+
+func (b *blockClient) Bytes() []byte { return b.bytes }
+
+// httpHandler serves HTTP requests with one of the plugin VM's handlers
+type httpHandler struct {
+	vm        *VMClient
+	extension string
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reply := HTTPResponse{}
+	if err := h.vm.client.Call("VM.HandleHTTP", &HTTPRequest{
+		Extension:  h.extension,
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Host:       r.Host,
+		RemoteAddr: r.RemoteAddr,
+		Header:     r.Header,
+		Body:       body,
+	}, &reply); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for key, values := range reply.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(reply.StatusCode)
+	w.Write(reply.Body)
+}
+
+// Messenger forwards the plugin VM's messages to the engine
+// It runs in the node and is registered under the name "Messenger"
+type Messenger struct{ toEngine chan<- common.Message }
+
+// Notify the engine of [args.Message]
+// If the channel to the engine is full, the message is dropped.
+func (m *Messenger) Notify(args *NotifyArgs, _ *Empty) error {
+	select {
+	case m.toEngine <- args.Message:
+	default:
+	}
+	return nil
+}
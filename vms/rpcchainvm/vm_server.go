@@ -0,0 +1,361 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/rpc"
+	"path/filepath"
+
+	"github.com/ava-labs/gecko/database/rpcdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+var (
+	errUnknownHandler = errors.New("unknown handler")
+)
+
+// VMServer exposes a ChainVM over net/rpc
+// It runs in the plugin and is registered under the name "VM"
+type VMServer struct {
+	vm smeng.ChainVM
+
+	// Makes calls to the node
+	broker *rpc.Client
+
+	ctx      *snow.Context
+	handlers map[string]*common.HTTPHandler
+
+	// Passes the messages the plugin's VM sends to the node
+	notifier *common.Notifier
+
+	// Blocks that have been sent to the node but not decided yet. The VM may
+	// not be able to look up a block it hasn't verified, so they're kept here.
+	blocks map[[32]byte]snowman.Block
+}
+
+// NewServer returns a server that exposes [vm] and makes calls to the node
+// over [broker]
+func NewServer(vm smeng.ChainVM, broker *rpc.Client) *VMServer {
+	return &VMServer{
+		vm:     vm,
+		broker: broker,
+		blocks: make(map[[32]byte]snowman.Block),
+	}
+}
+
+// Initialize implements the ChainVM interface
+func (vm *VMServer) Initialize(args *InitializeArgs, _ *Empty) error {
+	chainID, err := ids.ToID(args.ChainID)
+	if err != nil {
+		return err
+	}
+	nodeID, err := ids.ToShortID(args.NodeID)
+	if err != nil {
+		return err
+	}
+
+	logConfig, err := logging.DefaultConfig()
+	if args.LogDirectory != "" {
+		logConfig.Directory = args.LogDirectory
+	} else if err != nil {
+		return err
+	}
+	// Chains running the same plugin shouldn't write to the same log files
+	logConfig.Directory = filepath.Join(logConfig.Directory, chainID.String())
+	// The node displays what the plugin writes to stdout, so only log to file
+	logConfig.DisableDisplaying = true
+	log, err := logging.New(logConfig)
+	if err != nil {
+		return err
+	}
+
+	decisionDispatcher := &triggers.EventDispatcher{}
+	decisionDispatcher.Initialize(log)
+	consensusDispatcher := &triggers.EventDispatcher{}
+	consensusDispatcher.Initialize(log)
+
+	vm.ctx = &snow.Context{
+		NetworkID:           args.NetworkID,
+		ChainID:             chainID,
+		NodeID:              nodeID,
+		Log:                 log,
+		DecisionDispatcher:  decisionDispatcher,
+		ConsensusDispatcher: consensusDispatcher,
+		BCLookup:            &ids.Aliaser{},
+	}
+
+	// Forward the VM's messages to the node
+	vm.notifier = common.NewNotifier()
+	go func(messages <-chan common.Message) {
+		for msg := range messages {
+			if err := vm.broker.Call("Messenger.Notify", &NotifyArgs{Message: msg}, &Empty{}); err != nil {
+				log.Error("failed to notify the engine of %s due to %s", msg, err)
+			}
+		}
+	}(vm.notifier.Messages())
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if err := vm.vm.Initialize(
+		vm.ctx,
+		rpcdb.NewClient(vm.broker),
+		args.GenesisBytes,
+		args.ConfigBytes,
+		vm.notifier.ToEngine(),
+		nil,
+	); err != nil {
+		vm.notifier.Close()
+		return err
+	}
+	return nil
+}
+
+// Shutdown implements the ChainVM interface
+func (vm *VMServer) Shutdown(_ *Empty, _ *Empty) error {
+	if vm.ctx == nil {
+		return nil
+	}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.vm.Shutdown()
+	vm.notifier.Close()
+	vm.ctx.Log.Stop()
+	return nil
+}
+
+// CreateHandlers implements the ChainVM interface
+func (vm *VMServer) CreateHandlers(_ *Empty, reply *CreateHandlersReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.handlers = vm.vm.CreateHandlers()
+	for extension, handler := range vm.handlers {
+		reply.Handlers = append(reply.Handlers, Handler{
+			Extension:   extension,
+			LockOptions: handler.LockOptions,
+		})
+	}
+	return nil
+}
+
+// HandleHTTP serves an HTTP request with one of the VM's handlers
+func (vm *VMServer) HandleHTTP(args *HTTPRequest, reply *HTTPResponse) error {
+	vm.ctx.Lock.RLock()
+	handler, exists := vm.handlers[args.Extension]
+	vm.ctx.Lock.RUnlock()
+	if !exists {
+		return errUnknownHandler
+	}
+
+	req, err := http.NewRequest(args.Method, args.URL, bytes.NewReader(args.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = args.Header
+	req.Host = args.Host
+	req.RemoteAddr = args.RemoteAddr
+
+	// Lock the same way the node's API server would
+	switch handler.LockOptions {
+	case common.WriteLock:
+		vm.ctx.Lock.Lock()
+		defer vm.ctx.Lock.Unlock()
+	case common.ReadLock:
+		vm.ctx.Lock.RLock()
+		defer vm.ctx.Lock.RUnlock()
+	}
+
+	w := &responseWriter{header: http.Header{}}
+	handler.Handler.ServeHTTP(w, req)
+
+	reply.StatusCode = w.statusCode
+	if reply.StatusCode == 0 {
+		reply.StatusCode = http.StatusOK
+	}
+	reply.Header = w.header
+	reply.Body = w.body.Bytes()
+	return nil
+}
+
+// BuildBlock implements the ChainVM interface
+func (vm *VMServer) BuildBlock(_ *Empty, reply *BlockReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.vm.BuildBlock()
+	if err != nil {
+		return err
+	}
+	vm.reply(blk, reply)
+	return nil
+}
+
+// ParseBlock implements the ChainVM interface
+func (vm *VMServer) ParseBlock(args *ParseBlockArgs, reply *BlockReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.vm.ParseBlock(args.Bytes)
+	if err != nil {
+		return err
+	}
+	vm.reply(blk, reply)
+	return nil
+}
+
+// GetBlock implements the ChainVM interface
+func (vm *VMServer) GetBlock(args *BlockArgs, reply *BlockReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.block(args.ID)
+	if err != nil {
+		return err
+	}
+	vm.reply(blk, reply)
+	return nil
+}
+
+// SetPreference implements the ChainVM interface
+func (vm *VMServer) SetPreference(args *BlockArgs, _ *Empty) error {
+	blkID, err := ids.ToID(args.ID)
+	if err != nil {
+		return err
+	}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	vm.vm.SetPreference(blkID)
+	return nil
+}
+
+// LastAccepted implements the ChainVM interface
+func (vm *VMServer) LastAccepted(_ *Empty, reply *IDReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	reply.ID = vm.vm.LastAccepted().Bytes()
+	return nil
+}
+
+// BlockVerify implements the Block interface
+func (vm *VMServer) BlockVerify(args *BlockArgs, _ *Empty) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.block(args.ID)
+	if err != nil {
+		return err
+	}
+	return blk.Verify()
+}
+
+// BlockAccept implements the Block interface
+func (vm *VMServer) BlockAccept(args *BlockArgs, _ *Empty) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.block(args.ID)
+	if err != nil {
+		return err
+	}
+	blk.Accept()
+	delete(vm.blocks, blk.ID().Key())
+	return nil
+}
+
+// BlockReject implements the Block interface
+func (vm *VMServer) BlockReject(args *BlockArgs, _ *Empty) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.block(args.ID)
+	if err != nil {
+		return err
+	}
+	blk.Reject()
+	delete(vm.blocks, blk.ID().Key())
+	return nil
+}
+
+// BlockStatus implements the Block interface
+func (vm *VMServer) BlockStatus(args *BlockArgs, reply *StatusReply) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	blk, err := vm.block(args.ID)
+	if err != nil {
+		return err
+	}
+	reply.Status = uint32(blk.Status())
+	return nil
+}
+
+// block returns the block with ID [blkID]
+// Assumes the lock is held
+func (vm *VMServer) block(blkID []byte) (snowman.Block, error) {
+	id, err := ids.ToID(blkID)
+	if err != nil {
+		return nil, err
+	}
+	if blk, exists := vm.blocks[id.Key()]; exists {
+		return blk, nil
+	}
+	return vm.vm.GetBlock(id)
+}
+
+// reply describes [blk] in [reply] and remembers [blk] until it's decided
+// Assumes the lock is held
+func (vm *VMServer) reply(blk snowman.Block, reply *BlockReply) {
+	status := blk.Status()
+	if !status.Decided() {
+		vm.blocks[blk.ID().Key()] = blk
+	}
+
+	parentID := ids.Empty
+	if parent := blk.Parent(); parent != nil {
+		parentID = parent.ID()
+	}
+
+	reply.ID = blk.ID().Bytes()
+	reply.ParentID = parentID.Bytes()
+	reply.Status = uint32(status)
+	reply.Bytes = blk.Bytes()
+}
+
+// responseWriter records the response of an HTTP handler
+type responseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header { return w.header }
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
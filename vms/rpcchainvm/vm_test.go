@@ -0,0 +1,203 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcchainvm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+)
+
+// setupVM serves a timestampvm over in-memory connections, the way a plugin
+// would, and returns the node's side of it
+func setupVM(t *testing.T) (*VMClient, chan common.Message, func()) {
+	logDir, err := ioutil.TempDir("", "rpcchainvm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vmServerConn, vmClientConn := net.Pipe()
+	brokerServerConn, brokerClientConn := net.Pipe()
+	go serve(&timestampvm.VM{}, vmServerConn, brokerClientConn)
+
+	vm := &VMClient{
+		logDirectory: logDir,
+		ctx:          snow.DefaultContextTest(),
+	}
+	msgChan := make(chan common.Message, 1)
+	if err := vm.initialize(
+		vmClientConn,
+		brokerServerConn,
+		memdb.New(),
+		[]byte{0, 0, 0, 0, 0},
+		nil,
+		msgChan,
+	); err != nil {
+		t.Fatal(err)
+	}
+	return vm, msgChan, func() {
+		vm.Shutdown()
+		os.RemoveAll(logDir)
+	}
+}
+
+func TestVMClient(t *testing.T) {
+	vm, msgChan, shutdown := setupVM(t)
+	defer shutdown()
+
+	genesisID := vm.LastAccepted()
+	genesis, err := vm.GetBlock(genesisID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := genesis.Status(); status != choices.Accepted {
+		t.Fatalf("genesis block should be %s but is %s", choices.Accepted, status)
+	}
+	vm.SetPreference(genesisID)
+
+	// Propose data through the API, which is served by the plugin
+	handlers := vm.CreateHandlers()
+	handler, ok := handlers[""]
+	if !ok {
+		t.Fatal("plugin VM should have a handler at the empty extension")
+	}
+	data := [32]byte{'d', 'a', 't', 'a'}
+	body := fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"timestamp.proposeBlock","params":[{"data":"%s"}],"id":1}`,
+		formatting.CB58{Bytes: data[:]},
+	)
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("request should have succeeded but got status %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"Success":true`) {
+		t.Fatalf("proposal should have succeeded but the response was %s", w.Body.String())
+	}
+
+	if msg := <-msgChan; msg != common.PendingTxs {
+		t.Fatalf("engine should have been notified of %s but was notified of %s", common.PendingTxs, msg)
+	}
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blk.Parent().ID().Equals(genesisID) {
+		t.Fatalf("block's parent should be %s but is %s", genesisID, blk.Parent().ID())
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := vm.ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.ID().Equals(blk.ID()) {
+		t.Fatalf("parsed block should be %s but is %s", blk.ID(), parsed.ID())
+	}
+	if !bytes.Equal(parsed.Bytes(), blk.Bytes()) {
+		t.Fatal("parsed block's bytes should equal the bytes it was parsed from")
+	}
+
+	blk.Accept()
+	if status := parsed.Status(); status != choices.Accepted {
+		t.Fatalf("block should be %s but is %s", choices.Accepted, status)
+	}
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blk.ID()) {
+		t.Fatalf("last accepted block should be %s but is %s", blk.ID(), lastAccepted)
+	}
+}
+
+func TestVMClientParseBadBlock(t *testing.T) {
+	vm, _, shutdown := setupVM(t)
+	defer shutdown()
+
+	if _, err := vm.ParseBlock([]byte{1, 2, 3}); err == nil {
+		t.Fatal("should have errored because the block is malformed")
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	lines := []string(nil)
+	w := &lineWriter{log: func(line string) { lines = append(lines, line) }}
+
+	w.Write([]byte("first"))
+	w.Write([]byte(" line\r\nsecond line\nthird"))
+	if len(lines) != 2 {
+		t.Fatalf("should have logged %d lines but logged %d", 2, len(lines))
+	}
+	if lines[0] != "first line" {
+		t.Fatalf("first line should be %q but is %q", "first line", lines[0])
+	}
+	if lines[1] != "second line" {
+		t.Fatalf("second line should be %q but is %q", "second line", lines[1])
+	}
+}
+
+func TestParseHandshake(t *testing.T) {
+	hs, err := parseHandshake("GECKO_PLUGIN|1|127.0.0.1:9650|v1.2.3")
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case hs.protocolVersion != 1 || hs.addr != "127.0.0.1:9650" || hs.vmVersion != "v1.2.3":
+		t.Fatalf("parsed the wrong handshake: %+v", hs)
+	}
+
+	// Plugins built before the VM version was added leave it out
+	hs, err = parseHandshake("GECKO_PLUGIN|1|127.0.0.1:9650")
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case hs.addr != "127.0.0.1:9650" || hs.vmVersion != "":
+		t.Fatalf("parsed the wrong handshake: %+v", hs)
+	}
+
+	for _, line := range []string{"GECKO_PLUGIN|1", "GECKO_PLUGIN|one|127.0.0.1:9650", "PLUGIN|1|127.0.0.1:9650"} {
+		if _, err := parseHandshake(line); err == nil {
+			t.Fatalf("should have errored because %q is malformed", line)
+		}
+	}
+}
+
+func TestFactoryVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpcchainvm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "plugin")
+	script := fmt.Sprintf("#!/bin/sh\necho 'GECKO_PLUGIN|%d|127.0.0.1:0|v0.1.0'\nexec sleep 10\n", protocolVersion+1)
+	if err := ioutil.WriteFile(path, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := (&Factory{Path: path}).Versions()
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case versions.VM != "v0.1.0":
+		t.Fatalf("VM version should be %q but is %q", "v0.1.0", versions.VM)
+	case versions.Interface != protocolVersion+1:
+		t.Fatalf("interface version should be %d but is %d", protocolVersion+1, versions.Interface)
+	}
+}
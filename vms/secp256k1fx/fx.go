@@ -31,6 +31,14 @@ var (
 	errTooFewSigners                  = errors.New("input has less signers than expected")
 	errInputCredentialSignersMismatch = errors.New("input expected a different number of signers than provided in the credential")
 	errWrongSigner                    = errors.New("credential does not produce expected signer")
+	errSigIndexOutOfBounds            = errors.New("input signature index is out of the output's address bounds")
+)
+
+const (
+	// Number of public keys recovered from signatures that are cached. Keys are
+	// recovered in batches before a transaction's credentials are verified, so
+	// this should be more than the number of signatures in a transaction.
+	recoverCacheSize = 2048
 )
 
 // Fx ...
@@ -41,19 +49,29 @@ type Fx struct {
 
 // Initialize ...
 func (fx *Fx) Initialize(vmIntf interface{}) error {
-	vm, ok := vmIntf.(VM)
-	if !ok {
-		return errWrongVMType
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
 	}
 
-	c := vm.Codec()
+	c := fx.vm.Codec()
 	c.RegisterType(&MintOutput{})
 	c.RegisterType(&TransferOutput{})
 	c.RegisterType(&MintInput{})
 	c.RegisterType(&TransferInput{})
 	c.RegisterType(&Credential{})
+	return nil
+}
 
+// InitializeVM sets the VM this Fx is running under without registering any
+// types. This allows other feature extensions to reuse the verification logic
+// of this Fx with their own types.
+func (fx *Fx) InitializeVM(vmIntf interface{}) error {
+	vm, ok := vmIntf.(VM)
+	if !ok {
+		return errWrongVMType
+	}
 	fx.vm = vm
+	fx.secpFactory.Cache.Size = recoverCacheSize
 	return nil
 }
 
@@ -107,7 +125,7 @@ func (fx *Fx) verifyOperation(tx Tx, utxo *MintOutput, in *MintInput, cred *Cred
 		return errWrongMintCreated
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &in.Input, cred, &utxo.OutputOwners)
 }
 
 // VerifyTransfer ...
@@ -144,10 +162,12 @@ func (fx *Fx) verifyTransfer(tx Tx, utxo *TransferOutput, in *TransferInput, cre
 		return errTimelocked
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &in.Input, cred, &utxo.OutputOwners)
 }
 
-func (fx *Fx) verifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Credential) error {
+// VerifyCredentials ensures that the output can be spent by the input with the
+// credential. A nil return values means the output can be spent.
+func (fx *Fx) VerifyCredentials(tx Tx, in *Input, cred *Credential, out *OutputOwners) error {
 	numSigs := len(in.SigIndices)
 	switch {
 	case out.Threshold < uint32(numSigs):
@@ -158,22 +178,58 @@ func (fx *Fx) verifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Crede
 		return errInputCredentialSignersMismatch
 	}
 
+	// The input may reference an address that the output doesn't have
+	for _, index := range in.SigIndices {
+		if index >= uint32(len(out.Addrs)) {
+			return errSigIndexOutOfBounds
+		}
+	}
+
 	txBytes := tx.UnsignedBytes()
 	txHash := hashing.ComputeHash256(txBytes)
 
-	for i, index := range in.SigIndices {
-		sig := cred.Sigs[i]
-
-		pk, err := fx.secpFactory.RecoverHashPublicKey(txHash, sig[:])
-		if err != nil {
-			return err
-		}
+	hashes := make([][]byte, numSigs)
+	sigs := make([][]byte, numSigs)
+	for i := range cred.Sigs {
+		hashes[i] = txHash
+		sigs[i] = cred.Sigs[i][:]
+	}
+	pks, err := fx.secpFactory.RecoverHashPublicKeys(hashes, sigs)
+	if err != nil {
+		return err
+	}
 
+	for i, index := range in.SigIndices {
 		expectedAddress := out.Addrs[index]
-		if !expectedAddress.Equals(pk.Address()) {
+		if !expectedAddress.Equals(pks[i].Address()) {
 			return errWrongSigner
 		}
 	}
 
 	return nil
 }
+
+// RecoverSigners recovers the public keys that signed the credentials of [tx]
+// in one batch, so they're cached when the credentials are verified. Invalid
+// credentials are ignored here, as they're reported when they're verified.
+func (fx *Fx) RecoverSigners(txIntf interface{}, credsIntf []interface{}) {
+	tx, ok := txIntf.(Tx)
+	if !ok {
+		return
+	}
+	txHash := hashing.ComputeHash256(tx.UnsignedBytes())
+
+	hashes := [][]byte(nil)
+	sigs := [][]byte(nil)
+	for _, credIntf := range credsIntf {
+		cred, ok := credIntf.(*Credential)
+		if !ok {
+			continue
+		}
+		for i := range cred.Sigs {
+			hashes = append(hashes, txHash)
+			sigs = append(sigs, cred.Sigs[i][:])
+		}
+	}
+	_, _ = fx.secpFactory.RecoverHashPublicKeys(hashes, sigs)
+}
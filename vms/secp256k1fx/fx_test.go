@@ -548,6 +548,45 @@ func TestFxVerifyTransferWrongSigner(t *testing.T) {
 	}
 }
 
+func TestFxVerifyTransferSigIndexOutOfBounds(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	out := &TransferOutput{
+		Amt:      1,
+		Locktime: 0,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	in := &TransferInput{
+		Amt: 1,
+		Input: Input{
+			SigIndices: []uint32{1},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	err := fx.VerifyTransfer(tx, out, in, cred)
+	if err == nil {
+		t.Fatalf("Should have errored due to an out of bounds signature index")
+	}
+}
+
 func TestFxVerifyOperation(t *testing.T) {
 	vm := testVM{}
 	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
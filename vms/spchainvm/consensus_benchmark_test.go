@@ -62,12 +62,12 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 		go timeoutManager.Dispatch()
 
 		router := &router.ChainRouter{}
-		router.Initialize(logging.NoLog{}, &timeoutManager)
+		router.Initialize(logging.NoLog{}, &timeoutManager, nil, "", prometheus.NewRegistry())
 
 		// Initialize the VM
 		vm := &VM{}
 		ctx.Lock.Lock()
-		if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, nil); err != nil {
+		if err := vm.Initialize(ctx, vmDB, genesisData, nil, msgChan, nil); err != nil {
 			b.Fatal(err)
 		}
 
@@ -104,7 +104,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, msgChan, 1000, 0)
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
@@ -188,7 +188,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 		go timeoutManager.Dispatch()
 
 		router := &router.ChainRouter{}
-		router.Initialize(logging.NoLog{}, &timeoutManager)
+		router.Initialize(logging.NoLog{}, &timeoutManager, nil, "", prometheus.NewRegistry())
 
 		wg := sync.WaitGroup{}
 		wg.Add(numBlocks)
@@ -198,7 +198,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 			onAccept: func(ids.ID) { wg.Done() },
 		}
 		ctx.Lock.Lock()
-		if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, nil); err != nil {
+		if err := vm.Initialize(ctx, vmDB, genesisData, nil, msgChan, nil); err != nil {
 			b.Fatal(err)
 		}
 
@@ -235,7 +235,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, msgChan, 1000, 0)
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spchainvm
+
+import (
+	"bytes"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/platformvm"
+)
+
+// This chain is deprecated. The functions in this file let a network move the
+// funds held on it to the Platform Chain.
+
+// ExportAccounts returns the accounts in [db] that hold a non-zero balance.
+// [db] is the database of a chain running this VM. It is read directly, so it
+// may be the database of a node that isn't running.
+func ExportAccounts(db database.Database) ([]Account, error) {
+	c := Codec{}
+	accounts := []Account(nil)
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		account, err := c.UnmarshalAccount(iter.Value())
+		if err != nil || account.Balance() == 0 {
+			continue
+		}
+		// Other values may happen to parse as an account, so only the values
+		// stored under the key of the account they parse as are accounts.
+		if !bytes.Equal(iter.Key(), account.ID().LongID().Prefix(accountID).Bytes()) {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, iter.Error()
+}
+
+// PlatformAccounts returns [accounts] as accounts in the genesis of the
+// Platform Chain
+func PlatformAccounts(accounts []Account) []platformvm.APIAccount {
+	genesisAccounts := make([]platformvm.APIAccount, len(accounts))
+	for i, account := range accounts {
+		genesisAccounts[i] = platformvm.APIAccount{
+			Address: account.ID(),
+			Balance: json.Uint64(account.Balance()),
+		}
+	}
+	return genesisAccounts
+}
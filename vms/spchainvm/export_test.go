@@ -0,0 +1,48 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spchainvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestExportAccounts(t *testing.T) {
+	genesisAccounts := GenesisAccounts()
+	genesisAccounts[0].balance = 0 // Accounts without funds aren't exported
+
+	codec := Codec{}
+	genesisData, err := codec.MarshalGenesis(genesisAccounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := memdb.New()
+
+	vm := &VM{}
+	if err := vm.Initialize(ctx, db, genesisData, nil, make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := ExportAccounts(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != len(genesisAccounts)-1 {
+		t.Fatalf("expected %d accounts but got %d", len(genesisAccounts)-1, len(accounts))
+	}
+
+	balances := map[[20]byte]uint64{}
+	for _, account := range genesisAccounts[1:] {
+		balances[account.ID().Key()] = account.Balance()
+	}
+	for _, account := range PlatformAccounts(accounts) {
+		if balance, ok := balances[account.Address.Key()]; !ok {
+			t.Fatalf("unexpected account %s", account.Address)
+		} else if uint64(account.Balance) != balance {
+			t.Fatalf("expected %s to hold %d but holds %d", account.Address, balance, account.Balance)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/platformvm"
 )
 
 // Service defines the API exposed by the payments vm
@@ -65,3 +66,27 @@ func (service *Service) GetAccount(_ *http.Request, args *GetAccountArgs, reply
 	reply.Balance = json.Uint64(account.balance)
 	return nil
 }
+
+// ExportGenesisArgs are the arguments for calling ExportGenesis
+type ExportGenesisArgs struct{}
+
+// ExportGenesisReply is the reply from calling ExportGenesis
+// [Accounts] are this chain's accounts as accounts in the genesis of the
+// Platform Chain
+type ExportGenesisReply struct {
+	Accounts []platformvm.APIAccount `json:"accounts"`
+}
+
+// ExportGenesis returns the accounts of this chain that hold a balance as
+// accounts in the genesis of the Platform Chain, so that a network can move
+// them off of this deprecated chain
+func (service *Service) ExportGenesis(_ *http.Request, _ *ExportGenesisArgs, reply *ExportGenesisReply) error {
+	service.vm.ctx.Log.Verbo("ExportGenesis called")
+
+	accounts, err := ExportAccounts(service.vm.baseDB)
+	if err != nil {
+		return err
+	}
+	reply.Accounts = PlatformAccounts(accounts)
+	return nil
+}
@@ -7,8 +7,6 @@ import (
 	"errors"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
@@ -21,8 +19,7 @@ import (
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
-
-	jsoncodec "github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/core"
 )
 
 const (
@@ -76,6 +73,7 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	genesisBytes []byte,
+	configBytes []byte,
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
@@ -193,26 +191,23 @@ func (vm *VM) LastAccepted() ids.ID { return vm.lastAccepted }
 
 // CreateHandlers makes new service objects with references to the vm
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := jsoncodec.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&Service{vm: vm}, "spchain") // Name the API service "spchain"
-	return map[string]*common.HTTPHandler{
-		"": &common.HTTPHandler{LockOptions: common.WriteLock, Handler: newServer},
+	builder := core.NewHandlerBuilder()
+	builder.AddService("", "spchain", &Service{vm: vm}, common.WriteLock) // name this service "spchain"
+	handlers, err := builder.Build()
+	if err != nil {
+		vm.ctx.Log.Error("creating the VM's handlers failed with %s", err)
 	}
+	return handlers
 }
 
 // CreateStaticHandlers makes new service objects without references to the vm
 func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := jsoncodec.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&StaticService{}, "spchain") // Name the API service "spchain"
-	return map[string]*common.HTTPHandler{
-		"": &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer},
-	}
+	builder := core.NewHandlerBuilder()
+	// NoLock because the static functions probably wont be stateful (i.e. no
+	// write operations)
+	builder.AddService("", "spchain", &StaticService{}, common.NoLock) // name this service "spchain"
+	handlers, _ := builder.Build()
+	return handlers
 }
 
 // IssueTx ...
@@ -77,6 +77,7 @@ func BenchmarkParseBlock(b *testing.B) {
 		/*ctx=*/ ctx,
 		/*db=*/ memdb.New(),
 		/*genesis=*/ genesisBytes,
+		/*configBytes=*/ nil,
 		/*engineChan=*/ make(chan common.Message, 1),
 		/*fxs=*/ nil,
 	)
@@ -110,6 +111,7 @@ func BenchmarkParseAndVerify(b *testing.B) {
 			/*ctx=*/ snow.DefaultContextTest(),
 			/*db=*/ memdb.New(),
 			/*genesis=*/ genesisBytes,
+			/*configBytes=*/ nil,
 			/*engineChan=*/ make(chan common.Message, 1),
 			/*fxs=*/ nil,
 		)
@@ -145,6 +147,7 @@ func BenchmarkAccept(b *testing.B) {
 			/*ctx=*/ snow.DefaultContextTest(),
 			/*db=*/ memdb.New(),
 			/*genesis=*/ genesisBytes,
+			/*configBytes=*/ nil,
 			/*engineChan=*/ make(chan common.Message, 1),
 			/*fxs=*/ nil,
 		)
@@ -182,6 +185,7 @@ func ParseAndVerifyAndAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 			/*ctx=*/ snow.DefaultContextTest(),
 			/*db=*/ memdb.New(),
 			/*genesis=*/ genesisBytes,
+			/*configBytes=*/ nil,
 			/*engineChan=*/ make(chan common.Message, 1),
 			/*fxs=*/ nil,
 		)
@@ -236,6 +240,7 @@ func ParseThenVerifyThenAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 			/*ctx=*/ snow.DefaultContextTest(),
 			/*db=*/ memdb.New(),
 			/*genesis=*/ genesisBytes,
+			/*configBytes=*/ nil,
 			/*engineChan=*/ make(chan common.Message, 1),
 			/*fxs=*/ nil,
 		)
@@ -296,6 +301,7 @@ func IssueAndVerifyAndAccept(numBlocks, numTxsPerBlock int, b *testing.B) {
 			/*ctx=*/ snow.DefaultContextTest(),
 			/*db=*/ memdb.New(),
 			/*genesis=*/ genesisBytes,
+			/*configBytes=*/ nil,
 			/*engineChan=*/ make(chan common.Message, 1),
 			/*fxs=*/ nil,
 		)
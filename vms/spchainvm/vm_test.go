@@ -67,7 +67,7 @@ func TestPayments(t *testing.T) {
 	blocker, _ := queue.New(bootstrappingDB)
 
 	vm := &VM{}
-	vm.Initialize(ctx, db, genesisData, msgChan, nil)
+	vm.Initialize(ctx, db, genesisData, nil, msgChan, nil)
 
 	sender := &common.SenderTest{}
 	sender.T = t
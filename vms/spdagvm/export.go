@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spdagvm
+
+import (
+	"bytes"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
+)
+
+// This chain is deprecated. The functions in this file let a network move the
+// funds held on it to the AVM.
+
+// ExportUTXOs returns the UTXOs in [db].
+// [db] is the database of a chain running this VM. It is read directly, so it
+// may be the database of a node that isn't running.
+func ExportUTXOs(db database.Database) ([]*UTXO, error) {
+	c := Codec{}
+	utxos := []*UTXO(nil)
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	for iter.Next() {
+		utxo, err := c.UnmarshalUTXO(iter.Value())
+		if err != nil {
+			continue
+		}
+		// Other values may happen to parse as a UTXO, so only the values
+		// stored under the key of the UTXO they parse as are UTXOs.
+		if !bytes.Equal(iter.Key(), utxo.ID().Prefix(utxoID).Bytes()) {
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, iter.Error()
+}
+
+// AVMHolders returns the funds in [utxos] as holders of a fixed cap asset
+// in the genesis of the AVM. The funds of each address are merged into one
+// holder.
+// A UTXO can only be expressed as a holder if it is a payment to one address
+// that is unlocked at [time]. The IDs of the other UTXOs are returned in
+// [unexported].
+func AVMHolders(utxos []*UTXO, time uint64) (holders []avm.Holder, unexported []ids.ID, err error) {
+	balances := map[[20]byte]uint64{}
+	addrs := []ids.ShortID(nil)
+	for _, utxo := range utxos {
+		out, ok := utxo.Out().(*OutputPayment)
+		if !ok || out.Threshold() != 1 || len(out.Addresses()) != 1 || out.Locktime() > time {
+			unexported = append(unexported, utxo.ID())
+			continue
+		}
+
+		addr := out.Addresses()[0]
+		balance, exists := balances[addr.Key()]
+		if !exists {
+			addrs = append(addrs, addr)
+		}
+		balance, err = math.Add64(balance, out.Amount())
+		if err != nil {
+			return nil, nil, err
+		}
+		balances[addr.Key()] = balance
+	}
+
+	for _, addr := range addrs {
+		holders = append(holders, avm.Holder{
+			Amount:  json.Uint64(balances[addr.Key()]),
+			Address: addr.String(),
+		})
+	}
+	return holders, unexported, nil
+}
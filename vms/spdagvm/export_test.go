@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package spdagvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+func TestExportGenesis(t *testing.T) {
+	genesisTx := GenesisTx(defaultInitBalances)
+
+	vmDB := memdb.New()
+	vm := &VM{}
+	if err := vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	utxos, err := ExportUTXOs(vmDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != len(keys) {
+		t.Fatalf("expected %d UTXOs but got %d", len(keys), len(utxos))
+	}
+
+	holders, unexported, err := AVMHolders(utxos, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unexported) != 0 {
+		t.Fatalf("all the genesis UTXOs should have been exported")
+	}
+	if len(holders) != len(keys) {
+		t.Fatalf("expected %d holders but got %d", len(keys), len(holders))
+	}
+	for _, holder := range holders {
+		if balance, ok := defaultInitBalances[holder.Address]; !ok {
+			t.Fatalf("unexpected holder %s", holder.Address)
+		} else if uint64(holder.Amount) != balance {
+			t.Fatalf("expected %s to hold %d but holds %d", holder.Address, balance, holder.Amount)
+		}
+	}
+}
+
+func TestAVMHolders(t *testing.T) {
+	builder := Builder{
+		NetworkID: 0,
+		ChainID:   avaChainID,
+	}
+	addr0 := keys[0].PublicKey().Address()
+	addr1 := keys[1].PublicKey().Address()
+
+	tx, err := builder.NewTx(
+		/*ins=*/ nil,
+		/*outs=*/ []Output{
+			builder.NewOutputPayment(
+				/*amount=*/ 1,
+				/*locktime=*/ 0,
+				/*threshold=*/ 1,
+				/*addresses=*/ []ids.ShortID{addr0},
+			),
+			builder.NewOutputPayment(
+				/*amount=*/ 2,
+				/*locktime=*/ 0,
+				/*threshold=*/ 1,
+				/*addresses=*/ []ids.ShortID{addr0},
+			),
+			builder.NewOutputPayment(
+				/*amount=*/ 4,
+				/*locktime=*/ 10,
+				/*threshold=*/ 1,
+				/*addresses=*/ []ids.ShortID{addr0},
+			),
+			builder.NewOutputPayment(
+				/*amount=*/ 8,
+				/*locktime=*/ 0,
+				/*threshold=*/ 2,
+				/*addresses=*/ []ids.ShortID{addr0, addr1},
+			),
+		},
+		/*signers=*/ nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holders, unexported, err := AVMHolders(tx.UTXOs(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 1 {
+		t.Fatalf("expected %d holder(s) but got %d", 1, len(holders))
+	}
+	if holders[0].Address != addr0.String() {
+		t.Fatalf("expected the holder to be %s but was %s", addr0, holders[0].Address)
+	}
+	if holders[0].Amount != 3 {
+		t.Fatalf("expected the holder's amount to be %d but was %d", 3, holders[0].Amount)
+	}
+	if len(unexported) != 2 {
+		t.Fatalf("the locked and multisig UTXOs shouldn't have been exported")
+	}
+}
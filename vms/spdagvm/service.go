@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/avm"
 )
 
 var (
@@ -107,3 +108,30 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 	}
 	return nil
 }
+
+// ExportGenesisArgs are arguments for ExportGenesis
+type ExportGenesisArgs struct{}
+
+// ExportGenesisReply is the reply from ExportGenesis
+type ExportGenesisReply struct {
+	// The funds on this chain as holders of a fixed cap asset in the genesis
+	// of the AVM
+	Holders []avm.Holder `json:"holders"`
+
+	// IDs of the UTXOs that can't be expressed as holders
+	Unexported []ids.ID `json:"unexported"`
+}
+
+// ExportGenesis returns the funds on this chain as holders of a fixed cap
+// asset in the genesis of the AVM, so that a network can move them off of this
+// deprecated chain
+func (service *Service) ExportGenesis(r *http.Request, args *ExportGenesisArgs, reply *ExportGenesisReply) error {
+	service.vm.ctx.Log.Verbo("ExportGenesis called")
+
+	utxos, err := ExportUTXOs(service.vm.baseDB)
+	if err != nil {
+		return err
+	}
+	reply.Holders, reply.Unexported, err = AVMHolders(utxos, service.vm.clock.Unix())
+	return err
+}
@@ -9,8 +9,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
@@ -23,8 +21,7 @@ import (
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/timer"
-
-	jsoncodec "github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/core"
 )
 
 const (
@@ -85,6 +82,7 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	genesisBytes []byte,
+	configBytes []byte,
 	toEngine chan<- common.Message,
 	fxs []*common.Fx,
 ) error {
@@ -138,28 +136,23 @@ func (vm *VM) Shutdown() {
 
 // CreateHandlers makes new service objects with references to the vm
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := jsoncodec.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&Service{vm: vm}, "spdag") // name this service "spdag"
-	return map[string]*common.HTTPHandler{
-		"": &common.HTTPHandler{Handler: newServer},
+	builder := core.NewHandlerBuilder()
+	builder.AddService("", "spdag", &Service{vm: vm}, common.WriteLock) // name this service "spdag"
+	handlers, err := builder.Build()
+	if err != nil {
+		vm.ctx.Log.Error("creating the VM's handlers failed with %s", err)
 	}
+	return handlers
 }
 
 // CreateStaticHandlers makes new service objects without references to the vm
 func (vm *VM) CreateStaticHandlers() map[string]*common.HTTPHandler {
-	newServer := rpc.NewServer()
-	codec := jsoncodec.NewCodec()
-	newServer.RegisterCodec(codec, "application/json")
-	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	newServer.RegisterService(&StaticService{}, "spdag") // name this service "spdag"
-	return map[string]*common.HTTPHandler{
-		// NoLock because the static functions probably wont be stateful (i.e. no
-		// write operations)
-		"": &common.HTTPHandler{LockOptions: common.NoLock, Handler: newServer},
-	}
+	builder := core.NewHandlerBuilder()
+	// NoLock because the static functions probably wont be stateful (i.e. no
+	// write operations)
+	builder.AddService("", "spdag", &StaticService{}, common.NoLock) // name this service "spdag"
+	handlers, _ := builder.Build()
+	return handlers
 }
 
 // PendingTxs returns the transactions that have not yet
@@ -92,7 +92,7 @@ func TestAva(t *testing.T) {
 	msgChan := make(chan common.Message, 1)
 
 	vm := &VM{}
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	builder := Builder{
@@ -175,7 +175,7 @@ func TestInvalidSpentTx(t *testing.T) {
 	vm := &VM{}
 
 	ctx.Lock.Lock()
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	builder := Builder{
@@ -261,7 +261,7 @@ func TestInvalidTxVerification(t *testing.T) {
 	vm := &VM{}
 
 	ctx.Lock.Lock()
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	builder := Builder{
@@ -320,7 +320,7 @@ func TestRPCAPI(t *testing.T) {
 	vmDB := memdb.New()
 	msgChan := make(chan common.Message, 1)
 	vm := &VM{}
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	// Key: string repr. of an address
@@ -527,7 +527,7 @@ func TestMultipleSend(t *testing.T) {
 	vmDB := memdb.New()
 	msgChan := make(chan common.Message, 1)
 	vm := &VM{}
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 
 	// Initialize these data structures
 	addrToPK := map[string]string{}
@@ -636,7 +636,7 @@ func TestIssuePendingDependency(t *testing.T) {
 
 	ctx.Lock.Lock()
 	vm := &VM{}
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	builder := Builder{
@@ -742,7 +742,7 @@ func TestTxOutputOverflow(t *testing.T) {
 	msgChan := make(chan common.Message, 1)
 	ctx.Lock.Lock()
 	vm := &VM{}
-	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), msgChan, nil)
+	vm.Initialize(ctx, vmDB, genesisTx.Bytes(), nil, msgChan, nil)
 	vm.batchTimeout = 0
 
 	// Create a new private key
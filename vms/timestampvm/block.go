@@ -14,20 +14,26 @@ var (
 	errTimestampTooEarly = errors.New("block's timestamp is later than its parent's timestamp")
 	errDatabase          = errors.New("error while retrieving data from database")
 	errTimestampTooLate  = errors.New("block's timestamp is more than 1 hour ahead of local time")
+	errWrongHeight       = errors.New("block's height isn't one more than its parent's height")
 )
 
 // Block is a block on the chain.
 // Each block contains:
-// 1) A piece of data (a string)
-// 2) A timestamp
+// 1) Its height (the genesis block is at height 0)
+// 2) A piece of data (32 bytes)
+// 3) A timestamp
 type Block struct {
 	*core.Block `serialize:"true"`
+	Height      uint64        `serialize:"true"`
 	Data        [dataLen]byte `serialize:"true"`
 	Timestamp   int64         `serialize:"true"`
+
+	vm *VM
 }
 
 // Verify returns nil iff this block is valid.
 // To be valid, it must be that:
+// b.parent.Height + 1 == b.Height
 // b.parent.Timestamp < b.Timestamp <= [local time] + 1 hour
 func (b *Block) Verify() error {
 	if accepted, err := b.Block.Verify(); err != nil || accepted {
@@ -40,6 +46,10 @@ func (b *Block) Verify() error {
 		return errDatabase
 	}
 
+	if b.Height != parent.Height+1 {
+		return errWrongHeight
+	}
+
 	if b.Timestamp < time.Unix(parent.Timestamp, 0).Unix() {
 		return errTimestampTooEarly
 	}
@@ -52,3 +62,18 @@ func (b *Block) Verify() error {
 	b.VM.SaveBlock(b.VM.DB, b)
 	return b.VM.DB.Commit()
 }
+
+// Accept sets this block's status to Accepted, indexes it by its height and
+// tells the clients subscribed to accepted blocks about it
+func (b *Block) Accept() {
+	b.Block.Accept()
+
+	if err := b.vm.State.PutID(b.vm.DB, heightKey(b.Height), b.ID()); err != nil {
+		b.vm.Ctx.Log.Error("couldn't index block %s by its height: %s", b.ID(), err)
+	}
+	if err := b.vm.DB.Commit(); err != nil {
+		b.vm.Ctx.Log.Error("couldn't commit the acceptance of block %s: %s", b.ID(), err)
+	}
+
+	b.vm.pubsub.Publish(acceptedChannel, newAPIBlock(b))
+}
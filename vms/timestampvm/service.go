@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
 )
 
 var (
@@ -50,10 +51,23 @@ func (s *Service) ProposeBlock(_ *http.Request, args *ProposeBlockArgs, reply *P
 
 // APIBlock is the API representation of a block
 type APIBlock struct {
-	Timestamp int64  `json:"timestamp"` // Timestamp of most recent block
-	Data      string `json:"data"`      // Data in the most recent block. Base 58 repr. of 5 bytes.
-	ID        string `json:"id"`        // String repr. of ID of the most recent block
-	ParentID  string `json:"parentID"`  // String repr. of ID of the most recent block's parent
+	Timestamp int64       `json:"timestamp"` // Timestamp of the block
+	Height    json.Uint64 `json:"height"`    // Height of the block. The genesis block is at height 0.
+	Data      string      `json:"data"`      // Data in the block. Base 58 repr. of 32 bytes.
+	ID        string      `json:"id"`        // String repr. of ID of the block
+	ParentID  string      `json:"parentID"`  // String repr. of ID of the block's parent
+}
+
+// newAPIBlock returns the API representation of [block]
+func newAPIBlock(block *Block) APIBlock {
+	byteFormatter := formatting.CB58{Bytes: block.Data[:]}
+	return APIBlock{
+		Timestamp: block.Timestamp,
+		Height:    json.Uint64(block.Height),
+		Data:      byteFormatter.String(),
+		ID:        block.ID().String(),
+		ParentID:  block.ParentID().String(),
+	}
 }
 
 // GetBlockArgs are the arguments to GetBlock
@@ -92,11 +106,23 @@ func (s *Service) GetBlock(_ *http.Request, args *GetBlockArgs, reply *GetBlockR
 		return errBadData
 	}
 
-	reply.APIBlock.ID = block.ID().String()
-	reply.APIBlock.Timestamp = block.Timestamp
-	reply.APIBlock.ParentID = block.ParentID().String()
-	byteFormatter := formatting.CB58{Bytes: block.Data[:]}
-	reply.Data = byteFormatter.String()
+	reply.APIBlock = newAPIBlock(block)
+	return nil
+}
+
+// GetBlockByHeightArgs are the arguments to GetBlockByHeight
+type GetBlockByHeightArgs struct {
+	// Height of the accepted block we're getting
+	Height json.Uint64 `json:"height"`
+}
+
+// GetBlockByHeight gets the accepted block at height [args.Height]
+func (s *Service) GetBlockByHeight(_ *http.Request, args *GetBlockByHeightArgs, reply *GetBlockReply) error {
+	block, err := s.vm.getBlockByHeight(uint64(args.Height))
+	if err != nil {
+		return errNoSuchBlock
+	}
 
+	reply.APIBlock = newAPIBlock(block)
 	return nil
 }
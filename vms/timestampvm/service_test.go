@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timestampvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+// Assert that data proposed through the API ends up in an accepted block
+// that can be fetched by its height
+func TestServiceProposeAndGetBlockByHeight(t *testing.T) {
+	// Initialize the vm
+	db := memdb.New()
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = blockchainID
+	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	vm.SetPreference(vm.LastAccepted())
+	service := Service{vm}
+
+	// The genesis block is at height 0
+	genesisReply := GetBlockReply{}
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: 0}, &genesisReply); err != nil {
+		t.Fatal(err)
+	}
+	if genesisReply.ID != vm.LastAccepted().String() {
+		t.Fatalf("expected the block at height 0 to be %s but was %s", vm.LastAccepted(), genesisReply.ID)
+	}
+
+	// Propose data through the API
+	data := [dataLen]byte{'d', 'a', 't', 'a'}
+	proposeReply := ProposeBlockReply{}
+	if err := service.ProposeBlock(nil, &ProposeBlockArgs{Data: formatting.CB58{Bytes: data[:]}.String()}, &proposeReply); err != nil {
+		t.Fatal(err)
+	}
+	if !proposeReply.Success {
+		t.Fatal("proposal should have succeeded")
+	}
+
+	// Nothing is at height 1 until a block is accepted there
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: 1}, &GetBlockReply{}); err == nil {
+		t.Fatal("should have errored because no block has been accepted at height 1")
+	}
+
+	// The engine builds, verifies and accepts the block
+	block, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	block.Accept()
+
+	reply := GetBlockReply{}
+	if err := service.GetBlockByHeight(nil, &GetBlockByHeightArgs{Height: 1}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.ID != block.ID().String() {
+		t.Fatalf("expected the block at height 1 to be %s but was %s", block.ID(), reply.ID)
+	}
+	if reply.ParentID != genesisReply.ID {
+		t.Fatalf("expected the block's parent to be %s but was %s", genesisReply.ID, reply.ParentID)
+	}
+	if reply.Height != 1 {
+		t.Fatalf("expected the block's height to be %d but was %d", 1, reply.Height)
+	}
+	if expected := (formatting.CB58{Bytes: data[:]}).String(); reply.Data != expected {
+		t.Fatalf("expected the block's data to be %s but was %s", expected, reply.Data)
+	}
+}
+
+// Assert that the API only accepts data that is exactly 32 bytes
+func TestServiceProposeBadData(t *testing.T) {
+	// Initialize the vm
+	db := memdb.New()
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = blockchainID
+	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	service := Service{vm}
+
+	if err := service.ProposeBlock(nil, &ProposeBlockArgs{Data: "not base 58"}, &ProposeBlockReply{}); err != errBadData {
+		t.Fatal("should have errored because the data isn't base 58")
+	}
+	if err := service.ProposeBlock(nil, &ProposeBlockArgs{Data: formatting.CB58{Bytes: []byte{1, 2, 3}}.String()}, &ProposeBlockReply{}); err != errBadData {
+		t.Fatal("should have errored because the data isn't 32 bytes")
+	}
+	if len(vm.mempool) != 0 {
+		t.Fatal("bad data shouldn't have been added to the mempool")
+	}
+}
@@ -14,15 +14,30 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/core"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
-const dataLen = 32
+const (
+	dataLen = 32
+
+	// Name of the pubsub channel accepted blocks are published on
+	acceptedChannel = "accepted"
+)
 
 var (
 	errNoPendingBlocks = errors.New("there is no block to propose")
 	errBadGenesisBytes = errors.New("genesis data should be bytes (max length 32)")
+	errWrongBlockType  = errors.New("block has the wrong type")
 )
 
+// heightIndexID is the prefix of the keys that accepted blocks are indexed by
+var heightIndexID = ids.NewID([32]byte{'h', 'e', 'i', 'g', 'h', 't'})
+
+// heightKey returns the key the ID of the accepted block at [height] is
+// stored under
+func heightKey(height uint64) ids.ID { return heightIndexID.Prefix(height) }
+
 // VM implements the snowman.VM interface
 // Each block in this chain contains a Unix timestamp
 // and a piece of data (a string)
@@ -31,6 +46,8 @@ type VM struct {
 	codec codec.Codec
 	// Proposed pieces of data that haven't been put into a block and proposed yet
 	mempool [][dataLen]byte
+	// Clients that subscribe to this server are sent each accepted block
+	pubsub *cjson.PubSubServer
 }
 
 // Initialize this vm
@@ -43,6 +60,7 @@ func (vm *VM) Initialize(
 	ctx *snow.Context,
 	db database.Database,
 	genesisData []byte,
+	_ []byte,
 	toEngine chan<- common.Message,
 	_ []*common.Fx,
 ) error {
@@ -52,6 +70,11 @@ func (vm *VM) Initialize(
 	}
 	vm.codec = codec.NewDefault()
 
+	vm.pubsub = cjson.NewPubSubServer(ctx)
+	if err := vm.pubsub.Register(acceptedChannel); err != nil {
+		return err
+	}
+
 	// If database is empty, create it using the provided genesis data
 	if !vm.DBInitialized() {
 		if len(genesisData) > dataLen {
@@ -64,8 +87,8 @@ func (vm *VM) Initialize(
 		copy(genesisDataArr[:], genesisData)
 
 		// Create the genesis block
-		// Timestamp of genesis block is 0. It has no parent.
-		genesisBlock, err := vm.NewBlock(ids.Empty, genesisDataArr, time.Unix(0, 0))
+		// Height and timestamp of genesis block are 0. It has no parent.
+		genesisBlock, err := vm.NewBlock(ids.Empty, 0, genesisDataArr, time.Unix(0, 0))
 		if err != nil {
 			vm.Ctx.Log.Error("error while creating genesis block: %v", err)
 			return err
@@ -92,13 +115,19 @@ func (vm *VM) Initialize(
 }
 
 // CreateHandlers returns a map where:
-// Keys: The path extension for this VM's API (empty in this case)
+// Keys: The path extension for this VM's API
 // Values: The handler for the API
+// The JSON RPC API is at the empty extension, and clients can subscribe to
+// accepted blocks over a websocket at "/pubsub"
 func (vm *VM) CreateHandlers() map[string]*common.HTTPHandler {
-	handler := vm.NewHandler("timestamp", &Service{vm})
-	return map[string]*common.HTTPHandler{
-		"": handler,
+	builder := core.NewHandlerBuilder()
+	builder.AddService("", "timestamp", &Service{vm}, common.WriteLock)
+	builder.AddHandler("/pubsub", vm.pubsub, common.NoLock)
+	handlers, err := builder.Build()
+	if err != nil {
+		vm.Ctx.Log.Error("creating the VM's handlers failed with %s", err)
 	}
+	return handlers
 }
 
 // CreateStaticHandlers returns a map where:
@@ -113,6 +142,12 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		return nil, errNoPendingBlocks
 	}
 
+	// Get the block the new block is built on
+	parent, err := vm.getBlock(vm.Preferred())
+	if err != nil {
+		return nil, err
+	}
+
 	// Get the value to put in the new block
 	value := vm.mempool[0]
 	vm.mempool = vm.mempool[1:]
@@ -124,7 +159,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	}
 
 	// Build the block
-	block, err := vm.NewBlock(vm.Preferred(), value, time.Now())
+	block, err := vm.NewBlock(parent.ID(), parent.Height+1, value, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -144,19 +179,46 @@ func (vm *VM) proposeBlock(data [dataLen]byte) {
 // This function is used by the vm's state to unmarshal blocks saved in state
 func (vm *VM) ParseBlock(bytes []byte) (snowman.Block, error) {
 	block := &Block{}
-	err := vm.codec.Unmarshal(bytes, block)
+	if err := vm.codec.Unmarshal(bytes, block); err != nil {
+		return nil, err
+	}
 	block.Initialize(bytes, &vm.SnowmanVM)
-	return block, err
+	block.vm = vm
+	return block, nil
+}
+
+// getBlock returns the block with ID [blkID]
+func (vm *VM) getBlock(blkID ids.ID) (*Block, error) {
+	blockInterface, err := vm.GetBlock(blkID)
+	if err != nil {
+		return nil, err
+	}
+	block, ok := blockInterface.(*Block)
+	if !ok {
+		return nil, errWrongBlockType
+	}
+	return block, nil
+}
+
+// getBlockByHeight returns the accepted block at height [height]
+func (vm *VM) getBlockByHeight(height uint64) (*Block, error) {
+	blkID, err := vm.State.GetID(vm.DB, heightKey(height))
+	if err != nil {
+		return nil, err
+	}
+	return vm.getBlock(blkID)
 }
 
 // NewBlock returns a new Block where:
 // - the block's parent is [parentID]
+// - the block's height is [height]
 // - the block's data is [data]
 // - the block's timestamp is [timestamp]
 // The block is persisted in storage
-func (vm *VM) NewBlock(parentID ids.ID, data [dataLen]byte, timestamp time.Time) (*Block, error) {
+func (vm *VM) NewBlock(parentID ids.ID, height uint64, data [dataLen]byte, timestamp time.Time) (*Block, error) {
 	block := &Block{
 		Block:     core.NewBlock(parentID),
+		Height:    height,
 		Data:      data,
 		Timestamp: timestamp.Unix(),
 	}
@@ -167,6 +229,7 @@ func (vm *VM) NewBlock(parentID ids.ID, data [dataLen]byte, timestamp time.Time)
 	}
 
 	block.Initialize(blockBytes, &vm.SnowmanVM)
+	block.vm = vm
 
 	return block, nil
 }
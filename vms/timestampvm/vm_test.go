@@ -6,6 +6,7 @@ package timestampvm
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
@@ -44,7 +45,7 @@ func TestGenesis(t *testing.T) {
 	vm := &VM{}
 	ctx := snow.DefaultContextTest()
 	ctx.ChainID = blockchainID
-	vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, msgChan, nil)
+	vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil)
 
 	// Verify that the db is initialized
 	if !vm.DBInitialized() {
@@ -81,7 +82,7 @@ func TestHappyPath(t *testing.T) {
 	vm := &VM{}
 	ctx := snow.DefaultContextTest()
 	ctx.ChainID = blockchainID
-	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, msgChan, nil); err != nil {
+	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -198,7 +199,7 @@ func TestService(t *testing.T) {
 	vm := &VM{}
 	ctx := snow.DefaultContextTest()
 	ctx.ChainID = blockchainID
-	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, msgChan, nil); err != nil {
+	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil); err != nil {
 		t.Fatal(err)
 	}
 
@@ -207,3 +208,35 @@ func TestService(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// Assert that a block whose height doesn't follow its parent's fails
+// verification
+func TestVerifyWrongHeight(t *testing.T) {
+	// Initialize the vm
+	db := memdb.New()
+	msgChan := make(chan common.Message, 1)
+	vm := &VM{}
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = blockchainID
+	if err := vm.Initialize(ctx, db, []byte{0, 0, 0, 0, 0}, nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	genesisID := vm.LastAccepted()
+
+	// The genesis block is at height 0, so its child must be at height 1
+	block, err := vm.NewBlock(genesisID, 2, [dataLen]byte{0, 0, 0, 0, 1}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := block.Verify(); err != errWrongHeight {
+		t.Fatal("should have errored because the block's height is wrong")
+	}
+
+	block, err = vm.NewBlock(genesisID, 1, [dataLen]byte{0, 0, 0, 0, 1}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := block.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vms
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// InterfaceVersion is the version of the interface between the node and the
+// VMs it runs. It should be incremented whenever a change to the interface
+// breaks VMs that aren't built with the node, such as plugins.
+const InterfaceVersion = 1
+
+// Versions are the versions of a VM
+type Versions struct {
+	// Version of the VM. Empty if the VM doesn't report one.
+	VM string
+	// Version of the interface between the node and the VM that the VM
+	// implements
+	Interface uint32
+}
+
+// VersionedFactory is a VMFactory that reports the versions of the VMs it
+// creates. VMs built with the node always implement this node's
+// InterfaceVersion, so their factories needn't report their versions.
+type VersionedFactory interface {
+	VMFactory
+
+	Versions() (Versions, error)
+}
+
+// VMInfo describes a registered VM
+type VMInfo struct {
+	ID      ids.ID
+	Aliases []string
+	Versions
+
+	// Non-nil if this node can't run the VM. Chains of the VM fail to be
+	// created with this error.
+	Err error
+}
+
+// checkVersions returns the versions of the VMs [factory] creates, and an
+// error if this node can't run them
+func checkVersions(vmID ids.ID, factory VMFactory) (Versions, error) {
+	versioned, ok := factory.(VersionedFactory)
+	if !ok {
+		return Versions{Interface: InterfaceVersion}, nil
+	}
+	versions, err := versioned.Versions()
+	if err != nil {
+		return versions, fmt.Errorf("couldn't get the versions of VM %s: %w", vmID, err)
+	}
+	if versions.Interface != InterfaceVersion {
+		return versions, fmt.Errorf("VM %s implements interface version %d, but this node supports version %d. The VM must be rebuilt against this node",
+			vmID, versions.Interface, InterfaceVersion)
+	}
+	return versions, nil
+}
@@ -4,15 +4,12 @@
 package main
 
 import (
-	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/xputtest/loadgen"
 )
 
 // Config contains all of the configurations of an Ava client.
 type Config struct {
-	// Networking configurations
-	RemoteIP utils.IPDesc // Which Ava node to connect to
-
 	// ID of the network that this client will be issuing transactions to
 	NetworkID uint32
 
@@ -23,7 +20,13 @@ type Config struct {
 	LoggingConfig logging.Config
 
 	// Key describes which key to use to issue transactions
-	// MaxOutstandingTxs describes how many txs to pipeline
-	Key, MaxOutstandingTxs int
-	Chain                  ChainType
+	Key int
+
+	// Scenario describes the nodes txs are issued to, which chains they're
+	// issued on, and at what rate
+	Scenario *loadgen.Scenario
+
+	// ResultFile is where the result of the scenario is written, as JSON. If
+	// "-", it's written to stdout. If empty, it's only logged.
+	ResultFile string
 }
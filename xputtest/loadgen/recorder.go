@@ -0,0 +1,159 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Latency summarizes how long txs took to be decided after they were issued
+type Latency struct {
+	P50 Duration `json:"p50"`
+	P90 Duration `json:"p90"`
+	P99 Duration `json:"p99"`
+	Max Duration `json:"max"`
+}
+
+// Summary of the txs issued on a chain, to a node, or in total
+type Summary struct {
+	Issued  int `json:"issued"`
+	Decided int `json:"decided"`
+	// Txs decided per second
+	Throughput float64 `json:"throughput"`
+	Latency    Latency `json:"latency"`
+}
+
+// Result of running a scenario
+type Result struct {
+	Scenario string   `json:"scenario"`
+	Duration Duration `json:"duration"`
+	Total    Summary  `json:"total"`
+	// Summaries by the chain, and by the node, txs were issued to
+	Chains map[string]*Summary `json:"chains"`
+	Nodes  map[string]*Summary `json:"nodes"`
+}
+
+type issuedTx struct {
+	chain, node string
+	issued      time.Time
+}
+
+// group of txs that are summarized together
+type group struct {
+	issued    int
+	latencies []time.Duration
+}
+
+func (g *group) summary(duration time.Duration) *Summary {
+	sort.Slice(g.latencies, func(i, j int) bool { return g.latencies[i] < g.latencies[j] })
+	s := &Summary{
+		Issued:  g.issued,
+		Decided: len(g.latencies),
+		Latency: Latency{
+			P50: Duration(percentile(g.latencies, 50)),
+			P90: Duration(percentile(g.latencies, 90)),
+			P99: Duration(percentile(g.latencies, 99)),
+			Max: Duration(percentile(g.latencies, 100)),
+		},
+	}
+	if duration > 0 {
+		s.Throughput = float64(s.Decided) / duration.Seconds()
+	}
+	return s
+}
+
+// percentile returns the [p]th percentile of [sorted], by the nearest rank
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted)) + .5)
+	switch {
+	case rank < 1:
+		rank = 1
+	case rank > len(sorted):
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// Recorder tracks when txs are issued and decided
+type Recorder struct {
+	pending map[[32]byte]issuedTx
+
+	total  group
+	chains map[string]*group
+	nodes  map[string]*group
+}
+
+// NewRecorder returns a recorder that hasn't recorded any txs
+func NewRecorder() *Recorder {
+	return &Recorder{
+		pending: make(map[[32]byte]issuedTx),
+		chains:  make(map[string]*group),
+		nodes:   make(map[string]*group),
+	}
+}
+
+// Outstanding returns the number of issued txs that haven't been decided
+func (r *Recorder) Outstanding() int { return len(r.pending) }
+
+// Issued records that [txID] was issued on [chain] to [node] at [now]
+func (r *Recorder) Issued(chain, node string, txID ids.ID, now time.Time) {
+	r.pending[txID.Key()] = issuedTx{
+		chain:  chain,
+		node:   node,
+		issued: now,
+	}
+	r.total.issued++
+	r.group(r.chains, chain).issued++
+	r.group(r.nodes, node).issued++
+}
+
+// Decided records that [txID] was decided at [now]. Returns the chain the tx
+// was issued on, and false if the tx wasn't recorded as issued.
+func (r *Recorder) Decided(txID ids.ID, now time.Time) (string, bool) {
+	key := txID.Key()
+	tx, exists := r.pending[key]
+	if !exists {
+		return "", false
+	}
+	delete(r.pending, key)
+
+	latency := now.Sub(tx.issued)
+	r.total.latencies = append(r.total.latencies, latency)
+	r.group(r.chains, tx.chain).latencies = append(r.group(r.chains, tx.chain).latencies, latency)
+	r.group(r.nodes, tx.node).latencies = append(r.group(r.nodes, tx.node).latencies, latency)
+	return tx.chain, true
+}
+
+// Result summarizes the txs recorded while [scenario] ran for [duration]
+func (r *Recorder) Result(scenario string, duration time.Duration) *Result {
+	result := &Result{
+		Scenario: scenario,
+		Duration: Duration(duration),
+		Total:    *r.total.summary(duration),
+		Chains:   make(map[string]*Summary, len(r.chains)),
+		Nodes:    make(map[string]*Summary, len(r.nodes)),
+	}
+	for chain, g := range r.chains {
+		result.Chains[chain] = g.summary(duration)
+	}
+	for node, g := range r.nodes {
+		result.Nodes[node] = g.summary(duration)
+	}
+	return result
+}
+
+func (r *Recorder) group(groups map[string]*group, key string) *group {
+	g, exists := groups[key]
+	if !exists {
+		g = &group{}
+		groups[key] = g
+	}
+	return g
+}
@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	tests := []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{p: 0, expected: time.Millisecond},
+		{p: 50, expected: 50 * time.Millisecond},
+		{p: 99, expected: 99 * time.Millisecond},
+		{p: 100, expected: 100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if result := percentile(sorted, test.p); result != test.expected {
+			t.Fatalf("Percentile %f should be %s but is %s", test.p, test.expected, result)
+		}
+	}
+
+	if result := percentile(nil, 50); result != 0 {
+		t.Fatalf("Percentile of no latencies should be 0 but is %s", result)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	start := time.Unix(1000, 0)
+
+	txIDs := []ids.ID{
+		ids.NewID([32]byte{1}),
+		ids.NewID([32]byte{2}),
+		ids.NewID([32]byte{3}),
+	}
+	r.Issued("dag", "node1", txIDs[0], start)
+	r.Issued("dag", "node2", txIDs[1], start)
+	r.Issued("chain", "node1", txIDs[2], start)
+
+	if chain, ok := r.Decided(txIDs[0], start.Add(time.Second)); !ok || chain != "dag" {
+		t.Fatalf("Should have decided the tx issued on dag")
+	}
+	if _, ok := r.Decided(txIDs[0], start.Add(time.Second)); ok {
+		t.Fatalf("Shouldn't decide a tx twice")
+	}
+	if _, ok := r.Decided(ids.NewID([32]byte{4}), start); ok {
+		t.Fatalf("Shouldn't decide a tx that wasn't issued")
+	}
+	if _, ok := r.Decided(txIDs[2], start.Add(3*time.Second)); !ok {
+		t.Fatalf("Should have decided the tx issued on chain")
+	}
+	if outstanding := r.Outstanding(); outstanding != 1 {
+		t.Fatalf("Should have 1 outstanding tx but has %d", outstanding)
+	}
+
+	result := r.Result("test", 2*time.Second)
+	switch {
+	case result.Total.Issued != 3 || result.Total.Decided != 2:
+		t.Fatalf("Should have issued 3 and decided 2 txs in total")
+	case result.Total.Throughput != 1:
+		t.Fatalf("Throughput should be 1 tx per second but is %f", result.Total.Throughput)
+	case time.Duration(result.Total.Latency.Max) != 3*time.Second:
+		t.Fatalf("Max latency should be 3s but is %s", time.Duration(result.Total.Latency.Max))
+	case result.Chains["dag"].Issued != 2 || result.Chains["dag"].Decided != 1:
+		t.Fatalf("Should have issued 2 and decided 1 tx on dag")
+	case time.Duration(result.Chains["dag"].Latency.P50) != time.Second:
+		t.Fatalf("Median latency on dag should be 1s")
+	case result.Nodes["node1"].Issued != 2 || result.Nodes["node2"].Decided != 0:
+		t.Fatalf("Should have summarized the txs by node")
+	}
+}
@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
+)
+
+const (
+	// How often the runner issues the txs the rate allows
+	tickInterval = 10 * time.Millisecond
+
+	// How often the runner logs its progress
+	reportInterval = time.Second
+)
+
+// Issuer issues txs on a chain
+type Issuer interface {
+	// Issue sends the next tx to the node with index [node] in the scenario's
+	// nodes, and returns its ID. Returns false if no tx can be issued until a
+	// tx this issuer issued is decided.
+	Issue(node int) (ids.ID, bool)
+
+	// Decided notifies the issuer that a tx it issued was decided
+	Decided(txID ids.ID)
+}
+
+// Runner runs a scenario
+type Runner struct {
+	Scenario *Scenario
+	// Issuers of the scenario's chains, by the chains' names
+	Issuers map[string]Issuer
+	// IDs of decided txs
+	Decided <-chan ids.ID
+	Log     logging.Logger
+
+	recorder *Recorder
+	mix      random.Alias
+	nextNode int
+	issued   int
+}
+
+// Run the scenario, and return the result once its stages are over and its
+// txs are decided, or the drain timeout passed
+func (r *Runner) Run() (*Result, error) {
+	if err := r.Scenario.Verify(); err != nil {
+		return nil, err
+	}
+	weights := make([]uint64, len(r.Scenario.Mix))
+	for i, cw := range r.Scenario.Mix {
+		if _, exists := r.Issuers[cw.Chain]; !exists {
+			return nil, fmt.Errorf("no issuer for chain %s", cw.Chain)
+		}
+		weights[i] = cw.Weight
+	}
+	r.mix = random.Alias{Weights: weights}
+	r.recorder = NewRecorder()
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	reporter := time.NewTicker(reportInterval)
+	defer reporter.Stop()
+
+	duration := r.Scenario.Duration()
+	total := int(r.Scenario.TxsBy(duration))
+	start := time.Now()
+	drainEnd := start.Add(duration + time.Duration(r.Scenario.DrainTimeout))
+	for {
+		select {
+		case txID := <-r.Decided:
+			r.decided(txID)
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			if elapsed > duration {
+				// The txs the stages didn't get to are issued while draining
+				elapsed = duration
+			}
+			r.issue(elapsed)
+
+			done := r.issued >= total && r.recorder.Outstanding() == 0
+			if now.Sub(start) >= duration && (done || !now.Before(drainEnd)) {
+				ranFor := now.Sub(start)
+				r.Log.Info("Scenario %s finished after %s with %d txs undecided", r.Scenario.Name, ranFor, r.recorder.Outstanding())
+				return r.recorder.Result(r.Scenario.Name, ranFor), nil
+			}
+		case now := <-reporter.C:
+			r.Log.Info("%s into scenario %s: issued %d txs, %d outstanding", now.Sub(start), r.Scenario.Name, r.issued, r.recorder.Outstanding())
+		}
+	}
+}
+
+// issue the txs the scenario issues in its first [elapsed], that haven't been
+// issued yet
+func (r *Runner) issue(elapsed time.Duration) {
+	target := int(r.Scenario.TxsBy(elapsed))
+	for r.issued < target && r.recorder.Outstanding() < r.Scenario.MaxOutstanding {
+		chain := r.Scenario.Mix[r.mix.SampleReplace()].Chain
+		node := r.nextNode
+		txID, ok := r.Issuers[chain].Issue(node)
+		if !ok {
+			// The issuer is waiting on its txs to be decided, so the rate is
+			// caught up with once they are
+			r.Log.Verbo("Issuer of chain %s has no tx to issue", chain)
+			return
+		}
+		r.nextNode = (r.nextNode + 1) % len(r.Scenario.Nodes)
+		r.recorder.Issued(chain, r.Scenario.Nodes[node], txID, time.Now())
+		r.issued++
+	}
+}
+
+func (r *Runner) decided(txID ids.ID) {
+	chain, ok := r.recorder.Decided(txID, time.Now())
+	if !ok {
+		r.Log.Verbo("Ignoring decided tx %s that wasn't issued by this runner", txID)
+		return
+	}
+	r.Issuers[chain].Decided(txID)
+}
@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// testIssuer issues txs that are decided right away, until it issued [limit]
+type testIssuer struct {
+	decided chan<- ids.ID
+	limit   int
+
+	next    byte
+	nodes   map[int]int
+	decides int
+}
+
+func (i *testIssuer) Issue(node int) (ids.ID, bool) {
+	if int(i.next) >= i.limit {
+		return ids.ID{}, false
+	}
+	i.next++
+	i.nodes[node]++
+	txID := ids.NewID([32]byte{i.next})
+	i.decided <- txID
+	return txID, true
+}
+
+func (i *testIssuer) Decided(ids.ID) { i.decides++ }
+
+func TestRunner(t *testing.T) {
+	decided := make(chan ids.ID, 100)
+	issuer := &testIssuer{
+		decided: decided,
+		limit:   100,
+		nodes:   make(map[int]int),
+	}
+	r := Runner{
+		Scenario: &Scenario{
+			Name:           "test",
+			Nodes:          []string{"node1", "node2"},
+			Mix:            []ChainWeight{{Chain: "dag", Weight: 1}},
+			Stages:         []Stage{{Duration: Duration(200 * time.Millisecond), StartRate: 100, EndRate: 100}},
+			MaxOutstanding: 10,
+			DrainTimeout:   Duration(time.Second),
+		},
+		Issuers: map[string]Issuer{"dag": issuer},
+		Decided: decided,
+		Log:     logging.NoLog{},
+	}
+
+	result, err := r.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 20 txs are issued over 200ms at 100 txs per second
+	if result.Total.Issued != 20 {
+		t.Fatalf("Should have issued 20 txs but issued %d", result.Total.Issued)
+	}
+	if result.Total.Decided != result.Total.Issued || issuer.decides != result.Total.Issued {
+		t.Fatalf("Should have decided every issued tx")
+	}
+	if diff := issuer.nodes[0] - issuer.nodes[1]; diff < 0 || diff > 1 {
+		t.Fatalf("Should have spread the txs over the nodes but issued %d and %d", issuer.nodes[0], issuer.nodes[1])
+	}
+	if result.Nodes["node1"].Issued != issuer.nodes[0] {
+		t.Fatalf("Should have summarized the txs issued to each node")
+	}
+}
+
+func TestRunnerMissingIssuer(t *testing.T) {
+	r := Runner{
+		Scenario: &Scenario{
+			Nodes:          []string{"node1"},
+			Mix:            []ChainWeight{{Chain: "dag", Weight: 1}},
+			Stages:         []Stage{{Duration: Duration(time.Second), StartRate: 1, EndRate: 1}},
+			MaxOutstanding: 1,
+		},
+		Issuers: map[string]Issuer{},
+		Log:     logging.NoLog{},
+	}
+	if _, err := r.Run(); err == nil {
+		t.Fatalf("Should have errored because the dag chain has no issuer")
+	}
+}
@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+var (
+	errNoNodes          = errors.New("scenario must target at least one node")
+	errNoMix            = errors.New("scenario must issue txs on at least one chain")
+	errZeroWeight       = errors.New("chain weights must be positive")
+	errNoStages         = errors.New("scenario must have at least one stage")
+	errNonPositiveStage = errors.New("stage durations must be positive")
+	errNegativeRate     = errors.New("stage rates must not be negative")
+	errNoOutstanding    = errors.New("maxOutstanding must be positive")
+)
+
+// Duration is a time.Duration that's written in JSON as a string, such as
+// "1m30s"
+type Duration time.Duration
+
+// MarshalJSON implements the json.Marshaler interface
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	str := ""
+	if err := json.Unmarshal(b, &str); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(str)
+	*d = Duration(duration)
+	return err
+}
+
+// ChainWeight is the share of a scenario's txs issued on a chain
+type ChainWeight struct {
+	Chain  string `json:"chain"`
+	Weight uint64 `json:"weight"`
+}
+
+// Stage is a period of a scenario over which the rate txs are issued at
+// changes linearly from StartRate to EndRate, in txs per second
+type Stage struct {
+	Duration  Duration `json:"duration"`
+	StartRate float64  `json:"startRate"`
+	EndRate   float64  `json:"endRate"`
+}
+
+// txsBy returns the number of txs issued [elapsed] into the stage
+func (s Stage) txsBy(elapsed time.Duration) float64 {
+	t := elapsed.Seconds()
+	slope := (s.EndRate - s.StartRate) / time.Duration(s.Duration).Seconds()
+	return s.StartRate*t + slope*t*t/2
+}
+
+// Scenario describes a load test
+type Scenario struct {
+	Name string `json:"name"`
+
+	// Addresses of the throughput servers of the nodes txs are issued to.
+	// Txs are spread over the nodes in turn.
+	Nodes []string `json:"nodes"`
+
+	// Chains txs are issued on, by their share of the txs
+	Mix []ChainWeight `json:"mix"`
+
+	// Stages the scenario runs through, in order
+	Stages []Stage `json:"stages"`
+
+	// Maximum number of issued txs that haven't been decided. Txs aren't
+	// issued while there are this many, even if the rate allows.
+	MaxOutstanding int `json:"maxOutstanding"`
+
+	// How long to wait for the outstanding txs to be decided once the stages
+	// are over
+	DrainTimeout Duration `json:"drainTimeout"`
+}
+
+// LoadScenario reads the JSON encoded scenario in the file at [path]
+func LoadScenario(path string) (*Scenario, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scenario{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("couldn't parse scenario %s: %w", path, err)
+	}
+	return s, s.Verify()
+}
+
+// Verify returns nil iff the scenario can be run
+func (s *Scenario) Verify() error {
+	switch {
+	case len(s.Nodes) == 0:
+		return errNoNodes
+	case len(s.Mix) == 0:
+		return errNoMix
+	case len(s.Stages) == 0:
+		return errNoStages
+	case s.MaxOutstanding <= 0:
+		return errNoOutstanding
+	}
+	for _, cw := range s.Mix {
+		if cw.Weight == 0 {
+			return errZeroWeight
+		}
+	}
+	for _, stage := range s.Stages {
+		switch {
+		case stage.Duration <= 0:
+			return errNonPositiveStage
+		case stage.StartRate < 0 || stage.EndRate < 0:
+			return errNegativeRate
+		}
+	}
+	return nil
+}
+
+// Duration returns how long the scenario's stages last
+func (s *Scenario) Duration() time.Duration {
+	total := time.Duration(0)
+	for _, stage := range s.Stages {
+		total += time.Duration(stage.Duration)
+	}
+	return total
+}
+
+// TxsBy returns the number of txs the scenario issues in its first [elapsed]
+func (s *Scenario) TxsBy(elapsed time.Duration) float64 {
+	txs := float64(0)
+	for _, stage := range s.Stages {
+		duration := time.Duration(stage.Duration)
+		if elapsed < duration {
+			return txs + stage.txsBy(elapsed)
+		}
+		txs += stage.txsBy(duration)
+		elapsed -= duration
+	}
+	return txs
+}
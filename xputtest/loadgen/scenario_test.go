@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package loadgen
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScenarioTxsBy(t *testing.T) {
+	s := &Scenario{Stages: []Stage{
+		// Ramp from 0 to 100 txs per second over 10 seconds
+		{Duration: Duration(10 * time.Second), StartRate: 0, EndRate: 100},
+		// Hold at 100 txs per second
+		{Duration: Duration(5 * time.Second), StartRate: 100, EndRate: 100},
+	}}
+
+	if duration := s.Duration(); duration != 15*time.Second {
+		t.Fatalf("Scenario should last 15s but lasts %s", duration)
+	}
+
+	tests := []struct {
+		elapsed time.Duration
+		txs     float64
+	}{
+		{elapsed: 0, txs: 0},
+		{elapsed: 5 * time.Second, txs: 125},
+		{elapsed: 10 * time.Second, txs: 500},
+		{elapsed: 12 * time.Second, txs: 700},
+		{elapsed: 15 * time.Second, txs: 1000},
+		// No more txs are issued after the last stage
+		{elapsed: time.Minute, txs: 1000},
+	}
+	for _, test := range tests {
+		if txs := s.TxsBy(test.elapsed); math.Abs(txs-test.txs) > 1e-6 {
+			t.Fatalf("After %s, %f txs should have been issued but %f were", test.elapsed, test.txs, txs)
+		}
+	}
+}
+
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loadgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "scenario.json")
+	scenario := `{
+		"name": "ramp",
+		"nodes": ["127.0.0.1:9652", "127.0.0.1:9654"],
+		"mix": [{"chain": "dag", "weight": 3}, {"chain": "chain", "weight": 1}],
+		"stages": [{"duration": "1m", "startRate": 10, "endRate": 100}],
+		"maxOutstanding": 1000,
+		"drainTimeout": "30s"
+	}`
+	if err := ioutil.WriteFile(path, []byte(scenario), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadScenario(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case len(s.Nodes) != 2:
+		t.Fatalf("Should have parsed 2 nodes but parsed %d", len(s.Nodes))
+	case len(s.Mix) != 2 || s.Mix[0].Weight != 3:
+		t.Fatalf("Should have parsed the chain mix")
+	case time.Duration(s.Stages[0].Duration) != time.Minute:
+		t.Fatalf("Should have parsed the stage duration")
+	case time.Duration(s.DrainTimeout) != 30*time.Second:
+		t.Fatalf("Should have parsed the drain timeout")
+	}
+
+	b, err := json.Marshal(s.DrainTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"30s"` {
+		t.Fatalf("Duration should be written as a string but was written as %s", b)
+	}
+}
+
+func TestScenarioVerify(t *testing.T) {
+	valid := func() *Scenario {
+		return &Scenario{
+			Nodes:          []string{"127.0.0.1:9652"},
+			Mix:            []ChainWeight{{Chain: "dag", Weight: 1}},
+			Stages:         []Stage{{Duration: Duration(time.Second), StartRate: 1, EndRate: 1}},
+			MaxOutstanding: 1,
+		}
+	}
+	if err := valid().Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Scenario)
+		err    error
+	}{
+		{name: "no nodes", modify: func(s *Scenario) { s.Nodes = nil }, err: errNoNodes},
+		{name: "no mix", modify: func(s *Scenario) { s.Mix = nil }, err: errNoMix},
+		{name: "zero weight", modify: func(s *Scenario) { s.Mix[0].Weight = 0 }, err: errZeroWeight},
+		{name: "no stages", modify: func(s *Scenario) { s.Stages = nil }, err: errNoStages},
+		{name: "empty stage", modify: func(s *Scenario) { s.Stages[0].Duration = 0 }, err: errNonPositiveStage},
+		{name: "negative rate", modify: func(s *Scenario) { s.Stages[0].EndRate = -1 }, err: errNegativeRate},
+		{name: "no outstanding", modify: func(s *Scenario) { s.MaxOutstanding = 0 }, err: errNoOutstanding},
+	}
+	for _, test := range tests {
+		s := valid()
+		test.modify(s)
+		if err := s.Verify(); err != test.err {
+			t.Fatalf("%s: should have errored with %v but got %v", test.name, test.err, err)
+		}
+	}
+}
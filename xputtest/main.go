@@ -9,11 +9,14 @@ package main
 import "C"
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"runtime"
 	"runtime/pprof"
+	"syscall"
 	"time"
 	"unsafe"
 
@@ -25,12 +28,12 @@ import (
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
-	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/vms/platformvm"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 	"github.com/ava-labs/gecko/xputtest/chainwallet"
 	"github.com/ava-labs/gecko/xputtest/dagwallet"
+	"github.com/ava-labs/gecko/xputtest/loadgen"
 )
 
 // tp stores the persistent data needed when running the test.
@@ -38,7 +41,8 @@ type tp struct {
 	ec    salticidae.EventContext
 	build networking.Builder
 
-	conn salticidae.MsgNetworkConn
+	// Connections to the scenario's nodes, in order
+	conns []salticidae.MsgNetworkConn
 
 	log     logging.Logger
 	decided chan ids.ID
@@ -75,6 +79,7 @@ func decidedTx(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe
 func main() {
 	if err != nil {
 		fmt.Printf("Failed to parse arguments: %s\n", err)
+		return
 	}
 
 	config.LoggingConfig.Directory = path.Join(config.LoggingConfig.Directory, "client")
@@ -88,7 +93,7 @@ func main() {
 
 	t.log = log
 	crypto.EnableCrypto = config.EnableCrypto
-	t.decided = make(chan ids.ID, config.MaxOutstandingTxs)
+	t.decided = make(chan ids.ID, config.Scenario.MaxOutstanding)
 
 	if config.Key >= len(genesis.Keys) || config.Key < 0 {
 		log.Fatal("Unknown key specified")
@@ -114,16 +119,19 @@ func main() {
 	net.Start()
 	defer net.Stop()
 
-	remoteIP := salticidae.NewNetAddrFromIPPortString(config.RemoteIP.String(), true, &serr)
-	if code := serr.GetCode(); code != 0 {
-		log.Fatal("Sync error %s", salticidae.StrError(serr.GetCode()))
-		return
-	}
+	for _, node := range config.Scenario.Nodes {
+		remoteIP := salticidae.NewNetAddrFromIPPortString(node, true, &serr)
+		if code := serr.GetCode(); code != 0 {
+			log.Fatal("Sync error %s", salticidae.StrError(serr.GetCode()))
+			return
+		}
 
-	t.conn = net.ConnectSync(remoteIP, true, &serr)
-	if serr.GetCode() != 0 {
-		log.Fatal("Sync error %s", salticidae.StrError(serr.GetCode()))
-		return
+		conn := net.ConnectSync(remoteIP, true, &serr)
+		if serr.GetCode() != 0 {
+			log.Fatal("Sync error %s", salticidae.StrError(serr.GetCode()))
+			return
+		}
+		t.conns = append(t.conns, conn)
 	}
 
 	file, gErr := os.Create("cpu_client.profile")
@@ -137,20 +145,92 @@ func main() {
 
 	t.networkID = config.NetworkID
 
-	switch config.Chain {
-	case ChainChain:
-		t.benchmarkSnowman()
-	case DagChain:
-		t.benchmarkAvalanche()
-	default:
-		t.log.Fatal("did not specify whether to test dag or chain. Exiting")
-		return
+	issuers := map[string]loadgen.Issuer{}
+	for _, cw := range config.Scenario.Mix {
+		switch cw.Chain {
+		case ChainChain:
+			issuers[cw.Chain] = t.chainIssuer()
+		case DagChain:
+			issuers[cw.Chain] = t.dagIssuer()
+		default:
+			t.log.Fatal("unknown chain %s. Exiting", cw.Chain)
+			return
+		}
+	}
+
+	runner := loadgen.Runner{
+		Scenario: config.Scenario,
+		Issuers:  issuers,
+		Decided:  t.decided,
+		Log:      t.log,
 	}
+	go t.log.RecoverAndPanic(func() {
+		t.report(runner.Run())
+		// The event loop is stopped on its own thread by the signal handler
+		t.log.AssertNoError(syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	})
 
 	t.ec.Dispatch()
 }
 
-func (t *tp) benchmarkAvalanche() {
+// report logs the result of the scenario, and writes it to the result file
+func (t *tp) report(result *loadgen.Result, err error) {
+	if err != nil {
+		t.log.Error("Scenario failed: %s", err)
+		return
+	}
+	t.log.Info("Issued %d txs and decided %d. TPS: %.2f. Latency p50: %s, p99: %s",
+		result.Total.Issued,
+		result.Total.Decided,
+		result.Total.Throughput,
+		time.Duration(result.Total.Latency.P50),
+		time.Duration(result.Total.Latency.P99),
+	)
+
+	if config.ResultFile == "" {
+		return
+	}
+	b, err := json.MarshalIndent(result, "", "\t")
+	if err != nil {
+		t.log.Error("Couldn't marshal the result: %s", err)
+		return
+	}
+	if config.ResultFile == "-" {
+		fmt.Println(string(b))
+		return
+	}
+	if err := ioutil.WriteFile(config.ResultFile, b, 0644); err != nil {
+		t.log.Error("Couldn't write the result to %s: %s", config.ResultFile, err)
+	}
+}
+
+// issueTx sends [txBytes] to the node with index [node] in the scenario
+func (t *tp) issueTx(node int, chainID ids.ID, txBytes []byte) {
+	it, err := t.build.IssueTx(chainID, txBytes)
+	t.log.AssertNoError(err)
+	ds := it.DataStream()
+	ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
+	newMsg := salticidae.NewMsgMovedFromByteArray(networking.IssueTx, ba, false)
+
+	conn := t.conns[node]
+	conn.GetNet().SendMsg(newMsg, conn)
+
+	ds.Free()
+	ba.Free()
+	newMsg.Free()
+}
+
+// dagIssuer issues txs on the Simple DAG Payments chain. The UTXOs of its txs
+// are spent once the txs are decided.
+type dagIssuer struct {
+	t       *tp
+	chainID ids.ID
+	wallet  dagwallet.Wallet
+	pending map[[32]byte]*spdagvm.Tx
+	canAdd  []*spdagvm.Tx
+}
+
+func (t *tp) dagIssuer() *dagIssuer {
 	platformGenesisBytes := genesis.Genesis(t.networkID)
 	genesisState := &platformvm.Genesis{}
 	err := platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
@@ -182,65 +262,55 @@ func (t *tp) benchmarkAvalanche() {
 		wallet.AddUtxo(utxo)
 	}
 
-	go t.log.RecoverAndPanic(func() { t.IssueAvalanche(spDAGChain.ID(), wallet) })
+	return &dagIssuer{
+		t:       t,
+		chainID: spDAGChain.ID(),
+		wallet:  wallet,
+		pending: make(map[[32]byte]*spdagvm.Tx),
+	}
 }
 
-func (t *tp) IssueAvalanche(chainID ids.ID, wallet dagwallet.Wallet) {
-	t.log.Info("starting avalanche benchmark")
-	pending := make(map[[32]byte]*spdagvm.Tx)
-	canAdd := []*spdagvm.Tx{}
-	numAccepted := 0
-
-	t.decided <- ids.ID{}
-	meter := timer.TimedMeter{Duration: time.Second}
-	for d := range t.decided {
-		if numAccepted%1000 == 0 {
-			t.log.Info("TPS: %d", meter.Ticks())
-		}
-		if !d.IsZero() {
-			meter.Tick()
-			key := d.Key()
-			if tx := pending[key]; tx != nil {
-				canAdd = append(canAdd, tx)
-
-				t.log.Debug("Finalized %s", d)
-				delete(pending, key)
-				numAccepted++
-			}
+// Issue implements the loadgen.Issuer interface
+func (i *dagIssuer) Issue(node int) (ids.ID, bool) {
+	if i.wallet.Balance() == 0 {
+		if len(i.canAdd) == 0 {
+			return ids.ID{}, false
 		}
+		tx := i.canAdd[0]
+		i.canAdd = i.canAdd[1:]
 
-		for len(pending) < config.MaxOutstandingTxs && (wallet.Balance() > 0 || len(canAdd) > 0) {
-			if wallet.Balance() == 0 {
-				tx := canAdd[0]
-				canAdd = canAdd[1:]
-
-				for _, utxo := range tx.UTXOs() {
-					wallet.AddUtxo(utxo)
-				}
-			}
-
-			tx := wallet.Send(1, 0, wallet.GetAddress())
-			t.log.AssertTrue(tx != nil, "Tx creation failed")
-
-			it, err := t.build.IssueTx(chainID, tx.Bytes())
-			t.log.AssertNoError(err)
-			ds := it.DataStream()
-			ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
-			newMsg := salticidae.NewMsgMovedFromByteArray(networking.IssueTx, ba, false)
+		for _, utxo := range tx.UTXOs() {
+			i.wallet.AddUtxo(utxo)
+		}
+	}
 
-			t.conn.GetNet().SendMsg(newMsg, t.conn)
+	tx := i.wallet.Send(1, 0, i.wallet.GetAddress())
+	i.t.log.AssertTrue(tx != nil, "Tx creation failed")
+	i.t.issueTx(node, i.chainID, tx.Bytes())
 
-			ds.Free()
-			ba.Free()
-			newMsg.Free()
+	i.pending[tx.ID().Key()] = tx
+	i.t.log.Debug("Sent tx, pending = %d", len(i.pending))
+	return tx.ID(), true
+}
 
-			pending[tx.ID().Key()] = tx
-			t.log.Debug("Sent tx, pending = %d, accepted = %d", len(pending), numAccepted)
-		}
+// Decided implements the loadgen.Issuer interface
+func (i *dagIssuer) Decided(txID ids.ID) {
+	key := txID.Key()
+	if tx := i.pending[key]; tx != nil {
+		i.canAdd = append(i.canAdd, tx)
+		delete(i.pending, key)
 	}
 }
 
-func (t *tp) benchmarkSnowman() {
+// chainIssuer issues the txs generated when the test starts on the Simple
+// Chain Payments chain
+type chainIssuer struct {
+	t       *tp
+	chainID ids.ID
+	wallet  chainwallet.Wallet
+}
+
+func (t *tp) chainIssuer() *chainIssuer {
 	platformGenesisBytes := genesis.Genesis(t.networkID)
 	genesisState := &platformvm.Genesis{}
 	err := platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
@@ -276,51 +346,25 @@ func (t *tp) benchmarkSnowman() {
 
 	wallet.GenerateTxs()
 
-	go t.log.RecoverAndPanic(func() { t.IssueSnowman(spchainChain.ID(), wallet) })
+	return &chainIssuer{
+		t:       t,
+		chainID: spchainChain.ID(),
+		wallet:  wallet,
+	}
 }
 
-func (t *tp) IssueSnowman(chainID ids.ID, wallet chainwallet.Wallet) {
-	t.log.Debug("Issuing with %d", wallet.Balance())
-	numAccepted := 0
-	numPending := 0
-
-	t.decided <- ids.ID{}
-
-	meter := timer.TimedMeter{Duration: time.Second}
-	for d := range t.decided {
-		if numAccepted%1000 == 0 {
-			t.log.Info("TPS: %d", meter.Ticks())
-		}
-		if !d.IsZero() {
-			meter.Tick()
-			t.log.Debug("Finalized %s", d)
-			numAccepted++
-			numPending--
-		}
-
-		for numPending < config.MaxOutstandingTxs && wallet.Balance() > 0 && wallet.TxsSent < chainwallet.MaxNumTxs {
-			tx := wallet.NextTx()
-			t.log.AssertTrue(tx != nil, "Tx creation failed")
-
-			it, err := t.build.IssueTx(chainID, tx.Bytes())
-			t.log.AssertNoError(err)
-			ds := it.DataStream()
-			ba := salticidae.NewByteArrayMovedFromDataStream(ds, false)
-			newMsg := salticidae.NewMsgMovedFromByteArray(networking.IssueTx, ba, false)
-
-			t.conn.GetNet().SendMsg(newMsg, t.conn)
-
-			ds.Free()
-			ba.Free()
-			newMsg.Free()
-
-			numPending++
-			t.log.Debug("Sent tx, pending = %d, accepted = %d", numPending, numAccepted)
-		}
-		if wallet.TxsSent >= chainwallet.MaxNumTxs {
-			fmt.Println("done with test")
-			return
-		}
+// Issue implements the loadgen.Issuer interface
+func (i *chainIssuer) Issue(node int) (ids.ID, bool) {
+	if i.wallet.Balance() == 0 {
+		return ids.ID{}, false
 	}
-
+	tx := i.wallet.NextTx()
+	if tx == nil {
+		return ids.ID{}, false
+	}
+	i.t.issueTx(node, i.chainID, tx.Bytes())
+	return tx.ID(), true
 }
+
+// Decided implements the loadgen.Issuer interface
+func (i *chainIssuer) Decided(ids.ID) {}
@@ -4,19 +4,24 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/xputtest/loadgen"
 )
 
 var (
 	config Config
 	err    error
+
+	errNoChain = errors.New("did not specify whether to test dag or chain")
 )
 
 // Parse the CLI arguments
@@ -51,7 +56,13 @@ func init() {
 	chain := flag.Bool("chain", false, "Execute chain transactions")
 	dag := flag.Bool("dag", false, "Execute dag transactions")
 	flag.IntVar(&config.Key, "key", 0, "Index of the genesis key list to use")
-	flag.IntVar(&config.MaxOutstandingTxs, "max_outstanding", 1000, "Maximum number of transactions to leave outstanding")
+	maxOutstanding := flag.Int("max_outstanding", 1000, "Maximum number of transactions to leave outstanding")
+	rate := flag.Float64("rate", 1000, "Number of transactions issued per second")
+	duration := flag.Duration("duration", time.Minute, "How long transactions are issued for")
+
+	// Scenario:
+	scenarioFile := flag.String("scenario", "", "JSON file of the scenario to run. If set, the ip, port, chain, dag, max_outstanding, rate and duration flags are ignored")
+	flag.StringVar(&config.ResultFile, "result-file", "", "If set, the result of the test is written to this file as JSON. If -, it's written to stdout")
 
 	flag.Parse()
 
@@ -64,16 +75,6 @@ func init() {
 
 	config.NetworkID = networkID
 
-	// Remote:
-	parsedIP := net.ParseIP(*ip)
-	if parsedIP == nil {
-		errs.Add(fmt.Errorf("invalid IP Address %s", *ip))
-	}
-	config.RemoteIP = utils.IPDesc{
-		IP:   parsedIP,
-		Port: uint16(*port),
-	}
-
 	// Logging:
 	if *logsDir != "" {
 		loggingConfig.Directory = *logsDir
@@ -84,13 +85,42 @@ func init() {
 	loggingConfig.DisplayLevel = level
 	config.LoggingConfig = loggingConfig
 
-	// Test Variables:
-	switch {
-	case *chain:
-		config.Chain = ChainChain
-	case *dag:
-		config.Chain = DagChain
-	default:
-		config.Chain = UnknownChain
+	// Scenario:
+	if *scenarioFile != "" {
+		config.Scenario, err = loadgen.LoadScenario(*scenarioFile)
+		errs.Add(err)
+		return
+	}
+
+	// Without a scenario, the flags describe a test of one node at a steady
+	// rate
+	parsedIP := net.ParseIP(*ip)
+	if parsedIP == nil {
+		errs.Add(fmt.Errorf("invalid IP Address %s", *ip))
+	}
+	remoteIP := utils.IPDesc{
+		IP:   parsedIP,
+		Port: uint16(*port),
+	}
+	config.Scenario = &loadgen.Scenario{
+		Name:  "steady",
+		Nodes: []string{remoteIP.String()},
+		Stages: []loadgen.Stage{{
+			Duration:  loadgen.Duration(*duration),
+			StartRate: *rate,
+			EndRate:   *rate,
+		}},
+		MaxOutstanding: *maxOutstanding,
+		DrainTimeout:   loadgen.Duration(30 * time.Second),
+	}
+	if *chain {
+		config.Scenario.Mix = append(config.Scenario.Mix, loadgen.ChainWeight{Chain: ChainChain, Weight: 1})
+	}
+	if *dag {
+		config.Scenario.Mix = append(config.Scenario.Mix, loadgen.ChainWeight{Chain: DagChain, Weight: 1})
+	}
+	if len(config.Scenario.Mix) == 0 {
+		errs.Add(errNoChain)
 	}
+	errs.Add(config.Scenario.Verify())
 }
@@ -3,12 +3,10 @@
 
 package main
 
-// ChainType ...
-type ChainType int
-
-// Chain types
+// Names of the chains txs can be issued on, as given in scenarios
 const (
-	UnknownChain ChainType = iota
-	ChainChain
-	DagChain
+	// DagChain is the Simple DAG Payments chain
+	DagChain = "dag"
+	// ChainChain is the Simple Chain Payments chain
+	ChainChain = "chain"
 )